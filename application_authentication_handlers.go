@@ -22,7 +22,9 @@ func getApplicationAuthenticationDaoWithTenant(c echo.Context) (dao.ApplicationA
 		return nil, err
 	}
 
-	return dao.GetApplicationAuthenticationDao(&tenantId), nil
+	appAuthDao := dao.GetApplicationAuthenticationDao(c.Request().Context(), &tenantId)
+
+	return appAuthDao, nil
 }
 
 func ApplicationAuthenticationList(c echo.Context) error {
@@ -23,7 +23,9 @@ func getApplicationDaoWithTenant(c echo.Context) (dao.ApplicationDao, error) {
 		return nil, err
 	}
 
-	return dao.GetApplicationDao(&tenantId), nil
+	applicationDao := dao.GetApplicationDao(c.Request().Context(), &tenantId)
+
+	return applicationDao, nil
 }
 
 func ApplicationList(c echo.Context) error {
@@ -47,7 +49,21 @@ func ApplicationList(c echo.Context) error {
 		count        int64
 	)
 
-	applications, count, err = applicationDB.List(limit, offset, filters)
+	// The applications can be listed along with their sources' type name, to avoid having to look the sources up.
+	if c.QueryParam("include_source_type") == "true" {
+		return applicationListWithSourceType(c, applicationDB, limit, offset, filters)
+	}
+
+	authType, filters, err := extractAuthTypeFilter(filters)
+	if err != nil {
+		return err
+	}
+
+	if authType != "" {
+		applications, count, err = applicationDB.ListByAuthType(c.Request().Context(), authType, *applicationDB.Tenant(), limit, offset)
+	} else {
+		applications, count, err = applicationDB.List(limit, offset, filters)
+	}
 	if err != nil {
 		return err
 	}
@@ -62,6 +78,52 @@ func ApplicationList(c echo.Context) error {
 	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
 }
 
+// applicationListWithSourceType lists the applications along with their sources' type name.
+func applicationListWithSourceType(c echo.Context, applicationDB dao.ApplicationDao, limit, offset int, filters []util.Filter) error {
+	applications, count, err := applicationDB.ListWithSourceType(c.Request().Context(), *applicationDB.Tenant(), limit, offset, filters)
+	if err != nil {
+		return err
+	}
+
+	out := make([]interface{}, len(applications))
+	for i := 0; i < len(applications); i++ {
+		out[i] = applications[i].ToResponse()
+	}
+
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+}
+
+// extractAuthTypeFilter removes the "authtype" filter from the given filters and returns its value. Since the
+// applications get filtered by their authentication type with a dedicated query, the filter cannot be combined with
+// other filters.
+func extractAuthTypeFilter(filters []util.Filter) (string, []util.Filter, error) {
+	var authType string
+	remaining := make([]util.Filter, 0, len(filters))
+
+	for _, filter := range filters {
+		if filter.Name != "authtype" || filter.Subresource != "" {
+			remaining = append(remaining, filter)
+			continue
+		}
+
+		if (filter.Operation != "" && filter.Operation != "eq") || len(filter.Value) != 1 {
+			return "", nil, util.NewErrBadRequest(`the "authtype" filter accepts a single value`)
+		}
+
+		authType = filter.Value[0]
+	}
+
+	if authType != "" {
+		for _, filter := range remaining {
+			if filter.Operation != "sort_by" {
+				return "", nil, util.NewErrBadRequest(`the "authtype" filter cannot be combined with other filters`)
+			}
+		}
+	}
+
+	return authType, remaining, nil
+}
+
 func ApplicationGet(c echo.Context) error {
 	applicationDB, err := getApplicationDao(c)
 	if err != nil {
@@ -84,6 +146,21 @@ func ApplicationGet(c echo.Context) error {
 	return c.JSON(http.StatusOK, app.ToResponse())
 }
 
+// ApplicationAvailabilitySummary returns how many of the tenant's applications are on each availability status.
+func ApplicationAvailabilitySummary(c echo.Context) error {
+	applicationDB, err := getApplicationDao(c)
+	if err != nil {
+		return err
+	}
+
+	summary, err := applicationDB.AvailabilitySummary()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, summary)
+}
+
 func ApplicationCreate(c echo.Context) error {
 	applicationDB, err := getApplicationDao(c)
 	if err != nil {
@@ -388,3 +465,29 @@ func ApplicationUnpause(c echo.Context) error {
 
 	return c.JSON(http.StatusNoContent, nil)
 }
+
+// ApplicationValidateCredentials validates the credentials of the given application with the validator of its
+// application type.
+func ApplicationValidateCredentials(c echo.Context) error {
+	applicationId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	tenantId, err := getTenantFromEchoContext(c)
+	if err != nil {
+		return err
+	}
+
+	applicationDao, err := getApplicationDao(c)
+	if err != nil {
+		return err
+	}
+
+	valid, reason, err := applicationDao.ValidateCredentials(c.Request().Context(), applicationId, tenantId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, m.ApplicationCredentialValidationResponse{Valid: valid, Error: reason})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -336,6 +337,140 @@ func TestApplicationListBadRequestInvalidFilter(t *testing.T) {
 	templates.BadRequestTest(t, rec)
 }
 
+// TestApplicationListByAuthType tests that the applications can be filtered by a known authentication type.
+// TestApplicationListWithSourceType tests that the applications are listed along with their sources' type name when
+// requested.
+func TestApplicationListWithSourceType(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/applications?include_source_type=true",
+		nil,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"filters":  []util.Filter{},
+			"tenantID": int64(1),
+		},
+	)
+
+	err := ApplicationList(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out util.Collection
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	if len(out.Data) != len(fixtures.TestApplicationData) {
+		t.Fatalf(`want "%d" applications, got "%d"`, len(fixtures.TestApplicationData), len(out.Data))
+	}
+
+	sourceTypeNames := make(map[string]string)
+	for _, source := range fixtures.TestSourceData {
+		for _, sourceType := range fixtures.TestSourceTypeData {
+			if sourceType.Id == source.SourceTypeID {
+				sourceTypeNames[strconv.FormatInt(source.ID, 10)] = sourceType.Name
+			}
+		}
+	}
+
+	for _, item := range out.Data {
+		application, ok := item.(map[string]interface{})
+		if !ok {
+			t.Fatal("model did not deserialize as an application")
+		}
+
+		want := sourceTypeNames[application["source_id"].(string)]
+		if application["source_type_name"] != want {
+			t.Errorf(`want source type name "%s" for application "%v", got "%v"`, want, application["id"], application["source_type_name"])
+		}
+	}
+}
+
+func TestApplicationListByAuthType(t *testing.T) {
+	testutils.SkipIfNotSecretStoreDatabase(t)
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/applications?filter[authtype]=arn",
+		nil,
+		map[string]interface{}{
+			"limit":  100,
+			"offset": 0,
+			"filters": []util.Filter{
+				{Name: "authtype", Value: []string{"arn"}},
+			},
+			"tenantID": int64(1),
+		},
+	)
+
+	err := ApplicationList(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out util.Collection
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	// None of the fixtures' authentications are of the "arn" type.
+	if len(out.Data) != 0 {
+		t.Errorf(`want no applications, got "%d"`, len(out.Data))
+	}
+}
+
+// TestApplicationListByAuthTypeBadRequest tests that unknown authentication types, and the "authtype" filter combined
+// with other filters, are rejected.
+func TestApplicationListByAuthTypeBadRequest(t *testing.T) {
+	testCases := [][]util.Filter{
+		{
+			{Name: "authtype", Value: []string{"not-a-real-type"}},
+		},
+		{
+			{Name: "authtype", Value: []string{"arn"}},
+			{Name: "name", Value: []string{"app"}},
+		},
+		{
+			{Name: "authtype", Value: []string{"arn", "token"}},
+		},
+	}
+
+	for _, filters := range testCases {
+		c, rec := request.CreateTestContext(
+			http.MethodGet,
+			"/api/sources/v3.1/applications",
+			nil,
+			map[string]interface{}{
+				"limit":    100,
+				"offset":   0,
+				"filters":  filters,
+				"tenantID": int64(1),
+			},
+		)
+
+		badRequestApplicationList := ErrorHandlingContext(ApplicationList)
+		err := badRequestApplicationList(c)
+		if err != nil {
+			t.Error(err)
+		}
+
+		templates.BadRequestTest(t, rec)
+	}
+}
+
 func TestApplicationGet(t *testing.T) {
 	c, rec := request.CreateTestContext(
 		http.MethodGet,
@@ -369,6 +504,41 @@ func TestApplicationGet(t *testing.T) {
 	}
 }
 
+func TestApplicationAvailabilitySummary(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/applications/availability_summary",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	err := ApplicationAvailabilitySummary(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var summary m.AppAvailabilitySummary
+	err = json.Unmarshal(rec.Body.Bytes(), &summary)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	if summary.Total == 0 {
+		t.Error("want a non zero total of applications, got 0")
+	}
+
+	got := summary.Available + summary.Unavailable + summary.PartiallyAvailable + summary.Unknown
+	if summary.Total != got {
+		t.Errorf(`the total doesn't match the sum of the statuses. Want "%d", got "%d"`, summary.Total, got)
+	}
+}
+
 func TestApplicationGetNotFound(t *testing.T) {
 	c, rec := request.CreateTestContext(
 		http.MethodGet,
@@ -693,7 +863,7 @@ func TestApplicationDelete(t *testing.T) {
 
 	// Create a source
 	tenantID := fixtures.TestTenantData[0].Id
-	sourceDao := dao.GetSourceDao(&tenantID)
+	sourceDao := dao.GetSourceDao(context.Background(), &tenantID)
 
 	src := m.Source{
 		Name:         "Source for TestApplicationDelete()",
@@ -706,7 +876,7 @@ func TestApplicationDelete(t *testing.T) {
 	}
 
 	// Create an application
-	applicationDao := dao.GetApplicationDao(&tenantID)
+	applicationDao := dao.GetApplicationDao(context.Background(), &tenantID)
 
 	app := m.Application{
 		SourceID:          src.ID,
@@ -720,7 +890,7 @@ func TestApplicationDelete(t *testing.T) {
 	}
 
 	// Create an authentication
-	authenticationDao := dao.GetAuthenticationDao(&tenantID)
+	authenticationDao := dao.GetAuthenticationDao(context.Background(), &tenantID)
 
 	authName := "authentication for TestApplicationDelete()"
 	auth := m.Authentication{
@@ -737,7 +907,7 @@ func TestApplicationDelete(t *testing.T) {
 	}
 
 	// Create an application authentication
-	appAuthDao := dao.GetApplicationAuthenticationDao(&tenantID)
+	appAuthDao := dao.GetApplicationAuthenticationDao(context.Background(), &tenantID)
 	appAuth := m.ApplicationAuthentication{
 		ApplicationID:    app.ID,
 		AuthenticationID: auth.DbID,
@@ -1459,3 +1629,90 @@ func TestApplicationEditPausedUnitInvalidFields(t *testing.T) {
 	// Restore the binder to not affect any other tests.
 	c.Echo().Binder = backupBinder
 }
+
+// fakeCredentialValidator reports every credential as valid.
+type fakeCredentialValidator struct{}
+
+func (fakeCredentialValidator) Validate(_ context.Context, _ *m.Authentication) (bool, string, error) {
+	return true, "", nil
+}
+
+func TestApplicationValidateCredentials(t *testing.T) {
+	dao.CredentialValidators.Register(fixtures.TestApplicationData[0].ApplicationTypeID, fakeCredentialValidator{})
+	defer func() { dao.CredentialValidators = dao.NewCredentialValidatorRegistry() }()
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/applications/1/validate",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := ApplicationValidateCredentials(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out m.ApplicationCredentialValidationResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	if !out.Valid {
+		t.Errorf(`want valid credentials, got invalid ones: "%s"`, out.Error)
+	}
+}
+
+func TestApplicationValidateCredentialsNotFound(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/applications/9843762095/validate",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("9843762095")
+
+	notFoundValidate := ErrorHandlingContext(ApplicationValidateCredentials)
+	err := notFoundValidate(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+func TestApplicationValidateCredentialsBadRequest(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/applications/xxx/validate",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("xxx")
+
+	badRequestValidate := ErrorHandlingContext(ApplicationValidateCredentials)
+	err := badRequestValidate(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
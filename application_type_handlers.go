@@ -20,11 +20,14 @@ func getApplicationTypeDaoWithTenant(c echo.Context) (dao.ApplicationTypeDao, er
 		return nil, err
 	}
 
+	var applicationTypeDao dao.ApplicationTypeDao
 	if tenantId == 0 && err == nil {
-		return dao.GetApplicationTypeDao(nil), nil
+		applicationTypeDao = dao.GetApplicationTypeDao(c.Request().Context(), nil)
 	} else {
-		return dao.GetApplicationTypeDao(&tenantId), nil
+		applicationTypeDao = dao.GetApplicationTypeDao(c.Request().Context(), &tenantId)
 	}
+
+	return applicationTypeDao, nil
 }
 
 func SourceListApplicationTypes(c echo.Context) error {
@@ -123,5 +126,15 @@ func ApplicationTypeGet(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, appType.ToResponse())
+	sourceTypes, err := applicationTypeDB.ListCompatibleSourceTypes(appType.Id)
+	if err != nil {
+		return err
+	}
+
+	includes := make([]m.CompatibleTypeResponse, len(sourceTypes))
+	for i := range sourceTypes {
+		includes[i] = sourceTypes[i].ToCompatibleTypeResponse()
+	}
+
+	return c.JSON(http.StatusOK, m.ApplicationTypeWithIncludesResponse{ApplicationTypeResponse: appType.ToResponse(), Includes: includes})
 }
@@ -297,3 +297,84 @@ func TestApplicationTypeGetBadRequest(t *testing.T) {
 
 	templates.BadRequestTest(t, rec)
 }
+
+// TestApplicationTypeGetIncludes tests that the single application type response includes the source types it
+// supports.
+func TestApplicationTypeGetIncludes(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/application_types/2",
+		nil,
+		map[string]interface{}{},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+
+	err := ApplicationTypeGet(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out m.ApplicationTypeWithIncludesResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the response: %s`, err)
+	}
+
+	if len(out.Includes) != 2 || out.Includes[0].Name != "amazon" || out.Includes[1].Name != "google" {
+		t.Errorf(`want the "amazon" and "google" source types in the includes, got "%+v"`, out.Includes)
+	}
+}
+
+// TestApplicationTypeListCompatibleWithSourceType tests that the application types can be filtered by a source type,
+// and that an empty list is returned when none of them are compatible.
+func TestApplicationTypeListCompatibleWithSourceType(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	testCases := []struct {
+		sourceTypeId string
+		wantCount    int
+	}{
+		{sourceTypeId: "2", wantCount: 1},
+		{sourceTypeId: "100", wantCount: 0},
+	}
+
+	for _, tc := range testCases {
+		c, rec := request.CreateTestContext(
+			http.MethodGet,
+			"/api/sources/v3.1/application_types?source_type_id="+tc.sourceTypeId,
+			nil,
+			map[string]interface{}{
+				"limit":  100,
+				"offset": 0,
+				"filters": []util.Filter{
+					{Name: "source_type_id", Value: []string{tc.sourceTypeId}},
+				},
+			},
+		)
+
+		err := ApplicationTypeList(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+		}
+
+		var out util.Collection
+		err = json.Unmarshal(rec.Body.Bytes(), &out)
+		if err != nil {
+			t.Fatalf(`could not unmarshal the response: %s`, err)
+		}
+
+		if len(out.Data) != tc.wantCount {
+			t.Errorf(`[source type "%s"] want "%d" application types, got "%d"`, tc.sourceTypeId, tc.wantCount, len(out.Data))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// auditLogExportHeaders are the headers the audit log exports are streamed with, which have to be removed when the
+// export fails before anything has been streamed.
+var auditLogExportHeaders = []string{echo.HeaderContentType, echo.HeaderContentDisposition, "Transfer-Encoding", echo.HeaderContentEncoding, echo.HeaderVary}
+
+// function that defines how we get the dao - default implementation below.
+var getAuditLogDao func(c echo.Context) (dao.AuditLogDao, error)
+
+func getAuditLogDaoWithoutTenant(c echo.Context) (dao.AuditLogDao, error) {
+	auditLogDao := dao.GetAuditLogDao(c.Request().Context())
+
+	return auditLogDao, nil
+}
+
+// AuditLogExport streams the tenant's audit logs of the given period as a CSV file, compressing it when the client
+// accepts it.
+func AuditLogExport(c echo.Context) error {
+	if format := c.QueryParam("format"); format != "" && format != "csv" {
+		return util.NewErrBadRequest(fmt.Sprintf(`invalid format "%s", only "csv" is supported`, format))
+	}
+
+	from, err := time.Parse(time.RFC3339, c.QueryParam("from"))
+	if err != nil {
+		return util.NewErrBadRequest(fmt.Sprintf(`invalid "from" date: %s`, err))
+	}
+
+	to, err := time.Parse(time.RFC3339, c.QueryParam("to"))
+	if err != nil {
+		return util.NewErrBadRequest(fmt.Sprintf(`invalid "to" date: %s`, err))
+	}
+
+	tenantId, err := getTenantFromEchoContext(c)
+	if err != nil {
+		return err
+	}
+
+	auditLogDao, err := getAuditLogDao(c)
+	if err != nil {
+		return err
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "text/csv")
+	header.Set(echo.HeaderContentDisposition, "attachment; filename=audit_logs.csv")
+	header.Set("Transfer-Encoding", "chunked")
+
+	var w io.Writer = c.Response()
+	var gzipWriter *gzip.Writer
+	if strings.Contains(c.Request().Header.Get(echo.HeaderAcceptEncoding), "gzip") {
+		header.Set(echo.HeaderContentEncoding, "gzip")
+		header.Set(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+		gzipWriter = gzip.NewWriter(c.Response())
+		w = gzipWriter
+	}
+
+	err = auditLogDao.StreamExport(tenantId, from, to, w)
+	if err != nil {
+		// Once the export has started streaming its status cannot be changed anymore, so the export just gets cut
+		// short.
+		if c.Response().Committed {
+			l.Log.Errorf(`failed to stream the audit logs of tenant "%d": %s`, tenantId, err)
+			return nil
+		}
+
+		for _, name := range auditLogExportHeaders {
+			header.Del(name)
+		}
+
+		return err
+	}
+
+	if gzipWriter != nil {
+		return gzipWriter.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/labstack/echo/v4"
+)
+
+// setUpAuditLogDao replaces the audit log DAO with a mock one which holds a few audit logs of the fixture tenant, and
+// returns a function which restores the original DAO.
+func setUpAuditLogDao() func() {
+	backupGetAuditLogDao := getAuditLogDao
+
+	tenantId := fixtures.TestTenantData[0].Id
+	auditLogDao := &dao.MockAuditLogDao{AuditLogs: []m.AuditLog{
+		{ID: 1, EventType: "Source.create", ResourceType: "Source", ResourceId: "1", Actor: "jdoe", TenantId: tenantId, CreatedAt: time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: 2, EventType: "Source.update", ResourceType: "Source", ResourceId: "1", Actor: "psk", TenantId: tenantId, CreatedAt: time.Date(2022, 5, 2, 10, 0, 0, 0, time.UTC)},
+		{ID: 3, EventType: "Source.destroy", ResourceType: "Source", ResourceId: "1", Actor: "jdoe", TenantId: tenantId, CreatedAt: time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: 4, EventType: "Source.create", ResourceType: "Source", ResourceId: "2", Actor: "jdoe", TenantId: tenantId + 1, CreatedAt: time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)},
+	}}
+
+	getAuditLogDao = func(c echo.Context) (dao.AuditLogDao, error) { return auditLogDao, nil }
+
+	return func() { getAuditLogDao = backupGetAuditLogDao }
+}
+
+// TestAuditLogExport tests that the tenant's audit logs of the given period are exported as a CSV file.
+func TestAuditLogExport(t *testing.T) {
+	defer setUpAuditLogDao()()
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/audit_logs/export?from=2022-05-01T00:00:00Z&to=2022-06-01T00:00:00Z&format=csv",
+		nil,
+		map[string]interface{}{
+			h.TENANTID: fixtures.TestTenantData[0].Id,
+		},
+	)
+
+	err := AuditLogExport(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	if got := rec.Header().Get(echo.HeaderContentType); got != "text/csv" {
+		t.Errorf(`want content type "text/csv", got "%s"`, got)
+	}
+
+	if got := rec.Header().Get(echo.HeaderContentDisposition); got != "attachment; filename=audit_logs.csv" {
+		t.Errorf(`want an "audit_logs.csv" attachment, got "%s"`, got)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf(`could not read the exported CSV: %s`, err)
+	}
+
+	// The header plus the two audit logs of the tenant in the period.
+	if len(records) != 3 {
+		t.Fatalf(`want "3" CSV records, got "%d": %v`, len(records), records)
+	}
+
+	if records[1][0] != "1" || records[2][0] != "2" {
+		t.Errorf(`want the audit logs "1" and "2", got "%v"`, records[1:])
+	}
+}
+
+// TestAuditLogExportGzip tests that the export is compressed when the client accepts it.
+func TestAuditLogExportGzip(t *testing.T) {
+	defer setUpAuditLogDao()()
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/audit_logs/export?from=2022-05-01T00:00:00Z&to=2022-06-01T00:00:00Z",
+		nil,
+		map[string]interface{}{
+			h.TENANTID: fixtures.TestTenantData[0].Id,
+		},
+	)
+	c.Request().Header.Set(echo.HeaderAcceptEncoding, "gzip, deflate")
+
+	err := AuditLogExport(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rec.Header().Get(echo.HeaderContentEncoding); got != "gzip" {
+		t.Errorf(`want content encoding "gzip", got "%s"`, got)
+	}
+
+	reader, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf(`could not decompress the export: %s`, err)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf(`could not decompress the export: %s`, err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf(`could not read the exported CSV: %s`, err)
+	}
+
+	if len(records) != 3 {
+		t.Errorf(`want "3" CSV records, got "%d": %v`, len(records), records)
+	}
+}
+
+// TestAuditLogExportBadRequest tests that unsupported formats and invalid periods are rejected.
+func TestAuditLogExportBadRequest(t *testing.T) {
+	defer setUpAuditLogDao()()
+
+	queries := []string{
+		"from=2022-05-01T00:00:00Z&to=2022-06-01T00:00:00Z&format=json",
+		"from=yesterday&to=2022-06-01T00:00:00Z",
+		"from=2022-05-01T00:00:00Z",
+		"from=2022-06-01T00:00:00Z&to=2022-05-01T00:00:00Z",
+	}
+
+	for _, query := range queries {
+		c, rec := request.CreateTestContext(
+			http.MethodGet,
+			"/api/sources/v3.1/audit_logs/export?"+query,
+			nil,
+			map[string]interface{}{
+				h.TENANTID: fixtures.TestTenantData[0].Id,
+			},
+		)
+
+		badRequestAuditLogExport := ErrorHandlingContext(AuditLogExport)
+		err := badRequestAuditLogExport(c)
+		if err != nil {
+			t.Error(err)
+		}
+
+		templates.BadRequestTest(t, rec)
+
+		if got := rec.Header().Get(echo.HeaderContentDisposition); got != "" {
+			t.Errorf(`want no attachment for the query "%s", got "%s"`, query, got)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/dao"
@@ -14,6 +17,13 @@ import (
 	"gorm.io/datatypes"
 )
 
+const (
+	// expiringAuthenticationsHeader is the header of the authentication lists which tells how many authentications
+	// expire within the "expiringAuthenticationsWindow".
+	expiringAuthenticationsHeader = "X-Count-Expiring-30d"
+	expiringAuthenticationsWindow = 30 * 24 * time.Hour
+)
+
 var getAuthenticationDao func(c echo.Context) (dao.AuthenticationDao, error)
 
 func getAuthenticationDaoWithTenant(c echo.Context) (dao.AuthenticationDao, error) {
@@ -23,7 +33,9 @@ func getAuthenticationDaoWithTenant(c echo.Context) (dao.AuthenticationDao, erro
 		return nil, err
 	}
 
-	return dao.GetAuthenticationDao(&tenantId), nil
+	authDao := dao.GetAuthenticationDao(c.Request().Context(), &tenantId)
+
+	return authDao, nil
 }
 
 func AuthenticationList(c echo.Context) error {
@@ -42,12 +54,28 @@ func AuthenticationList(c echo.Context) error {
 		return err
 	}
 
-	authentications, count, err := authDao.List(limit, offset, filters)
+	expiresBefore, filters, err := extractExpiresBeforeFilter(filters)
 	if err != nil {
 		return err
 	}
 
+	var (
+		authentications []m.Authentication
+		count           int64
+	)
+
 	tenantId := authDao.Tenant()
+	if expiresBefore != nil {
+		authentications, count, err = authDao.ListExpiringBefore(c.Request().Context(), *tenantId, *expiresBefore, limit, offset)
+	} else {
+		authentications, count, err = authDao.List(limit, offset, filters)
+	}
+	if err != nil {
+		return err
+	}
+
+	setExpiringAuthenticationsCount(c, authDao, *tenantId)
+
 	out := make([]interface{}, 0, len(authentications))
 	for _, auth := range authentications {
 		// Set the marketplace token —if the auth is of the marketplace type— for the authentication.
@@ -62,6 +90,60 @@ func AuthenticationList(c echo.Context) error {
 	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
 }
 
+// extractExpiresBeforeFilter removes the "expires_before" filter from the given filters and returns its value. A date
+// without a time includes the whole day. Since the authentications get filtered by their expiry with a dedicated
+// query, the filter cannot be combined with other filters.
+func extractExpiresBeforeFilter(filters []util.Filter) (*time.Time, []util.Filter, error) {
+	var expiresBefore *time.Time
+	remaining := make([]util.Filter, 0, len(filters))
+
+	for _, filter := range filters {
+		if filter.Name != "expires_before" || filter.Subresource != "" {
+			remaining = append(remaining, filter)
+			continue
+		}
+
+		if (filter.Operation != "" && filter.Operation != "eq") || len(filter.Value) != 1 {
+			return nil, nil, util.NewErrBadRequest(`the "expires_before" filter accepts a single value`)
+		}
+
+		before, err := time.Parse(time.RFC3339, filter.Value[0])
+		if err != nil {
+			date, dateErr := time.Parse("2006-01-02", filter.Value[0])
+			if dateErr != nil {
+				return nil, nil, util.NewErrBadRequest(fmt.Sprintf(`invalid "expires_before" date "%s": expected a date or an RFC3339 timestamp`, filter.Value[0]))
+			}
+
+			before = date.AddDate(0, 0, 1).Add(-time.Microsecond)
+		}
+
+		expiresBefore = &before
+	}
+
+	if expiresBefore != nil {
+		for _, filter := range remaining {
+			if filter.Operation != "sort_by" {
+				return nil, nil, util.NewErrBadRequest(`the "expires_before" filter cannot be combined with other filters`)
+			}
+		}
+	}
+
+	return expiresBefore, remaining, nil
+}
+
+// setExpiringAuthenticationsCount sets the header which tells how many of the tenant's authentications expire within
+// the next 30 days, the ones which have already expired included. The header is left out when the count cannot be
+// obtained, since it is just a heads-up.
+func setExpiringAuthenticationsCount(c echo.Context, authDao dao.AuthenticationDao, tenantId int64) {
+	_, count, err := authDao.ListExpiringBefore(c.Request().Context(), tenantId, time.Now().Add(expiringAuthenticationsWindow), 1, 0)
+	if err != nil {
+		c.Logger().Debugf(`could not count the expiring authentications of tenant "%d": %s`, tenantId, err)
+		return
+	}
+
+	c.Response().Header().Set(expiringAuthenticationsHeader, strconv.FormatInt(count, 10))
+}
+
 func AuthenticationGet(c echo.Context) error {
 	authDao, err := getAuthenticationDao(c)
 	if err != nil {
@@ -120,6 +202,7 @@ func AuthenticationCreate(c echo.Context) error {
 		Password:     createRequest.Password,
 		Extra:        extra,
 		ExtraDb:      extraDb,
+		ExpiresAt:    createRequest.ExpiresAt,
 		ResourceType: createRequest.ResourceType,
 		ResourceID:   createRequest.ResourceID,
 	}
@@ -176,7 +259,16 @@ func AuthenticationEdit(c echo.Context) error {
 		return util.NewErrBadRequest(err)
 	}
 
-	sourceDao := dao.GetSourceDao(authDao.Tenant())
+	// An "available" status reported back from a connectivity check means that the credentials were successfully
+	// used.
+	if updateRequest.AvailabilityStatus != nil && *updateRequest.AvailabilityStatus == m.Available && !config.IsVaultOn() {
+		err = authDao.TouchLastUsed(auth.DbID)
+		if err != nil {
+			c.Logger().Warnf(`unable to update the last used timestamp of authentication "%d": %s`, auth.DbID, err)
+		}
+	}
+
+	sourceDao := dao.GetSourceDao(c.Request().Context(), authDao.Tenant())
 	source, err := sourceDao.GetById(&auth.SourceID)
 	if err != nil {
 		return err
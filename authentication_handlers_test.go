@@ -8,8 +8,10 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
@@ -21,6 +23,7 @@ import (
 	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/google/go-cmp/cmp"
+	"github.com/labstack/echo/v4"
 	"github.com/redhatinsights/platform-go-middlewares/identity"
 )
 
@@ -459,3 +462,149 @@ func TestAuthenticationDeleteNotFound(t *testing.T) {
 
 	templates.NotFoundTest(t, rec)
 }
+
+// TestAuthenticationListExpiresBefore tests that the authentications can be filtered by their expiry date, and that
+// the number of authentications expiring in the next 30 days is returned in a header.
+func TestAuthenticationListExpiresBefore(t *testing.T) {
+	tenantId := int64(12345)
+	inTwoDays := time.Now().Add(48 * time.Hour)
+	endOf2024 := time.Date(2024, 12, 31, 18, 0, 0, 0, time.UTC)
+	startOf2025 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	authDao := &dao.MockAuthenticationDao{Authentications: []m.Authentication{
+		{DbID: 1, TenantID: tenantId, ExpiresAt: &startOf2025},
+		{DbID: 2, TenantID: tenantId, ExpiresAt: &endOf2024},
+		{DbID: 3, TenantID: tenantId, ExpiresAt: &inTwoDays},
+		{DbID: 4, TenantID: tenantId},
+		{DbID: 5, TenantID: tenantId + 1, ExpiresAt: &endOf2024},
+	}}
+
+	backupDao := getAuthenticationDao
+	getAuthenticationDao = func(c echo.Context) (dao.AuthenticationDao, error) { return authDao, nil }
+	defer func() { getAuthenticationDao = backupDao }()
+
+	// The vault secret store represents the authentications with their "ID" field, so the database one is forced to
+	// be able to check the IDs of the listed authentications.
+	backupSecretStore := conf.SecretStore
+	conf.SecretStore = "database"
+	defer func() { conf.SecretStore = backupSecretStore }()
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/authentications?filter[expires_before]=2024-12-31",
+		nil,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"filters":  []util.Filter{{Name: "expires_before", Value: []string{"2024-12-31"}}},
+			"tenantID": tenantId,
+		},
+	)
+
+	err := AuthenticationList(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out util.Collection
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf(`failed unmarshalling output: %s`, err)
+	}
+
+	if len(out.Data) != 1 {
+		t.Fatalf(`want one authentication, got "%d"`, len(out.Data))
+	}
+
+	auth, ok := out.Data[0].(map[string]interface{})
+	if !ok || auth["id"] != "2" {
+		t.Errorf(`want authentication "2", got "%v"`, out.Data[0])
+	}
+
+	// The already expired authentications count too, but not the ones without expiry or from other tenants.
+	if got := rec.Header().Get(expiringAuthenticationsHeader); got != "3" {
+		t.Errorf(`want "%s" header "3", got "%s"`, expiringAuthenticationsHeader, got)
+	}
+}
+
+// TestAuthenticationListExpiresBeforeBadRequest tests that invalid "expires_before" filters are rejected.
+func TestAuthenticationListExpiresBeforeBadRequest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		filters []util.Filter
+	}{
+		{
+			name:    "invalid date",
+			filters: []util.Filter{{Name: "expires_before", Value: []string{"tomorrow"}}},
+		},
+		{
+			name:    "multiple values",
+			filters: []util.Filter{{Name: "expires_before", Value: []string{"2024-12-31", "2025-01-01"}}},
+		},
+		{
+			name:    "unsupported operation",
+			filters: []util.Filter{{Name: "expires_before", Operation: "gt", Value: []string{"2024-12-31"}}},
+		},
+		{
+			name: "combined with other filters",
+			filters: []util.Filter{
+				{Name: "expires_before", Value: []string{"2024-12-31"}},
+				{Name: "authtype", Value: []string{"token"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		c, rec := request.CreateTestContext(
+			http.MethodGet,
+			"/api/sources/v3.1/authentications",
+			nil,
+			map[string]interface{}{
+				"limit":    100,
+				"offset":   0,
+				"filters":  tc.filters,
+				"tenantID": int64(1),
+			},
+		)
+
+		badRequestAuthenticationList := ErrorHandlingContext(AuthenticationList)
+		err := badRequestAuthenticationList(c)
+		if err != nil {
+			t.Errorf(`[%s] unexpected error: %s`, tc.name, err)
+		}
+
+		templates.BadRequestTest(t, rec)
+	}
+}
+
+// TestExtractExpiresBeforeFilter tests that the filter's dates include the whole day, and that the timestamps are
+// taken as they are.
+func TestExtractExpiresBeforeFilter(t *testing.T) {
+	testCases := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "2024-12-31", want: time.Date(2024, 12, 31, 23, 59, 59, 999999000, time.UTC)},
+		{value: "2024-12-31T10:00:00Z", want: time.Date(2024, 12, 31, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		sortBy := util.Filter{Name: "expires_at", Operation: "sort_by"}
+		got, remaining, err := extractExpiresBeforeFilter([]util.Filter{{Name: "expires_before", Value: []string{tc.value}}, sortBy})
+		if err != nil {
+			t.Fatalf(`[%s] want no error, got "%s"`, tc.value, err)
+		}
+
+		if got == nil || !got.Equal(tc.want) {
+			t.Errorf(`[%s] want "%s", got "%v"`, tc.value, tc.want, got)
+		}
+
+		if len(remaining) != 1 || remaining[0].Name != sortBy.Name {
+			t.Errorf(`[%s] want only the sorting filter to remain, got "%v"`, tc.value, remaining)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// function that defines how we get the dao - default implementation below.
+var getAvailabilityScheduleDao func(c echo.Context) (dao.AvailabilityScheduleDao, error)
+
+func getAvailabilityScheduleDaoWithTenant(c echo.Context) (dao.AvailabilityScheduleDao, error) {
+	tenantId, err := getTenantFromEchoContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return dao.GetAvailabilityScheduleDao(c.Request().Context(), &tenantId), nil
+}
+
+// SourceAvailabilityScheduleUpsert schedules the periodic availability checks of a source with the given cron
+// expression, replacing any previous schedule.
+func SourceAvailabilityScheduleUpsert(c echo.Context) error {
+	sourceId, err := strconv.ParseInt(c.Param("source_id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	upsertRequest := &m.AvailabilityScheduleUpsertRequest{}
+	err = c.Bind(upsertRequest)
+	if err != nil {
+		return err
+	}
+
+	if upsertRequest.CronExpr == "" {
+		return util.NewErrBadRequest(`the "cron_expr" is required`)
+	}
+
+	scheduleDao, err := getAvailabilityScheduleDao(c)
+	if err != nil {
+		return err
+	}
+
+	err = scheduleDao.Upsert(sourceId, upsertRequest.CronExpr)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/labstack/echo/v4"
+)
+
+// setUpAvailabilityScheduleDao replaces the availability schedule DAO with an empty mock one, and returns it along with
+// a function which restores the original DAO.
+func setUpAvailabilityScheduleDao() (*dao.MockAvailabilityScheduleDao, func()) {
+	backupGetAvailabilityScheduleDao := getAvailabilityScheduleDao
+
+	scheduleDao := &dao.MockAvailabilityScheduleDao{}
+	getAvailabilityScheduleDao = func(c echo.Context) (dao.AvailabilityScheduleDao, error) { return scheduleDao, nil }
+
+	return scheduleDao, func() { getAvailabilityScheduleDao = backupGetAvailabilityScheduleDao }
+}
+
+// TestSourceAvailabilityScheduleUpsert tests that the source's availability checks get scheduled with the given cron
+// expression.
+func TestSourceAvailabilityScheduleUpsert(t *testing.T) {
+	scheduleDao, restore := setUpAvailabilityScheduleDao()
+	defer restore()
+
+	sourceId := fixtures.TestSourceData[0].ID
+
+	c, rec := request.CreateTestContext(
+		http.MethodPut,
+		"/api/sources/v3.1/sources/1/availability_schedule",
+		bytes.NewReader([]byte(`{"cron_expr": "*/5 * * * *"}`)),
+		map[string]interface{}{
+			h.TENANTID: fixtures.TestTenantData[0].Id,
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("1")
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+	err := SourceAvailabilityScheduleUpsert(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusNoContent, rec.Code)
+	}
+
+	if len(scheduleDao.Schedules) != 1 || scheduleDao.Schedules[0].SourceId != sourceId || scheduleDao.Schedules[0].CronExpr != "*/5 * * * *" {
+		t.Errorf(`want source "%d" scheduled with "*/5 * * * *", got "%v"`, sourceId, scheduleDao.Schedules)
+	}
+}
+
+// TestSourceAvailabilityScheduleUpsertBadRequest tests that a bad request is returned for invalid source IDs and for
+// missing or invalid cron expressions.
+func TestSourceAvailabilityScheduleUpsertBadRequest(t *testing.T) {
+	_, restore := setUpAvailabilityScheduleDao()
+	defer restore()
+
+	testCases := []struct {
+		sourceId string
+		body     string
+	}{
+		{sourceId: "xxx", body: `{"cron_expr": "*/5 * * * *"}`},
+		{sourceId: "1", body: `{}`},
+		{sourceId: "1", body: `{"cron_expr": "every hour"}`},
+	}
+
+	for _, tc := range testCases {
+		c, rec := request.CreateTestContext(
+			http.MethodPut,
+			"/api/sources/v3.1/sources/"+tc.sourceId+"/availability_schedule",
+			bytes.NewReader([]byte(tc.body)),
+			map[string]interface{}{
+				h.TENANTID: fixtures.TestTenantData[0].Id,
+			},
+		)
+
+		c.SetParamNames("source_id")
+		c.SetParamValues(tc.sourceId)
+		c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+		badRequestSourceAvailabilityScheduleUpsert := ErrorHandlingContext(SourceAvailabilityScheduleUpsert)
+		err := badRequestSourceAvailabilityScheduleUpsert(c)
+		if err != nil {
+			t.Error(err)
+		}
+
+		templates.BadRequestTest(t, rec)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 	"github.com/redhatinsights/platform-go-middlewares/identity"
 )
@@ -48,3 +49,61 @@ func BulkCreate(c echo.Context) error {
 
 	return c.JSON(http.StatusCreated, output.ToResponse())
 }
+
+// SourceImportCsv imports the sources from the CSV file uploaded in the "file" field of a multipart form. Every row is
+// created independently, and the outcome of each one is reported back in a multi-status response.
+func SourceImportCsv(c echo.Context) error {
+	tenantID, ok := c.Get(h.TENANTID).(int64)
+	if !ok {
+		return fmt.Errorf("failed to pull tenant from request")
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return util.NewErrBadRequest(`a CSV file is required in the "file" field`)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return util.NewErrBadRequest(fmt.Sprintf("unable to open the uploaded file: %s", err))
+	}
+	defer file.Close()
+
+	reqSources, err := service.ParseSourcesCsv(file)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	accountNumber, err := getAccountNumberFromEchoContext(c)
+	if err != nil {
+		c.Logger().Warn(err)
+	}
+
+	results := service.BulkCreate(reqSources, &m.Tenant{Id: tenantID, ExternalTenant: accountNumber}, false)
+
+	output := m.BulkCreateOutput{}
+	out := make([]m.SourceImportRowResponse, len(results))
+	for i, result := range results {
+		out[i] = m.SourceImportRowResponse{Row: i + 1, Status: result.Status}
+
+		if result.Err != nil {
+			out[i].Error = result.Err.Error()
+		}
+
+		if result.Source != nil {
+			output.Sources = append(output.Sources, *result.Source)
+		}
+	}
+
+	if len(output.Sources) != 0 {
+		forwardableHeaders, err := service.ForwadableHeaders(c)
+		if err != nil {
+			return err
+		}
+
+		xrhid, _ := c.Get(h.XRHID).(string)
+		service.SendBulkMessages(&output, forwardableHeaders, xrhid)
+	}
+
+	return c.JSON(http.StatusMultiStatus, out)
+}
@@ -3,13 +3,19 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
 	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/labstack/echo/v4"
 	"github.com/redhatinsights/platform-go-middlewares/identity"
 )
 
@@ -40,3 +46,124 @@ func TestBulkCreateMissingSourceType(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// csvImportContext creates a test context with a multipart form which holds the given CSV contents in its "file" field.
+func csvImportContext(t *testing.T, contents string) (echo.Context, *httptest.ResponseRecorder) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("file", "sources.csv")
+	if err != nil {
+		t.Fatalf("could not create the form file: %s", err)
+	}
+
+	_, err = part.Write([]byte(contents))
+	if err != nil {
+		t.Fatalf("could not write the form file: %s", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("could not close the multipart writer: %s", err)
+	}
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/sources/import/csv",
+		body,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+	c.Request().Header.Add("Content-Type", writer.FormDataContentType())
+	c.Set("identity", &identity.XRHID{Identity: identity.Identity{AccountNumber: fixtures.TestTenantData[0].ExternalTenant}})
+
+	return c, rec
+}
+
+func TestSourceImportCsv(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	contents := "name,source_type_name,external_id\n" +
+		"csv imported source,amazon,csv-import-1\n" +
+		"csv source with a bad type,nonexistent,\n" +
+		fixtures.TestSourceData[0].Name + ",amazon,\n"
+
+	c, rec := csvImportContext(t, contents)
+
+	err := SourceImportCsv(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Remove the imported source so that it doesn't affect the rest of the tests.
+	defer dao.DB.Where("name = ?", "csv imported source").Delete(&m.Source{})
+
+	if rec.Code != http.StatusMultiStatus {
+		t.Errorf("want status %d, got %d", http.StatusMultiStatus, rec.Code)
+	}
+
+	var out []m.SourceImportRowResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatal("Failed unmarshaling output")
+	}
+
+	wantStatuses := []string{service.BulkCreateStatusCreated, service.BulkCreateStatusError, service.BulkCreateStatusSkipped}
+	if len(out) != len(wantStatuses) {
+		t.Fatalf("want %d rows, got %d", len(wantStatuses), len(out))
+	}
+
+	for i, want := range wantStatuses {
+		if out[i].Row != i+1 {
+			t.Errorf("want row %d, got %d", i+1, out[i].Row)
+		}
+
+		if out[i].Status != want {
+			t.Errorf(`want status "%s" for row %d, got "%s" (%s)`, want, i+1, out[i].Status, out[i].Error)
+		}
+	}
+}
+
+func TestSourceImportCsvMissingColumn(t *testing.T) {
+	c, rec := csvImportContext(t, "name\ncsv imported source\n")
+
+	badRequestSourceImportCsv := ErrorHandlingContext(SourceImportCsv)
+	err := badRequestSourceImportCsv(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+func TestSourceImportCsvMalformed(t *testing.T) {
+	c, rec := csvImportContext(t, "name,source_type_name\n\"csv imported source,amazon\n")
+
+	badRequestSourceImportCsv := ErrorHandlingContext(SourceImportCsv)
+	err := badRequestSourceImportCsv(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+func TestSourceImportCsvMissingFile(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/sources/import/csv",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	badRequestSourceImportCsv := ErrorHandlingContext(SourceImportCsv)
+	err := badRequestSourceImportCsv(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
@@ -4,7 +4,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	clowder "github.com/redhatinsights/app-common-go/pkg/api/v1"
 	"github.com/spf13/viper"
@@ -14,43 +17,75 @@ var parsedConfig *SourcesApiConfig
 
 // SourcesApiConfig is the struct for storing runtime configuration
 type SourcesApiConfig struct {
-	AppName                   string
-	Hostname                  string
-	KafkaBrokers              []string
-	KafkaTopics               map[string]string
-	KafkaGroupID              string
-	MetricsPort               int
-	LogLevel                  string
-	LogLevelForMiddlewareLogs string
-	LogGroup                  string
-	LogHandler                string
-	LogLevelForSqlLogs        string
-	MarketplaceHost           string
-	AwsRegion                 string
-	AwsAccessKeyID            string
-	AwsSecretAccessKey        string
-	DatabaseHost              string
-	DatabasePort              int
-	DatabaseUser              string
-	DatabasePassword          string
-	DatabaseName              string
-	FeatureFlagsEnvironment   string
-	FeatureFlagsUrl           string
-	FeatureFlagsAPIToken      string
-	FeatureFlagsService       string
-	FeatureFlagsBearerToken   string
-	CacheHost                 string
-	CachePort                 int
-	CachePassword             string
-	SlowSQLThreshold          int
-	Psks                      []string
-	BypassRbac                bool
-	StatusListener            bool
-	BackgroundWorker          bool
-	MigrationsSetup           bool
-	MigrationsReset           bool
-	SecretStore               string
-	TenantTranslatorUrl       string
+	AppName                      string
+	Hostname                     string
+	KafkaBrokers                 []string
+	KafkaTopics                  map[string]string
+	KafkaGroupID                 string
+	MetricsPort                  int
+	LogLevel                     string
+	LogLevelForMiddlewareLogs    string
+	LogGroup                     string
+	LogHandler                   string
+	LogLevelForSqlLogs           string
+	MarketplaceHost              string
+	AwsRegion                    string
+	AwsAccessKeyID               string
+	AwsSecretAccessKey           string
+	DatabaseHost                 string
+	DatabasePort                 int
+	DatabaseUser                 string
+	DatabasePassword             string
+	DatabaseName                 string
+	FeatureFlagsEnvironment      string
+	FeatureFlagsUrl              string
+	FeatureFlagsAPIToken         string
+	FeatureFlagsService          string
+	FeatureFlagsBearerToken      string
+	CacheHost                    string
+	CachePort                    int
+	CachePassword                string
+	SlowQueryMs                  int
+	LogAllSqlQueries             bool
+	Psks                         []string
+	BypassRbac                   bool
+	StatusListener               bool
+	BackgroundWorker             bool
+	MigrationsSetup              bool
+	MigrationsReset              bool
+	SecretStore                  string
+	TenantTranslatorUrl          string
+	StaleAuthDays                int
+	AcceptedContentTypes         []string
+	RequestAvailabilityOnCreate  bool
+	DefaultTenantMaxSources      int
+	DefaultTenantMaxApplications int
+	MaskSensitiveFields          bool
+	RbacDenialIncludesPermission bool
+	FilterValueMaxLength         int
+	MaxFiltersPerRequest         int
+	MaxInFilterValues            int
+	DefaultPageSize              int
+	KnownAuthTypes               []string
+	IdentityHeaderName           string
+	PskHeaderName                string
+	TenantCacheSize              int
+	TenantCacheTtl               time.Duration
+	LogAuthorizationDenials      bool
+	HashDeniedIdentities         bool
+	RbacRetryAfter               time.Duration
+	CostCenterPattern            string
+	AggregateBulkEvents          bool
+	CyndiLagThreshold            int64
+	CyndiReplicationSlot         string
+	RhcConnectionDeleteGuard     bool
+	MaxRequestBodyBytes          int64
+	MaxBulkCreateBodyBytes       int64
+	TrustedSystemCNs             []string
+	PermissiveSystemIdentities   bool
+	ReadOnly                     bool
+	ReadOnlyRetryAfter           time.Duration
+	MaxAvailabilityStreamClients int
 }
 
 // Get - returns the config parsed from runtime vars
@@ -146,8 +181,16 @@ func Get() *SourcesApiConfig {
 	options.SetDefault("LogLevelForMiddlewareLogs", "DEBUG")
 	options.SetDefault("LogLevelForSqlLogs", "DEBUG")
 	options.SetDefault("MarketplaceHost", os.Getenv("MARKETPLACE_HOST"))
-	options.SetDefault("SlowSQLThreshold", 2) //seconds
+	// The queries which take longer than the threshold get logged as slow queries.
+	slowQueryMs, err := strconv.Atoi(os.Getenv("DB_SLOW_QUERY_MS"))
+	if err != nil || slowQueryMs <= 0 {
+		slowQueryMs = 2000
+	}
+	options.SetDefault("SlowQueryMs", slowQueryMs)
+	// Every query gets logged in development, while only the slow ones get logged in production.
+	options.SetDefault("LogAllSqlQueries", os.Getenv("SOURCES_ENV") != "stage" && os.Getenv("SOURCES_ENV") != "prod")
 	options.SetDefault("BypassRbac", os.Getenv("BYPASS_RBAC") == "true")
+	options.SetDefault("RequestAvailabilityOnCreate", os.Getenv("REQUEST_AVAILABILITY_ON_CREATE") == "true")
 	// The secret store defaults to the database in case an empty or an incorrect value are provided.
 	secretStore := os.Getenv("SECRET_STORE")
 	if secretStore != "database" && secretStore != "vault" {
@@ -155,6 +198,158 @@ func Get() *SourcesApiConfig {
 	}
 	options.SetDefault("SecretStore", secretStore)
 	options.SetDefault("TenantTranslatorUrl", os.Getenv("TENANT_TRANSLATOR_URL"))
+	// The number of days after which an unused authentication is considered stale.
+	staleAuthDays, err := strconv.Atoi(os.Getenv("STALE_AUTH_DAYS"))
+	if err != nil || staleAuthDays <= 0 {
+		staleAuthDays = 90
+	}
+	options.SetDefault("StaleAuthDays", staleAuthDays)
+	// The content types accepted on the bodies of the write requests.
+	acceptedContentTypes := os.Getenv("ACCEPTED_CONTENT_TYPES")
+	if acceptedContentTypes == "" {
+		acceptedContentTypes = "application/json,application/vnd.api+json"
+	}
+	options.SetDefault("AcceptedContentTypes", strings.Split(acceptedContentTypes, ","))
+	// The quotas given to the tenants when they get onboarded.
+	defaultTenantMaxSources, err := strconv.Atoi(os.Getenv("DEFAULT_TENANT_MAX_SOURCES"))
+	if err != nil || defaultTenantMaxSources <= 0 {
+		defaultTenantMaxSources = 1000
+	}
+	options.SetDefault("DefaultTenantMaxSources", defaultTenantMaxSources)
+	defaultTenantMaxApplications, err := strconv.Atoi(os.Getenv("DEFAULT_TENANT_MAX_APPLICATIONS"))
+	if err != nil || defaultTenantMaxApplications <= 0 {
+		defaultTenantMaxApplications = 5000
+	}
+	options.SetDefault("DefaultTenantMaxApplications", defaultTenantMaxApplications)
+	// The sensitive fields, such as the "rhc_id"s, are masked in the logs by default in production.
+	maskSensitiveFields := os.Getenv("MASK_SENSITIVE_FIELDS")
+	if maskSensitiveFields == "" {
+		options.SetDefault("MaskSensitiveFields", os.Getenv("SOURCES_ENV") == "prod")
+	} else {
+		options.SetDefault("MaskSensitiveFields", maskSensitiveFields == "true")
+	}
+	// The permission required by RBAC is included in the denial responses unless it is considered sensitive.
+	options.SetDefault("RbacDenialIncludesPermission", os.Getenv("RBAC_DENIAL_INCLUDES_PERMISSION") != "false")
+	// The limits which protect the queries from oversized filters.
+	filterValueMaxLength, err := strconv.Atoi(os.Getenv("FILTER_VALUE_MAX_LENGTH"))
+	if err != nil || filterValueMaxLength <= 0 {
+		filterValueMaxLength = 256
+	}
+	options.SetDefault("FilterValueMaxLength", filterValueMaxLength)
+	maxFiltersPerRequest, err := strconv.Atoi(os.Getenv("MAX_FILTERS_PER_REQUEST"))
+	if err != nil || maxFiltersPerRequest <= 0 {
+		maxFiltersPerRequest = 50
+	}
+	options.SetDefault("MaxFiltersPerRequest", maxFiltersPerRequest)
+	maxInFilterValues, err := strconv.Atoi(os.Getenv("MAX_IN_FILTER_VALUES"))
+	if err != nil || maxInFilterValues <= 0 {
+		maxInFilterValues = 100
+	}
+	options.SetDefault("MaxInFilterValues", maxInFilterValues)
+	defaultPageSize, err := strconv.Atoi(os.Getenv("DEFAULT_PAGE_SIZE"))
+	if err != nil || defaultPageSize <= 0 {
+		defaultPageSize = 100
+	}
+	options.SetDefault("DefaultPageSize", defaultPageSize)
+	// The authentication types the applications can be filtered by.
+	knownAuthTypes := os.Getenv("KNOWN_AUTH_TYPES")
+	if knownAuthTypes == "" {
+		knownAuthTypes = "access_key_secret_key,api_token_account_id,arn,bitbucket-app-password,cloud-meter-arn,docker-access-token,github-personal-access-token,gitlab-personal-access-token,lighthouse_subscription_id,marketplace-token,ocid,project_id_service_account_json,quay-encrypted-password,receptor_node,tenant_id_client_id_client_secret,token,username_password"
+	}
+	options.SetDefault("KnownAuthTypes", strings.Split(knownAuthTypes, ","))
+	// The names of the request headers the identity and the PSK are read from, for the deployments which sit behind
+	// gateways that use different headers.
+	identityHeaderName := os.Getenv("IDENTITY_HEADER_NAME")
+	if identityHeaderName == "" {
+		identityHeaderName = "x-rh-identity"
+	}
+	options.SetDefault("IdentityHeaderName", identityHeaderName)
+	pskHeaderName := os.Getenv("PSK_HEADER_NAME")
+	if pskHeaderName == "" {
+		pskHeaderName = "x-rh-sources-psk"
+	}
+	options.SetDefault("PskHeaderName", pskHeaderName)
+	// The tenants resolved from the identities are cached to avoid hitting the database on every request.
+	tenantCacheSize, err := strconv.Atoi(os.Getenv("TENANT_CACHE_SIZE"))
+	if err != nil || tenantCacheSize <= 0 {
+		tenantCacheSize = 512
+	}
+	options.SetDefault("TenantCacheSize", tenantCacheSize)
+	tenantCacheTtl, err := time.ParseDuration(os.Getenv("TENANT_CACHE_TTL"))
+	if err != nil || tenantCacheTtl <= 0 {
+		tenantCacheTtl = 5 * time.Minute
+	}
+	options.SetDefault("TenantCacheTtl", tenantCacheTtl)
+	// The authorization denials can be logged as an audit trail of the unauthorized attempts, optionally hashing the
+	// identities of the callers.
+	options.SetDefault("LogAuthorizationDenials", os.Getenv("LOG_AUTHORIZATION_DENIALS") == "true")
+	options.SetDefault("HashDeniedIdentities", os.Getenv("HASH_DENIED_IDENTITIES") == "true")
+	// The clients are told to retry after this long when RBAC is unavailable.
+	rbacRetryAfter, err := time.ParseDuration(os.Getenv("RBAC_RETRY_AFTER"))
+	if err != nil || rbacRetryAfter <= 0 {
+		rbacRetryAfter = 30 * time.Second
+	}
+	options.SetDefault("RbacRetryAfter", rbacRetryAfter)
+	// The sources' cost centers must match this pattern. Invalid patterns fall back to the default one.
+	costCenterPattern := os.Getenv("COST_CENTER_PATTERN")
+	if _, err := regexp.Compile(costCenterPattern); err != nil || costCenterPattern == "" {
+		costCenterPattern = `^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`
+	}
+	options.SetDefault("CostCenterPattern", costCenterPattern)
+	// The bulk operations raise a single aggregated event instead of one event per affected resource when enabled.
+	options.SetDefault("AggregateBulkEvents", os.Getenv("AGGREGATE_BULK_EVENTS") == "true")
+	// The readiness probe reports the Cyndi pipeline as degraded when it has more unconfirmed WAL bytes than this.
+	cyndiLagThreshold, err := strconv.ParseInt(os.Getenv("CYNDI_LAG_THRESHOLD"), 10, 64)
+	if err != nil || cyndiLagThreshold <= 0 {
+		cyndiLagThreshold = 64 * 1024 * 1024
+	}
+	options.SetDefault("CyndiLagThreshold", cyndiLagThreshold)
+	// The replication slot the Cyndi pipeline consumes, which the readiness probe checks the lag of.
+	cyndiReplicationSlot := os.Getenv("CYNDI_REPLICATION_SLOT")
+	if cyndiReplicationSlot == "" {
+		cyndiReplicationSlot = "cyndi"
+	}
+	options.SetDefault("CyndiReplicationSlot", cyndiReplicationSlot)
+	// The connections linked to available sources can only be deleted by forcing it when enabled.
+	options.SetDefault("RhcConnectionDeleteGuard", os.Getenv("RHC_CONNECTION_DELETE_GUARD") == "true")
+	// The write requests with bigger bodies get rejected. The bulk create requests carry many resources at once, so
+	// they get a limit of their own.
+	maxRequestBodyBytes, err := strconv.ParseInt(os.Getenv("MAX_REQUEST_BODY_BYTES"), 10, 64)
+	if err != nil || maxRequestBodyBytes <= 0 {
+		maxRequestBodyBytes = 1024 * 1024
+	}
+	options.SetDefault("MaxRequestBodyBytes", maxRequestBodyBytes)
+	maxBulkCreateBodyBytes, err := strconv.ParseInt(os.Getenv("MAX_BULK_CREATE_BODY_BYTES"), 10, 64)
+	if err != nil || maxBulkCreateBodyBytes <= 0 {
+		maxBulkCreateBodyBytes = 10 * 1024 * 1024
+	}
+	options.SetDefault("MaxBulkCreateBodyBytes", maxBulkCreateBodyBytes)
+	// The system identities are only authorized when their "cn" or "cluster_id" is in this list, unless it is empty.
+	trustedSystemCNs := make([]string, 0)
+	for _, cn := range strings.Split(os.Getenv("TRUSTED_SYSTEM_CNS"), ",") {
+		if cn = strings.TrimSpace(cn); cn != "" {
+			trustedSystemCNs = append(trustedSystemCNs, cn)
+		}
+	}
+	options.SetDefault("TrustedSystemCNs", trustedSystemCNs)
+	// The system identities are authorized as long as they carry any "cn" or "cluster_id" when enabled, which was the
+	// behavior before the stricter checks got introduced.
+	options.SetDefault("PermissiveSystemIdentities", os.Getenv("PERMISSIVE_SYSTEM_IDENTITIES") == "true")
+	// The writes are rejected with a "503 Service Unavailable" when enabled, which is meant for the maintenance windows.
+	options.SetDefault("ReadOnly", os.Getenv("READ_ONLY") == "true")
+	// The clients are told to retry their writes after this long when the read-only mode is enabled.
+	readOnlyRetryAfter, err := time.ParseDuration(os.Getenv("READ_ONLY_RETRY_AFTER"))
+	if err != nil || readOnlyRetryAfter <= 0 {
+		readOnlyRetryAfter = 5 * time.Minute
+	}
+	options.SetDefault("ReadOnlyRetryAfter", readOnlyRetryAfter)
+	// Every pod follows the availability changes through a single database connection, which is shared by up to this
+	// many streaming clients.
+	maxAvailabilityStreamClients, err := strconv.Atoi(os.Getenv("MAX_AVAILABILITY_STREAM_CLIENTS"))
+	if err != nil || maxAvailabilityStreamClients <= 0 {
+		maxAvailabilityStreamClients = 500
+	}
+	options.SetDefault("MaxAvailabilityStreamClients", maxAvailabilityStreamClients)
 
 	// Parse any Flags (using our own flag set to not conflict with the global flag)
 	fs := flag.NewFlagSet("runtime", flag.ContinueOnError)
@@ -163,7 +358,7 @@ func Get() *SourcesApiConfig {
 	setUpDatabase := fs.Bool("setup", false, "create the database and exit")
 	resetDatabase := fs.Bool("reset", false, "drop the database, recreate it and exit")
 
-	err := fs.Parse(os.Args[1:])
+	err = fs.Parse(os.Args[1:])
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error parsing flags: %v\n", err)
 	}
@@ -187,43 +382,75 @@ func Get() *SourcesApiConfig {
 
 	options.AutomaticEnv()
 	parsedConfig = &SourcesApiConfig{
-		AppName:                   options.GetString("AppName"),
-		Hostname:                  options.GetString("Hostname"),
-		KafkaBrokers:              options.GetStringSlice("KafkaBrokers"),
-		KafkaTopics:               options.GetStringMapString("KafkaTopics"),
-		KafkaGroupID:              options.GetString("KafkaGroupID"),
-		MetricsPort:               options.GetInt("MetricsPort"),
-		LogLevel:                  options.GetString("LogLevel"),
-		LogLevelForMiddlewareLogs: options.GetString("LogLevelForMiddlewareLogs"),
-		LogLevelForSqlLogs:        options.GetString("LogLevelForSqlLogs"),
-		SlowSQLThreshold:          options.GetInt("SlowSQLThreshold"),
-		LogHandler:                options.GetString("LogHandler"),
-		LogGroup:                  options.GetString("LogGroup"),
-		MarketplaceHost:           options.GetString("MarketplaceHost"),
-		AwsRegion:                 options.GetString("AwsRegion"),
-		AwsAccessKeyID:            options.GetString("AwsAccessKeyID"),
-		AwsSecretAccessKey:        options.GetString("AwsSecretAccessKey"),
-		DatabaseHost:              options.GetString("DatabaseHost"),
-		DatabasePort:              options.GetInt("DatabasePort"),
-		DatabaseUser:              options.GetString("DatabaseUser"),
-		DatabasePassword:          options.GetString("DatabasePassword"),
-		DatabaseName:              options.GetString("DatabaseName"),
-		FeatureFlagsEnvironment:   options.GetString("FeatureFlagsEnvironment"),
-		FeatureFlagsUrl:           options.GetString("FeatureFlagsUrl"),
-		FeatureFlagsAPIToken:      options.GetString("FeatureFlagsAPIToken"),
-		FeatureFlagsBearerToken:   options.GetString("FeatureFlagsBearerToken"),
-		FeatureFlagsService:       options.GetString("FeatureFlagsService"),
-		CacheHost:                 options.GetString("CacheHost"),
-		CachePort:                 options.GetInt("CachePort"),
-		CachePassword:             options.GetString("CachePassword"),
-		Psks:                      options.GetStringSlice("psks"),
-		BypassRbac:                options.GetBool("BypassRbac"),
-		StatusListener:            options.GetBool("StatusListener"),
-		BackgroundWorker:          options.GetBool("BackgroundWorker"),
-		MigrationsSetup:           options.GetBool("MigrationsSetup"),
-		MigrationsReset:           options.GetBool("MigrationsReset"),
-		SecretStore:               options.GetString("SecretStore"),
-		TenantTranslatorUrl:       options.GetString("TenantTranslatorUrl"),
+		AppName:                      options.GetString("AppName"),
+		Hostname:                     options.GetString("Hostname"),
+		KafkaBrokers:                 options.GetStringSlice("KafkaBrokers"),
+		KafkaTopics:                  options.GetStringMapString("KafkaTopics"),
+		KafkaGroupID:                 options.GetString("KafkaGroupID"),
+		MetricsPort:                  options.GetInt("MetricsPort"),
+		LogLevel:                     options.GetString("LogLevel"),
+		LogLevelForMiddlewareLogs:    options.GetString("LogLevelForMiddlewareLogs"),
+		LogLevelForSqlLogs:           options.GetString("LogLevelForSqlLogs"),
+		SlowQueryMs:                  options.GetInt("SlowQueryMs"),
+		LogAllSqlQueries:             options.GetBool("LogAllSqlQueries"),
+		LogHandler:                   options.GetString("LogHandler"),
+		LogGroup:                     options.GetString("LogGroup"),
+		MarketplaceHost:              options.GetString("MarketplaceHost"),
+		AwsRegion:                    options.GetString("AwsRegion"),
+		AwsAccessKeyID:               options.GetString("AwsAccessKeyID"),
+		AwsSecretAccessKey:           options.GetString("AwsSecretAccessKey"),
+		DatabaseHost:                 options.GetString("DatabaseHost"),
+		DatabasePort:                 options.GetInt("DatabasePort"),
+		DatabaseUser:                 options.GetString("DatabaseUser"),
+		DatabasePassword:             options.GetString("DatabasePassword"),
+		DatabaseName:                 options.GetString("DatabaseName"),
+		FeatureFlagsEnvironment:      options.GetString("FeatureFlagsEnvironment"),
+		FeatureFlagsUrl:              options.GetString("FeatureFlagsUrl"),
+		FeatureFlagsAPIToken:         options.GetString("FeatureFlagsAPIToken"),
+		FeatureFlagsBearerToken:      options.GetString("FeatureFlagsBearerToken"),
+		FeatureFlagsService:          options.GetString("FeatureFlagsService"),
+		CacheHost:                    options.GetString("CacheHost"),
+		CachePort:                    options.GetInt("CachePort"),
+		CachePassword:                options.GetString("CachePassword"),
+		Psks:                         options.GetStringSlice("psks"),
+		BypassRbac:                   options.GetBool("BypassRbac"),
+		StatusListener:               options.GetBool("StatusListener"),
+		BackgroundWorker:             options.GetBool("BackgroundWorker"),
+		MigrationsSetup:              options.GetBool("MigrationsSetup"),
+		MigrationsReset:              options.GetBool("MigrationsReset"),
+		SecretStore:                  options.GetString("SecretStore"),
+		TenantTranslatorUrl:          options.GetString("TenantTranslatorUrl"),
+		StaleAuthDays:                options.GetInt("StaleAuthDays"),
+		AcceptedContentTypes:         options.GetStringSlice("AcceptedContentTypes"),
+		RequestAvailabilityOnCreate:  options.GetBool("RequestAvailabilityOnCreate"),
+		DefaultTenantMaxSources:      options.GetInt("DefaultTenantMaxSources"),
+		DefaultTenantMaxApplications: options.GetInt("DefaultTenantMaxApplications"),
+		MaskSensitiveFields:          options.GetBool("MaskSensitiveFields"),
+		RbacDenialIncludesPermission: options.GetBool("RbacDenialIncludesPermission"),
+		FilterValueMaxLength:         options.GetInt("FilterValueMaxLength"),
+		MaxFiltersPerRequest:         options.GetInt("MaxFiltersPerRequest"),
+		MaxInFilterValues:            options.GetInt("MaxInFilterValues"),
+		DefaultPageSize:              options.GetInt("DefaultPageSize"),
+		KnownAuthTypes:               options.GetStringSlice("KnownAuthTypes"),
+		IdentityHeaderName:           options.GetString("IdentityHeaderName"),
+		PskHeaderName:                options.GetString("PskHeaderName"),
+		TenantCacheSize:              options.GetInt("TenantCacheSize"),
+		TenantCacheTtl:               options.GetDuration("TenantCacheTtl"),
+		LogAuthorizationDenials:      options.GetBool("LogAuthorizationDenials"),
+		HashDeniedIdentities:         options.GetBool("HashDeniedIdentities"),
+		RbacRetryAfter:               options.GetDuration("RbacRetryAfter"),
+		CostCenterPattern:            options.GetString("CostCenterPattern"),
+		AggregateBulkEvents:          options.GetBool("AggregateBulkEvents"),
+		CyndiLagThreshold:            options.GetInt64("CyndiLagThreshold"),
+		CyndiReplicationSlot:         options.GetString("CyndiReplicationSlot"),
+		RhcConnectionDeleteGuard:     options.GetBool("RhcConnectionDeleteGuard"),
+		MaxRequestBodyBytes:          options.GetInt64("MaxRequestBodyBytes"),
+		MaxBulkCreateBodyBytes:       options.GetInt64("MaxBulkCreateBodyBytes"),
+		TrustedSystemCNs:             options.GetStringSlice("TrustedSystemCNs"),
+		PermissiveSystemIdentities:   options.GetBool("PermissiveSystemIdentities"),
+		ReadOnly:                     options.GetBool("ReadOnly"),
+		ReadOnlyRetryAfter:           options.GetDuration("ReadOnlyRetryAfter"),
+		MaxAvailabilityStreamClients: options.GetInt("MaxAvailabilityStreamClients"),
 	}
 
 	return parsedConfig
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/RedHatInsights/sources-api-go/config"
@@ -11,12 +12,13 @@ import (
 
 // GetApplicationAuthenticationDao is a function definition that can be replaced in runtime in case some other DAO
 // provider is needed.
-var GetApplicationAuthenticationDao func(*int64) ApplicationAuthenticationDao
+var GetApplicationAuthenticationDao func(context.Context, *int64) ApplicationAuthenticationDao
 
 // getDefaultApplicationAuthenticationDao gets the default DAO implementation which will have the given tenant ID.
-func getDefaultApplicationAuthenticationDao(tenantId *int64) ApplicationAuthenticationDao {
+func getDefaultApplicationAuthenticationDao(ctx context.Context, tenantId *int64) ApplicationAuthenticationDao {
 	return &applicationAuthenticationDaoImpl{
-		TenantID: tenantId,
+		requestContext: requestContext{ctx: ctx},
+		TenantID:       tenantId,
 	}
 }
 
@@ -27,6 +29,7 @@ func init() {
 
 type applicationAuthenticationDaoImpl struct {
 	TenantID *int64
+	requestContext
 }
 
 func (a *applicationAuthenticationDaoImpl) ApplicationAuthenticationsByApplications(applications []m.Application) ([]m.ApplicationAuthentication, error) {
@@ -37,8 +40,7 @@ func (a *applicationAuthenticationDaoImpl) ApplicationAuthenticationsByApplicati
 		applicationIDs = append(applicationIDs, value.ID)
 	}
 
-	err := DB.
-		Debug().
+	err := a.db().
 		Preload("Tenant").
 		Where("application_id IN ?", applicationIDs).
 		Where("tenant_id = ?", a.TenantID).
@@ -55,8 +57,7 @@ func (a *applicationAuthenticationDaoImpl) ApplicationAuthenticationsByApplicati
 func (a *applicationAuthenticationDaoImpl) ApplicationAuthenticationsByAuthentications(authentications []m.Authentication) ([]m.ApplicationAuthentication, error) {
 	var applicationAuthentications []m.ApplicationAuthentication
 
-	query := DB.
-		Debug().
+	query := a.db().
 		Preload("Tenant")
 
 	if config.IsVaultOn() {
@@ -99,7 +100,7 @@ func (a *applicationAuthenticationDaoImpl) ApplicationAuthenticationsByResource(
 
 func (a *applicationAuthenticationDaoImpl) List(limit int, offset int, filters []util.Filter) ([]m.ApplicationAuthentication, int64, error) {
 	appAuths := make([]m.ApplicationAuthentication, 0, limit)
-	query := DB.Debug().
+	query := a.db().
 		Model(&m.ApplicationAuthentication{}).
 		Where("tenant_id = ?", a.TenantID)
 
@@ -120,7 +121,7 @@ func (a *applicationAuthenticationDaoImpl) List(limit int, offset int, filters [
 
 func (a *applicationAuthenticationDaoImpl) GetById(id *int64) (*m.ApplicationAuthentication, error) {
 	appAuth := &m.ApplicationAuthentication{ID: *id}
-	result := DB.Debug().
+	result := a.db().
 		Where("tenant_id = ?", a.TenantID).First(&appAuth)
 	if result.Error != nil {
 		return nil, util.NewErrNotFound("application authentication")
@@ -130,7 +131,7 @@ func (a *applicationAuthenticationDaoImpl) GetById(id *int64) (*m.ApplicationAut
 
 func (a *applicationAuthenticationDaoImpl) Create(appAuth *m.ApplicationAuthentication) error {
 	appAuth.TenantID = *a.TenantID
-	err := DB.Debug().Create(appAuth).Error
+	err := a.db().Create(appAuth).Error
 	if err != nil {
 		return util.NewErrBadRequest("failed to create application_authentication: " + err.Error())
 	}
@@ -138,15 +139,14 @@ func (a *applicationAuthenticationDaoImpl) Create(appAuth *m.ApplicationAuthenti
 }
 
 func (a *applicationAuthenticationDaoImpl) Update(appAuth *m.ApplicationAuthentication) error {
-	result := DB.Debug().Updates(appAuth)
+	result := a.db().Updates(appAuth)
 	return result.Error
 }
 
 func (a *applicationAuthenticationDaoImpl) Delete(id *int64) (*m.ApplicationAuthentication, error) {
 	var applicationAuthentication m.ApplicationAuthentication
 
-	result := DB.
-		Debug().
+	result := a.db().
 		Clauses(clause.Returning{}).
 		Where("id = ?", id).
 		Where("tenant_id = ?", a.TenantID).
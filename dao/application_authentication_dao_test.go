@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
@@ -16,7 +17,7 @@ func TestDeleteApplicationAuthentication(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("delete")
 
-	applicationAuthenticationDao := GetApplicationAuthenticationDao(&fixtures.TestSourceData[0].TenantID)
+	applicationAuthenticationDao := GetApplicationAuthenticationDao(context.Background(), &fixtures.TestSourceData[0].TenantID)
 
 	applicationAuthentication := fixtures.TestApplicationAuthenticationData[0]
 	// Set the ID to 0 to let GORM know it should insert a new applicationAuthentication and not update an existing one.
@@ -53,7 +54,7 @@ func TestDeleteApplicationAuthenticationNotExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("delete")
 
-	applicationAuthenticationDao := GetApplicationAuthenticationDao(&fixtures.TestSourceData[0].TenantID)
+	applicationAuthenticationDao := GetApplicationAuthenticationDao(context.Background(), &fixtures.TestSourceData[0].TenantID)
 
 	nonExistentId := int64(12345)
 	_, err := applicationAuthenticationDao.Delete(&nonExistentId)
@@ -73,9 +74,9 @@ func TestApplicationAuthenticationsByApplicationsDatabase(t *testing.T) {
 	SwitchSchema("appauthfind")
 
 	// Get all the DAOs we are going to work with.
-	authDao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
-	appDao := GetApplicationDao(&fixtures.TestTenantData[0].Id)
-	appAuthDao := GetApplicationAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	authDao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
+	appDao := GetApplicationDao(context.Background(), &fixtures.TestTenantData[0].Id)
+	appAuthDao := GetApplicationAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	// Maximum of resources to create.
 	maxCreatedResources := 5
@@ -170,8 +171,8 @@ func TestApplicationAuthenticationsByAuthenticationsDatabase(t *testing.T) {
 	SwitchSchema("appauthfind")
 
 	// Get all the DAOs we are going to work with.
-	authDao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
-	appAuthDao := GetApplicationAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	authDao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
+	appAuthDao := GetApplicationAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	// Maximum of authentications to create.
 	maxCreatedAuths := 5
@@ -252,7 +253,7 @@ func TestApplicationAuthenticationListOffsetAndLimit(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("offset_limit")
 
-	appAuthDao := GetApplicationAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	appAuthDao := GetApplicationAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	wantCount := int64(len(fixtures.TestApplicationAuthenticationData))
 
 	for _, d := range fixtures.TestDataOffsetLimit {
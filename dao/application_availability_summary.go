@@ -0,0 +1,94 @@
+package dao
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// availabilitySummaryCacheTtl is the amount of time an availability summary is kept in the cache for a tenant.
+const availabilitySummaryCacheTtl = 60 * time.Second
+
+// availabilitySummaryCache holds the computed availability summaries, keyed by tenant ID.
+var availabilitySummaryCache sync.Map
+
+// applicationAvailabilityPercent exports the percentage of available applications of the tenants whose summary has
+// been computed.
+var applicationAvailabilityPercent = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "sources_application_availability_percent",
+	Help: "Percentage of the tenant's applications which are available",
+}, []string{"tenant_id"})
+
+// cachedAvailabilitySummary is the cache entry for a tenant's availability summary.
+type cachedAvailabilitySummary struct {
+	summary   *m.AppAvailabilitySummary
+	expiresAt time.Time
+}
+
+// applicationStatusCount is the row returned by the availability summary query.
+type applicationStatusCount struct {
+	AvailabilityStatus string
+	Count              int
+}
+
+func (a *applicationDaoImpl) AvailabilitySummary() (*m.AppAvailabilitySummary, error) {
+	if cached, ok := availabilitySummaryCache.Load(*a.TenantID); ok {
+		entry := cached.(cachedAvailabilitySummary)
+		if time.Now().Before(entry.expiresAt) {
+			return entry.summary, nil
+		}
+	}
+
+	var statusCounts []applicationStatusCount
+	err := a.db().
+		Model(&m.Application{}).
+		Select(`availability_status, COUNT(*) AS count`).
+		Where(`tenant_id = ?`, a.TenantID).
+		Group(`availability_status`).
+		Scan(&statusCounts).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarizeApplicationStatuses(statusCounts)
+
+	availabilitySummaryCache.Store(*a.TenantID, cachedAvailabilitySummary{
+		summary:   summary,
+		expiresAt: time.Now().Add(availabilitySummaryCacheTtl),
+	})
+	applicationAvailabilityPercent.WithLabelValues(strconv.FormatInt(*a.TenantID, 10)).Set(summary.PercentAvailable)
+
+	return summary, nil
+}
+
+// summarizeApplicationStatuses builds the availability summary from the per status counts. Any status which is not
+// "available", "unavailable" or "partially_available" is considered as "unknown".
+func summarizeApplicationStatuses(statusCounts []applicationStatusCount) *m.AppAvailabilitySummary {
+	var summary m.AppAvailabilitySummary
+	for _, statusCount := range statusCounts {
+		switch statusCount.AvailabilityStatus {
+		case m.Available:
+			summary.Available += statusCount.Count
+		case m.Unavailable:
+			summary.Unavailable += statusCount.Count
+		case m.PartiallyAvailable:
+			summary.PartiallyAvailable += statusCount.Count
+		default:
+			summary.Unknown += statusCount.Count
+		}
+
+		summary.Total += statusCount.Count
+	}
+
+	if summary.Total > 0 {
+		summary.PercentAvailable = float64(summary.Available) / float64(summary.Total) * 100
+	}
+
+	return &summary
+}
@@ -0,0 +1,44 @@
+package dao
+
+import (
+	"testing"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// TestSummarizeApplicationStatuses tests that the statuses are properly summed up, that any unrecognized status is
+// counted as "unknown" and that the percentage of available applications is properly computed.
+func TestSummarizeApplicationStatuses(t *testing.T) {
+	statusCounts := []applicationStatusCount{
+		{AvailabilityStatus: m.Available, Count: 6},
+		{AvailabilityStatus: m.Unavailable, Count: 2},
+		{AvailabilityStatus: m.PartiallyAvailable, Count: 1},
+		{AvailabilityStatus: m.InProgress, Count: 2},
+		{AvailabilityStatus: "", Count: 1},
+	}
+
+	got := summarizeApplicationStatuses(statusCounts)
+
+	want := m.AppAvailabilitySummary{
+		Total:              12,
+		Available:          6,
+		Unavailable:        2,
+		Unknown:            3,
+		PartiallyAvailable: 1,
+		PercentAvailable:   50,
+	}
+
+	if want != *got {
+		t.Errorf(`unexpected summary. Want "%+v", got "%+v"`, want, *got)
+	}
+}
+
+// TestSummarizeApplicationStatusesNoApplications tests that no division by zero happens when the tenant doesn't have
+// any applications.
+func TestSummarizeApplicationStatusesNoApplications(t *testing.T) {
+	got := summarizeApplicationStatuses(nil)
+
+	if *got != (m.AppAvailabilitySummary{}) {
+		t.Errorf(`want an empty summary, got "%+v"`, *got)
+	}
+}
@@ -1,10 +1,13 @@
 package dao
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"gorm.io/gorm"
@@ -13,12 +16,13 @@ import (
 
 // GetApplicationDao is a function definition that can be replaced in runtime in case some other DAO
 // provider is needed.
-var GetApplicationDao func(*int64) ApplicationDao
+var GetApplicationDao func(context.Context, *int64) ApplicationDao
 
 // getDefaultApplicationAuthenticationDao gets the default DAO implementation which will have the given tenant ID.
-func getDefaultApplicationDao(tenantId *int64) ApplicationDao {
+func getDefaultApplicationDao(ctx context.Context, tenantId *int64) ApplicationDao {
 	return &applicationDaoImpl{
-		TenantID: tenantId,
+		requestContext: requestContext{ctx: ctx},
+		TenantID:       tenantId,
 	}
 }
 
@@ -29,16 +33,17 @@ func init() {
 
 type applicationDaoImpl struct {
 	TenantID *int64
+	requestContext
 }
 
 func (a *applicationDaoImpl) SubCollectionList(primaryCollection interface{}, limit int, offset int, filters []util.Filter) ([]m.Application, int64, error) {
 	applications := make([]m.Application, 0, limit)
-	relationObject, err := m.NewRelationObject(primaryCollection, *a.TenantID, DB.Debug())
+	relationObject, err := m.NewRelationObject(primaryCollection, *a.TenantID, a.db())
 	if err != nil {
 		return nil, 0, util.NewErrNotFound("source")
 	}
 
-	query := relationObject.HasMany(&m.Application{}, DB.Debug())
+	query := relationObject.HasMany(&m.Application{}, a.db())
 	query = query.Where("applications.tenant_id = ?", a.TenantID)
 
 	query, err = applyFilters(query, filters)
@@ -58,7 +63,7 @@ func (a *applicationDaoImpl) SubCollectionList(primaryCollection interface{}, li
 
 func (a *applicationDaoImpl) List(limit int, offset int, filters []util.Filter) ([]m.Application, int64, error) {
 	applications := make([]m.Application, 0, limit)
-	query := DB.Debug().
+	query := a.db().
 		Model(&m.Application{}).
 		Where("applications.tenant_id = ?", a.TenantID)
 
@@ -80,9 +85,43 @@ func (a *applicationDaoImpl) List(limit int, offset int, filters []util.Filter)
 	return applications, count, nil
 }
 
+func (a *applicationDaoImpl) ListWithSourceType(ctx context.Context, tenantId int64, limit, offset int, filters []util.Filter) ([]m.ApplicationWithSourceType, int64, error) {
+	query := a.db().
+		WithContext(ctx).
+		Model(&m.Application{}).
+		Joins(`INNER JOIN "sources" ON "sources"."id" = "applications"."source_id"`).
+		Joins(`INNER JOIN "source_types" ON "source_types"."id" = "sources"."source_type_id"`).
+		Where("applications.tenant_id = ?", tenantId)
+
+	query, err := applyFilters(query, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	count := int64(0)
+	err = query.Count(&count).Error
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	applications := make([]m.ApplicationWithSourceType, 0, limit)
+	err = query.
+		Select(`"applications".*, "source_types"."name" AS "source_type_name"`).
+		Order("applications.id").
+		Limit(limit).
+		Offset(offset).
+		Scan(&applications).
+		Error
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	return applications, count, nil
+}
+
 func (a *applicationDaoImpl) GetById(id *int64) (*m.Application, error) {
 	app := &m.Application{ID: *id}
-	result := DB.Debug().
+	result := a.db().
 		Where("tenant_id = ?", a.TenantID).
 		First(&app)
 	if result.Error != nil {
@@ -95,7 +134,7 @@ func (a *applicationDaoImpl) GetById(id *int64) (*m.Application, error) {
 // Function that searches for an application and preloads any specified relations
 func (a *applicationDaoImpl) GetByIdWithPreload(id *int64, preloads ...string) (*m.Application, error) {
 	app := &m.Application{ID: *id}
-	q := DB.Where("tenant_id = ?", a.TenantID)
+	q := a.db().Where("tenant_id = ?", a.TenantID)
 
 	for _, preload := range preloads {
 		q = q.Preload(preload)
@@ -111,21 +150,20 @@ func (a *applicationDaoImpl) GetByIdWithPreload(id *int64, preloads ...string) (
 
 func (a *applicationDaoImpl) Create(app *m.Application) error {
 	app.TenantID = *a.TenantID
-	result := DB.Debug().Create(app)
+	result := a.db().Create(app)
 
 	return result.Error
 }
 
 func (a *applicationDaoImpl) Update(app *m.Application) error {
-	result := DB.Debug().Updates(app)
+	result := a.db().Updates(app)
 	return result.Error
 }
 
 func (a *applicationDaoImpl) Delete(id *int64) (*m.Application, error) {
 	var application m.Application
 
-	result := DB.
-		Debug().
+	result := a.db().
 		Clauses(clause.Returning{}).
 		Where("id = ?", id).
 		Where("tenant_id = ?", a.TenantID).
@@ -149,7 +187,7 @@ func (a *applicationDaoImpl) Tenant() *int64 {
 func (a *applicationDaoImpl) IsSuperkey(id int64) bool {
 	var valid bool
 
-	result := DB.Model(&m.Application{}).
+	result := a.db().Model(&m.Application{}).
 		Select(`"Source".app_creation_workflow = ?`, m.AccountAuth).
 		Where("applications.id = ?", id).
 		Where("applications.tenant_id = ?", a.TenantID).
@@ -165,7 +203,7 @@ func (a *applicationDaoImpl) IsSuperkey(id int64) bool {
 
 func (a *applicationDaoImpl) BulkMessage(resource util.Resource) (map[string]interface{}, error) {
 	application := &m.Application{ID: resource.ResourceID}
-	result := DB.Debug().Preload("Source").Find(&application)
+	result := a.db().Preload("Source").Find(&application)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -179,7 +217,7 @@ func (a *applicationDaoImpl) BulkMessage(resource util.Resource) (map[string]int
 }
 
 func (a *applicationDaoImpl) FetchAndUpdateBy(resource util.Resource, updateAttributes map[string]interface{}) (interface{}, error) {
-	result := DB.Debug().Model(&m.Application{ID: resource.ResourceID}).Updates(updateAttributes)
+	result := a.db().Model(&m.Application{ID: resource.ResourceID}).Updates(updateAttributes)
 	if result.RowsAffected == 0 {
 		return nil, fmt.Errorf("application not found %v", resource)
 	}
@@ -195,7 +233,7 @@ func (a *applicationDaoImpl) FetchAndUpdateBy(resource util.Resource, updateAttr
 
 func (a *applicationDaoImpl) FindWithTenant(id *int64) (*m.Application, error) {
 	app := &m.Application{ID: *id}
-	result := DB.Debug().Preload("Tenant").Find(&app)
+	result := a.db().Preload("Tenant").Find(&app)
 
 	return app, result.Error
 }
@@ -208,7 +246,7 @@ func (a *applicationDaoImpl) ToEventJSON(resource util.Resource) ([]byte, error)
 }
 
 func (a *applicationDaoImpl) Pause(id int64) error {
-	err := DB.Debug().
+	err := a.db().
 		Model(&m.Application{}).
 		Where("id = ?", id).
 		Where("tenant_id = ?", a.TenantID).
@@ -219,7 +257,7 @@ func (a *applicationDaoImpl) Pause(id int64) error {
 }
 
 func (a *applicationDaoImpl) Unpause(id int64) error {
-	err := DB.Debug().
+	err := a.db().
 		Model(&m.Application{}).
 		Where("id = ?", id).
 		Where("tenant_id = ?", a.TenantID).
@@ -229,6 +267,38 @@ func (a *applicationDaoImpl) Unpause(id int64) error {
 	return err
 }
 
+func (a *applicationDaoImpl) PauseBySource(sourceId int64, tenantId int64) error {
+	// Only the applications which get paused here are marked, so that the ones which were already paused are left
+	// alone when resuming the source.
+	err := a.db().
+		Model(&m.Application{}).
+		Where("source_id = ?", sourceId).
+		Where("tenant_id = ?", tenantId).
+		Where("paused_at IS NULL").
+		Updates(map[string]interface{}{
+			"paused_at":             gorm.Expr("NOW()"),
+			"paused_before_archive": false,
+		}).
+		Error
+
+	return err
+}
+
+func (a *applicationDaoImpl) ResumeBySource(sourceId int64, tenantId int64) error {
+	err := a.db().
+		Model(&m.Application{}).
+		Where("source_id = ?", sourceId).
+		Where("tenant_id = ?", tenantId).
+		Where("paused_before_archive = ?", false).
+		Updates(map[string]interface{}{
+			"paused_at":             nil,
+			"paused_before_archive": nil,
+		}).
+		Error
+
+	return err
+}
+
 func (a *applicationDaoImpl) DeleteCascade(applicationId int64) ([]m.ApplicationAuthentication, *m.Application, error) {
 	var applicationAuthentications []m.ApplicationAuthentication
 	var application *m.Application
@@ -240,49 +310,47 @@ func (a *applicationDaoImpl) DeleteCascade(applicationId int64) ([]m.Application
 	// The "len(objects) != 0" check to delete the resources is necessary to avoid Gorm issuing the "cannot batch
 	// delete without a where condition" error, since there might be times when the applications don't have any related
 	// application authentications.
-	err := DB.
-		Debug().
-		Transaction(func(tx *gorm.DB) error {
-			// Fetch and delete the application authentications.
-			err := tx.
-				Model(m.ApplicationAuthentication{}).
-				Preload("Tenant").
-				Where("application_id = ?", applicationId).
-				Where("tenant_id = ?", a.TenantID).
-				Find(&applicationAuthentications).
+	err := transaction(a.db(), func(tx *gorm.DB) error {
+		// Fetch and delete the application authentications.
+		err := tx.
+			Model(m.ApplicationAuthentication{}).
+			Preload("Tenant").
+			Where("application_id = ?", applicationId).
+			Where("tenant_id = ?", a.TenantID).
+			Find(&applicationAuthentications).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		if len(applicationAuthentications) != 0 {
+			err = tx.
+				Delete(&applicationAuthentications).
 				Error
 
 			if err != nil {
 				return err
 			}
-
-			if len(applicationAuthentications) != 0 {
-				err = tx.
-					Delete(&applicationAuthentications).
-					Error
-
-				if err != nil {
-					return err
-				}
-			}
-
-			// Fetch and delete the application itself.
+		}
+
+		// Fetch and delete the application itself.
+		err = tx.
+			Model(m.Application{}).
+			Preload("Tenant").
+			Where("id = ?", applicationId).
+			Where("tenant_id = ?", a.TenantID).
+			Find(&application).
+			Error
+
+		if application != nil {
 			err = tx.
-				Model(m.Application{}).
-				Preload("Tenant").
-				Where("id = ?", applicationId).
-				Where("tenant_id = ?", a.TenantID).
-				Find(&application).
+				Delete(&application).
 				Error
+		}
 
-			if application != nil {
-				err = tx.
-					Delete(&application).
-					Error
-			}
-
-			return err
-		})
+		return err
+	})
 
 	if err != nil {
 		return nil, nil, err
@@ -294,7 +362,7 @@ func (a *applicationDaoImpl) DeleteCascade(applicationId int64) ([]m.Application
 func (a *applicationDaoImpl) Exists(applicationId int64) (bool, error) {
 	var applicationExists bool
 
-	err := DB.Model(&m.Application{}).
+	err := a.db().Model(&m.Application{}).
 		Select("1").
 		Where("id = ?", applicationId).
 		Where("tenant_id = ?", a.TenantID).
@@ -307,3 +375,146 @@ func (a *applicationDaoImpl) Exists(applicationId int64) (bool, error) {
 
 	return applicationExists, nil
 }
+
+// validatedAuthenticationsLimit is the number of the application's authentications which are considered when looking
+// for the credentials to validate.
+const validatedAuthenticationsLimit = 100
+
+func (a *applicationDaoImpl) ValidateCredentials(ctx context.Context, appId int64, tenantId int64) (bool, string, error) {
+	var application m.Application
+	err := a.db().
+		Preload("ApplicationType").
+		Preload("Source.SourceType").
+		Where("id = ?", appId).
+		Where("tenant_id = ?", tenantId).
+		First(&application).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, "", util.NewErrNotFound("application")
+	}
+
+	if err != nil {
+		return false, "", err
+	}
+
+	validator, ok := CredentialValidators.Get(application.ApplicationTypeID)
+	if !ok {
+		return false, "", util.NewErrBadRequest("the credentials of this application type cannot be validated")
+	}
+
+	authTypes, err := supportedAuthTypes(&application.ApplicationType, application.Source.SourceType.Name)
+	if err != nil {
+		return false, "", err
+	}
+
+	authDao := GetAuthenticationDao(ctx, &tenantId)
+	authentications, _, err := authDao.ListForApplication(appId, validatedAuthenticationsLimit, 0, nil)
+	if err != nil {
+		return false, "", err
+	}
+
+	for i := range authentications {
+		if util.SliceContainsString(authTypes, authentications[i].AuthType) {
+			return validator.Validate(ctx, &authentications[i])
+		}
+	}
+
+	return false, "", util.NewErrNotFound("authentication")
+}
+
+// supportedAuthTypes returns the authentication types the application type supports for the given source type.
+func supportedAuthTypes(applicationType *m.ApplicationType, sourceTypeName string) ([]string, error) {
+	if len(applicationType.SupportedAuthenticationTypes) == 0 {
+		return []string{}, nil
+	}
+
+	var authTypes map[string][]string
+	err := json.Unmarshal(applicationType.SupportedAuthenticationTypes, &authTypes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse the supported authentication types of the application type %q: %w", applicationType.Name, err)
+	}
+
+	return authTypes[sourceTypeName], nil
+}
+
+// applicationHasAuthType is the condition which keeps the applications that have at least one authentication of the
+// given type, either linked through the "application_authentications" table or directly attached to the application.
+// The "EXISTS" subquery prevents the applications with multiple matching authentications from showing up more than
+// once.
+const applicationHasAuthType = `EXISTS (SELECT 1 FROM "authentications" WHERE "authentications"."authtype" = ? AND "authentications"."tenant_id" = ? AND (("authentications"."resource_type" = 'Application' AND "authentications"."resource_id" = "applications"."id") OR "authentications"."id" IN (SELECT "application_authentications"."authentication_id" FROM "application_authentications" WHERE "application_authentications"."application_id" = "applications"."id")))`
+
+func (a *applicationDaoImpl) ListByAuthType(ctx context.Context, authType string, tenantId int64, limit, offset int) ([]m.Application, int64, error) {
+	if !util.SliceContainsString(config.Get().KnownAuthTypes, authType) {
+		return nil, 0, util.NewErrBadRequest(fmt.Sprintf("unknown authentication type %q", authType))
+	}
+
+	if config.IsVaultOn() {
+		return nil, 0, util.NewErrBadRequest("the applications cannot be filtered by their authentication type when the authentications are stored in Vault")
+	}
+
+	query := a.db().
+		WithContext(ctx).
+		Model(&m.Application{}).
+		Where("applications.tenant_id = ?", tenantId).
+		Where(applicationHasAuthType, authType, tenantId)
+
+	count := int64(0)
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	applications := make([]m.Application, 0, limit)
+	err = query.
+		Order("applications.id").
+		Limit(limit).
+		Offset(offset).
+		Find(&applications).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return applications, count, nil
+}
+
+// availabilityStatusSeverities ranks the availability statuses from the least to the most severe. The statuses which
+// aren't listed are considered unknown.
+var availabilityStatusSeverities = map[string]int{
+	m.Available:          0,
+	m.Unknown:            1,
+	m.PartiallyAvailable: 2,
+	m.Unavailable:        3,
+}
+
+// worstAvailabilityStatus returns the most severe of the given statuses, or an empty string if none are given.
+func worstAvailabilityStatus(statuses []string) string {
+	worst := ""
+	for _, status := range statuses {
+		if _, ok := availabilityStatusSeverities[status]; !ok {
+			status = m.Unknown
+		}
+
+		if worst == "" || availabilityStatusSeverities[status] > availabilityStatusSeverities[worst] {
+			worst = status
+		}
+	}
+
+	return worst
+}
+
+func (a *applicationDaoImpl) GetApplicationStatusRollup(sourceId, tenantId int64) (string, error) {
+	var statuses []string
+	err := a.db().
+		Model(&m.Application{}).
+		Where("source_id = ?", sourceId).
+		Where("tenant_id = ?", tenantId).
+		Pluck("COALESCE(availability_status, '')", &statuses).
+		Error
+	if err != nil {
+		return "", err
+	}
+
+	return worstAvailabilityStatus(statuses), nil
+}
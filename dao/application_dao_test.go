@@ -2,6 +2,7 @@ package dao
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -22,7 +23,7 @@ func TestPausingApplication(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("pause_unpause")
 
-	applicationDao := GetApplicationDao(&fixtures.TestSourceData[0].TenantID)
+	applicationDao := GetApplicationDao(context.Background(), &fixtures.TestSourceData[0].TenantID)
 	err := applicationDao.Pause(testApplication.ID)
 	if err != nil {
 		t.Errorf(`want nil error, got "%s"`, err)
@@ -46,7 +47,7 @@ func TestResumeApplication(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("pause_unpause")
 
-	applicationDao := GetApplicationDao(&testApplication.TenantID)
+	applicationDao := GetApplicationDao(context.Background(), &testApplication.TenantID)
 	err := applicationDao.Unpause(testApplication.ID)
 
 	if err != nil {
@@ -71,7 +72,7 @@ func TestDeleteApplication(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("delete")
 
-	applicationDao := GetApplicationDao(&fixtures.TestSourceData[0].TenantID)
+	applicationDao := GetApplicationDao(context.Background(), &fixtures.TestSourceData[0].TenantID)
 
 	application := fixtures.TestApplicationData[0]
 	// Set the ID to 0 to let GORM know it should insert a new application and not update an existing one.
@@ -117,7 +118,7 @@ func TestDeleteApplicationNotExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("delete")
 
-	applicationDao := GetApplicationDao(&fixtures.TestSourceData[0].TenantID)
+	applicationDao := GetApplicationDao(context.Background(), &fixtures.TestSourceData[0].TenantID)
 
 	nonExistentId := int64(12345)
 	_, err := applicationDao.Delete(&nonExistentId)
@@ -134,7 +135,7 @@ func TestApplicationDeleteCascade(t *testing.T) {
 	SwitchSchema("delete")
 
 	// Create a new application on the database to cleanly test the function under test.
-	applicationDao := GetApplicationDao(&fixtures.TestTenantData[0].Id)
+	applicationDao := GetApplicationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	fixtureApp := m.Application{
 		ApplicationTypeID: fixtures.TestApplicationTypeData[0].Id,
 		SourceID:          fixtures.TestSourceData[0].ID,
@@ -148,8 +149,8 @@ func TestApplicationDeleteCascade(t *testing.T) {
 
 	// Create the authentications and the application authentications. The former are needed to avoid the foreign key
 	// constraints.
-	authenticationDao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
-	applicationAuthenticationDao := GetApplicationAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	authenticationDao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
+	applicationAuthenticationDao := GetApplicationAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	// Set the maximum amount of authentications we will create.
 	maxAuthenticationsCreated := 5
@@ -191,7 +192,6 @@ func TestApplicationDeleteCascade(t *testing.T) {
 	// Count the application authentications from the given application, to check that they were deleted.
 	var appAuthCount int64
 	err = DB.
-		Debug().
 		Model(m.ApplicationAuthentication{}).
 		Where("application_id = ?", fixtureApp.ID).
 		Where("tenant_id = ?", fixtures.TestTenantData[0].Id).
@@ -236,7 +236,6 @@ func TestApplicationDeleteCascade(t *testing.T) {
 	// Try to fetch the deleted application.
 	var deletedApplicationCheck *m.Application
 	err = DB.
-		Debug().
 		Model(m.Application{}).
 		Where(`id = ?`, fixtureApp.ID).
 		Where(`tenant_id = ?`, fixtures.TestTenantData[0].Id).
@@ -287,7 +286,7 @@ func TestApplicationExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("exists")
 
-	applicationDao := GetApplicationDao(&fixtures.TestTenantData[0].Id)
+	applicationDao := GetApplicationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	got, err := applicationDao.Exists(fixtures.TestApplicationData[0].ID)
 	if err != nil {
@@ -306,7 +305,7 @@ func TestApplicationNotExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("exists")
 
-	applicationDao := GetApplicationDao(&fixtures.TestTenantData[0].Id)
+	applicationDao := GetApplicationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	got, err := applicationDao.Exists(12345)
 	if err != nil {
@@ -326,7 +325,7 @@ func TestApplicationSubcollectionListWithOffsetAndLimit(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("offset_limit")
 
-	applicationDao := GetApplicationDao(&fixtures.TestTenantData[0].Id)
+	applicationDao := GetApplicationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	sourceId := fixtures.TestSourceData[0].ID
 
 	var wantCount int64
@@ -368,7 +367,7 @@ func TestApplicationListOffsetAndLimit(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("offset_limit")
 
-	applicationDao := GetApplicationDao(&fixtures.TestTenantData[0].Id)
+	applicationDao := GetApplicationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	wantCount := int64(len(fixtures.TestApplicationData))
 
 	for _, d := range fixtures.TestDataOffsetLimit {
@@ -396,3 +395,365 @@ func TestApplicationListOffsetAndLimit(t *testing.T) {
 	}
 	DropSchema("offset_limit")
 }
+
+// TestValidateCredentialsSupportedAuthType tests that the validated credentials are the ones whose type the
+// application type supports, and that the applications of other tenants are not found.
+func TestValidateCredentialsSupportedAuthType(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("validate_credentials")
+
+	CredentialValidators.Register(testApplication.ApplicationTypeID, arnCredentialValidator{})
+	defer func() { CredentialValidators = NewCredentialValidatorRegistry() }()
+
+	err := DB.
+		Model(&m.ApplicationType{}).
+		Where("id = ?", testApplication.ApplicationTypeID).
+		Update("supported_authentication_types", `{"amazon": ["arn"]}`).
+		Error
+	if err != nil {
+		t.Fatalf(`could not set the supported authentication types: %s`, err)
+	}
+
+	// The fixtures already attach an authentication without a type and with an invalid ARN to the application.
+	authentication := m.Authentication{
+		AuthType:     "arn",
+		Username:     util.StringRef("arn:aws:iam::123456789012:role/CostManagement"),
+		ResourceType: "Application",
+		ResourceID:   testApplication.ID,
+		SourceID:     testApplication.SourceID,
+		TenantID:     testApplication.TenantID,
+	}
+	err = DB.Create(&authentication).Error
+	if err != nil {
+		t.Fatalf(`could not create the authentication: %s`, err)
+	}
+
+	tenantId := testApplication.TenantID
+	valid, reason, err := GetApplicationDao(context.Background(), &tenantId).ValidateCredentials(context.Background(), testApplication.ID, tenantId)
+	if err != nil {
+		t.Fatalf(`want no errors, got "%s"`, err)
+	}
+
+	if !valid {
+		t.Errorf(`want the "arn" credentials validated, got them invalid because "%s"`, reason)
+	}
+
+	otherTenantId := fixtures.TestTenantData[1].Id
+	_, _, err = GetApplicationDao(context.Background(), &otherTenantId).ValidateCredentials(context.Background(), testApplication.ID, otherTenantId)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error for another tenant's application, got "%v"`, err)
+	}
+
+	DropSchema("validate_credentials")
+}
+
+// TestListByAuthType tests that the applications which have authentications of the given type are listed once, even
+// when they have more than one matching authentication.
+func TestListByAuthType(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	testutils.SkipIfNotSecretStoreDatabase(t)
+	SwitchSchema("application_list_by_auth_type")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	auth := fixtures.TestAuthenticationData[0]
+
+	// The authentication is both attached to the application and linked to it through an application
+	// authentication.
+	err := DB.
+		Model(&m.Authentication{}).
+		Where("id = ?", auth.DbID).
+		Update("authtype", "arn").
+		Error
+	if err != nil {
+		t.Fatalf(`could not update the authentication: %s`, err)
+	}
+
+	secondAuth := m.Authentication{
+		AuthType:     "arn",
+		TenantID:     tenantId,
+		SourceID:     auth.SourceID,
+		ResourceType: "Application",
+		ResourceID:   auth.ResourceID,
+	}
+	err = DB.Create(&secondAuth).Error
+	if err != nil {
+		t.Fatalf(`could not create the authentication: %s`, err)
+	}
+
+	applicationDao := GetApplicationDao(context.Background(), &tenantId)
+	applications, count, err := applicationDao.ListByAuthType(context.Background(), "arn", tenantId, 100, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 1 || len(applications) != 1 {
+		t.Fatalf(`want one application, got "%d" with a count of "%d"`, len(applications), count)
+	}
+
+	if applications[0].ID != auth.ResourceID {
+		t.Errorf(`want application "%d", got "%d"`, auth.ResourceID, applications[0].ID)
+	}
+
+	// No applications have "token" authentications.
+	applications, count, err = applicationDao.ListByAuthType(context.Background(), "token", tenantId, 100, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 0 || len(applications) != 0 {
+		t.Errorf(`want no applications, got "%d" with a count of "%d"`, len(applications), count)
+	}
+
+	// Other tenants' applications are not listed.
+	otherTenant := tenantId + 12345
+	applications, _, err = GetApplicationDao(context.Background(), &otherTenant).ListByAuthType(context.Background(), "arn", otherTenant, 100, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(applications) != 0 {
+		t.Errorf(`want no applications for another tenant, got "%d"`, len(applications))
+	}
+
+	DropSchema("application_list_by_auth_type")
+}
+
+// TestListByAuthTypeUnknown tests that unknown authentication types are rejected.
+func TestListByAuthTypeUnknown(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("application_list_by_auth_type")
+
+	tenantId := fixtures.TestTenantData[0].Id
+
+	_, _, err := GetApplicationDao(context.Background(), &tenantId).ListByAuthType(context.Background(), "not-a-real-type", tenantId, 100, 0)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	DropSchema("application_list_by_auth_type")
+}
+
+// TestListWithSourceType tests that the applications are listed along with their sources' type name, and that the
+// filters still apply to the applications.
+func TestListWithSourceType(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("application_list_with_source_type")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	application := fixtures.TestApplicationData[0]
+
+	var wantSourceTypeName string
+	for _, source := range fixtures.TestSourceData {
+		for _, sourceType := range fixtures.TestSourceTypeData {
+			if source.ID == application.SourceID && sourceType.Id == source.SourceTypeID {
+				wantSourceTypeName = sourceType.Name
+			}
+		}
+	}
+
+	filters := []util.Filter{{Name: "id", Value: []string{"1"}}}
+
+	applications, count, err := GetApplicationDao(context.Background(), &tenantId).ListWithSourceType(context.Background(), tenantId, 100, 0, filters)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 1 || len(applications) != 1 {
+		t.Fatalf(`want one application, got "%d" with a count of "%d"`, len(applications), count)
+	}
+
+	if applications[0].ID != application.ID || applications[0].SourceID != application.SourceID {
+		t.Errorf(`want application "%d" of source "%d", got "%+v"`, application.ID, application.SourceID, applications[0].Application)
+	}
+
+	if applications[0].SourceTypeName != wantSourceTypeName {
+		t.Errorf(`want source type name "%s", got "%s"`, wantSourceTypeName, applications[0].SourceTypeName)
+	}
+
+	DropSchema("application_list_with_source_type")
+}
+
+// sourceApplications returns the source's applications.
+func sourceApplications(t *testing.T, sourceId int64) []m.Application {
+	var applications []m.Application
+	err := DB.
+		Where("source_id = ?", sourceId).
+		Find(&applications).
+		Error
+
+	if err != nil {
+		t.Fatalf(`could not fetch the applications: %s`, err)
+	}
+
+	return applications
+}
+
+// pausedApplications returns whether each of the source's applications is paused, keyed by application ID.
+func pausedApplications(t *testing.T, sourceId int64) map[int64]bool {
+	applications := sourceApplications(t, sourceId)
+
+	paused := make(map[int64]bool, len(applications))
+	for _, application := range applications {
+		paused[application.ID] = application.PausedAt != nil
+	}
+
+	return paused
+}
+
+// TestPauseAndResumeBySource tests that all the source's applications get paused, and that resuming them leaves
+// paused the ones which had been paused on their own.
+func TestPauseAndResumeBySource(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("application_pause_by_source")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	sourceId := fixtures.TestSourceData[0].ID
+	applicationDao := GetApplicationDao(context.Background(), &tenantId)
+
+	// The first application of the source gets paused on its own.
+	var independentlyPausedId int64
+	for _, application := range fixtures.TestApplicationData {
+		if application.SourceID == sourceId {
+			independentlyPausedId = application.ID
+			break
+		}
+	}
+
+	err := applicationDao.Pause(independentlyPausedId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	// Pausing the source twice must not mark the applications it already paused as independently paused.
+	for i := 0; i < 2; i++ {
+		err = applicationDao.PauseBySource(sourceId, tenantId)
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+	}
+
+	for _, application := range sourceApplications(t, sourceId) {
+		if application.PausedAt == nil {
+			t.Errorf(`want application "%d" to be paused, got it running`, application.ID)
+		}
+
+		if application.ID == independentlyPausedId && application.PausedBeforeArchive != nil {
+			t.Errorf(`want the independently paused application "%d" to be left unmarked, got "%t"`, application.ID, *application.PausedBeforeArchive)
+		}
+
+		if application.ID != independentlyPausedId && (application.PausedBeforeArchive == nil || *application.PausedBeforeArchive) {
+			t.Errorf(`want application "%d" to be marked as paused by the source, got "%v"`, application.ID, application.PausedBeforeArchive)
+		}
+	}
+
+	err = applicationDao.ResumeBySource(sourceId, tenantId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	for _, application := range sourceApplications(t, sourceId) {
+		paused := application.PausedAt != nil
+
+		if application.ID == independentlyPausedId && !paused {
+			t.Errorf(`want the independently paused application "%d" to stay paused, got it running`, application.ID)
+		}
+
+		if application.ID != independentlyPausedId && paused {
+			t.Errorf(`want application "%d" to be resumed, got it paused`, application.ID)
+		}
+
+		if application.PausedBeforeArchive != nil {
+			t.Errorf(`want the mark of application "%d" to be cleared, got "%t"`, application.ID, *application.PausedBeforeArchive)
+		}
+	}
+
+	DropSchema("application_pause_by_source")
+}
+
+// TestPauseBySourceOtherTenant tests that the applications of other tenants' sources are left alone.
+func TestPauseBySourceOtherTenant(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("application_pause_by_source")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	sourceId := fixtures.TestSourceData[0].ID
+	otherTenant := tenantId + 12345
+
+	err := GetApplicationDao(context.Background(), &otherTenant).PauseBySource(sourceId, otherTenant)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	for id, paused := range pausedApplications(t, sourceId) {
+		if paused {
+			t.Errorf(`want application "%d" to be left alone, got it paused`, id)
+		}
+	}
+
+	DropSchema("application_pause_by_source")
+}
+
+// TestWorstAvailabilityStatus tests that the most severe status is picked, and that the unrecognized statuses count
+// as unknown.
+func TestWorstAvailabilityStatus(t *testing.T) {
+	testCases := []struct {
+		statuses []string
+		want     string
+	}{
+		{statuses: nil, want: ""},
+		{statuses: []string{m.Available, m.Available}, want: m.Available},
+		{statuses: []string{m.Available, ""}, want: m.Unknown},
+		{statuses: []string{m.InProgress, m.Available}, want: m.Unknown},
+		{statuses: []string{m.Available, m.PartiallyAvailable, ""}, want: m.PartiallyAvailable},
+		{statuses: []string{m.Unavailable, m.PartiallyAvailable, m.Available}, want: m.Unavailable},
+	}
+
+	for _, tc := range testCases {
+		if got := worstAvailabilityStatus(tc.statuses); got != tc.want {
+			t.Errorf(`want "%s" for "%v", got "%s"`, tc.want, tc.statuses, got)
+		}
+	}
+}
+
+// TestSourceGetByIdApplicationStatusRollup tests that the fetched source carries the worst status of its
+// applications.
+func TestSourceGetByIdApplicationStatusRollup(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("application_status_rollup")
+
+	source := fixtures.TestSourceData[0]
+
+	statuses := map[int64]string{1: m.Available, 3: m.PartiallyAvailable}
+	for id, status := range statuses {
+		err := DB.Model(&m.Application{}).Where("id = ?", id).Update("availability_status", status).Error
+		if err != nil {
+			t.Fatalf(`could not set up the application: %s`, err)
+		}
+	}
+
+	got, err := GetSourceDao(context.Background(), &source.TenantID).GetById(&source.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if got.ApplicationStatusRollup != m.PartiallyAvailable {
+		t.Errorf(`want rollup "%s", got "%s"`, m.PartiallyAvailable, got.ApplicationStatusRollup)
+	}
+
+	// The rollup reflects the application updates straight away.
+	err = GetApplicationDao(context.Background(), &source.TenantID).Update(&m.Application{ID: 1, AvailabilityStatus: m.Unavailable})
+	if err != nil {
+		t.Fatalf(`could not update the application: %s`, err)
+	}
+
+	got, err = GetSourceDao(context.Background(), &source.TenantID).GetById(&source.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if got.ApplicationStatusRollup != m.Unavailable {
+		t.Errorf(`want rollup "%s", got "%s"`, m.Unavailable, got.ApplicationStatusRollup)
+	}
+
+	DropSchema("application_status_rollup")
+}
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 
 	m "github.com/RedHatInsights/sources-api-go/model"
@@ -9,12 +10,13 @@ import (
 
 // GetApplicationTypeDao is a function definition that can be replaced in runtime in case some other DAO provider is
 // needed.
-var GetApplicationTypeDao func(*int64) ApplicationTypeDao
+var GetApplicationTypeDao func(context.Context, *int64) ApplicationTypeDao
 
 // getDefaultApplicationAuthenticationDao gets the default DAO implementation which will have the given tenant ID.
-func getDefaultApplicationTypeDao(tenantId *int64) ApplicationTypeDao {
+func getDefaultApplicationTypeDao(ctx context.Context, tenantId *int64) ApplicationTypeDao {
 	return &applicationTypeDaoImpl{
-		TenantID: tenantId,
+		requestContext: requestContext{ctx: ctx},
+		TenantID:       tenantId,
 	}
 }
 
@@ -25,6 +27,7 @@ func init() {
 
 type applicationTypeDaoImpl struct {
 	TenantID *int64
+	requestContext
 }
 
 func (a *applicationTypeDaoImpl) SubCollectionList(primaryCollection interface{}, limit, offset int, filters []util.Filter) ([]m.ApplicationType, int64, error) {
@@ -32,12 +35,12 @@ func (a *applicationTypeDaoImpl) SubCollectionList(primaryCollection interface{}
 	// 0, size of limit (since we will not be returning more than that)
 	applicationTypes := make([]m.ApplicationType, 0, limit)
 
-	relationObject, err := m.NewRelationObject(primaryCollection, *a.TenantID, DB.Debug())
+	relationObject, err := m.NewRelationObject(primaryCollection, *a.TenantID, a.db())
 	if err != nil {
 		return nil, 0, util.NewErrNotFound("source")
 	}
 
-	query := relationObject.HasMany(&m.ApplicationType{}, DB.Debug())
+	query := relationObject.HasMany(&m.ApplicationType{}, a.db())
 
 	query, err = applyFilters(query, filters)
 	if err != nil {
@@ -61,9 +64,19 @@ func (a *applicationTypeDaoImpl) List(limit, offset int, filters []util.Filter)
 	// allocating a slice of application types, initial length of
 	// 0, size of limit (since we will not be returning more than that)
 	appTypes := make([]m.ApplicationType, 0, limit)
-	query := DB.Debug().Model(&m.ApplicationType{})
+	query := a.db().Model(&m.ApplicationType{})
 
-	query, err := applyFilters(query, filters)
+	// the application types can be narrowed down to the ones compatible with a source type.
+	sourceTypeId, filters, err := extractIdFilter(filters, "source_type_id")
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	if sourceTypeId != nil {
+		query = query.Where(applicationTypeSupportingSourceType, *sourceTypeId)
+	}
+
+	query, err = applyFilters(query, filters)
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
@@ -83,7 +96,7 @@ func (a *applicationTypeDaoImpl) List(limit, offset int, filters []util.Filter)
 
 func (a *applicationTypeDaoImpl) GetById(id *int64) (*m.ApplicationType, error) {
 	appType := &m.ApplicationType{Id: *id}
-	result := DB.Debug().First(appType)
+	result := a.db().First(appType)
 	if result.Error != nil {
 		return nil, util.NewErrNotFound("application type")
 	}
@@ -91,9 +104,24 @@ func (a *applicationTypeDaoImpl) GetById(id *int64) (*m.ApplicationType, error)
 	return appType, nil
 }
 
+func (a *applicationTypeDaoImpl) ListCompatibleSourceTypes(appTypeId int64) ([]m.SourceType, error) {
+	sourceTypes := make([]m.SourceType, 0)
+	result := a.db().
+		Model(&m.SourceType{}).
+		Where(sourceTypeSupportedByApplicationType, appTypeId).
+		Order("source_types.id").
+		Find(&sourceTypes)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return sourceTypes, nil
+}
+
 func (a *applicationTypeDaoImpl) GetByName(name string) (*m.ApplicationType, error) {
 	apptype := &m.ApplicationType{}
-	result := DB.Debug().Where("name LIKE ?", "%"+name+"%").First(&apptype)
+	result := a.db().Where("name LIKE ?", "%"+name+"%").First(&apptype)
 
 	return apptype, result.Error
 }
@@ -114,7 +142,7 @@ func (at *applicationTypeDaoImpl) ApplicationTypeCompatibleWithSource(typeId, so
 	// Looks up the source ID and then compare's the source-type's name with the
 	// application type's supported source types
 	source := m.Source{ID: sourceId}
-	result := DB.Debug().Preload("SourceType").Find(&source)
+	result := at.db().Preload("SourceType").Find(&source)
 	if result.Error != nil {
 		return fmt.Errorf("source not found")
 	}
@@ -130,7 +158,7 @@ func (at *applicationTypeDaoImpl) ApplicationTypeCompatibleWithSourceType(appTyp
 	// datatypes.JsonQuery("application_types.supported_source_types") but that
 	// doesn't work when we're specifying something joined in, in this case
 	// "source_types.name"
-	result := DB.Debug().
+	result := at.db().
 		Select("application_types.*").
 		Joins("LEFT JOIN source_types ON source_types.id = ?", sourceTypeId).
 		Where("application_types.id = ?", appTypeId).
@@ -148,7 +176,7 @@ func (at *applicationTypeDaoImpl) GetSuperKeyResultType(applicationTypeId int64,
 	//
 	// the short story is that we're pulling the `authType` key out of the
 	// supportedAuthenticationTypes which is an array and then plucking index 0
-	result := DB.Debug().
+	result := at.db().
 		Model(&m.ApplicationType{Id: applicationTypeId}).
 		Select("application_types.supported_authentication_types::json -> ? ->> 0", authType).
 		Scan(&resultType)
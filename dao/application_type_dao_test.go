@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
@@ -15,7 +16,7 @@ func TestApplicationTypeSubCollectionListOffsetAndLimit(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("offset_limit")
 
-	appTypeDao := GetApplicationTypeDao(&fixtures.TestTenantData[0].Id)
+	appTypeDao := GetApplicationTypeDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	sourceId := int64(1)
 
 	var appTypeList []int64
@@ -58,7 +59,7 @@ func TestApplicationTypeListOffsetAndLimit(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("offset_limit")
 
-	appTypeDao := GetApplicationTypeDao(&fixtures.TestTenantData[0].Id)
+	appTypeDao := GetApplicationTypeDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	wantCount := int64(len(fixtures.TestApplicationTypeData))
 
 	for _, d := range fixtures.TestDataOffsetLimit {
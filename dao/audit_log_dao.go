@@ -0,0 +1,105 @@
+package dao
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm/clause"
+)
+
+// GetAuditLogDao is a function definition that can be replaced in runtime in case some other DAO provider is needed.
+var GetAuditLogDao func(context.Context) AuditLogDao
+
+// getDefaultAuditLogDao gets the default DAO implementation.
+func getDefaultAuditLogDao(ctx context.Context) AuditLogDao {
+	return &auditLogDaoImpl{requestContext: requestContext{ctx: ctx}}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetAuditLogDao = getDefaultAuditLogDao
+}
+
+// auditLogExportFlushEvery is the number of exported rows after which the CSV writer gets flushed, so that the export
+// reaches the client while it is being generated.
+const auditLogExportFlushEvery = 500
+
+// validateAuditLogPeriod returns a "bad request" error when the period to export is empty or inverted.
+func validateAuditLogPeriod(from, to time.Time) error {
+	if !from.Before(to) {
+		return util.NewErrBadRequest(`the "from" date must be before the "to" date`)
+	}
+
+	return nil
+}
+
+type auditLogDaoImpl struct {
+	requestContext
+}
+
+func (a *auditLogDaoImpl) Create(auditLog *m.AuditLog) error {
+	return a.db().
+		Omit(clause.Associations).
+		Create(auditLog).
+		Error
+}
+
+func (a *auditLogDaoImpl) StreamExport(tenantId int64, from, to time.Time, w io.Writer) error {
+	err := validateAuditLogPeriod(from, to)
+	if err != nil {
+		return err
+	}
+
+	rows, err := a.db().
+		Model(&m.AuditLog{}).
+		Where("tenant_id = ?", tenantId).
+		Where("created_at >= ?", from).
+		Where("created_at < ?", to).
+		Order("created_at, id").
+		Rows()
+
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	csvWriter := csv.NewWriter(w)
+	err = csvWriter.Write(m.AuditLogCsvHeader)
+	if err != nil {
+		return err
+	}
+
+	var exported int
+	for rows.Next() {
+		var auditLog m.AuditLog
+		err = a.db().ScanRows(rows, &auditLog)
+		if err != nil {
+			return fmt.Errorf("could not read the audit log: %w", err)
+		}
+
+		err = csvWriter.Write(auditLog.ToCsvRecord())
+		if err != nil {
+			return err
+		}
+
+		exported++
+		if exported%auditLogExportFlushEvery == 0 {
+			csvWriter.Flush()
+			if err = csvWriter.Error(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
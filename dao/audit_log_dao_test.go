@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestAuditLogStreamExport tests that only the tenant's audit logs of the given period are exported, in order.
+func TestAuditLogStreamExport(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("audit_log_export")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	otherTenantId := fixtures.TestTenantData[1].Id
+	from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	auditLogs := []m.AuditLog{
+		{EventType: "Source.update", ResourceType: "Source", ResourceId: "1", Actor: "jdoe", TenantId: tenantId, CreatedAt: from.Add(2 * time.Hour)},
+		{EventType: "Source.create", ResourceType: "Source", ResourceId: "1", Actor: "jdoe", TenantId: tenantId, CreatedAt: from},
+		{EventType: "Source.destroy", ResourceType: "Source", ResourceId: "1", Actor: "psk", TenantId: tenantId, CreatedAt: to},
+		{EventType: "Source.create", ResourceType: "Source", ResourceId: "2", Actor: "jdoe", TenantId: otherTenantId, CreatedAt: from},
+	}
+
+	auditLogDao := GetAuditLogDao(context.Background())
+	for i := range auditLogs {
+		err := auditLogDao.Create(&auditLogs[i])
+		if err != nil {
+			t.Fatalf(`could not create the audit log: %s`, err)
+		}
+	}
+
+	var out bytes.Buffer
+	err := auditLogDao.StreamExport(tenantId, from, to, &out)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf(`could not read the exported CSV: %s`, err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf(`want the header and two audit logs, got "%v"`, records)
+	}
+
+	if records[1][2] != "Source.create" || records[2][2] != "Source.update" {
+		t.Errorf(`want the "Source.create" and "Source.update" audit logs, got "%v"`, records[1:])
+	}
+
+	DropSchema("audit_log_export")
+}
+
+// TestAuditLogStreamExportInvalidPeriod tests that inverted periods are rejected.
+func TestAuditLogStreamExportInvalidPeriod(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("audit_log_export")
+
+	from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	var out bytes.Buffer
+	err := GetAuditLogDao(context.Background()).StreamExport(fixtures.TestTenantData[0].Id, from, from.Add(-time.Hour), &out)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	if out.Len() != 0 {
+		t.Errorf(`want nothing exported, got "%s"`, out.String())
+	}
+
+	DropSchema("audit_log_export")
+}
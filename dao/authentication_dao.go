@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,17 +26,19 @@ const vaultSecretPathFormat = "secret/data/%d/%s_%d_%s"
 
 // GetAuthenticationDao is a function definition that can be replaced in runtime in case some other DAO provider is
 // needed.
-var GetAuthenticationDao func(*int64) AuthenticationDao
+var GetAuthenticationDao func(context.Context, *int64) AuthenticationDao
 
 // getDefaultAuthenticationDao gets the default DAO implementation which will have the given tenant ID.
-func getDefaultAuthenticationDao(tenantId *int64) AuthenticationDao {
+func getDefaultAuthenticationDao(ctx context.Context, tenantId *int64) AuthenticationDao {
 	if config.IsVaultOn() {
 		return &authenticationDaoImpl{
-			TenantID: tenantId,
+			requestContext: requestContext{ctx: ctx},
+			TenantID:       tenantId,
 		}
 	} else {
 		return &authenticationDaoDbImpl{
-			TenantID: tenantId,
+			requestContext: requestContext{ctx: ctx},
+			TenantID:       tenantId,
 		}
 	}
 }
@@ -47,6 +50,7 @@ func init() {
 
 type authenticationDaoImpl struct {
 	TenantID *int64
+	requestContext
 }
 
 /*
@@ -92,7 +96,7 @@ func (a *authenticationDaoImpl) List(limit int, offset int, filters []util.Filte
 
 func (a *authenticationDaoImpl) ListForSource(sourceID int64, _, _ int, _ []util.Filter) ([]m.Authentication, int64, error) {
 	// Check if sourceID exists
-	_, err := GetSourceDao(a.TenantID).GetById(&sourceID)
+	_, err := GetSourceDao(a.ctx, a.TenantID).GetById(&sourceID)
 	if err != nil {
 		return nil, 0, util.NewErrNotFound("source")
 	}
@@ -120,7 +124,7 @@ func (a *authenticationDaoImpl) ListForSource(sourceID int64, _, _ int, _ []util
 
 func (a *authenticationDaoImpl) ListForApplication(applicationID int64, _, _ int, _ []util.Filter) ([]m.Authentication, int64, error) {
 	// checking if application exists first
-	_, err := GetApplicationDao(a.TenantID).GetById(&applicationID)
+	_, err := GetApplicationDao(a.ctx, a.TenantID).GetById(&applicationID)
 	if err != nil {
 		return nil, 0, util.NewErrNotFound("application")
 	}
@@ -148,7 +152,7 @@ func (a *authenticationDaoImpl) ListForApplication(applicationID int64, _, _ int
 
 func (a *authenticationDaoImpl) ListForApplicationAuthentication(appauthID int64, _, _ int, _ []util.Filter) ([]m.Authentication, int64, error) {
 	appauth := m.ApplicationAuthentication{ID: appauthID}
-	result := DB.
+	result := a.db().
 		Where("tenant_id = ?", *a.TenantID).
 		First(&appauth)
 
@@ -165,7 +169,7 @@ func (a *authenticationDaoImpl) ListForApplicationAuthentication(appauthID int64
 }
 
 func (a *authenticationDaoImpl) ListForEndpoint(endpointID int64, limit, offset int, filters []util.Filter) ([]m.Authentication, int64, error) {
-	_, err := GetEndpointDao(a.TenantID).GetById(&endpointID)
+	_, err := GetEndpointDao(a.ctx, a.TenantID).GetById(&endpointID)
 	if err != nil {
 		return nil, 0, util.NewErrNotFound("endpoint")
 	}
@@ -233,7 +237,7 @@ func (a *authenticationDaoImpl) GetById(uid string) (*m.Authentication, error) {
 }
 
 func (a *authenticationDaoImpl) Create(auth *m.Authentication) error {
-	query := DB.Select("source_id").Where("tenant_id = ?", *a.TenantID)
+	query := a.db().Select("source_id").Where("tenant_id = ?", *a.TenantID)
 
 	switch strings.ToLower(auth.ResourceType) {
 	case "application":
@@ -537,6 +541,32 @@ func authFromVault(secret *api.Secret) *m.Authentication {
 		auth.LastCheckedAt = &parsedLastCheckedAt
 	}
 
+	if data["last_used_at"] != nil {
+		var lastUsedAt string
+		if lastUsedAt, ok = data["last_used_at"].(string); !ok {
+			return nil
+		}
+
+		parsedLastUsedAt, err := time.Parse(time.RFC3339Nano, lastUsedAt)
+		if err != nil {
+			return nil
+		}
+		auth.LastUsedAt = &parsedLastUsedAt
+	}
+
+	if data["expires_at"] != nil {
+		var expiresAt string
+		if expiresAt, ok = data["expires_at"].(string); !ok {
+			return nil
+		}
+
+		parsedExpiresAt, err := time.Parse(time.RFC3339Nano, expiresAt)
+		if err != nil {
+			return nil
+		}
+		auth.ExpiresAt = &parsedExpiresAt
+	}
+
 	return auth
 }
 
@@ -566,7 +596,7 @@ func (a *authenticationDaoImpl) FetchAndUpdateBy(resource util.Resource, updateA
 		return nil, err
 	}
 
-	sourceDao := GetSourceDao(a.TenantID)
+	sourceDao := GetSourceDao(a.ctx, a.TenantID)
 	source, err := sourceDao.GetById(&authentication.SourceID)
 	if err != nil {
 		return nil, err
@@ -616,6 +646,26 @@ func (a *authenticationDaoImpl) AuthenticationsByResource(authentication *m.Auth
 	return resourceAuthentications, nil
 }
 
+func (a *authenticationDaoImpl) TouchLastUsed(_ int64) error {
+	return errors.New("tracking when the authentications are used is not supported with the vault secret store")
+}
+
+func (a *authenticationDaoImpl) ListStale(_ time.Time, _, _ int) ([]m.Authentication, int64, error) {
+	return nil, 0, errors.New("listing stale authentications is not supported with the vault secret store")
+}
+
+func (a *authenticationDaoImpl) ListExpiringSoon(_ int64, _ time.Duration) ([]m.Authentication, error) {
+	return nil, errors.New("listing expiring authentications is not supported with the vault secret store")
+}
+
+func (a *authenticationDaoImpl) ListTenantsWithExpiringSoon(_ time.Duration) ([]int64, error) {
+	return nil, errors.New("listing expiring authentications is not supported with the vault secret store")
+}
+
+func (a *authenticationDaoImpl) ListExpiringBefore(_ context.Context, _ int64, _ time.Time, _, _ int) ([]m.Authentication, int64, error) {
+	return nil, 0, errors.New("listing expiring authentications is not supported with the vault secret store")
+}
+
 func (a *authenticationDaoImpl) ListIdsForResource(resourceType string, resourceIds []int64) ([]m.Authentication, error) {
 	keys, err := a.listKeys()
 	if err != nil {
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -22,12 +23,15 @@ func TestAuthFromVault(t *testing.T) {
 	now := time.Now()
 	lastAvailableCheckedAt := now.Add(time.Duration(-1) * time.Hour)
 	createdAt := now.Add(time.Duration(-2) * time.Hour)
+	expiresAt := now.Add(30 * 24 * time.Hour)
 
 	authentication := m.Authentication{
 		AuthType:        "test-authtype",
 		Extra:           nil,
 		LastAvailableAt: &lastAvailableCheckedAt,
 		LastCheckedAt:   &lastAvailableCheckedAt,
+		LastUsedAt:      &lastAvailableCheckedAt,
+		ExpiresAt:       &expiresAt,
 		ResourceType:    "source",
 		ResourceID:      123,
 		SourceID:        25,
@@ -51,6 +55,8 @@ func TestAuthFromVault(t *testing.T) {
 	}
 	data["last_available_at"] = authentication.LastAvailableAt.Format(time.RFC3339Nano)
 	data["last_checked_at"] = authentication.LastCheckedAt.Format(time.RFC3339Nano)
+	data["last_used_at"] = authentication.LastUsedAt.Format(time.RFC3339Nano)
+	data["expires_at"] = authentication.ExpiresAt.Format(time.RFC3339Nano)
 	// setting the password manually due to the fact that it can be null therefore not in the db. and if it _were_ in
 	// the vault db it would come back as a regular string and not a pointer.
 	data["password"] = "my-password"
@@ -157,6 +163,22 @@ func TestAuthFromVault(t *testing.T) {
 				t.Errorf(`authentication last checked at statuses are different. Want "%s", got "%s"`, want, got)
 			}
 		}
+
+		{
+			want := authentication.LastUsedAt.Format(time.RFC3339Nano)
+			got := resultingAuth.LastUsedAt.Format(time.RFC3339Nano)
+			if want != got {
+				t.Errorf(`authentication last used at timestamps are different. Want "%s", got "%s"`, want, got)
+			}
+		}
+
+		{
+			want := authentication.ExpiresAt.Format(time.RFC3339Nano)
+			got := resultingAuth.ExpiresAt.Format(time.RFC3339Nano)
+			if want != got {
+				t.Errorf(`authentication expires at timestamps are different. Want "%s", got "%s"`, want, got)
+			}
+		}
 	}
 }
 
@@ -272,7 +294,7 @@ func TestAuthenticationListOffsetAndLimit(t *testing.T) {
 		}
 
 		conf.SecretStore = secretStore
-		authenticationDao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+		authenticationDao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 		for _, d := range fixtures.TestDataOffsetLimit {
 			authentications, gotCount, err := authenticationDao.List(d.Limit, d.Offset, []util.Filter{})
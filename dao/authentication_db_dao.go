@@ -1,21 +1,24 @@
 package dao
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
 )
 
 type authenticationDaoDbImpl struct {
 	TenantID *int64
+	requestContext
 }
 
 func (add *authenticationDaoDbImpl) List(limit, offset int, filters []util.Filter) ([]m.Authentication, int64, error) {
-	query := DB.
-		Debug().
+	query := add.db().
 		Where("authentications.tenant_id = ?", add.TenantID).
 		Model(&m.Authentication{})
 
@@ -46,8 +49,7 @@ func (add *authenticationDaoDbImpl) List(limit, offset int, filters []util.Filte
 func (add *authenticationDaoDbImpl) GetById(id string) (*m.Authentication, error) {
 	authentication := &m.Authentication{}
 
-	err := DB.
-		Debug().
+	err := add.db().
 		Where("id = ?", id).
 		Where("tenant_id = ?", add.TenantID).
 		First(&authentication).
@@ -63,7 +65,7 @@ func (add *authenticationDaoDbImpl) GetById(id string) (*m.Authentication, error
 func (add *authenticationDaoDbImpl) ListForSource(sourceID int64, limit, offset int, filters []util.Filter) ([]m.Authentication, int64, error) {
 	// Check that the source exists before continuing.
 	var sourceExists bool
-	err := DB.Debug().
+	err := add.db().
 		Model(&m.Source{}).
 		Select(`1`).
 		Where(`id = ?`, sourceID).
@@ -80,8 +82,7 @@ func (add *authenticationDaoDbImpl) ListForSource(sourceID int64, limit, offset
 	}
 
 	// List and count all the authentications from the given source.
-	query := DB.
-		Debug().
+	query := add.db().
 		Model(&m.Authentication{})
 
 	query, err = applyFilters(query, filters)
@@ -114,7 +115,7 @@ func (add *authenticationDaoDbImpl) ListForSource(sourceID int64, limit, offset
 func (add *authenticationDaoDbImpl) ListForApplication(applicationID int64, limit, offset int, filters []util.Filter) ([]m.Authentication, int64, error) {
 	// Check that the application exists before continuing.
 	var applicationExists bool
-	err := DB.Debug().
+	err := add.db().
 		Model(&m.Application{}).
 		Select(`1`).
 		Where(`id = ?`, applicationID).
@@ -131,8 +132,7 @@ func (add *authenticationDaoDbImpl) ListForApplication(applicationID int64, limi
 	}
 
 	// List and count all the authentications from the given application.
-	query := DB.
-		Debug().
+	query := add.db().
 		Model(&m.Authentication{})
 
 	query, err = applyFilters(query, filters)
@@ -170,8 +170,7 @@ func (add *authenticationDaoDbImpl) ListForApplicationAuthentication(appAuthID i
 	// Get application authentication
 	appAuth := &m.ApplicationAuthentication{ID: appAuthID}
 
-	err := DB.
-		Debug().
+	err := add.db().
 		Where("tenant_id = ?", add.TenantID).
 		First(&appAuth).
 		Error
@@ -194,7 +193,7 @@ func (add *authenticationDaoDbImpl) ListForApplicationAuthentication(appAuthID i
 func (add *authenticationDaoDbImpl) ListForEndpoint(endpointID int64, limit, offset int, filters []util.Filter) ([]m.Authentication, int64, error) {
 	// Check that the endpoint exists before continuing.
 	var endpointExists bool
-	err := DB.Debug().
+	err := add.db().
 		Model(&m.Endpoint{}).
 		Select(`1`).
 		Where(`id = ?`, endpointID).
@@ -211,7 +210,7 @@ func (add *authenticationDaoDbImpl) ListForEndpoint(endpointID int64, limit, off
 	}
 
 	// List and count all the authentications from the given endpoint.
-	query := DB.Debug().
+	query := add.db().
 		Model(&m.Authentication{})
 
 	query, err = applyFilters(query, filters)
@@ -246,7 +245,7 @@ func (add *authenticationDaoDbImpl) ListForEndpoint(endpointID int64, limit, off
 }
 
 func (add *authenticationDaoDbImpl) Create(authentication *m.Authentication) error {
-	query := DB.Debug().Select("source_id").Where("tenant_id = ?", *add.TenantID)
+	query := add.db().Select("source_id").Where("tenant_id = ?", *add.TenantID)
 
 	switch strings.ToLower(authentication.ResourceType) {
 	case "application":
@@ -281,8 +280,7 @@ func (add *authenticationDaoDbImpl) Create(authentication *m.Authentication) err
 		authentication.Password = &encryptedValue
 	}
 
-	return DB.
-		Debug().
+	return add.db().
 		Create(authentication).
 		Error
 }
@@ -300,12 +298,11 @@ func (add *authenticationDaoDbImpl) BulkCreate(auth *m.Authentication) error {
 		auth.Password = &encryptedValue
 	}
 
-	return DB.Debug().Create(auth).Error
+	return add.db().Create(auth).Error
 }
 
 func (add *authenticationDaoDbImpl) Update(authentication *m.Authentication) error {
-	return DB.
-		Debug().
+	return add.db().
 		Where("tenant_id = ?", add.TenantID).
 		Updates(authentication).
 		Error
@@ -314,8 +311,7 @@ func (add *authenticationDaoDbImpl) Update(authentication *m.Authentication) err
 func (add *authenticationDaoDbImpl) Delete(id string) (*m.Authentication, error) {
 	var authentication m.Authentication
 
-	err := DB.
-		Debug().
+	err := add.db().
 		Where("id = ?", id).
 		Where("tenant_id = ?", add.TenantID).
 		First(&authentication).
@@ -325,8 +321,7 @@ func (add *authenticationDaoDbImpl) Delete(id string) (*m.Authentication, error)
 		return nil, util.NewErrNotFound("authentication")
 	}
 
-	err = DB.
-		Debug().
+	err = add.db().
 		Where("tenant_id = ?", add.TenantID).
 		Delete(authentication).
 		Error
@@ -390,7 +385,7 @@ func (add *authenticationDaoDbImpl) FetchAndUpdateBy(resource util.Resource, upd
 		return nil, err
 	}
 
-	sourceDao := GetSourceDao(add.TenantID)
+	sourceDao := GetSourceDao(add.ctx, add.TenantID)
 	source, err := sourceDao.GetById(&authentication.SourceID)
 	if err != nil {
 		return nil, err
@@ -421,8 +416,7 @@ func (add *authenticationDaoDbImpl) ToEventJSON(resource util.Resource) ([]byte,
 func (add *authenticationDaoDbImpl) ListIdsForResource(resourceType string, resourceIds []int64) ([]m.Authentication, error) {
 	var authentications []m.Authentication
 
-	err := DB.
-		Debug().
+	err := add.db().
 		Model(m.Authentication{}).
 		Where("resource_type = ?", resourceType).
 		Where("resource_id IN ?", resourceIds).
@@ -451,8 +445,7 @@ func (add *authenticationDaoDbImpl) BulkDelete(authentications []m.Authenticatio
 	// delete without a where condition" error. In theory this should not happen, since this function is expected to
 	// be called with a "len(authentications) != 0" slice, but just to be safe...
 	var dbAuths []m.Authentication
-	err := DB.
-		Debug().
+	err := add.db().
 		Preload("Tenant").
 		Where("id IN ?", authIds).
 		Where("tenant_id = ?", add.TenantID).
@@ -464,8 +457,7 @@ func (add *authenticationDaoDbImpl) BulkDelete(authentications []m.Authenticatio
 	}
 
 	if len(dbAuths) != 0 {
-		err = DB.
-			Debug().
+		err = add.db().
 			Where("tenant_id = ?", add.TenantID).
 			Delete(&dbAuths).
 			Error
@@ -477,3 +469,122 @@ func (add *authenticationDaoDbImpl) BulkDelete(authentications []m.Authenticatio
 
 	return dbAuths, nil
 }
+
+func (add *authenticationDaoDbImpl) TouchLastUsed(authId int64) error {
+	result := add.db().
+		Model(&m.Authentication{}).
+		Where("id = ?", authId).
+		Where("tenant_id = ?", add.TenantID).
+		Update("last_used_at", gorm.Expr("NOW()"))
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return util.NewErrNotFound("authentication")
+	}
+
+	return nil
+}
+
+func (add *authenticationDaoDbImpl) ListStale(usedBefore time.Time, limit, offset int) ([]m.Authentication, int64, error) {
+	// Authentications which have never been used are left out, since there is no way of knowing for how long they
+	// have been unused.
+	query := add.db().
+		Model(&m.Authentication{}).
+		Where("last_used_at < ?", usedBefore)
+
+	// getting the total count for pagination
+	count := int64(0)
+	query.Count(&count)
+
+	authentications := make([]m.Authentication, 0, limit)
+	err := query.
+		Preload("Tenant").
+		Order("id").
+		Limit(limit).
+		Offset(offset).
+		Find(&authentications).
+		Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return authentications, count, nil
+}
+
+func (add *authenticationDaoDbImpl) ListExpiringSoon(tenantId int64, within time.Duration) ([]m.Authentication, error) {
+	// The authentications which have already expired are listed too, so that they keep being reported until their
+	// credentials get rotated.
+	authentications := make([]m.Authentication, 0)
+	err := add.db().
+		Model(&m.Authentication{}).
+		Preload("Tenant").
+		Where("tenant_id = ?", tenantId).
+		Where("expires_at IS NOT NULL").
+		Where("expires_at < NOW() + ?::INTERVAL", expiryInterval(within)).
+		Order("expires_at").
+		Find(&authentications).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return authentications, nil
+}
+
+func (add *authenticationDaoDbImpl) ListTenantsWithExpiringSoon(within time.Duration) ([]int64, error) {
+	var tenantIds []int64
+	err := add.db().
+		Model(&m.Authentication{}).
+		Distinct("tenant_id").
+		Where("expires_at IS NOT NULL").
+		Where("expires_at < NOW() + ?::INTERVAL", expiryInterval(within)).
+		Order("tenant_id").
+		Pluck("tenant_id", &tenantIds).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return tenantIds, nil
+}
+
+func (add *authenticationDaoDbImpl) ListExpiringBefore(ctx context.Context, tenantId int64, before time.Time, limit, offset int) ([]m.Authentication, int64, error) {
+	query := add.db().
+		WithContext(ctx).
+		Model(&m.Authentication{}).
+		Where("tenant_id = ?", tenantId).
+		Where("expires_at IS NOT NULL").
+		Where("expires_at <= ?", before)
+
+	count := int64(0)
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	authentications := make([]m.Authentication, 0, limit)
+	err = query.
+		Order("expires_at ASC").
+		Order("id").
+		Limit(limit).
+		Offset(offset).
+		Find(&authentications).
+		Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return authentications, count, nil
+}
+
+// expiryInterval formats the given duration as a Postgres interval.
+func expiryInterval(within time.Duration) string {
+	return fmt.Sprintf("%d microseconds", within.Microseconds())
+}
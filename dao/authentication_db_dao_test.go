@@ -2,6 +2,7 @@ package dao
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -30,7 +31,7 @@ func setUpValidAuthentication() *model.Authentication {
 
 // createAuthenticationFixture inserts a new authentication fixture in the database.
 func createAuthenticationFixture(t *testing.T) {
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	auth := setUpValidAuthentication()
 
@@ -47,7 +48,7 @@ func TestAuthenticationDbCreate(t *testing.T) {
 	testutils.SkipIfNotSecretStoreDatabase(t)
 	SwitchSchema("authentications_db")
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	auth := setUpValidAuthentication()
 
@@ -67,7 +68,7 @@ func TestAuthenticationDbBulkCreate(t *testing.T) {
 	testutils.SkipIfNotSecretStoreDatabase(t)
 	SwitchSchema("authentications_db")
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	auth := setUpValidAuthentication()
 
@@ -85,7 +86,7 @@ func TestAuthenticationDbList(t *testing.T) {
 	testutils.SkipIfNotSecretStoreDatabase(t)
 	SwitchSchema("authentications_db")
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	// Create another authentication to see if the listing function also brings it back.
 	createAuthenticationFixture(t)
@@ -126,7 +127,7 @@ func TestAuthenticationDbGetById(t *testing.T) {
 	// Create the authentication fixture that we will be fetching.
 	authFixture := setUpValidAuthentication()
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	// Using bulk create so we don't check to see if the resource is there first
 	err := dao.BulkCreate(authFixture)
 	if err != nil {
@@ -158,7 +159,7 @@ func TestAuthenticationDbUpdate(t *testing.T) {
 	// Create the authentication fixture that we will be fetching.
 	authFixture := setUpValidAuthentication()
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	// Using bulk create so we don't check to see if the resource is there first
 	err := dao.BulkCreate(authFixture)
 	if err != nil {
@@ -191,6 +192,120 @@ func TestAuthenticationDbUpdate(t *testing.T) {
 	DropSchema("authentications_db")
 }
 
+// TestAuthenticationDbTouchLastUsed tests that touching an authentication sets its "last used at" timestamp, and that
+// it then gets listed as stale only when it was used before the given time.
+func TestAuthenticationDbTouchLastUsed(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	testutils.SkipIfNotSecretStoreDatabase(t)
+	SwitchSchema("authentications_db")
+
+	authFixture := setUpValidAuthentication()
+
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
+	// Using bulk create so we don't check to see if the resource is there first
+	err := dao.BulkCreate(authFixture)
+	if err != nil {
+		t.Errorf(`error creating the authentication: %s`, err)
+	}
+
+	err = dao.TouchLastUsed(authFixture.DbID)
+	if err != nil {
+		t.Errorf(`error touching the authentication: %s`, err)
+	}
+
+	touchedAuth, err := dao.GetById(strconv.FormatInt(authFixture.DbID, 10))
+	if err != nil {
+		t.Errorf(`error fetching the authentication: %s`, err)
+	}
+
+	if touchedAuth.LastUsedAt == nil {
+		t.Errorf(`want the "last used at" timestamp set, got nil`)
+	}
+
+	_, count, err := dao.ListStale(time.Now().Add(-time.Hour), 100, 0)
+	if err != nil {
+		t.Errorf(`error listing the stale authentications: %s`, err)
+	}
+
+	if count != 0 {
+		t.Errorf(`want no stale authentications, got "%d"`, count)
+	}
+
+	staleAuths, count, err := dao.ListStale(time.Now().Add(time.Hour), 100, 0)
+	if err != nil {
+		t.Errorf(`error listing the stale authentications: %s`, err)
+	}
+
+	if count != 1 || staleAuths[0].DbID != authFixture.DbID {
+		t.Errorf(`want authentication "%d" as the only stale one, got "%v"`, authFixture.DbID, staleAuths)
+	}
+
+	err = dao.TouchLastUsed(12345)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`unexpected error received. Want "%s", got "%s"`, reflect.TypeOf(util.ErrNotFoundEmpty), reflect.TypeOf(err))
+	}
+
+	DropSchema("authentications_db")
+}
+
+// TestAuthenticationDbListExpiringSoon tests that the authentications are listed, along with their tenants, only when
+// they expire within the given duration.
+func TestAuthenticationDbListExpiringSoon(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	testutils.SkipIfNotSecretStoreDatabase(t)
+	SwitchSchema("authentications_db")
+
+	authFixture := setUpValidAuthentication()
+	expiresAt := time.Now().Add(10 * 24 * time.Hour)
+	authFixture.ExpiresAt = &expiresAt
+
+	tenantId := fixtures.TestTenantData[0].Id
+	dao := GetAuthenticationDao(context.Background(), &tenantId)
+	// Using bulk create so we don't check to see if the resource is there first
+	err := dao.BulkCreate(authFixture)
+	if err != nil {
+		t.Errorf(`error creating the authentication: %s`, err)
+	}
+
+	expiring, err := dao.ListExpiringSoon(tenantId, 5*24*time.Hour)
+	if err != nil {
+		t.Errorf(`error listing the expiring authentications: %s`, err)
+	}
+
+	if len(expiring) != 0 {
+		t.Errorf(`want no expiring authentications, got "%v"`, expiring)
+	}
+
+	expiring, err = dao.ListExpiringSoon(tenantId, 30*24*time.Hour)
+	if err != nil {
+		t.Errorf(`error listing the expiring authentications: %s`, err)
+	}
+
+	if len(expiring) != 1 || expiring[0].DbID != authFixture.DbID || expiring[0].Tenant.Id != tenantId {
+		t.Errorf(`want authentication "%d" as the only expiring one, got "%v"`, authFixture.DbID, expiring)
+	}
+
+	tenantIds, err := dao.ListTenantsWithExpiringSoon(30 * 24 * time.Hour)
+	if err != nil {
+		t.Errorf(`error listing the tenants with expiring authentications: %s`, err)
+	}
+
+	if len(tenantIds) != 1 || tenantIds[0] != tenantId {
+		t.Errorf(`want tenant "%d" only, got "%v"`, tenantId, tenantIds)
+	}
+
+	expiring, err = dao.ListExpiringSoon(fixtures.TestTenantData[1].Id, 30*24*time.Hour)
+	if err != nil {
+		t.Errorf(`error listing the expiring authentications: %s`, err)
+	}
+
+	if len(expiring) != 0 {
+		t.Errorf(`want no expiring authentications for another tenant, got "%v"`, expiring)
+	}
+
+	DropSchema("authentications_db")
+}
+
 // TestAuthenticationDbGet tests that the "delete" operation is able to delete the expected authentication.
 func TestAuthenticationDbDelete(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
@@ -200,7 +315,7 @@ func TestAuthenticationDbDelete(t *testing.T) {
 	// Create the authentication fixture that we will be fetching.
 	authFixture := setUpValidAuthentication()
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	// Using bulk create so we don't check to see if the resource is there first
 	err := dao.BulkCreate(authFixture)
 	if err != nil {
@@ -237,7 +352,7 @@ func TestAuthenticationDbDeleteNotFound(t *testing.T) {
 	testutils.SkipIfNotSecretStoreDatabase(t)
 	SwitchSchema("authentications_db")
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	_, err := dao.Delete("12345")
 	if !errors.Is(err, util.ErrNotFoundEmpty) {
 		t.Errorf(`unexpected error received. Want "%s", got "%s"`, reflect.TypeOf(util.ErrNotFoundEmpty), reflect.TypeOf(err))
@@ -249,7 +364,7 @@ func TestAuthenticationDbDeleteNotFound(t *testing.T) {
 // TestTenantId is a trivial test which tests that a correct tenant ID is returned in the function.
 func TestTenantId(t *testing.T) {
 	tenantId := int64(12345)
-	dao := GetAuthenticationDao(&tenantId)
+	dao := GetAuthenticationDao(context.Background(), &tenantId)
 
 	want := tenantId
 	got := dao.Tenant()
@@ -266,7 +381,7 @@ func TestListForSource(t *testing.T) {
 	SwitchSchema("authentications_db")
 
 	// Create a new source the new fixtures will be attached to.
-	sourceDao := GetSourceDao(&fixtures.TestTenantData[1].Id)
+	sourceDao := GetSourceDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	source := model.Source{
 		Name:         "new source in new tenant",
 		SourceTypeID: fixtures.TestSourceTypeData[0].Id,
@@ -279,7 +394,7 @@ func TestListForSource(t *testing.T) {
 	}
 
 	// Create three new authentications for the new source.
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[1].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	var i int
 	var maxAuths = 3
 	for i < maxAuths {
@@ -327,7 +442,7 @@ func TestListForSourceNotFound(t *testing.T) {
 	testutils.SkipIfNotSecretStoreDatabase(t)
 	SwitchSchema("authentications_db")
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[1].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 
 	// Call the function under test.
 	_, _, err := dao.ListForSource(12345, 100, 0, []util.Filter{})
@@ -351,7 +466,7 @@ func TestListForApplication(t *testing.T) {
 	SwitchSchema("authentications_db")
 
 	// Create a new source the new fixtures will be attached to.
-	sourceDao := GetSourceDao(&fixtures.TestTenantData[1].Id)
+	sourceDao := GetSourceDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	source := model.Source{
 		Name:         "new source in new tenant",
 		SourceTypeID: fixtures.TestSourceTypeData[0].Id,
@@ -364,7 +479,7 @@ func TestListForApplication(t *testing.T) {
 	}
 
 	// Create an application fixture.
-	applicationDao := GetApplicationDao(&fixtures.TestTenantData[1].Id)
+	applicationDao := GetApplicationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	application := model.Application{
 		ApplicationTypeID: fixtures.TestApplicationTypeData[0].Id,
 		SourceID:          source.ID,
@@ -376,7 +491,7 @@ func TestListForApplication(t *testing.T) {
 	}
 
 	// Create three new authentications for the new application.
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[1].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	var i int
 	var maxAuths = 3
 	for i < maxAuths {
@@ -423,7 +538,7 @@ func TestListForApplicationNotFound(t *testing.T) {
 	testutils.SkipIfNotSecretStoreDatabase(t)
 	SwitchSchema("authentications_db")
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[1].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 
 	// Call the function under test.
 	_, _, err := dao.ListForApplication(12345, 100, 0, []util.Filter{})
@@ -447,7 +562,7 @@ func TestListForApplicationAuthentication(t *testing.T) {
 	SwitchSchema("authentications_db")
 
 	// Create a new source the new fixtures will be attached to.
-	sourceDao := GetSourceDao(&fixtures.TestTenantData[1].Id)
+	sourceDao := GetSourceDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	source := model.Source{
 		Name:         "new source in new tenant",
 		SourceTypeID: fixtures.TestSourceTypeData[0].Id,
@@ -460,7 +575,7 @@ func TestListForApplicationAuthentication(t *testing.T) {
 	}
 
 	// Create an application fixture.
-	applicationDao := GetApplicationDao(&fixtures.TestTenantData[1].Id)
+	applicationDao := GetApplicationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	application := model.Application{
 		ApplicationTypeID: fixtures.TestApplicationTypeData[0].Id,
 		SourceID:          source.ID,
@@ -472,7 +587,7 @@ func TestListForApplicationAuthentication(t *testing.T) {
 	}
 
 	// Create a new authentication for the new application authentication.
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[1].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	auth := &model.Authentication{
 		AuthType:     TestAuthType,
 		ResourceType: "Application",
@@ -487,7 +602,7 @@ func TestListForApplicationAuthentication(t *testing.T) {
 	}
 
 	// Create the application authentication.
-	appAuthDao := GetApplicationAuthenticationDao(&fixtures.TestTenantData[1].Id)
+	appAuthDao := GetApplicationAuthenticationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	appAuth := model.ApplicationAuthentication{
 		TenantID:         fixtures.TestTenantData[1].Id,
 		ApplicationID:    application.ID,
@@ -527,7 +642,7 @@ func TestListForApplicationAuthenticationNotFound(t *testing.T) {
 	testutils.SkipIfNotSecretStoreDatabase(t)
 	SwitchSchema("authentications_db")
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[1].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 
 	// Call the function under test.
 	_, _, err := dao.ListForApplicationAuthentication(12345, 100, 0, []util.Filter{})
@@ -550,7 +665,7 @@ func TestListForEndpoint(t *testing.T) {
 	SwitchSchema("authentications_db")
 
 	// Create a new source the new fixtures will be attached to.
-	sourceDao := GetSourceDao(&fixtures.TestTenantData[1].Id)
+	sourceDao := GetSourceDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	source := model.Source{
 		Name:         "new source in new tenant",
 		SourceTypeID: fixtures.TestSourceTypeData[0].Id,
@@ -563,7 +678,7 @@ func TestListForEndpoint(t *testing.T) {
 	}
 
 	// Create an endpoint fixture.
-	endpointDao := GetEndpointDao(&fixtures.TestTenantData[1].Id)
+	endpointDao := GetEndpointDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	endpoint := model.Endpoint{
 		SourceID: source.ID,
 	}
@@ -574,7 +689,7 @@ func TestListForEndpoint(t *testing.T) {
 	}
 
 	// Create three new authentications for the new application authentication.
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[1].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 	var i int
 	var maxAuths = 3
 	for i < maxAuths {
@@ -621,7 +736,7 @@ func TestListForEndpointNotFound(t *testing.T) {
 	testutils.SkipIfNotSecretStoreDatabase(t)
 	SwitchSchema("authentications_db")
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[1].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[1].Id)
 
 	// Call the function under test.
 	_, _, err := dao.ListForEndpoint(12345, 0, 0, []util.Filter{})
@@ -646,7 +761,7 @@ func TestFetchAndUpdateBy(t *testing.T) {
 	// Create the authentication fixture that we will be fetching.
 	authFixture := setUpValidAuthentication()
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	// Using bulk create so we don't check to see if the resource is there first
 	err := dao.BulkCreate(authFixture)
 	if err != nil {
@@ -733,7 +848,7 @@ func TestToEventJSON(t *testing.T) {
 	// Create the authentication fixture that we will be fetching.
 	authFixture := setUpValidAuthentication()
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	// Using bulk create so we don't check to see if the resource is there first
 	err := dao.BulkCreate(authFixture)
 	if err != nil {
@@ -783,7 +898,7 @@ func TestBulkMessage(t *testing.T) {
 	authFixture.ResourceID = fixtures.TestSourceData[0].ID
 	authFixture.ResourceType = "Source"
 
-	dao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	dao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	err := dao.BulkCreate(authFixture)
 	if err != nil {
 		t.Errorf(`error creating the authentication: %s`, err)
@@ -797,7 +912,7 @@ func TestBulkMessage(t *testing.T) {
 	if err != nil {
 		t.Errorf(`could not fetch the authentication from the database: %s`, err)
 	}
-	sourceDao := GetSourceDao(&fixtures.TestTenantData[0].Id)
+	sourceDao := GetSourceDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	source, err := sourceDao.GetById(&authFixture.SourceID)
 	if err != nil {
 		t.Errorf(`could not fetch source: %s`, err)
@@ -845,7 +960,6 @@ func TestListIdsForResource(t *testing.T) {
 
 	// In this test we want a clean "authentications" table.
 	err := DB.
-		Debug().
 		Model(model.Authentication{}).
 		Delete(fixtures.TestAuthenticationData).
 		Error
@@ -877,7 +991,7 @@ func TestListIdsForResource(t *testing.T) {
 	// How many authentications will we be creating per resource?
 	maxAuthenticationsPerResource := 5
 
-	authsDao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	authsDao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	// Create the authentications.
 	for _, resource := range resources {
@@ -986,7 +1100,7 @@ func TestBulkDelete(t *testing.T) {
 	// How many authentications will we be creating per resource?
 	maxAuthenticationsPerResource := 5
 
-	authsDao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	authsDao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	// Store the authentications for later.
 	var createdAuthentications = make([]model.Authentication, 0, len(resources)*maxAuthenticationsPerResource)
@@ -1083,7 +1197,7 @@ func TestBulkDeleteRegression(t *testing.T) {
 	// How many authentications will we be creating per resource?
 	maxAuthenticationsPerResource := 5
 
-	authsDao := GetAuthenticationDao(&fixtures.TestTenantData[0].Id)
+	authsDao := GetAuthenticationDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	for i := 0; i < maxAuthenticationsPerResource; i++ {
 		authFixture := setUpValidAuthentication()
@@ -1129,3 +1243,54 @@ func TestBulkDeleteRegression(t *testing.T) {
 
 	DropSchema("authentications_db")
 }
+
+// TestListExpiringBefore tests that only the tenant's authentications which expire before the given date are listed,
+// sorted by their expiry date.
+func TestListExpiringBefore(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	testutils.SkipIfNotSecretStoreDatabase(t)
+	SwitchSchema("authentications_db")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	authsDao := GetAuthenticationDao(context.Background(), &tenantId)
+	before := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	expiries := []time.Time{before.Add(-time.Hour), before.Add(time.Hour), before.Add(-48 * time.Hour)}
+	for _, expiry := range expiries {
+		expiresAt := expiry
+
+		auth := setUpValidAuthentication()
+		auth.ExpiresAt = &expiresAt
+
+		err := authsDao.BulkCreate(auth)
+		if err != nil {
+			t.Fatalf(`error creating the authentication: %s`, err)
+		}
+	}
+
+	auths, count, err := authsDao.ListExpiringBefore(context.Background(), tenantId, before, 100, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 2 || len(auths) != 2 {
+		t.Fatalf(`want two expiring authentications, got a count of "%d" and "%d" authentications`, count, len(auths))
+	}
+
+	if !auths[0].ExpiresAt.Equal(expiries[2]) || !auths[1].ExpiresAt.Equal(expiries[0]) {
+		t.Errorf(`want the authentications sorted by their expiry, got "%s" and "%s"`, auths[0].ExpiresAt, auths[1].ExpiresAt)
+	}
+
+	// Other tenants don't see the authentications.
+	otherTenant := tenantId + 12345
+	_, count, err = authsDao.ListExpiringBefore(context.Background(), otherTenant, before, 100, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 0 {
+		t.Errorf(`want no expiring authentications for another tenant, got "%d"`, count)
+	}
+
+	DropSchema("authentications_db")
+}
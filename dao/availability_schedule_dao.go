@@ -0,0 +1,138 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetAvailabilityScheduleDao is a function definition that can be replaced in runtime in case some other DAO provider
+// is needed.
+var GetAvailabilityScheduleDao func(context.Context, *int64) AvailabilityScheduleDao
+
+// getDefaultAvailabilityScheduleDao gets the default DAO implementation which will have the given tenant ID.
+func getDefaultAvailabilityScheduleDao(ctx context.Context, tenantId *int64) AvailabilityScheduleDao {
+	return &availabilityScheduleDaoImpl{
+		requestContext: requestContext{ctx: ctx},
+		TenantID:       tenantId,
+	}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetAvailabilityScheduleDao = getDefaultAvailabilityScheduleDao
+}
+
+type availabilityScheduleDaoImpl struct {
+	TenantID *int64
+	requestContext
+}
+
+// nextRun returns the first time the given cron expression fires after the given time.
+func nextRun(cronExpr string, after time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(cronExpr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron expression %q: %w", cronExpr, err)
+	}
+
+	return schedule.Next(after), nil
+}
+
+func (a *availabilityScheduleDaoImpl) Upsert(sourceId int64, cronExpr string) error {
+	nextRunAt, err := nextRun(cronExpr, time.Now())
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	var sourceExists bool
+	err = a.db().
+		Model(&m.Source{}).
+		Select("1").
+		Where("id = ?", sourceId).
+		Where("tenant_id = ?", a.TenantID).
+		Scan(&sourceExists).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	if !sourceExists {
+		return util.NewErrNotFound("source")
+	}
+
+	schedule := m.AvailabilitySchedule{
+		SourceId:  sourceId,
+		TenantId:  *a.TenantID,
+		CronExpr:  cronExpr,
+		NextRunAt: nextRunAt,
+	}
+
+	return a.db().
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "source_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"cron_expr", "next_run_at", "updated_at"}),
+		}).
+		Create(&schedule).
+		Error
+}
+
+func (a *availabilityScheduleDaoImpl) ClaimDue(asOf time.Time, limit int) ([]m.AvailabilitySchedule, error) {
+	var schedules []m.AvailabilitySchedule
+
+	err := transaction(a.db(), func(tx *gorm.DB) error {
+		// The schedules which are being claimed by other replicas are skipped, so that every due check is only
+		// raised once.
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("Source").
+			Preload("Tenant").
+			Where("next_run_at <= ?", asOf).
+			Order("next_run_at").
+			Limit(limit).
+			Find(&schedules).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		for i := range schedules {
+			nextRunAt, err := nextRun(schedules[i].CronExpr, asOf)
+			if err != nil {
+				return err
+			}
+
+			err = tx.
+				Model(&m.AvailabilitySchedule{}).
+				Where("source_id = ?", schedules[i].SourceId).
+				Updates(map[string]interface{}{
+					"last_run_at": asOf,
+					"next_run_at": nextRunAt,
+				}).
+				Error
+
+			if err != nil {
+				return err
+			}
+
+			schedules[i].LastRunAt = &asOf
+			schedules[i].NextRunAt = nextRunAt
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,167 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm/clause"
+)
+
+// TestNextRun tests that the next run is computed from the cron expression, and that invalid expressions are
+// rejected.
+func TestNextRun(t *testing.T) {
+	after := time.Date(2022, 5, 18, 10, 30, 0, 0, time.UTC)
+
+	next, err := nextRun("0 * * * *", after)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	want := time.Date(2022, 5, 18, 11, 0, 0, 0, time.UTC)
+	if !next.Equal(want) {
+		t.Errorf(`want next run "%s", got "%s"`, want, next)
+	}
+
+	_, err = nextRun("every hour", after)
+	if err == nil {
+		t.Errorf(`want an error for an invalid cron expression, got none`)
+	}
+}
+
+// TestAvailabilitySchedule tests that the scheduled sources are claimed once they're due, and that claiming them
+// schedules their next check.
+func TestAvailabilitySchedule(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("availability_schedule_tests")
+
+	source := fixtures.TestSourceData[0]
+	scheduleDao := GetAvailabilityScheduleDao(context.Background(), &source.TenantID)
+
+	err := scheduleDao.Upsert(source.ID, "*/5 * * * *")
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	// Nothing is due right now, since the first run is in the future.
+	due, err := scheduleDao.ClaimDue(time.Now(), 10)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(due) != 0 {
+		t.Errorf(`want no due schedules, got "%v"`, due)
+	}
+
+	// In ten minutes the source is due.
+	later := time.Now().Add(10 * time.Minute)
+	due, err = scheduleDao.ClaimDue(later, 10)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(due) != 1 || due[0].SourceId != source.ID {
+		t.Fatalf(`want the schedule of source "%d" to be due, got "%v"`, source.ID, due)
+	}
+
+	if due[0].Source.ID != source.ID || due[0].Tenant.Id != source.TenantID {
+		t.Errorf(`want source "%d" and tenant "%d" preloaded, got "%d" and "%d"`, source.ID, source.TenantID, due[0].Source.ID, due[0].Tenant.Id)
+	}
+
+	var schedule m.AvailabilitySchedule
+	err = DB.
+		Where("source_id = ?", source.ID).
+		First(&schedule).
+		Error
+
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if schedule.LastRunAt == nil || !schedule.LastRunAt.Equal(later.Truncate(time.Microsecond)) {
+		t.Errorf(`want last run "%s", got "%v"`, later, schedule.LastRunAt)
+	}
+
+	if !schedule.NextRunAt.After(later) {
+		t.Errorf(`want the next run to be after "%s", got "%s"`, later, schedule.NextRunAt)
+	}
+
+	// The claimed schedule isn't due again until its next run.
+	due, err = scheduleDao.ClaimDue(later, 10)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(due) != 0 {
+		t.Errorf(`want the claimed schedule not to be due again, got "%v"`, due)
+	}
+
+	DropSchema("availability_schedule_tests")
+}
+
+// TestAvailabilityScheduleClaimDueSkipsLocked tests that the schedules which are being claimed by someone else are
+// skipped instead of being claimed twice.
+func TestAvailabilityScheduleClaimDueSkipsLocked(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("availability_schedule_tests")
+
+	source := fixtures.TestSourceData[0]
+	scheduleDao := GetAvailabilityScheduleDao(context.Background(), &source.TenantID)
+
+	err := scheduleDao.Upsert(source.ID, "*/5 * * * *")
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	// Simulate another replica which is in the middle of claiming the schedule.
+	tx := DB.Begin()
+	defer tx.Rollback()
+
+	err = tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("source_id = ?", source.ID).
+		First(&m.AvailabilitySchedule{}).
+		Error
+
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	due, err := scheduleDao.ClaimDue(time.Now().Add(10*time.Minute), 10)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(due) != 0 {
+		t.Errorf(`want the locked schedule to be skipped, got "%v"`, due)
+	}
+
+	tx.Rollback()
+	DropSchema("availability_schedule_tests")
+}
+
+// TestAvailabilityScheduleUpsertInvalid tests that invalid cron expressions and other tenants' sources are rejected.
+func TestAvailabilityScheduleUpsertInvalid(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("availability_schedule_tests")
+
+	source := fixtures.TestSourceData[0]
+
+	err := GetAvailabilityScheduleDao(context.Background(), &source.TenantID).Upsert(source.ID, "every hour")
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	otherTenant := source.TenantID + 12345
+	err = GetAvailabilityScheduleDao(context.Background(), &otherTenant).Upsert(source.ID, "0 * * * *")
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("availability_schedule_tests")
+}
@@ -1,9 +1,11 @@
 package dao
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
 )
@@ -13,17 +15,30 @@ const (
 	DEFAULT_OFFSET = 0
 )
 
+// defaultPageSize is the number of records the lists return when they are not given a limit.
+var defaultPageSize = config.Get().DefaultPageSize
+
+// pageSize returns the given limit, or the default page size when the limit is zero or negative. Otherwise GORM
+// would either return nothing, or not limit the query at all.
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+
+	return limit
+}
+
 func GetFromResourceType(resourceType string) (m.EventModelDao, error) {
 	var resource m.EventModelDao
 	switch strings.ToLower(resourceType) {
 	case "source":
-		resource = GetSourceDao(nil)
+		resource = GetSourceDao(context.Background(), nil)
 	case "endpoint":
-		resource = GetEndpointDao(nil)
+		resource = GetEndpointDao(context.Background(), nil)
 	case "application":
-		resource = GetApplicationDao(nil)
+		resource = GetApplicationDao(context.Background(), nil)
 	case "authentication":
-		resource = GetAuthenticationDao(nil)
+		resource = GetAuthenticationDao(context.Background(), nil)
 	default:
 		return nil, fmt.Errorf("invalid resource_type (%s) to get DAO instance", resourceType)
 	}
@@ -38,7 +53,7 @@ func GetAvailabilityStatusFromStatusMessage(tenantID int64, resourceID string, r
 		if err != nil {
 			return "", err
 		}
-		resource, err := GetSourceDao(&tenantID).GetById(&recordID)
+		resource, err := GetSourceDao(context.Background(), &tenantID).GetById(&recordID)
 		if err != nil {
 			return "", err
 		}
@@ -48,7 +63,7 @@ func GetAvailabilityStatusFromStatusMessage(tenantID int64, resourceID string, r
 		if err != nil {
 			return "", err
 		}
-		resource, err := GetEndpointDao(&tenantID).GetById(&recordID)
+		resource, err := GetEndpointDao(context.Background(), &tenantID).GetById(&recordID)
 		if err != nil {
 			return "", err
 		}
@@ -58,13 +73,13 @@ func GetAvailabilityStatusFromStatusMessage(tenantID int64, resourceID string, r
 		if err != nil {
 			return "", err
 		}
-		resource, err := GetApplicationDao(&tenantID).GetById(&recordID)
+		resource, err := GetApplicationDao(context.Background(), &tenantID).GetById(&recordID)
 		if err != nil {
 			return "", err
 		}
 		return resource.AvailabilityStatus, err
 	case "Authentication":
-		resource, err := GetAuthenticationDao(&tenantID).GetById(resourceID)
+		resource, err := GetAuthenticationDao(context.Background(), &tenantID).GetById(resourceID)
 		if err != nil || resource.AvailabilityStatus == nil {
 			return "", err
 		}
@@ -110,7 +125,7 @@ func BulkMessageFromSource(source *m.Source, authentication *m.Authentication) (
 
 	bulkMessage["applications"] = applications
 
-	authDao := GetAuthenticationDao(&source.TenantID)
+	authDao := GetAuthenticationDao(context.Background(), &source.TenantID)
 	authenticationsByResource, err := authDao.AuthenticationsByResource(authentication)
 	if err != nil {
 		return nil, err
@@ -122,7 +137,7 @@ func BulkMessageFromSource(source *m.Source, authentication *m.Authentication) (
 		authentications[i] = authenticationsByResource[i].ToEvent()
 	}
 
-	applicationAuthenticationDao := GetApplicationAuthenticationDao(&source.TenantID)
+	applicationAuthenticationDao := GetApplicationAuthenticationDao(context.Background(), &source.TenantID)
 	applicationAuthenticationsFromResource, err := applicationAuthenticationDao.ApplicationAuthenticationsByResource(authentication.ResourceType, source.Applications, authenticationsByResource)
 
 	if err != nil {
@@ -0,0 +1,44 @@
+package dao
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// The compatibility matrix between the source types and the application types is stored in the application types'
+// "supported_source_types" column, which holds the names of the source types each application type supports.
+const (
+	// sourceTypeSupportedByApplicationType matches the source types supported by the given application type.
+	sourceTypeSupportedByApplicationType = `"source_types"."name" IN (SELECT jsonb_array_elements_text("application_types"."supported_source_types"::jsonb) FROM "application_types" WHERE "application_types"."id" = ?)`
+	// applicationTypeSupportingSourceType matches the application types which support the given source type.
+	applicationTypeSupportingSourceType = `EXISTS (SELECT 1 FROM "source_types" WHERE "source_types"."id" = ? AND "source_types"."name" IN (SELECT jsonb_array_elements_text("application_types"."supported_source_types"::jsonb)))`
+)
+
+// extractIdFilter removes the "equals" filter with the given name from the filters, and returns its parsed ID. A nil
+// ID is returned when the filter isn't present.
+func extractIdFilter(filters []util.Filter, name string) (*int64, []util.Filter, error) {
+	var id *int64
+	remaining := make([]util.Filter, 0, len(filters))
+
+	for _, filter := range filters {
+		if filter.Subresource != "" || filter.Name != name || (filter.Operation != "" && filter.Operation != "eq") {
+			remaining = append(remaining, filter)
+			continue
+		}
+
+		if len(filter.Value) != 1 {
+			return nil, nil, fmt.Errorf("the %q filter accepts a single value", name)
+		}
+
+		parsed, err := strconv.ParseInt(filter.Value[0], 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid ID %q for the %q filter", filter.Value[0], name)
+		}
+
+		id = &parsed
+	}
+
+	return id, remaining, nil
+}
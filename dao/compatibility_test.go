@@ -0,0 +1,151 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestExtractIdFilter tests that the ID filter is pulled out from the rest of the filters, and that invalid values
+// are rejected.
+func TestExtractIdFilter(t *testing.T) {
+	filters := []util.Filter{
+		{Name: "name", Value: []string{"amazon"}},
+		{Name: "application_type_id", Value: []string{"5"}},
+	}
+
+	id, remaining, err := extractIdFilter(filters, "application_type_id")
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if id == nil || *id != 5 {
+		t.Errorf(`want ID "5", got "%v"`, id)
+	}
+
+	if len(remaining) != 1 || remaining[0].Name != "name" {
+		t.Errorf(`want only the "name" filter to remain, got "%v"`, remaining)
+	}
+
+	id, _, err = extractIdFilter(filters[:1], "application_type_id")
+	if err != nil || id != nil {
+		t.Errorf(`want no ID and no error when the filter is missing, got "%v" and "%v"`, id, err)
+	}
+
+	invalid := [][]string{{"abc"}, {"1", "2"}}
+	for _, value := range invalid {
+		_, _, err = extractIdFilter([]util.Filter{{Name: "application_type_id", Value: value}}, "application_type_id")
+		if err == nil {
+			t.Errorf(`want an error for the value "%v", got none`, value)
+		}
+	}
+}
+
+// TestSourceTypeListCompatibleWithApplicationType tests that the source types can be filtered by the application
+// types that support them.
+func TestSourceTypeListCompatibleWithApplicationType(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("compatibility")
+
+	testCases := []struct {
+		appTypeId string
+		wantIds   []int64
+	}{
+		{appTypeId: "1", wantIds: []int64{1}},
+		{appTypeId: "2", wantIds: []int64{1, 2}},
+		{appTypeId: "12345", wantIds: []int64{}},
+	}
+
+	for _, tc := range testCases {
+		filters := []util.Filter{
+			{Name: "application_type_id", Value: []string{tc.appTypeId}},
+			{Operation: "sort_by", Value: []string{"id"}},
+		}
+
+		sourceTypes, count, err := GetSourceTypeDao(context.Background()).List(100, 0, filters)
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+
+		if int(count) != len(tc.wantIds) || len(sourceTypes) != len(tc.wantIds) {
+			t.Fatalf(`[application type "%s"] want "%d" source types, got "%d"`, tc.appTypeId, len(tc.wantIds), len(sourceTypes))
+		}
+
+		for i, sourceType := range sourceTypes {
+			if sourceType.Id != tc.wantIds[i] {
+				t.Errorf(`[application type "%s"] want source type "%d", got "%d"`, tc.appTypeId, tc.wantIds[i], sourceType.Id)
+			}
+		}
+	}
+
+	DropSchema("compatibility")
+}
+
+// TestApplicationTypeListCompatibleWithSourceType tests that the application types can be filtered by the source
+// types they support.
+func TestApplicationTypeListCompatibleWithSourceType(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("compatibility")
+
+	testCases := []struct {
+		sourceTypeId string
+		wantIds      []int64
+	}{
+		{sourceTypeId: "1", wantIds: []int64{1, 2}},
+		{sourceTypeId: "2", wantIds: []int64{2}},
+		{sourceTypeId: "100", wantIds: []int64{}},
+	}
+
+	for _, tc := range testCases {
+		filters := []util.Filter{
+			{Name: "source_type_id", Value: []string{tc.sourceTypeId}},
+			{Operation: "sort_by", Value: []string{"id"}},
+		}
+
+		appTypes, count, err := GetApplicationTypeDao(context.Background(), &fixtures.TestTenantData[0].Id).List(100, 0, filters)
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+
+		if int(count) != len(tc.wantIds) || len(appTypes) != len(tc.wantIds) {
+			t.Fatalf(`[source type "%s"] want "%d" application types, got "%d"`, tc.sourceTypeId, len(tc.wantIds), len(appTypes))
+		}
+
+		for i, appType := range appTypes {
+			if appType.Id != tc.wantIds[i] {
+				t.Errorf(`[source type "%s"] want application type "%d", got "%d"`, tc.sourceTypeId, tc.wantIds[i], appType.Id)
+			}
+		}
+	}
+
+	DropSchema("compatibility")
+}
+
+// TestListCompatibleTypes tests that both sides of the compatibility matrix are returned for a single type.
+func TestListCompatibleTypes(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("compatibility")
+
+	sourceTypes, err := GetApplicationTypeDao(context.Background(), &fixtures.TestTenantData[0].Id).ListCompatibleSourceTypes(2)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(sourceTypes) != 2 || sourceTypes[0].Name != "amazon" || sourceTypes[1].Name != "google" {
+		t.Errorf(`want the "amazon" and "google" source types, got "%v"`, sourceTypes)
+	}
+
+	appTypes, err := GetSourceTypeDao(context.Background()).ListCompatibleApplicationTypes(2)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(appTypes) != 1 || appTypes[0].Id != 2 {
+		t.Errorf(`want application type "2", got "%v"`, appTypes)
+	}
+
+	DropSchema("compatibility")
+}
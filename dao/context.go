@@ -0,0 +1,25 @@
+package dao
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// requestContext holds the context the DAO's queries are bound to, so that cancelling it —for example when the client
+// of a request disconnects— also cancels the DAO's in-flight database queries. It is meant to be embedded in the DAO
+// implementations, and it is set once, when the DAO gets built, so that a DAO never changes the context it is bound
+// to.
+type requestContext struct {
+	ctx context.Context
+}
+
+// db returns a database handle which is bound to the DAO's context, or the plain database handle if the DAO has
+// no context.
+func (rc *requestContext) db() *gorm.DB {
+	if rc.ctx == nil {
+		return DB
+	}
+
+	return DB.WithContext(rc.ctx)
+}
@@ -0,0 +1,56 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+)
+
+// TestGetDaoBindsContext tests that the context the DAO implementations are built with gets bound to them.
+func TestGetDaoBindsContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), struct{}{}, "request")
+
+	sourceDao, ok := getDefaultSourceDao(ctx, &fixtures.TestTenantData[0].Id).(*sourceDaoImpl)
+	if !ok {
+		t.Fatalf("want a source DAO implementation, got %T", sourceDao)
+	}
+
+	if sourceDao.ctx != ctx {
+		t.Errorf("want the context bound to the DAO, got %v", sourceDao.ctx)
+	}
+
+	rhcConnectionDao, ok := getDefaultRhcConnectionDao(ctx, &fixtures.TestTenantData[0].Id).(*instrumentedRhcConnectionDao)
+	if !ok {
+		t.Fatalf("want an instrumented rhc connection DAO, got %T", rhcConnectionDao)
+	}
+
+	if rhcConnectionDao.dao.(*rhcConnectionDaoImpl).ctx != ctx {
+		t.Errorf("want the context bound to the wrapped DAO, got %v", rhcConnectionDao.dao.(*rhcConnectionDaoImpl).ctx)
+	}
+}
+
+// TestGetDaoCancelsQuery tests that cancelling the context the DAO was built with cancels the query that is in
+// flight.
+func TestGetDaoCancelsQuery(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sourceDao := &sourceDaoImpl{requestContext: requestContext{ctx: ctx}, TenantID: &fixtures.TestTenantData[0].Id}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	err := sourceDao.db().Exec("SELECT pg_sleep(10)").Error
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf(`want "%s" error, got "%v"`, context.Canceled, err)
+	}
+
+}
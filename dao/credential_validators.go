@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// CredentialValidator validates the credentials of the applications of a given type. It returns whether the
+// credentials are valid, and the reason why they are not when they aren't. The error is only returned when the
+// validation itself could not be performed.
+type CredentialValidator interface {
+	Validate(ctx context.Context, auth *m.Authentication) (bool, string, error)
+}
+
+// CredentialValidatorRegistry maps the application type IDs to the validators of their credentials.
+type CredentialValidatorRegistry struct {
+	mutex      sync.RWMutex
+	validators map[int64]CredentialValidator
+}
+
+// NewCredentialValidatorRegistry returns an empty registry.
+func NewCredentialValidatorRegistry() *CredentialValidatorRegistry {
+	return &CredentialValidatorRegistry{validators: make(map[int64]CredentialValidator)}
+}
+
+// Register sets the validator for the given application type, replacing any previous one.
+func (cvr *CredentialValidatorRegistry) Register(applicationTypeId int64, validator CredentialValidator) {
+	cvr.mutex.Lock()
+	defer cvr.mutex.Unlock()
+
+	cvr.validators[applicationTypeId] = validator
+}
+
+// Get returns the validator for the given application type, if there is one.
+func (cvr *CredentialValidatorRegistry) Get(applicationTypeId int64) (CredentialValidator, bool) {
+	cvr.mutex.RLock()
+	defer cvr.mutex.RUnlock()
+
+	validator, ok := cvr.validators[applicationTypeId]
+	return validator, ok
+}
+
+// CredentialValidators is the registry used to validate the applications' credentials.
+var CredentialValidators = NewCredentialValidatorRegistry()
+
+// knownCredentialValidators holds the validators of the known application types, keyed by the application type name.
+var knownCredentialValidators = map[string]CredentialValidator{
+	"/insights/platform/cost-management": arnCredentialValidator{},
+	"/insights/platform/cloud-meter":     arnCredentialValidator{},
+}
+
+// RegisterKnownCredentialValidators registers the validators of the known application types. The application types'
+// IDs are fetched from the static type cache, so it must be called once the cache has been populated.
+func RegisterKnownCredentialValidators() {
+	for name, validator := range knownCredentialValidators {
+		applicationTypeId := Static.GetApplicationTypeId(name)
+		if applicationTypeId == 0 {
+			continue
+		}
+
+		CredentialValidators.Register(applicationTypeId, validator)
+	}
+}
+
+// arnRegex matches the ARNs of the AWS IAM roles.
+var arnRegex = regexp.MustCompile(`^arn:aws(-[a-z]+)*:iam::\d{12}:role/.+$`)
+
+// arnCredentialValidator validates the credentials which hold an AWS IAM role's ARN in their username.
+type arnCredentialValidator struct{}
+
+func (arnCredentialValidator) Validate(_ context.Context, auth *m.Authentication) (bool, string, error) {
+	if auth.Username == nil || *auth.Username == "" {
+		return false, "the ARN is missing", nil
+	}
+
+	if !arnRegex.MatchString(*auth.Username) {
+		return false, fmt.Sprintf("%q is not a valid IAM role ARN", *auth.Username), nil
+	}
+
+	return true, "", nil
+}
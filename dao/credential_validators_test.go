@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestCredentialValidatorRegistry tests that the validators are returned for the application types they were
+// registered for.
+func TestCredentialValidatorRegistry(t *testing.T) {
+	registry := NewCredentialValidatorRegistry()
+	registry.Register(1, arnCredentialValidator{})
+
+	if _, ok := registry.Get(1); !ok {
+		t.Errorf(`want a validator for the application type "1", got none`)
+	}
+
+	if _, ok := registry.Get(2); ok {
+		t.Errorf(`want no validator for the application type "2", got one`)
+	}
+}
+
+// TestArnCredentialValidator tests that only the well formed IAM role ARNs are considered valid.
+func TestArnCredentialValidator(t *testing.T) {
+	testCases := []struct {
+		username *string
+		valid    bool
+	}{
+		{username: util.StringRef("arn:aws:iam::123456789012:role/CostManagement"), valid: true},
+		{username: util.StringRef("arn:aws-us-gov:iam::123456789012:role/path/CostManagement"), valid: true},
+		{username: util.StringRef("arn:aws:iam::1234:role/CostManagement"), valid: false},
+		{username: util.StringRef("arn:aws:s3:::bucket"), valid: false},
+		{username: util.StringRef(""), valid: false},
+		{username: nil, valid: false},
+	}
+
+	for _, tc := range testCases {
+		valid, reason, err := arnCredentialValidator{}.Validate(context.Background(), &m.Authentication{Username: tc.username})
+		if err != nil {
+			t.Errorf(`want no error, got "%s"`, err)
+		}
+
+		if valid != tc.valid {
+			t.Errorf(`want valid "%t" for "%v", got "%t"`, tc.valid, util.ValueOrBlank(tc.username), valid)
+		}
+
+		if !valid && reason == "" {
+			t.Errorf(`want a reason for the invalid credentials, got none`)
+		}
+	}
+}
+
+// TestSupportedAuthTypes tests that the authentication types are taken from the source type's entry of the
+// application type.
+func TestSupportedAuthTypes(t *testing.T) {
+	applicationType := m.ApplicationType{
+		Name:                         "/insights/platform/cost-management",
+		SupportedAuthenticationTypes: []byte(`{"amazon": ["arn"], "azure": ["tenant_id_client_id_client_secret"]}`),
+	}
+
+	got, err := supportedAuthTypes(&applicationType, "amazon")
+	if err != nil {
+		t.Fatalf(`want no errors, got "%s"`, err)
+	}
+
+	if len(got) != 1 || got[0] != "arn" {
+		t.Errorf(`want the "[arn]" authentication types, got "%v"`, got)
+	}
+
+	got, err = supportedAuthTypes(&applicationType, "google")
+	if err != nil || len(got) != 0 {
+		t.Errorf(`want no authentication types and no errors for an unsupported source type, got "%v" and "%v"`, got, err)
+	}
+
+	applicationType.SupportedAuthenticationTypes = []byte(`["arn"]`)
+	if _, err := supportedAuthTypes(&applicationType, "amazon"); err == nil {
+		t.Errorf(`want an error for malformed supported authentication types, got none`)
+	}
+}
@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"context"
+	"errors"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+)
+
+// GetCyndiStatusDao is a function definition that can be replaced in runtime in case some other DAO provider is
+// needed.
+var GetCyndiStatusDao func(context.Context) CyndiStatusDao
+
+// getDefaultCyndiStatusDao gets the default DAO implementation.
+func getDefaultCyndiStatusDao(ctx context.Context) CyndiStatusDao {
+	return &cyndiStatusDaoImpl{requestContext: requestContext{ctx: ctx}}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetCyndiStatusDao = getDefaultCyndiStatusDao
+}
+
+// ErrCyndiNotConfigured is returned when the database doesn't have the Cyndi replication slot, which happens in the
+// environments where the pipeline isn't deployed.
+var ErrCyndiNotConfigured = errors.New("the Cyndi replication slot does not exist")
+
+// ErrCyndiInactive is returned when nothing is consuming the Cyndi replication slot, which means that the pipeline
+// stopped syncing the data altogether.
+var ErrCyndiInactive = errors.New("the Cyndi replication slot has no consumer")
+
+type cyndiStatusDaoImpl struct {
+	requestContext
+}
+
+func (c *cyndiStatusDaoImpl) GetReplicationLag() (int64, error) {
+	// The lag is the amount of WAL the pipeline hasn't confirmed yet. The replication slots view only holds a row per
+	// slot, so the probe doesn't depend on the size of any table.
+	var slots []struct {
+		Active   bool
+		LagBytes int64
+	}
+
+	err := c.db().
+		Raw(`SELECT "active", COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), "confirmed_flush_lsn"), 0)::BIGINT AS "lag_bytes" FROM "pg_replication_slots" WHERE "slot_name" = ?`, config.Get().CyndiReplicationSlot).
+		Scan(&slots).
+		Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(slots) == 0 {
+		return 0, ErrCyndiNotConfigured
+	}
+
+	if !slots[0].Active {
+		return 0, ErrCyndiInactive
+	}
+
+	return slots[0].LagBytes, nil
+}
@@ -0,0 +1,23 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+)
+
+// TestGetReplicationLagNotConfigured tests that a specific error is returned when the database doesn't have the
+// Cyndi replication slot.
+func TestGetReplicationLagNotConfigured(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("cyndi_status")
+
+	_, err := GetCyndiStatusDao(context.Background()).GetReplicationLag()
+	if !errors.Is(err, ErrCyndiNotConfigured) {
+		t.Errorf(`want the "not configured" error, got "%v"`, err)
+	}
+
+	DropSchema("cyndi_status")
+}
@@ -0,0 +1,84 @@
+package dao
+
+// DaoHook gets called before and after the DAO operations which modify records, so that the business logic —such as
+// clearing caches or publishing events— can be attached to the operations themselves instead of being scattered
+// through the handlers. The hooks run inside the operation's transaction, so returning an error from any of them
+// aborts the operation and rolls back its changes.
+type DaoHook interface {
+	BeforeCreate(record interface{}) error
+	AfterCreate(record interface{}) error
+	BeforeUpdate(record interface{}) error
+	AfterUpdate(record interface{}) error
+	BeforeDelete(record interface{}) error
+	AfterDelete(record interface{}) error
+}
+
+// BaseDaoHook implements every DaoHook method as a no-op, so that the hooks can embed it and only implement the
+// methods they are interested in.
+type BaseDaoHook struct{}
+
+func (BaseDaoHook) BeforeCreate(_ interface{}) error { return nil }
+func (BaseDaoHook) AfterCreate(_ interface{}) error  { return nil }
+func (BaseDaoHook) BeforeUpdate(_ interface{}) error { return nil }
+func (BaseDaoHook) AfterUpdate(_ interface{}) error  { return nil }
+func (BaseDaoHook) BeforeDelete(_ interface{}) error { return nil }
+func (BaseDaoHook) AfterDelete(_ interface{}) error  { return nil }
+
+// runHooks calls the given hook method on every hook, in the order they were registered. It stops at the first error
+// and returns it.
+func runHooks(hooks []DaoHook, call func(hook DaoHook) error) error {
+	for _, hook := range hooks {
+		err := call(hook)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CacheInvalidationHook evicts the cached entries of the records once they have been created, updated or deleted.
+type CacheInvalidationHook struct {
+	BaseDaoHook
+	// Invalidate evicts the cached entries of the given record.
+	Invalidate func(record interface{}) error
+}
+
+func (c CacheInvalidationHook) AfterCreate(record interface{}) error {
+	return c.Invalidate(record)
+}
+
+func (c CacheInvalidationHook) AfterUpdate(record interface{}) error {
+	return c.Invalidate(record)
+}
+
+func (c CacheInvalidationHook) AfterDelete(record interface{}) error {
+	return c.Invalidate(record)
+}
+
+// DaoEventPublisher publishes the events of the records modified by the DAOs.
+type DaoEventPublisher interface {
+	Publish(eventType string, record interface{}) error
+}
+
+// KafkaPublishHook publishes a "<ResourceType>.create", "<ResourceType>.update" or "<ResourceType>.destroy" event
+// for the records once they have been created, updated or deleted. Since the hooks run before the transaction is
+// committed, the publisher should only fail when the event couldn't be delivered.
+type KafkaPublishHook struct {
+	BaseDaoHook
+	// ResourceType is the prefix of the published event types, such as "RhcConnection".
+	ResourceType string
+	Publisher    DaoEventPublisher
+}
+
+func (k KafkaPublishHook) AfterCreate(record interface{}) error {
+	return k.Publisher.Publish(k.ResourceType+".create", record)
+}
+
+func (k KafkaPublishHook) AfterUpdate(record interface{}) error {
+	return k.Publisher.Publish(k.ResourceType+".update", record)
+}
+
+func (k KafkaPublishHook) AfterDelete(record interface{}) error {
+	return k.Publisher.Publish(k.ResourceType+".destroy", record)
+}
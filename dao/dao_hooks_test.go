@@ -0,0 +1,157 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// recordingHook records the hook methods it gets called with, and fails the ones present in "failOn".
+type recordingHook struct {
+	name   string
+	calls  *[]string
+	failOn map[string]bool
+}
+
+func (r recordingHook) record(method string) error {
+	*r.calls = append(*r.calls, r.name+"."+method)
+	if r.failOn[method] {
+		return errors.New(r.name + " failed on " + method)
+	}
+
+	return nil
+}
+
+func (r recordingHook) BeforeCreate(_ interface{}) error { return r.record("BeforeCreate") }
+func (r recordingHook) AfterCreate(_ interface{}) error  { return r.record("AfterCreate") }
+func (r recordingHook) BeforeUpdate(_ interface{}) error { return r.record("BeforeUpdate") }
+func (r recordingHook) AfterUpdate(_ interface{}) error  { return r.record("AfterUpdate") }
+func (r recordingHook) BeforeDelete(_ interface{}) error { return r.record("BeforeDelete") }
+func (r recordingHook) AfterDelete(_ interface{}) error  { return r.record("AfterDelete") }
+
+// TestRunHooks tests that the hooks are called in the order they were registered, and that the first error stops the
+// rest of the hooks from being called.
+func TestRunHooks(t *testing.T) {
+	var calls []string
+	hooks := []DaoHook{
+		recordingHook{name: "first", calls: &calls},
+		recordingHook{name: "second", calls: &calls, failOn: map[string]bool{"AfterCreate": true}},
+		recordingHook{name: "third", calls: &calls},
+	}
+
+	err := runHooks(hooks, func(hook DaoHook) error { return hook.BeforeCreate(nil) })
+	if err != nil {
+		t.Errorf(`want no error, got "%s"`, err)
+	}
+
+	err = runHooks(hooks, func(hook DaoHook) error { return hook.AfterCreate(nil) })
+	if err == nil {
+		t.Errorf(`want an error, got none`)
+	}
+
+	want := []string{"first.BeforeCreate", "second.BeforeCreate", "third.BeforeCreate", "first.AfterCreate", "second.AfterCreate"}
+	if !reflect.DeepEqual(want, calls) {
+		t.Errorf(`want calls "%v", got "%v"`, want, calls)
+	}
+}
+
+// recordingPublisher records the event types it publishes.
+type recordingPublisher struct {
+	eventTypes []string
+}
+
+func (r *recordingPublisher) Publish(eventType string, _ interface{}) error {
+	r.eventTypes = append(r.eventTypes, eventType)
+	return nil
+}
+
+// TestExampleHooks tests that the cache invalidation and the Kafka publishing hooks only act after the records have
+// been modified.
+func TestExampleHooks(t *testing.T) {
+	record := &m.RhcConnection{ID: 1}
+
+	var invalidated []interface{}
+	cacheHook := CacheInvalidationHook{Invalidate: func(record interface{}) error {
+		invalidated = append(invalidated, record)
+		return nil
+	}}
+
+	publisher := &recordingPublisher{}
+	kafkaHook := KafkaPublishHook{ResourceType: "RhcConnection", Publisher: publisher}
+
+	for _, hook := range []DaoHook{cacheHook, kafkaHook} {
+		for _, call := range []func(interface{}) error{hook.BeforeCreate, hook.AfterCreate, hook.BeforeUpdate, hook.AfterUpdate, hook.BeforeDelete, hook.AfterDelete} {
+			err := call(record)
+			if err != nil {
+				t.Errorf(`want no error, got "%s"`, err)
+			}
+		}
+	}
+
+	if len(invalidated) != 3 {
+		t.Errorf(`want the cache to be invalidated three times, got "%d"`, len(invalidated))
+	}
+
+	want := []string{"RhcConnection.create", "RhcConnection.update", "RhcConnection.destroy"}
+	if !reflect.DeepEqual(want, publisher.eventTypes) {
+		t.Errorf(`want events "%v", got "%v"`, want, publisher.eventTypes)
+	}
+}
+
+// TestRhcConnectionHookErrorsRollBack tests that a failing hook aborts the operation and rolls back its changes.
+func TestRhcConnectionHookErrorsRollBack(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("dao_hooks")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	sourceId := fixtures.TestSourceData[0].ID
+
+	var calls []string
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+	rhcConnectionDao.RegisterHook(recordingHook{name: "hook", calls: &calls, failOn: map[string]bool{"AfterCreate": true, "AfterDelete": true}})
+
+	// The creation is rolled back.
+	rhcConnection := &m.RhcConnection{RhcId: "hooked-rhc-id", Sources: []m.Source{{ID: sourceId}}}
+	_, err := rhcConnectionDao.Create(rhcConnection)
+	if err == nil {
+		t.Fatalf(`want an error from the hook, got none`)
+	}
+
+	var count int64
+	err = DB.Model(&m.RhcConnection{}).Where("rhc_id = ?", "hooked-rhc-id").Count(&count).Error
+	if err != nil {
+		t.Fatalf(`could not count the connections: %s`, err)
+	}
+
+	if count != 0 {
+		t.Errorf(`want the creation to be rolled back, got "%d" connections`, count)
+	}
+
+	// The deletion is rolled back.
+	existingId := fixtures.TestRhcConnectionData[0].ID
+	_, err = rhcConnectionDao.Delete(&existingId, false)
+	if err == nil {
+		t.Fatalf(`want an error from the hook, got none`)
+	}
+
+	err = DB.Model(&m.RhcConnection{}).Where("id = ?", existingId).Count(&count).Error
+	if err != nil {
+		t.Fatalf(`could not count the connections: %s`, err)
+	}
+
+	if count != 1 {
+		t.Errorf(`want the deletion to be rolled back, got "%d" connections`, count)
+	}
+
+	want := []string{"hook.BeforeCreate", "hook.AfterCreate", "hook.BeforeDelete", "hook.AfterDelete"}
+	if !reflect.DeepEqual(want, calls) {
+		t.Errorf(`want calls "%v", got "%v"`, want, calls)
+	}
+
+	DropSchema("dao_hooks")
+}
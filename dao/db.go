@@ -29,8 +29,10 @@ func Init() {
 	l := &logging.CustomGORMLogger{
 		SkipErrorRecordNotFound: true,
 		Logger:                  logging.Log,
-		SlowThreshold:           time.Duration(conf.SlowSQLThreshold) * time.Second,
+		SlowThreshold:           time.Duration(conf.SlowQueryMs) * time.Millisecond,
 		LogLevelForSqlLogs:      conf.LogLevelForSqlLogs,
+		LogAllQueries:           conf.LogAllSqlQueries,
+		MaskSensitiveFields:     conf.MaskSensitiveFields,
 	}
 
 	// Reset the database if the command flag was provided.
@@ -93,6 +95,12 @@ func Init() {
 	}
 	rawDB.SetMaxOpenConns(20)
 
+	// Track the in-flight operations so that they can be drained on shutdown.
+	err = registerOperationTracking(DB)
+	if err != nil {
+		panic(err)
+	}
+
 	// Perform database migrations.
 	migrations.Migrate(DB)
 
@@ -126,6 +134,15 @@ func Init() {
 	if err != nil {
 		logging.Log.Fatalf("Failed to populate static type cache: %v", err)
 	}
+
+	// The credential validators are registered by their application types' IDs, which come from the cache.
+	RegisterKnownCredentialValidators()
+
+	// Guard the writes once the migrations and the seeding are done, since these must still run in read-only mode.
+	err = registerReadOnlyGuard(DB)
+	if err != nil {
+		panic(err)
+	}
 }
 
 func dbString() string {
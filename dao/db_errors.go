@@ -0,0 +1,30 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/jackc/pgconn"
+)
+
+// sourcesExternalIdIndex is the name of the unique index of the sources' external IDs.
+const sourcesExternalIdIndex = "index_sources_on_tenant_id_and_external_id"
+
+// sourcesNameIndex is the name of the unique index of the sources' case insensitive names.
+const sourcesNameIndex = "index_sources_on_lower_name_and_tenant_id"
+
+// rhcConnectionsRhcIdIndex is the name of the unique index of the connections' rhc_ids within each tenant.
+const rhcConnectionsRhcIdIndex = "index_rhc_connections_on_tenant_id_and_rhc_id"
+
+// uniqueViolationCode is the PostgreSQL error code for the "unique_violation" errors.
+const uniqueViolationCode = "23505"
+
+// isUniqueViolation returns true when the given error was raised by PostgreSQL because the given unique constraint
+// was violated.
+func isUniqueViolation(err error, constraintName string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == constraintName
+}
@@ -0,0 +1,31 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgconn"
+)
+
+// TestIsUniqueViolation tests that only the unique violations of the given constraint are detected.
+func TestIsUniqueViolation(t *testing.T) {
+	testData := []struct {
+		Err  error
+		Want bool
+	}{
+		{Err: &pgconn.PgError{Code: uniqueViolationCode, ConstraintName: sourcesExternalIdIndex}, Want: true},
+		{Err: fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: uniqueViolationCode, ConstraintName: sourcesExternalIdIndex}), Want: true},
+		{Err: &pgconn.PgError{Code: uniqueViolationCode, ConstraintName: "other_constraint"}, Want: false},
+		{Err: &pgconn.PgError{Code: "23503", ConstraintName: sourcesExternalIdIndex}, Want: false},
+		{Err: errors.New("some other error"), Want: false},
+		{Err: nil, Want: false},
+	}
+
+	for _, tt := range testData {
+		got := isUniqueViolation(tt.Err, sourcesExternalIdIndex)
+		if tt.Want != got {
+			t.Errorf(`unexpected result for error "%v". Want "%t", got "%t"`, tt.Err, tt.Want, got)
+		}
+	}
+}
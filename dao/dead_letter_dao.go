@@ -0,0 +1,151 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetDeadLetterDao is a function definition that can be replaced in runtime in case some other DAO provider is
+// needed.
+var GetDeadLetterDao func(context.Context, *int64) DeadLetterDao
+
+// getDefaultDeadLetterDao gets the default DAO implementation which will have the given tenant ID.
+func getDefaultDeadLetterDao(ctx context.Context, tenantId *int64) DeadLetterDao {
+	return &deadLetterDaoImpl{
+		requestContext: requestContext{ctx: ctx},
+		TenantID:       tenantId,
+	}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetDeadLetterDao = getDefaultDeadLetterDao
+}
+
+// publishDeadLetter sends the dead letter message to its topic again. It is a variable so that the tests can replace
+// it, since they don't have a broker.
+var publishDeadLetter = func(deadLetter *m.DeadLetterMessage) error {
+	headers, err := deadLetter.KafkaHeaders()
+	if err != nil {
+		return fmt.Errorf("could not read the headers of the dead letter message: %w", err)
+	}
+
+	producerConfig := kafka.ProducerConfig{Topic: config.Get().KafkaTopic(deadLetter.Topic)}
+	manager := &kafka.Manager{Config: kafka.Config{KafkaBrokers: config.Get().KafkaBrokers, ProducerConfig: producerConfig}}
+
+	message := &kafka.Message{}
+	message.AddHeaders(headers)
+	message.AddValue(deadLetter.Payload)
+
+	err = manager.Produce(message)
+	if err != nil {
+		return err
+	}
+
+	return manager.Producer().Close()
+}
+
+type deadLetterDaoImpl struct {
+	TenantID *int64
+	requestContext
+}
+
+func (d *deadLetterDaoImpl) List(topic, status string, limit, offset int) ([]m.DeadLetterMessage, int64, error) {
+	if status != "" && !util.SliceContainsString(m.DeadLetterStatuses, status) {
+		return nil, 0, util.NewErrBadRequest(fmt.Sprintf(`invalid status "%s", expected any of "%s"`, status, strings.Join(m.DeadLetterStatuses, ", ")))
+	}
+
+	query := d.db().
+		Model(&m.DeadLetterMessage{}).
+		Where("tenant_id = ?", d.TenantID)
+
+	if topic != "" {
+		query = query.Where("topic = ?", topic)
+	}
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deadLetters := make([]m.DeadLetterMessage, 0, limit)
+	err = query.
+		Order("id").
+		Limit(limit).
+		Offset(offset).
+		Find(&deadLetters).
+		Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deadLetters, count, nil
+}
+
+func (d *deadLetterDaoImpl) Create(deadLetter *m.DeadLetterMessage) error {
+	deadLetter.TenantId = *d.TenantID
+	if deadLetter.Status == "" {
+		deadLetter.Status = m.DeadLetterPending
+	}
+
+	return d.db().
+		Omit(clause.Associations).
+		Create(deadLetter).
+		Error
+}
+
+func (d *deadLetterDaoImpl) Requeue(id int64) (*m.DeadLetterMessage, error) {
+	var deadLetter m.DeadLetterMessage
+	err := d.db().
+		Where("id = ?", id).
+		Where("tenant_id = ?", d.TenantID).
+		First(&deadLetter).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, util.NewErrNotFound("dead letter message")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = publishDeadLetter(&deadLetter)
+	if err != nil {
+		return nil, fmt.Errorf("could not replay the dead letter message: %w", err)
+	}
+
+	now := time.Now()
+	err = d.db().
+		Model(&deadLetter).
+		Updates(map[string]interface{}{
+			"status":      m.DeadLetterReplayed,
+			"requeued_at": now,
+		}).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetter.Status = m.DeadLetterReplayed
+	deadLetter.RequeuedAt = &now
+
+	return &deadLetter, nil
+}
@@ -0,0 +1,124 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestDeadLetterList tests that the tenant's dead letter messages are listed filtered by their topic and status.
+func TestDeadLetterList(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("dead_letters")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	deadLetterDao := GetDeadLetterDao(context.Background(), &tenantId)
+
+	deadLetters := []m.DeadLetterMessage{
+		{Topic: "platform.sources.event-stream", EventType: "Source.create", Payload: []byte(`{}`)},
+		{Topic: "platform.sources.event-stream", EventType: "Source.update", Payload: []byte(`{}`), Status: m.DeadLetterReplayed},
+		{Topic: "platform.sources.status", EventType: "availability_status", Payload: []byte(`{}`)},
+	}
+
+	for i := range deadLetters {
+		err := deadLetterDao.Create(&deadLetters[i])
+		if err != nil {
+			t.Fatalf(`could not create the dead letter message: %s`, err)
+		}
+	}
+
+	got, count, err := deadLetterDao.List("platform.sources.event-stream", m.DeadLetterPending, 10, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 1 || len(got) != 1 || got[0].ID != deadLetters[0].ID {
+		t.Errorf(`want only the dead letter "%d", got "%v"`, deadLetters[0].ID, got)
+	}
+
+	_, count, err = deadLetterDao.List("", "", 10, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != int64(len(deadLetters)) {
+		t.Errorf(`want "%d" dead letters, got "%d"`, len(deadLetters), count)
+	}
+
+	_, _, err = deadLetterDao.List("", "lost", 10, 0)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	otherTenant := tenantId + 12345
+	_, count, err = GetDeadLetterDao(context.Background(), &otherTenant).List("", "", 10, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 0 {
+		t.Errorf(`want no dead letters for another tenant, got "%d"`, count)
+	}
+
+	DropSchema("dead_letters")
+}
+
+// TestDeadLetterRequeue tests that the requeued messages get published again and marked as replayed, and that other
+// tenants' messages are not found.
+func TestDeadLetterRequeue(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("dead_letters")
+
+	backupPublish := publishDeadLetter
+	defer func() { publishDeadLetter = backupPublish }()
+
+	var published []int64
+	publishDeadLetter = func(deadLetter *m.DeadLetterMessage) error {
+		published = append(published, deadLetter.ID)
+		return nil
+	}
+
+	tenantId := fixtures.TestTenantData[0].Id
+	deadLetterDao := GetDeadLetterDao(context.Background(), &tenantId)
+
+	deadLetter := m.DeadLetterMessage{Topic: "platform.sources.event-stream", EventType: "Source.create", Payload: []byte(`{}`)}
+	err := deadLetterDao.Create(&deadLetter)
+	if err != nil {
+		t.Fatalf(`could not create the dead letter message: %s`, err)
+	}
+
+	otherTenant := tenantId + 12345
+	_, err = GetDeadLetterDao(context.Background(), &otherTenant).Requeue(deadLetter.ID)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	requeued, err := deadLetterDao.Requeue(deadLetter.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(published) != 1 || published[0] != deadLetter.ID {
+		t.Errorf(`want the dead letter "%d" to be published once, got "%v"`, deadLetter.ID, published)
+	}
+
+	if requeued.Status != m.DeadLetterReplayed || requeued.RequeuedAt == nil {
+		t.Errorf(`want a replayed dead letter with a requeue date, got "%+v"`, requeued)
+	}
+
+	replayed, _, err := deadLetterDao.List("", m.DeadLetterReplayed, 10, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(replayed) != 1 || replayed[0].RequeuedAt == nil {
+		t.Errorf(`want the dead letter to be stored as replayed, got "%v"`, replayed)
+	}
+
+	DropSchema("dead_letters")
+}
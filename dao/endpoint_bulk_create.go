@@ -0,0 +1,112 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"gorm.io/gorm"
+)
+
+// BulkError is the validation error of one of the resources of a bulk operation, identified by its position in the
+// request.
+type BulkError struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// EndpointCreatedPublisher publishes the "endpoint.created" events.
+type EndpointCreatedPublisher interface {
+	PublishEndpointCreated(endpoint *m.Endpoint) error
+}
+
+// EndpointPublisher is the publisher used when the endpoints get bulk created. It can be replaced in runtime to either
+// plug in a real publisher or a mocked one for the tests. When nil, no events are published.
+var EndpointPublisher EndpointCreatedPublisher
+
+func (a *endpointDaoImpl) BulkCreate(ctx context.Context, endpoints []m.Endpoint, tenantId int64) ([]m.Endpoint, []BulkError, error) {
+	db := a.db().WithContext(ctx)
+
+	created := make([]m.Endpoint, len(endpoints))
+	copy(created, endpoints)
+
+	// Check the ownership of every endpoint's source before opening the transaction, so that all the errors are
+	// reported at once.
+	sourceIds := make([]int64, 0, len(created))
+	for i := range created {
+		created[i].TenantID = tenantId
+		sourceIds = append(sourceIds, created[i].SourceID)
+	}
+
+	ownedSourceIds := make([]int64, 0)
+	if len(sourceIds) > 0 {
+		err := db.
+			Model(&m.Source{}).
+			Where("id IN ?", sourceIds).
+			Where("tenant_id = ?", tenantId).
+			Pluck("id", &ownedSourceIds).
+			Error
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	owned := make(map[int64]bool, len(ownedSourceIds))
+	for _, id := range ownedSourceIds {
+		owned[id] = true
+	}
+
+	bulkErrors := make([]BulkError, 0)
+	for i := range created {
+		if !owned[created[i].SourceID] {
+			bulkErrors = append(bulkErrors, BulkError{Index: i, Message: fmt.Sprintf("source %d not found", created[i].SourceID)})
+		}
+	}
+
+	if len(bulkErrors) > 0 {
+		return nil, bulkErrors, nil
+	}
+
+	if len(created) == 0 {
+		return created, nil, nil
+	}
+
+	err := transaction(db, func(tx *gorm.DB) error {
+		return tx.Create(&created).Error
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publishEndpointsCreated(db, created, tenantId)
+
+	return created, nil, nil
+}
+
+// publishEndpointsCreated publishes an "endpoint.created" event for each of the given endpoints, along with their
+// tenant. The endpoints have already been committed at this point, so the errors are only logged.
+func publishEndpointsCreated(db *gorm.DB, endpoints []m.Endpoint, tenantId int64) {
+	publisher := EndpointPublisher
+	if publisher == nil {
+		return
+	}
+
+	var tenant m.Tenant
+	err := db.Where("id = ?", tenantId).First(&tenant).Error
+	if err != nil {
+		logging.Log.Errorf(`Unable to fetch tenant "%d" to publish the created endpoints: %s`, tenantId, err)
+		return
+	}
+
+	for i := range endpoints {
+		endpoints[i].Tenant = tenant
+
+		err = publisher.PublishEndpointCreated(&endpoints[i])
+		if err != nil {
+			logging.Log.Errorf(`Unable to publish the creation of endpoint "%d": %s`, endpoints[i].ID, err)
+		}
+	}
+}
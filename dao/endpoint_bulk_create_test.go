@@ -0,0 +1,120 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// mockEndpointCreatedPublisher records the endpoints it's asked to publish.
+type mockEndpointCreatedPublisher struct {
+	published []m.Endpoint
+}
+
+func (p *mockEndpointCreatedPublisher) PublishEndpointCreated(endpoint *m.Endpoint) error {
+	p.published = append(p.published, *endpoint)
+	return nil
+}
+
+// TestEndpointBulkCreate tests that all the endpoints get created, and that an event gets published for each of them.
+func TestEndpointBulkCreate(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("endpoint_bulk_create")
+
+	originalPublisher := EndpointPublisher
+	publisher := &mockEndpointCreatedPublisher{}
+	EndpointPublisher = publisher
+	defer func() { EndpointPublisher = originalPublisher }()
+
+	source := fixtures.TestSourceData[0]
+	first, second := "first.example.com", "second.example.com"
+
+	endpoints := []m.Endpoint{
+		{SourceID: source.ID, Host: &first},
+		{SourceID: source.ID, Host: &second},
+	}
+
+	created, bulkErrors, err := GetEndpointDao(context.Background(), &source.TenantID).BulkCreate(context.Background(), endpoints, source.TenantID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(bulkErrors) != 0 {
+		t.Fatalf(`want no validation errors, got "%v"`, bulkErrors)
+	}
+
+	if len(created) != len(endpoints) {
+		t.Fatalf(`want "%d" created endpoints, got "%d"`, len(endpoints), len(created))
+	}
+
+	for _, endpoint := range created {
+		if endpoint.ID == 0 || endpoint.TenantID != source.TenantID {
+			t.Errorf(`want a created endpoint of tenant "%d", got "%+v"`, source.TenantID, endpoint)
+		}
+	}
+
+	if len(publisher.published) != len(endpoints) {
+		t.Errorf(`want "%d" published events, got "%d"`, len(endpoints), len(publisher.published))
+	}
+
+	for _, endpoint := range publisher.published {
+		if endpoint.Tenant.Id != source.TenantID {
+			t.Errorf(`want the published endpoint to carry tenant "%d", got "%d"`, source.TenantID, endpoint.Tenant.Id)
+		}
+	}
+
+	DropSchema("endpoint_bulk_create")
+}
+
+// TestEndpointBulkCreateInvalid tests that nothing gets created, and no events get published, when any of the
+// endpoints belongs to a source the tenant doesn't own.
+func TestEndpointBulkCreateInvalid(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("endpoint_bulk_create")
+
+	originalPublisher := EndpointPublisher
+	publisher := &mockEndpointCreatedPublisher{}
+	EndpointPublisher = publisher
+	defer func() { EndpointPublisher = originalPublisher }()
+
+	source := fixtures.TestSourceData[0]
+	host := "example.com"
+
+	var countBefore int64
+	DB.Model(&m.Endpoint{}).Count(&countBefore)
+
+	endpoints := []m.Endpoint{
+		{SourceID: source.ID, Host: &host},
+		{SourceID: 12345, Host: &host},
+		{SourceID: fixtures.TestSourceData[0].ID + 54321, Host: &host},
+	}
+
+	created, bulkErrors, err := GetEndpointDao(context.Background(), &source.TenantID).BulkCreate(context.Background(), endpoints, source.TenantID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if created != nil {
+		t.Errorf(`want no created endpoints, got "%v"`, created)
+	}
+
+	if len(bulkErrors) != 2 || bulkErrors[0].Index != 1 || bulkErrors[1].Index != 2 {
+		t.Errorf(`want the errors of endpoints 1 and 2, got "%v"`, bulkErrors)
+	}
+
+	var countAfter int64
+	DB.Model(&m.Endpoint{}).Count(&countAfter)
+
+	if countAfter != countBefore {
+		t.Errorf(`want "%d" endpoints, got "%d"`, countBefore, countAfter)
+	}
+
+	if len(publisher.published) != 0 {
+		t.Errorf(`want no published events, got "%d"`, len(publisher.published))
+	}
+
+	DropSchema("endpoint_bulk_create")
+}
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -11,12 +12,13 @@ import (
 
 // GetEndpointDao is a function definition that can be replaced in runtime in case some other DAO provider is
 // needed.
-var GetEndpointDao func(*int64) EndpointDao
+var GetEndpointDao func(context.Context, *int64) EndpointDao
 
 // getDefaultAuthenticationDao gets the default DAO implementation which will have the given tenant ID.
-func getDefaultEndpointDao(tenantId *int64) EndpointDao {
+func getDefaultEndpointDao(ctx context.Context, tenantId *int64) EndpointDao {
 	return &endpointDaoImpl{
-		TenantID: tenantId,
+		requestContext: requestContext{ctx: ctx},
+		TenantID:       tenantId,
 	}
 }
 
@@ -27,16 +29,17 @@ func init() {
 
 type endpointDaoImpl struct {
 	TenantID *int64
+	requestContext
 }
 
 func (a *endpointDaoImpl) SubCollectionList(primaryCollection interface{}, limit int, offset int, filters []util.Filter) ([]m.Endpoint, int64, error) {
 	endpoints := make([]m.Endpoint, 0, limit)
-	relationObject, err := m.NewRelationObject(primaryCollection, *a.TenantID, DB.Debug())
+	relationObject, err := m.NewRelationObject(primaryCollection, *a.TenantID, a.db())
 	if err != nil {
 		return nil, 0, util.NewErrNotFound("source")
 	}
 
-	query := relationObject.HasMany(&m.Endpoint{}, DB.Debug())
+	query := relationObject.HasMany(&m.Endpoint{}, a.db())
 	query = query.Where("endpoints.tenant_id = ?", a.TenantID)
 
 	query, err = applyFilters(query, filters)
@@ -57,7 +60,7 @@ func (a *endpointDaoImpl) SubCollectionList(primaryCollection interface{}, limit
 
 func (a *endpointDaoImpl) List(limit int, offset int, filters []util.Filter) ([]m.Endpoint, int64, error) {
 	endpoints := make([]m.Endpoint, 0, limit)
-	query := DB.Debug().Model(&m.Endpoint{}).
+	query := a.db().Model(&m.Endpoint{}).
 		Where("tenant_id = ?", a.TenantID)
 
 	query, err := applyFilters(query, filters)
@@ -79,7 +82,7 @@ func (a *endpointDaoImpl) List(limit int, offset int, filters []util.Filter) ([]
 // Function that searches for an application and preloads any specified relations
 func (a *endpointDaoImpl) GetByIdWithPreload(id *int64, preloads ...string) (*m.Endpoint, error) {
 	app := &m.Endpoint{ID: *id}
-	q := DB.Where("tenant_id = ?", a.TenantID)
+	q := a.db().Where("tenant_id = ?", a.TenantID)
 
 	for _, preload := range preloads {
 		q = q.Preload(preload)
@@ -91,7 +94,7 @@ func (a *endpointDaoImpl) GetByIdWithPreload(id *int64, preloads ...string) (*m.
 
 func (a *endpointDaoImpl) GetById(id *int64) (*m.Endpoint, error) {
 	app := &m.Endpoint{ID: *id}
-	result := DB.Debug().
+	result := a.db().
 		Where("tenant_id = ?", a.TenantID).
 		First(&app)
 	if result.Error != nil {
@@ -104,20 +107,19 @@ func (a *endpointDaoImpl) GetById(id *int64) (*m.Endpoint, error) {
 func (a *endpointDaoImpl) Create(app *m.Endpoint) error {
 	app.TenantID = *a.TenantID
 
-	result := DB.Debug().Create(app)
+	result := a.db().Create(app)
 	return result.Error
 }
 
 func (a *endpointDaoImpl) Update(app *m.Endpoint) error {
-	result := DB.Updates(app)
+	result := a.db().Updates(app)
 	return result.Error
 }
 
 func (a *endpointDaoImpl) Delete(id *int64) (*m.Endpoint, error) {
 	var endpoint m.Endpoint
 
-	result := DB.
-		Debug().
+	result := a.db().
 		Clauses(clause.Returning{}).
 		Where("id = ?", id).
 		Where("tenant_id = ?", a.TenantID).
@@ -142,13 +144,13 @@ func (a *endpointDaoImpl) CanEndpointBeSetAsDefaultForSource(sourceId int64) boo
 	endpoint := &m.Endpoint{}
 
 	// add double quotes to the "default" column to avoid any clashes with postgres' "default" keyword
-	result := DB.Debug().Where(`"default" = true AND source_id = ?`, sourceId).First(&endpoint)
+	result := a.db().Where(`"default" = true AND source_id = ?`, sourceId).First(&endpoint)
 	return result.Error != nil
 }
 
 func (a *endpointDaoImpl) IsRoleUniqueForSource(role string, sourceId int64) bool {
 	endpoint := &m.Endpoint{}
-	result := DB.Debug().Where("role = ? AND source_id = ?", role, sourceId).First(&endpoint)
+	result := a.db().Where("role = ? AND source_id = ?", role, sourceId).First(&endpoint)
 
 	// If the record doesn't exist "result.Error" will have a "record not found" error
 	return result.Error != nil
@@ -157,14 +159,14 @@ func (a *endpointDaoImpl) IsRoleUniqueForSource(role string, sourceId int64) boo
 func (a *endpointDaoImpl) SourceHasEndpoints(sourceId int64) bool {
 	endpoint := &m.Endpoint{}
 
-	result := DB.Debug().Where("source_id = ?", sourceId).First(&endpoint)
+	result := a.db().Where("source_id = ?", sourceId).First(&endpoint)
 
 	return result.Error == nil
 }
 
 func (a *endpointDaoImpl) BulkMessage(resource util.Resource) (map[string]interface{}, error) {
 	endpoint := &m.Endpoint{ID: resource.ResourceID}
-	result := DB.Debug().Preload("Source").Find(&endpoint)
+	result := a.db().Preload("Source").Find(&endpoint)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -175,7 +177,7 @@ func (a *endpointDaoImpl) BulkMessage(resource util.Resource) (map[string]interf
 }
 
 func (a *endpointDaoImpl) FetchAndUpdateBy(resource util.Resource, updateAttributes map[string]interface{}) (interface{}, error) {
-	result := DB.Debug().Model(&m.Endpoint{ID: resource.ResourceID}).Updates(updateAttributes)
+	result := a.db().Model(&m.Endpoint{ID: resource.ResourceID}).Updates(updateAttributes)
 
 	if result.RowsAffected == 0 {
 		return nil, fmt.Errorf("endpoint not found %v", resource)
@@ -192,7 +194,7 @@ func (a *endpointDaoImpl) FetchAndUpdateBy(resource util.Resource, updateAttribu
 
 func (a *endpointDaoImpl) FindWithTenant(id *int64) (*m.Endpoint, error) {
 	endpoint := &m.Endpoint{ID: *id}
-	result := DB.Debug().Preload("Tenant").Find(&endpoint)
+	result := a.db().Preload("Tenant").Find(&endpoint)
 
 	return endpoint, result.Error
 }
@@ -207,7 +209,7 @@ func (a *endpointDaoImpl) ToEventJSON(resource util.Resource) ([]byte, error) {
 func (a *endpointDaoImpl) Exists(endpointId int64) (bool, error) {
 	var endpointExists bool
 
-	err := DB.Model(&m.Endpoint{}).
+	err := a.db().Model(&m.Endpoint{}).
 		Select("1").
 		Where("id = ?", endpointId).
 		Where("tenant_id = ?", a.TenantID).
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
@@ -16,7 +17,7 @@ func TestDeleteEndpoint(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("delete")
 
-	endpointDao := GetEndpointDao(&fixtures.TestSourceData[0].TenantID)
+	endpointDao := GetEndpointDao(context.Background(), &fixtures.TestSourceData[0].TenantID)
 
 	endpoint := fixtures.TestEndpointData[0]
 	// Set the ID to 0 to let GORM know it should insert a new endpoint and not update an existing one.
@@ -63,7 +64,7 @@ func TestDeleteEndpointNotExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("delete")
 
-	endpointDao := GetEndpointDao(&fixtures.TestSourceData[0].TenantID)
+	endpointDao := GetEndpointDao(context.Background(), &fixtures.TestSourceData[0].TenantID)
 
 	nonExistentId := int64(12345)
 	_, err := endpointDao.Delete(&nonExistentId)
@@ -80,7 +81,7 @@ func TestEndpointExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("exists")
 
-	endpointDao := GetEndpointDao(&fixtures.TestTenantData[0].Id)
+	endpointDao := GetEndpointDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	got, err := endpointDao.Exists(fixtures.TestEndpointData[0].ID)
 	if err != nil {
@@ -99,7 +100,7 @@ func TestEndpointNotExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("exists")
 
-	endpointDao := GetEndpointDao(&fixtures.TestTenantData[0].Id)
+	endpointDao := GetEndpointDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	got, err := endpointDao.Exists(12345)
 	if err != nil {
@@ -119,7 +120,7 @@ func TestEndpointListOffsetAndLimit(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("offset_limit")
 
-	endpointDao := GetEndpointDao(&fixtures.TestTenantData[0].Id)
+	endpointDao := GetEndpointDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	wantCount := int64(len(fixtures.TestEndpointData))
 
 	for _, d := range fixtures.TestDataOffsetLimit {
@@ -154,7 +155,7 @@ func TestEndpointSubCollectionListOffsetAndLimit(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("offset_limit")
 
-	endpointDao := GetEndpointDao(&fixtures.TestTenantData[0].Id)
+	endpointDao := GetEndpointDao(context.Background(), &fixtures.TestTenantData[0].Id)
 	sourceId := int64(1)
 
 	var wantCount int64
@@ -1,9 +1,14 @@
 package dao
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/RedHatInsights/sources-api-go/config"
+	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"gorm.io/gorm"
 )
@@ -16,8 +21,30 @@ func applyFilters(query *gorm.DB, filters []util.Filter) (*gorm.DB, error) {
 		}
 	}
 
+	// the "created_at" bounds are gathered first so that a bounded range can be emitted when both are given.
+	var createdAfter, createdBefore *time.Time
+
 	var filterName string
 	for _, filter := range filters {
+		if filter.Subresource == "" && filter.Name == "created_at" && (filter.Operation == "gte" || filter.Operation == "lte") {
+			if len(filter.Value) == 0 {
+				return nil, fmt.Errorf("bad filter, no value")
+			}
+
+			bound, err := time.Parse(time.RFC3339, filter.Value[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid created_at date %q, expected an RFC3339 timestamp", filter.Value[0])
+			}
+
+			if filter.Operation == "gte" {
+				createdAfter = &bound
+			} else {
+				createdBefore = &bound
+			}
+
+			continue
+		}
+
 		// subresource filtering!
 		if filter.Subresource != "" {
 			switch filter.Subresource {
@@ -40,7 +67,14 @@ func applyFilters(query *gorm.DB, filters []util.Filter) (*gorm.DB, error) {
 					return nil, fmt.Errorf("cannot filter based on applications subresource for table %q", query.Statement.Table)
 				}
 
-				query = query.Joins(`Applications`)
+				// A source may have many applications, so the join produces a row per matching application. The
+				// query is made distinct to return every source once. Since the select list is made of the
+				// source's columns only, sorting by any of them keeps working, but sorting by an application's
+				// column isn't possible, as Postgres requires the "ORDER BY" expressions of a "SELECT DISTINCT" to
+				// be in its select list.
+				query = query.
+					Joins(`LEFT JOIN "applications" AS "Applications" ON "Applications"."source_id" = "sources"."id"`).
+					Distinct()
 				filterName = fmt.Sprintf("%v.%v", `"Applications"`, filter.Name)
 			default:
 				return nil, fmt.Errorf("invalid subresource type [%v]", filter.Subresource)
@@ -65,6 +99,18 @@ func applyFilters(query *gorm.DB, filters []util.Filter) (*gorm.DB, error) {
 			} else {
 				query = query.Where(fmt.Sprintf("%v = ?", filterName), filter.Value[0])
 			}
+		case "in":
+			values, err := parseInFilterValues(filter)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(values) == 0 {
+				// Nothing can match an empty list.
+				query = query.Where("FALSE")
+			} else {
+				query = query.Where(fmt.Sprintf("%v IN ?", filterName), values)
+			}
 		case "not_eq":
 			query = query.Where(fmt.Sprintf("%v != ?", filterName), filter.Value[0])
 		case "gt":
@@ -75,6 +121,17 @@ func applyFilters(query *gorm.DB, filters []util.Filter) (*gorm.DB, error) {
 			query = query.Where(fmt.Sprintf("%v < ?", filterName), filter.Value[0])
 		case "lte":
 			query = query.Where(fmt.Sprintf("%v <= ?", filterName), filter.Value[0])
+		case "null":
+			isNull, err := parseNullFilterValue(filter)
+			if err != nil {
+				return nil, err
+			}
+
+			if isNull {
+				query = query.Where(fmt.Sprintf("%v IS NULL", filterName))
+			} else {
+				query = query.Where(fmt.Sprintf("%v IS NOT NULL", filterName))
+			}
 		case "nil":
 			query = query.Where(fmt.Sprintf("%v IS NULL", filterName))
 		case "not_nil":
@@ -102,5 +159,295 @@ func applyFilters(query *gorm.DB, filters []util.Filter) (*gorm.DB, error) {
 		}
 	}
 
-	return query, nil
+	return applyCreatedAtRange(query, createdAfter, createdBefore), nil
+}
+
+// maxInFilterValues is the maximum number of values an "in" filter accepts.
+var maxInFilterValues = config.Get().MaxInFilterValues
+
+// inFilterColumn parses the values of a field which can be filtered with the "in" operation.
+type inFilterColumn interface {
+	parseValue(field, value string) (interface{}, error)
+}
+
+// idColumn is a column which holds IDs, so its values are parsed as integers.
+type idColumn struct{}
+
+func (idColumn) parseValue(field, value string) (interface{}, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID %q for the field %q", value, field)
+	}
+
+	return id, nil
+}
+
+// EnumColumn is a column which only holds a fixed set of values, so that any other value can be reported to the
+// client instead of silently matching nothing.
+type EnumColumn struct {
+	ValidValues []string
+}
+
+// errInvalidEnumValue is returned when a value is not one of the valid values of an enum column.
+var errInvalidEnumValue = errors.New("invalid enum value")
+
+func (e EnumColumn) parseValue(_, value string) (interface{}, error) {
+	if !util.SliceContainsString(e.ValidValues, value) {
+		return nil, errInvalidEnumValue
+	}
+
+	return value, nil
+}
+
+// quotedValidValues returns the valid values of the column as a quoted, comma separated list.
+func (e EnumColumn) quotedValidValues() string {
+	quoted := make([]string, 0, len(e.ValidValues))
+	for _, value := range e.ValidValues {
+		if value != "" {
+			quoted = append(quoted, strconv.Quote(value))
+		}
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// inFilterFields are the fields which can be filtered with the "in" operation.
+var inFilterFields = map[string]inFilterColumn{
+	"id":                  idColumn{},
+	"source_type_id":      idColumn{},
+	"application_type_id": idColumn{},
+	"availability_status": EnumColumn{ValidValues: m.AvailabilityStatuses},
+}
+
+// parseInFilterValues parses the comma separated values of an "in" filter. The IDs are parsed as integers, and the
+// values of the enum columns are validated, reporting every invalid value at once.
+func parseInFilterValues(filter util.Filter) ([]interface{}, error) {
+	column, ok := inFilterFields[filter.Name]
+	if !ok {
+		return nil, fmt.Errorf("the \"in\" operation is not supported for the field %q", filter.Name)
+	}
+
+	values := make([]interface{}, 0)
+	invalidValues := make([]string, 0)
+	for _, rawValues := range filter.Value {
+		for _, value := range strings.Split(rawValues, ",") {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+
+			if len(values)+len(invalidValues) == maxInFilterValues {
+				return nil, fmt.Errorf("the \"in\" filter for the field %q accepts up to %d values", filter.Name, maxInFilterValues)
+			}
+
+			parsed, err := column.parseValue(filter.Name, value)
+			if errors.Is(err, errInvalidEnumValue) {
+				invalidValues = append(invalidValues, strconv.Quote(value))
+				continue
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, parsed)
+		}
+	}
+
+	if len(invalidValues) > 0 {
+		return nil, fmt.Errorf("invalid values %s for the field %q, expected any of %s", strings.Join(invalidValues, ", "), filter.Name, column.(EnumColumn).quotedValidValues())
+	}
+
+	return values, nil
+}
+
+// likePatternReplacer escapes the backslashes and the wildcards of the "LIKE" patterns.
+var likePatternReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLikePattern escapes the given value so that it gets matched literally by the "LIKE" and "ILIKE" operators.
+func escapeLikePattern(value string) string {
+	return likePatternReplacer.Replace(value)
+}
+
+// nullFilterFields are the nullable fields which can be filtered with the "null" operation.
+var nullFilterFields = []string{
+	"availability_status",
+	"availability_status_error",
+	"budget_code",
+	"cost_center",
+	"expires_at",
+	"external_id",
+	"last_available_at",
+	"last_checked_at",
+	"last_used_at",
+	"paused_at",
+}
+
+// parseNullFilterValue returns whether a "null" filter asks for the null values, or for the non null ones.
+func parseNullFilterValue(filter util.Filter) (bool, error) {
+	if !util.SliceContainsString(nullFilterFields, filter.Name) {
+		return false, fmt.Errorf("the \"null\" operation is not supported for the field %q", filter.Name)
+	}
+
+	switch strings.ToLower(filter.Value[0]) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid value %q for the \"null\" operation, expected \"true\" or \"false\"", filter.Value[0])
+	}
+}
+
+// countDistinct counts the distinct values of the given column the query would return. The filters which join other
+// tables may produce duplicated rows, which a plain "count(*)" would count too.
+func countDistinct(query *gorm.DB, column string) (int64, error) {
+	count := int64(0)
+	err := query.Session(&gorm.Session{}).Distinct(column).Count(&count).Error
+
+	return count, err
+}
+
+// applyCreatedAtRange restricts the query to the records created within the given bounds, any of which may be nil.
+func applyCreatedAtRange(query *gorm.DB, createdAfter, createdBefore *time.Time) *gorm.DB {
+	column := "created_at"
+	if query.Statement.Table != "" {
+		column = fmt.Sprintf("%v.%v", query.Statement.Table, column)
+	}
+
+	switch {
+	case createdAfter != nil && createdBefore != nil:
+		query = query.Where(fmt.Sprintf("%v BETWEEN ? AND ?", column), *createdAfter, *createdBefore)
+	case createdAfter != nil:
+		query = query.Where(fmt.Sprintf("%v >= ?", column), *createdAfter)
+	case createdBefore != nil:
+		query = query.Where(fmt.Sprintf("%v <= ?", column), *createdBefore)
+	}
+
+	return query
+}
+
+// sourceTypeNameFilter is the name of the filter which filters the sources by the names of their source types.
+const sourceTypeNameFilter = "source_type_name"
+
+// ExtractSourceTypeNameFilter removes the "source_type_name" filters from the given filters, and returns their
+// lowercased names. Both the "eq" and the "in" operations are accepted, and the "in" values may be comma separated.
+func ExtractSourceTypeNameFilter(filters []util.Filter) ([]string, []util.Filter, error) {
+	names := make([]string, 0)
+	remaining := make([]util.Filter, 0, len(filters))
+
+	for _, filter := range filters {
+		if filter.Subresource != "" || filter.Name != sourceTypeNameFilter {
+			remaining = append(remaining, filter)
+			continue
+		}
+
+		if filter.Operation != "" && filter.Operation != "eq" && filter.Operation != "in" {
+			return nil, nil, fmt.Errorf("the %q filter only supports the \"eq\" and \"in\" operations", sourceTypeNameFilter)
+		}
+
+		for _, rawValues := range filter.Value {
+			for _, name := range strings.Split(rawValues, ",") {
+				name = strings.ToLower(strings.TrimSpace(name))
+				if name == "" {
+					continue
+				}
+
+				if len(names) == maxInFilterValues {
+					return nil, nil, fmt.Errorf("the %q filter accepts up to %d values", sourceTypeNameFilter, maxInFilterValues)
+				}
+
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names, remaining, nil
+}
+
+// rhcIdFilter is the name of the filter which filters the connections by their rhc_ids.
+const rhcIdFilter = "rhc_id"
+
+// extractRhcIdFilter removes the "rhc_id" filters from the given filters, and returns their rhc_ids. Both the "eq"
+// and the "in" operations are accepted, the former possibly repeated, as in "filter[rhc_id][]=a&filter[rhc_id][]=b",
+// and the "in" values may be comma separated.
+func extractRhcIdFilter(filters []util.Filter) ([]string, []util.Filter, error) {
+	rhcIds := make([]string, 0)
+	remaining := make([]util.Filter, 0, len(filters))
+
+	for _, filter := range filters {
+		if filter.Subresource != "" || filter.Name != rhcIdFilter {
+			remaining = append(remaining, filter)
+			continue
+		}
+
+		if filter.Operation != "" && filter.Operation != "eq" && filter.Operation != "in" {
+			remaining = append(remaining, filter)
+			continue
+		}
+
+		for _, rawValues := range filter.Value {
+			values := []string{rawValues}
+			if filter.Operation == "in" {
+				values = strings.Split(rawValues, ",")
+			}
+
+			for _, rhcId := range values {
+				rhcId = strings.TrimSpace(rhcId)
+				if rhcId == "" {
+					continue
+				}
+
+				if len(rhcIds) == maxInFilterValues {
+					return nil, nil, fmt.Errorf("the %q filter accepts up to %d values", rhcIdFilter, maxInFilterValues)
+				}
+
+				rhcIds = append(rhcIds, rhcId)
+			}
+		}
+	}
+
+	return rhcIds, remaining, nil
+}
+
+// tagsFilter is the name of the filter which filters the connections by their tags.
+const tagsFilter = "tags"
+
+// extractTagsFilter removes the "tags" filters from the given filters, and returns the tags they require, which are
+// given as "key:value" pairs, possibly repeated as in "filter[tags][]=env:prod&filter[tags][]=team:networking". The
+// connections must have all the returned tags.
+func extractTagsFilter(filters []util.Filter) (map[string]string, []util.Filter, error) {
+	tags := make(map[string]string)
+	remaining := make([]util.Filter, 0, len(filters))
+
+	for _, filter := range filters {
+		if filter.Subresource != "" || filter.Name != tagsFilter {
+			remaining = append(remaining, filter)
+			continue
+		}
+
+		if filter.Operation != "" && filter.Operation != "eq" {
+			return nil, nil, fmt.Errorf("the %q filter only accepts the \"eq\" operation", tagsFilter)
+		}
+
+		for _, value := range filter.Value {
+			pair := strings.SplitN(value, ":", 2)
+			if len(pair) != 2 {
+				return nil, nil, fmt.Errorf(`invalid tag "%s" for the %q filter, expected "key:value"`, value, tagsFilter)
+			}
+
+			if len(tags) == maxInFilterValues {
+				return nil, nil, fmt.Errorf("the %q filter accepts up to %d values", tagsFilter, maxInFilterValues)
+			}
+
+			tags[pair[0]] = pair[1]
+		}
+	}
+
+	err := validateTags(tags)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tags, remaining, nil
 }
@@ -0,0 +1,228 @@
+package dao
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestParseInFilterValues tests that the comma separated values of the "in" filters are parsed, and that the IDs are
+// parsed as integers.
+func TestParseInFilterValues(t *testing.T) {
+	testCases := []struct {
+		filter util.Filter
+		want   []interface{}
+	}{
+		{
+			filter: util.Filter{Name: "id", Operation: "in", Value: []string{"1,2, 3"}},
+			want:   []interface{}{int64(1), int64(2), int64(3)},
+		},
+		{
+			filter: util.Filter{Name: "source_type_id", Operation: "in", Value: []string{"1", "2"}},
+			want:   []interface{}{int64(1), int64(2)},
+		},
+		{
+			filter: util.Filter{Name: "availability_status", Operation: "in", Value: []string{"available,unavailable"}},
+			want:   []interface{}{"available", "unavailable"},
+		},
+		{
+			filter: util.Filter{Name: "application_type_id", Operation: "in", Value: []string{""}},
+			want:   []interface{}{},
+		},
+	}
+
+	for _, tc := range testCases {
+		got, err := parseInFilterValues(tc.filter)
+		if err != nil {
+			t.Errorf(`want nil error for "%v", got "%s"`, tc.filter.Value, err)
+		}
+
+		if !reflect.DeepEqual(tc.want, got) {
+			t.Errorf(`want "%v", got "%v"`, tc.want, got)
+		}
+	}
+}
+
+// TestParseInFilterValuesInvalid tests that the unsupported fields, the invalid IDs and the too long lists are
+// rejected.
+func TestParseInFilterValuesInvalid(t *testing.T) {
+	tooManyIds := make([]string, maxInFilterValues+1)
+	for i := range tooManyIds {
+		tooManyIds[i] = "1"
+	}
+
+	filters := []util.Filter{
+		{Name: "name", Operation: "in", Value: []string{"a,b"}},
+		{Name: "id", Operation: "in", Value: []string{"1,a"}},
+		{Name: "id", Operation: "in", Value: []string{strings.Join(tooManyIds, ",")}},
+		{Name: "availability_status", Operation: "in", Value: []string{"available,invalid_state"}},
+	}
+
+	for _, filter := range filters {
+		_, err := parseInFilterValues(filter)
+		if err == nil {
+			t.Errorf(`want an error for the filter "%v", got none`, filter)
+		}
+	}
+}
+
+// TestParseInFilterValuesInvalidEnum tests that every invalid value of an enum column is reported in the error.
+func TestParseInFilterValuesInvalidEnum(t *testing.T) {
+	filter := util.Filter{Name: "availability_status", Operation: "in", Value: []string{"available,invalid_state", "bogus"}}
+
+	_, err := parseInFilterValues(filter)
+	if err == nil {
+		t.Fatalf(`want an error for the invalid statuses, got none`)
+	}
+
+	for _, want := range []string{`"invalid_state"`, `"bogus"`, `"partially_available"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf(`want the error to contain %s, got "%s"`, want, err)
+		}
+	}
+
+	reported := strings.SplitN(err.Error(), "expected", 2)[0]
+	if strings.Contains(reported, `"available"`) {
+		t.Errorf(`want the valid value "available" not to be reported as invalid, got "%s"`, err)
+	}
+}
+
+// TestParseNullFilterValue tests that the "null" filters only accept boolean values for the allow listed fields.
+func TestParseNullFilterValue(t *testing.T) {
+	testCases := []struct {
+		filter  util.Filter
+		want    bool
+		wantErr bool
+	}{
+		{filter: util.Filter{Name: "paused_at", Operation: "null", Value: []string{"true"}}, want: true},
+		{filter: util.Filter{Name: "paused_at", Operation: "null", Value: []string{"FALSE"}}, want: false},
+		{filter: util.Filter{Name: "paused_at", Operation: "null", Value: []string{"yes"}}, wantErr: true},
+		{filter: util.Filter{Name: "name", Operation: "null", Value: []string{"true"}}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := parseNullFilterValue(tc.filter)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf(`want an error for the filter "%v", got none`, tc.filter)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf(`want nil error for the filter "%v", got "%s"`, tc.filter, err)
+		}
+
+		if got != tc.want {
+			t.Errorf(`want "%t" for the filter "%v", got "%t"`, tc.want, tc.filter, got)
+		}
+	}
+}
+
+// TestExtractSourceTypeNameFilter tests that the source type names are extracted lowercased from the filters, and that
+// the rest of the filters are left untouched.
+func TestExtractSourceTypeNameFilter(t *testing.T) {
+	filters := []util.Filter{
+		{Name: "name", Value: []string{"my source"}},
+		{Name: "source_type_name", Operation: "in", Value: []string{"Amazon, google,,"}},
+		{Name: "source_type_name", Value: []string{"AZURE"}},
+	}
+
+	names, remaining, err := ExtractSourceTypeNameFilter(filters)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	wantNames := []string{"amazon", "google", "azure"}
+	if !reflect.DeepEqual(wantNames, names) {
+		t.Errorf(`want names "%v", got "%v"`, wantNames, names)
+	}
+
+	if !reflect.DeepEqual(filters[:1], remaining) {
+		t.Errorf(`want the remaining filters "%v", got "%v"`, filters[:1], remaining)
+	}
+
+	_, _, err = ExtractSourceTypeNameFilter([]util.Filter{{Name: "source_type_name", Operation: "contains", Value: []string{"ama"}}})
+	if err == nil {
+		t.Errorf(`want an error for an unsupported operation, got none`)
+	}
+}
+
+// TestExtractRhcIdFilter tests that the rhc_ids of the repeated "eq" filters and of the "in" filters are extracted,
+// that the rest of the filters are left untouched, and that the too long lists are rejected.
+func TestExtractRhcIdFilter(t *testing.T) {
+	filters := []util.Filter{
+		{Name: "availability_status", Value: []string{"available"}},
+		{Name: "rhc_id", Value: []string{"a", "b"}},
+		{Name: "rhc_id", Operation: "in", Value: []string{"c, d,,"}},
+		{Name: "rhc_id", Operation: "contains", Value: []string{"e"}},
+	}
+
+	rhcIds, remaining, err := extractRhcIdFilter(filters)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	wantRhcIds := []string{"a", "b", "c", "d"}
+	if !reflect.DeepEqual(wantRhcIds, rhcIds) {
+		t.Errorf(`want rhc_ids "%v", got "%v"`, wantRhcIds, rhcIds)
+	}
+
+	wantRemaining := []util.Filter{filters[0], filters[3]}
+	if !reflect.DeepEqual(wantRemaining, remaining) {
+		t.Errorf(`want the remaining filters "%v", got "%v"`, wantRemaining, remaining)
+	}
+
+	tooManyRhcIds := make([]string, maxInFilterValues+1)
+	for i := range tooManyRhcIds {
+		tooManyRhcIds[i] = "rhc-id"
+	}
+
+	_, _, err = extractRhcIdFilter([]util.Filter{{Name: "rhc_id", Value: tooManyRhcIds}})
+	if err == nil {
+		t.Errorf(`want an error for too many rhc_ids, got none`)
+	}
+}
+
+// TestExtractTagsFilter tests that the "tags" filters are extracted as "key:value" pairs, and that the invalid tags
+// are rejected.
+func TestExtractTagsFilter(t *testing.T) {
+	filters := []util.Filter{
+		{Name: "availability_status", Value: []string{"available"}},
+		{Name: "tags", Value: []string{"env:prod"}},
+		{Name: "tags", Operation: "eq", Value: []string{"team:networking"}},
+	}
+
+	tags, remaining, err := extractTagsFilter(filters)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	wantTags := map[string]string{"env": "prod", "team": "networking"}
+	if !reflect.DeepEqual(wantTags, tags) {
+		t.Errorf(`want tags "%v", got "%v"`, wantTags, tags)
+	}
+
+	wantRemaining := []util.Filter{filters[0]}
+	if !reflect.DeepEqual(wantRemaining, remaining) {
+		t.Errorf(`want the remaining filters "%v", got "%v"`, wantRemaining, remaining)
+	}
+
+	invalidFilters := []util.Filter{
+		{Name: "tags", Value: []string{"env"}},
+		{Name: "tags", Value: []string{"env:"}},
+		{Name: "tags", Value: []string{"-env:prod"}},
+		{Name: "tags", Value: []string{"url:http://example.org"}},
+		{Name: "tags", Operation: "contains", Value: []string{"env:prod"}},
+	}
+
+	for _, filter := range invalidFilters {
+		_, _, err = extractTagsFilter([]util.Filter{filter})
+		if err == nil {
+			t.Errorf(`want an error for the filter "%v", got none`, filter)
+		}
+	}
+}
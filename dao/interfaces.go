@@ -1,10 +1,15 @@
 package dao
 
 import (
+	"context"
+	"io"
+	"time"
+
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/hashicorp/vault/api"
 	"github.com/redhatinsights/platform-go-middlewares/identity"
+	"gorm.io/gorm"
 )
 
 type SourceDao interface {
@@ -14,12 +19,29 @@ type SourceDao interface {
 	ListInternal(limit, offset int, filters []util.Filter) ([]m.Source, int64, error)
 	SubCollectionList(primaryCollection interface{}, limit, offset int, filters []util.Filter) ([]m.Source, int64, error)
 	GetById(id *int64) (*m.Source, error)
+	// GetWithRelated gets the tenant's source along with its endpoints, its applications and their types, and its
+	// rhc connections.
+	GetWithRelated(sourceId, tenantId int64) (*m.SourceWithRelated, error)
+	// GetByIdForUpdate fetches the source and locks it until the given transaction ends. The source must be updated
+	// within the same transaction.
+	GetByIdForUpdate(tx *gorm.DB, id *int64) (*m.Source, error)
+	// Create creates the source. An "unprocessable entity" error is returned when its cost center doesn't match the
+	// configured pattern.
 	Create(src *m.Source) error
+	// Update updates the source. An "unprocessable entity" error is returned when its cost center doesn't match the
+	// configured pattern.
 	Update(src *m.Source) error
+	// ClearFields sets the given nullable columns of the source to NULL. A "bad request" error is returned when any
+	// of the fields is not nullable.
+	ClearFields(ctx context.Context, sourceId int64, tenantId int64, fields []string) error
 	Delete(id *int64) (*m.Source, error)
 	Tenant() *int64
 	NameExistsInCurrentTenant(name string) bool
 	GetByIdWithPreload(id *int64, preloads ...string) (*m.Source, error)
+	// GetByExternalId gets the source which has the given external ID.
+	GetByExternalId(externalId string) (*m.Source, error)
+	// ListByCostCenter lists the tenant's sources which are charged to the given cost center.
+	ListByCostCenter(ctx context.Context, costCenter string, tenantId int64, limit, offset int) ([]m.Source, int64, error)
 	// ListForRhcConnection gets all the sources that are related to a given rhcConnection id.
 	ListForRhcConnection(rhcConnectionId *int64, limit, offset int, filters []util.Filter) ([]m.Source, int64, error)
 	BulkMessage(resource util.Resource) (map[string]interface{}, error)
@@ -35,6 +57,19 @@ type SourceDao interface {
 	DeleteCascade(sourceId int64) ([]m.ApplicationAuthentication, []m.Application, []m.Endpoint, []m.RhcConnection, *m.Source, error)
 	// Exists returns true if the source exists.
 	Exists(sourceId int64) (bool, error)
+	// ListenAvailabilityChanges calls the given function every time the availability status of the given source
+	// changes. It blocks until the context is done, the function returns an error or the listener fails. It returns
+	// "ErrTooManyListeners" right away when too many clients are already listening.
+	ListenAvailabilityChanges(ctx context.Context, sourceId int64, onChange func(notification m.SourceAvailabilityNotification) error) error
+	// SLAReport computes how long the given source was available between the given dates, weighting each of its
+	// availability statuses by the time it held it.
+	SLAReport(sourceId int64, from, to time.Time) (*m.SourceSLAReport, error)
+	// HealthSummary returns the number of the tenant's sources on each availability status, along with the last time
+	// any of them was checked and the sources which were checked the longest ago.
+	HealthSummary(tenantId int64) (*m.SourceHealthSummary, error)
+	// GetDependencyGraph returns the source's applications, endpoints and connections, along with the other sources
+	// which share those connections.
+	GetDependencyGraph(sourceId, tenantId int64) (*m.SourceDependencyGraph, error)
 }
 
 type ApplicationDao interface {
@@ -52,12 +87,32 @@ type ApplicationDao interface {
 	Pause(id int64) error
 	// Unpause resumes the application.
 	Unpause(id int64) error
+	// PauseBySource pauses all the source's applications which aren't paused yet, and marks them as paused by the
+	// source so that ResumeBySource leaves the rest of them paused.
+	PauseBySource(sourceId int64, tenantId int64) error
+	// ResumeBySource resumes the source's applications which PauseBySource paused, and clears their mark.
+	ResumeBySource(sourceId int64, tenantId int64) error
 	GetByIdWithPreload(id *int64, preloads ...string) (*m.Application, error)
 	IsSuperkey(id int64) bool
 	// DeleteCascade deletes the application along with all its related application authentications.
 	DeleteCascade(applicationId int64) ([]m.ApplicationAuthentication, *m.Application, error)
 	// Exists returns true if the application exists.
 	Exists(applicationId int64) (bool, error)
+	// AvailabilitySummary returns the number of applications of the tenant grouped by their availability status.
+	AvailabilitySummary() (*m.AppAvailabilitySummary, error)
+	// ValidateCredentials validates the credentials of the application with the validator of its application type.
+	// The validated credentials are the first authentication of the application whose type the application type
+	// supports for the source's type. It returns whether they are valid, and the reason why they are not when they
+	// aren't.
+	ValidateCredentials(ctx context.Context, appId int64, tenantId int64) (bool, string, error)
+	// ListWithSourceType lists the tenant's applications along with the name of their sources' type, which is joined
+	// in the same query.
+	ListWithSourceType(ctx context.Context, tenantId int64, limit, offset int, filters []util.Filter) ([]m.ApplicationWithSourceType, int64, error)
+	// ListByAuthType lists the tenant's applications which have at least one authentication of the given type.
+	ListByAuthType(ctx context.Context, authType string, tenantId int64, limit, offset int) ([]m.Application, int64, error)
+	// GetApplicationStatusRollup returns the worst availability status of the source's applications, or an empty
+	// string when the source has no applications.
+	GetApplicationStatusRollup(sourceId, tenantId int64) (string, error)
 }
 
 type AuthenticationDao interface {
@@ -81,6 +136,19 @@ type AuthenticationDao interface {
 	ListIdsForResource(resourceType string, resourceIds []int64) ([]m.Authentication, error)
 	// BulkDelete deletes all the authentications given as a list, and returns the ones that were deleted.
 	BulkDelete(authentications []m.Authentication) ([]m.Authentication, error)
+	// TouchLastUsed sets the "last_used_at" timestamp of the given authentication to the current time.
+	TouchLastUsed(authId int64) error
+	// ListStale lists the authentications, across all the tenants, which were last used before the given time.
+	ListStale(usedBefore time.Time, limit, offset int) ([]m.Authentication, int64, error)
+	// ListExpiringSoon lists the tenant's authentications which expire within the given duration, along with their
+	// tenants. The ones which have already expired are listed too.
+	ListExpiringSoon(tenantId int64, within time.Duration) ([]m.Authentication, error)
+	// ListTenantsWithExpiringSoon lists the IDs of the tenants, across all the tenants, which have authentications
+	// that expire within the given duration.
+	ListTenantsWithExpiringSoon(within time.Duration) ([]int64, error)
+	// ListExpiringBefore lists the tenant's authentications which expire at or before the given time, the soonest to
+	// expire first, along with the total count of them.
+	ListExpiringBefore(ctx context.Context, tenantId int64, before time.Time, limit, offset int) ([]m.Authentication, int64, error)
 }
 
 type ApplicationAuthenticationDao interface {
@@ -104,6 +172,8 @@ type ApplicationTypeDao interface {
 	GetSuperKeyResultType(applicationTypeId int64, authType string) (string, error)
 	ApplicationTypeCompatibleWithSourceType(appTypeId, sourceTypeId int64) error
 	GetByName(name string) (*m.ApplicationType, error)
+	// ListCompatibleSourceTypes returns the source types the given application type supports.
+	ListCompatibleSourceTypes(appTypeId int64) ([]m.SourceType, error)
 }
 
 type EndpointDao interface {
@@ -126,6 +196,11 @@ type EndpointDao interface {
 	ToEventJSON(resource util.Resource) ([]byte, error)
 	// Exists returns true if the endpoint exists.
 	Exists(endpointId int64) (bool, error)
+	// BulkCreate creates all the given endpoints in a single transaction, so that either all or none of them get
+	// created. The endpoints must have been validated with "service.ValidateEndpointBulkCreateRequest" beforehand.
+	// When any of them belongs to a source the tenant doesn't own, nothing gets created and the errors are returned
+	// instead. An "endpoint.created" event is published for each of the endpoints once the transaction commits.
+	BulkCreate(ctx context.Context, endpoints []m.Endpoint, tenantId int64) ([]m.Endpoint, []BulkError, error)
 }
 
 type MetaDataDao interface {
@@ -144,6 +219,10 @@ type SourceTypeDao interface {
 	Update(src *m.SourceType) error
 	Delete(id *int64) error
 	GetByName(name string) (*m.SourceType, error)
+	// ExistingNames returns the given lowercased names which belong to a source type, ignoring the case.
+	ExistingNames(names []string) ([]string, error)
+	// ListCompatibleApplicationTypes returns the application types which support the given source type.
+	ListCompatibleApplicationTypes(sourceTypeId int64) ([]m.ApplicationType, error)
 }
 
 type VaultClient interface {
@@ -155,12 +234,102 @@ type VaultClient interface {
 
 type RhcConnectionDao interface {
 	List(limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error)
+	// ListWithLastModified is like "List", but it also returns the latest "updated_at" of the filtered connections,
+	// or nil when there are none. The deletions don't modify the remaining connections, so they are only reflected
+	// by the count. The links to the sources are not reflected by either of them.
+	ListWithLastModified(limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, *time.Time, error)
+	// ListByStatus lists the tenant's connections which have the given availability status, ordered by their IDs.
+	// The "unknown" status lists the connections whose availability hasn't been checked yet. Any other status
+	// returns a bad request error.
+	ListByStatus(status string, limit, offset int) ([]m.RhcConnection, int64, error)
+	// TopologyEdges lists the links between the tenant's connections and sources in a single query, ordered by the
+	// connections and then by the sources, so that the topology graph can be built without fetching the connections.
+	// The pages are capped at 5000 edges.
+	TopologyEdges(limit, offset int) ([]m.RhcConnectionTopologyEdge, int64, error)
 	GetById(id *int64) (*m.RhcConnection, error)
+	// GetByIds gets all the tenant's connections with the given IDs in a single query. Missing IDs are skipped.
+	GetByIds(ids []int64) ([]m.RhcConnection, error)
+	// LatestPerSource gets the most recently created connection of each of the given sources, keyed by the source
+	// ID. The sources without connections are left out of the map.
+	LatestPerSource(sourceIds []int64) (map[int64]*m.RhcConnection, error)
+	// ListModifiedBy lists the tenant's connections which the given actor has created or modified since the given
+	// time, the most recently modified ones first.
+	ListModifiedBy(actor string, since time.Time, limit, offset int) ([]m.RhcConnection, int64, error)
+	// ListStatusHistory lists the latest changes of the tenant's connection's availability status, the most recent
+	// ones first.
+	ListStatusHistory(rhcConnectionId int64, tenantId int64, limit int) ([]m.RhcConnectionStatusEvent, error)
+	// GetBySourceAndRhcId gets the connection with the given rhc_id which is linked to the given source.
+	GetBySourceAndRhcId(sourceId *int64, rhcId string) (*m.RhcConnection, error)
 	Create(rhcConnection *m.RhcConnection) (*m.RhcConnection, error)
-	Update(rhcConnection *m.RhcConnection) error
-	Delete(id *int64) (*m.RhcConnection, error)
+	// CreateOrLink makes sure that a connection with the given rhc_id exists and that it is linked to the given
+	// source, and returns whether a new link had to be created.
+	CreateOrLink(rhcId string, sourceId int64) (*m.RhcConnection, bool, error)
+	// Update updates the tenant's connection, and returns the number of updated rows so that the callers can tell
+	// when the connection didn't exist. Only the mutable fields get updated, and a "bad request" error is returned
+	// when the "rhc_id" or the tenant of the connection is attempted to be changed.
+	Update(rhcConnection *m.RhcConnection) (int64, error)
+	// AddTags adds the given tags to the tenant's connection, overwriting the values of the existing keys. Returns a
+	// "bad request" error when the tags don't follow the "key: value" format.
+	AddTags(id int64, tags map[string]string) (*m.RhcConnection, error)
+	// RemoveTags removes the tags with the given keys from the tenant's connection.
+	RemoveTags(id int64, keys []string) (*m.RhcConnection, error)
+	// Delete deletes the connection, and returns a "not found" error when no rows were deleted. When the deletion
+	// guard is enabled, the connections linked to available sources are only deleted when forced, and a "bad
+	// request" error is returned otherwise.
+	Delete(id *int64, force bool) (*m.RhcConnection, error)
+	// DeleteIfExists deletes the tenant's connection if it exists, and returns whether it was deleted or not.
+	DeleteIfExists(id *int64) (bool, *m.RhcConnection, error)
+	// UnlinkFromSource removes the link between the given connection and the given tenant's source. The connection
+	// itself is only deleted if it is no longer linked to any other source. It returns the connection, along with the
+	// tenant's sources which are still linked to it, and whether it got deleted.
+	UnlinkFromSource(rhcConnectionId, sourceId, tenantId int64) (*m.RhcConnection, bool, error)
+	// ListByApplicationType gets the connections linked to sources which have an application of the given type.
+	ListByApplicationType(appTypeId int64, limit, offset int) ([]m.RhcConnection, int64, error)
+	// ListBySourceName gets the connections linked to the tenant's sources whose names start with the given prefix,
+	// regardless of the case.
+	ListBySourceName(namePrefix string, limit, offset int) ([]m.RhcConnection, int64, error)
+	// ListShared gets the connections which are linked to at least "minSources" sources. It defaults to two sources
+	// when "minSources" is not positive.
+	ListShared(minSources int, limit, offset int) ([]m.RhcConnection, int64, error)
+	// ListWithSources gets the connections along with the full sources they're linked to.
+	ListWithSources(ctx context.Context, limit, offset int, filters []util.Filter) ([]m.RhcConnectionWithSources, int64, error)
+	// ListWithSourceAvailability gets the connections along with the aggregated availability status of the sources
+	// they're linked to.
+	ListWithSourceAvailability(limit, offset int) ([]m.RhcConnectionWithAvailability, int64, error)
 	// ListForSource gets all the related connections to the given source id.
 	ListForSource(sourceId *int64, limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error)
+	// AdminListForSource gets the connections linked to the given source regardless of their tenant, for the
+	// administrators. The soft deleted connections and links are included, and marked as deleted, when requested.
+	AdminListForSource(sourceId *int64, includeDeleted bool) ([]m.RhcConnectionAdminEntry, error)
+	// ListForSourceUID gets all the related connections to the tenant's source with the given external UID.
+	ListForSourceUID(sourceUID string, limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error)
+	// Deduplicate merges all the tenant's connections which share the given rhc_id into the oldest one, and returns it.
+	Deduplicate(rhcId string) (*m.RhcConnection, error)
+	// DeduplicateDryRun returns the changes "Deduplicate" would make for the given rhc_id, without applying them.
+	DeduplicateDryRun(rhcId string) (*m.RhcConnectionDeduplication, error)
+	// Merge merges the loser connection into the winner one: the loser's links are moved to the winner, the ones
+	// which would be redundant are dropped, and the loser is deleted, all in a single transaction. Both connections
+	// must belong to the tenant. The winner is returned along with its updated links.
+	Merge(winnerId, loserId *int64) (*m.RhcConnection, error)
+	// MergeDryRun returns the changes "Merge" would make, without applying them.
+	MergeDryRun(winnerId, loserId *int64) (*m.RhcConnectionDeduplication, error)
+	// SummaryBySource returns, for each of the tenant's sources which are linked to any connection, how many
+	// connections it is linked to and how many of them are available. The summaries are cached for a short while.
+	SummaryBySource(tenantId int64) ([]m.RhcConnectionSourceSummary, error)
+	// CountForTenant returns the number of connections the tenant has, without fetching them.
+	CountForTenant() (int64, error)
+	// CountSnapshot returns the number of connections every tenant has, in a single grouped query. It is meant for the
+	// admin jobs which record the connections' growth over time, and it doesn't persist anything by itself.
+	CountSnapshot() ([]m.RhcConnectionCountSnapshot, error)
+	// CheckIntegrity reports the tenant's connections which are not linked to any source, and the links which point at
+	// missing sources or connections or which disagree on their tenant. It is meant for the monitoring jobs which alert
+	// on data drift, so it only reads the data and never fixes it.
+	CheckIntegrity() (m.IntegrityReport, error)
+	// ProbeConnection synchronously checks whether the tenant's connection is reachable, without storing the result.
+	ProbeConnection(id *int64) (reachable bool, detail string, err error)
+	// RegisterHook registers a hook which gets called before and after the connections are created, updated or
+	// deleted. The hooks are called in the order they were registered.
+	RegisterHook(hook DaoHook)
 }
 
 type TenantDao interface {
@@ -171,3 +340,81 @@ type TenantDao interface {
 	// and if it is not preset, by its EBS account number.
 	TenantByIdentity(identity *identity.Identity) (*m.Tenant, error)
 }
+
+type TenantOnboardingDao interface {
+	// EnsureOnboarded returns the tenant of the given identity, creating it along with its default quota if it
+	// doesn't exist yet. Like "GetOrCreateTenantID", the tenant is looked up by its OrgId and then by its EBS account
+	// number, and the legacy tenants which only had an account number get the identity's OrgId stored. A
+	// "tenant.created" event is published when the tenant gets created.
+	EnsureOnboarded(id *identity.Identity) (*m.Tenant, error)
+}
+
+type AvailabilityScheduleDao interface {
+	// Upsert schedules the periodic availability checks of the tenant's source with the given cron expression,
+	// replacing any previous schedule.
+	Upsert(sourceId int64, cronExpr string) error
+	// ClaimDue claims the schedules, across all the tenants, which are due an availability check at the given time,
+	// along with their sources and tenants. The claimed schedules are moved to their next check in the same
+	// transaction, and the ones being claimed by other replicas are skipped, so that each check is only due once.
+	ClaimDue(asOf time.Time, limit int) ([]m.AvailabilitySchedule, error)
+}
+
+type TenantStatsDao interface {
+	// GetStats returns the number of resources of each type the given tenant has. The statistics are cached for a
+	// couple of minutes.
+	GetStats(tenantId int64) (*m.TenantStats, error)
+}
+
+type KafkaOffsetDao interface {
+	// List returns the offsets of every consumer group, topic and partition.
+	List() ([]m.KafkaOffset, error)
+	// GetOffset returns the next offset the consumer group has to process from the topic's partition, or a "not
+	// found" error when the group hasn't processed anything from it yet.
+	GetOffset(group, topic string, partition int) (int64, error)
+	// SetOffset records the next offset the consumer group has to process from the topic's partition. The offset
+	// never moves backwards, so that the batches which get committed late don't undo the progress.
+	SetOffset(group, topic string, partition int, offset int64) error
+	// ResetToTime moves the consumer group's offsets of all the topic's partitions to the first messages which were
+	// produced at or after the given time, so that they can be replayed.
+	ResetToTime(group, topic string, t time.Time) error
+}
+
+type SourceTypeFlagDao interface {
+	// GetAll returns the capability flags of the given source type. Returns a "not found" error when the source type
+	// doesn't exist.
+	GetAll(sourceTypeId int64) ([]m.SourceTypeFlag, error)
+	// BulkSet creates or updates the given capability flags of the source type at once, leaving the rest of its
+	// flags as they are.
+	BulkSet(sourceTypeId int64, flags map[string]bool) error
+}
+
+type AuditLogDao interface {
+	// Create stores the given audit log.
+	Create(auditLog *m.AuditLog) error
+	// StreamExport writes the tenant's audit logs created between "from", inclusive, and "to", exclusive, to the
+	// given writer as CSV rows, one at a time. Returns a "bad request" error when "from" isn't before "to".
+	StreamExport(tenantId int64, from, to time.Time, w io.Writer) error
+}
+
+type CyndiStatusDao interface {
+	// GetReplicationLag returns the number of WAL bytes the Cyndi pipeline hasn't confirmed from its replication slot
+	// yet. Returns "ErrCyndiNotConfigured" when the slot doesn't exist, and "ErrCyndiInactive" when nothing consumes
+	// it.
+	GetReplicationLag() (int64, error)
+}
+
+type DeadLetterDao interface {
+	// List returns the tenant's dead letter messages, optionally filtered by their topic and their status, along
+	// with the total count of the matching messages.
+	List(topic, status string, limit, offset int) ([]m.DeadLetterMessage, int64, error)
+	// Create stores a message which could not be delivered to Kafka as a pending dead letter message.
+	Create(deadLetter *m.DeadLetterMessage) error
+	// Requeue publishes the tenant's dead letter message to its topic again and marks it as replayed. Returns a "not
+	// found" error when the message doesn't exist or belongs to another tenant.
+	Requeue(id int64) (*m.DeadLetterMessage, error)
+}
+
+type TenantQuotaDao interface {
+	// GetOrCreate returns the tenant's quota, creating it with the given defaults if it doesn't exist yet.
+	GetOrCreate(defaults *m.TenantQuota) (*m.TenantQuota, error)
+}
@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetKafkaOffsetDao is a function definition that can be replaced in runtime in case some other DAO provider is
+// needed.
+var GetKafkaOffsetDao func(context.Context) KafkaOffsetDao
+
+// getDefaultKafkaOffsetDao gets the default DAO implementation.
+func getDefaultKafkaOffsetDao(ctx context.Context) KafkaOffsetDao {
+	return &kafkaOffsetDaoImpl{requestContext: requestContext{ctx: ctx}}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetKafkaOffsetDao = getDefaultKafkaOffsetDao
+}
+
+// offsetsForTime looks up the offsets of the first messages of each of the topic's partitions which were produced
+// at or after the given time. It is a variable so that the tests can replace it, since they don't have a broker.
+var offsetsForTime = func(topic string, t time.Time) (map[int]int64, error) {
+	manager := &kafka.Manager{Config: kafka.Config{KafkaBrokers: config.Get().KafkaBrokers}}
+
+	return manager.OffsetsForTime(topic, t)
+}
+
+// kafkaOffsetConflictColumns are the columns which identify the offset of a consumer group in a topic's partition.
+var kafkaOffsetConflictColumns = []clause.Column{{Name: "consumer_group"}, {Name: "topic"}, {Name: "partition"}}
+
+type kafkaOffsetDaoImpl struct {
+	requestContext
+}
+
+func (k *kafkaOffsetDaoImpl) List() ([]m.KafkaOffset, error) {
+	offsets := make([]m.KafkaOffset, 0)
+	err := k.db().
+		Order(`"consumer_group", "topic", "partition"`).
+		Find(&offsets).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return offsets, nil
+}
+
+func (k *kafkaOffsetDaoImpl) GetOffset(group, topic string, partition int) (int64, error) {
+	var kafkaOffset m.KafkaOffset
+	err := k.db().
+		Where(`"consumer_group" = ?`, group).
+		Where(`"topic" = ?`, topic).
+		Where(`"partition" = ?`, partition).
+		First(&kafkaOffset).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, util.NewErrNotFound("kafka offset")
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return kafkaOffset.Offset, nil
+}
+
+func (k *kafkaOffsetDaoImpl) SetOffset(group, topic string, partition int, offset int64) error {
+	kafkaOffset := m.KafkaOffset{ConsumerGroup: group, Topic: topic, Partition: partition, Offset: offset}
+
+	// A member of the consumer group which has just lost the partition may flush its batch late, so the offset must not
+	// move backwards.
+	return k.db().
+		Clauses(clause.OnConflict{
+			Columns: kafkaOffsetConflictColumns,
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"offset":     gorm.Expr(`GREATEST("kafka_offsets"."offset", EXCLUDED."offset")`),
+				"updated_at": gorm.Expr(`EXCLUDED."updated_at"`),
+			}),
+		}).
+		Create(&kafkaOffset).
+		Error
+}
+
+func (k *kafkaOffsetDaoImpl) ResetToTime(group, topic string, t time.Time) error {
+	offsets, err := offsetsForTime(topic, t)
+	if err != nil {
+		return err
+	}
+
+	if len(offsets) == 0 {
+		return util.NewErrNotFound("kafka topic")
+	}
+
+	kafkaOffsets := make([]m.KafkaOffset, 0, len(offsets))
+	for partition, offset := range offsets {
+		kafkaOffsets = append(kafkaOffsets, m.KafkaOffset{ConsumerGroup: group, Topic: topic, Partition: partition, Offset: offset})
+	}
+
+	// Unlike when setting an offset, resetting it is expected to move it backwards.
+	return k.db().
+		Clauses(clause.OnConflict{
+			Columns:   kafkaOffsetConflictColumns,
+			DoUpdates: clause.AssignmentColumns([]string{"offset", "updated_at"}),
+		}).
+		Create(&kafkaOffsets).
+		Error
+}
@@ -0,0 +1,96 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestKafkaOffsetSetAndGet tests that the offsets get recorded, and that they never move backwards.
+func TestKafkaOffsetSetAndGet(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("kafka_offsets")
+
+	kafkaOffsetDao := GetKafkaOffsetDao(context.Background())
+
+	_, err := kafkaOffsetDao.GetOffset("group", "topic", 0)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error for an unknown partition, got "%v"`, err)
+	}
+
+	for _, offset := range []int64{5, 10, 7} {
+		err = kafkaOffsetDao.SetOffset("group", "topic", 0, offset)
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+	}
+
+	got, err := kafkaOffsetDao.GetOffset("group", "topic", 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if got != 10 {
+		t.Errorf(`want offset "10", got "%d"`, got)
+	}
+
+	// The other partitions are tracked separately.
+	_, err = kafkaOffsetDao.GetOffset("group", "topic", 1)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error for another partition, got "%v"`, err)
+	}
+
+	DropSchema("kafka_offsets")
+}
+
+// TestKafkaOffsetResetToTime tests that the offsets of every partition are moved to the ones the broker reports for
+// the given time, even when that moves them backwards.
+func TestKafkaOffsetResetToTime(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("kafka_offsets")
+
+	originalOffsetsForTime := offsetsForTime
+	defer func() { offsetsForTime = originalOffsetsForTime }()
+
+	offsetsForTime = func(topic string, _ time.Time) (map[int]int64, error) {
+		return map[int]int64{0: 3, 1: 8}, nil
+	}
+
+	kafkaOffsetDao := GetKafkaOffsetDao(context.Background())
+
+	err := kafkaOffsetDao.SetOffset("group", "topic", 0, 20)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	err = kafkaOffsetDao.ResetToTime("group", "topic", time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	for partition, want := range map[int]int64{0: 3, 1: 8} {
+		got, err := kafkaOffsetDao.GetOffset("group", "topic", partition)
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+
+		if got != want {
+			t.Errorf(`want offset "%d" for partition "%d", got "%d"`, want, partition, got)
+		}
+	}
+
+	offsets, err := kafkaOffsetDao.List()
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(offsets) != 2 {
+		t.Errorf(`want "2" offsets, got "%d"`, len(offsets))
+	}
+
+	DropSchema("kafka_offsets")
+}
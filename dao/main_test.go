@@ -91,7 +91,6 @@ func ConnectToTestDB(dbSchema string) {
 	DB = db
 
 	err = DB.
-		Debug().
 		Exec(fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s`, dbSchema)).
 		Error
 
@@ -102,7 +101,6 @@ func ConnectToTestDB(dbSchema string) {
 	// Set the database's search path to the schema, so that no prefix needs to be added by default to the tables in
 	// the queries.
 	err = DB.
-		Debug().
 		Exec(fmt.Sprintf(`SET search_path TO %s`, dbSchema)).
 		Error
 
@@ -137,7 +135,6 @@ func CreateFixtures(schema string) {
 // DropSchema drops the database schema entirely.
 func DropSchema(dbSchema string) {
 	err := DB.
-		Debug().
 		Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", dbSchema)).
 		Error
 
@@ -162,6 +159,7 @@ func MigrateSchema() {
 		AvailabilityStatusError string         `gorm:"column:availability_status_error"`
 		LastCheckedAt           time.Time      `gorm:"column:last_checked_at"`
 		LastAvailableAt         time.Time      `gorm:"column:last_available_at"`
+		LastUsedAt              *time.Time     `gorm:"column:last_used_at"`
 		SourceId                int64          `gorm:"column:source_id"`
 		TenantId                int64          `gorm:"column:tenant_id"`
 		ResourceType            string         `gorm:"column:resource_type"`
@@ -182,6 +180,9 @@ func MigrateSchema() {
 		&m.Application{},
 		&authentication{},
 		&m.ApplicationAuthentication{},
+		&m.TenantQuota{},
+		&m.AvailabilitySchedule{},
+		&m.SourceAvailabilityChange{},
 	)
 
 	if err != nil {
@@ -207,7 +208,6 @@ func SwitchSchema(schema string) {
 	ConnectToTestDB(schema)
 
 	err := DB.
-		Debug().
 		Exec(fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s`, schema)).
 		Error
 
@@ -218,7 +218,6 @@ func SwitchSchema(schema string) {
 	// Set the database's search path to the schema, so that no prefix needs to be added by default to the tables in
 	// the queries.
 	err = DB.
-		Debug().
 		Exec(fmt.Sprintf(`SET search_path TO %s`, schema)).
 		Error
 
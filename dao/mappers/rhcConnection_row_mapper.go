@@ -30,6 +30,12 @@ func MapRowToRhcConnection(row map[string]interface{}) (*model.RhcConnection, er
 		}
 	}
 
+	if value, ok := row["tags"]; ok {
+		if tags, ok := value.(string); ok {
+			rhcConnection.Tags = datatypes.JSON(tags)
+		}
+	}
+
 	if value, ok := row["availability_status"]; ok {
 		if availabilityStatus, ok := value.(string); ok {
 			rhcConnection.AvailabilityStatus = availabilityStatus
@@ -85,6 +91,12 @@ func MapRowToRhcConnection(row map[string]interface{}) (*model.RhcConnection, er
 		}
 	}
 
+	if value, ok := row["updated_by"]; ok {
+		if updatedBy, ok := value.(string); ok {
+			rhcConnection.UpdatedBy = updatedBy
+		}
+	}
+
 	if value, ok := row["source_ids"]; ok {
 		if idList, ok := value.(string); ok {
 			err := MapIdListToRhcConnection(idList, &rhcConnection)
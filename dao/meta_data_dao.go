@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"strings"
 
 	m "github.com/RedHatInsights/sources-api-go/model"
@@ -14,11 +15,11 @@ const (
 
 // GetMetaDataDao is a function definition that can be replaced in runtime in case some other DAO provider is
 // needed.
-var GetMetaDataDao func() MetaDataDao
+var GetMetaDataDao func(context.Context) MetaDataDao
 
 // getDefaultMetaDataDao gets the default DAO implementation which will have the given tenant ID.
-func getDefaultMetaDataDao() MetaDataDao {
-	return &metaDataDaoImpl{}
+func getDefaultMetaDataDao(ctx context.Context) MetaDataDao {
+	return &metaDataDaoImpl{requestContext: requestContext{ctx: ctx}}
 }
 
 // init sets the default DAO implementation so that other packages can request it easily.
@@ -26,16 +27,18 @@ func init() {
 	GetMetaDataDao = getDefaultMetaDataDao
 }
 
-type metaDataDaoImpl struct{}
+type metaDataDaoImpl struct {
+	requestContext
+}
 
 func (md *metaDataDaoImpl) SubCollectionList(primaryCollection interface{}, limit int, offset int, filters []util.Filter) ([]m.MetaData, int64, error) {
 	metadatas := make([]m.MetaData, 0, limit)
-	relationObject, err := m.NewRelationObject(primaryCollection, -1, DB.Debug())
+	relationObject, err := m.NewRelationObject(primaryCollection, -1, md.db())
 	if err != nil {
 		return nil, 0, util.NewErrNotFound("application type")
 	}
 
-	query := relationObject.HasMany(&m.MetaData{}, DB.Debug())
+	query := relationObject.HasMany(&m.MetaData{}, md.db())
 	query = query.Where("meta_data.type = ?", m.APP_META_DATA)
 
 	query, err = applyFilters(query, filters)
@@ -55,7 +58,7 @@ func (md *metaDataDaoImpl) SubCollectionList(primaryCollection interface{}, limi
 
 func (md *metaDataDaoImpl) List(limit int, offset int, filters []util.Filter) ([]m.MetaData, int64, error) {
 	metaData := make([]m.MetaData, 0, limit)
-	query := DB.Debug().Model(&m.MetaData{}).Where("type = ?", m.APP_META_DATA)
+	query := md.db().Model(&m.MetaData{}).Where("type = ?", m.APP_META_DATA)
 
 	query, err := applyFilters(query, filters)
 	if err != nil {
@@ -74,7 +77,7 @@ func (md *metaDataDaoImpl) List(limit int, offset int, filters []util.Filter) ([
 
 func (md *metaDataDaoImpl) GetById(id *int64) (*m.MetaData, error) {
 	metaData := &m.MetaData{ID: *id}
-	result := DB.Debug().First(&metaData)
+	result := md.db().First(&metaData)
 	if result.Error != nil {
 		return nil, util.NewErrNotFound("metadata")
 	}
@@ -85,7 +88,7 @@ func (md *metaDataDaoImpl) GetById(id *int64) (*m.MetaData, error) {
 func (md *metaDataDaoImpl) GetSuperKeySteps(applicationTypeId int64) ([]m.MetaData, error) {
 	steps := make([]m.MetaData, 0)
 
-	result := DB.Model(&m.MetaData{}).
+	result := md.db().Model(&m.MetaData{}).
 		Where("type = ?", m.SUPERKEY_META_DATA).
 		Where("application_type_id = ?", applicationTypeId).
 		Order("step").
@@ -96,7 +99,7 @@ func (md *metaDataDaoImpl) GetSuperKeySteps(applicationTypeId int64) ([]m.MetaDa
 
 func (md *metaDataDaoImpl) GetSuperKeyAccountNumber(applicationTypeId int64) (string, error) {
 	var account string
-	result := DB.Model(&m.MetaData{}).
+	result := md.db().Model(&m.MetaData{}).
 		Select("payload").
 		Where("type = ?", m.APP_META_DATA).
 		Where("application_type_id = ?", applicationTypeId).
@@ -110,7 +113,7 @@ func (md *metaDataDaoImpl) GetSuperKeyAccountNumber(applicationTypeId int64) (st
 func (md *metaDataDaoImpl) ApplicationOptedIntoRetry(applicationTypeId int64) (bool, error) {
 	var optIn bool
 
-	result := DB.Debug().
+	result := md.db().
 		Model(&m.MetaData{}).
 		Select(`payload::text = '"true"'`).
 		Where("name = ?", RETRY_SOURCE_CREATION_SETTING).
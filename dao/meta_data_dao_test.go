@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
@@ -15,7 +16,7 @@ func TestMetaDataSubcollectionListWithOffsetAndLimit(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("offset_limit")
 
-	metaDataDao := GetMetaDataDao()
+	metaDataDao := GetMetaDataDao(context.Background())
 
 	appTypeId := fixtures.TestApplicationTypeData[0].Id
 	// How many meta data with given application type id is in fixtures
@@ -58,7 +59,7 @@ func TestMetaDataListOffsetAndLimit(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("offset_limit")
 
-	metaDataDao := GetMetaDataDao()
+	metaDataDao := GetMetaDataDao(context.Background())
 	wantCount := int64(len(fixtures.TestMetaDataData))
 
 	for _, d := range fixtures.TestDataOffsetLimit {
@@ -1,14 +1,23 @@
 package dao
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/redhatinsights/platform-go-middlewares/identity"
+	"gorm.io/gorm"
 )
 
 type MockSourceDao struct {
@@ -49,6 +58,35 @@ type MockAuthenticationDao struct {
 	Authentications []m.Authentication
 }
 
+type MockTenantStatsDao struct {
+	Stats []m.TenantStats
+}
+
+type MockKafkaOffsetDao struct {
+	Offsets []m.KafkaOffset
+}
+
+type MockSourceTypeFlagDao struct {
+	Flags []m.SourceTypeFlag
+}
+
+type MockAuditLogDao struct {
+	AuditLogs []m.AuditLog
+}
+
+type MockAvailabilityScheduleDao struct {
+	Schedules []m.AvailabilitySchedule
+}
+
+type MockCyndiStatusDao struct {
+	Lag int64
+	Err error
+}
+
+type MockDeadLetterDao struct {
+	DeadLetters []m.DeadLetterMessage
+}
+
 func (src *MockSourceDao) SubCollectionList(primaryCollection interface{}, limit, offset int, filters []util.Filter) ([]m.Source, int64, error) {
 	var sources []m.Source
 
@@ -117,13 +155,84 @@ func (src *MockSourceDao) GetById(id *int64) (*m.Source, error) {
 	return nil, util.NewErrNotFound("source")
 }
 
+func (src *MockSourceDao) GetWithRelated(sourceId, tenantId int64) (*m.SourceWithRelated, error) {
+	source, err := src.GetById(&sourceId)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.TenantID != tenantId {
+		return nil, util.NewErrNotFound("source")
+	}
+
+	sourceWithRelated := &m.SourceWithRelated{Source: *source}
+	for _, endpoint := range fixtures.TestEndpointData {
+		if endpoint.SourceID == sourceId {
+			sourceWithRelated.Endpoints = append(sourceWithRelated.Endpoints, endpoint)
+		}
+	}
+
+	for _, app := range fixtures.TestApplicationData {
+		if app.SourceID != sourceId {
+			continue
+		}
+
+		for _, appType := range fixtures.TestApplicationTypeData {
+			if appType.Id == app.ApplicationTypeID {
+				app.ApplicationType = appType
+			}
+		}
+
+		sourceWithRelated.Applications = append(sourceWithRelated.Applications, m.ApplicationWithType{Application: app})
+	}
+
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.SourceId != sourceId {
+			continue
+		}
+
+		for _, rhcConnection := range fixtures.TestRhcConnectionData {
+			if rhcConnection.ID == link.RhcConnectionId {
+				rhcConnection.Sources = []m.Source{{ID: sourceId}}
+				sourceWithRelated.RhcConnections = append(sourceWithRelated.RhcConnections, rhcConnection)
+			}
+		}
+	}
+
+	return sourceWithRelated, nil
+}
+
+func (src *MockSourceDao) GetByIdForUpdate(_ *gorm.DB, id *int64) (*m.Source, error) {
+	return src.GetById(id)
+}
+
 func (src *MockSourceDao) Create(s *m.Source) error {
+	err := validateCostCenter(s.CostCenter)
+	if err != nil {
+		return err
+	}
+
 	src.Sources = append(src.Sources, *s)
 	return nil
 }
 
 func (src *MockSourceDao) Update(s *m.Source) error {
-	return nil
+	return validateCostCenter(s.CostCenter)
+}
+
+func (src *MockSourceDao) ClearFields(_ context.Context, sourceId int64, _ int64, fields []string) error {
+	err := validateClearableSourceFields(fields)
+	if err != nil {
+		return err
+	}
+
+	for _, source := range src.Sources {
+		if source.ID == sourceId {
+			return nil
+		}
+	}
+
+	return util.NewErrNotFound("source")
 }
 
 func (src *MockSourceDao) Delete(id *int64) (*m.Source, error) {
@@ -152,6 +261,86 @@ func (src *MockSourceDao) Exists(sourceId int64) (bool, error) {
 	return false, nil
 }
 
+// GetDependencyGraph returns a graph with just the source, since the mock has no dependants for it.
+func (src *MockSourceDao) GetDependencyGraph(sourceId, _ int64) (*m.SourceDependencyGraph, error) {
+	for _, source := range src.Sources {
+		if source.ID == sourceId {
+			return &m.SourceDependencyGraph{
+				ID:             source.ID,
+				Name:           source.Name,
+				Applications:   make([]m.SourceDependencyApplication, 0),
+				Endpoints:      make([]m.SourceDependencyEndpoint, 0),
+				RhcConnections: make([]m.SourceDependencyRhcConnection, 0),
+			}, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("source")
+}
+
+// ListenAvailabilityChanges doesn't produce any notifications, and it returns once the context is done.
+func (src *MockSourceDao) ListenAvailabilityChanges(ctx context.Context, _ int64, _ func(notification m.SourceAvailabilityNotification) error) error {
+	<-ctx.Done()
+
+	return nil
+}
+
+func (src *MockSourceDao) SLAReport(sourceId int64, from, to time.Time) (*m.SourceSLAReport, error) {
+	if !from.Before(to) {
+		return nil, util.NewErrBadRequest(`the "from" date must be before the "to" date`)
+	}
+
+	for _, source := range src.Sources {
+		if source.ID == sourceId {
+			return &m.SourceSLAReport{SourceId: sourceId, From: from, To: to}, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("source")
+}
+
+func (src *MockSourceDao) HealthSummary(tenantId int64) (*m.SourceHealthSummary, error) {
+	sources := make([]m.Source, 0, len(src.Sources))
+	for _, source := range src.Sources {
+		if source.TenantID == tenantId {
+			sources = append(sources, source)
+		}
+	}
+
+	// Mimic the ranking of the query: the never checked sources go first.
+	sort.SliceStable(sources, func(i, j int) bool {
+		a, b := sources[i].LastCheckedAt, sources[j].LastCheckedAt
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+
+		return a.Before(*b)
+	})
+
+	rows := make([]sourceHealthRow, 0, len(sources))
+	for i, source := range sources {
+		rows = append(rows, sourceHealthRow{
+			Kind:               sourceHealthRowStatus,
+			AvailabilityStatus: source.AvailabilityStatus,
+			Count:              1,
+			LatestCheck:        source.LastCheckedAt,
+		})
+
+		if i < sourceHealthOldestCheckedLimit {
+			id, name := source.ID, source.Name
+			rows = append(rows, sourceHealthRow{
+				Kind:               sourceHealthRowOldest,
+				AvailabilityStatus: source.AvailabilityStatus,
+				ID:                 &id,
+				Name:               &name,
+				LastCheckedAt:      source.LastCheckedAt,
+			})
+		}
+	}
+
+	return summarizeSourceHealth(rows), nil
+}
+
 // NameExistsInCurrentTenant returns always false because it's the safe default in case the request gets validated
 // in the tests.
 func (src *MockSourceDao) NameExistsInCurrentTenant(name string) bool {
@@ -172,6 +361,37 @@ func (src *MockSourceDao) GetByIdWithPreload(id *int64, preloads ...string) (*m.
 	return nil, util.NewErrNotFound("source")
 }
 
+func (src *MockSourceDao) GetByExternalId(externalId string) (*m.Source, error) {
+	for _, i := range src.Sources {
+		if i.ExternalId != nil && *i.ExternalId == externalId {
+			return &i, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("source")
+}
+
+func (src *MockSourceDao) ListByCostCenter(_ context.Context, costCenter string, tenantId int64, limit, offset int) ([]m.Source, int64, error) {
+	sources := make([]m.Source, 0)
+	for _, source := range src.Sources {
+		if source.TenantID == tenantId && source.CostCenter != nil && *source.CostCenter == costCenter {
+			sources = append(sources, source)
+		}
+	}
+
+	count := int64(len(sources))
+	if offset >= len(sources) {
+		return []m.Source{}, count, nil
+	}
+
+	end := offset + limit
+	if end > len(sources) {
+		end = len(sources)
+	}
+
+	return sources[offset:end], count, nil
+}
+
 func (m *MockSourceDao) ListForRhcConnection(id *int64, limit, offset int, filters []util.Filter) ([]m.Source, int64, error) {
 	count := int64(len(m.RelatedSources))
 
@@ -361,6 +581,33 @@ func (a *MockApplicationTypeDao) GetByName(_ string) (*m.ApplicationType, error)
 	return nil, nil
 }
 
+func (a *MockApplicationTypeDao) ListCompatibleSourceTypes(appTypeId int64) ([]m.SourceType, error) {
+	sourceTypes := make([]m.SourceType, 0)
+	for _, appType := range a.ApplicationTypes {
+		if appType.Id != appTypeId {
+			continue
+		}
+
+		for _, sourceType := range fixtures.TestSourceTypeData {
+			if mockSupportsSourceType(appType, sourceType) {
+				sourceTypes = append(sourceTypes, sourceType)
+			}
+		}
+	}
+
+	return sourceTypes, nil
+}
+
+// mockSupportsSourceType returns true when the source type's name is in the application type's supported source types.
+func mockSupportsSourceType(appType m.ApplicationType, sourceType m.SourceType) bool {
+	var supported []string
+	if len(appType.SupportedSourceTypes) == 0 || json.Unmarshal(appType.SupportedSourceTypes, &supported) != nil {
+		return false
+	}
+
+	return util.SliceContainsString(supported, sourceType.Name)
+}
+
 func (a *MockSourceTypeDao) List(limit int, offset int, filters []util.Filter) ([]m.SourceType, int64, error) {
 	count := int64(len(a.SourceTypes))
 	return a.SourceTypes, count, nil
@@ -376,16 +623,44 @@ func (a *MockSourceTypeDao) GetById(id *int64) (*m.SourceType, error) {
 	return nil, util.NewErrNotFound("source type")
 }
 
+func (a *MockSourceTypeDao) ListCompatibleApplicationTypes(sourceTypeId int64) ([]m.ApplicationType, error) {
+	appTypes := make([]m.ApplicationType, 0)
+	for _, sourceType := range a.SourceTypes {
+		if sourceType.Id != sourceTypeId {
+			continue
+		}
+
+		for _, appType := range fixtures.TestApplicationTypeData {
+			if mockSupportsSourceType(appType, sourceType) {
+				appTypes = append(appTypes, appType)
+			}
+		}
+	}
+
+	return appTypes, nil
+}
+
 func (a *MockSourceTypeDao) GetByName(_ string) (*m.SourceType, error) {
 	return nil, nil
 }
 
+func (a *MockSourceTypeDao) ExistingNames(names []string) ([]string, error) {
+	existing := make([]string, 0)
+	for _, sourceType := range a.SourceTypes {
+		if util.SliceContainsString(names, strings.ToLower(sourceType.Name)) {
+			existing = append(existing, strings.ToLower(sourceType.Name))
+		}
+	}
+
+	return existing, nil
+}
+
 func (a *MockSourceTypeDao) Create(src *m.SourceType) error {
 	panic("not implemented") // TODO: Implement
 }
 
 func (a *MockSourceTypeDao) Update(src *m.SourceType) error {
-	panic("not implemented") // TODO: Implement
+	return nil
 }
 
 func (a *MockSourceTypeDao) Delete(id *int64) error {
@@ -498,6 +773,100 @@ func (a *MockApplicationDao) Exists(applicationId int64) (bool, error) {
 	return false, nil
 }
 
+func (a *MockApplicationDao) AvailabilitySummary() (*m.AppAvailabilitySummary, error) {
+	statusCounts := make([]applicationStatusCount, 0, len(a.Applications))
+	for _, application := range a.Applications {
+		statusCounts = append(statusCounts, applicationStatusCount{AvailabilityStatus: application.AvailabilityStatus, Count: 1})
+	}
+
+	return summarizeApplicationStatuses(statusCounts), nil
+}
+
+// ValidateCredentials reports the credentials of any existing application as valid.
+func (a *MockApplicationDao) ValidateCredentials(_ context.Context, appId int64, _ int64) (bool, string, error) {
+	for _, application := range a.Applications {
+		if application.ID == appId {
+			return true, "", nil
+		}
+	}
+
+	return false, "", util.NewErrNotFound("application")
+}
+
+func (a *MockApplicationDao) GetApplicationStatusRollup(sourceId, tenantId int64) (string, error) {
+	statuses := make([]string, 0)
+	for _, application := range a.Applications {
+		if application.SourceID == sourceId && application.TenantID == tenantId {
+			statuses = append(statuses, application.AvailabilityStatus)
+		}
+	}
+
+	return worstAvailabilityStatus(statuses), nil
+}
+
+func (a *MockApplicationDao) ListWithSourceType(_ context.Context, tenantId int64, limit, offset int, filters []util.Filter) ([]m.ApplicationWithSourceType, int64, error) {
+	sourceTypeNames := make(map[int64]string)
+	for _, sourceType := range fixtures.TestSourceTypeData {
+		sourceTypeNames[sourceType.Id] = sourceType.Name
+	}
+
+	applications := make([]m.ApplicationWithSourceType, 0)
+	for _, application := range a.Applications {
+		if application.TenantID != tenantId {
+			continue
+		}
+
+		for _, source := range fixtures.TestSourceData {
+			if source.ID == application.SourceID {
+				applications = append(applications, m.ApplicationWithSourceType{Application: application, SourceTypeName: sourceTypeNames[source.SourceTypeID]})
+			}
+		}
+	}
+
+	return applications, int64(len(applications)), nil
+}
+
+func (a *MockApplicationDao) ListByAuthType(_ context.Context, authType string, tenantId int64, limit, offset int) ([]m.Application, int64, error) {
+	if !util.SliceContainsString(config.Get().KnownAuthTypes, authType) {
+		return nil, 0, util.NewErrBadRequest(fmt.Sprintf("unknown authentication type %q", authType))
+	}
+
+	applications := make([]m.Application, 0)
+	for _, application := range a.Applications {
+		if application.TenantID != tenantId {
+			continue
+		}
+
+		for _, auth := range fixtures.TestAuthenticationData {
+			if auth.AuthType != authType || auth.TenantID != tenantId {
+				continue
+			}
+
+			linked := auth.ResourceType == "Application" && auth.ResourceID == application.ID
+			for _, appAuth := range fixtures.TestApplicationAuthenticationData {
+				if appAuth.ApplicationID == application.ID && appAuth.AuthenticationID == auth.DbID {
+					linked = true
+				}
+			}
+
+			if linked {
+				applications = append(applications, application)
+				break
+			}
+		}
+	}
+
+	count := int64(len(applications))
+	if offset > len(applications) {
+		offset = len(applications)
+	}
+	if offset+limit < len(applications) {
+		applications = applications[:offset+limit]
+	}
+
+	return applications[offset:], count, nil
+}
+
 func (m *MockApplicationDao) BulkMessage(_ util.Resource) (map[string]interface{}, error) {
 	return nil, nil
 }
@@ -518,6 +887,36 @@ func (a *MockApplicationDao) Unpause(_ int64) error {
 	return nil
 }
 
+func (a *MockApplicationDao) PauseBySource(sourceId int64, tenantId int64) error {
+	now := time.Now()
+	for i, app := range a.Applications {
+		if app.SourceID != sourceId || app.TenantID != tenantId || app.PausedAt != nil {
+			continue
+		}
+
+		pausedBeforeArchive := false
+		a.Applications[i].PausedAt = &now
+		a.Applications[i].PausedBeforeArchive = &pausedBeforeArchive
+	}
+
+	return nil
+}
+
+func (a *MockApplicationDao) ResumeBySource(sourceId int64, tenantId int64) error {
+	for i, app := range a.Applications {
+		if app.SourceID != sourceId || app.TenantID != tenantId {
+			continue
+		}
+
+		if app.PausedBeforeArchive != nil && !*app.PausedBeforeArchive {
+			a.Applications[i].PausedAt = nil
+			a.Applications[i].PausedBeforeArchive = nil
+		}
+	}
+
+	return nil
+}
+
 func (src *MockApplicationDao) IsSuperkey(id int64) bool {
 	return false
 }
@@ -562,6 +961,37 @@ func (a *MockEndpointDao) Create(src *m.Endpoint) error {
 	return nil
 }
 
+func (a *MockEndpointDao) BulkCreate(_ context.Context, endpoints []m.Endpoint, tenantId int64) ([]m.Endpoint, []BulkError, error) {
+	created := make([]m.Endpoint, len(endpoints))
+	copy(created, endpoints)
+
+	bulkErrors := make([]BulkError, 0)
+	for i := range created {
+		created[i].TenantID = tenantId
+
+		owned := false
+		for _, source := range fixtures.TestSourceData {
+			if source.ID == created[i].SourceID && source.TenantID == tenantId {
+				owned = true
+			}
+		}
+
+		if !owned {
+			bulkErrors = append(bulkErrors, BulkError{Index: i, Message: fmt.Sprintf("source %d not found", created[i].SourceID)})
+		}
+	}
+
+	if len(bulkErrors) > 0 {
+		return nil, bulkErrors, nil
+	}
+
+	for i := range created {
+		created[i].ID = int64(len(a.Endpoints) + i + 1)
+	}
+
+	return created, nil, nil
+}
+
 func (a *MockEndpointDao) Update(endpoint *m.Endpoint) error {
 	if endpoint.ID == fixtures.TestEndpointData[0].ID {
 		return nil
@@ -619,6 +1049,43 @@ func (m *MockRhcConnectionDao) List(limit, offset int, filters []util.Filter) ([
 	return m.RhcConnections, count, nil
 }
 
+func (mr *MockRhcConnectionDao) TopologyEdges(limit, offset int) ([]m.RhcConnectionTopologyEdge, int64, error) {
+	edges := make([]m.RhcConnectionTopologyEdge, 0)
+	for _, rhcConnection := range mr.RhcConnections {
+		for _, source := range rhcConnection.Sources {
+			edges = append(edges, m.RhcConnectionTopologyEdge{RhcConnectionId: rhcConnection.ID, SourceId: source.ID})
+		}
+	}
+
+	return edges, int64(len(edges)), nil
+}
+
+func (mr *MockRhcConnectionDao) ListByStatus(status string, limit, offset int) ([]m.RhcConnection, int64, error) {
+	if _, ok := m.ValidSourceTransitions[status]; !ok {
+		return nil, 0, util.NewErrBadRequest(fmt.Sprintf(`invalid availability status "%s"`, status))
+	}
+
+	out := make([]m.RhcConnection, 0)
+	for _, rhcConnection := range mr.RhcConnections {
+		if m.TransitionState(rhcConnection.AvailabilityStatus) == status {
+			out = append(out, rhcConnection)
+		}
+	}
+
+	return out, int64(len(out)), nil
+}
+
+func (mr *MockRhcConnectionDao) ListWithLastModified(limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, *time.Time, error) {
+	var lastModified *time.Time
+	for i := range mr.RhcConnections {
+		if updatedAt := mr.RhcConnections[i].UpdatedAt; !updatedAt.IsZero() && (lastModified == nil || updatedAt.After(*lastModified)) {
+			lastModified = &updatedAt
+		}
+	}
+
+	return mr.RhcConnections, int64(len(mr.RhcConnections)), lastModified, nil
+}
+
 func (mr *MockRhcConnectionDao) GetById(id *int64) (*m.RhcConnection, error) {
 	// The ".ToResponse" method of the RhcConnection expects to have at least one related source.
 	source := []m.Source{
@@ -637,29 +1104,105 @@ func (mr *MockRhcConnectionDao) GetById(id *int64) (*m.RhcConnection, error) {
 	return nil, util.NewErrNotFound("rhcConnection")
 }
 
-func (mr *MockRhcConnectionDao) Create(rhcConnection *m.RhcConnection) (*m.RhcConnection, error) {
-	// Check if in fixtures is a source with given source id
-	var sourceExists bool
-	for _, src := range fixtures.TestSourceData {
-		if src.ID == rhcConnection.Sources[0].ID {
-			sourceExists = true
+func (mr *MockRhcConnectionDao) GetByIds(ids []int64) ([]m.RhcConnection, error) {
+	rhcConnections := make([]m.RhcConnection, 0, len(ids))
+	for _, rhcConnection := range mr.RhcConnections {
+		for _, id := range ids {
+			if rhcConnection.ID == id {
+				rhcConnections = append(rhcConnections, rhcConnection)
+				break
+			}
 		}
 	}
 
-	if !sourceExists {
-		return nil, util.NewErrNotFound("source")
-	}
+	return rhcConnections, nil
+}
 
-	// Check if in fixtures exists same relation
-	var relationExists bool
-	for _, s := range fixtures.TestSourceRhcConnectionData {
-		if s.SourceId == rhcConnection.Sources[0].ID {
-			for _, r := range fixtures.TestRhcConnectionData {
-				if s.RhcConnectionId == r.ID && r.RhcId == rhcConnection.RhcId {
-					relationExists = true
-				}
+func (mr *MockRhcConnectionDao) LatestPerSource(sourceIds []int64) (map[int64]*m.RhcConnection, error) {
+	latest := make(map[int64]*m.RhcConnection)
+	for _, sourceId := range sourceIds {
+		for _, link := range fixtures.TestSourceRhcConnectionData {
+			if link.SourceId != sourceId {
+				continue
 			}
-		}
+
+			for i, rhcConnection := range mr.RhcConnections {
+				if rhcConnection.ID != link.RhcConnectionId {
+					continue
+				}
+
+				current, ok := latest[sourceId]
+				if !ok || rhcConnection.CreatedAt.After(current.CreatedAt) || (rhcConnection.CreatedAt.Equal(current.CreatedAt) && rhcConnection.ID > current.ID) {
+					latest[sourceId] = &mr.RhcConnections[i]
+				}
+			}
+		}
+	}
+
+	return latest, nil
+}
+
+func (mr *MockRhcConnectionDao) ListModifiedBy(actor string, since time.Time, limit, offset int) ([]m.RhcConnection, int64, error) {
+	rhcConnections := make([]m.RhcConnection, 0)
+	for _, rhcConnection := range mr.RhcConnections {
+		if rhcConnection.UpdatedBy == actor && !rhcConnection.UpdatedAt.Before(since) {
+			rhcConnections = append(rhcConnections, rhcConnection)
+		}
+	}
+
+	return rhcConnections, int64(len(rhcConnections)), nil
+}
+
+func (mr *MockRhcConnectionDao) ListStatusHistory(rhcConnectionId int64, tenantId int64, limit int) ([]m.RhcConnectionStatusEvent, error) {
+	for _, rhcConnection := range mr.RhcConnections {
+		if rhcConnection.ID == rhcConnectionId && rhcConnection.TenantId == tenantId {
+			return []m.RhcConnectionStatusEvent{}, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("rhcConnection")
+}
+
+func (mr *MockRhcConnectionDao) GetBySourceAndRhcId(sourceId *int64, rhcId string) (*m.RhcConnection, error) {
+	for _, s := range fixtures.TestSourceRhcConnectionData {
+		if s.SourceId != *sourceId {
+			continue
+		}
+
+		for _, rhcConnection := range mr.RhcConnections {
+			if rhcConnection.ID == s.RhcConnectionId && rhcConnection.RhcId == rhcId {
+				rhcConnection.Sources = []m.Source{{ID: s.SourceId}}
+				return &rhcConnection, nil
+			}
+		}
+	}
+
+	return nil, util.NewErrNotFound("rhcConnection")
+}
+
+func (mr *MockRhcConnectionDao) Create(rhcConnection *m.RhcConnection) (*m.RhcConnection, error) {
+	// Check if in fixtures is a source with given source id
+	var sourceExists bool
+	for _, src := range fixtures.TestSourceData {
+		if src.ID == rhcConnection.Sources[0].ID {
+			sourceExists = true
+		}
+	}
+
+	if !sourceExists {
+		return nil, util.NewErrNotFound("source")
+	}
+
+	// Check if in fixtures exists same relation
+	var relationExists bool
+	for _, s := range fixtures.TestSourceRhcConnectionData {
+		if s.SourceId == rhcConnection.Sources[0].ID {
+			for _, r := range fixtures.TestRhcConnectionData {
+				if s.RhcConnectionId == r.ID && r.RhcId == rhcConnection.RhcId {
+					relationExists = true
+				}
+			}
+		}
 	}
 
 	if relationExists {
@@ -669,19 +1212,128 @@ func (mr *MockRhcConnectionDao) Create(rhcConnection *m.RhcConnection) (*m.RhcCo
 	return rhcConnection, nil
 }
 
-func (m *MockRhcConnectionDao) Update(rhcConnection *m.RhcConnection) error {
+func (mr *MockRhcConnectionDao) CreateOrLink(rhcId string, sourceId int64) (*m.RhcConnection, bool, error) {
+	var sourceExists bool
+	for _, src := range fixtures.TestSourceData {
+		if src.ID == sourceId {
+			sourceExists = true
+		}
+	}
+
+	if !sourceExists {
+		return nil, false, util.NewErrNotFound("source")
+	}
+
+	for _, rhcConnection := range fixtures.TestRhcConnectionData {
+		if rhcConnection.RhcId != rhcId {
+			continue
+		}
+
+		for _, link := range fixtures.TestSourceRhcConnectionData {
+			if link.SourceId == sourceId && link.RhcConnectionId == rhcConnection.ID {
+				rhcConnection.Sources = []m.Source{{ID: sourceId}}
+				return &rhcConnection, false, nil
+			}
+		}
+
+		rhcConnection.Sources = []m.Source{{ID: sourceId}}
+		return &rhcConnection, true, nil
+	}
+
+	return &m.RhcConnection{RhcId: rhcId, Sources: []m.Source{{ID: sourceId}}}, true, nil
+}
+
+func (m *MockRhcConnectionDao) Update(rhcConnection *m.RhcConnection) (int64, error) {
 	for _, rhcTmp := range m.RhcConnections {
 		if rhcTmp.ID == rhcConnection.ID {
-			return nil
+			if rhcConnection.RhcId != "" && rhcConnection.RhcId != rhcTmp.RhcId {
+				return 0, util.NewErrBadRequest(`the "rhc_id" of a connection cannot be modified`)
+			}
+
+			if rhcConnection.TenantId != 0 && rhcConnection.TenantId != rhcTmp.TenantId {
+				return 0, util.NewErrBadRequest("the tenant of a connection cannot be modified")
+			}
+
+			return 1, nil
 		}
 	}
 
-	return util.NewErrNotFound("rhcConnection")
+	return 0, nil
 }
 
-func (m *MockRhcConnectionDao) Delete(id *int64) (*m.RhcConnection, error) {
-	for _, rhcTmp := range m.RhcConnections {
+func (mr *MockRhcConnectionDao) AddTags(id int64, tags map[string]string) (*m.RhcConnection, error) {
+	if len(tags) == 0 {
+		return nil, util.NewErrBadRequest("at least one tag is required")
+	}
+
+	err := validateTags(tags)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	return mr.updateTags(id, func(current map[string]string) {
+		for key, value := range tags {
+			current[key] = value
+		}
+	})
+}
+
+func (mr *MockRhcConnectionDao) RemoveTags(id int64, keys []string) (*m.RhcConnection, error) {
+	if len(keys) == 0 {
+		return nil, util.NewErrBadRequest("at least one tag key is required")
+	}
+
+	for _, key := range keys {
+		err := validateTagKey(key)
+		if err != nil {
+			return nil, util.NewErrBadRequest(err)
+		}
+	}
+
+	return mr.updateTags(id, func(current map[string]string) {
+		for _, key := range keys {
+			delete(current, key)
+		}
+	})
+}
+
+// updateTags applies the given modification to the tags of the mocked connection.
+func (mr *MockRhcConnectionDao) updateTags(id int64, modify func(current map[string]string)) (*m.RhcConnection, error) {
+	for i, rhcConnection := range mr.RhcConnections {
+		if rhcConnection.ID != id {
+			continue
+		}
+
+		current := make(map[string]string)
+		if len(rhcConnection.Tags) > 0 {
+			err := json.Unmarshal(rhcConnection.Tags, &current)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		modify(current)
+
+		tags, err := json.Marshal(current)
+		if err != nil {
+			return nil, err
+		}
+
+		mr.RhcConnections[i].Tags = tags
+
+		return &mr.RhcConnections[i], nil
+	}
+
+	return nil, util.NewErrNotFound("rhcConnection")
+}
+
+func (mr *MockRhcConnectionDao) Delete(id *int64, force bool) (*m.RhcConnection, error) {
+	for _, rhcTmp := range mr.RhcConnections {
 		if rhcTmp.ID == *id {
+			if rhcConnectionDeleteGuard && !force && mockRhcConnectionInUse(*id) {
+				return nil, util.NewErrBadRequest("connection in use")
+			}
+
 			return &rhcTmp, nil
 		}
 	}
@@ -689,12 +1341,344 @@ func (m *MockRhcConnectionDao) Delete(id *int64) (*m.RhcConnection, error) {
 	return nil, util.NewErrNotFound("rhcConnection")
 }
 
+// mockRhcConnectionInUse returns true when the connection is linked to any available fixture source.
+func mockRhcConnectionInUse(rhcConnectionId int64) bool {
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.RhcConnectionId != rhcConnectionId {
+			continue
+		}
+
+		for _, source := range fixtures.TestSourceData {
+			if source.ID == link.SourceId && source.AvailabilityStatus == m.Available {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (mr *MockRhcConnectionDao) UnlinkFromSource(rhcConnectionId, sourceId, tenantId int64) (*m.RhcConnection, bool, error) {
+	linked := false
+	otherLinks := 0
+	var remainingSources []m.Source
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.RhcConnectionId != rhcConnectionId {
+			continue
+		}
+
+		if link.SourceId == sourceId && link.TenantId == tenantId {
+			linked = true
+			continue
+		}
+
+		otherLinks++
+		if link.TenantId == tenantId {
+			remainingSources = append(remainingSources, m.Source{ID: link.SourceId})
+		}
+	}
+
+	if !linked {
+		return nil, false, util.NewErrNotFound("rhcConnection")
+	}
+
+	for _, rhcConnection := range mr.RhcConnections {
+		if rhcConnection.ID == rhcConnectionId {
+			rhcConnection.Sources = remainingSources
+			return &rhcConnection, otherLinks == 0, nil
+		}
+	}
+
+	return nil, false, util.NewErrNotFound("rhcConnection")
+}
+
+func (m *MockRhcConnectionDao) DeleteIfExists(id *int64) (bool, *m.RhcConnection, error) {
+	for _, rhcTmp := range m.RhcConnections {
+		if rhcTmp.ID == *id {
+			return true, &rhcTmp, nil
+		}
+	}
+
+	return false, nil, nil
+}
+
+func (m *MockRhcConnectionDao) ListByApplicationType(appTypeId int64, limit, offset int) ([]m.RhcConnection, int64, error) {
+	count := int64(len(m.RelatedRhcConnections))
+
+	return m.RelatedRhcConnections, count, nil
+}
+
+func (mr *MockRhcConnectionDao) ListBySourceName(namePrefix string, limit, offset int) ([]m.RhcConnection, int64, error) {
+	if strings.TrimSpace(namePrefix) == "" {
+		return nil, 0, util.NewErrBadRequest("the source name prefix cannot be empty")
+	}
+
+	rhcConnections := make([]m.RhcConnection, 0)
+	for _, rhcConnection := range mr.RhcConnections {
+		for _, link := range fixtures.TestSourceRhcConnectionData {
+			if link.RhcConnectionId != rhcConnection.ID {
+				continue
+			}
+
+			if mockSourceNameHasPrefix(link.SourceId, namePrefix) {
+				rhcConnections = append(rhcConnections, rhcConnection)
+				break
+			}
+		}
+	}
+
+	return rhcConnections, int64(len(rhcConnections)), nil
+}
+
+// mockSourceNameHasPrefix returns true when the fixture source's name starts with the given prefix, regardless of the
+// case.
+func mockSourceNameHasPrefix(sourceId int64, namePrefix string) bool {
+	for _, source := range fixtures.TestSourceData {
+		if source.ID == sourceId {
+			return strings.HasPrefix(strings.ToLower(source.Name), strings.ToLower(namePrefix))
+		}
+	}
+
+	return false
+}
+
+func (m *MockRhcConnectionDao) ListShared(minSources int, limit, offset int) ([]m.RhcConnection, int64, error) {
+	count := int64(len(m.RelatedRhcConnections))
+
+	return m.RelatedRhcConnections, count, nil
+}
+
 func (m *MockRhcConnectionDao) ListForSource(sourceId *int64, limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error) {
 	count := int64(len(m.RelatedRhcConnections))
 
 	return m.RelatedRhcConnections, count, nil
 }
 
+func (mr *MockRhcConnectionDao) ListWithSources(_ context.Context, limit, offset int, filters []util.Filter) ([]m.RhcConnectionWithSources, int64, error) {
+	rhcConnections := make([]m.RhcConnectionWithSources, 0, len(mr.RhcConnections))
+	for _, rhcConnection := range mr.RhcConnections {
+		rhcConnectionWithSources := m.RhcConnectionWithSources{RhcConnection: rhcConnection, Sources: []m.Source{}}
+
+		for _, link := range fixtures.TestSourceRhcConnectionData {
+			if link.RhcConnectionId != rhcConnection.ID {
+				continue
+			}
+
+			for _, source := range fixtures.TestSourceData {
+				if source.ID == link.SourceId {
+					rhcConnectionWithSources.Sources = append(rhcConnectionWithSources.Sources, source)
+				}
+			}
+		}
+
+		rhcConnections = append(rhcConnections, rhcConnectionWithSources)
+	}
+
+	return rhcConnections, int64(len(rhcConnections)), nil
+}
+
+func (mr *MockRhcConnectionDao) ListWithSourceAvailability(limit, offset int) ([]m.RhcConnectionWithAvailability, int64, error) {
+	rhcConnections := make([]m.RhcConnectionWithAvailability, 0, len(mr.RhcConnections))
+	for _, rhcConnection := range mr.RhcConnections {
+		rhcConnectionWithAvailability := m.RhcConnectionWithAvailability{RhcConnection: rhcConnection}
+
+		for _, link := range fixtures.TestSourceRhcConnectionData {
+			if link.RhcConnectionId != rhcConnection.ID {
+				continue
+			}
+
+			for _, source := range fixtures.TestSourceData {
+				if source.ID == link.SourceId {
+					rhcConnectionWithAvailability.AddSourcesAvailability(source.AvailabilityStatus, 1)
+				}
+			}
+		}
+
+		rhcConnections = append(rhcConnections, rhcConnectionWithAvailability)
+	}
+
+	return rhcConnections, int64(len(rhcConnections)), nil
+}
+
+func (mr *MockRhcConnectionDao) AdminListForSource(sourceId *int64, includeDeleted bool) ([]m.RhcConnectionAdminEntry, error) {
+	entries := make([]m.RhcConnectionAdminEntry, 0)
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.SourceId != *sourceId {
+			continue
+		}
+
+		for _, rhcConnection := range mr.RhcConnections {
+			if rhcConnection.ID == link.RhcConnectionId {
+				entries = append(entries, m.RhcConnectionAdminEntry{RhcConnection: rhcConnection})
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func (mr *MockRhcConnectionDao) ListForSourceUID(sourceUID string, limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error) {
+	for _, source := range fixtures.TestSourceData {
+		if source.Uid != nil && *source.Uid == sourceUID {
+			return mr.ListForSource(&source.ID, limit, offset, filters)
+		}
+	}
+
+	return nil, 0, util.NewErrNotFound("source")
+}
+
+func (mr *MockRhcConnectionDao) SummaryBySource(tenantId int64) ([]m.RhcConnectionSourceSummary, error) {
+	summaries := make(map[int64]*m.RhcConnectionSourceSummary)
+	sourceIds := make([]int64, 0)
+	for _, rhcConnection := range mr.RhcConnections {
+		for _, source := range rhcConnection.Sources {
+			if source.TenantID != tenantId {
+				continue
+			}
+
+			summary, ok := summaries[source.ID]
+			if !ok {
+				summary = &m.RhcConnectionSourceSummary{SourceId: source.ID, SourceName: source.Name}
+				summaries[source.ID] = summary
+				sourceIds = append(sourceIds, source.ID)
+			}
+
+			summary.ConnectionCount++
+			if rhcConnection.AvailabilityStatus == m.Available {
+				summary.AvailableCount++
+			}
+		}
+	}
+
+	sort.Slice(sourceIds, func(i, j int) bool { return sourceIds[i] < sourceIds[j] })
+
+	out := make([]m.RhcConnectionSourceSummary, 0, len(sourceIds))
+	for _, sourceId := range sourceIds {
+		out = append(out, *summaries[sourceId])
+	}
+
+	return out, nil
+}
+
+func (mr *MockRhcConnectionDao) CountForTenant() (int64, error) {
+	return int64(len(mr.RhcConnections)), nil
+}
+
+func (mr *MockRhcConnectionDao) CountSnapshot() ([]m.RhcConnectionCountSnapshot, error) {
+	counts := make(map[int64]int64)
+	for _, rhcConnection := range mr.RhcConnections {
+		counts[rhcConnection.TenantId]++
+	}
+
+	snapshot := make([]m.RhcConnectionCountSnapshot, 0, len(counts))
+	for tenantId, count := range counts {
+		snapshot = append(snapshot, m.RhcConnectionCountSnapshot{TenantID: tenantId, Count: count})
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].TenantID < snapshot[j].TenantID })
+
+	return snapshot, nil
+}
+
+func (mr *MockRhcConnectionDao) ProbeConnection(id *int64) (bool, string, error) {
+	rhcConnection, err := mr.GetById(id)
+	if err != nil {
+		return false, "", err
+	}
+
+	return RhcProber.Probe(m.RhcConnectionAvailabilityCheck{RhcConnectionId: rhcConnection.ID, RhcId: rhcConnection.RhcId})
+}
+
+func (mr *MockRhcConnectionDao) RegisterHook(_ DaoHook) {}
+
+func (mr *MockRhcConnectionDao) Deduplicate(rhcId string) (*m.RhcConnection, error) {
+	deduplication, err := mr.DeduplicateDryRun(rhcId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deduplication.Canonical, nil
+}
+
+func (mr *MockRhcConnectionDao) CheckIntegrity() (m.IntegrityReport, error) {
+	report := m.IntegrityReport{
+		OrphanedRhcConnections:    m.IntegrityIssue{SampleIds: []int64{}},
+		MissingSourceLinks:        m.IntegrityLinkIssue{SampleLinks: []m.IntegrityLinkId{}},
+		MissingRhcConnectionLinks: m.IntegrityLinkIssue{SampleLinks: []m.IntegrityLinkId{}},
+		TenantMismatchedLinks:     m.IntegrityLinkIssue{SampleLinks: []m.IntegrityLinkId{}},
+	}
+
+	for _, rhcConnection := range mr.RhcConnections {
+		linked := false
+		for _, link := range fixtures.TestSourceRhcConnectionData {
+			if link.RhcConnectionId == rhcConnection.ID {
+				linked = true
+				break
+			}
+		}
+
+		if !linked {
+			report.OrphanedRhcConnections.Count++
+			report.OrphanedRhcConnections.SampleIds = append(report.OrphanedRhcConnections.SampleIds, rhcConnection.ID)
+		}
+	}
+
+	return report, nil
+}
+
+func (mr *MockRhcConnectionDao) DeduplicateDryRun(rhcId string) (*m.RhcConnectionDeduplication, error) {
+	var deduplication *m.RhcConnectionDeduplication
+	for _, rhcConnection := range mr.RhcConnections {
+		if rhcConnection.RhcId != rhcId {
+			continue
+		}
+
+		if deduplication == nil {
+			deduplication = &m.RhcConnectionDeduplication{Canonical: rhcConnection}
+		} else {
+			deduplication.DuplicateIds = append(deduplication.DuplicateIds, rhcConnection.ID)
+		}
+	}
+
+	if deduplication == nil {
+		return nil, util.NewErrNotFound("rhcConnection")
+	}
+
+	return deduplication, nil
+}
+
+func (mr *MockRhcConnectionDao) Merge(winnerId, loserId *int64) (*m.RhcConnection, error) {
+	merge, err := mr.MergeDryRun(winnerId, loserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &merge.Canonical, nil
+}
+
+func (mr *MockRhcConnectionDao) MergeDryRun(winnerId, loserId *int64) (*m.RhcConnectionDeduplication, error) {
+	if *winnerId == *loserId {
+		return nil, util.NewErrBadRequest("a connection cannot be merged into itself")
+	}
+
+	var winner *m.RhcConnection
+	loserFound := false
+	for i := range mr.RhcConnections {
+		switch mr.RhcConnections[i].ID {
+		case *winnerId:
+			winner = &mr.RhcConnections[i]
+		case *loserId:
+			loserFound = true
+		}
+	}
+
+	if winner == nil || !loserFound {
+		return nil, util.NewErrNotFound("rhcConnection")
+	}
+
+	return &m.RhcConnectionDeduplication{Canonical: *winner, DuplicateIds: []int64{*loserId}}, nil
+}
+
 func (m MockApplicationAuthenticationDao) List(limit, offset int, filters []util.Filter) ([]m.ApplicationAuthentication, int64, error) {
 	count := int64(len(m.ApplicationAuthentications))
 	return m.ApplicationAuthentications, count, nil
@@ -929,3 +1913,334 @@ func (mad MockAuthenticationDao) ListIdsForResource(resourceType string, resourc
 func (m MockAuthenticationDao) BulkDelete(authentications []m.Authentication) ([]m.Authentication, error) {
 	return authentications, nil
 }
+
+func (m MockAuthenticationDao) TouchLastUsed(authId int64) error {
+	for _, auth := range m.Authentications {
+		if auth.DbID == authId {
+			return nil
+		}
+	}
+
+	return util.NewErrNotFound("authentication")
+}
+
+func (mad MockAuthenticationDao) ListStale(usedBefore time.Time, limit, offset int) ([]m.Authentication, int64, error) {
+	var stale []m.Authentication
+	for _, auth := range mad.Authentications {
+		if auth.LastUsedAt != nil && auth.LastUsedAt.Before(usedBefore) {
+			stale = append(stale, auth)
+		}
+	}
+
+	return stale, int64(len(stale)), nil
+}
+
+func (mad MockAuthenticationDao) ListExpiringSoon(tenantId int64, within time.Duration) ([]m.Authentication, error) {
+	expiresBefore := time.Now().Add(within)
+
+	expiring := make([]m.Authentication, 0)
+	for _, auth := range mad.Authentications {
+		if auth.TenantID == tenantId && auth.ExpiresAt != nil && auth.ExpiresAt.Before(expiresBefore) {
+			expiring = append(expiring, auth)
+		}
+	}
+
+	return expiring, nil
+}
+
+func (mad MockAuthenticationDao) ListTenantsWithExpiringSoon(within time.Duration) ([]int64, error) {
+	expiresBefore := time.Now().Add(within)
+
+	seen := make(map[int64]bool)
+	tenantIds := make([]int64, 0)
+	for _, auth := range mad.Authentications {
+		if auth.ExpiresAt != nil && auth.ExpiresAt.Before(expiresBefore) && !seen[auth.TenantID] {
+			seen[auth.TenantID] = true
+			tenantIds = append(tenantIds, auth.TenantID)
+		}
+	}
+
+	return tenantIds, nil
+}
+
+func (mad MockAuthenticationDao) ListExpiringBefore(_ context.Context, tenantId int64, before time.Time, limit, offset int) ([]m.Authentication, int64, error) {
+	expiring := make([]m.Authentication, 0)
+	for _, auth := range mad.Authentications {
+		if auth.TenantID == tenantId && auth.ExpiresAt != nil && !auth.ExpiresAt.After(before) {
+			expiring = append(expiring, auth)
+		}
+	}
+
+	sort.SliceStable(expiring, func(i, j int) bool { return expiring[i].ExpiresAt.Before(*expiring[j].ExpiresAt) })
+
+	count := int64(len(expiring))
+	if offset >= len(expiring) {
+		return []m.Authentication{}, count, nil
+	}
+
+	end := offset + limit
+	if end > len(expiring) {
+		end = len(expiring)
+	}
+
+	return expiring[offset:end], count, nil
+}
+
+// MockTenantOnboardingDao onboards the tenants by looking them up in the given tenants.
+type MockTenantOnboardingDao struct {
+	Tenants []m.Tenant
+}
+
+func (mt *MockTenantOnboardingDao) EnsureOnboarded(id *identity.Identity) (*m.Tenant, error) {
+	for _, tenant := range mt.Tenants {
+		if (id.OrgID != "" && tenant.OrgID == id.OrgID) || (id.AccountNumber != "" && tenant.ExternalTenant == id.AccountNumber) {
+			return &tenant, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("tenant")
+}
+
+func (mt *MockTenantStatsDao) GetStats(tenantId int64) (*m.TenantStats, error) {
+	for _, stats := range mt.Stats {
+		if stats.TenantId == tenantId {
+			return &stats, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("tenant")
+}
+
+func (mk *MockKafkaOffsetDao) List() ([]m.KafkaOffset, error) {
+	return mk.Offsets, nil
+}
+
+func (mk *MockKafkaOffsetDao) GetOffset(group, topic string, partition int) (int64, error) {
+	for _, kafkaOffset := range mk.Offsets {
+		if kafkaOffset.ConsumerGroup == group && kafkaOffset.Topic == topic && kafkaOffset.Partition == partition {
+			return kafkaOffset.Offset, nil
+		}
+	}
+
+	return 0, util.NewErrNotFound("kafka offset")
+}
+
+func (mk *MockKafkaOffsetDao) SetOffset(group, topic string, partition int, offset int64) error {
+	for i, kafkaOffset := range mk.Offsets {
+		if kafkaOffset.ConsumerGroup == group && kafkaOffset.Topic == topic && kafkaOffset.Partition == partition {
+			if offset > kafkaOffset.Offset {
+				mk.Offsets[i].Offset = offset
+			}
+
+			return nil
+		}
+	}
+
+	mk.Offsets = append(mk.Offsets, m.KafkaOffset{ConsumerGroup: group, Topic: topic, Partition: partition, Offset: offset})
+
+	return nil
+}
+
+// ResetToTime resets the offsets of the topic to the beginning, since there is no broker to look the offsets up.
+func (mk *MockKafkaOffsetDao) ResetToTime(group, topic string, _ time.Time) error {
+	found := false
+	for i, kafkaOffset := range mk.Offsets {
+		if kafkaOffset.ConsumerGroup == group && kafkaOffset.Topic == topic {
+			mk.Offsets[i].Offset = 0
+			found = true
+		}
+	}
+
+	if !found {
+		return util.NewErrNotFound("kafka topic")
+	}
+
+	return nil
+}
+
+func (md *MockDeadLetterDao) List(topic, status string, limit, offset int) ([]m.DeadLetterMessage, int64, error) {
+	if status != "" && !util.SliceContainsString(m.DeadLetterStatuses, status) {
+		return nil, 0, util.NewErrBadRequest(fmt.Sprintf(`invalid status "%s"`, status))
+	}
+
+	deadLetters := make([]m.DeadLetterMessage, 0)
+	for _, deadLetter := range md.DeadLetters {
+		if (topic == "" || deadLetter.Topic == topic) && (status == "" || deadLetter.Status == status) {
+			deadLetters = append(deadLetters, deadLetter)
+		}
+	}
+
+	count := int64(len(deadLetters))
+	if offset > len(deadLetters) {
+		offset = len(deadLetters)
+	}
+
+	deadLetters = deadLetters[offset:]
+	if limit < len(deadLetters) {
+		deadLetters = deadLetters[:limit]
+	}
+
+	return deadLetters, count, nil
+}
+
+func (md *MockDeadLetterDao) Create(deadLetter *m.DeadLetterMessage) error {
+	if deadLetter.Status == "" {
+		deadLetter.Status = m.DeadLetterPending
+	}
+
+	md.DeadLetters = append(md.DeadLetters, *deadLetter)
+
+	return nil
+}
+
+// Requeue marks the dead letter message as replayed, since there is no broker to publish it to.
+func (md *MockDeadLetterDao) Requeue(id int64) (*m.DeadLetterMessage, error) {
+	for i, deadLetter := range md.DeadLetters {
+		if deadLetter.ID == id {
+			now := time.Now()
+			md.DeadLetters[i].Status = m.DeadLetterReplayed
+			md.DeadLetters[i].RequeuedAt = &now
+
+			return &md.DeadLetters[i], nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("dead letter message")
+}
+
+func (ms *MockSourceTypeFlagDao) GetAll(sourceTypeId int64) ([]m.SourceTypeFlag, error) {
+	if !mockSourceTypeExists(sourceTypeId) {
+		return nil, util.NewErrNotFound("source type")
+	}
+
+	flags := make([]m.SourceTypeFlag, 0)
+	for _, flag := range ms.Flags {
+		if flag.SourceTypeId == sourceTypeId {
+			flags = append(flags, flag)
+		}
+	}
+
+	return flags, nil
+}
+
+func (ms *MockSourceTypeFlagDao) BulkSet(sourceTypeId int64, flags map[string]bool) error {
+	err := validateSourceTypeFlags(flags)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	if !mockSourceTypeExists(sourceTypeId) {
+		return util.NewErrNotFound("source type")
+	}
+
+	for name, enabled := range flags {
+		found := false
+		for i, flag := range ms.Flags {
+			if flag.SourceTypeId == sourceTypeId && flag.Name == name {
+				ms.Flags[i].Enabled = enabled
+				found = true
+			}
+		}
+
+		if !found {
+			ms.Flags = append(ms.Flags, m.SourceTypeFlag{SourceTypeId: sourceTypeId, Name: name, Enabled: enabled})
+		}
+	}
+
+	return nil
+}
+
+// mockSourceTypeExists returns true when the source type is one of the fixtures.
+func mockSourceTypeExists(sourceTypeId int64) bool {
+	for _, sourceType := range fixtures.TestSourceTypeData {
+		if sourceType.Id == sourceTypeId {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (mc *MockCyndiStatusDao) GetReplicationLag() (int64, error) {
+	if mc.Err != nil {
+		return 0, mc.Err
+	}
+
+	return mc.Lag, nil
+}
+
+func (ma *MockAuditLogDao) Create(auditLog *m.AuditLog) error {
+	ma.AuditLogs = append(ma.AuditLogs, *auditLog)
+
+	return nil
+}
+
+func (ma *MockAuditLogDao) StreamExport(tenantId int64, from, to time.Time, w io.Writer) error {
+	err := validateAuditLogPeriod(from, to)
+	if err != nil {
+		return err
+	}
+
+	csvWriter := csv.NewWriter(w)
+	err = csvWriter.Write(m.AuditLogCsvHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, auditLog := range ma.AuditLogs {
+		if auditLog.TenantId != tenantId || auditLog.CreatedAt.Before(from) || !auditLog.CreatedAt.Before(to) {
+			continue
+		}
+
+		err = csvWriter.Write(auditLog.ToCsvRecord())
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func (ma *MockAvailabilityScheduleDao) Upsert(sourceId int64, cronExpr string) error {
+	nextRunAt, err := nextRun(cronExpr, time.Now())
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	schedule := m.AvailabilitySchedule{SourceId: sourceId, CronExpr: cronExpr, NextRunAt: nextRunAt}
+	for i := range ma.Schedules {
+		if ma.Schedules[i].SourceId == sourceId {
+			ma.Schedules[i] = schedule
+			return nil
+		}
+	}
+
+	ma.Schedules = append(ma.Schedules, schedule)
+
+	return nil
+}
+
+func (ma *MockAvailabilityScheduleDao) ClaimDue(asOf time.Time, limit int) ([]m.AvailabilitySchedule, error) {
+	var due []m.AvailabilitySchedule
+	for i := range ma.Schedules {
+		if len(due) == limit {
+			break
+		}
+
+		if ma.Schedules[i].NextRunAt.After(asOf) {
+			continue
+		}
+
+		nextRunAt, err := nextRun(ma.Schedules[i].CronExpr, asOf)
+		if err != nil {
+			return nil, err
+		}
+
+		ma.Schedules[i].LastRunAt = &asOf
+		ma.Schedules[i].NextRunAt = nextRunAt
+		due = append(due, ma.Schedules[i])
+	}
+
+	return due, nil
+}
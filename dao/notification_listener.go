@@ -0,0 +1,182 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// notificationSubscriptionBuffer is how many notifications a subscriber can have pending before it is considered to
+// have fallen behind.
+const notificationSubscriptionBuffer = 32
+
+// ErrTooManyListeners is returned when a notification listener already has as many subscribers as it accepts.
+var ErrTooManyListeners = errors.New("too many clients are already listening for notifications")
+
+// ErrListenerFellBehind is reported to the subscribers which don't keep up with the notifications, right before they
+// get dropped.
+var ErrListenerFellBehind = errors.New("the client fell behind the notifications")
+
+// notificationConn is the part of a dedicated database connection which is needed to listen for notifications.
+type notificationConn interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	WaitForNotification(ctx context.Context) (*pgconn.Notification, error)
+	Close(ctx context.Context) error
+}
+
+// notificationSubscription receives the payloads of the notifications sent to the listener's channel. When the
+// subscription ends because of a failure, the error is sent through "errs".
+type notificationSubscription struct {
+	payloads chan string
+	errs     chan error
+}
+
+// notificationListener shares a single "LISTEN" connection between all its subscribers, and fans the notifications
+// sent to its channel out to them. The connection gets opened along with the first subscription and closed when the
+// last one goes away.
+type notificationListener struct {
+	channel        string
+	maxSubscribers int
+	connect        func(ctx context.Context) (notificationConn, error)
+
+	mutex       sync.Mutex
+	subscribers map[*notificationSubscription]struct{}
+	// current is the context of the running connection, which gets cancelled to close it.
+	current context.Context
+	stop    context.CancelFunc
+}
+
+// newNotificationListener returns a listener for the given channel which accepts up to "maxSubscribers" subscribers.
+func newNotificationListener(channel string, maxSubscribers int) *notificationListener {
+	return &notificationListener{
+		channel:        channel,
+		maxSubscribers: maxSubscribers,
+		connect: func(ctx context.Context) (notificationConn, error) {
+			// A dedicated connection is required since the "LISTEN" command binds the notifications to the
+			// connection it was issued on, which means we cannot use a connection from the pool.
+			return pgx.Connect(ctx, dbString())
+		},
+		subscribers: make(map[*notificationSubscription]struct{}),
+	}
+}
+
+// subscribe starts receiving the notifications of the listener's channel, opening the shared connection if needed.
+func (l *notificationListener) subscribe() (*notificationSubscription, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if len(l.subscribers) >= l.maxSubscribers {
+		return nil, ErrTooManyListeners
+	}
+
+	subscription := &notificationSubscription{
+		payloads: make(chan string, notificationSubscriptionBuffer),
+		errs:     make(chan error, 1),
+	}
+	l.subscribers[subscription] = struct{}{}
+
+	if l.current == nil {
+		l.current, l.stop = context.WithCancel(context.Background())
+		go l.listen(l.current)
+	}
+
+	return subscription, nil
+}
+
+// unsubscribe stops sending notifications to the given subscription, and closes the shared connection when nobody
+// else is listening.
+func (l *notificationListener) unsubscribe(subscription *notificationSubscription) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	delete(l.subscribers, subscription)
+	l.stopIfUnused()
+}
+
+// stopIfUnused closes the shared connection when there are no subscribers left. The caller must hold the mutex.
+func (l *notificationListener) stopIfUnused() {
+	if len(l.subscribers) == 0 && l.current != nil {
+		l.stop()
+		l.current, l.stop = nil, nil
+	}
+}
+
+// listen receives the notifications through a new connection until the given context is cancelled. If the
+// connection fails, the error is reported to all the subscribers, which get dropped.
+func (l *notificationListener) listen(ctx context.Context) {
+	err := l.receive(ctx)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// The connection was closed on purpose, or a newer one is already serving the subscribers.
+	if ctx.Err() != nil || l.current != ctx {
+		return
+	}
+
+	logging.Log.Warnf(`the listener of the "%s" notifications failed: %s`, l.channel, err)
+
+	for subscription := range l.subscribers {
+		subscription.errs <- err
+		delete(l.subscribers, subscription)
+	}
+
+	l.stopIfUnused()
+}
+
+// receive opens the connection, starts listening for the channel's notifications and publishes them until either
+// the connection fails or the context is cancelled.
+func (l *notificationListener) receive(ctx context.Context) error {
+	conn, err := l.connect(ctx)
+	if err != nil {
+		return fmt.Errorf("could not open a connection to listen for notifications: %w", err)
+	}
+	defer func() {
+		// The context is most likely done by now, so a fresh one is needed to close the connection.
+		err := conn.Close(context.Background())
+		if err != nil {
+			logging.Log.Warnf(`could not close the connection which listens for the "%s" notifications: %s`, l.channel, err)
+		}
+	}()
+
+	_, err = conn.Exec(ctx, "LISTEN "+l.channel)
+	if err != nil {
+		return fmt.Errorf("could not listen for notifications: %w", err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		l.publish(ctx, notification.Payload)
+	}
+}
+
+// publish sends the payload to every subscriber. The subscribers which have too many pending notifications are
+// dropped, so that they don't hold the rest back.
+func (l *notificationListener) publish(ctx context.Context, payload string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.current != ctx {
+		return
+	}
+
+	for subscription := range l.subscribers {
+		select {
+		case subscription.payloads <- payload:
+		default:
+			subscription.errs <- ErrListenerFellBehind
+			delete(l.subscribers, subscription)
+		}
+	}
+
+	l.stopIfUnused()
+}
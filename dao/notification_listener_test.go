@@ -0,0 +1,190 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+)
+
+// fakeNotificationConn delivers the notifications sent through its channel, and fails with the error sent through
+// "failures".
+type fakeNotificationConn struct {
+	notifications chan string
+	failures      chan error
+	closed        chan struct{}
+	closeOnce     sync.Once
+}
+
+func newFakeNotificationConn() *fakeNotificationConn {
+	return &fakeNotificationConn{
+		notifications: make(chan string),
+		failures:      make(chan error),
+		closed:        make(chan struct{}),
+	}
+}
+
+func (f *fakeNotificationConn) Exec(_ context.Context, _ string, _ ...interface{}) (pgconn.CommandTag, error) {
+	return nil, nil
+}
+
+func (f *fakeNotificationConn) WaitForNotification(ctx context.Context) (*pgconn.Notification, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-f.failures:
+		return nil, err
+	case payload := <-f.notifications:
+		return &pgconn.Notification{Payload: payload}, nil
+	}
+}
+
+func (f *fakeNotificationConn) Close(_ context.Context) error {
+	f.closeOnce.Do(func() { close(f.closed) })
+
+	return nil
+}
+
+// newFakeNotificationListener returns a listener which counts the connections it opens, all of them being the given
+// fake one.
+func newFakeNotificationListener(conn *fakeNotificationConn, maxSubscribers int, connections *int) *notificationListener {
+	listener := newNotificationListener("test", maxSubscribers)
+	listener.connect = func(ctx context.Context) (notificationConn, error) {
+		*connections++
+		return conn, nil
+	}
+
+	return listener
+}
+
+// receivePayload waits for a payload of the given subscription.
+func receivePayload(t *testing.T, subscription *notificationSubscription) string {
+	t.Helper()
+
+	select {
+	case payload := <-subscription.payloads:
+		return payload
+	case err := <-subscription.errs:
+		t.Fatalf(`want a payload, got the error "%s"`, err)
+	case <-time.After(time.Second):
+		t.Fatalf(`want a payload, got nothing`)
+	}
+
+	return ""
+}
+
+// TestNotificationListenerSharesConnection tests that all the subscribers get the notifications through a single
+// connection, which gets closed once the last subscriber goes away.
+func TestNotificationListenerSharesConnection(t *testing.T) {
+	conn := newFakeNotificationConn()
+	connections := 0
+	listener := newFakeNotificationListener(conn, 10, &connections)
+
+	first, err := listener.subscribe()
+	if err != nil {
+		t.Fatalf(`want no errors, got "%s"`, err)
+	}
+
+	second, err := listener.subscribe()
+	if err != nil {
+		t.Fatalf(`want no errors, got "%s"`, err)
+	}
+
+	conn.notifications <- "hello"
+
+	for _, subscription := range []*notificationSubscription{first, second} {
+		if payload := receivePayload(t, subscription); payload != "hello" {
+			t.Errorf(`want the "hello" payload, got "%s"`, payload)
+		}
+	}
+
+	if connections != 1 {
+		t.Errorf(`want a single connection, got "%d"`, connections)
+	}
+
+	listener.unsubscribe(first)
+
+	select {
+	case <-conn.closed:
+		t.Errorf(`want the connection open while somebody listens, got it closed`)
+	default:
+	}
+
+	listener.unsubscribe(second)
+
+	select {
+	case <-conn.closed:
+	case <-time.After(time.Second):
+		t.Errorf(`want the connection closed once nobody listens, got it open`)
+	}
+}
+
+// TestNotificationListenerMaxSubscribers tests that the subscriptions over the limit are rejected.
+func TestNotificationListenerMaxSubscribers(t *testing.T) {
+	connections := 0
+	listener := newFakeNotificationListener(newFakeNotificationConn(), 1, &connections)
+
+	subscription, err := listener.subscribe()
+	if err != nil {
+		t.Fatalf(`want no errors, got "%s"`, err)
+	}
+	defer listener.unsubscribe(subscription)
+
+	_, err = listener.subscribe()
+	if !errors.Is(err, ErrTooManyListeners) {
+		t.Errorf(`want "%s", got "%v"`, ErrTooManyListeners, err)
+	}
+}
+
+// TestNotificationListenerFailure tests that a connection failure is reported to every subscriber.
+func TestNotificationListenerFailure(t *testing.T) {
+	conn := newFakeNotificationConn()
+	connections := 0
+	listener := newFakeNotificationListener(conn, 10, &connections)
+
+	first, _ := listener.subscribe()
+	second, _ := listener.subscribe()
+
+	failure := errors.New("connection lost")
+	conn.failures <- failure
+
+	for _, subscription := range []*notificationSubscription{first, second} {
+		select {
+		case err := <-subscription.errs:
+			if !errors.Is(err, failure) {
+				t.Errorf(`want "%s", got "%s"`, failure, err)
+			}
+		case <-time.After(time.Second):
+			t.Errorf(`want the failure reported, got nothing`)
+		}
+	}
+}
+
+// TestNotificationListenerDropsSlowSubscribers tests that the subscribers which don't keep up get dropped without
+// holding the rest back.
+func TestNotificationListenerDropsSlowSubscribers(t *testing.T) {
+	conn := newFakeNotificationConn()
+	connections := 0
+	listener := newFakeNotificationListener(conn, 10, &connections)
+
+	slow, _ := listener.subscribe()
+	fast, _ := listener.subscribe()
+	defer listener.unsubscribe(fast)
+
+	for i := 0; i <= notificationSubscriptionBuffer; i++ {
+		conn.notifications <- "change"
+		receivePayload(t, fast)
+	}
+
+	select {
+	case err := <-slow.errs:
+		if !errors.Is(err, ErrListenerFellBehind) {
+			t.Errorf(`want "%s", got "%s"`, ErrListenerFellBehind, err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf(`want the slow subscriber dropped, got it still subscribed`)
+	}
+}
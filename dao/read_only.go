@@ -0,0 +1,60 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+)
+
+// ReadOnlyMessage is the detail of the errors returned for the writes rejected by the read-only mode.
+const ReadOnlyMessage = "the service is in read-only mode for maintenance, please retry later"
+
+// readOnlyExemptionKey is the key of the context value which exempts the statements from the read-only mode.
+type readOnlyExemptionKey struct{}
+
+// ExemptFromReadOnly returns a context whose statements are let through even when the read-only mode is enabled. It is
+// meant for the writes the reads depend on, such as onboarding the tenants on their first request.
+func ExemptFromReadOnly(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, readOnlyExemptionKey{}, true)
+}
+
+// registerReadOnlyGuard registers the callbacks that abort the creations, the updates and the deletions when the
+// read-only mode is enabled, so that the writes which don't come from the API —such as the status updates— are
+// rejected too. The raw statements are not guarded, since they cannot be told apart from the reads.
+func registerReadOnlyGuard(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	errs := []error{
+		callbacks.Create().Before("*").Register("sources:read_only_create", rejectWhenReadOnly),
+		callbacks.Update().Before("*").Register("sources:read_only_update", rejectWhenReadOnly),
+		callbacks.Delete().Before("*").Register("sources:read_only_delete", rejectWhenReadOnly),
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("could not register the read-only callbacks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// rejectWhenReadOnly aborts the statement with a "service unavailable" error when the read-only mode is enabled, unless
+// the statement's context has been exempted from it.
+func rejectWhenReadOnly(db *gorm.DB) {
+	if !conf.ReadOnly {
+		return
+	}
+
+	if db.Statement != nil && db.Statement.Context != nil && db.Statement.Context.Value(readOnlyExemptionKey{}) != nil {
+		return
+	}
+
+	_ = db.AddError(util.NewErrServiceUnavailable(ReadOnlyMessage, conf.ReadOnlyRetryAfter))
+}
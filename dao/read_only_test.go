@@ -0,0 +1,56 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+)
+
+// TestRejectWhenReadOnly tests that the statements are aborted with a "service unavailable" error only when the
+// read-only mode is enabled.
+func TestRejectWhenReadOnly(t *testing.T) {
+	backupReadOnly, backupRetryAfter := conf.ReadOnly, conf.ReadOnlyRetryAfter
+	defer func() { conf.ReadOnly, conf.ReadOnlyRetryAfter = backupReadOnly, backupRetryAfter }()
+
+	conf.ReadOnly = false
+	db := &gorm.DB{Config: &gorm.Config{}}
+	rejectWhenReadOnly(db)
+	if db.Error != nil {
+		t.Errorf(`want no error when the read-only mode is disabled, got "%s"`, db.Error)
+	}
+
+	conf.ReadOnly, conf.ReadOnlyRetryAfter = true, time.Minute
+	db = &gorm.DB{Config: &gorm.Config{}}
+	rejectWhenReadOnly(db)
+	if !errors.Is(db.Error, util.ErrServiceUnavailableEmpty) {
+		t.Fatalf(`want a service unavailable error, got "%v"`, db.Error)
+	}
+
+	if retryAfter := db.Error.(util.ErrServiceUnavailable).RetryAfter; retryAfter != time.Minute {
+		t.Errorf(`want a retry after "%s", got "%s"`, time.Minute, retryAfter)
+	}
+}
+
+// TestRejectWhenReadOnlyExempted tests that the statements whose context has been exempted from the read-only mode
+// are let through.
+func TestRejectWhenReadOnlyExempted(t *testing.T) {
+	backupReadOnly := conf.ReadOnly
+	defer func() { conf.ReadOnly = backupReadOnly }()
+
+	conf.ReadOnly = true
+	db := &gorm.DB{Config: &gorm.Config{}, Statement: &gorm.Statement{Context: ExemptFromReadOnly(context.Background())}}
+	rejectWhenReadOnly(db)
+	if db.Error != nil {
+		t.Errorf(`want no error for an exempted statement, got "%s"`, db.Error)
+	}
+
+	db = &gorm.DB{Config: &gorm.Config{}, Statement: &gorm.Statement{Context: context.Background()}}
+	rejectWhenReadOnly(db)
+	if !errors.Is(db.Error, util.ErrServiceUnavailableEmpty) {
+		t.Errorf(`want a service unavailable error for a statement which isn't exempted, got "%v"`, db.Error)
+	}
+}
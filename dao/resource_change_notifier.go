@@ -0,0 +1,87 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// resourceChangesChannel is the channel the raised events get announced on.
+const resourceChangesChannel = "resource_changes"
+
+// maxNotificationPayloadSize is the size PostgreSQL's notification payloads must stay under.
+const maxNotificationPayloadSize = 8000
+
+// resourceChangesListener is shared by the change broker of the process, which is its only subscriber.
+var resourceChangesListener = newNotificationListener(resourceChangesChannel, 1)
+
+// NotifyResourceChange announces the raised event on the resource changes channel, so that the clients which stream
+// the changes get them no matter which replica or process made them. When the event's payload is too big for a
+// notification, it is left out and the notification is marked as truncated.
+func NotifyResourceChange(ctx context.Context, eventType string, payload []byte, headers []kafka.Header) error {
+	notification, err := resourceChangeNotification(eventType, payload, headers)
+	if err != nil {
+		return err
+	}
+
+	return DB.
+		WithContext(ctx).
+		Exec("SELECT pg_notify(?, ?)", resourceChangesChannel, notification).
+		Error
+}
+
+// resourceChangeNotification builds the notification's payload for the given event.
+func resourceChangeNotification(eventType string, payload []byte, headers []kafka.Header) (string, error) {
+	notification := m.ResourceChangeNotification{EventType: eventType, Payload: payload}
+	for _, header := range headers {
+		switch header.Key {
+		case h.ORGID:
+			notification.OrgId = string(header.Value)
+		case h.ACCOUNT_NUMBER:
+			notification.Account = string(header.Value)
+		}
+	}
+
+	out, err := json.Marshal(notification)
+	if err != nil {
+		return "", err
+	}
+
+	if len(out) < maxNotificationPayloadSize {
+		return string(out), nil
+	}
+
+	notification.Payload = nil
+	notification.Truncated = true
+
+	out, err = json.Marshal(notification)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// ListenResourceChanges calls "onChange" with the payload of every announced resource change, until either the
+// context is done or the listener fails.
+func ListenResourceChanges(ctx context.Context, onChange func(payload string)) error {
+	subscription, err := resourceChangesListener.subscribe()
+	if err != nil {
+		return err
+	}
+	defer resourceChangesListener.unsubscribe(subscription)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-subscription.errs:
+			return err
+		case payload := <-subscription.payloads:
+			onChange(payload)
+		}
+	}
+}
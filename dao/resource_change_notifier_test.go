@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// TestResourceChangeNotification tests that the notification identifies the tenant from the event's headers, and
+// that the payloads which don't fit in a notification are left out.
+func TestResourceChangeNotification(t *testing.T) {
+	headers := []kafka.Header{
+		{Key: h.ORGID, Value: []byte("12345")},
+		{Key: h.ACCOUNT_NUMBER, Value: []byte("67890")},
+		{Key: "event_type", Value: []byte("RhcConnection.update")},
+	}
+
+	testCases := []struct {
+		name          string
+		payload       string
+		wantPayload   string
+		wantTruncated bool
+	}{
+		{
+			name:        "small payload",
+			payload:     `{"id":"1"}`,
+			wantPayload: `{"id":"1"}`,
+		},
+		{
+			name:          "oversized payload",
+			payload:       `{"extra":"` + strings.Repeat("a", maxNotificationPayloadSize) + `"}`,
+			wantTruncated: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		out, err := resourceChangeNotification("RhcConnection.update", []byte(tc.payload), headers)
+		if err != nil {
+			t.Fatalf(`[%s] want no errors, got "%s"`, tc.name, err)
+		}
+
+		if len(out) >= maxNotificationPayloadSize {
+			t.Errorf(`[%s] want the notification under "%d" bytes, got "%d"`, tc.name, maxNotificationPayloadSize, len(out))
+		}
+
+		var notification m.ResourceChangeNotification
+		err = json.Unmarshal([]byte(out), &notification)
+		if err != nil {
+			t.Fatalf(`[%s] could not unmarshal the notification: %s`, tc.name, err)
+		}
+
+		if notification.OrgId != "12345" || notification.Account != "67890" || notification.EventType != "RhcConnection.update" {
+			t.Errorf(`[%s] want the tenant and the event type of the event, got "%+v"`, tc.name, notification)
+		}
+
+		if notification.Truncated != tc.wantTruncated || string(notification.Payload) != tc.wantPayload {
+			t.Errorf(`[%s] want the payload "%s" and truncated "%t", got "%s" and "%t"`, tc.name, tc.wantPayload, tc.wantTruncated, notification.Payload, notification.Truncated)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package dao
+
+import (
+	"github.com/RedHatInsights/sources-api-go/config"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// RhcConnectionAvailabilityPublisher publishes the availability check requests for the rhcConnections, so that
+// their status gets determined by the downstream systems.
+type RhcConnectionAvailabilityPublisher interface {
+	PublishAvailabilityCheck(request m.RhcConnectionAvailabilityCheck) error
+}
+
+// RhcAvailabilityPublisher is the publisher used when a rhcConnection gets created. It can be replaced in runtime to
+// either plug in a real publisher or a mocked one for the tests. When nil, no requests are published.
+var RhcAvailabilityPublisher RhcConnectionAvailabilityPublisher
+
+// requestRhcConnectionAvailabilityCheck publishes an availability check request for the given connection if the
+// feature is enabled. It must only be called once the connection has been committed to the database, since otherwise
+// the downstream systems might not be able to find it. Any publishing errors are just logged, since they shouldn't
+// make the creation fail.
+func requestRhcConnectionAvailabilityCheck(tenantId int64, rhcConnection *m.RhcConnection) {
+	if !config.Get().RequestAvailabilityOnCreate || RhcAvailabilityPublisher == nil {
+		return
+	}
+
+	err := RhcAvailabilityPublisher.PublishAvailabilityCheck(newRhcConnectionAvailabilityCheck(tenantId, rhcConnection))
+	if err != nil {
+		logging.Log.Warnf(`Unable to publish the availability check request for rhcConnection "%d": %s`, rhcConnection.ID, err)
+	}
+}
+
+// newRhcConnectionAvailabilityCheck builds the availability check request for the given connection.
+func newRhcConnectionAvailabilityCheck(tenantId int64, rhcConnection *m.RhcConnection) m.RhcConnectionAvailabilityCheck {
+	request := m.RhcConnectionAvailabilityCheck{
+		RhcConnectionId: rhcConnection.ID,
+		RhcId:           rhcConnection.RhcId,
+		TenantId:        tenantId,
+	}
+
+	if len(rhcConnection.Sources) > 0 {
+		request.SourceId = rhcConnection.Sources[0].ID
+	}
+
+	return request
+}
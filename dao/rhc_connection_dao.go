@@ -1,10 +1,17 @@
 package dao
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/dao/mappers"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"gorm.io/gorm"
@@ -13,12 +20,16 @@ import (
 
 // GetRhcConnectionDao is a function definition that can be replaced in runtime in case some other DAO provider is
 // needed.
-var GetRhcConnectionDao func(*int64) RhcConnectionDao
+var GetRhcConnectionDao func(context.Context, *int64) RhcConnectionDao
 
-// getDefaultRhcConnectionDao gets the default DAO implementation which will have the given tenant ID.
-func getDefaultRhcConnectionDao(tenantId *int64) RhcConnectionDao {
-	return &rhcConnectionDaoImpl{
-		TenantID: tenantId,
+// getDefaultRhcConnectionDao gets the default DAO implementation which will have the given tenant ID. The DAO gets
+// instrumented so that the metrics of its operations are exported.
+func getDefaultRhcConnectionDao(ctx context.Context, tenantId *int64) RhcConnectionDao {
+	return &instrumentedRhcConnectionDao{
+		dao: &rhcConnectionDaoImpl{
+			requestContext: requestContext{ctx: ctx},
+			TenantID:       tenantId,
+		},
 	}
 }
 
@@ -29,51 +40,424 @@ func init() {
 
 type rhcConnectionDaoImpl struct {
 	TenantID *int64
+	requestContext
+	// hooks are called, in order, before and after the connections are created, updated or deleted.
+	hooks []DaoHook
+}
+
+func (s *rhcConnectionDaoImpl) RegisterHook(hook DaoHook) {
+	s.hooks = append(s.hooks, hook)
 }
 
 func (s *rhcConnectionDaoImpl) List(limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error) {
-	query := DB.
-		Debug().
+	query, err := applyRhcConnectionFilters(s.listQuery(s.db()), filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	return findRhcConnections(query, limit, offset)
+}
+
+func (s *rhcConnectionDaoImpl) ListWithLastModified(limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, *time.Time, error) {
+	query, err := applyRhcConnectionFilters(s.listQuery(s.db()), filters)
+	if err != nil {
+		return nil, 0, nil, util.NewErrBadRequest(err)
+	}
+
+	// The last modification is aggregated along with the count for pagination, so that it doesn't cost another
+	// query.
+	var aggregate struct {
+		Count        int64
+		LastModified *time.Time
+	}
+
+	err = s.db().
+		Table(`(?) AS "listed"`, query).
+		Select(`COUNT(*) AS "count", MAX("listed"."updated_at") AS "last_modified"`).
+		Scan(&aggregate).
+		Error
+
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	// Order the connections by their IDs for stable pages, just like "findRhcConnections" does.
+	rhcConnections, err := scanRhcConnections(query.Order(`"rhc_connections"."id" ASC`).Limit(pageSize(limit)).Offset(offset))
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	return rhcConnections, aggregate.Count, aggregate.LastModified, nil
+}
+
+func (s *rhcConnectionDaoImpl) ListByStatus(status string, limit, offset int) ([]m.RhcConnection, int64, error) {
+	if _, ok := m.ValidSourceTransitions[status]; !ok {
+		return nil, 0, util.NewErrBadRequest(fmt.Sprintf(`invalid availability status "%s"`, status))
+	}
+
+	query := s.listQuery(s.db())
+	if status == m.Unknown {
+		query = query.Where(`COALESCE("rhc_connections"."availability_status", '') = ''`)
+	} else {
+		query = query.Where(`"rhc_connections"."availability_status" = ?`, status)
+	}
+
+	// Count the aggregated rows instead of the joined ones, so that the count matches the filtered connections.
+	var count int64
+	err := s.db().
+		Table(`(?) AS "listed"`, query).
+		Count(&count).
+		Error
+
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	rhcConnections, err := scanRhcConnections(query.Order(`"rhc_connections"."id" ASC`).Limit(pageSize(limit)).Offset(offset))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rhcConnections, count, nil
+}
+
+// topologyEdgesMaxPageSize bounds the number of topology edges returned in a single page, so that the huge tenants
+// page through their edges instead of loading them all at once.
+const topologyEdgesMaxPageSize = 5000
+
+func (s *rhcConnectionDaoImpl) TopologyEdges(limit, offset int) ([]m.RhcConnectionTopologyEdge, int64, error) {
+	query := s.db().
+		Model(&m.SourceRhcConnection{}).
+		Where("tenant_id = ?", s.TenantID)
+
+	var count int64
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	limit = pageSize(limit)
+	if limit > topologyEdgesMaxPageSize {
+		limit = topologyEdgesMaxPageSize
+	}
+
+	edges := make([]m.RhcConnectionTopologyEdge, 0)
+	err = query.
+		Select("rhc_connection_id", "source_id").
+		Order("rhc_connection_id ASC, source_id ASC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&edges).
+		Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return edges, count, nil
+}
+
+// listQuery returns the query which lists the tenant's connections along with the IDs of the sources they're linked
+// to, aggregated in a comma separated "source_ids" column.
+func (s *rhcConnectionDaoImpl) listQuery(db *gorm.DB) *gorm.DB {
+	return db.
 		Model(&m.RhcConnection{}).
 		Select(`"rhc_connections".*, STRING_AGG(CAST ("jt"."source_id" AS TEXT), ',') AS "source_ids"`).
 		Joins(`INNER JOIN "source_rhc_connections" AS "jt" ON "rhc_connections"."id" = "jt"."rhc_connection_id"`).
 		Where(`"jt"."tenant_id" = ?`, s.TenantID).
 		Group(`"rhc_connections"."id"`)
+}
 
-	query, err := applyFilters(query, filters)
+// applyRhcConnectionFilters applies the given filters to the aggregation query which lists the connections. The
+// rhc_ids are matched with a single "IN" predicate instead of the generic filters, since these would make the query
+// distinct, which doesn't play along with the aggregation. The tags are matched by checking that the connections'
+// tags contain the requested ones.
+func applyRhcConnectionFilters(query *gorm.DB, filters []util.Filter) (*gorm.DB, error) {
+	rhcIds, filters, err := extractRhcIdFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rhcIds) > 0 {
+		query = query.Where(`"rhc_connections"."rhc_id" IN ?`, rhcIds)
+	}
+
+	tags, filters, err := extractTagsFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tags) > 0 {
+		tagsJson, err := json.Marshal(tags)
+		if err != nil {
+			return nil, err
+		}
+
+		query = query.Where(`"rhc_connections"."tags" @> ?::JSONB`, string(tagsJson))
+	}
+
+	return applyFilters(query, filters)
+}
+
+// ListWithSources lists the connections in two phases: the connections and their source IDs are fetched first, and
+// then all their sources are fetched in a single batch. Unlike joining the sources in the listing query, this doesn't
+// repeat the connections' columns for every linked source, and keeps the pagination on the connections themselves.
+//
+// "BenchmarkListWithSources" compares it with the JOIN query at a thousand connections, but it needs the integration
+// database and it hasn't been run against one yet, so there is no measured result to quote. Record the numbers here
+// once "go test ./dao -run ^$ -bench WithSources -integration" has been run.
+func (s *rhcConnectionDaoImpl) ListWithSources(ctx context.Context, limit, offset int, filters []util.Filter) ([]m.RhcConnectionWithSources, int64, error) {
+	db := s.db().WithContext(ctx)
+
+	query, err := applyRhcConnectionFilters(s.listQuery(db), filters)
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
+	rhcConnections, count, err := findRhcConnections(query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sourceIds := make([]int64, 0)
+	for _, rhcConnection := range rhcConnections {
+		for _, source := range rhcConnection.Sources {
+			sourceIds = append(sourceIds, source.ID)
+		}
+	}
+
+	sources := make([]m.Source, 0)
+	if len(sourceIds) > 0 {
+		err = db.
+			Where(`id IN ?`, sourceIds).
+			Where(`tenant_id = ?`, s.TenantID).
+			Find(&sources).
+			Error
+
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	sourcesById := make(map[int64]m.Source, len(sources))
+	for _, source := range sources {
+		sourcesById[source.ID] = source
+	}
+
+	rhcConnectionsWithSources := make([]m.RhcConnectionWithSources, len(rhcConnections))
+	for i, rhcConnection := range rhcConnections {
+		rhcConnectionSources := make([]m.Source, 0, len(rhcConnection.Sources))
+		for _, source := range rhcConnection.Sources {
+			if fullSource, ok := sourcesById[source.ID]; ok {
+				rhcConnectionSources = append(rhcConnectionSources, fullSource)
+			}
+		}
+
+		rhcConnectionsWithSources[i] = m.RhcConnectionWithSources{RhcConnection: rhcConnection, Sources: rhcConnectionSources}
+	}
+
+	return rhcConnectionsWithSources, count, nil
+}
+
+func (s *rhcConnectionDaoImpl) CountForTenant() (int64, error) {
+	// The connections are counted through their model instead of just counting the links, so that the deleted
+	// connections are left out once the connections support soft deletes.
+	linksQuery := s.db().
+		Model(&m.SourceRhcConnection{}).
+		Select(`"rhc_connection_id"`).
+		Where(`"tenant_id" = ?`, s.TenantID)
+
+	var count int64
+	err := s.db().
+		Model(&m.RhcConnection{}).
+		Where(`"id" IN (?)`, linksQuery).
+		Count(&count).
+		Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (s *rhcConnectionDaoImpl) CountSnapshot() ([]m.RhcConnectionCountSnapshot, error) {
+	snapshot := make([]m.RhcConnectionCountSnapshot, 0)
+
+	// The connections are counted through their model for the same reason as in "CountForTenant".
+	err := s.db().
+		Model(&m.RhcConnection{}).
+		Select(`"tenant_id", COUNT(*) AS "count"`).
+		Group(`"tenant_id"`).
+		Order(`"tenant_id" ASC`).
+		Scan(&snapshot).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func (s *rhcConnectionDaoImpl) ListByApplicationType(appTypeId int64, limit, offset int) ([]m.RhcConnection, int64, error) {
+	// The applications are joined on a subquery, since joining them directly would produce a row per application,
+	// which would both duplicate the aggregated source IDs and inflate the count.
+	connectionsQuery := s.db().
+		Table(`"source_rhc_connections" AS "sr"`).
+		Select(`"sr"."rhc_connection_id"`).
+		Joins(`INNER JOIN "sources" ON "sources"."id" = "sr"."source_id"`).
+		Joins(`INNER JOIN "applications" ON "applications"."source_id" = "sources"."id"`).
+		Joins(`INNER JOIN "application_types" ON "application_types"."id" = "applications"."application_type_id"`).
+		Where(`"application_types"."id" = ?`, appTypeId).
+		Where(`"sr"."tenant_id" = ?`, s.TenantID).
+		Where(`"applications"."tenant_id" = ?`, s.TenantID)
+
+	query := s.db().
+		Model(&m.RhcConnection{}).
+		Select(`"rhc_connections".*, STRING_AGG(CAST ("jt"."source_id" AS TEXT), ',') AS "source_ids"`).
+		Joins(`INNER JOIN "source_rhc_connections" AS "jt" ON "rhc_connections"."id" = "jt"."rhc_connection_id"`).
+		Where(`"rhc_connections"."id" IN (?)`, connectionsQuery).
+		Where(`"jt"."tenant_id" = ?`, s.TenantID).
+		Group(`"rhc_connections"."id"`)
+
+	return findRhcConnections(query, limit, offset)
+}
+
+func (s *rhcConnectionDaoImpl) ListBySourceName(namePrefix string, limit, offset int) ([]m.RhcConnection, int64, error) {
+	if strings.TrimSpace(namePrefix) == "" {
+		return nil, 0, util.NewErrBadRequest("the source name prefix cannot be empty")
+	}
+
+	// The sources are matched on a subquery so that the aggregated "source_ids" still contains all the sources the
+	// connections are linked to, and not just the matching ones.
+	connectionsQuery := s.db().
+		Table(`"source_rhc_connections" AS "sr"`).
+		Select(`"sr"."rhc_connection_id"`).
+		Joins(`INNER JOIN "sources" ON "sources"."id" = "sr"."source_id"`).
+		Where(`"sources"."name" ILIKE ?`, escapeLikePattern(namePrefix)+"%").
+		Where(`"sources"."tenant_id" = ?`, s.TenantID).
+		Where(`"sr"."tenant_id" = ?`, s.TenantID)
+
+	query := s.listQuery(s.db()).
+		Where(`"rhc_connections"."id" IN (?)`, connectionsQuery)
+
+	return findRhcConnections(query, limit, offset)
+}
+
+// defaultSharedMinSources is the minimum number of related sources a connection must have to be considered shared,
+// when no minimum is specified.
+const defaultSharedMinSources = 2
+
+func (s *rhcConnectionDaoImpl) ListShared(minSources int, limit, offset int) ([]m.RhcConnection, int64, error) {
+	if minSources <= 0 {
+		minSources = defaultSharedMinSources
+	}
+
+	query := s.db().
+		Model(&m.RhcConnection{}).
+		Select(`"rhc_connections".*, STRING_AGG(CAST ("jt"."source_id" AS TEXT), ',') AS "source_ids"`).
+		Joins(`INNER JOIN "source_rhc_connections" AS "jt" ON "rhc_connections"."id" = "jt"."rhc_connection_id"`).
+		Where(`"jt"."tenant_id" = ?`, s.TenantID).
+		Group(`"rhc_connections"."id"`).
+		Having(`COUNT("jt"."source_id") >= ?`, minSources)
+
+	return findRhcConnections(query, limit, offset)
+}
+
+func (s *rhcConnectionDaoImpl) ListWithSourceAvailability(limit, offset int) ([]m.RhcConnectionWithAvailability, int64, error) {
+	rhcConnections, count, err := s.List(limit, offset, []util.Filter{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(rhcConnections) == 0 {
+		return []m.RhcConnectionWithAvailability{}, count, nil
+	}
+
+	rhcConnectionIds := make([]int64, len(rhcConnections))
+	for i, rhcConnection := range rhcConnections {
+		rhcConnectionIds[i] = rhcConnection.ID
+	}
+
+	// The statuses of the linked sources are aggregated in a single query for the whole page of connections.
+	var statusCounts []struct {
+		RhcConnectionId    int64
+		AvailabilityStatus string
+		Count              int64
+	}
+
+	err = s.db().
+		Select(`"jt"."rhc_connection_id", COALESCE("sources"."availability_status", '') AS "availability_status", COUNT(*) AS "count"`).
+		Table(`"source_rhc_connections" AS "jt"`).
+		Joins(`INNER JOIN "sources" ON "sources"."id" = "jt"."source_id"`).
+		Where(`"jt"."tenant_id" = ?`, s.TenantID).
+		Where(`"jt"."rhc_connection_id" IN ?`, rhcConnectionIds).
+		Group(`"jt"."rhc_connection_id", COALESCE("sources"."availability_status", '')`).
+		Scan(&statusCounts).
+		Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rhcConnectionsWithAvailability := make([]m.RhcConnectionWithAvailability, len(rhcConnections))
+	indexes := make(map[int64]int, len(rhcConnections))
+	for i, rhcConnection := range rhcConnections {
+		rhcConnectionsWithAvailability[i] = m.RhcConnectionWithAvailability{RhcConnection: rhcConnection}
+		indexes[rhcConnection.ID] = i
+	}
+
+	for _, statusCount := range statusCounts {
+		rhcConnectionsWithAvailability[indexes[statusCount.RhcConnectionId]].AddSourcesAvailability(statusCount.AvailabilityStatus, statusCount.Count)
+	}
+
+	return rhcConnectionsWithAvailability, count, nil
+}
+
+// findRhcConnections counts the results of the given aggregation query, and runs it with the given limit and offset
+// to map the resulting rows to RhcConnections.
+func findRhcConnections(query *gorm.DB, limit, offset int) ([]m.RhcConnection, int64, error) {
 	// Getting the total count (filters included) for pagination.
 	count := int64(0)
 	query.Count(&count)
 
+	// Order the connections by their IDs —after any requested sorting— so that the pages are stable.
+	rhcConnections, err := scanRhcConnections(query.Order(`"rhc_connections"."id" ASC`).Limit(pageSize(limit)).Offset(offset))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rhcConnections, count, nil
+}
+
+// scanRhcConnections runs the given aggregation query and maps every resulting row to an RhcConnection.
+func scanRhcConnections(query *gorm.DB) ([]m.RhcConnection, error) {
 	// Run the actual query.
-	result, err := query.Limit(limit).Offset(offset).Rows()
+	result, err := query.Rows()
 	if err != nil {
-		return nil, 0, util.NewErrBadRequest(err)
+		return nil, util.NewErrBadRequest(err)
 	}
 
 	// We call next as otherwise "ScanRows" complains, but since we're going to map the results to an array of
 	// map[string]interface{}, "ScanRows" will already scan every row into that array, thus freeing us from calling
 	// result.Next() again.
 	if !result.Next() {
-		return []m.RhcConnection{}, count, nil
+		return []m.RhcConnection{}, result.Close()
 	}
 
 	// Loop through the rows to map both the connection and its related sources.
 	var rows []map[string]interface{}
 	err = DB.ScanRows(result, &rows)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	rhcConnections := make([]m.RhcConnection, 0)
 	for _, row := range rows {
 		rhcConnection, err := mappers.MapRowToRhcConnection(row)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 
 		rhcConnections = append(rhcConnections, *rhcConnection)
@@ -81,15 +465,14 @@ func (s *rhcConnectionDaoImpl) List(limit, offset int, filters []util.Filter) ([
 
 	err = result.Close()
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	return rhcConnections, count, nil
+	return rhcConnections, nil
 }
 
 func (s *rhcConnectionDaoImpl) GetById(id *int64) (*m.RhcConnection, error) {
-	query := DB.
-		Debug().
+	query := s.db().
 		Model(&m.RhcConnection{}).
 		Select(`"rhc_connections".*, STRING_AGG(CAST ("jt"."source_id" AS TEXT), ',') AS "source_ids"`).
 		Joins(`INNER JOIN "source_rhc_connections" AS "jt" ON "rhc_connections"."id" = "jt"."rhc_connection_id"`).
@@ -97,6 +480,125 @@ func (s *rhcConnectionDaoImpl) GetById(id *int64) (*m.RhcConnection, error) {
 		Where(`"jt"."tenant_id" = ?`, s.TenantID).
 		Group(`"rhc_connections"."id"`)
 
+	rhcConnection, err := findSingleRhcConnection(query)
+	if errors.Is(err, util.ErrNotFoundEmpty) {
+		// Tell apart the connections that never existed from the ones that were deleted.
+		deletedQuery := s.db().
+			Where(`"id" = ?`, id).
+			Where(`"id" IN (SELECT "rhc_connection_id" FROM "source_rhc_connections" WHERE "tenant_id" = ?)`, s.TenantID)
+
+		return nil, notFoundOrGone(deletedQuery, &m.RhcConnection{}, err)
+	}
+
+	return rhcConnection, err
+}
+
+// GetByIds fetches all the given connections in a single query. The IDs which don't belong to any of the tenant's
+// connections are simply absent from the result.
+func (s *rhcConnectionDaoImpl) GetByIds(ids []int64) ([]m.RhcConnection, error) {
+	if len(ids) == 0 {
+		return []m.RhcConnection{}, nil
+	}
+
+	query := s.listQuery(s.db()).
+		Where(`"rhc_connections"."id" IN ?`, ids).
+		Order(`"rhc_connections"."id"`)
+
+	return scanRhcConnections(query)
+}
+
+func (s *rhcConnectionDaoImpl) LatestPerSource(sourceIds []int64) (map[int64]*m.RhcConnection, error) {
+	latest := make(map[int64]*m.RhcConnection)
+	if len(sourceIds) == 0 {
+		return latest, nil
+	}
+
+	// Rank the connections of every source by their creation date, so that only the newest ones are picked.
+	rankedQuery := s.db().
+		Select(`"jt"."source_id", "jt"."rhc_connection_id", ROW_NUMBER() OVER (PARTITION BY "jt"."source_id" ORDER BY "rhc_connections"."created_at" DESC, "rhc_connections"."id" DESC) AS "rank"`).
+		Table(`"source_rhc_connections" AS "jt"`).
+		Joins(`INNER JOIN "rhc_connections" ON "rhc_connections"."id" = "jt"."rhc_connection_id"`).
+		Where(`"jt"."tenant_id" = ?`, s.TenantID).
+		Where(`"jt"."source_id" IN ?`, sourceIds)
+
+	var pairs []struct {
+		SourceId        int64
+		RhcConnectionId int64
+	}
+
+	err := s.db().
+		Select(`"source_id", "rhc_connection_id"`).
+		Table(`(?) AS "ranked"`, rankedQuery).
+		Where(`"rank" = 1`).
+		Scan(&pairs).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pairs) == 0 {
+		return latest, nil
+	}
+
+	rhcConnectionIds := make([]int64, 0, len(pairs))
+	for _, pair := range pairs {
+		rhcConnectionIds = append(rhcConnectionIds, pair.RhcConnectionId)
+	}
+
+	rhcConnections, err := s.GetByIds(rhcConnectionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[int64]*m.RhcConnection, len(rhcConnections))
+	for i := range rhcConnections {
+		byId[rhcConnections[i].ID] = &rhcConnections[i]
+	}
+
+	for _, pair := range pairs {
+		if rhcConnection, ok := byId[pair.RhcConnectionId]; ok {
+			latest[pair.SourceId] = rhcConnection
+		}
+	}
+
+	return latest, nil
+}
+
+func (s *rhcConnectionDaoImpl) ListModifiedBy(actor string, since time.Time, limit, offset int) ([]m.RhcConnection, int64, error) {
+	query := s.listQuery(s.db()).
+		Where(`"rhc_connections"."tenant_id" = ?`, s.TenantID).
+		Where(`"rhc_connections"."updated_by" = ?`, actor).
+		Where(`"rhc_connections"."updated_at" >= ?`, since).
+		Order(`"rhc_connections"."updated_at" DESC`)
+
+	return findRhcConnections(query, limit, offset)
+}
+
+func (s *rhcConnectionDaoImpl) GetBySourceAndRhcId(sourceId *int64, rhcId string) (*m.RhcConnection, error) {
+	// The link is checked on a subquery so that the aggregated "source_ids" still contains all the sources the
+	// connection is related to, and not just the one we are filtering by.
+	linkQuery := s.db().
+		Model(&m.SourceRhcConnection{}).
+		Select(`"rhc_connection_id"`).
+		Where(`"source_id" = ?`, sourceId).
+		Where(`"tenant_id" = ?`, s.TenantID)
+
+	query := s.db().
+		Model(&m.RhcConnection{}).
+		Select(`"rhc_connections".*, STRING_AGG(CAST ("jt"."source_id" AS TEXT), ',') AS "source_ids"`).
+		Joins(`INNER JOIN "source_rhc_connections" AS "jt" ON "rhc_connections"."id" = "jt"."rhc_connection_id"`).
+		Where(`"rhc_connections"."rhc_id" = ?`, rhcId).
+		Where(`"rhc_connections"."id" IN (?)`, linkQuery).
+		Where(`"jt"."tenant_id" = ?`, s.TenantID).
+		Group(`"rhc_connections"."id"`)
+
+	return findSingleRhcConnection(query)
+}
+
+// findSingleRhcConnection runs the given aggregation query, which is expected to return a single row, and maps the
+// result to an RhcConnection. If no rows are returned, a "not found" error is returned instead.
+func findSingleRhcConnection(query *gorm.DB) (*m.RhcConnection, error) {
 	// Run the actual query.
 	result, err := query.Rows()
 	if err != nil {
@@ -137,27 +639,23 @@ func (s *rhcConnectionDaoImpl) GetById(id *int64) (*m.RhcConnection, error) {
 func (s *rhcConnectionDaoImpl) Create(rhcConnection *m.RhcConnection) (*m.RhcConnection, error) {
 	// If the source doesn't exist we cannot create the RhcConnection, since it needs to be linked to at least one
 	// source.
-	var sourceExists bool
-	err := DB.Debug().
-		Model(&m.Source{}).
-		Select(`1`).
-		Where(`id = ?`, rhcConnection.Sources[0].ID).
-		Where(`tenant_id = ?`, s.TenantID).
-		Scan(&sourceExists).
-		Error
-
-	// Something went wrong with the query
+	err := s.checkSourceExists(rhcConnection.Sources[0].ID)
 	if err != nil {
 		return nil, err
 	}
 
-	if !sourceExists {
-		return nil, util.NewErrNotFound("source")
-	}
+	// Each tenant gets its own connection, even if other tenants have registered the same rhc_id.
+	rhcConnection.TenantId = *s.TenantID
+
+	err = transaction(s.db(), func(tx *gorm.DB) error {
+		err := runHooks(s.hooks, func(hook DaoHook) error { return hook.BeforeCreate(rhcConnection) })
+		if err != nil {
+			return err
+		}
 
-	err = DB.Transaction(func(tx *gorm.DB) error {
-		err := tx.Debug().
+		err = tx.
 			Where(`rhc_id = ?`, rhcConnection.RhcId).
+			Where(`tenant_id = ?`, s.TenantID).
 			Omit(clause.Associations).
 			FirstOrCreate(&rhcConnection).
 			Error
@@ -166,79 +664,711 @@ func (s *rhcConnectionDaoImpl) Create(rhcConnection *m.RhcConnection) (*m.RhcCon
 			return err
 		}
 
-		// Try to insert an sourceRhcConnection, which is just the relation between a rhcConnection and a source.
-		sourceRhcConnection := m.SourceRhcConnection{
-			SourceId:        rhcConnection.Sources[0].ID,
-			RhcConnectionId: rhcConnection.ID,
-			TenantId:        *s.TenantID,
-		}
-
-		// Check if it exists first.
-		var relationExists bool
-		err = tx.Debug().
-			Model(&m.SourceRhcConnection{}).
-			Select(`1`).
-			Where(`source_id = ?`, sourceRhcConnection.SourceId).
-			Where(`rhc_connection_id = ?`, sourceRhcConnection.RhcConnectionId).
-			Where(`tenant_id = ?`, sourceRhcConnection.TenantId).
-			Scan(&relationExists).
-			Error
+		linked, err := s.linkToSource(tx, rhcConnection.ID, rhcConnection.Sources[0].ID)
 		if err != nil {
 			return err
 		}
 
-		// If it exists, we let the client know. If it doesn't, we attempt to create it.
-		if relationExists {
+		// If the relation already existed, we let the client know.
+		if !linked {
 			return util.NewErrBadRequest("connection already exists")
 		}
 
-		err = tx.
-			Debug().
-			Create(&sourceRhcConnection).
-			Error
-		if err != nil {
-			return err
-		}
-
+		return runHooks(s.hooks, func(hook DaoHook) error { return hook.AfterCreate(rhcConnection) })
+	})
+	// Two concurrent requests might not see each other's connection, in which case the unique index rejects the
+	// last one to be inserted.
+	if isUniqueViolation(err, rhcConnectionsRhcIdIndex) {
+		return rhcConnection, errRhcIdAlreadyRegistered(rhcConnection.RhcId)
+	}
+
+	if err != nil {
+		return rhcConnection, err
+	}
+
+	// The check is requested once the transaction has been committed, so that the connection is visible to whoever
+	// ends up processing the request.
+	requestRhcConnectionAvailabilityCheck(*s.TenantID, rhcConnection)
+
+	return rhcConnection, nil
+}
+
+func (s *rhcConnectionDaoImpl) CreateOrLink(rhcId string, sourceId int64) (*m.RhcConnection, bool, error) {
+	err := s.checkSourceExists(sourceId)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rhcConnection := &m.RhcConnection{RhcId: rhcId, TenantId: *s.TenantID}
+	var linked bool
+
+	err = transaction(s.db(), func(tx *gorm.DB) error {
+		err := tx.
+			Where(`rhc_id = ?`, rhcId).
+			Where(`tenant_id = ?`, s.TenantID).
+			Omit(clause.Associations).
+			FirstOrCreate(rhcConnection).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		linked, err = s.linkToSource(tx, rhcConnection.ID, sourceId)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	rhcConnection.Sources = []m.Source{{ID: sourceId}}
+
+	// Only the new links get checked, since the existing ones have already been checked when they were created.
+	if linked {
+		requestRhcConnectionAvailabilityCheck(*s.TenantID, rhcConnection)
+	}
+
+	return rhcConnection, linked, nil
+}
+
+// errRhcIdAlreadyRegistered returns the "conflict" error for an rhc_id which has already been registered. The error is
+// built directly instead of through "util.NewErrConflict", since the latter would log the rhc_id without masking it.
+func errRhcIdAlreadyRegistered(rhcId string) error {
+	logging.Log.Errorf("rhc_id %s is already registered", logging.RedactRhcId(rhcId))
+
+	return util.ErrConflict{Message: fmt.Sprintf("rhc_id %s is already registered", rhcId)}
+}
+
+// checkSourceExists returns a "not found" error if the tenant doesn't have a source with the given ID.
+func (s *rhcConnectionDaoImpl) checkSourceExists(sourceId int64) error {
+	var sourceExists bool
+	err := s.db().
+		Model(&m.Source{}).
+		Select(`1`).
+		Where(`id = ?`, sourceId).
+		Where(`tenant_id = ?`, s.TenantID).
+		Scan(&sourceExists).
+		Error
+
+	// Something went wrong with the query
+	if err != nil {
+		return err
+	}
+
+	if !sourceExists {
+		return util.NewErrNotFound("source")
+	}
+
+	return nil
+}
+
+// linkToSource inserts the relation between the given connection and source, which is just a "sourceRhcConnection"
+// row, unless it already exists. It returns whether the relation was inserted or not.
+func (s *rhcConnectionDaoImpl) linkToSource(tx *gorm.DB, rhcConnectionId, sourceId int64) (bool, error) {
+	sourceRhcConnection := m.SourceRhcConnection{
+		SourceId:        sourceId,
+		RhcConnectionId: rhcConnectionId,
+		TenantId:        *s.TenantID,
+	}
+
+	result := tx.
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&sourceRhcConnection)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (s *rhcConnectionDaoImpl) Update(rhcConnection *m.RhcConnection) (int64, error) {
+	var rowsAffected int64
+
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		err := runHooks(s.hooks, func(hook DaoHook) error { return hook.BeforeUpdate(rhcConnection) })
+		if err != nil {
+			return err
+		}
+
+		err = s.validateImmutableFields(tx, rhcConnection)
+		if err != nil {
+			return err
+		}
+
+		statusChange, err := s.lockStatusChange(tx, rhcConnection)
+		if err != nil {
+			return err
+		}
+
+		mutable := mutableRhcConnectionFields(rhcConnection)
+		result := tx.
+			Where(`"id" IN (SELECT "rhc_connection_id" FROM "source_rhc_connections" WHERE "tenant_id" = ?)`, s.TenantID).
+			Updates(mutable)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		rhcConnection.UpdatedAt = mutable.UpdatedAt
+
+		// Nothing was updated, so there is nothing to record or to notify the hooks about.
+		rowsAffected = result.RowsAffected
+		if rowsAffected == 0 {
+			return nil
+		}
+
+		if statusChange != nil {
+			err = recordStatusChange(tx, statusChange)
+			if err != nil {
+				return err
+			}
+		}
+
+		return runHooks(s.hooks, func(hook DaoHook) error { return hook.AfterUpdate(rhcConnection) })
+	})
+
+	return rowsAffected, err
+}
+
+// mutableRhcConnectionFields copies the fields of the connection which are allowed to be modified. The ID is only
+// kept to identify the row, since the primary key never makes it into the "SET" clause. Anything else, such as the
+// "rhc_id" or the tenant, never reaches the "UPDATE" statement.
+func mutableRhcConnectionFields(rhcConnection *m.RhcConnection) *m.RhcConnection {
+	return &m.RhcConnection{
+		ID:                      rhcConnection.ID,
+		Extra:                   rhcConnection.Extra,
+		AvailabilityStatus:      rhcConnection.AvailabilityStatus,
+		LastCheckedAt:           rhcConnection.LastCheckedAt,
+		LastAvailableAt:         rhcConnection.LastAvailableAt,
+		AvailabilityStatusError: rhcConnection.AvailabilityStatusError,
+		UpdatedBy:               rhcConnection.UpdatedBy,
+	}
+}
+
+// validateImmutableFields returns a "bad request" error when the given connection attempts to change its "rhc_id" or
+// its tenant. The empty values are not considered as attempts, since they are not written by "Updates" anyway.
+func (s *rhcConnectionDaoImpl) validateImmutableFields(tx *gorm.DB, rhcConnection *m.RhcConnection) error {
+	var stored m.RhcConnection
+	err := tx.
+		Select(`"rhc_id"`, `"tenant_id"`).
+		Where(`"id" = ?`, rhcConnection.ID).
+		Where(`"id" IN (SELECT "rhc_connection_id" FROM "source_rhc_connections" WHERE "tenant_id" = ?)`, s.TenantID).
+		Limit(1).
+		Find(&stored).
+		Error
+	if err != nil {
+		return err
+	}
+
+	// The connection doesn't exist for the tenant, so the update won't affect any rows.
+	if stored.RhcId == "" {
 		return nil
+	}
+
+	if rhcConnection.RhcId != "" && rhcConnection.RhcId != stored.RhcId {
+		return util.NewErrBadRequest(`the "rhc_id" of a connection cannot be modified`)
+	}
+
+	if rhcConnection.TenantId != 0 && rhcConnection.TenantId != stored.TenantId {
+		return util.NewErrBadRequest("the tenant of a connection cannot be modified")
+	}
+
+	return nil
+}
+
+// rhcConnectionDeleteGuard refuses the deletions of the connections linked to available sources, unless they are
+// forced.
+var rhcConnectionDeleteGuard = config.Get().RhcConnectionDeleteGuard
+
+func (s *rhcConnectionDaoImpl) Delete(id *int64, force bool) (*m.RhcConnection, error) {
+	var rhcConnection m.RhcConnection
+
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		if rhcConnectionDeleteGuard && !force {
+			err := ensureRhcConnectionNotInUse(tx, *id)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := runHooks(s.hooks, func(hook DaoHook) error { return hook.BeforeDelete(&m.RhcConnection{ID: *id}) })
+		if err != nil {
+			return err
+		}
+
+		// The foreign key and the "cascade on delete" in the join table takes care of deleting the related
+		// "source_rhc_connection" row.
+		result := tx.
+			Clauses(clause.Returning{}).
+			Where("id = ?", id).
+			Delete(&rhcConnection)
+
+		if result.Error != nil {
+			return fmt.Errorf(`failed to delete rhcConnection with id "%d": %s`, *id, result.Error)
+		}
+
+		if result.RowsAffected == 0 {
+			return util.NewErrNotFound("rhcConnection")
+		}
+
+		return runHooks(s.hooks, func(hook DaoHook) error { return hook.AfterDelete(&rhcConnection) })
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return rhcConnection, err
+	return &rhcConnection, nil
 }
 
-func (s *rhcConnectionDaoImpl) Update(rhcConnection *m.RhcConnection) error {
-	err := DB.Debug().
-		Updates(rhcConnection).
+// ensureRhcConnectionNotInUse returns a "bad request" error when the connection is linked to any available source.
+func ensureRhcConnectionNotInUse(tx *gorm.DB, rhcConnectionId int64) error {
+	var inUse bool
+	err := tx.
+		Raw(`SELECT EXISTS (SELECT 1 FROM "source_rhc_connections" AS "jt" INNER JOIN "sources" ON "sources"."id" = "jt"."source_id" WHERE "jt"."rhc_connection_id" = ? AND "sources"."availability_status" = ?)`, rhcConnectionId, m.Available).
+		Scan(&inUse).
 		Error
-	return err
+
+	if err != nil {
+		return fmt.Errorf(`failed to check whether the rhcConnection "%d" is in use: %w`, rhcConnectionId, err)
+	}
+
+	if inUse {
+		return util.NewErrBadRequest("connection in use")
+	}
+
+	return nil
 }
 
-func (s *rhcConnectionDaoImpl) Delete(id *int64) (*m.RhcConnection, error) {
+func (s *rhcConnectionDaoImpl) UnlinkFromSource(rhcConnectionId, sourceId, tenantId int64) (*m.RhcConnection, bool, error) {
 	var rhcConnection m.RhcConnection
+	deleted := false
+
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		result := tx.
+			Where(`"rhc_connection_id" = ?`, rhcConnectionId).
+			Where(`"source_id" = ?`, sourceId).
+			Where(`"tenant_id" = ?`, tenantId).
+			Delete(&m.SourceRhcConnection{})
+
+		if result.Error != nil {
+			return fmt.Errorf(`failed to unlink rhcConnection "%d" from source "%d": %w`, rhcConnectionId, sourceId, result.Error)
+		}
 
-	// The foreign key and the "cascade on delete" in the join table takes care of deleting the related
-	// "source_rhc_connection" row.
-	result := DB.
-		Debug().
+		if result.RowsAffected == 0 {
+			return util.NewErrNotFound("rhcConnection")
+		}
+
+		var err error
+		deleted, err = deleteRhcConnectionIfOrphan(tx, rhcConnectionId, &rhcConnection)
+		if err != nil || deleted {
+			return err
+		}
+
+		err = tx.
+			Where(`"id" = ?`, rhcConnectionId).
+			First(&rhcConnection).
+			Error
+
+		if err != nil {
+			return fmt.Errorf(`failed to fetch the unlinked rhcConnection "%d": %w`, rhcConnectionId, err)
+		}
+
+		// The connection keeps the tenant's sources which are still linked to it.
+		var sourceIds []int64
+		err = tx.
+			Model(&m.SourceRhcConnection{}).
+			Where(`"rhc_connection_id" = ?`, rhcConnectionId).
+			Where(`"tenant_id" = ?`, tenantId).
+			Order(`"source_id"`).
+			Pluck(`"source_id"`, &sourceIds).
+			Error
+
+		if err != nil {
+			return fmt.Errorf(`failed to fetch the sources of the unlinked rhcConnection "%d": %w`, rhcConnectionId, err)
+		}
+
+		for _, id := range sourceIds {
+			rhcConnection.Sources = append(rhcConnection.Sources, m.Source{ID: id})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &rhcConnection, deleted, nil
+}
+
+// deleteRhcConnectionIfOrphan deletes the given connection if it is no longer linked to any source, and returns whether
+// it was deleted. The deleted connection is returned in the given model.
+func deleteRhcConnectionIfOrphan(tx *gorm.DB, rhcConnectionId int64, rhcConnection *m.RhcConnection) (bool, error) {
+	result := tx.
 		Clauses(clause.Returning{}).
-		Where("id = ?", id).
-		Delete(&rhcConnection)
+		Where(`"rhc_connections"."id" = ?`, rhcConnectionId).
+		Where(`NOT EXISTS (SELECT 1 FROM "source_rhc_connections" WHERE "source_rhc_connections"."rhc_connection_id" = "rhc_connections"."id")`).
+		Delete(rhcConnection)
 
 	if result.Error != nil {
-		return nil, fmt.Errorf(`failed to delete rhcConnection with id "%d": %s`, id, result.Error)
+		return false, fmt.Errorf(`failed to delete the orphan rhcConnection "%d": %w`, rhcConnectionId, result.Error)
 	}
 
-	if result.RowsAffected == 0 {
-		return nil, util.NewErrNotFound("rhcConnection")
+	return result.RowsAffected > 0, nil
+}
+
+// integrityReportSampleSize is the maximum number of IDs the integrity report samples for each of its issues.
+const integrityReportSampleSize = 10
+
+func (s *rhcConnectionDaoImpl) CheckIntegrity() (m.IntegrityReport, error) {
+	var report m.IntegrityReport
+
+	// The checks run in a single read only transaction, so that they all see the same snapshot of the data.
+	err := s.db().Transaction(func(tx *gorm.DB) error {
+		var err error
+
+		orphansQuery := tx.
+			Table(`"rhc_connections"`).
+			Where(`"rhc_connections"."tenant_id" = ?`, s.TenantID).
+			Where(`NOT EXISTS (SELECT 1 FROM "source_rhc_connections" WHERE "source_rhc_connections"."rhc_connection_id" = "rhc_connections"."id")`)
+
+		report.OrphanedRhcConnections, err = sampleIntegrityIssue(orphansQuery, `"rhc_connections"."id"`)
+		if err != nil {
+			return fmt.Errorf(`failed to check the orphaned connections: %w`, err)
+		}
+
+		missingSourcesQuery := tx.
+			Table(`"source_rhc_connections"`).
+			Where(`"source_rhc_connections"."tenant_id" = ?`, s.TenantID).
+			Where(`NOT EXISTS (SELECT 1 FROM "sources" WHERE "sources"."id" = "source_rhc_connections"."source_id")`)
+
+		report.MissingSourceLinks, err = sampleIntegrityLinkIssue(missingSourcesQuery)
+		if err != nil {
+			return fmt.Errorf(`failed to check the links to missing sources: %w`, err)
+		}
+
+		missingRhcConnectionsQuery := tx.
+			Table(`"source_rhc_connections"`).
+			Where(`"source_rhc_connections"."tenant_id" = ?`, s.TenantID).
+			Where(`NOT EXISTS (SELECT 1 FROM "rhc_connections" WHERE "rhc_connections"."id" = "source_rhc_connections"."rhc_connection_id")`)
+
+		report.MissingRhcConnectionLinks, err = sampleIntegrityLinkIssue(missingRhcConnectionsQuery)
+		if err != nil {
+			return fmt.Errorf(`failed to check the links to missing connections: %w`, err)
+		}
+
+		// The mismatched links are reported to every tenant involved, since any of them could be the wrong one.
+		mismatchedQuery := tx.
+			Table(`"source_rhc_connections"`).
+			Joins(`INNER JOIN "sources" ON "sources"."id" = "source_rhc_connections"."source_id"`).
+			Joins(`INNER JOIN "rhc_connections" ON "rhc_connections"."id" = "source_rhc_connections"."rhc_connection_id"`).
+			Where(`? IN ("source_rhc_connections"."tenant_id", "sources"."tenant_id", "rhc_connections"."tenant_id")`, s.TenantID).
+			Where(`("sources"."tenant_id" <> "source_rhc_connections"."tenant_id" OR "rhc_connections"."tenant_id" <> "source_rhc_connections"."tenant_id")`)
+
+		report.TenantMismatchedLinks, err = sampleIntegrityLinkIssue(mismatchedQuery)
+		if err != nil {
+			return fmt.Errorf(`failed to check the tenant mismatched links: %w`, err)
+		}
+
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+
+	if err != nil {
+		return m.IntegrityReport{}, err
 	}
 
-	return &rhcConnection, nil
+	return report, nil
+}
+
+// sampleIntegrityIssue counts the rows of the given query and samples their IDs in a single query, by computing the
+// total with a window function before the sample gets limited.
+func sampleIntegrityIssue(query *gorm.DB, idColumn string) (m.IntegrityIssue, error) {
+	var rows []struct {
+		Id    int64
+		Total int64
+	}
+
+	err := query.
+		Select(idColumn + ` AS "id", COUNT(*) OVER () AS "total"`).
+		Order(idColumn + ` ASC`).
+		Limit(integrityReportSampleSize).
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return m.IntegrityIssue{}, err
+	}
+
+	issue := m.IntegrityIssue{SampleIds: make([]int64, 0, len(rows))}
+	for _, row := range rows {
+		issue.Count = row.Total
+		issue.SampleIds = append(issue.SampleIds, row.Id)
+	}
+
+	return issue, nil
+}
+
+// sampleIntegrityLinkIssue counts the links of the given query and samples them in a single query, the same way
+// "sampleIntegrityIssue" does.
+func sampleIntegrityLinkIssue(query *gorm.DB) (m.IntegrityLinkIssue, error) {
+	var rows []struct {
+		SourceId        int64
+		RhcConnectionId int64
+		Total           int64
+	}
+
+	err := query.
+		Select(`"source_rhc_connections"."source_id", "source_rhc_connections"."rhc_connection_id", COUNT(*) OVER () AS "total"`).
+		Order(`"source_rhc_connections"."source_id" ASC, "source_rhc_connections"."rhc_connection_id" ASC`).
+		Limit(integrityReportSampleSize).
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return m.IntegrityLinkIssue{}, err
+	}
+
+	issue := m.IntegrityLinkIssue{SampleLinks: make([]m.IntegrityLinkId, 0, len(rows))}
+	for _, row := range rows {
+		issue.Count = row.Total
+		issue.SampleLinks = append(issue.SampleLinks, m.IntegrityLinkId{SourceId: row.SourceId, RhcConnectionId: row.RhcConnectionId})
+	}
+
+	return issue, nil
+}
+
+// DeleteIfExists deletes the connection only if it is linked to one of the tenant's sources. Unlike "Delete", a missing
+// connection is not considered an error, so that repeated cleanups can safely call it.
+func (s *rhcConnectionDaoImpl) DeleteIfExists(id *int64) (bool, *m.RhcConnection, error) {
+	var rhcConnection m.RhcConnection
+	var deleted bool
+
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		err := runHooks(s.hooks, func(hook DaoHook) error { return hook.BeforeDelete(&m.RhcConnection{ID: *id}) })
+		if err != nil {
+			return err
+		}
+
+		// The foreign key and the "cascade on delete" in the join table takes care of deleting the related
+		// "source_rhc_connection" row.
+		result := tx.
+			Clauses(clause.Returning{}).
+			Where("id = ?", id).
+			Where(`id IN (SELECT "rhc_connection_id" FROM "source_rhc_connections" WHERE "tenant_id" = ?)`, s.TenantID).
+			Delete(&rhcConnection)
+
+		if result.Error != nil {
+			return fmt.Errorf(`failed to delete rhcConnection with id "%d": %s`, *id, result.Error)
+		}
+
+		// Nothing was deleted, so there is nothing to notify the hooks about.
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		deleted = true
+		return runHooks(s.hooks, func(hook DaoHook) error { return hook.AfterDelete(&rhcConnection) })
+	})
+	if err != nil || !deleted {
+		return false, nil, err
+	}
+
+	return true, &rhcConnection, nil
+}
+
+func (s *rhcConnectionDaoImpl) Deduplicate(rhcId string) (*m.RhcConnection, error) {
+	deduplication, err := s.deduplicate(rhcId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &deduplication.Canonical, nil
+}
+
+func (s *rhcConnectionDaoImpl) DeduplicateDryRun(rhcId string) (*m.RhcConnectionDeduplication, error) {
+	return s.deduplicate(rhcId, true)
+}
+
+// deduplicate merges all the connections which share the given rhc_id into the oldest one, which is considered the
+// canonical connection, as described in "mergeRhcConnections". Only the DAO's tenant's connections are merged, since
+// the connections of different tenants may legitimately share the rhc_id.
+func (s *rhcConnectionDaoImpl) deduplicate(rhcId string, dryRun bool) (*m.RhcConnectionDeduplication, error) {
+	var deduplication m.RhcConnectionDeduplication
+
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		// Lock the connections so that no links get added to the duplicates while we are moving them.
+		var rhcConnections []m.RhcConnection
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("rhc_id = ?", rhcId).
+			Where("tenant_id = ?", s.TenantID).
+			Order("id ASC").
+			Find(&rhcConnections).
+			Error
+		if err != nil {
+			return err
+		}
+
+		if len(rhcConnections) == 0 {
+			return util.NewErrNotFound("rhcConnection")
+		}
+
+		deduplication.Canonical = rhcConnections[0]
+		deduplication.DuplicateIds = make([]int64, 0, len(rhcConnections)-1)
+		deduplication.RepointedSourceIds = make([]int64, 0)
+		deduplication.DroppedSourceIds = make([]int64, 0)
+
+		for _, duplicate := range rhcConnections[1:] {
+			deduplication.DuplicateIds = append(deduplication.DuplicateIds, duplicate.ID)
+		}
+
+		if len(deduplication.DuplicateIds) == 0 {
+			return nil
+		}
+
+		return mergeRhcConnections(tx, &deduplication, dryRun)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &deduplication, nil
+}
+
+func (s *rhcConnectionDaoImpl) Merge(winnerId, loserId *int64) (*m.RhcConnection, error) {
+	_, err := s.merge(winnerId, loserId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetById(winnerId)
+}
+
+func (s *rhcConnectionDaoImpl) MergeDryRun(winnerId, loserId *int64) (*m.RhcConnectionDeduplication, error) {
+	return s.merge(winnerId, loserId, true)
+}
+
+// merge merges the loser connection into the winner one, as described in "mergeRhcConnections". Both connections must
+// belong to the DAO's tenant, since the links of a tenant's sources cannot point to another tenant's connection.
+func (s *rhcConnectionDaoImpl) merge(winnerId, loserId *int64, dryRun bool) (*m.RhcConnectionDeduplication, error) {
+	if *winnerId == *loserId {
+		return nil, util.NewErrBadRequest("a connection cannot be merged into itself")
+	}
+
+	var merge m.RhcConnectionDeduplication
+
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		// Lock the connections so that no links get added to the loser while we are moving them. They are locked in
+		// the order of their IDs to avoid deadlocks with concurrent merges.
+		var rhcConnections []m.RhcConnection
+		err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []int64{*winnerId, *loserId}).
+			Where("tenant_id = ?", s.TenantID).
+			Order("id ASC").
+			Find(&rhcConnections).
+			Error
+		if err != nil {
+			return err
+		}
+
+		if len(rhcConnections) != 2 {
+			return util.NewErrNotFound("rhcConnection")
+		}
+
+		for _, rhcConnection := range rhcConnections {
+			if rhcConnection.ID == *winnerId {
+				merge.Canonical = rhcConnection
+			}
+		}
+
+		merge.DuplicateIds = []int64{*loserId}
+
+		return mergeRhcConnections(tx, &merge, dryRun)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &merge, nil
+}
+
+// mergeRhcConnections moves the links of the merge's duplicates to its canonical connection, unless the source is
+// already linked to it, in which case the redundant link is dropped. Then the duplicates are deleted. The moved and
+// the dropped links are recorded in the given merge. When "dryRun" is true the changes are only computed, and nothing
+// gets modified. The connections are expected to be locked by the given transaction.
+func mergeRhcConnections(tx *gorm.DB, merge *m.RhcConnectionDeduplication, dryRun bool) error {
+	merge.RepointedSourceIds = make([]int64, 0)
+	merge.DroppedSourceIds = make([]int64, 0)
+
+	// Gather the sources which are already linked to the canonical connection, to detect the links that would
+	// collide with the unique index of the join table.
+	var linkedSourceIds []int64
+	err := tx.
+		Model(&m.SourceRhcConnection{}).
+		Where("rhc_connection_id = ?", merge.Canonical.ID).
+		Pluck("source_id", &linkedSourceIds).
+		Error
+	if err != nil {
+		return err
+	}
+
+	linked := make(map[int64]bool, len(linkedSourceIds))
+	for _, sourceId := range linkedSourceIds {
+		linked[sourceId] = true
+	}
+
+	var duplicateLinks []m.SourceRhcConnection
+	err = tx.
+		Where("rhc_connection_id IN ?", merge.DuplicateIds).
+		Order("rhc_connection_id ASC").
+		Find(&duplicateLinks).
+		Error
+	if err != nil {
+		return err
+	}
+
+	for _, link := range duplicateLinks {
+		if linked[link.SourceId] {
+			merge.DroppedSourceIds = append(merge.DroppedSourceIds, link.SourceId)
+		} else {
+			merge.RepointedSourceIds = append(merge.RepointedSourceIds, link.SourceId)
+			linked[link.SourceId] = true
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	for _, sourceId := range merge.RepointedSourceIds {
+		// A source might be linked to more than one duplicate, so only one of its links gets moved. The rest of
+		// them are deleted along with the duplicates.
+		err = tx.
+			Exec(
+				`UPDATE "source_rhc_connections" SET "rhc_connection_id" = ? WHERE "ctid" = (SELECT "ctid" FROM "source_rhc_connections" WHERE "source_id" = ? AND "rhc_connection_id" IN ? LIMIT 1)`,
+				merge.Canonical.ID,
+				sourceId,
+				merge.DuplicateIds,
+			).
+			Error
+		if err != nil {
+			return err
+		}
+	}
+
+	// The redundant links are removed by the "cascade on delete" of the join table's foreign key.
+	return tx.
+		Where("id IN ?", merge.DuplicateIds).
+		Delete(&m.RhcConnection{}).
+		Error
 }
 
 func (s *rhcConnectionDaoImpl) ListForSource(sourceId *int64, limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error) {
 	rhcConnections := make([]m.RhcConnection, 0)
 
-	query := DB.Debug().
+	query := s.db().
 		Model(&m.RhcConnection{}).
 		Joins(`INNER JOIN "source_rhc_connections" "sr" ON "rhc_connections"."id" = "sr"."rhc_connection_id"`).
 		Where(`"sr"."source_id" = ?`, sourceId).
@@ -253,11 +1383,68 @@ func (s *rhcConnectionDaoImpl) ListForSource(sourceId *int64, limit, offset int,
 	count := int64(0)
 	query.Count(&count)
 
-	// Run the actual query.
-	err = query.Limit(limit).Offset(offset).Find(&rhcConnections).Error
+	// Run the actual query. The connections are ordered by their IDs —after any requested sorting— so that the pages
+	// don't overlap or skip connections.
+	err = query.
+		Order(`"rhc_connections"."id" ASC`).
+		Limit(pageSize(limit)).
+		Offset(offset).
+		Find(&rhcConnections).
+		Error
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
 	return rhcConnections, count, nil
 }
+
+func (s *rhcConnectionDaoImpl) AdminListForSource(sourceId *int64, includeDeleted bool) ([]m.RhcConnectionAdminEntry, error) {
+	// The entry is deleted when either the connection or its link to the source were soft deleted.
+	deleted := fmt.Sprintf("(%s OR %s)", softDeletedCondition(&m.RhcConnection{}, "rhc_connections"), softDeletedCondition(&m.SourceRhcConnection{}, "sr"))
+
+	query := s.db().
+		Model(&m.RhcConnection{}).
+		Select(`"rhc_connections".*, ` + deleted + ` AS "deleted"`).
+		Joins(`INNER JOIN "source_rhc_connections" "sr" ON "rhc_connections"."id" = "sr"."rhc_connection_id"`).
+		Where(`"sr"."source_id" = ?`, sourceId)
+
+	// "Unscoped" only lifts the soft delete condition of the connections, so the one of the links is added by hand.
+	if includeDeleted {
+		query = query.Unscoped()
+	} else {
+		query = query.Where("NOT " + deleted)
+	}
+
+	entries := make([]m.RhcConnectionAdminEntry, 0)
+	err := query.
+		Order(`"rhc_connections"."id" ASC`).
+		Scan(&entries).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (s *rhcConnectionDaoImpl) ListForSourceUID(sourceUID string, limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error) {
+	var sourceIds []int64
+	err := s.db().
+		Model(&m.Source{}).
+		Where(`uid = ?`, sourceUID).
+		Where(`tenant_id = ?`, s.TenantID).
+		Limit(1).
+		Pluck(`id`, &sourceIds).
+		Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(sourceIds) == 0 {
+		return nil, 0, util.NewErrNotFound("source")
+	}
+
+	return s.ListForSource(&sourceIds[0], limit, offset, filters)
+}
@@ -1,5 +1,29 @@
 package dao
 
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/datatypes"
+	"gorm.io/gorm/clause"
+)
+
 // import (
 // 	"bytes"
 // 	"errors"
@@ -68,7 +92,7 @@ const RHC_CONNECTION_SCHEMA = "rhc_connection"
 // 	}
 
 // 	var gotJoinTable model.SourceRhcConnection
-// 	err = DB.Debug().
+// 	err = DB.
 // 		Model(&model.SourceRhcConnection{}).
 // 		Where(`rhc_connection_id = ?`, got.ID).
 // 		Find(&gotJoinTable).
@@ -152,7 +176,7 @@ const RHC_CONNECTION_SCHEMA = "rhc_connection"
 // 	}
 
 // 	var gotJoinTable = make([]model.SourceRhcConnection, 0, 2)
-// 	err = DB.Debug().
+// 	err = DB.
 // 		Model(&model.SourceRhcConnection{}).
 // 		Where(`rhc_connection_id = ?`, got.ID).
 // 		Find(&gotJoinTable).
@@ -241,7 +265,7 @@ const RHC_CONNECTION_SCHEMA = "rhc_connection"
 // 	}
 
 // 	var rhcConnectionExists bool
-// 	err = DB.Debug().
+// 	err = DB.
 // 		Model(&model.RhcConnection{}).
 // 		Select(`1`).
 // 		Where(`id = ?`, fixtures.TestRhcConnectionData[0].ID).
@@ -332,7 +356,7 @@ const RHC_CONNECTION_SCHEMA = "rhc_connection"
 
 // 	// Find all the connections that we will remove from the DB.
 // 	dbRhcConnections := make([]model.RhcConnection, 0)
-// 	err := DB.Debug().
+// 	err := DB.
 // 		Model(&model.RhcConnection{}).
 // 		Find(&dbRhcConnections).
 // 		Error
@@ -344,7 +368,7 @@ const RHC_CONNECTION_SCHEMA = "rhc_connection"
 // 	// Remove each connection so we can simulate a "rows are closed" situation, where the ".Next" function returns a
 // 	// "false" value.
 // 	for _, conn := range dbRhcConnections {
-// 		err = DB.Debug().
+// 		err = DB.
 // 			Delete(conn).
 // 			Error
 
@@ -505,3 +529,2466 @@ const RHC_CONNECTION_SCHEMA = "rhc_connection"
 // 	}
 // 	DropSchema("offset_limit")
 // }
+
+// sortedSourceIds returns the sorted IDs of the sources the connection is linked to.
+func sortedSourceIds(rhcConnection *m.RhcConnection) []int64 {
+	sourceIds := make([]int64, 0, len(rhcConnection.Sources))
+	for _, source := range rhcConnection.Sources {
+		sourceIds = append(sourceIds, source.ID)
+	}
+
+	sort.Slice(sourceIds, func(i, j int) bool { return sourceIds[i] < sourceIds[j] })
+
+	return sourceIds
+}
+
+// TestRhcConnectionGetBySourceAndRhcId tests that the connection is only found through the tenant's sources it is
+// linked to, and that it comes with all its sources and not just the one it was looked up by.
+func TestRhcConnectionGetBySourceAndRhcId(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema(RHC_CONNECTION_SCHEMA)
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+
+	// The "a" connection is linked to the first and the second sources.
+	want := fixtures.TestRhcConnectionData[0]
+	got, err := rhcConnectionDao.GetBySourceAndRhcId(&fixtures.TestSourceData[1].ID, want.RhcId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	wantSourceIds := []int64{fixtures.TestSourceData[0].ID, fixtures.TestSourceData[1].ID}
+	if got.ID != want.ID || !reflect.DeepEqual(sortedSourceIds(got), wantSourceIds) {
+		t.Errorf(`want connection "%d" linked to "%v", got connection "%d" linked to "%v"`, want.ID, wantSourceIds, got.ID, sortedSourceIds(got))
+	}
+
+	// The "b" connection is only linked to the first source.
+	_, err = rhcConnectionDao.GetBySourceAndRhcId(&fixtures.TestSourceData[1].ID, fixtures.TestRhcConnectionData[1].RhcId)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error for a connection which isn't linked to the source, got "%v"`, err)
+	}
+
+	_, err = rhcConnectionDao.GetBySourceAndRhcId(&fixtures.TestSourceData[0].ID, "missing")
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error for a missing rhc_id, got "%v"`, err)
+	}
+
+	otherTenantId := fixtures.TestTenantData[1].Id
+	_, err = GetRhcConnectionDao(context.Background(), &otherTenantId).GetBySourceAndRhcId(&fixtures.TestSourceData[0].ID, want.RhcId)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error for another tenant, got "%v"`, err)
+	}
+
+	DropSchema(RHC_CONNECTION_SCHEMA)
+}
+
+// TestRhcConnectionListByApplicationType tests that only the connections linked to the tenant's sources which have an
+// application of the given type are listed, along with all their sources.
+func TestRhcConnectionListByApplicationType(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema(RHC_CONNECTION_SCHEMA)
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+
+	// The second application type only has applications on the first and the fourth sources, and the fourth one isn't
+	// linked to any connection.
+	appTypeId := fixtures.TestApplicationTypeData[1].Id
+	rhcConnections, count, err := rhcConnectionDao.ListByApplicationType(appTypeId, 100, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	wantIds := []int64{fixtures.TestRhcConnectionData[0].ID, fixtures.TestRhcConnectionData[1].ID}
+	if count != int64(len(wantIds)) || len(rhcConnections) != len(wantIds) {
+		t.Fatalf(`want "%d" connections, got "%d" with a count of "%d"`, len(wantIds), len(rhcConnections), count)
+	}
+
+	for i, rhcConnection := range rhcConnections {
+		if rhcConnection.ID != wantIds[i] {
+			t.Errorf(`want connection "%d", got "%d"`, wantIds[i], rhcConnection.ID)
+		}
+	}
+
+	// The "a" connection keeps the second source even though it has no applications of the given type.
+	wantSourceIds := []int64{fixtures.TestSourceData[0].ID, fixtures.TestSourceData[1].ID}
+	if got := sortedSourceIds(&rhcConnections[0]); !reflect.DeepEqual(got, wantSourceIds) {
+		t.Errorf(`want the connection linked to "%v", got "%v"`, wantSourceIds, got)
+	}
+
+	rhcConnections, count, err = rhcConnectionDao.ListByApplicationType(12345, 100, 0)
+	if err != nil || count != 0 || len(rhcConnections) != 0 {
+		t.Errorf(`want no connections for a missing application type, got "%d" with the error "%v"`, len(rhcConnections), err)
+	}
+
+	otherTenantId := fixtures.TestTenantData[1].Id
+	rhcConnections, count, err = GetRhcConnectionDao(context.Background(), &otherTenantId).ListByApplicationType(appTypeId, 100, 0)
+	if err != nil || count != 0 || len(rhcConnections) != 0 {
+		t.Errorf(`want no connections for another tenant, got "%d" with the error "%v"`, len(rhcConnections), err)
+	}
+
+	DropSchema(RHC_CONNECTION_SCHEMA)
+}
+
+// TestRhcConnectionDeleteIfExists tests that the tenant's connection gets deleted, and that the connections which are
+// already gone or belong to other tenants are reported as not deleted without an error.
+func TestRhcConnectionDeleteIfExists(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema(RHC_CONNECTION_SCHEMA)
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+
+	id := fixtures.TestRhcConnectionData[2].ID
+	deleted, rhcConnection, err := rhcConnectionDao.DeleteIfExists(&id)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if !deleted || rhcConnection == nil || rhcConnection.ID != id {
+		t.Errorf(`want connection "%d" deleted, got deleted "%t" and "%+v"`, id, deleted, rhcConnection)
+	}
+
+	if rhcConnectionExists(t, id) || countSourceRhcConnections(t, id, fixtures.TestSourceData[1].ID) != 0 {
+		t.Errorf(`want the connection and its links deleted, but they still exist`)
+	}
+
+	// Deleting it again is not an error.
+	deleted, rhcConnection, err = rhcConnectionDao.DeleteIfExists(&id)
+	if err != nil || deleted || rhcConnection != nil {
+		t.Errorf(`want nothing deleted for an already deleted connection, got deleted "%t", "%+v" and the error "%v"`, deleted, rhcConnection, err)
+	}
+
+	otherTenantId := fixtures.TestTenantData[1].Id
+	otherId := fixtures.TestRhcConnectionData[1].ID
+	deleted, _, err = GetRhcConnectionDao(context.Background(), &otherTenantId).DeleteIfExists(&otherId)
+	if err != nil || deleted {
+		t.Errorf(`want nothing deleted for another tenant, got deleted "%t" and the error "%v"`, deleted, err)
+	}
+
+	if !rhcConnectionExists(t, otherId) {
+		t.Errorf(`want the connection "%d" kept, but it got deleted`, otherId)
+	}
+
+	DropSchema(RHC_CONNECTION_SCHEMA)
+}
+
+// TestRhcConnectionListShared tests that only the tenant's connections which are linked to at least the given number
+// of sources are listed, and that two sources are required by default.
+func TestRhcConnectionListShared(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema(RHC_CONNECTION_SCHEMA)
+
+	tenantId := fixtures.TestTenantData[0].Id
+	otherTenantId := fixtures.TestTenantData[1].Id
+
+	// Only the "a" connection is linked to two sources.
+	testCases := []struct {
+		name       string
+		tenantId   int64
+		minSources int
+		wantIds    []int64
+	}{
+		{
+			name:     "default minimum",
+			tenantId: tenantId,
+			wantIds:  []int64{fixtures.TestRhcConnectionData[0].ID},
+		},
+		{
+			name:       "single source",
+			tenantId:   tenantId,
+			minSources: 1,
+			wantIds:    []int64{fixtures.TestRhcConnectionData[0].ID, fixtures.TestRhcConnectionData[1].ID, fixtures.TestRhcConnectionData[2].ID},
+		},
+		{
+			name:       "more sources than any connection has",
+			tenantId:   tenantId,
+			minSources: 3,
+			wantIds:    []int64{},
+		},
+		{
+			name:     "another tenant",
+			tenantId: otherTenantId,
+			wantIds:  []int64{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tcTenantId := tc.tenantId
+		rhcConnections, count, err := GetRhcConnectionDao(context.Background(), &tcTenantId).ListShared(tc.minSources, 100, 0)
+		if err != nil {
+			t.Fatalf(`[%s] want no error, got "%s"`, tc.name, err)
+		}
+
+		gotIds := make([]int64, 0, len(rhcConnections))
+		for _, rhcConnection := range rhcConnections {
+			gotIds = append(gotIds, rhcConnection.ID)
+		}
+
+		if count != int64(len(tc.wantIds)) || !reflect.DeepEqual(gotIds, tc.wantIds) {
+			t.Errorf(`[%s] want the connections "%v", got "%v" with a count of "%d"`, tc.name, tc.wantIds, gotIds, count)
+		}
+	}
+
+	DropSchema(RHC_CONNECTION_SCHEMA)
+}
+
+// createDuplicateRhcConnection creates a connection for the tenant with the given rhc_id, linked to the given
+// sources. The unique index on the tenant and the rhc_id is expected to be dropped beforehand.
+func createDuplicateRhcConnection(t *testing.T, tenantId int64, rhcId string, sourceIds ...int64) m.RhcConnection {
+	rhcConnection := m.RhcConnection{RhcId: rhcId, TenantId: tenantId}
+	err := DB.Omit(clause.Associations).Create(&rhcConnection).Error
+	if err != nil {
+		t.Fatalf(`could not create the duplicate connection: %s`, err)
+	}
+
+	for _, sourceId := range sourceIds {
+		link := m.SourceRhcConnection{SourceId: sourceId, RhcConnectionId: rhcConnection.ID, TenantId: tenantId}
+		err = DB.Omit(clause.Associations).Create(&link).Error
+		if err != nil {
+			t.Fatalf(`could not link the duplicate connection to the source "%d": %s`, sourceId, err)
+		}
+	}
+
+	return rhcConnection
+}
+
+// TestRhcConnectionDeduplicate tests that the tenant's connections which share an rhc_id are merged into the oldest
+// one, that the links which would collide with the oldest connection's are dropped, and that the other tenants'
+// connections are left alone.
+func TestRhcConnectionDeduplicate(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema(RHC_CONNECTION_SCHEMA)
+
+	// The duplicates can only exist in the databases which predate the unique index, so it is dropped to create them.
+	err := DB.Exec(`DROP INDEX IF EXISTS "index_rhc_connections_on_tenant_id_and_rhc_id"`).Error
+	if err != nil {
+		t.Fatalf(`could not drop the unique index: %s`, err)
+	}
+
+	tenantId := fixtures.TestTenantData[0].Id
+	otherTenantId := fixtures.TestTenantData[1].Id
+
+	// The oldest "a" connection is already linked to the first and the second sources, so the duplicate's link to the
+	// first source collides with it, and its link to the third one gets moved.
+	canonical := fixtures.TestRhcConnectionData[0]
+	collidingSourceId := fixtures.TestSourceData[0].ID
+	movedSourceId := fixtures.TestSourceData[2].ID
+	duplicate := createDuplicateRhcConnection(t, tenantId, canonical.RhcId, collidingSourceId, movedSourceId)
+
+	otherTenantSource := createOtherTenantSource(t)
+	otherTenantConnection := createDuplicateRhcConnection(t, otherTenantId, canonical.RhcId, otherTenantSource.ID)
+
+	got, err := GetRhcConnectionDao(context.Background(), &tenantId).Deduplicate(canonical.RhcId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if got.ID != canonical.ID {
+		t.Errorf(`want the duplicates merged into connection "%d", got "%d"`, canonical.ID, got.ID)
+	}
+
+	if rhcConnectionExists(t, duplicate.ID) {
+		t.Errorf(`want the duplicate connection "%d" deleted, but it still exists`, duplicate.ID)
+	}
+
+	for _, sourceId := range []int64{collidingSourceId, movedSourceId} {
+		if count := countSourceRhcConnections(t, canonical.ID, sourceId); count != 1 {
+			t.Errorf(`want the source "%d" linked once to the connection "%d", got "%d" links`, sourceId, canonical.ID, count)
+		}
+	}
+
+	if !rhcConnectionExists(t, otherTenantConnection.ID) || countSourceRhcConnections(t, otherTenantConnection.ID, otherTenantSource.ID) != 1 {
+		t.Errorf(`want the other tenant's connection left alone, but it was modified`)
+	}
+
+	// The other tenant's connection has no duplicates, so it is returned as is.
+	got, err = GetRhcConnectionDao(context.Background(), &otherTenantId).Deduplicate(canonical.RhcId)
+	if err != nil || got.ID != otherTenantConnection.ID {
+		t.Errorf(`want the other tenant's connection "%d", got "%+v" with the error "%v"`, otherTenantConnection.ID, got, err)
+	}
+
+	_, err = GetRhcConnectionDao(context.Background(), &tenantId).Deduplicate("missing")
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error for a missing rhc_id, got "%v"`, err)
+	}
+
+	DropSchema(RHC_CONNECTION_SCHEMA)
+}
+
+// TestRhcConnectionCountForTenant tests that the connections linked to the tenant's sources are counted once each, no
+// matter how many sources they are linked to, and that other tenants' connections are not counted.
+func TestRhcConnectionCountForTenant(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema(RHC_CONNECTION_SCHEMA)
+
+	tenantId := fixtures.TestTenantData[0].Id
+	otherTenantId := fixtures.TestTenantData[1].Id
+
+	count, err := GetRhcConnectionDao(context.Background(), &tenantId).CountForTenant()
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	// The "a" connection is linked to two sources, but it is still a single connection.
+	if want := int64(len(fixtures.TestRhcConnectionData)); count != want {
+		t.Errorf(`want "%d" connections, got "%d"`, want, count)
+	}
+
+	count, err = GetRhcConnectionDao(context.Background(), &otherTenantId).CountForTenant()
+	if err != nil || count != 0 {
+		t.Errorf(`want no connections for a tenant without any, got "%d" with the error "%v"`, count, err)
+	}
+
+	otherTenantSource := createOtherTenantSource(t)
+	_, err = GetRhcConnectionDao(context.Background(), &otherTenantId).Create(&m.RhcConnection{RhcId: "other tenant", Sources: []m.Source{{ID: otherTenantSource.ID}}})
+	if err != nil {
+		t.Fatalf(`could not create the other tenant's connection: %s`, err)
+	}
+
+	count, err = GetRhcConnectionDao(context.Background(), &otherTenantId).CountForTenant()
+	if err != nil || count != 1 {
+		t.Errorf(`want "1" connection for the other tenant, got "%d" with the error "%v"`, count, err)
+	}
+
+	count, err = GetRhcConnectionDao(context.Background(), &tenantId).CountForTenant()
+	if want := int64(len(fixtures.TestRhcConnectionData)); err != nil || count != want {
+		t.Errorf(`want the tenant's count to stay at "%d", got "%d" with the error "%v"`, want, count, err)
+	}
+
+	DropSchema(RHC_CONNECTION_SCHEMA)
+}
+
+// TestAdminListForSource tests that the connections linked to the source are listed regardless of the DAO's tenant.
+func TestAdminListForSource(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_admin_list_for_source")
+
+	sourceId := fixtures.TestSourceData[0].ID
+	otherTenant := fixtures.TestTenantData[0].Id + 12345
+
+	for _, includeDeleted := range []bool{false, true} {
+		entries, err := GetRhcConnectionDao(context.Background(), &otherTenant).AdminListForSource(&sourceId, includeDeleted)
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+
+		var want []int64
+		for _, link := range fixtures.TestSourceRhcConnectionData {
+			if link.SourceId == sourceId {
+				want = append(want, link.RhcConnectionId)
+			}
+		}
+
+		if len(entries) != len(want) {
+			t.Fatalf(`want "%d" connections, got "%d"`, len(want), len(entries))
+		}
+
+		for i, entry := range entries {
+			if entry.ID != want[i] {
+				t.Errorf(`want connection "%d", got "%d"`, want[i], entry.ID)
+			}
+
+			if entry.Deleted {
+				t.Errorf(`want connection "%d" not to be marked as deleted`, entry.ID)
+			}
+		}
+	}
+
+	DropSchema("rhc_connection_admin_list_for_source")
+}
+
+// fakeRhcAvailabilityPublisher stores the published requests instead of sending them anywhere.
+type fakeRhcAvailabilityPublisher struct {
+	requests []m.RhcConnectionAvailabilityCheck
+}
+
+func (f *fakeRhcAvailabilityPublisher) PublishAvailabilityCheck(request m.RhcConnectionAvailabilityCheck) error {
+	f.requests = append(f.requests, request)
+	return nil
+}
+
+// TestRequestRhcConnectionAvailabilityCheck tests that the availability check requests are only published when the
+// feature is enabled, and that they carry the connection's details.
+func TestRequestRhcConnectionAvailabilityCheck(t *testing.T) {
+	originalPublisher := RhcAvailabilityPublisher
+	originalSetting := config.Get().RequestAvailabilityOnCreate
+	defer func() {
+		RhcAvailabilityPublisher = originalPublisher
+		config.Get().RequestAvailabilityOnCreate = originalSetting
+	}()
+
+	publisher := &fakeRhcAvailabilityPublisher{}
+	RhcAvailabilityPublisher = publisher
+
+	rhcConnection := &m.RhcConnection{
+		ID:      5,
+		RhcId:   "a",
+		Sources: []m.Source{{ID: 10}},
+	}
+
+	config.Get().RequestAvailabilityOnCreate = false
+	requestRhcConnectionAvailabilityCheck(1, rhcConnection)
+
+	if len(publisher.requests) != 0 {
+		t.Fatalf("want no requests published when the feature is disabled, got %d", len(publisher.requests))
+	}
+
+	config.Get().RequestAvailabilityOnCreate = true
+	requestRhcConnectionAvailabilityCheck(1, rhcConnection)
+
+	if len(publisher.requests) != 1 {
+		t.Fatalf("want one request published, got %d", len(publisher.requests))
+	}
+
+	want := m.RhcConnectionAvailabilityCheck{RhcConnectionId: 5, RhcId: "a", SourceId: 10, TenantId: 1}
+	if publisher.requests[0] != want {
+		t.Errorf("want %+v, got %+v", want, publisher.requests[0])
+	}
+}
+
+// TestCheckIntegrityHealthy tests that the fixtures don't present any integrity problems.
+func TestCheckIntegrityHealthy(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_check_integrity")
+
+	tenantId := fixtures.TestTenantData[0].Id
+
+	report, err := GetRhcConnectionDao(context.Background(), &tenantId).CheckIntegrity()
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if !report.Healthy() {
+		t.Errorf(`want a healthy report, got "%+v"`, report)
+	}
+
+	DropSchema("rhc_connection_check_integrity")
+}
+
+// TestCheckIntegrityProblems tests that the orphaned connections and the tenant mismatched links are reported, both
+// to the tenant of the link and to the tenant of the source.
+func TestCheckIntegrityProblems(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_check_integrity")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	otherTenantId := fixtures.TestTenantData[1].Id
+
+	orphan := m.RhcConnection{RhcId: "orphan", TenantId: tenantId}
+	err := DB.Create(&orphan).Error
+	if err != nil {
+		t.Fatalf(`could not create the orphaned connection: %s`, err)
+	}
+
+	// The link belongs to the other tenant, while both the source and the connection belong to the first one.
+	mismatched := m.SourceRhcConnection{
+		SourceId:        fixtures.TestSourceData[1].ID,
+		RhcConnectionId: fixtures.TestRhcConnectionData[1].ID,
+		TenantId:        otherTenantId,
+	}
+	err = DB.Create(&mismatched).Error
+	if err != nil {
+		t.Fatalf(`could not create the mismatched link: %s`, err)
+	}
+
+	report, err := GetRhcConnectionDao(context.Background(), &tenantId).CheckIntegrity()
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if report.OrphanedRhcConnections.Count != 1 || len(report.OrphanedRhcConnections.SampleIds) != 1 || report.OrphanedRhcConnections.SampleIds[0] != orphan.ID {
+		t.Errorf(`want the orphaned connection "%d" to be reported, got "%+v"`, orphan.ID, report.OrphanedRhcConnections)
+	}
+
+	wantLink := m.IntegrityLinkId{SourceId: mismatched.SourceId, RhcConnectionId: mismatched.RhcConnectionId}
+	if report.TenantMismatchedLinks.Count != 1 || len(report.TenantMismatchedLinks.SampleLinks) != 1 || report.TenantMismatchedLinks.SampleLinks[0] != wantLink {
+		t.Errorf(`want the mismatched link "%+v" to be reported, got "%+v"`, wantLink, report.TenantMismatchedLinks)
+	}
+
+	if report.MissingSourceLinks.Count != 0 || report.MissingRhcConnectionLinks.Count != 0 {
+		t.Errorf(`want no links to missing rows, got "%+v" and "%+v"`, report.MissingSourceLinks, report.MissingRhcConnectionLinks)
+	}
+
+	// The other tenant gets the mismatched link reported too, but not the first tenant's orphaned connection.
+	report, err = GetRhcConnectionDao(context.Background(), &otherTenantId).CheckIntegrity()
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if report.TenantMismatchedLinks.Count != 1 {
+		t.Errorf(`want the mismatched link to be reported to the other tenant, got "%+v"`, report.TenantMismatchedLinks)
+	}
+
+	if report.OrphanedRhcConnections.Count != 0 {
+		t.Errorf(`want no orphaned connections for the other tenant, got "%+v"`, report.OrphanedRhcConnections)
+	}
+
+	DropSchema("rhc_connection_check_integrity")
+}
+
+// TestCountSnapshot tests that the connections get counted per tenant.
+func TestCountSnapshot(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_count_snapshot")
+
+	otherTenant := fixtures.TestTenantData[1].Id
+	err := DB.Create(&m.RhcConnection{RhcId: "other-tenant-rhc-id", TenantId: otherTenant}).Error
+	if err != nil {
+		t.Fatalf(`could not create the connection: %s`, err)
+	}
+
+	snapshot, err := GetRhcConnectionDao(context.Background(), &otherTenant).CountSnapshot()
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	want := map[int64]int64{
+		fixtures.TestTenantData[0].Id: int64(len(fixtures.TestRhcConnectionData)),
+		otherTenant:                   1,
+	}
+
+	if len(snapshot) != len(want) {
+		t.Fatalf(`want "%d" tenants in the snapshot, got "%+v"`, len(want), snapshot)
+	}
+
+	for _, count := range snapshot {
+		if want[count.TenantID] != count.Count {
+			t.Errorf(`want "%d" connections for tenant "%d", got "%d"`, want[count.TenantID], count.TenantID, count.Count)
+		}
+	}
+
+	DropSchema("rhc_connection_count_snapshot")
+}
+
+// countSourceRhcConnections returns the number of links between the given connection and source.
+func countSourceRhcConnections(t *testing.T, rhcConnectionId, sourceId int64) int64 {
+	var count int64
+	err := DB.
+		Model(&m.SourceRhcConnection{}).
+		Where("rhc_connection_id = ?", rhcConnectionId).
+		Where("source_id = ?", sourceId).
+		Count(&count).
+		Error
+
+	if err != nil {
+		t.Fatalf(`could not count the links: %s`, err)
+	}
+
+	return count
+}
+
+// TestCreateOrLinkNewConnection tests that a new connection gets created and linked to the source.
+func TestCreateOrLinkNewConnection(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_create_or_link")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	sourceId := fixtures.TestSourceData[0].ID
+
+	rhcConnection, linked, err := GetRhcConnectionDao(context.Background(), &tenantId).CreateOrLink("new-rhc-id", sourceId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if !linked {
+		t.Errorf(`want a new link, got none`)
+	}
+
+	if rhcConnection.ID == 0 || rhcConnection.RhcId != "new-rhc-id" {
+		t.Errorf(`want a new connection with rhc_id "new-rhc-id", got "%+v"`, rhcConnection)
+	}
+
+	if count := countSourceRhcConnections(t, rhcConnection.ID, sourceId); count != 1 {
+		t.Errorf(`want one link, got "%d"`, count)
+	}
+
+	DropSchema("rhc_connection_create_or_link")
+}
+
+// TestCreateOrLinkExistingConnectionNewLink tests that an existing connection gets linked to a source it wasn't
+// linked to, without creating another connection.
+func TestCreateOrLinkExistingConnectionNewLink(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_create_or_link")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	sourceId := fixtures.TestSourceData[0].ID
+	existing := fixtures.TestRhcConnectionData[2]
+
+	rhcConnection, linked, err := GetRhcConnectionDao(context.Background(), &tenantId).CreateOrLink(existing.RhcId, sourceId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if !linked {
+		t.Errorf(`want a new link, got none`)
+	}
+
+	if rhcConnection.ID != existing.ID {
+		t.Errorf(`want the existing connection "%d", got "%d"`, existing.ID, rhcConnection.ID)
+	}
+
+	if count := countSourceRhcConnections(t, existing.ID, sourceId); count != 1 {
+		t.Errorf(`want one link, got "%d"`, count)
+	}
+
+	DropSchema("rhc_connection_create_or_link")
+}
+
+// TestCreateOrLinkFullyExisting tests that nothing gets created when the connection already exists and is already
+// linked to the source.
+func TestCreateOrLinkFullyExisting(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_create_or_link")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	sourceId := fixtures.TestSourceData[0].ID
+	existing := fixtures.TestRhcConnectionData[0]
+
+	rhcConnection, linked, err := GetRhcConnectionDao(context.Background(), &tenantId).CreateOrLink(existing.RhcId, sourceId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if linked {
+		t.Errorf(`want no new link, got one`)
+	}
+
+	if rhcConnection.ID != existing.ID {
+		t.Errorf(`want the existing connection "%d", got "%d"`, existing.ID, rhcConnection.ID)
+	}
+
+	if count := countSourceRhcConnections(t, existing.ID, sourceId); count != 1 {
+		t.Errorf(`want one link, got "%d"`, count)
+	}
+
+	DropSchema("rhc_connection_create_or_link")
+}
+
+// TestCreateOrLinkSourceNotFound tests that a "not found" error is returned when the source doesn't belong to the
+// tenant.
+func TestCreateOrLinkSourceNotFound(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_create_or_link")
+
+	tenantId := fixtures.TestTenantData[0].Id
+
+	_, _, err := GetRhcConnectionDao(context.Background(), &tenantId).CreateOrLink("new-rhc-id", 12345)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("rhc_connection_create_or_link")
+}
+
+// TestDeleteGuardBlocked tests that the connections linked to available sources cannot be deleted when the guard is
+// enabled.
+func TestDeleteGuardBlocked(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_delete_guard")
+
+	backupGuard := rhcConnectionDeleteGuard
+	rhcConnectionDeleteGuard = true
+
+	tenantId := fixtures.TestTenantData[0].Id
+	id := fixtures.TestRhcConnectionData[0].ID
+
+	_, err := GetRhcConnectionDao(context.Background(), &tenantId).Delete(&id, false)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	var count int64
+	err = DB.Model(&m.RhcConnection{}).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		t.Fatalf(`could not count the connections: %s`, err)
+	}
+
+	if count != 1 {
+		t.Errorf(`want the connection to be kept, got "%d" connections`, count)
+	}
+
+	rhcConnectionDeleteGuard = backupGuard
+	DropSchema("rhc_connection_delete_guard")
+}
+
+// TestDeleteGuardForced tests that forcing the deletion bypasses the guard.
+func TestDeleteGuardForced(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_delete_guard")
+
+	backupGuard := rhcConnectionDeleteGuard
+	rhcConnectionDeleteGuard = true
+
+	tenantId := fixtures.TestTenantData[0].Id
+	id := fixtures.TestRhcConnectionData[0].ID
+
+	rhcConnection, err := GetRhcConnectionDao(context.Background(), &tenantId).Delete(&id, true)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if rhcConnection.ID != id {
+		t.Errorf(`want the deleted connection "%d", got "%d"`, id, rhcConnection.ID)
+	}
+
+	rhcConnectionDeleteGuard = backupGuard
+	DropSchema("rhc_connection_delete_guard")
+}
+
+// TestDeleteGuardUnlinked tests that the connections which aren't linked to any source can be deleted with the guard
+// enabled.
+func TestDeleteGuardUnlinked(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_delete_guard")
+
+	backupGuard := rhcConnectionDeleteGuard
+	rhcConnectionDeleteGuard = true
+
+	tenantId := fixtures.TestTenantData[0].Id
+
+	unlinked := m.RhcConnection{RhcId: "unlinked-rhc-id"}
+	err := DB.Create(&unlinked).Error
+	if err != nil {
+		t.Fatalf(`could not create the connection: %s`, err)
+	}
+
+	rhcConnection, err := GetRhcConnectionDao(context.Background(), &tenantId).Delete(&unlinked.ID, false)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if rhcConnection.ID != unlinked.ID {
+		t.Errorf(`want the deleted connection "%d", got "%d"`, unlinked.ID, rhcConnection.ID)
+	}
+
+	rhcConnectionDeleteGuard = backupGuard
+	DropSchema("rhc_connection_delete_guard")
+}
+
+// TestRhcConnectionGetByIds tests that the requested connections are fetched along with their related sources, and
+// that the missing IDs are skipped.
+func TestRhcConnectionGetByIds(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_get_by_ids")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	first := fixtures.TestRhcConnectionData[0]
+	third := fixtures.TestRhcConnectionData[2]
+
+	rhcConnections, err := GetRhcConnectionDao(context.Background(), &tenantId).GetByIds([]int64{third.ID, first.ID, 12345})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(rhcConnections) != 2 {
+		t.Fatalf(`want two connections, got "%d"`, len(rhcConnections))
+	}
+
+	if rhcConnections[0].ID != first.ID || rhcConnections[1].ID != third.ID {
+		t.Errorf(`want connections "%d" and "%d", got "%d" and "%d"`, first.ID, third.ID, rhcConnections[0].ID, rhcConnections[1].ID)
+	}
+
+	// The first connection is linked to two sources in the fixtures.
+	var wantSources int
+	for _, src := range fixtures.TestSourceRhcConnectionData {
+		if src.RhcConnectionId == first.ID {
+			wantSources++
+		}
+	}
+
+	if len(rhcConnections[0].Sources) != wantSources {
+		t.Errorf(`want "%d" related sources, got "%d"`, wantSources, len(rhcConnections[0].Sources))
+	}
+
+	DropSchema("rhc_connection_get_by_ids")
+}
+
+// TestRhcConnectionGetByIdsEmpty tests that no connections are returned for an empty list of IDs or for another
+// tenant's connections.
+func TestRhcConnectionGetByIdsEmpty(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_get_by_ids")
+
+	tenantId := fixtures.TestTenantData[0].Id
+
+	rhcConnections, err := GetRhcConnectionDao(context.Background(), &tenantId).GetByIds(nil)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(rhcConnections) != 0 {
+		t.Errorf(`want no connections, got "%d"`, len(rhcConnections))
+	}
+
+	otherTenant := tenantId + 12345
+	rhcConnections, err = GetRhcConnectionDao(context.Background(), &otherTenant).GetByIds([]int64{fixtures.TestRhcConnectionData[0].ID})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(rhcConnections) != 0 {
+		t.Errorf(`want no connections for another tenant, got "%d"`, len(rhcConnections))
+	}
+
+	DropSchema("rhc_connection_get_by_ids")
+}
+
+// TestRhcConnectionListWithLastModified tests that the latest modification of the filtered connections is returned
+// along with them, and that it is nil when no connections match the filters.
+func TestRhcConnectionListWithLastModified(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_last_modified")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	first := fixtures.TestRhcConnectionData[0]
+	third := fixtures.TestRhcConnectionData[2]
+
+	lastModified := time.Date(2022, 6, 1, 10, 30, 0, 0, time.UTC)
+	updates := map[int64]time.Time{first.ID: lastModified.Add(-time.Hour), third.ID: lastModified}
+	for id, updatedAt := range updates {
+		err := DB.Model(&m.RhcConnection{}).Where("id = ?", id).UpdateColumn("updated_at", updatedAt).Error
+		if err != nil {
+			t.Fatalf(`could not update the connection: %s`, err)
+		}
+	}
+
+	filters := []util.Filter{{Name: "rhc_id", Value: []string{first.RhcId, third.RhcId}}}
+
+	rhcConnections, count, gotLastModified, err := GetRhcConnectionDao(context.Background(), &tenantId).ListWithLastModified(1, 0, filters)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	// The last modification covers the whole filtered set, not just the page.
+	if count != 2 || len(rhcConnections) != 1 || rhcConnections[0].ID != first.ID {
+		t.Errorf(`want the first of two connections, got "%d" connections out of "%d"`, len(rhcConnections), count)
+	}
+
+	if gotLastModified == nil || !gotLastModified.Equal(lastModified) {
+		t.Errorf(`want the last modification "%s", got "%v"`, lastModified, gotLastModified)
+	}
+
+	filters = []util.Filter{{Name: "rhc_id", Value: []string{"unknown"}}}
+
+	_, count, gotLastModified, err = GetRhcConnectionDao(context.Background(), &tenantId).ListWithLastModified(10, 0, filters)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 0 || gotLastModified != nil {
+		t.Errorf(`want no connections and no last modification, got "%d" connections and "%v"`, count, gotLastModified)
+	}
+
+	DropSchema("rhc_connection_last_modified")
+}
+
+// TestLatestPerSource tests that the most recently created connection of each source is returned, keyed by the
+// source ID.
+func TestLatestPerSource(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_latest_per_source")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	firstSource := fixtures.TestSourceData[0].ID
+	secondSource := fixtures.TestSourceData[1].ID
+
+	// Make the first connection, which is linked to both sources, the newest one.
+	newest := fixtures.TestRhcConnectionData[0].ID
+	err := DB.
+		Model(&m.RhcConnection{}).
+		Where("id = ?", newest).
+		UpdateColumn("created_at", time.Now().Add(time.Hour)).
+		Error
+
+	if err != nil {
+		t.Fatalf(`could not update the connection's creation date: %s`, err)
+	}
+
+	latest, err := GetRhcConnectionDao(context.Background(), &tenantId).LatestPerSource([]int64{firstSource, secondSource, 12345})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(latest) != 2 {
+		t.Errorf(`want the latest connection of two sources, got "%v"`, latest)
+	}
+
+	for _, sourceId := range []int64{firstSource, secondSource} {
+		if latest[sourceId] == nil || latest[sourceId].ID != newest {
+			t.Errorf(`want the connection "%d" for the source "%d", got "%v"`, newest, sourceId, latest[sourceId])
+		}
+	}
+
+	otherTenant := tenantId + 12345
+	latest, err = GetRhcConnectionDao(context.Background(), &otherTenant).LatestPerSource([]int64{firstSource, secondSource})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(latest) != 0 {
+		t.Errorf(`want no connections for another tenant, got "%v"`, latest)
+	}
+
+	DropSchema("rhc_connection_latest_per_source")
+}
+
+// TestRhcConnectionListByStatus tests that only the connections with the given status are listed, and that the count
+// isn't inflated by the connections being linked to multiple sources.
+func TestRhcConnectionListByStatus(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_list_by_status")
+
+	tenantId := fixtures.TestTenantData[0].Id
+
+	var wantIds []int64
+	for _, rhcConnection := range fixtures.TestRhcConnectionData {
+		if rhcConnection.AvailabilityStatus == m.Available {
+			wantIds = append(wantIds, rhcConnection.ID)
+		}
+	}
+
+	rhcConnections, count, err := GetRhcConnectionDao(context.Background(), &tenantId).ListByStatus(m.Available, 1, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != int64(len(wantIds)) {
+		t.Errorf(`want a count of "%d", got "%d"`, len(wantIds), count)
+	}
+
+	if len(rhcConnections) != 1 || rhcConnections[0].ID != wantIds[0] {
+		t.Errorf(`want the connection "%d" in the first page, got "%+v"`, wantIds[0], rhcConnections)
+	}
+
+	rhcConnections, _, err = GetRhcConnectionDao(context.Background(), &tenantId).ListByStatus(m.Available, 10, 1)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(rhcConnections) != len(wantIds)-1 || rhcConnections[0].ID != wantIds[1] {
+		t.Errorf(`want the connection "%d" in the second page, got "%+v"`, wantIds[1], rhcConnections)
+	}
+
+	DropSchema("rhc_connection_list_by_status")
+}
+
+// TestRhcConnectionListByStatusInvalid tests that a bad request error is returned for unknown statuses.
+func TestRhcConnectionListByStatusInvalid(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_list_by_status")
+
+	tenantId := fixtures.TestTenantData[0].Id
+
+	_, _, err := GetRhcConnectionDao(context.Background(), &tenantId).ListByStatus("broken", 10, 0)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	DropSchema("rhc_connection_list_by_status")
+}
+
+// TestListForSourcePagination tests that paginating through a source's connections returns every connection exactly
+// once, in a stable order.
+func TestListForSourcePagination(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_list_for_source")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	sourceId := fixtures.TestSourceData[0].ID
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+
+	for i := 0; i < 5; i++ {
+		_, _, err := rhcConnectionDao.CreateOrLink(fmt.Sprintf("paginated-rhc-id-%d", i), sourceId)
+		if err != nil {
+			t.Fatalf(`could not create the connection: %s`, err)
+		}
+	}
+
+	const pageSize = 2
+	seen := make(map[int64]bool)
+	var lastId int64
+	var total int64
+
+	for offset := 0; ; offset += pageSize {
+		rhcConnections, count, err := rhcConnectionDao.ListForSource(&sourceId, pageSize, offset, nil)
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+
+		total = count
+		if len(rhcConnections) == 0 {
+			break
+		}
+
+		for _, rhcConnection := range rhcConnections {
+			if seen[rhcConnection.ID] {
+				t.Errorf(`connection "%d" showed up in more than one page`, rhcConnection.ID)
+			}
+
+			if rhcConnection.ID <= lastId {
+				t.Errorf(`want the connections ordered by their IDs, got "%d" after "%d"`, rhcConnection.ID, lastId)
+			}
+
+			seen[rhcConnection.ID] = true
+			lastId = rhcConnection.ID
+		}
+	}
+
+	if int64(len(seen)) != total {
+		t.Errorf(`want "%d" connections across all the pages, got "%d"`, total, len(seen))
+	}
+
+	DropSchema("rhc_connection_list_for_source")
+}
+
+// TestListForSourceUID tests that the connections are listed for the source the UID resolves to.
+func TestListForSourceUID(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_list_for_source_uid")
+
+	source := fixtures.TestSourceData[0]
+
+	wantIds := make(map[int64]bool)
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.SourceId == source.ID {
+			wantIds[link.RhcConnectionId] = true
+		}
+	}
+
+	rhcConnections, count, err := GetRhcConnectionDao(context.Background(), &source.TenantID).ListForSourceUID(*source.Uid, 100, 0, []util.Filter{})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if int(count) != len(wantIds) || len(rhcConnections) != len(wantIds) {
+		t.Errorf(`want "%d" connections, got "%d" with a count of "%d"`, len(wantIds), len(rhcConnections), count)
+	}
+
+	for _, rhcConnection := range rhcConnections {
+		if !wantIds[rhcConnection.ID] {
+			t.Errorf(`unexpected connection "%d" for source "%d"`, rhcConnection.ID, source.ID)
+		}
+	}
+
+	DropSchema("rhc_connection_list_for_source_uid")
+}
+
+// TestListForSourceUIDNotFound tests that a "not found" error is returned when the UID doesn't resolve to one of the
+// tenant's sources.
+func TestListForSourceUIDNotFound(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_list_for_source_uid")
+
+	source := fixtures.TestSourceData[0]
+	otherTenant := source.TenantID + 12345
+
+	testCases := []struct {
+		tenantId  int64
+		sourceUID string
+	}{
+		{tenantId: source.TenantID, sourceUID: "unknown-uid"},
+		{tenantId: otherTenant, sourceUID: *source.Uid},
+	}
+
+	for _, tc := range testCases {
+		tenantId := tc.tenantId
+
+		_, _, err := GetRhcConnectionDao(context.Background(), &tenantId).ListForSourceUID(tc.sourceUID, 100, 0, []util.Filter{})
+		if !errors.Is(err, util.ErrNotFoundEmpty) {
+			t.Errorf(`[tenant "%d", uid "%s"] want a not found error, got "%v"`, tc.tenantId, tc.sourceUID, err)
+		}
+	}
+
+	DropSchema("rhc_connection_list_for_source_uid")
+}
+
+// TestListModifiedBy tests that only the connections the actor has modified since the given time are listed, the
+// most recently modified ones first.
+func TestListModifiedBy(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_list_modified_by")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	now := time.Now()
+
+	modifications := []struct {
+		id        int64
+		updatedBy string
+		updatedAt time.Time
+	}{
+		{id: fixtures.TestRhcConnectionData[0].ID, updatedBy: "service-account-x", updatedAt: now.Add(-time.Hour)},
+		{id: fixtures.TestRhcConnectionData[1].ID, updatedBy: "service-account-x", updatedAt: now.Add(-48 * time.Hour)},
+		{id: fixtures.TestRhcConnectionData[2].ID, updatedBy: "service-account-x", updatedAt: now.Add(-time.Minute)},
+	}
+
+	for _, modification := range modifications {
+		err := DB.
+			Model(&m.RhcConnection{}).
+			Where("id = ?", modification.id).
+			UpdateColumns(map[string]interface{}{"updated_by": modification.updatedBy, "updated_at": modification.updatedAt}).
+			Error
+		if err != nil {
+			t.Fatalf(`could not set up the connection: %s`, err)
+		}
+	}
+
+	rhcConnections, count, err := GetRhcConnectionDao(context.Background(), &tenantId).ListModifiedBy("service-account-x", now.Add(-24*time.Hour), 10, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	want := []int64{fixtures.TestRhcConnectionData[2].ID, fixtures.TestRhcConnectionData[0].ID}
+	if count != int64(len(want)) || len(rhcConnections) != len(want) {
+		t.Fatalf(`want "%d" connections, got "%d" with a count of "%d"`, len(want), len(rhcConnections), count)
+	}
+
+	for i, rhcConnection := range rhcConnections {
+		if rhcConnection.ID != want[i] {
+			t.Errorf(`want connection "%d" at position "%d", got "%d"`, want[i], i, rhcConnection.ID)
+		}
+
+		if rhcConnection.UpdatedBy != "service-account-x" {
+			t.Errorf(`want the connection to be updated by "service-account-x", got "%s"`, rhcConnection.UpdatedBy)
+		}
+	}
+
+	// Other actors and other tenants don't get the connections.
+	rhcConnections, _, err = GetRhcConnectionDao(context.Background(), &tenantId).ListModifiedBy("someone-else", now.Add(-24*time.Hour), 10, 0)
+	if err != nil || len(rhcConnections) != 0 {
+		t.Errorf(`want no connections for another actor, got "%d" and error "%v"`, len(rhcConnections), err)
+	}
+
+	otherTenant := tenantId + 12345
+	rhcConnections, _, err = GetRhcConnectionDao(context.Background(), &otherTenant).ListModifiedBy("service-account-x", now.Add(-24*time.Hour), 10, 0)
+	if err != nil || len(rhcConnections) != 0 {
+		t.Errorf(`want no connections for another tenant, got "%d" and error "%v"`, len(rhcConnections), err)
+	}
+
+	DropSchema("rhc_connection_list_modified_by")
+}
+
+// TestRhcConnectionMerge tests that the loser's links are moved to the winner, that the colliding ones are dropped,
+// and that the dry run reports the same changes without applying them.
+func TestRhcConnectionMerge(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_merge")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+
+	// The winner is linked to the first source, and the loser to the first and the second ones.
+	winner := fixtures.TestRhcConnectionData[1]
+	loser := fixtures.TestRhcConnectionData[0]
+
+	merge, err := rhcConnectionDao.MergeDryRun(&winner.ID, &loser.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if merge.Canonical.ID != winner.ID || !reflect.DeepEqual(merge.DuplicateIds, []int64{loser.ID}) {
+		t.Errorf(`want connection "%d" merged into "%d", got "%+v"`, loser.ID, winner.ID, merge)
+	}
+
+	if !reflect.DeepEqual(merge.RepointedSourceIds, []int64{fixtures.TestSourceData[1].ID}) || !reflect.DeepEqual(merge.DroppedSourceIds, []int64{fixtures.TestSourceData[0].ID}) {
+		t.Errorf(`want the second source's link moved and the first one's dropped, got "%+v"`, merge)
+	}
+
+	// The dry run doesn't modify anything.
+	if count := countSourceRhcConnections(t, loser.ID, fixtures.TestSourceData[1].ID); count != 1 {
+		t.Errorf(`want the loser's links untouched by the dry run, got "%d" links`, count)
+	}
+
+	rhcConnection, err := rhcConnectionDao.Merge(&winner.ID, &loser.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	sourceIds := make([]int64, 0, len(rhcConnection.Sources))
+	for _, source := range rhcConnection.Sources {
+		sourceIds = append(sourceIds, source.ID)
+	}
+	sort.Slice(sourceIds, func(i, j int) bool { return sourceIds[i] < sourceIds[j] })
+
+	if rhcConnection.ID != winner.ID || !reflect.DeepEqual(sourceIds, []int64{fixtures.TestSourceData[0].ID, fixtures.TestSourceData[1].ID}) {
+		t.Errorf(`want the winner linked to both sources, got connection "%d" linked to "%v"`, rhcConnection.ID, sourceIds)
+	}
+
+	var loserCount int64
+	err = DB.Model(&m.RhcConnection{}).Where("id = ?", loser.ID).Count(&loserCount).Error
+	if err != nil {
+		t.Fatalf(`could not count the connections: %s`, err)
+	}
+
+	if loserCount != 0 {
+		t.Errorf(`want the loser deleted, but it still exists`)
+	}
+
+	DropSchema("rhc_connection_merge")
+}
+
+// TestRhcConnectionMergeInvalid tests that a connection cannot be merged into itself, nor with another tenant's
+// connection.
+func TestRhcConnectionMergeInvalid(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_merge")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	winner := fixtures.TestRhcConnectionData[1]
+	loser := fixtures.TestRhcConnectionData[0]
+
+	_, err := GetRhcConnectionDao(context.Background(), &tenantId).Merge(&winner.ID, &winner.ID)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	otherTenant := fixtures.TestTenantData[1].Id
+	_, err = GetRhcConnectionDao(context.Background(), &otherTenant).Merge(&winner.ID, &loser.ID)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	missing := int64(12345)
+	_, err = GetRhcConnectionDao(context.Background(), &tenantId).MergeDryRun(&winner.ID, &missing)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("rhc_connection_merge")
+}
+
+// TestInstrumentedRhcConnectionDao tests that the durations are recorded by method and outcome, and that the rows
+// returned by the list methods are counted.
+func TestInstrumentedRhcConnectionDao(t *testing.T) {
+	instrumented := &instrumentedRhcConnectionDao{
+		dao: &MockRhcConnectionDao{
+			RhcConnections: []m.RhcConnection{{ID: 1}, {ID: 2}},
+		},
+	}
+
+	listedRows := testutil.ToFloat64(rhcConnectionDaoListedRows.WithLabelValues("List"))
+	seriesCount := testutil.CollectAndCount(rhcConnectionDaoDuration)
+
+	_, _, err := instrumented.List(10, 0, nil)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if got := testutil.ToFloat64(rhcConnectionDaoListedRows.WithLabelValues("List")); got != listedRows+2 {
+		t.Errorf(`want "%f" listed rows, got "%f"`, listedRows+2, got)
+	}
+
+	// A missing connection records an "error" outcome.
+	id := int64(12345)
+	_, err = instrumented.GetById(&id)
+	if err == nil {
+		t.Fatalf(`want an error, got none`)
+	}
+
+	if got := testutil.CollectAndCount(rhcConnectionDaoDuration); got < seriesCount+2 {
+		t.Errorf(`want at least "%d" observed series, got "%d"`, seriesCount+2, got)
+	}
+
+}
+
+// TestPageSize tests that the omitted, zero and negative limits fall back to the default page size, and that the
+// explicit ones are kept.
+func TestPageSize(t *testing.T) {
+	testCases := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{name: "omitted", want: defaultPageSize},
+		{name: "zero", limit: 0, want: defaultPageSize},
+		{name: "negative", limit: -1, want: defaultPageSize},
+		{name: "explicit", limit: 3, want: 3},
+	}
+
+	for _, tc := range testCases {
+		if got := pageSize(tc.limit); got != tc.want {
+			t.Errorf(`[%s] want page size "%d", got "%d"`, tc.name, tc.want, got)
+		}
+	}
+}
+
+// TestRhcConnectionListDefaultPageSize tests that the lists of connections fall back to the default page size when
+// they are not given a limit, and honor the explicit ones.
+func TestRhcConnectionListDefaultPageSize(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_page_size")
+
+	originalDefaultPageSize := defaultPageSize
+	defer func() { defaultPageSize = originalDefaultPageSize }()
+	defaultPageSize = 2
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+
+	for _, limit := range []int{0, -1} {
+		rhcConnections, count, err := rhcConnectionDao.List(limit, 0, nil)
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+
+		if count <= int64(defaultPageSize) {
+			t.Fatalf(`the fixtures must have more than "%d" connections, got "%d"`, defaultPageSize, count)
+		}
+
+		if len(rhcConnections) != defaultPageSize {
+			t.Errorf(`want "%d" connections for the limit "%d", got "%d"`, defaultPageSize, limit, len(rhcConnections))
+		}
+	}
+
+	rhcConnections, _, err := rhcConnectionDao.List(1, 0, nil)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(rhcConnections) != 1 {
+		t.Errorf(`want "1" connection for an explicit limit, got "%d"`, len(rhcConnections))
+	}
+
+	sourceId := fixtures.TestSourceData[0].ID
+	_, _, err = rhcConnectionDao.ListForSource(&sourceId, 0, 0, nil)
+	if err != nil {
+		t.Errorf(`want no error listing the source's connections without a limit, got "%s"`, err)
+	}
+
+	DropSchema("rhc_connection_page_size")
+}
+
+// fakeRhcConnectionProber stores the probed requests and reports every connection as reachable.
+type fakeRhcConnectionProber struct {
+	requests []m.RhcConnectionAvailabilityCheck
+}
+
+func (f *fakeRhcConnectionProber) Probe(request m.RhcConnectionAvailabilityCheck) (bool, string, error) {
+	f.requests = append(f.requests, request)
+	return true, "reachable", nil
+}
+
+// TestNoopRhcConnectionProber tests that the default prober reports the connections as unreachable.
+func TestNoopRhcConnectionProber(t *testing.T) {
+	reachable, detail, err := noopRhcConnectionProber{}.Probe(m.RhcConnectionAvailabilityCheck{})
+	if err != nil {
+		t.Errorf(`want no error, got "%s"`, err)
+	}
+
+	if reachable || detail == "" {
+		t.Errorf(`want an unreachable connection with a detail, got "%t" and "%s"`, reachable, detail)
+	}
+}
+
+// TestProbeConnection tests that the tenant's connection is probed with the configured prober, and that the result
+// is not stored.
+func TestProbeConnection(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_probe")
+
+	originalProber := RhcProber
+	defer func() {
+		RhcProber = originalProber
+	}()
+
+	prober := &fakeRhcConnectionProber{}
+	RhcProber = prober
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnection := fixtures.TestRhcConnectionData[0]
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+
+	before, err := rhcConnectionDao.GetById(&rhcConnection.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	reachable, detail, err := rhcConnectionDao.ProbeConnection(&rhcConnection.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if !reachable || detail != "reachable" {
+		t.Errorf(`want a reachable connection, got "%t" and "%s"`, reachable, detail)
+	}
+
+	if len(prober.requests) != 1 {
+		t.Fatalf(`want one probe, got "%d"`, len(prober.requests))
+	}
+
+	request := prober.requests[0]
+	if request.RhcConnectionId != rhcConnection.ID || request.RhcId != rhcConnection.RhcId || request.TenantId != tenantId {
+		t.Errorf(`unexpected probe request "%+v"`, request)
+	}
+
+	after, err := rhcConnectionDao.GetById(&rhcConnection.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if before.AvailabilityStatus != after.AvailabilityStatus || !before.UpdatedAt.Equal(after.UpdatedAt) {
+		t.Errorf(`want the connection to be left untouched, got "%+v" after "%+v"`, after, before)
+	}
+
+	DropSchema("rhc_connection_probe")
+}
+
+// TestProbeConnectionNotFound tests that other tenants' connections are not probed.
+func TestProbeConnectionNotFound(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_probe")
+
+	originalProber := RhcProber
+	defer func() {
+		RhcProber = originalProber
+	}()
+
+	prober := &fakeRhcConnectionProber{}
+	RhcProber = prober
+
+	otherTenant := fixtures.TestTenantData[0].Id + 12345
+	_, _, err := GetRhcConnectionDao(context.Background(), &otherTenant).ProbeConnection(&fixtures.TestRhcConnectionData[0].ID)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	if len(prober.requests) != 0 {
+		t.Errorf(`want no probes, got "%d"`, len(prober.requests))
+	}
+
+	DropSchema("rhc_connection_probe")
+}
+
+// TestRhcConnectionListRhcIdFilter tests that the connections get listed, along with their related sources, for a
+// list of rhc_ids in a single query, and that the other tenants' connections are left out.
+func TestRhcConnectionListRhcIdFilter(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_rhc_id_filter")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	first := fixtures.TestRhcConnectionData[0]
+	third := fixtures.TestRhcConnectionData[2]
+
+	filters := []util.Filter{{Name: "rhc_id", Value: []string{first.RhcId, third.RhcId, "unknown"}}}
+
+	rhcConnections, count, err := GetRhcConnectionDao(context.Background(), &tenantId).List(10, 0, filters)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 2 || len(rhcConnections) != 2 {
+		t.Fatalf(`want two connections, got "%d"`, count)
+	}
+
+	if rhcConnections[0].ID != first.ID || rhcConnections[1].ID != third.ID {
+		t.Errorf(`want connections "%d" and "%d", got "%d" and "%d"`, first.ID, third.ID, rhcConnections[0].ID, rhcConnections[1].ID)
+	}
+
+	if len(rhcConnections[0].Sources) == 0 {
+		t.Errorf(`want the related sources of connection "%d", got none`, first.ID)
+	}
+
+	otherTenant := fixtures.TestTenantData[1].Id
+	_, count, err = GetRhcConnectionDao(context.Background(), &otherTenant).List(10, 0, filters)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 0 {
+		t.Errorf(`want no connections for another tenant, got "%d"`, count)
+	}
+
+	DropSchema("rhc_connection_rhc_id_filter")
+}
+
+// TestEscapeLikePattern tests that the wildcards and the backslashes are escaped.
+func TestEscapeLikePattern(t *testing.T) {
+	testCases := []struct {
+		value string
+		want  string
+	}{
+		{value: "Source1", want: "Source1"},
+		{value: "100%", want: `100\%`},
+		{value: "my_source", want: `my\_source`},
+		{value: `back\slash`, want: `back\\slash`},
+	}
+
+	for _, tc := range testCases {
+		if got := escapeLikePattern(tc.value); got != tc.want {
+			t.Errorf(`want "%s" for "%s", got "%s"`, tc.want, tc.value, got)
+		}
+	}
+}
+
+// TestRhcConnectionListBySourceName tests that the connections linked to the sources whose names start with the
+// prefix are returned, regardless of the case, along with all the sources they're linked to.
+func TestRhcConnectionListBySourceName(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_source_name")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+
+	rhcConnections, count, err := rhcConnectionDao.ListBySourceName("source1", 10, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	// The first source is linked to the first two connections.
+	if count != 2 || len(rhcConnections) != 2 {
+		t.Fatalf(`want "2" connections, got "%d" out of "%d"`, len(rhcConnections), count)
+	}
+
+	if rhcConnections[0].ID != fixtures.TestRhcConnectionData[0].ID || rhcConnections[1].ID != fixtures.TestRhcConnectionData[1].ID {
+		t.Errorf(`want the connections ordered by their IDs, got "%d" and "%d"`, rhcConnections[0].ID, rhcConnections[1].ID)
+	}
+
+	// The first connection is also linked to the second source, which must not be filtered out of the aggregation.
+	if len(rhcConnections[0].Sources) != 2 {
+		t.Errorf(`want the first connection to have "2" sources, got "%d"`, len(rhcConnections[0].Sources))
+	}
+
+	// The wildcards are matched literally.
+	_, count, err = rhcConnectionDao.ListBySourceName("Source_", 10, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 0 {
+		t.Errorf(`want no connections for an escaped wildcard, got "%d"`, count)
+	}
+
+	_, _, err = rhcConnectionDao.ListBySourceName(" ", 10, 0)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error for an empty prefix, got "%v"`, err)
+	}
+
+	otherTenant := tenantId + 12345
+	_, count, err = GetRhcConnectionDao(context.Background(), &otherTenant).ListBySourceName("source1", 10, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 0 {
+		t.Errorf(`want no connections for another tenant, got "%d"`, count)
+	}
+
+	DropSchema("rhc_connection_source_name")
+}
+
+// TestSummaryBySource tests that the connections are counted for each of the tenant's sources they're linked to, and
+// that the summary is served from the cache afterwards.
+func TestSummaryBySource(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_source_summary")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionSourceSummaryCache.Delete(tenantId)
+
+	summary, err := GetRhcConnectionDao(context.Background(), &tenantId).SummaryBySource(tenantId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	wantCounts := make(map[int64]int)
+	wantAvailable := make(map[int64]int)
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.TenantId != tenantId {
+			continue
+		}
+
+		wantCounts[link.SourceId]++
+		for _, rhcConnection := range fixtures.TestRhcConnectionData {
+			if rhcConnection.ID == link.RhcConnectionId && rhcConnection.AvailabilityStatus == m.Available {
+				wantAvailable[link.SourceId]++
+			}
+		}
+	}
+
+	if len(summary) != len(wantCounts) {
+		t.Fatalf(`want "%d" summaries, got "%d"`, len(wantCounts), len(summary))
+	}
+
+	for _, s := range summary {
+		if s.ConnectionCount != wantCounts[s.SourceId] || s.AvailableCount != wantAvailable[s.SourceId] {
+			t.Errorf(`want "%d" connections and "%d" available for source "%d", got "%d" and "%d"`, wantCounts[s.SourceId], wantAvailable[s.SourceId], s.SourceId, s.ConnectionCount, s.AvailableCount)
+		}
+	}
+
+	// Linking another connection doesn't change the summary until the cache expires.
+	_, _, err = GetRhcConnectionDao(context.Background(), &tenantId).CreateOrLink("summary-rhc-id", fixtures.TestSourceData[0].ID)
+	if err != nil {
+		t.Fatalf(`could not link the connection: %s`, err)
+	}
+
+	cached, err := GetRhcConnectionDao(context.Background(), &tenantId).SummaryBySource(tenantId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(cached) != len(summary) || (len(cached) > 0 && cached[0] != summary[0]) {
+		t.Errorf(`want the cached summary "%+v", got "%+v"`, summary, cached)
+	}
+
+	rhcConnectionSourceSummaryCache.Delete(tenantId)
+	DropSchema("rhc_connection_source_summary")
+}
+
+// TestRhcConnectionStatusHistory tests that the status changes get recorded along with who made them, and that the
+// updates which don't change the status don't record anything.
+func TestRhcConnectionStatusHistory(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_status_history")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+	rhcConnection := fixtures.TestRhcConnectionData[0]
+
+	updates := []m.RhcConnection{
+		{ID: rhcConnection.ID, AvailabilityStatus: m.Unavailable, UpdatedBy: "jdoe"},
+		{ID: rhcConnection.ID, AvailabilityStatus: m.Unavailable, UpdatedBy: "jdoe"},
+		{ID: rhcConnection.ID, Extra: []byte(`{"a": "b"}`)},
+	}
+
+	for _, update := range updates {
+		_, err := rhcConnectionDao.Update(&update)
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+	}
+
+	events, err := rhcConnectionDao.ListStatusHistory(rhcConnection.ID, tenantId, 10)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf(`want a single status event, got "%d"`, len(events))
+	}
+
+	if events[0].PreviousStatus != rhcConnection.AvailabilityStatus || events[0].NewStatus != m.Unavailable || events[0].ChangedBy != "jdoe" {
+		t.Errorf(`want a change from "%s" to "%s" by "jdoe", got "%+v"`, rhcConnection.AvailabilityStatus, m.Unavailable, events[0])
+	}
+
+	DropSchema("rhc_connection_status_history")
+}
+
+// TestRhcConnectionStatusHistoryInvalid tests that invalid transitions are rejected, and that other tenants' histories
+// cannot be listed.
+func TestRhcConnectionStatusHistoryInvalid(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_status_history")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+	rhcConnection := fixtures.TestRhcConnectionData[0]
+
+	_, err := rhcConnectionDao.Update(&m.RhcConnection{ID: rhcConnection.ID, AvailabilityStatus: "bogus"})
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	otherTenant := tenantId + 12345
+	_, err = rhcConnectionDao.ListStatusHistory(rhcConnection.ID, otherTenant, 10)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("rhc_connection_status_history")
+}
+
+// TestRhcConnectionStatusHistoryCap tests that the oldest events get pruned once the history exceeds its cap.
+func TestRhcConnectionStatusHistoryCap(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_status_history")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnection := fixtures.TestRhcConnectionData[0]
+
+	oldest := time.Now().Add(-time.Hour)
+	events := make([]m.RhcConnectionStatusEvent, rhcConnectionStatusHistoryCap)
+	for i := range events {
+		events[i] = m.RhcConnectionStatusEvent{
+			RhcConnectionId: rhcConnection.ID,
+			PreviousStatus:  m.Unavailable,
+			NewStatus:       m.Available,
+			ChangedAt:       oldest.Add(time.Duration(i) * time.Second),
+		}
+	}
+
+	err := DB.Create(&events).Error
+	if err != nil {
+		t.Fatalf(`could not create the status events: %s`, err)
+	}
+
+	_, err = GetRhcConnectionDao(context.Background(), &tenantId).Update(&m.RhcConnection{ID: rhcConnection.ID, AvailabilityStatus: m.Unavailable})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	history, err := GetRhcConnectionDao(context.Background(), &tenantId).ListStatusHistory(rhcConnection.ID, tenantId, rhcConnectionStatusHistoryCap*2)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(history) != rhcConnectionStatusHistoryCap {
+		t.Errorf(`want "%d" events, got "%d"`, rhcConnectionStatusHistoryCap, len(history))
+	}
+
+	for _, event := range history {
+		if event.ID == events[0].ID {
+			t.Errorf(`want the oldest event "%d" to be pruned, got it`, events[0].ID)
+		}
+	}
+
+	if history[0].NewStatus != m.Unavailable {
+		t.Errorf(`want the latest event to be first, got "%+v"`, history[0])
+	}
+
+	DropSchema("rhc_connection_status_history")
+}
+
+// TestValidateTags tests that the tags' keys and values must follow the expected format.
+func TestValidateTags(t *testing.T) {
+	validTags := map[string]string{"env": "prod", "team.name": "networking", "example.org/owner": "ops_team-1"}
+	if err := validateTags(validTags); err != nil {
+		t.Errorf(`want no error for "%v", got "%s"`, validTags, err)
+	}
+
+	invalidTags := []map[string]string{
+		{"": "prod"},
+		{"-env": "prod"},
+		{"env": ""},
+		{"env": "prod env"},
+		{"env:name": "prod"},
+		{strings.Repeat("k", 64): "prod"},
+		{"env": strings.Repeat("v", 64)},
+	}
+
+	for _, tags := range invalidTags {
+		if err := validateTags(tags); err == nil {
+			t.Errorf(`want an error for "%v", got none`, tags)
+		}
+	}
+}
+
+// TestRhcConnectionTags tests that the tags get added to and removed from the connections, and that the connections
+// can be filtered by them.
+func TestRhcConnectionTags(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_tags")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+	id := fixtures.TestRhcConnectionData[0].ID
+
+	_, err := rhcConnectionDao.AddTags(id, map[string]string{"env": "prod", "team": "networking"})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	rhcConnection, err := rhcConnectionDao.AddTags(id, map[string]string{"env": "stage"})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	var tags map[string]string
+	err = json.Unmarshal(rhcConnection.Tags, &tags)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the tags: %s`, err)
+	}
+
+	wantTags := map[string]string{"env": "stage", "team": "networking"}
+	if !reflect.DeepEqual(wantTags, tags) {
+		t.Errorf(`want tags "%v", got "%v"`, wantTags, tags)
+	}
+
+	rhcConnections, count, err := rhcConnectionDao.List(10, 0, []util.Filter{{Name: "tags", Value: []string{"env:stage", "team:networking"}}})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 1 || len(rhcConnections) != 1 || rhcConnections[0].ID != id {
+		t.Errorf(`want only the connection "%d", got "%v"`, id, rhcConnections)
+	}
+
+	rhcConnection, err = rhcConnectionDao.RemoveTags(id, []string{"env"})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	tags = nil
+	err = json.Unmarshal(rhcConnection.Tags, &tags)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the tags: %s`, err)
+	}
+
+	wantTags = map[string]string{"team": "networking"}
+	if !reflect.DeepEqual(wantTags, tags) {
+		t.Errorf(`want tags "%v", got "%v"`, wantTags, tags)
+	}
+
+	_, count, err = rhcConnectionDao.List(10, 0, []util.Filter{{Name: "tags", Value: []string{"env:stage"}}})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 0 {
+		t.Errorf(`want no connections tagged with "env:stage", got "%d"`, count)
+	}
+
+	DropSchema("rhc_connection_tags")
+}
+
+// TestRhcConnectionTagsInvalid tests that invalid tags and other tenants' connections are rejected.
+func TestRhcConnectionTagsInvalid(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_tags")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	id := fixtures.TestRhcConnectionData[0].ID
+
+	_, err := GetRhcConnectionDao(context.Background(), &tenantId).AddTags(id, map[string]string{"env": "prod env"})
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	_, err = GetRhcConnectionDao(context.Background(), &tenantId).RemoveTags(id, nil)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	otherTenant := tenantId + 12345
+	_, err = GetRhcConnectionDao(context.Background(), &otherTenant).AddTags(id, map[string]string{"env": "prod"})
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("rhc_connection_tags")
+}
+
+// TestTopologyEdges tests that every link of the tenant is returned as an edge, ordered by the connections and then by
+// the sources, and that the edges are paginated.
+func TestTopologyEdges(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_topology")
+
+	tenantId := fixtures.TestTenantData[0].Id
+
+	var wantCount int64
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.TenantId == tenantId {
+			wantCount++
+		}
+	}
+
+	edges, count, err := GetRhcConnectionDao(context.Background(), &tenantId).TopologyEdges(100, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != wantCount || int64(len(edges)) != wantCount {
+		t.Fatalf(`want "%d" edges, got "%d" with a count of "%d"`, wantCount, len(edges), count)
+	}
+
+	for i := 1; i < len(edges); i++ {
+		previous, current := edges[i-1], edges[i]
+		if previous.RhcConnectionId > current.RhcConnectionId || (previous.RhcConnectionId == current.RhcConnectionId && previous.SourceId >= current.SourceId) {
+			t.Errorf(`want the edges ordered, got "%+v" before "%+v"`, previous, current)
+		}
+	}
+
+	page, count, err := GetRhcConnectionDao(context.Background(), &tenantId).TopologyEdges(1, 1)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != wantCount || len(page) != 1 || page[0] != edges[1] {
+		t.Errorf(`want the second edge "%+v", got "%+v" with a count of "%d"`, edges[1], page, count)
+	}
+
+	DropSchema("rhc_connection_topology")
+}
+
+// createOtherTenantSource creates a source for the second tenant of the fixtures.
+func createOtherTenantSource(t *testing.T) m.Source {
+	source := m.Source{
+		Name:         "other tenant's source",
+		SourceTypeID: fixtures.TestSourceData[0].SourceTypeID,
+		TenantID:     fixtures.TestTenantData[1].Id,
+	}
+
+	err := DB.Create(&source).Error
+	if err != nil {
+		t.Fatalf(`could not create the other tenant's source: %s`, err)
+	}
+
+	return source
+}
+
+// TestCreateSameRhcIdDifferentTenants tests that when two tenants concurrently register the same rhc_id, each of
+// them gets its own connection.
+func TestCreateSameRhcIdDifferentTenants(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_unique")
+
+	sources := []m.Source{fixtures.TestSourceData[0], createOtherTenantSource(t)}
+	rhcConnections := make([]*m.RhcConnection, len(sources))
+	errs := make([]error, len(sources))
+
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+
+		go func(i int, source m.Source) {
+			defer wg.Done()
+
+			rhcConnection := &m.RhcConnection{
+				RhcId:   "shared-rhc-id",
+				Sources: []m.Source{{ID: source.ID}},
+			}
+
+			rhcConnections[i], errs[i] = GetRhcConnectionDao(context.Background(), &source.TenantID).Create(rhcConnection)
+		}(i, source)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf(`want no error for tenant "%d", got "%s"`, sources[i].TenantID, err)
+		}
+	}
+
+	if rhcConnections[0].ID == rhcConnections[1].ID {
+		t.Errorf(`want two distinct connections, got "%d" for both tenants`, rhcConnections[0].ID)
+	}
+
+	for i, rhcConnection := range rhcConnections {
+		if rhcConnection.TenantId != sources[i].TenantID {
+			t.Errorf(`want the connection to belong to tenant "%d", got "%d"`, sources[i].TenantID, rhcConnection.TenantId)
+		}
+	}
+
+	var count int64
+	err := DB.Model(&m.RhcConnection{}).Where("rhc_id = ?", "shared-rhc-id").Count(&count).Error
+	if err != nil {
+		t.Fatalf(`could not count the connections: %s`, err)
+	}
+
+	if count != 2 {
+		t.Errorf(`want two connections with the rhc_id, got "%d"`, count)
+	}
+
+	DropSchema("rhc_connection_unique")
+}
+
+// TestCreateOrLinkSameRhcIdDifferentTenants tests that linking an rhc_id which another tenant has already registered
+// creates a new connection instead of reusing the other tenant's one.
+func TestCreateOrLinkSameRhcIdDifferentTenants(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_unique")
+
+	source := createOtherTenantSource(t)
+	existing := fixtures.TestRhcConnectionData[0]
+
+	rhcConnection, linked, err := GetRhcConnectionDao(context.Background(), &source.TenantID).CreateOrLink(existing.RhcId, source.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if !linked {
+		t.Errorf(`want a new link, got none`)
+	}
+
+	if rhcConnection.ID == existing.ID {
+		t.Errorf(`want a new connection, got the other tenant's connection "%d"`, existing.ID)
+	}
+
+	if count := countSourceRhcConnections(t, existing.ID, source.ID); count != 0 {
+		t.Errorf(`want the other tenant's connection to be left unlinked, got "%d" links`, count)
+	}
+
+	DropSchema("rhc_connection_unique")
+}
+
+// rhcConnectionExists returns whether the connection with the given ID exists.
+func rhcConnectionExists(t *testing.T, rhcConnectionId int64) bool {
+	var count int64
+	err := DB.
+		Model(&m.RhcConnection{}).
+		Where("id = ?", rhcConnectionId).
+		Count(&count).
+		Error
+
+	if err != nil {
+		t.Fatalf(`could not count the connections: %s`, err)
+	}
+
+	return count > 0
+}
+
+// TestUnlinkFromSourceKeepsSharedConnection tests that unlinking a connection which is still linked to other sources
+// only removes the link.
+func TestUnlinkFromSourceKeepsSharedConnection(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_unlink")
+
+	// The connection is linked to the first and the second sources.
+	link := fixtures.TestSourceRhcConnectionData[0]
+
+	rhcConnection, deleted, err := GetRhcConnectionDao(context.Background(), &link.TenantId).UnlinkFromSource(link.RhcConnectionId, link.SourceId, link.TenantId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if deleted {
+		t.Errorf(`want the connection not to be reported as deleted, got it deleted`)
+	}
+
+	// The connection is still linked to the second source.
+	if len(rhcConnection.Sources) != 1 || rhcConnection.Sources[0].ID != fixtures.TestSourceData[1].ID {
+		t.Errorf(`want the connection to keep source "%d", got "%v"`, fixtures.TestSourceData[1].ID, rhcConnection.SourceIDs())
+	}
+
+	if count := countSourceRhcConnections(t, link.RhcConnectionId, link.SourceId); count != 0 {
+		t.Errorf(`want the link to be removed, got "%d" links`, count)
+	}
+
+	if !rhcConnectionExists(t, link.RhcConnectionId) {
+		t.Errorf(`want the connection to still exist, since it is linked to other sources`)
+	}
+
+	DropSchema("rhc_connection_unlink")
+}
+
+// TestUnlinkFromSourceDeletesOrphan tests that unlinking a connection from its last source deletes the connection.
+func TestUnlinkFromSourceDeletesOrphan(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_unlink")
+
+	// The connection is only linked to the second source.
+	link := fixtures.TestSourceRhcConnectionData[3]
+
+	// An orphan connection which has nothing to do with the unlinked one.
+	orphan := m.RhcConnection{RhcId: "unrelated-orphan", TenantId: link.TenantId}
+	err := DB.Create(&orphan).Error
+	if err != nil {
+		t.Fatalf(`could not create the orphan connection: %s`, err)
+	}
+
+	rhcConnection, deleted, err := GetRhcConnectionDao(context.Background(), &link.TenantId).UnlinkFromSource(link.RhcConnectionId, link.SourceId, link.TenantId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if !deleted || rhcConnection.ID != link.RhcConnectionId {
+		t.Errorf(`want connection "%d" reported as deleted, got "%d" with deleted "%t"`, link.RhcConnectionId, rhcConnection.ID, deleted)
+	}
+
+	// Only the unlinked connection is cleaned up, even if there are other orphan connections.
+	if !rhcConnectionExists(t, orphan.ID) {
+		t.Errorf(`want the unrelated orphan connection "%d" to be left alone`, orphan.ID)
+	}
+
+	if rhcConnectionExists(t, link.RhcConnectionId) {
+		t.Errorf(`want the orphan connection to be deleted`)
+	}
+
+	DropSchema("rhc_connection_unlink")
+}
+
+// TestUnlinkFromSourceNotFound tests that a "not found" error is returned when the link doesn't exist or belongs to
+// another tenant.
+func TestUnlinkFromSourceNotFound(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_unlink")
+
+	link := fixtures.TestSourceRhcConnectionData[0]
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &link.TenantId)
+
+	_, _, err := rhcConnectionDao.UnlinkFromSource(fixtures.TestRhcConnectionData[2].ID, fixtures.TestSourceData[0].ID, link.TenantId)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error for a missing link, got "%v"`, err)
+	}
+
+	_, _, err = rhcConnectionDao.UnlinkFromSource(link.RhcConnectionId, link.SourceId, link.TenantId+12345)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error for another tenant's link, got "%v"`, err)
+	}
+
+	if count := countSourceRhcConnections(t, link.RhcConnectionId, link.SourceId); count != 1 {
+		t.Errorf(`want the link to be untouched, got "%d" links`, count)
+	}
+
+	DropSchema("rhc_connection_unlink")
+}
+
+// TestMutableRhcConnectionFields tests that the immutable fields of the connection are never copied to the update.
+func TestMutableRhcConnectionFields(t *testing.T) {
+	rhcConnection := m.RhcConnection{
+		ID:                 5,
+		RhcId:              "hijacked",
+		TenantId:           12345,
+		Extra:              datatypes.JSON(`{"a": "b"}`),
+		AvailabilityStatus: m.Available,
+		UpdatedBy:          "someone",
+	}
+
+	got := mutableRhcConnectionFields(&rhcConnection)
+
+	if got.ID != rhcConnection.ID {
+		t.Errorf(`want the ID "%d" to identify the row, got "%d"`, rhcConnection.ID, got.ID)
+	}
+
+	if got.RhcId != "" || got.TenantId != 0 {
+		t.Errorf(`want no "rhc_id" nor tenant, got "%s" and "%d"`, got.RhcId, got.TenantId)
+	}
+
+	if string(got.Extra) != string(rhcConnection.Extra) || got.AvailabilityStatus != m.Available || got.UpdatedBy != "someone" {
+		t.Errorf(`want the mutable fields to be copied, got "%+v"`, got)
+	}
+}
+
+// TestRhcConnectionUpdateRowsAffected tests that the number of updated rows is returned, and that it is zero for
+// connections which don't exist or which belong to other tenants.
+func TestRhcConnectionUpdateRowsAffected(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_update")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnection := fixtures.TestRhcConnectionData[0]
+	rhcConnection.AvailabilityStatus = m.Unavailable
+
+	rowsAffected, err := GetRhcConnectionDao(context.Background(), &tenantId).Update(&rhcConnection)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if rowsAffected != 1 {
+		t.Errorf(`want "1" updated row, got "%d"`, rowsAffected)
+	}
+
+	missing := m.RhcConnection{ID: 12345, AvailabilityStatus: m.Unavailable}
+	rowsAffected, err = GetRhcConnectionDao(context.Background(), &tenantId).Update(&missing)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if rowsAffected != 0 {
+		t.Errorf(`want "0" updated rows for a missing connection, got "%d"`, rowsAffected)
+	}
+
+	otherTenant := tenantId + 12345
+	rowsAffected, err = GetRhcConnectionDao(context.Background(), &otherTenant).Update(&rhcConnection)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if rowsAffected != 0 {
+		t.Errorf(`want "0" updated rows for another tenant's connection, got "%d"`, rowsAffected)
+	}
+
+	DropSchema("rhc_connection_update")
+}
+
+// TestRhcConnectionUpdateImmutableFields tests that attempting to change the "rhc_id" or the tenant of a connection
+// is rejected, and that neither of them gets modified.
+func TestRhcConnectionUpdateImmutableFields(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_update")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+	original := fixtures.TestRhcConnectionData[0]
+
+	hijackedRhcId := original
+	hijackedRhcId.RhcId = "hijacked-rhc-id"
+
+	_, err := rhcConnectionDao.Update(&hijackedRhcId)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error when changing the "rhc_id", got "%v"`, err)
+	}
+
+	hijackedTenant := original
+	hijackedTenant.TenantId = tenantId + 12345
+
+	_, err = rhcConnectionDao.Update(&hijackedTenant)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error when changing the tenant, got "%v"`, err)
+	}
+
+	stored, err := rhcConnectionDao.GetById(&original.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if stored.RhcId != original.RhcId || stored.TenantId != original.TenantId {
+		t.Errorf(`want "rhc_id" "%s" and tenant "%d" to be untouched, got "%s" and "%d"`, original.RhcId, original.TenantId, stored.RhcId, stored.TenantId)
+	}
+
+	DropSchema("rhc_connection_update")
+}
+
+// TestListWithSourceAvailability tests that the connections are listed along with the worst availability status and
+// the status counts of their linked sources.
+func TestListWithSourceAvailability(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_with_availability")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	sourceStatuses := map[int64]string{
+		fixtures.TestSourceData[0].ID: m.Available,
+		fixtures.TestSourceData[1].ID: m.Unavailable,
+	}
+
+	for sourceId, status := range sourceStatuses {
+		err := DB.Model(&m.Source{}).Where("id = ?", sourceId).Update("availability_status", status).Error
+		if err != nil {
+			t.Fatalf(`could not update the source's status: %s`, err)
+		}
+	}
+
+	// Compute the expected aggregations from the fixtures' links.
+	wantCounts := make(map[int64]map[string]int64)
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if wantCounts[link.RhcConnectionId] == nil {
+			wantCounts[link.RhcConnectionId] = make(map[string]int64)
+		}
+
+		wantCounts[link.RhcConnectionId][sourceStatuses[link.SourceId]]++
+	}
+
+	rhcConnections, count, err := GetRhcConnectionDao(context.Background(), &tenantId).ListWithSourceAvailability(100, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if int(count) != len(wantCounts) || len(rhcConnections) != len(wantCounts) {
+		t.Errorf(`want "%d" connections, got "%d" with a count of "%d"`, len(wantCounts), len(rhcConnections), count)
+	}
+
+	for _, rhcConnection := range rhcConnections {
+		want := wantCounts[rhcConnection.ID]
+
+		for status, wantCount := range want {
+			if rhcConnection.SourcesAvailabilityCounts[status] != wantCount {
+				t.Errorf(`[connection "%d"] want "%d" sources with status "%s", got "%d"`, rhcConnection.ID, wantCount, status, rhcConnection.SourcesAvailabilityCounts[status])
+			}
+		}
+
+		wantWorst := m.Available
+		if want[m.Unavailable] > 0 {
+			wantWorst = m.Unavailable
+		} else if want[""] > 0 {
+			wantWorst = ""
+		}
+
+		if rhcConnection.SourcesAvailabilityStatus != wantWorst {
+			t.Errorf(`[connection "%d"] want worst status "%s", got "%s"`, rhcConnection.ID, wantWorst, rhcConnection.SourcesAvailabilityStatus)
+		}
+	}
+
+	DropSchema("rhc_connection_with_availability")
+}
+
+// TestListWithSources tests that the connections are listed along with their full sources.
+func TestListWithSources(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("rhc_connection_with_sources")
+
+	tenantId := fixtures.TestTenantData[0].Id
+
+	wantSourceIds := make(map[int64]map[int64]bool)
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if wantSourceIds[link.RhcConnectionId] == nil {
+			wantSourceIds[link.RhcConnectionId] = make(map[int64]bool)
+		}
+
+		wantSourceIds[link.RhcConnectionId][link.SourceId] = true
+	}
+
+	sourceNames := make(map[int64]string)
+	for _, source := range fixtures.TestSourceData {
+		sourceNames[source.ID] = source.Name
+	}
+
+	rhcConnections, count, err := GetRhcConnectionDao(context.Background(), &tenantId).ListWithSources(context.Background(), 100, 0, []util.Filter{})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if int(count) != len(wantSourceIds) || len(rhcConnections) != len(wantSourceIds) {
+		t.Errorf(`want "%d" connections, got "%d" with a count of "%d"`, len(wantSourceIds), len(rhcConnections), count)
+	}
+
+	for _, rhcConnection := range rhcConnections {
+		want := wantSourceIds[rhcConnection.ID]
+		if len(rhcConnection.Sources) != len(want) {
+			t.Errorf(`[connection "%d"] want "%d" sources, got "%d"`, rhcConnection.ID, len(want), len(rhcConnection.Sources))
+		}
+
+		for _, source := range rhcConnection.Sources {
+			if !want[source.ID] {
+				t.Errorf(`[connection "%d"] unexpected source "%d"`, rhcConnection.ID, source.ID)
+			}
+
+			// The sources must be the full objects, not just their IDs.
+			if source.Name != sourceNames[source.ID] {
+				t.Errorf(`[connection "%d"] want source name "%s", got "%s"`, rhcConnection.ID, sourceNames[source.ID], source.Name)
+			}
+		}
+	}
+
+	DropSchema("rhc_connection_with_sources")
+}
+
+// listWithSourcesJoin is the JOIN based alternative to "ListWithSources", which fetches the connections and their
+// sources in a single query. It is only used to benchmark both approaches against each other.
+func listWithSourcesJoin(tenantId int64, limit, offset int) ([]m.RhcConnectionWithSources, error) {
+	page := DB.
+		Model(&m.SourceRhcConnection{}).
+		Select(`DISTINCT "rhc_connection_id"`).
+		Where(`"tenant_id" = ?`, tenantId).
+		Order(`"rhc_connection_id"`).
+		Limit(limit).
+		Offset(offset)
+
+	var rows []struct {
+		RhcConnectionId int64
+		RhcId           string
+		m.Source
+	}
+
+	err := DB.
+		Table(`"rhc_connections"`).
+		Select(`"rhc_connections"."id" AS "rhc_connection_id", "rhc_connections"."rhc_id", "sources".*`).
+		Joins(`INNER JOIN "source_rhc_connections" AS "jt" ON "jt"."rhc_connection_id" = "rhc_connections"."id"`).
+		Joins(`INNER JOIN "sources" ON "sources"."id" = "jt"."source_id"`).
+		Where(`"jt"."tenant_id" = ?`, tenantId).
+		Where(`"rhc_connections"."id" IN (?)`, page).
+		Order(`"rhc_connections"."id"`).
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	rhcConnections := make([]m.RhcConnectionWithSources, 0)
+	for _, row := range rows {
+		last := len(rhcConnections) - 1
+		if last < 0 || rhcConnections[last].ID != row.RhcConnectionId {
+			rhcConnections = append(rhcConnections, m.RhcConnectionWithSources{RhcConnection: m.RhcConnection{ID: row.RhcConnectionId, RhcId: row.RhcId}})
+			last++
+		}
+
+		rhcConnections[last].Sources = append(rhcConnections[last].Sources, row.Source)
+	}
+
+	return rhcConnections, nil
+}
+
+// setUpRhcConnectionsWithSources creates a thousand connections, each one linked to two sources.
+func setUpRhcConnectionsWithSources(b *testing.B) int64 {
+	tenantId := fixtures.TestTenantData[0].Id
+
+	sources := []m.Source{fixtures.TestSourceData[0], fixtures.TestSourceData[1]}
+	for i := 0; i < 1000; i++ {
+		rhcConnection := m.RhcConnection{RhcId: fmt.Sprintf("with-sources-%d", i)}
+		if err := DB.Create(&rhcConnection).Error; err != nil {
+			b.Fatalf(`could not create the connection: %s`, err)
+		}
+
+		for _, source := range sources {
+			link := m.SourceRhcConnection{SourceId: source.ID, RhcConnectionId: rhcConnection.ID, TenantId: tenantId}
+			if err := DB.Create(&link).Error; err != nil {
+				b.Fatalf(`could not link the connection: %s`, err)
+			}
+		}
+	}
+
+	return tenantId
+}
+
+// BenchmarkListWithSources benchmarks listing a thousand connections with their sources in two phases. Compare it
+// with "BenchmarkListWithSourcesJoin" by running "go test ./dao -run ^$ -bench WithSources -integration".
+func BenchmarkListWithSources(b *testing.B) {
+	if !flags.Integration {
+		b.Skip("Skipping integration benchmark")
+	}
+
+	SwitchSchema("rhc_connection_with_sources")
+	tenantId := setUpRhcConnectionsWithSources(b)
+	rhcConnectionDao := GetRhcConnectionDao(context.Background(), &tenantId)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, err := rhcConnectionDao.ListWithSources(context.Background(), 1000, 0, []util.Filter{})
+		if err != nil {
+			b.Fatalf(`want nil error, got "%s"`, err)
+		}
+	}
+	b.StopTimer()
+
+	DropSchema("rhc_connection_with_sources")
+}
+
+// BenchmarkListWithSourcesJoin benchmarks listing the same connections as "BenchmarkListWithSources" with a single
+// JOIN query.
+func BenchmarkListWithSourcesJoin(b *testing.B) {
+	if !flags.Integration {
+		b.Skip("Skipping integration benchmark")
+	}
+
+	SwitchSchema("rhc_connection_with_sources")
+	tenantId := setUpRhcConnectionsWithSources(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := listWithSourcesJoin(tenantId, 1000, 0)
+		if err != nil {
+			b.Fatalf(`want nil error, got "%s"`, err)
+		}
+	}
+	b.StopTimer()
+
+	DropSchema("rhc_connection_with_sources")
+}
+
+// TestErrRhcIdAlreadyRegistered tests that the conflict error keeps the rhc_id in its message, and that the logged
+// line masks it when the sensitive fields are configured to be masked.
+func TestErrRhcIdAlreadyRegistered(t *testing.T) {
+	backupLog, backupMask := logging.Log, conf.MaskSensitiveFields
+	defer func() { logging.Log, conf.MaskSensitiveFields = backupLog, backupMask }()
+
+	logger, hook := logrustest.NewNullLogger()
+	logging.Log = logger
+	conf.MaskSensitiveFields = true
+
+	rhcId := "a0b1c2d3-rhc-connector"
+	err := errRhcIdAlreadyRegistered(rhcId)
+
+	if !errors.Is(err, util.ErrConflictEmpty) {
+		t.Fatalf(`want a conflict error, got "%v"`, err)
+	}
+
+	if !strings.Contains(err.Error(), rhcId) {
+		t.Errorf(`want the error message to contain the rhc_id "%s", got "%s"`, rhcId, err)
+	}
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatalf(`want a log entry, got none`)
+	}
+
+	if strings.Contains(entry.Message, rhcId) {
+		t.Errorf(`want the rhc_id masked in the logs, got "%s"`, entry.Message)
+	}
+
+	if !strings.Contains(entry.Message, logging.MaskValue(rhcId)) {
+		t.Errorf(`want the masked rhc_id "%s" in the logs, got "%s"`, logging.MaskValue(rhcId), entry.Message)
+	}
+}
@@ -0,0 +1,334 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// rhcConnectionDaoDuration exports how long the rhcConnection DAO methods take, by method and outcome. The tenants are
+// purposely left out of the labels to keep their cardinality bounded.
+var rhcConnectionDaoDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "sources_rhc_connection_dao_duration_seconds",
+	Help:    "Duration of the rhcConnection DAO operations",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "outcome"})
+
+// rhcConnectionDaoListedRows exports the number of connections returned by the rhcConnection DAO list methods.
+var rhcConnectionDaoListedRows = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sources_rhc_connection_dao_listed_rows_total",
+	Help: "Number of rows returned by the rhcConnection DAO list operations",
+}, []string{"method"})
+
+// observeRhcConnectionDao records the duration and the outcome of a DAO method which started at the given time.
+func observeRhcConnectionDao(method string, start time.Time, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+
+	rhcConnectionDaoDuration.WithLabelValues(method, outcome).Observe(time.Since(start).Seconds())
+}
+
+// observeRhcConnectionDaoList records the same as "observeRhcConnectionDao", along with the number of returned rows.
+func observeRhcConnectionDaoList(method string, start time.Time, rows int, err error) {
+	observeRhcConnectionDao(method, start, err)
+
+	if err == nil {
+		rhcConnectionDaoListedRows.WithLabelValues(method).Add(float64(rows))
+	}
+}
+
+// instrumentedRhcConnectionDao wraps an rhcConnection DAO to export the metrics of every one of its methods.
+type instrumentedRhcConnectionDao struct {
+	dao RhcConnectionDao
+}
+
+func (i *instrumentedRhcConnectionDao) List(limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error) {
+	start := time.Now()
+	rhcConnections, count, err := i.dao.List(limit, offset, filters)
+	observeRhcConnectionDaoList("List", start, len(rhcConnections), err)
+
+	return rhcConnections, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListWithLastModified(limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, *time.Time, error) {
+	start := time.Now()
+	rhcConnections, count, lastModified, err := i.dao.ListWithLastModified(limit, offset, filters)
+	observeRhcConnectionDaoList("ListWithLastModified", start, len(rhcConnections), err)
+
+	return rhcConnections, count, lastModified, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListByStatus(status string, limit, offset int) ([]m.RhcConnection, int64, error) {
+	start := time.Now()
+	rhcConnections, count, err := i.dao.ListByStatus(status, limit, offset)
+	observeRhcConnectionDaoList("ListByStatus", start, len(rhcConnections), err)
+
+	return rhcConnections, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) TopologyEdges(limit, offset int) ([]m.RhcConnectionTopologyEdge, int64, error) {
+	start := time.Now()
+	edges, count, err := i.dao.TopologyEdges(limit, offset)
+	observeRhcConnectionDaoList("TopologyEdges", start, len(edges), err)
+
+	return edges, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) GetById(id *int64) (*m.RhcConnection, error) {
+	start := time.Now()
+	rhcConnection, err := i.dao.GetById(id)
+	observeRhcConnectionDao("GetById", start, err)
+
+	return rhcConnection, err
+}
+
+func (i *instrumentedRhcConnectionDao) GetByIds(ids []int64) ([]m.RhcConnection, error) {
+	start := time.Now()
+	rhcConnections, err := i.dao.GetByIds(ids)
+	observeRhcConnectionDaoList("GetByIds", start, len(rhcConnections), err)
+
+	return rhcConnections, err
+}
+
+func (i *instrumentedRhcConnectionDao) LatestPerSource(sourceIds []int64) (map[int64]*m.RhcConnection, error) {
+	start := time.Now()
+	latest, err := i.dao.LatestPerSource(sourceIds)
+	observeRhcConnectionDaoList("LatestPerSource", start, len(latest), err)
+
+	return latest, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListModifiedBy(actor string, since time.Time, limit, offset int) ([]m.RhcConnection, int64, error) {
+	start := time.Now()
+	rhcConnections, count, err := i.dao.ListModifiedBy(actor, since, limit, offset)
+	observeRhcConnectionDaoList("ListModifiedBy", start, len(rhcConnections), err)
+
+	return rhcConnections, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListStatusHistory(rhcConnectionId int64, tenantId int64, limit int) ([]m.RhcConnectionStatusEvent, error) {
+	start := time.Now()
+	events, err := i.dao.ListStatusHistory(rhcConnectionId, tenantId, limit)
+	observeRhcConnectionDaoList("ListStatusHistory", start, len(events), err)
+
+	return events, err
+}
+
+func (i *instrumentedRhcConnectionDao) GetBySourceAndRhcId(sourceId *int64, rhcId string) (*m.RhcConnection, error) {
+	start := time.Now()
+	rhcConnection, err := i.dao.GetBySourceAndRhcId(sourceId, rhcId)
+	observeRhcConnectionDao("GetBySourceAndRhcId", start, err)
+
+	return rhcConnection, err
+}
+
+func (i *instrumentedRhcConnectionDao) Create(rhcConnection *m.RhcConnection) (*m.RhcConnection, error) {
+	start := time.Now()
+	created, err := i.dao.Create(rhcConnection)
+	observeRhcConnectionDao("Create", start, err)
+
+	return created, err
+}
+
+func (i *instrumentedRhcConnectionDao) CreateOrLink(rhcId string, sourceId int64) (*m.RhcConnection, bool, error) {
+	start := time.Now()
+	rhcConnection, linked, err := i.dao.CreateOrLink(rhcId, sourceId)
+	observeRhcConnectionDao("CreateOrLink", start, err)
+
+	return rhcConnection, linked, err
+}
+
+func (i *instrumentedRhcConnectionDao) Update(rhcConnection *m.RhcConnection) (int64, error) {
+	start := time.Now()
+	rowsAffected, err := i.dao.Update(rhcConnection)
+	observeRhcConnectionDao("Update", start, err)
+
+	return rowsAffected, err
+}
+
+func (i *instrumentedRhcConnectionDao) Delete(id *int64, force bool) (*m.RhcConnection, error) {
+	start := time.Now()
+	rhcConnection, err := i.dao.Delete(id, force)
+	observeRhcConnectionDao("Delete", start, err)
+
+	return rhcConnection, err
+}
+
+func (i *instrumentedRhcConnectionDao) DeleteIfExists(id *int64) (bool, *m.RhcConnection, error) {
+	start := time.Now()
+	deleted, rhcConnection, err := i.dao.DeleteIfExists(id)
+	observeRhcConnectionDao("DeleteIfExists", start, err)
+
+	return deleted, rhcConnection, err
+}
+
+func (i *instrumentedRhcConnectionDao) UnlinkFromSource(rhcConnectionId, sourceId, tenantId int64) (*m.RhcConnection, bool, error) {
+	start := time.Now()
+	rhcConnection, deleted, err := i.dao.UnlinkFromSource(rhcConnectionId, sourceId, tenantId)
+	observeRhcConnectionDao("UnlinkFromSource", start, err)
+
+	return rhcConnection, deleted, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListByApplicationType(appTypeId int64, limit, offset int) ([]m.RhcConnection, int64, error) {
+	start := time.Now()
+	rhcConnections, count, err := i.dao.ListByApplicationType(appTypeId, limit, offset)
+	observeRhcConnectionDaoList("ListByApplicationType", start, len(rhcConnections), err)
+
+	return rhcConnections, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListBySourceName(namePrefix string, limit, offset int) ([]m.RhcConnection, int64, error) {
+	start := time.Now()
+	rhcConnections, count, err := i.dao.ListBySourceName(namePrefix, limit, offset)
+	observeRhcConnectionDaoList("ListBySourceName", start, len(rhcConnections), err)
+
+	return rhcConnections, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListShared(minSources int, limit, offset int) ([]m.RhcConnection, int64, error) {
+	start := time.Now()
+	rhcConnections, count, err := i.dao.ListShared(minSources, limit, offset)
+	observeRhcConnectionDaoList("ListShared", start, len(rhcConnections), err)
+
+	return rhcConnections, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListWithSources(ctx context.Context, limit, offset int, filters []util.Filter) ([]m.RhcConnectionWithSources, int64, error) {
+	start := time.Now()
+	rhcConnections, count, err := i.dao.ListWithSources(ctx, limit, offset, filters)
+	observeRhcConnectionDaoList("ListWithSources", start, len(rhcConnections), err)
+
+	return rhcConnections, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListWithSourceAvailability(limit, offset int) ([]m.RhcConnectionWithAvailability, int64, error) {
+	start := time.Now()
+	rhcConnections, count, err := i.dao.ListWithSourceAvailability(limit, offset)
+	observeRhcConnectionDaoList("ListWithSourceAvailability", start, len(rhcConnections), err)
+
+	return rhcConnections, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListForSource(sourceId *int64, limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error) {
+	start := time.Now()
+	rhcConnections, count, err := i.dao.ListForSource(sourceId, limit, offset, filters)
+	observeRhcConnectionDaoList("ListForSource", start, len(rhcConnections), err)
+
+	return rhcConnections, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) AdminListForSource(sourceId *int64, includeDeleted bool) ([]m.RhcConnectionAdminEntry, error) {
+	start := time.Now()
+	entries, err := i.dao.AdminListForSource(sourceId, includeDeleted)
+	observeRhcConnectionDaoList("AdminListForSource", start, len(entries), err)
+
+	return entries, err
+}
+
+func (i *instrumentedRhcConnectionDao) ListForSourceUID(sourceUID string, limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error) {
+	start := time.Now()
+	rhcConnections, count, err := i.dao.ListForSourceUID(sourceUID, limit, offset, filters)
+	observeRhcConnectionDaoList("ListForSourceUID", start, len(rhcConnections), err)
+
+	return rhcConnections, count, err
+}
+
+func (i *instrumentedRhcConnectionDao) Deduplicate(rhcId string) (*m.RhcConnection, error) {
+	start := time.Now()
+	rhcConnection, err := i.dao.Deduplicate(rhcId)
+	observeRhcConnectionDao("Deduplicate", start, err)
+
+	return rhcConnection, err
+}
+
+func (i *instrumentedRhcConnectionDao) DeduplicateDryRun(rhcId string) (*m.RhcConnectionDeduplication, error) {
+	start := time.Now()
+	deduplication, err := i.dao.DeduplicateDryRun(rhcId)
+	observeRhcConnectionDao("DeduplicateDryRun", start, err)
+
+	return deduplication, err
+}
+
+func (i *instrumentedRhcConnectionDao) Merge(winnerId, loserId *int64) (*m.RhcConnection, error) {
+	start := time.Now()
+	rhcConnection, err := i.dao.Merge(winnerId, loserId)
+	observeRhcConnectionDao("Merge", start, err)
+
+	return rhcConnection, err
+}
+
+func (i *instrumentedRhcConnectionDao) MergeDryRun(winnerId, loserId *int64) (*m.RhcConnectionDeduplication, error) {
+	start := time.Now()
+	merge, err := i.dao.MergeDryRun(winnerId, loserId)
+	observeRhcConnectionDao("MergeDryRun", start, err)
+
+	return merge, err
+}
+
+func (i *instrumentedRhcConnectionDao) SummaryBySource(tenantId int64) ([]m.RhcConnectionSourceSummary, error) {
+	start := time.Now()
+	summary, err := i.dao.SummaryBySource(tenantId)
+	observeRhcConnectionDaoList("SummaryBySource", start, len(summary), err)
+
+	return summary, err
+}
+
+func (i *instrumentedRhcConnectionDao) CountForTenant() (int64, error) {
+	start := time.Now()
+	count, err := i.dao.CountForTenant()
+	observeRhcConnectionDao("CountForTenant", start, err)
+
+	return count, err
+}
+
+func (i *instrumentedRhcConnectionDao) CountSnapshot() ([]m.RhcConnectionCountSnapshot, error) {
+	start := time.Now()
+	snapshot, err := i.dao.CountSnapshot()
+	observeRhcConnectionDaoList("CountSnapshot", start, len(snapshot), err)
+
+	return snapshot, err
+}
+
+func (i *instrumentedRhcConnectionDao) CheckIntegrity() (m.IntegrityReport, error) {
+	start := time.Now()
+	report, err := i.dao.CheckIntegrity()
+	observeRhcConnectionDao("CheckIntegrity", start, err)
+
+	return report, err
+}
+
+func (i *instrumentedRhcConnectionDao) ProbeConnection(id *int64) (bool, string, error) {
+	start := time.Now()
+	reachable, detail, err := i.dao.ProbeConnection(id)
+	observeRhcConnectionDao("ProbeConnection", start, err)
+
+	return reachable, detail, err
+}
+
+func (i *instrumentedRhcConnectionDao) AddTags(id int64, tags map[string]string) (*m.RhcConnection, error) {
+	start := time.Now()
+	rhcConnection, err := i.dao.AddTags(id, tags)
+	observeRhcConnectionDao("AddTags", start, err)
+
+	return rhcConnection, err
+}
+
+func (i *instrumentedRhcConnectionDao) RemoveTags(id int64, keys []string) (*m.RhcConnection, error) {
+	start := time.Now()
+	rhcConnection, err := i.dao.RemoveTags(id, keys)
+	observeRhcConnectionDao("RemoveTags", start, err)
+
+	return rhcConnection, err
+}
+
+// RegisterHook is not instrumented, since it doesn't hit the database.
+func (i *instrumentedRhcConnectionDao) RegisterHook(hook DaoHook) {
+	i.dao.RegisterHook(hook)
+}
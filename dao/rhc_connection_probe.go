@@ -0,0 +1,31 @@
+package dao
+
+import (
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// RhcConnectionProber synchronously checks whether a connection is reachable, as opposed to the availability checks
+// which are requested asynchronously and whose results end up stored in the database.
+type RhcConnectionProber interface {
+	Probe(request m.RhcConnectionAvailabilityCheck) (reachable bool, detail string, err error)
+}
+
+// RhcProber is the prober used to check the connections on demand. It can be replaced in runtime to either plug in a
+// real prober or a mocked one for the tests.
+var RhcProber RhcConnectionProber = noopRhcConnectionProber{}
+
+// noopRhcConnectionProber is the default prober, which doesn't reach anything and reports so.
+type noopRhcConnectionProber struct{}
+
+func (noopRhcConnectionProber) Probe(_ m.RhcConnectionAvailabilityCheck) (bool, string, error) {
+	return false, "no connection prober is configured", nil
+}
+
+func (s *rhcConnectionDaoImpl) ProbeConnection(id *int64) (bool, string, error) {
+	rhcConnection, err := s.GetById(id)
+	if err != nil {
+		return false, "", err
+	}
+
+	return RhcProber.Probe(newRhcConnectionAvailabilityCheck(*s.TenantID, rhcConnection))
+}
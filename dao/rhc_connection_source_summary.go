@@ -0,0 +1,54 @@
+package dao
+
+import (
+	"sync"
+	"time"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// rhcConnectionSourceSummaryCacheTtl is the amount of time the summary of the connections by source is kept in the
+// cache for a tenant.
+const rhcConnectionSourceSummaryCacheTtl = 30 * time.Second
+
+// rhcConnectionSourceSummaryCache holds the computed summaries of the connections by source, keyed by tenant ID.
+var rhcConnectionSourceSummaryCache sync.Map
+
+// cachedRhcConnectionSourceSummary is the cache entry for a tenant's summary of the connections by source.
+type cachedRhcConnectionSourceSummary struct {
+	summary   []m.RhcConnectionSourceSummary
+	expiresAt time.Time
+}
+
+func (s *rhcConnectionDaoImpl) SummaryBySource(tenantId int64) ([]m.RhcConnectionSourceSummary, error) {
+	if cached, ok := rhcConnectionSourceSummaryCache.Load(tenantId); ok {
+		entry := cached.(cachedRhcConnectionSourceSummary)
+		if time.Now().Before(entry.expiresAt) {
+			return entry.summary, nil
+		}
+	}
+
+	summary := make([]m.RhcConnectionSourceSummary, 0)
+	err := s.db().
+		Table(`"sources"`).
+		Select(`"sources"."id" AS "source_id", "sources"."name" AS "source_name", COUNT(*) AS "connection_count", COUNT(*) FILTER (WHERE "rhc_connections"."availability_status" = ?) AS "available_count"`, m.Available).
+		Joins(`INNER JOIN "source_rhc_connections" ON "source_rhc_connections"."source_id" = "sources"."id"`).
+		Joins(`INNER JOIN "rhc_connections" ON "rhc_connections"."id" = "source_rhc_connections"."rhc_connection_id"`).
+		Where(`"sources"."tenant_id" = ?`, tenantId).
+		Where(`"source_rhc_connections"."tenant_id" = ?`, tenantId).
+		Group(`"sources"."id", "sources"."name"`).
+		Order(`"sources"."id" ASC`).
+		Scan(&summary).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	rhcConnectionSourceSummaryCache.Store(tenantId, cachedRhcConnectionSourceSummary{
+		summary:   summary,
+		expiresAt: time.Now().Add(rhcConnectionSourceSummaryCacheTtl),
+	})
+
+	return summary, nil
+}
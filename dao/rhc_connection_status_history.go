@@ -0,0 +1,101 @@
+package dao
+
+import (
+	"time"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rhcConnectionStatusHistoryCap is the maximum number of status events kept for every connection. The oldest events
+// get pruned when new ones are recorded.
+const rhcConnectionStatusHistoryCap = 100
+
+// lockStatusChange locks the connection that is about to be updated, and returns the status event to be recorded if
+// the update changes the connection's availability status. A "bad request" error is returned if the transition isn't
+// valid, and nil is returned when the status doesn't change or the connection doesn't exist.
+func (s *rhcConnectionDaoImpl) lockStatusChange(tx *gorm.DB, rhcConnection *m.RhcConnection) (*m.RhcConnectionStatusEvent, error) {
+	// "Updates" leaves the empty statuses untouched, so they don't change anything.
+	if rhcConnection.AvailabilityStatus == "" {
+		return nil, nil
+	}
+
+	var previousStatuses []string
+	err := tx.
+		Model(&m.RhcConnection{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(`"id" = ?`, rhcConnection.ID).
+		Where(`"id" IN (SELECT "rhc_connection_id" FROM "source_rhc_connections" WHERE "tenant_id" = ?)`, s.TenantID).
+		Pluck(`COALESCE("availability_status", '')`, &previousStatuses).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(previousStatuses) == 0 || previousStatuses[0] == rhcConnection.AvailabilityStatus {
+		return nil, nil
+	}
+
+	err = m.ValidateTransition(previousStatuses[0], rhcConnection.AvailabilityStatus)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	return &m.RhcConnectionStatusEvent{
+		RhcConnectionId: rhcConnection.ID,
+		PreviousStatus:  previousStatuses[0],
+		NewStatus:       rhcConnection.AvailabilityStatus,
+		ChangedAt:       time.Now(),
+		ChangedBy:       rhcConnection.UpdatedBy,
+	}, nil
+}
+
+// recordStatusChange stores the status event, and prunes the connection's oldest events which exceed the history's
+// cap.
+func recordStatusChange(tx *gorm.DB, event *m.RhcConnectionStatusEvent) error {
+	err := tx.Create(event).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.
+		Where(`"rhc_connection_id" = ?`, event.RhcConnectionId).
+		Where(`"id" NOT IN (SELECT "id" FROM "rhc_connection_status_events" WHERE "rhc_connection_id" = ? ORDER BY "changed_at" DESC, "id" DESC LIMIT ?)`, event.RhcConnectionId, rhcConnectionStatusHistoryCap).
+		Delete(&m.RhcConnectionStatusEvent{}).
+		Error
+}
+
+func (s *rhcConnectionDaoImpl) ListStatusHistory(rhcConnectionId int64, tenantId int64, limit int) ([]m.RhcConnectionStatusEvent, error) {
+	var linked bool
+	err := s.db().
+		Model(&m.SourceRhcConnection{}).
+		Select(`1`).
+		Where(`"rhc_connection_id" = ?`, rhcConnectionId).
+		Where(`"tenant_id" = ?`, tenantId).
+		Limit(1).
+		Scan(&linked).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	if !linked {
+		return nil, util.NewErrNotFound("rhcConnection")
+	}
+
+	events := make([]m.RhcConnectionStatusEvent, 0)
+	err = s.db().
+		Where(`"rhc_connection_id" = ?`, rhcConnectionId).
+		Order(`"changed_at" DESC`).
+		Order(`"id" DESC`).
+		Limit(pageSize(limit)).
+		Find(&events).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
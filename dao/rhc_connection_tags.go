@@ -0,0 +1,104 @@
+package dao
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+)
+
+// tagKeyRegex and tagValueRegex are the formats the keys and the values of the connections' tags must follow.
+var (
+	tagKeyRegex   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]{0,62}$`)
+	tagValueRegex = regexp.MustCompile(`^[A-Za-z0-9._/-]{1,63}$`)
+)
+
+// validateTagKey returns an error when the given tag key doesn't follow the expected format.
+func validateTagKey(key string) error {
+	if !tagKeyRegex.MatchString(key) {
+		return fmt.Errorf(`invalid tag key "%s": it must start with a letter or a digit, and contain up to 63 letters, digits, ".", "_", "/" or "-"`, key)
+	}
+
+	return nil
+}
+
+// validateTags returns an error when any of the given tags' keys or values doesn't follow the expected format.
+func validateTags(tags map[string]string) error {
+	for key, value := range tags {
+		err := validateTagKey(key)
+		if err != nil {
+			return err
+		}
+
+		if !tagValueRegex.MatchString(value) {
+			return fmt.Errorf(`invalid value "%s" for the tag "%s": it must contain between 1 and 63 letters, digits, ".", "_", "/" or "-"`, value, key)
+		}
+	}
+
+	return nil
+}
+
+func (s *rhcConnectionDaoImpl) AddTags(id int64, tags map[string]string) (*m.RhcConnection, error) {
+	if len(tags) == 0 {
+		return nil, util.NewErrBadRequest("at least one tag is required")
+	}
+
+	err := validateTags(tags)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	tagsJson, err := json.Marshal(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// The given tags overwrite the existing ones with the same keys.
+	return s.updateTags(id, gorm.Expr(`COALESCE("tags", '{}'::JSONB) || ?::JSONB`, string(tagsJson)))
+}
+
+func (s *rhcConnectionDaoImpl) RemoveTags(id int64, keys []string) (*m.RhcConnection, error) {
+	if len(keys) == 0 {
+		return nil, util.NewErrBadRequest("at least one tag key is required")
+	}
+
+	for _, key := range keys {
+		err := validateTagKey(key)
+		if err != nil {
+			return nil, util.NewErrBadRequest(err)
+		}
+	}
+
+	return s.updateTags(id, gorm.Expr(`COALESCE("tags", '{}'::JSONB) - ARRAY[?]::TEXT[]`, keys))
+}
+
+// updateTags sets the tags of the tenant's connection to the result of the given expression, and returns the updated
+// connection.
+func (s *rhcConnectionDaoImpl) updateTags(id int64, tags interface{}) (*m.RhcConnection, error) {
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		result := tx.
+			Model(&m.RhcConnection{}).
+			Where("id = ?", id).
+			Where("tenant_id = ?", s.TenantID).
+			Update("tags", tags)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return util.NewErrNotFound("rhcConnection")
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetById(&id)
+}
@@ -90,7 +90,10 @@ func seedSourceTypes() error {
 		// mark the fields as updated
 		st.Category = values.Category
 		st.ProductName = values.ProductName
-		st.IconUrl = values.IconURL
+		// the icon can be changed by the operators, so only the seeded default gets set.
+		if st.IconUrl == "" {
+			st.IconUrl = values.IconURL
+		}
 		st.Schema = schema
 		st.Vendor = values.Vendor
 		st.Name = name
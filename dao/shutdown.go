@@ -0,0 +1,177 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// ErrShuttingDown is returned for any DAO operation that tries to start once the shutdown has begun.
+var ErrShuttingDown = errors.New("the database connection is shutting down")
+
+// trackedOperationKey is the key under which the statements flag that they are being tracked as in-flight operations.
+const trackedOperationKey = "sources:tracked_operation"
+
+// operations keeps track of the in-flight DAO operations so that they can be drained on shutdown.
+var operations = newOperationTracker()
+
+// operationTracker counts the operations that are currently running against the database, and stops accepting new
+// ones once it starts draining.
+type operationTracker struct {
+	mutex    sync.Mutex
+	inFlight int
+	draining bool
+	drained  chan struct{}
+}
+
+func newOperationTracker() *operationTracker {
+	return &operationTracker{drained: make(chan struct{})}
+}
+
+// start registers a new in-flight operation. It returns an "ErrShuttingDown" error if the tracker is draining.
+func (ot *operationTracker) start() error {
+	ot.mutex.Lock()
+	defer ot.mutex.Unlock()
+
+	if ot.draining {
+		return ErrShuttingDown
+	}
+
+	ot.inFlight++
+	return nil
+}
+
+// done marks an in-flight operation as finished.
+func (ot *operationTracker) done() {
+	ot.mutex.Lock()
+	defer ot.mutex.Unlock()
+
+	ot.inFlight--
+	if ot.draining && ot.inFlight == 0 {
+		close(ot.drained)
+	}
+}
+
+// drain stops accepting new operations and returns a channel which gets closed once all the in-flight operations have
+// finished.
+func (ot *operationTracker) drain() <-chan struct{} {
+	ot.mutex.Lock()
+	defer ot.mutex.Unlock()
+
+	if !ot.draining {
+		ot.draining = true
+
+		if ot.inFlight == 0 {
+			close(ot.drained)
+		}
+	}
+
+	return ot.drained
+}
+
+// registerOperationTracking registers the callbacks that track every statement that runs outside of a transaction as
+// an in-flight operation. Statements that run inside a transaction are not tracked individually, since the
+// transaction as a whole is tracked by the "Transaction" function.
+func registerOperationTracking(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	errs := []error{
+		callbacks.Create().Before("*").Register("sources:track_create_start", startTrackedStatement),
+		callbacks.Create().After("*").Register("sources:track_create_end", endTrackedStatement),
+		callbacks.Query().Before("*").Register("sources:track_query_start", startTrackedStatement),
+		callbacks.Query().After("*").Register("sources:track_query_end", endTrackedStatement),
+		callbacks.Update().Before("*").Register("sources:track_update_start", startTrackedStatement),
+		callbacks.Update().After("*").Register("sources:track_update_end", endTrackedStatement),
+		callbacks.Delete().Before("*").Register("sources:track_delete_start", startTrackedStatement),
+		callbacks.Delete().After("*").Register("sources:track_delete_end", endTrackedStatement),
+		callbacks.Row().Before("*").Register("sources:track_row_start", startTrackedStatement),
+		callbacks.Row().After("*").Register("sources:track_row_end", endTrackedStatement),
+		callbacks.Raw().Before("*").Register("sources:track_raw_start", startTrackedStatement),
+		callbacks.Raw().After("*").Register("sources:track_raw_end", endTrackedStatement),
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("could not register the operation tracking callbacks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// startTrackedStatement registers the statement as an in-flight operation, unless it is running inside a
+// transaction. If the DAO is shutting down, the statement is aborted.
+func startTrackedStatement(db *gorm.DB) {
+	if _, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter); inTransaction {
+		return
+	}
+
+	err := operations.start()
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+
+	db.InstanceSet(trackedOperationKey, true)
+}
+
+// endTrackedStatement marks the statement's operation as finished, if it was being tracked.
+func endTrackedStatement(db *gorm.DB) {
+	if tracked, ok := db.InstanceGet(trackedOperationKey); ok && tracked.(bool) {
+		operations.done()
+	}
+}
+
+// Transaction runs the given function inside a transaction which is tracked as a single in-flight operation, so that
+// a shutdown waits for it to either commit or roll back before closing the database connection.
+func Transaction(fc func(tx *gorm.DB) error) error {
+	return transaction(DB, fc)
+}
+
+// transaction runs the given function inside a tracked transaction opened on the given database handle.
+func transaction(db *gorm.DB, fc func(tx *gorm.DB) error) error {
+	err := operations.start()
+	if err != nil {
+		return err
+	}
+	defer operations.done()
+
+	// The nested transactions are committed along with the outermost one, which is the one publishing the events.
+	if _, nested := db.Statement.ConnPool.(gorm.TxCommitter); nested {
+		return db.Transaction(fc)
+	}
+
+	// The availability changes made inside the transaction are only published once it has been committed.
+	events := &pendingAvailabilityEvents{}
+	err = db.WithContext(withPendingAvailabilityEvents(db.Statement.Context, events)).Transaction(fc)
+	events.flush(err)
+
+	return err
+}
+
+// Shutdown stops accepting new DAO operations and waits for the in-flight ones to finish before closing the database
+// connection. If the given context expires before the operations have been drained, the connection gets closed anyway
+// and the context's error is returned.
+func Shutdown(ctx context.Context) error {
+	var drainErr error
+	select {
+	case <-operations.drain():
+	case <-ctx.Done():
+		drainErr = fmt.Errorf("timed out waiting for the in-flight database operations to finish: %w", ctx.Err())
+	}
+
+	sqlDb, err := DB.DB()
+	if err != nil {
+		return err
+	}
+
+	err = sqlDb.Close()
+	if err != nil {
+		return err
+	}
+
+	return drainErr
+}
@@ -0,0 +1,53 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestOperationTrackerDrainWaitsForInFlight tests that draining the tracker doesn't finish until all the in-flight
+// operations are done.
+func TestOperationTrackerDrainWaitsForInFlight(t *testing.T) {
+	tracker := newOperationTracker()
+
+	err := tracker.start()
+	if err != nil {
+		t.Errorf(`want nil error, got "%s"`, err)
+	}
+
+	drained := tracker.drain()
+
+	select {
+	case <-drained:
+		t.Errorf("want the tracker to wait for the in-flight operation, but it got drained")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	tracker.done()
+
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Errorf("want the tracker drained after finishing the in-flight operation, but it wasn't")
+	}
+}
+
+// TestOperationTrackerRejectsWhenDraining tests that no new operations are accepted once the tracker starts draining.
+func TestOperationTrackerRejectsWhenDraining(t *testing.T) {
+	tracker := newOperationTracker()
+
+	select {
+	case <-tracker.drain():
+	case <-time.After(time.Second):
+		t.Errorf("want an idle tracker to be drained immediately, but it wasn't")
+	}
+
+	err := tracker.start()
+	if !errors.Is(err, ErrShuttingDown) {
+		t.Errorf(`want "%s", got "%v"`, ErrShuttingDown, err)
+	}
+
+	// Draining an already drained tracker must not panic.
+	tracker.drain()
+}
@@ -0,0 +1,80 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// softDeleteSupport caches whether the models support soft deletes, keyed by their type, so that their schemas only
+// get parsed once.
+var softDeleteSupport sync.Map
+
+// supportsSoftDelete returns true when the given model has a "gorm.DeletedAt" field, which means that deleting it
+// only marks the record as deleted.
+func supportsSoftDelete(model interface{}) bool {
+	modelType := reflect.TypeOf(model)
+	if supported, ok := softDeleteSupport.Load(modelType); ok {
+		return supported.(bool)
+	}
+
+	supported := false
+
+	modelSchema, err := schema.Parse(model, &sync.Map{}, schema.NamingStrategy{})
+	if err == nil {
+		deletedAtType := reflect.TypeOf(gorm.DeletedAt{})
+		for _, field := range modelSchema.Fields {
+			if field.FieldType == deletedAtType {
+				supported = true
+				break
+			}
+		}
+	}
+
+	softDeleteSupport.Store(modelType, supported)
+
+	return supported
+}
+
+// softDeletedCondition returns the SQL condition which tells whether the given table's row was soft deleted, which is
+// always false for the models which don't support soft deletes.
+func softDeletedCondition(model interface{}, table string) string {
+	if !supportsSoftDelete(model) {
+		return "FALSE"
+	}
+
+	return fmt.Sprintf(`"%s"."deleted_at" IS NOT NULL`, table)
+}
+
+// notFoundOrGone returns an "util.ErrGone" error when the given query finds a soft deleted record, or the given
+// "not found" error otherwise. The query is expected to be scoped to the record and the tenant already, and it is
+// only run when the model supports soft deletes.
+func notFoundOrGone(query *gorm.DB, model interface{}, notFound error) error {
+	if !supportsSoftDelete(model) {
+		return notFound
+	}
+
+	var count int64
+	err := query.
+		Unscoped().
+		Model(model).
+		Where("deleted_at IS NOT NULL").
+		Count(&count).
+		Error
+
+	if err != nil || count == 0 {
+		return notFound
+	}
+
+	var errNotFound util.ErrNotFound
+	if !errors.As(notFound, &errNotFound) {
+		return notFound
+	}
+
+	return util.NewErrGone(errNotFound.Type)
+}
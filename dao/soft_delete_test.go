@@ -0,0 +1,71 @@
+package dao
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+)
+
+// softDeletableModel is a model which only gets marked as deleted.
+type softDeletableModel struct {
+	ID        int64
+	DeletedAt gorm.DeletedAt
+}
+
+// TestSupportsSoftDelete tests that only the models with a "gorm.DeletedAt" field are reported as soft deletable, and
+// that the answer gets cached.
+func TestSupportsSoftDelete(t *testing.T) {
+	if supportsSoftDelete(&m.RhcConnection{}) {
+		t.Errorf(`want "false" for the rhcConnection model, got "true"`)
+	}
+
+	if !supportsSoftDelete(&softDeletableModel{}) {
+		t.Errorf(`want "true" for a model with a "DeletedAt" field, got "false"`)
+	}
+
+	if supported, ok := softDeleteSupport.Load(reflect.TypeOf(&softDeletableModel{})); !ok || supported != true {
+		t.Errorf(`want the soft delete support of the model cached, got "%v"`, supported)
+	}
+}
+
+// TestNotFoundOrGone tests that a soft deleted record is reported as gone, and that a record which never existed is
+// still reported as not found.
+func TestNotFoundOrGone(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("soft_delete")
+
+	err := DB.AutoMigrate(&softDeletableModel{})
+	if err != nil {
+		t.Fatalf(`could not migrate the soft deletable model: %s`, err)
+	}
+
+	record := softDeletableModel{ID: 1}
+	err = DB.Create(&record).Error
+	if err != nil {
+		t.Fatalf(`could not create the record: %s`, err)
+	}
+
+	err = DB.Delete(&record).Error
+	if err != nil {
+		t.Fatalf(`could not soft delete the record: %s`, err)
+	}
+
+	notFound := util.NewErrNotFound("softDeletableModel")
+
+	err = notFoundOrGone(DB.Where("id = ?", record.ID), &softDeletableModel{}, notFound)
+	if !errors.Is(err, util.ErrGoneEmpty) {
+		t.Errorf(`want a "gone" error for the soft deleted record, got "%v"`, err)
+	}
+
+	err = notFoundOrGone(DB.Where("id = ?", 12345), &softDeletableModel{}, notFound)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a "not found" error for a record which never existed, got "%v"`, err)
+	}
+
+	DropSchema("soft_delete")
+}
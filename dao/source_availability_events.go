@@ -0,0 +1,204 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SourceAvailabilityChangedPublisher publishes the availability status changes of the sources.
+type SourceAvailabilityChangedPublisher interface {
+	PublishSourceAvailabilityChanged(event *m.SourceAvailabilityChangedEvent) error
+}
+
+// SourceAvailabilityPublisher is the publisher used when the availability status of a source changes. It gets set by
+// "RegisterSourceAvailabilityHook". When nil, no events are published.
+var SourceAvailabilityPublisher SourceAvailabilityChangedPublisher
+
+// previousAvailabilityKey is the key under which the update statements store the source's availability status from
+// before the update.
+const previousAvailabilityKey = "sources:previous_availability"
+
+// previousAvailability is the source's availability status from before the update, along with its tenant, since the
+// updated sources don't always carry it.
+type previousAvailability struct {
+	AvailabilityStatus string
+	TenantId           int64
+}
+
+// RegisterSourceAvailabilityHook registers the callbacks that publish an event with the given publisher every time the
+// availability status of a source gets updated. The events are only published once the update has been committed:
+// right after the statement when it runs on its own, or once the transaction commits when it runs inside one opened by
+// "transaction". The updates which are rolled back are never published.
+func RegisterSourceAvailabilityHook(db *gorm.DB, publisher SourceAvailabilityChangedPublisher) error {
+	SourceAvailabilityPublisher = publisher
+
+	callbacks := db.Callback()
+
+	errs := []error{
+		callbacks.Update().Before("gorm:update").Register("sources:source_availability_previous", recordPreviousAvailabilityStatus),
+		callbacks.Update().After("gorm:commit_or_rollback_transaction").Register("sources:source_availability_publish", publishAvailabilityChange),
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return fmt.Errorf("could not register the source availability callbacks: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// updatedAvailabilitySource returns the source whose availability status the statement updates, if any. Only the
+// updates of whole sources —such as the ones "sourceDaoImpl.Update" runs— are considered.
+func updatedAvailabilitySource(db *gorm.DB) (*m.Source, bool) {
+	source, ok := db.Statement.Dest.(*m.Source)
+	if !ok || source.ID == 0 || source.AvailabilityStatus == "" {
+		return nil, false
+	}
+
+	return source, true
+}
+
+// recordPreviousAvailabilityStatus stores the source's current availability status in the statement, before it gets
+// updated. The source is locked so that the concurrent updates don't report the same previous status.
+func recordPreviousAvailabilityStatus(db *gorm.DB) {
+	if db.Error != nil || SourceAvailabilityPublisher == nil {
+		return
+	}
+
+	source, ok := updatedAvailabilitySource(db)
+	if !ok {
+		return
+	}
+
+	var previous []previousAvailability
+	err := db.
+		Session(&gorm.Session{NewDB: true}).
+		Model(&m.Source{}).
+		Select("availability_status", "tenant_id").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", source.ID).
+		Scan(&previous).
+		Error
+
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("could not fetch the previous availability status of the source: %w", err))
+		return
+	}
+
+	if len(previous) == 1 {
+		db.InstanceSet(previousAvailabilityKey, previous[0])
+	}
+}
+
+// publishAvailabilityChange publishes the change of the source's availability status once the statement has been
+// committed. When the statement runs inside a transaction, the event is deferred until the transaction commits.
+func publishAvailabilityChange(db *gorm.DB) {
+	if db.Error != nil || db.RowsAffected == 0 {
+		return
+	}
+
+	stored, ok := db.InstanceGet(previousAvailabilityKey)
+	if !ok {
+		return
+	}
+	previous := stored.(previousAvailability)
+
+	source, ok := updatedAvailabilitySource(db)
+	if !ok || source.AvailabilityStatus == previous.AvailabilityStatus {
+		return
+	}
+
+	event := &m.SourceAvailabilityChangedEvent{
+		SourceId:          source.ID,
+		TenantId:          previous.TenantId,
+		PreviousStatus:    previous.AvailabilityStatus,
+		NewStatus:         source.AvailabilityStatus,
+		CheckedAt:         source.LastCheckedAt,
+		AvailabilityError: source.AvailabilityStatusError,
+	}
+
+	if _, inTransaction := db.Statement.ConnPool.(gorm.TxCommitter); !inTransaction {
+		publishSourceAvailabilityChanged(event)
+		return
+	}
+
+	if events, ok := pendingAvailabilityEventsFrom(db.Statement.Context); ok {
+		events.add(event)
+		return
+	}
+
+	logging.Log.Warnf(`The availability change of source "%d" was not published, since it was made in a transaction which wasn't opened by the DAOs`, source.ID)
+}
+
+// publishSourceAvailabilityChanged publishes the event, and logs the error if it couldn't be. The change has already
+// been committed at this point, so the error cannot be returned to the caller.
+func publishSourceAvailabilityChanged(event *m.SourceAvailabilityChangedEvent) {
+	publisher := SourceAvailabilityPublisher
+	if publisher == nil {
+		return
+	}
+
+	err := publisher.PublishSourceAvailabilityChanged(event)
+	if err != nil {
+		logging.Log.Errorf(`Unable to publish the availability change of source "%d": %s`, event.SourceId, err)
+	}
+}
+
+// pendingAvailabilityEventsKey is the key under which the transactions' contexts carry their pending events.
+type pendingAvailabilityEventsKey struct{}
+
+// pendingAvailabilityEvents holds the availability changes made inside a transaction until it finishes.
+type pendingAvailabilityEvents struct {
+	mutex  sync.Mutex
+	events []*m.SourceAvailabilityChangedEvent
+}
+
+// withPendingAvailabilityEvents returns a copy of the given context which carries the given pending events.
+func withPendingAvailabilityEvents(ctx context.Context, events *pendingAvailabilityEvents) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, pendingAvailabilityEventsKey{}, events)
+}
+
+// pendingAvailabilityEventsFrom returns the pending events carried by the given context, if any.
+func pendingAvailabilityEventsFrom(ctx context.Context) (*pendingAvailabilityEvents, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	events, ok := ctx.Value(pendingAvailabilityEventsKey{}).(*pendingAvailabilityEvents)
+	return events, ok
+}
+
+func (p *pendingAvailabilityEvents) add(event *m.SourceAvailabilityChangedEvent) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.events = append(p.events, event)
+}
+
+// flush publishes the pending events if the transaction was committed, which is signaled by a nil error, and discards
+// them otherwise.
+func (p *pendingAvailabilityEvents) flush(txErr error) {
+	p.mutex.Lock()
+	events := p.events
+	p.events = nil
+	p.mutex.Unlock()
+
+	if txErr != nil {
+		return
+	}
+
+	for _, event := range events {
+		publishSourceAvailabilityChanged(event)
+	}
+}
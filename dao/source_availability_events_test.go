@@ -0,0 +1,115 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"gorm.io/gorm"
+)
+
+// recordingAvailabilityPublisher records the availability changes it is asked to publish.
+type recordingAvailabilityPublisher struct {
+	events []*m.SourceAvailabilityChangedEvent
+}
+
+func (r *recordingAvailabilityPublisher) PublishSourceAvailabilityChanged(event *m.SourceAvailabilityChangedEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+// TestPendingAvailabilityEventsFlush tests that the pending events are only published when the transaction commits.
+func TestPendingAvailabilityEventsFlush(t *testing.T) {
+	publisher := &recordingAvailabilityPublisher{}
+	SourceAvailabilityPublisher = publisher
+	defer func() { SourceAvailabilityPublisher = nil }()
+
+	pending := &pendingAvailabilityEvents{}
+	pending.add(&m.SourceAvailabilityChangedEvent{SourceId: 1, NewStatus: m.Unavailable})
+	pending.flush(errors.New("rolled back"))
+
+	if len(publisher.events) != 0 {
+		t.Errorf(`want no events published for a rolled back transaction, got "%d"`, len(publisher.events))
+	}
+
+	// The discarded events must not be published by a later flush either.
+	pending.flush(nil)
+	if len(publisher.events) != 0 {
+		t.Errorf(`want the discarded events to stay unpublished, got "%d"`, len(publisher.events))
+	}
+
+	pending.add(&m.SourceAvailabilityChangedEvent{SourceId: 1, NewStatus: m.Available})
+	pending.flush(nil)
+
+	if len(publisher.events) != 1 || publisher.events[0].NewStatus != m.Available {
+		t.Errorf(`want the committed change published, got "%+v"`, publisher.events)
+	}
+}
+
+// TestSourceAvailabilityHook tests that the availability changes are published once they're committed, and that they
+// are not published when the transaction rolls back.
+func TestSourceAvailabilityHook(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_availability_hook")
+
+	publisher := &recordingAvailabilityPublisher{}
+	err := RegisterSourceAvailabilityHook(DB, publisher)
+	if err != nil {
+		t.Fatalf(`could not register the hook: %s`, err)
+	}
+	defer func() { SourceAvailabilityPublisher = nil }()
+
+	source := fixtures.TestSourceData[0]
+
+	// The rolled back change is not published.
+	err = transaction(DB, func(tx *gorm.DB) error {
+		err := tx.Updates(&m.Source{ID: source.ID, AvailabilityStatus: m.Unavailable}).Error
+		if err != nil {
+			return err
+		}
+
+		return errors.New("roll back")
+	})
+	if err == nil {
+		t.Fatalf(`want the transaction to roll back, got no error`)
+	}
+
+	if len(publisher.events) != 0 {
+		t.Errorf(`want no events published for a rolled back transaction, got "%+v"`, publisher.events)
+	}
+
+	// The committed change is published with the status it had before.
+	err = transaction(DB, func(tx *gorm.DB) error {
+		return tx.Updates(&m.Source{ID: source.ID, AvailabilityStatus: m.Unavailable, AvailabilityStatusError: "boom"}).Error
+	})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(publisher.events) != 1 {
+		t.Fatalf(`want one event published, got "%d"`, len(publisher.events))
+	}
+
+	event := publisher.events[0]
+	if event.SourceId != source.ID || event.TenantId != source.TenantID || event.PreviousStatus != source.AvailabilityStatus || event.NewStatus != m.Unavailable || event.AvailabilityError != "boom" {
+		t.Errorf(`want the change of source "%d" from "%s" to "%s", got "%+v"`, source.ID, source.AvailabilityStatus, m.Unavailable, event)
+	}
+
+	// The updates made outside of a transaction are published too, but only when the status actually changes.
+	sourceDao := GetSourceDao(context.Background(), &source.TenantID)
+	for _, status := range []string{m.Available, m.Available} {
+		err = sourceDao.Update(&m.Source{ID: source.ID, AvailabilityStatus: status})
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+	}
+
+	if len(publisher.events) != 2 || publisher.events[1].PreviousStatus != m.Unavailable || publisher.events[1].NewStatus != m.Available {
+		t.Errorf(`want a single change from "%s" to "%s", got "%+v"`, m.Unavailable, m.Available, publisher.events)
+	}
+
+	DropSchema("source_availability_hook")
+}
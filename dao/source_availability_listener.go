@@ -0,0 +1,52 @@
+package dao
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// sourceAvailabilityChannel is the channel the "sources_availability_status_notify" trigger sends the notifications
+// to.
+const sourceAvailabilityChannel = "source_availability"
+
+// sourceAvailabilityListener is shared by all the clients which follow the availability changes of the sources, so
+// that they don't hold a database connection each.
+var sourceAvailabilityListener = newNotificationListener(sourceAvailabilityChannel, config.Get().MaxAvailabilityStreamClients)
+
+func (s *sourceDaoImpl) ListenAvailabilityChanges(ctx context.Context, sourceId int64, onChange func(notification m.SourceAvailabilityNotification) error) error {
+	subscription, err := sourceAvailabilityListener.subscribe()
+	if err != nil {
+		return err
+	}
+	defer sourceAvailabilityListener.unsubscribe(subscription)
+
+	for {
+		select {
+		// The caller stopped listening, so this isn't an error.
+		case <-ctx.Done():
+			return nil
+		case err := <-subscription.errs:
+			return err
+		case payload := <-subscription.payloads:
+			var notification m.SourceAvailabilityNotification
+			err := json.Unmarshal([]byte(payload), &notification)
+			if err != nil {
+				logging.Log.Warnf(`could not unmarshal the availability change notification "%s": %s`, payload, err)
+				continue
+			}
+
+			if notification.SourceId != sourceId || notification.TenantId != *s.TenantID {
+				continue
+			}
+
+			err = onChange(notification)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
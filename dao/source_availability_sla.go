@@ -0,0 +1,124 @@
+package dao
+
+import (
+	"time"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+)
+
+func (s *sourceDaoImpl) SLAReport(sourceId int64, from, to time.Time) (*m.SourceSLAReport, error) {
+	if !from.Before(to) {
+		return nil, util.NewErrBadRequest(`the "from" date must be before the "to" date`)
+	}
+
+	var initial *m.SourceAvailabilityChange
+	var changes []m.SourceAvailabilityChange
+
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		var sourceExists bool
+		err := tx.
+			Model(&m.Source{}).
+			Select(`1`).
+			Where(`id = ?`, sourceId).
+			Where(`tenant_id = ?`, s.TenantID).
+			Scan(&sourceExists).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		if !sourceExists {
+			return util.NewErrNotFound("source")
+		}
+
+		// The last change before the period tells which status the source had when the period started.
+		var previous []m.SourceAvailabilityChange
+		err = tx.
+			Where(`source_id = ?`, sourceId).
+			Where(`changed_at <= ?`, from).
+			Order(`changed_at DESC`).
+			Limit(1).
+			Find(&previous).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		if len(previous) > 0 {
+			initial = &previous[0]
+		}
+
+		return tx.
+			Where(`source_id = ?`, sourceId).
+			Where(`changed_at > ?`, from).
+			Where(`changed_at < ?`, to).
+			Order(`changed_at ASC`).
+			Find(&changes).
+			Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	report := computeSLAReport(initial, changes, from, to, time.Now())
+	report.SourceId = sourceId
+
+	return report, nil
+}
+
+// computeSLAReport accumulates the time the source spent "available" and the time it spent on any other status
+// between the given dates. The given changes must be sorted by date and fall within the period, and the initial
+// change, if any, is the status the source had when the period started. The time before the first known status is
+// not accounted for, and neither is the time after "now", since the source's status is not known yet for it.
+func computeSLAReport(initial *m.SourceAvailabilityChange, changes []m.SourceAvailabilityChange, from, to, now time.Time) *m.SourceSLAReport {
+	report := &m.SourceSLAReport{From: from, To: to}
+
+	var currentStatus string
+	var currentSince time.Time
+	known := false
+
+	if initial != nil {
+		currentStatus = initial.AvailabilityStatus
+		currentSince = from
+		known = true
+	}
+
+	accumulate := func(until time.Time) {
+		if until.After(now) {
+			until = now
+		}
+
+		if !known || !until.After(currentSince) {
+			return
+		}
+
+		seconds := int64(until.Sub(currentSince).Seconds())
+		if currentStatus == m.Available {
+			report.UpTimeSec += seconds
+		} else {
+			report.DownTimeSec += seconds
+		}
+	}
+
+	for _, change := range changes {
+		accumulate(change.ChangedAt)
+
+		currentStatus = change.AvailabilityStatus
+		currentSince = change.ChangedAt
+		known = true
+	}
+
+	accumulate(to)
+
+	report.TotalSec = report.UpTimeSec + report.DownTimeSec
+	if report.TotalSec > 0 {
+		report.PercentAvailable = float64(report.UpTimeSec) / float64(report.TotalSec) * 100
+	}
+
+	return report
+}
@@ -0,0 +1,165 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestComputeSLAReport tests that the uptime is weighted by the time the source held each status.
+func TestComputeSLAReport(t *testing.T) {
+	from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(10 * time.Hour)
+
+	testCases := []struct {
+		name        string
+		initial     *m.SourceAvailabilityChange
+		changes     []m.SourceAvailabilityChange
+		wantUp      int64
+		wantDown    int64
+		wantPercent float64
+	}{
+		{
+			name:        "no known status",
+			wantPercent: 0,
+		},
+		{
+			name:        "available the whole period",
+			initial:     &m.SourceAvailabilityChange{AvailabilityStatus: m.Available},
+			wantUp:      36000,
+			wantPercent: 100,
+		},
+		{
+			name:    "unavailable for a quarter of the period",
+			initial: &m.SourceAvailabilityChange{AvailabilityStatus: m.Available},
+			changes: []m.SourceAvailabilityChange{
+				{AvailabilityStatus: m.Unavailable, ChangedAt: from.Add(2 * time.Hour)},
+				{AvailabilityStatus: m.Available, ChangedAt: from.Add(4 * time.Hour)},
+				{AvailabilityStatus: m.PartiallyAvailable, ChangedAt: from.Add(9 * time.Hour)},
+				{AvailabilityStatus: m.Available, ChangedAt: from.Add(9*time.Hour + 30*time.Minute)},
+			},
+			wantUp:      27000,
+			wantDown:    9000,
+			wantPercent: 75,
+		},
+		{
+			name: "status only known halfway through the period",
+			changes: []m.SourceAvailabilityChange{
+				{AvailabilityStatus: m.Available, ChangedAt: from.Add(5 * time.Hour)},
+			},
+			wantUp:      18000,
+			wantPercent: 100,
+		},
+	}
+
+	for _, tc := range testCases {
+		report := computeSLAReport(tc.initial, tc.changes, from, to, to)
+
+		if report.UpTimeSec != tc.wantUp || report.DownTimeSec != tc.wantDown {
+			t.Errorf(`[%s] want "%d" up and "%d" down seconds, got "%d" and "%d"`, tc.name, tc.wantUp, tc.wantDown, report.UpTimeSec, report.DownTimeSec)
+		}
+
+		if report.TotalSec != tc.wantUp+tc.wantDown {
+			t.Errorf(`[%s] want "%d" total seconds, got "%d"`, tc.name, tc.wantUp+tc.wantDown, report.TotalSec)
+		}
+
+		if report.PercentAvailable != tc.wantPercent {
+			t.Errorf(`[%s] want "%f" percent available, got "%f"`, tc.name, tc.wantPercent, report.PercentAvailable)
+		}
+	}
+}
+
+// TestComputeSLAReportFutureTo tests that the time after "now" is not credited to the source's current status when the
+// period ends in the future.
+func TestComputeSLAReportFutureTo(t *testing.T) {
+	from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	now := from.Add(4 * time.Hour)
+	to := from.Add(10 * time.Hour)
+
+	initial := &m.SourceAvailabilityChange{AvailabilityStatus: m.Available}
+	changes := []m.SourceAvailabilityChange{
+		{AvailabilityStatus: m.Unavailable, ChangedAt: from.Add(3 * time.Hour)},
+	}
+
+	report := computeSLAReport(initial, changes, from, to, now)
+
+	if report.UpTimeSec != 10800 || report.DownTimeSec != 3600 {
+		t.Errorf(`want "10800" up and "3600" down seconds, got "%d" and "%d"`, report.UpTimeSec, report.DownTimeSec)
+	}
+
+	if report.PercentAvailable != 75 {
+		t.Errorf(`want "75" percent available, got "%f"`, report.PercentAvailable)
+	}
+
+	// A period which starts in the future has no time to account for.
+	report = computeSLAReport(initial, nil, to, to.Add(time.Hour), now)
+
+	if report.TotalSec != 0 {
+		t.Errorf(`want no accounted seconds for a future period, got "%d"`, report.TotalSec)
+	}
+}
+
+// TestSLAReport tests that the report is computed from the source's recorded availability changes.
+func TestSLAReport(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_sla_report")
+
+	source := fixtures.TestSourceData[0]
+	from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(4 * time.Hour)
+
+	changes := []m.SourceAvailabilityChange{
+		{SourceId: source.ID, TenantId: source.TenantID, AvailabilityStatus: m.Available, ChangedAt: from.Add(-time.Hour)},
+		{SourceId: source.ID, TenantId: source.TenantID, AvailabilityStatus: m.Unavailable, ChangedAt: from.Add(time.Hour)},
+		{SourceId: source.ID, TenantId: source.TenantID, AvailabilityStatus: m.Available, ChangedAt: from.Add(2 * time.Hour)},
+		{SourceId: source.ID, TenantId: source.TenantID, AvailabilityStatus: m.Unavailable, ChangedAt: to.Add(time.Hour)},
+	}
+
+	err := DB.Create(&changes).Error
+	if err != nil {
+		t.Fatalf(`could not create the availability changes: %s`, err)
+	}
+
+	report, err := GetSourceDao(context.Background(), &source.TenantID).SLAReport(source.ID, from, to)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if report.UpTimeSec != 3*3600 || report.DownTimeSec != 3600 {
+		t.Errorf(`want "%d" up and "%d" down seconds, got "%d" and "%d"`, 3*3600, 3600, report.UpTimeSec, report.DownTimeSec)
+	}
+
+	if report.PercentAvailable != 75 {
+		t.Errorf(`want "75" percent available, got "%f"`, report.PercentAvailable)
+	}
+
+	DropSchema("source_sla_report")
+}
+
+// TestSLAReportInvalid tests that inverted periods and other tenants' sources are rejected.
+func TestSLAReportInvalid(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_sla_report")
+
+	source := fixtures.TestSourceData[0]
+	from := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := GetSourceDao(context.Background(), &source.TenantID).SLAReport(source.ID, from, from.Add(-time.Hour))
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	otherTenant := source.TenantID + 12345
+	_, err = GetSourceDao(context.Background(), &otherTenant).SLAReport(source.ID, from, from.Add(time.Hour))
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("source_sla_report")
+}
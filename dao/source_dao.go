@@ -1,10 +1,16 @@
 package dao
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"sync"
 	"time"
 
+	"github.com/RedHatInsights/sources-api-go/config"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"gorm.io/gorm"
@@ -13,12 +19,13 @@ import (
 
 // GetSourceDao is a function definition that can be replaced in runtime in case some other DAO provider is
 // needed.
-var GetSourceDao func(*int64) SourceDao
+var GetSourceDao func(context.Context, *int64) SourceDao
 
 // getDefaultRhcConnectionDao gets the default DAO implementation which will have the given tenant ID.
-func getDefaultSourceDao(tenantId *int64) SourceDao {
+func getDefaultSourceDao(ctx context.Context, tenantId *int64) SourceDao {
 	return &sourceDaoImpl{
-		TenantID: tenantId,
+		requestContext: requestContext{ctx: ctx},
+		TenantID:       tenantId,
 	}
 }
 
@@ -29,6 +36,7 @@ func init() {
 
 type sourceDaoImpl struct {
 	TenantID *int64
+	requestContext
 }
 
 func (s *sourceDaoImpl) SubCollectionList(primaryCollection interface{}, limit, offset int, filters []util.Filter) ([]m.Source, int64, error) {
@@ -36,11 +44,11 @@ func (s *sourceDaoImpl) SubCollectionList(primaryCollection interface{}, limit,
 	// 0, size of limit (since we will not be returning more than that)
 	sources := make([]m.Source, 0, limit)
 
-	relationObject, err := m.NewRelationObject(primaryCollection, *s.TenantID, DB.Debug())
+	relationObject, err := m.NewRelationObject(primaryCollection, *s.TenantID, s.db())
 	if err != nil {
 		return nil, 0, util.NewErrNotFound(relationObject.StringBaseObject())
 	}
-	query := relationObject.HasMany(&m.Source{}, DB.Debug())
+	query := relationObject.HasMany(&m.Source{}, s.db())
 
 	query = query.Where("sources.tenant_id = ?", s.TenantID)
 
@@ -64,29 +72,59 @@ func (s *sourceDaoImpl) SubCollectionList(primaryCollection interface{}, limit,
 
 func (s *sourceDaoImpl) List(limit, offset int, filters []util.Filter) ([]m.Source, int64, error) {
 	sources := make([]m.Source, 0, limit)
-	query := DB.Debug().Model(&m.Source{}).
+	query := s.db().Model(&m.Source{}).
 		Where("sources.tenant_id = ?", s.TenantID)
 
-	query, err := applyFilters(query, filters)
+	sourceTypeNames, filters, err := ExtractSourceTypeNameFilter(filters)
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
-	// getting the total count (filters included) for pagination
-	count := int64(0)
-	query.Count(&count)
+	// The source types are matched in a subquery rather than joined, so that the unqualified columns of the other
+	// filters and of the sorting don't become ambiguous.
+	if len(sourceTypeNames) > 0 {
+		query = query.Where(`"sources"."source_type_id" IN (SELECT "id" FROM "source_types" WHERE LOWER("name") IN ?)`, sourceTypeNames)
+	}
+
+	query, err = applyFilters(query, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	// getting the total count (filters included) for pagination, concurrently with the actual query since the count
+	// can be as slow as the query itself. Both statements are run on their own session so that they don't share any
+	// state.
+	var (
+		count    int64
+		countErr error
+		wg       sync.WaitGroup
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		count, countErr = countDistinct(query, "sources.id")
+	}()
 
 	// limiting + running the actual query.
-	result := query.Limit(limit).Offset(offset).Find(&sources)
+	result := query.Session(&gorm.Session{}).Limit(limit).Offset(offset).Find(&sources)
+	wg.Wait()
+
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	// The sources are still returned when just the count fails, so that they can be displayed without the total.
+	if countErr != nil {
+		logging.Log.Warnf(`[tenant_id: %d] could not count the sources: %s`, *s.TenantID, countErr)
+		count = util.UnknownCount
+	}
+
 	return sources, count, nil
 }
 
 func (s *sourceDaoImpl) ListInternal(limit, offset int, filters []util.Filter) ([]m.Source, int64, error) {
-	query := DB.Debug().
+	query := s.db().
 		Model(&m.Source{}).
 		Select(`sources.id, sources.availability_status, "Tenant".external_tenant`)
 
@@ -110,20 +148,52 @@ func (s *sourceDaoImpl) ListInternal(limit, offset int, filters []util.Filter) (
 
 func (s *sourceDaoImpl) GetById(id *int64) (*m.Source, error) {
 	src := &m.Source{ID: *id}
-	result := DB.Debug().
+	result := s.db().
 		Where("tenant_id = ?", s.TenantID).
 		First(src)
 	if result.Error != nil {
 		return nil, util.NewErrNotFound("source")
 	}
 
+	// The rollup is computed on every read, so it always reflects the current statuses of the applications.
+	applicationDao := GetApplicationDao(s.ctx, s.TenantID)
+	rollup, err := applicationDao.GetApplicationStatusRollup(src.ID, src.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	src.ApplicationStatusRollup = rollup
+
 	return src, nil
 }
 
+// GetByIdForUpdate fetches the tenant's source and locks its row with a "SELECT ... FOR UPDATE" until the given
+// transaction ends, so that read-modify-write flows don't lose any concurrent updates. It must be called with a
+// transaction, and the source must be updated through that same transaction, since otherwise the update would wait
+// for the lock held by the transaction itself.
+func (s *sourceDaoImpl) GetByIdForUpdate(tx *gorm.DB, id *int64) (*m.Source, error) {
+	var src m.Source
+	err := tx.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Where("tenant_id = ?", s.TenantID).
+		First(&src).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, util.NewErrNotFound("source")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &src, nil
+}
+
 // Function that searches for a source and preloads any specified relations
 func (s *sourceDaoImpl) GetByIdWithPreload(id *int64, preloads ...string) (*m.Source, error) {
 	src := &m.Source{ID: *id}
-	q := DB.Debug().Where("tenant_id = ?", s.TenantID)
+	q := s.db().Where("tenant_id = ?", s.TenantID)
 
 	for _, preload := range preloads {
 		q = q.Preload(preload)
@@ -136,22 +206,204 @@ func (s *sourceDaoImpl) GetByIdWithPreload(id *int64, preloads ...string) (*m.So
 	return src, nil
 }
 
+func (s *sourceDaoImpl) GetWithRelated(sourceId, tenantId int64) (*m.SourceWithRelated, error) {
+	var source m.Source
+	err := s.db().
+		Preload("Endpoints").
+		Preload("Applications").
+		Preload("Applications.ApplicationType").
+		Where("id = ?", sourceId).
+		Where("tenant_id = ?", tenantId).
+		First(&source).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, util.NewErrNotFound("source")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The connections get their tenant's sources preloaded, since the responses list the IDs of the sources they're
+	// linked to.
+	rhcConnections := make([]m.RhcConnection, 0)
+	err = s.db().
+		Model(&m.RhcConnection{}).
+		Preload("Sources", "sources.tenant_id = ?", tenantId).
+		Joins(`INNER JOIN "source_rhc_connections" "sr" ON "rhc_connections"."id" = "sr"."rhc_connection_id"`).
+		Where(`"sr"."source_id" = ?`, sourceId).
+		Where(`"sr"."tenant_id" = ?`, tenantId).
+		Order(`"rhc_connections"."id"`).
+		Find(&rhcConnections).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	applications := make([]m.ApplicationWithType, len(source.Applications))
+	for i := range source.Applications {
+		applications[i] = m.ApplicationWithType{Application: source.Applications[i]}
+	}
+
+	return &m.SourceWithRelated{
+		Source:         source,
+		Endpoints:      source.Endpoints,
+		Applications:   applications,
+		RhcConnections: rhcConnections,
+	}, nil
+}
+
+// GetByExternalId gets the tenant's source which has the given external ID.
+func (s *sourceDaoImpl) GetByExternalId(externalId string) (*m.Source, error) {
+	var src m.Source
+	result := s.db().
+		Where("external_id = ?", externalId).
+		Where("tenant_id = ?", s.TenantID).
+		First(&src)
+	if result.Error != nil {
+		return nil, util.NewErrNotFound("source")
+	}
+
+	return &src, nil
+}
+
 func (s *sourceDaoImpl) Create(src *m.Source) error {
+	err := validateCostCenter(src.CostCenter)
+	if err != nil {
+		return err
+	}
+
 	src.TenantID = *s.TenantID // the TenantID gets injected in the middleware
-	result := DB.Debug().Create(src)
-	return result.Error
+	result := s.db().Create(src)
+	return sourceWriteError(result.Error, src)
 }
 
 func (s *sourceDaoImpl) Update(src *m.Source) error {
-	result := DB.Debug().Updates(src)
-	return result.Error
+	err := validateCostCenter(src.CostCenter)
+	if err != nil {
+		return err
+	}
+
+	result := s.db().Updates(src)
+	return sourceWriteError(result.Error, src)
+}
+
+// costCenterRegexp is the pattern the sources' cost centers must match. The configuration guarantees that the pattern
+// compiles.
+var costCenterRegexp = regexp.MustCompile(config.Get().CostCenterPattern)
+
+// validateCostCenter returns an "unprocessable entity" error when the given cost center doesn't match the configured
+// pattern.
+func validateCostCenter(costCenter *string) error {
+	if costCenter == nil || costCenterRegexp.MatchString(*costCenter) {
+		return nil
+	}
+
+	return util.NewErrUnprocessableEntity(fmt.Sprintf("cost center %q does not match the pattern %q", *costCenter, costCenterRegexp.String()))
+}
+
+// ListByCostCenter lists the tenant's sources which are charged to the given cost center.
+func (s *sourceDaoImpl) ListByCostCenter(ctx context.Context, costCenter string, tenantId int64, limit, offset int) ([]m.Source, int64, error) {
+	query := s.db().
+		WithContext(ctx).
+		Model(&m.Source{}).
+		Where("cost_center = ?", costCenter).
+		Where("tenant_id = ?", tenantId)
+
+	count := int64(0)
+	err := query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sources := make([]m.Source, 0, limit)
+	err = query.
+		Order("id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&sources).
+		Error
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return sources, count, nil
+}
+
+// sourceNullableColumns holds the columns which can be explicitly set to NULL by the clients.
+var sourceNullableColumns = []string{"version", "imported", "source_ref", "external_id", "cost_center", "budget_code"}
+
+// IsClearableSourceField returns true when the given source field can be set to NULL.
+func IsClearableSourceField(field string) bool {
+	return util.SliceContainsString(sourceNullableColumns, field)
+}
+
+// validateClearableSourceFields returns a "bad request" error when any of the given fields cannot be set to NULL.
+func validateClearableSourceFields(fields []string) error {
+	for _, field := range fields {
+		if !IsClearableSourceField(field) {
+			return util.NewErrBadRequest(fmt.Sprintf("field %q cannot be set to null", field))
+		}
+	}
+
+	return nil
+}
+
+func (s *sourceDaoImpl) ClearFields(ctx context.Context, sourceId int64, tenantId int64, fields []string) error {
+	err := validateClearableSourceFields(fields)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// "Updates" skips the zero values of the structs, so the columns are set to NULL through a map instead.
+	updates := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		updates[field] = gorm.Expr("NULL")
+	}
+
+	result := s.db().
+		WithContext(ctx).
+		Model(&m.Source{}).
+		Where("id = ?", sourceId).
+		Where("tenant_id = ?", tenantId).
+		Updates(updates)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return util.NewErrNotFound("source")
+	}
+
+	return nil
+}
+
+// sourceWriteError translates the unique violations of the external ID and name indexes to conflict errors, so that
+// the clients know that the external ID or the name are already taken in their tenant.
+func sourceWriteError(err error, src *m.Source) error {
+	if isUniqueViolation(err, sourcesExternalIdIndex) {
+		return util.NewErrConflict("a source with the given external id already exists")
+	}
+
+	if isUniqueViolation(err, sourcesNameIndex) {
+		return util.NewErrConflict(fmt.Sprintf("a source with name '%s' already exists", src.Name))
+	}
+
+	return err
 }
 
 func (s *sourceDaoImpl) Delete(id *int64) (*m.Source, error) {
 	var source m.Source
 
-	result := DB.
-		Debug().
+	result := s.db().
 		Clauses(clause.Returning{}).
 		Where("id = ?", id).
 		Where("tenant_id = ?", s.TenantID).
@@ -172,9 +424,10 @@ func (s *sourceDaoImpl) Tenant() *int64 {
 	return s.TenantID
 }
 
+// NameExistsInCurrentTenant checks the name ignoring its case, just like the sources' unique name index does.
 func (s *sourceDaoImpl) NameExistsInCurrentTenant(name string) bool {
 	src := &m.Source{Name: name}
-	result := DB.Debug().Where("name = ? AND tenant_id = ?", name, s.TenantID).First(src)
+	result := s.db().Where("LOWER(name) = LOWER(?) AND tenant_id = ?", name, s.TenantID).First(src)
 
 	// If the name is found, GORM returns one row and no errors.
 	return result.Error == nil
@@ -182,7 +435,7 @@ func (s *sourceDaoImpl) NameExistsInCurrentTenant(name string) bool {
 
 func (s *sourceDaoImpl) IsSuperkey(id int64) bool {
 	var valid bool
-	result := DB.Model(&m.Source{}).
+	result := s.db().Model(&m.Source{}).
 		Select("app_creation_workflow = ?", m.AccountAuth).
 		Where("tenant_id = ?", s.TenantID).
 		Where("id = ?", id).
@@ -197,7 +450,7 @@ func (s *sourceDaoImpl) IsSuperkey(id int64) bool {
 
 func (s *sourceDaoImpl) BulkMessage(resource util.Resource) (map[string]interface{}, error) {
 	src := m.Source{ID: resource.ResourceID}
-	result := DB.Debug().Find(&src)
+	result := s.db().Find(&src)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -207,7 +460,7 @@ func (s *sourceDaoImpl) BulkMessage(resource util.Resource) (map[string]interfac
 }
 
 func (s *sourceDaoImpl) FetchAndUpdateBy(resource util.Resource, updateAttributes map[string]interface{}) (interface{}, error) {
-	result := DB.Debug().Model(&m.Source{ID: resource.ResourceID}).Updates(updateAttributes)
+	result := s.db().Model(&m.Source{ID: resource.ResourceID}).Updates(updateAttributes)
 
 	if result.RowsAffected == 0 {
 		return nil, fmt.Errorf("source not found %v", resource)
@@ -224,7 +477,7 @@ func (s *sourceDaoImpl) FetchAndUpdateBy(resource util.Resource, updateAttribute
 
 func (s *sourceDaoImpl) FindWithTenant(id *int64) (*m.Source, error) {
 	src := &m.Source{ID: *id}
-	result := DB.Debug().Preload("Tenant").Find(&src)
+	result := s.db().Preload("Tenant").Find(&src)
 
 	return src, result.Error
 }
@@ -246,7 +499,7 @@ func (s *sourceDaoImpl) ToEventJSON(resource util.Resource) ([]byte, error) {
 func (s *sourceDaoImpl) ListForRhcConnection(rhcConnectionId *int64, limit, offset int, filters []util.Filter) ([]m.Source, int64, error) {
 	sources := make([]m.Source, 0)
 
-	query := DB.Debug().
+	query := s.db().
 		Model(&m.Source{}).
 		Joins(`INNER JOIN "source_rhc_connections" "sr" ON "sources"."id" = "sr"."source_id"`).
 		Where(`"sr"."rhc_connection_id" = ?`, rhcConnectionId).
@@ -254,7 +507,7 @@ func (s *sourceDaoImpl) ListForRhcConnection(rhcConnectionId *int64, limit, offs
 
 	query, err := applyFilters(query, filters)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, util.NewErrBadRequest(err)
 	}
 
 	// Getting the total count (filters included) for pagination.
@@ -271,8 +524,8 @@ func (s *sourceDaoImpl) ListForRhcConnection(rhcConnectionId *int64, limit, offs
 }
 
 func (s *sourceDaoImpl) Pause(id int64) error {
-	err := DB.Debug().Transaction(func(tx *gorm.DB) error {
-		err := tx.Debug().
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		err := tx.
 			Model(&m.Source{}).
 			Where("id = ?", id).
 			Where("tenant_id = ?", s.TenantID).
@@ -283,7 +536,7 @@ func (s *sourceDaoImpl) Pause(id int64) error {
 			return err
 		}
 
-		err = tx.Debug().
+		err = tx.
 			Model(&m.Application{}).
 			Where("source_id = ?", id).
 			Where("tenant_id = ?", s.TenantID).
@@ -297,8 +550,8 @@ func (s *sourceDaoImpl) Pause(id int64) error {
 }
 
 func (s *sourceDaoImpl) Unpause(id int64) error {
-	err := DB.Debug().Transaction(func(tx *gorm.DB) error {
-		err := tx.Debug().
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		err := tx.
 			Model(&m.Source{}).
 			Where("id = ?", id).
 			Where("tenant_id = ?", s.TenantID).
@@ -309,7 +562,7 @@ func (s *sourceDaoImpl) Unpause(id int64) error {
 			return err
 		}
 
-		err = tx.Debug().
+		err = tx.
 			Model(&m.Application{}).
 			Where("source_id = ?", id).
 			Where("tenant_id = ?", s.TenantID).
@@ -335,122 +588,120 @@ func (s *sourceDaoImpl) DeleteCascade(sourceId int64) ([]m.ApplicationAuthentica
 	// The "len(objects) != 0" check to delete the resources is necessary to avoid Gorm issuing the "cannot batch
 	// delete without a where condition" error, since there might be times when the resources don't have any related
 	// sub resources.
-	err := DB.
-		Debug().
-		Transaction(func(tx *gorm.DB) error {
-			// Fetch and delete the application authentications.
-			err := tx.
-				Model(&m.ApplicationAuthentication{}).
-				Preload("Tenant").
-				Joins(`INNER JOIN "applications" ON "application_authentications"."application_id" = "applications"."id"`).
-				Where(`"applications"."source_id" = ?`, sourceId).
-				Where(`"applications"."tenant_id" = ?`, s.TenantID).
-				Find(&applicationAuthentications).
-				Error
-
-			if err != nil {
-				return err
-			}
-
-			if len(applicationAuthentications) != 0 {
-				err = tx.
-					Delete(&applicationAuthentications).
-					Error
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		// Fetch and delete the application authentications.
+		err := tx.
+			Model(&m.ApplicationAuthentication{}).
+			Preload("Tenant").
+			Joins(`INNER JOIN "applications" ON "application_authentications"."application_id" = "applications"."id"`).
+			Where(`"applications"."source_id" = ?`, sourceId).
+			Where(`"applications"."tenant_id" = ?`, s.TenantID).
+			Find(&applicationAuthentications).
+			Error
 
-				if err != nil {
-					return err
-				}
-			}
+		if err != nil {
+			return err
+		}
 
-			// Fetch and delete the applications.
+		if len(applicationAuthentications) != 0 {
 			err = tx.
-				Model(&m.Application{}).
-				Preload("Tenant").
-				Where("source_id = ?", sourceId).
-				Where("tenant_id = ?", s.TenantID).
-				Find(&applications).
+				Delete(&applicationAuthentications).
 				Error
 
 			if err != nil {
 				return err
 			}
+		}
 
-			if len(applications) != 0 {
-				err = tx.
-					Delete(&applications).
-					Error
+		// Fetch and delete the applications.
+		err = tx.
+			Model(&m.Application{}).
+			Preload("Tenant").
+			Where("source_id = ?", sourceId).
+			Where("tenant_id = ?", s.TenantID).
+			Find(&applications).
+			Error
 
-				if err != nil {
-					return err
-				}
-			}
+		if err != nil {
+			return err
+		}
 
-			// Fetch and delete the endpoints.
+		if len(applications) != 0 {
 			err = tx.
-				Model(m.Endpoint{}).
-				Preload("Tenant").
-				Where("source_id = ?", sourceId).
-				Where("tenant_id = ?", s.TenantID).
-				Find(&endpoints).
+				Delete(&applications).
 				Error
 
 			if err != nil {
 				return err
 			}
+		}
 
-			if len(endpoints) != 0 {
-				err = tx.
-					Delete(&endpoints).
-					Error
+		// Fetch and delete the endpoints.
+		err = tx.
+			Model(m.Endpoint{}).
+			Preload("Tenant").
+			Where("source_id = ?", sourceId).
+			Where("tenant_id = ?", s.TenantID).
+			Find(&endpoints).
+			Error
 
-				if err != nil {
-					return err
-				}
-			}
+		if err != nil {
+			return err
+		}
 
-			// Fetch and delete the rhcConnections.
+		if len(endpoints) != 0 {
 			err = tx.
-				Model(&m.RhcConnection{}).
-				Joins(`INNER JOIN "source_rhc_connections" ON "source_rhc_connections"."rhc_connection_id" = "rhc_connections"."id"`).
-				Where(`"source_rhc_connections"."source_id" = ?`, sourceId).
-				Where(`"source_rhc_connections"."tenant_id" = ?`, s.TenantID).
-				Find(&rhcConnections).
+				Delete(&endpoints).
 				Error
 
 			if err != nil {
 				return err
 			}
+		}
 
-			if len(rhcConnections) != 0 {
-				err = tx.
-					Delete(&rhcConnections).
-					Error
+		// Fetch and delete the rhcConnections.
+		err = tx.
+			Model(&m.RhcConnection{}).
+			Joins(`INNER JOIN "source_rhc_connections" ON "source_rhc_connections"."rhc_connection_id" = "rhc_connections"."id"`).
+			Where(`"source_rhc_connections"."source_id" = ?`, sourceId).
+			Where(`"source_rhc_connections"."tenant_id" = ?`, s.TenantID).
+			Find(&rhcConnections).
+			Error
 
-				if err != nil {
-					return err
-				}
-			}
+		if err != nil {
+			return err
+		}
 
-			// Fetch and delete the source itself.
+		if len(rhcConnections) != 0 {
 			err = tx.
-				Preload("Tenant").
-				Where("id = ?", sourceId).
-				Where("tenant_id = ?", s.TenantID).
-				Find(&source).
+				Delete(&rhcConnections).
 				Error
 
 			if err != nil {
 				return err
 			}
+		}
 
-			if source != nil {
-				err = tx.
-					Delete(&source).
-					Error
-			}
+		// Fetch and delete the source itself.
+		err = tx.
+			Preload("Tenant").
+			Where("id = ?", sourceId).
+			Where("tenant_id = ?", s.TenantID).
+			Find(&source).
+			Error
 
+		if err != nil {
 			return err
-		})
+		}
+
+		if source != nil {
+			err = tx.
+				Delete(&source).
+				Error
+		}
+
+		return err
+	})
 
 	if err != nil {
 		return nil, nil, nil, nil, nil, err
@@ -462,7 +713,7 @@ func (s *sourceDaoImpl) DeleteCascade(sourceId int64) ([]m.ApplicationAuthentica
 func (s *sourceDaoImpl) Exists(sourceId int64) (bool, error) {
 	var sourceExists bool
 
-	err := DB.Model(&m.Source{}).
+	err := s.db().Model(&m.Source{}).
 		Select("1").
 		Where("id = ?", sourceId).
 		Where("tenant_id = ?", s.TenantID).
@@ -475,3 +726,140 @@ func (s *sourceDaoImpl) Exists(sourceId int64) (bool, error) {
 
 	return sourceExists, nil
 }
+
+/*
+	GetDependencyGraph is assembled from a few targeted queries instead of a single recursive CTE, since the graph is
+	only two levels deep and every level is reachable through an index:
+
+	1. The source is fetched by its primary key.
+	2. The applications are fetched through "index_applications_on_source_id", and the application types are joined
+	   by their primary key.
+	3. The endpoints are fetched through "index_endpoints_on_source_id".
+	4. The connections are fetched through the leading "source_id" column of
+	   "index_source_rhc_connections_on_source_id_and_rhc_connection_id", and joined by their primary key.
+	5. The sibling sources are fetched by the connections' IDs, which results in a sequential scan of the
+	   "source_rhc_connections" table, filtered by the tenant. The table is small per tenant, and the sources are
+	   then joined by their primary key.
+
+	Every query is run in the same read only transaction, so that the graph is consistent.
+*/
+func (s *sourceDaoImpl) GetDependencyGraph(sourceId, tenantId int64) (*m.SourceDependencyGraph, error) {
+	var graph *m.SourceDependencyGraph
+
+	err := transaction(s.db(), func(tx *gorm.DB) error {
+		err := tx.Exec(`SET TRANSACTION READ ONLY`).Error
+		if err != nil {
+			return err
+		}
+
+		var source m.Source
+		err = tx.
+			Model(&m.Source{}).
+			Select(`"id"`, `"name"`).
+			Where(`"id" = ?`, sourceId).
+			Where(`"tenant_id" = ?`, tenantId).
+			First(&source).
+			Error
+
+		if err != nil {
+			return util.NewErrNotFound("source")
+		}
+
+		graph = &m.SourceDependencyGraph{
+			ID:             source.ID,
+			Name:           source.Name,
+			Applications:   make([]m.SourceDependencyApplication, 0),
+			Endpoints:      make([]m.SourceDependencyEndpoint, 0),
+			RhcConnections: make([]m.SourceDependencyRhcConnection, 0),
+		}
+
+		err = tx.
+			Table(`"applications"`).
+			Select(`"applications"."id" AS "id"`, `"application_types"."id" AS "application_type_id"`, `"application_types"."name" AS "application_type_name"`).
+			Joins(`INNER JOIN "application_types" ON "application_types"."id" = "applications"."application_type_id"`).
+			Where(`"applications"."source_id" = ?`, sourceId).
+			Where(`"applications"."tenant_id" = ?`, tenantId).
+			Order(`"applications"."id"`).
+			Scan(&graph.Applications).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Table(`"endpoints"`).
+			Select(`"id"`, `COALESCE("host", '') AS "host"`).
+			Where(`"source_id" = ?`, sourceId).
+			Where(`"tenant_id" = ?`, tenantId).
+			Order(`"id"`).
+			Scan(&graph.Endpoints).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.
+			Table(`"rhc_connections"`).
+			Select(`"rhc_connections"."id" AS "id"`, `"rhc_connections"."rhc_id" AS "rhc_id"`).
+			Joins(`INNER JOIN "source_rhc_connections" AS "sr" ON "sr"."rhc_connection_id" = "rhc_connections"."id"`).
+			Where(`"sr"."source_id" = ?`, sourceId).
+			Where(`"sr"."tenant_id" = ?`, tenantId).
+			Order(`"rhc_connections"."id"`).
+			Scan(&graph.RhcConnections).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		if len(graph.RhcConnections) == 0 {
+			return nil
+		}
+
+		rhcConnectionIds := make([]int64, 0, len(graph.RhcConnections))
+		for _, rhcConnection := range graph.RhcConnections {
+			rhcConnectionIds = append(rhcConnectionIds, rhcConnection.ID)
+		}
+
+		var siblings []struct {
+			RhcConnectionId int64
+			ID              int64
+			Name            string
+		}
+
+		err = tx.
+			Table(`"source_rhc_connections" AS "sr"`).
+			Select(`"sr"."rhc_connection_id" AS "rhc_connection_id"`, `"sources"."id" AS "id"`, `"sources"."name" AS "name"`).
+			Joins(`INNER JOIN "sources" ON "sources"."id" = "sr"."source_id"`).
+			Where(`"sr"."rhc_connection_id" IN ?`, rhcConnectionIds).
+			Where(`"sr"."source_id" != ?`, sourceId).
+			Where(`"sr"."tenant_id" = ?`, tenantId).
+			Order(`"sources"."id"`).
+			Scan(&siblings).
+			Error
+
+		if err != nil {
+			return err
+		}
+
+		for i := range graph.RhcConnections {
+			graph.RhcConnections[i].SiblingSources = make([]m.SourceDependencySource, 0)
+
+			for _, sibling := range siblings {
+				if sibling.RhcConnectionId == graph.RhcConnections[i].ID {
+					graph.RhcConnections[i].SiblingSources = append(graph.RhcConnections[i].SiblingSources, m.SourceDependencySource{ID: sibling.ID, Name: sibling.Name})
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
@@ -1,8 +1,14 @@
 package dao
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"math"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,6 +16,8 @@ import (
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
 )
 
 var sourceDao = sourceDaoImpl{
@@ -68,7 +76,7 @@ func TestPausingSource(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("pause_unpause")
 
-	sourceDao := GetSourceDao(&testSource.TenantID)
+	sourceDao := GetSourceDao(context.Background(), &testSource.TenantID)
 	err := sourceDao.Pause(testSource.ID)
 	if err != nil {
 		t.Errorf(`want nil error, got "%s"`, err)
@@ -98,7 +106,7 @@ func TestResumingSource(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("pause_unpause")
 
-	sourceDao := GetSourceDao(&testSource.TenantID)
+	sourceDao := GetSourceDao(context.Background(), &testSource.TenantID)
 	err := sourceDao.Unpause(fixtures.TestSourceData[0].ID)
 	if err != nil {
 		t.Errorf(`want nil error, got "%s"`, err)
@@ -128,7 +136,7 @@ func TestDeleteSource(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("delete")
 
-	sourceDao := GetSourceDao(&fixtures.TestSourceData[0].TenantID)
+	sourceDao := GetSourceDao(context.Background(), &fixtures.TestSourceData[0].TenantID)
 
 	source := fixtures.TestSourceData[0]
 	// Set the ID to 0 to let GORM know it should insert a new source and not update an existing one.
@@ -173,7 +181,7 @@ func TestDeleteSourceNotExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("delete")
 
-	sourceDao := GetSourceDao(&fixtures.TestSourceData[0].TenantID)
+	sourceDao := GetSourceDao(context.Background(), &fixtures.TestSourceData[0].TenantID)
 
 	nonExistentId := int64(12345)
 	_, err := sourceDao.Delete(&nonExistentId)
@@ -634,7 +642,7 @@ func TestSourceExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("exists")
 
-	sourceDao := GetSourceDao(&fixtures.TestTenantData[0].Id)
+	sourceDao := GetSourceDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	got, err := sourceDao.Exists(fixtures.TestSourceData[0].ID)
 	if err != nil {
@@ -653,7 +661,7 @@ func TestSourceNotExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("exists")
 
-	sourceDao := GetSourceDao(&fixtures.TestTenantData[0].Id)
+	sourceDao := GetSourceDao(context.Background(), &fixtures.TestTenantData[0].Id)
 
 	got, err := sourceDao.Exists(12345)
 	if err != nil {
@@ -816,3 +824,1009 @@ func TestSourceListForRhcConnectionWithOffsetAndLimit(t *testing.T) {
 	}
 	DropSchema("offset_limit")
 }
+
+// TestSourceListCreatedAtRange tests that the sources can be filtered by a "created_at" range, and that the count
+// honors it.
+func TestSourceListCreatedAtRange(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("created_at_range")
+
+	var wantCount int64
+	for _, src := range fixtures.TestSourceData {
+		if src.TenantID == *sourceDao.TenantID {
+			wantCount++
+		}
+	}
+
+	now := time.Now()
+	filters := []util.Filter{
+		{Name: "created_at", Operation: "gte", Value: []string{now.Add(-time.Hour).Format(time.RFC3339)}},
+		{Name: "created_at", Operation: "lte", Value: []string{now.Add(time.Hour).Format(time.RFC3339)}},
+	}
+
+	_, gotCount, err := sourceDao.List(100, 0, filters)
+	if err != nil {
+		t.Errorf(`unexpected error when listing the sources: %s`, err)
+	}
+
+	if wantCount != gotCount {
+		t.Errorf(`incorrect count of sources, want "%d", got "%d"`, wantCount, gotCount)
+	}
+
+	// A range in the future shouldn't match any source.
+	filters = []util.Filter{
+		{Name: "created_at", Operation: "gte", Value: []string{now.Add(time.Hour).Format(time.RFC3339)}},
+		{Name: "created_at", Operation: "lte", Value: []string{now.Add(2 * time.Hour).Format(time.RFC3339)}},
+	}
+
+	sources, gotCount, err := sourceDao.List(100, 0, filters)
+	if err != nil {
+		t.Errorf(`unexpected error when listing the sources: %s`, err)
+	}
+
+	if gotCount != 0 || len(sources) != 0 {
+		t.Errorf(`want no sources, got "%d" with a count of "%d"`, len(sources), gotCount)
+	}
+
+	DropSchema("created_at_range")
+}
+
+// TestSourceListCreatedAtInvalidDate tests that an unparseable "created_at" date results in a bad request error.
+func TestSourceListCreatedAtInvalidDate(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	filters := []util.Filter{
+		{Name: "created_at", Operation: "gte", Value: []string{"yesterday"}},
+	}
+
+	_, _, err := sourceDao.List(100, 0, filters)
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+}
+
+// TestSourceListInFilter tests that the sources can be fetched by a list of IDs.
+func TestSourceListInFilter(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	var wantIds []int64
+	var rawIds []string
+	for _, src := range fixtures.TestSourceData {
+		if src.TenantID == *sourceDao.TenantID && len(wantIds) < 2 {
+			wantIds = append(wantIds, src.ID)
+			rawIds = append(rawIds, fmt.Sprintf("%d", src.ID))
+		}
+	}
+
+	filters := []util.Filter{
+		{Name: "id", Operation: "in", Value: []string{strings.Join(rawIds, ",")}},
+		{Operation: "sort_by", Value: []string{"id"}},
+	}
+
+	sources, count, err := sourceDao.List(100, 0, filters)
+	if err != nil {
+		t.Fatalf(`want nil error, got "%s"`, err)
+	}
+
+	if count != int64(len(wantIds)) {
+		t.Errorf(`want count "%d", got "%d"`, len(wantIds), count)
+	}
+
+	var gotIds []int64
+	for _, src := range sources {
+		gotIds = append(gotIds, src.ID)
+	}
+
+	if !reflect.DeepEqual(wantIds, gotIds) {
+		t.Errorf(`want sources "%v", got "%v"`, wantIds, gotIds)
+	}
+}
+
+// TestSourceListInFilterEmpty tests that an empty "in" filter doesn't match any source.
+func TestSourceListInFilterEmpty(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	filters := []util.Filter{
+		{Name: "id", Operation: "in", Value: []string{""}},
+	}
+
+	sources, count, err := sourceDao.List(100, 0, filters)
+	if err != nil {
+		t.Fatalf(`want nil error, got "%s"`, err)
+	}
+
+	if count != 0 || len(sources) != 0 {
+		t.Errorf(`want no sources, got "%d" with a count of "%d"`, len(sources), count)
+	}
+}
+
+// TestSourceListSourceTypeNameFilter tests that the sources can be filtered by the names of their source types,
+// ignoring the case.
+func TestSourceListSourceTypeNameFilter(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	var wantIds []int64
+	for _, src := range fixtures.TestSourceData {
+		if src.TenantID == *sourceDao.TenantID && src.SourceTypeID == fixtures.TestSourceTypeData[1].Id {
+			wantIds = append(wantIds, src.ID)
+		}
+	}
+
+	filters := []util.Filter{
+		{Name: "source_type_name", Operation: "in", Value: []string{strings.ToUpper(fixtures.TestSourceTypeData[1].Name) + ",unknown"}},
+		{Operation: "sort_by", Value: []string{"id"}},
+	}
+
+	sources, count, err := sourceDao.List(100, 0, filters)
+	if err != nil {
+		t.Fatalf(`want nil error, got "%s"`, err)
+	}
+
+	if count != int64(len(wantIds)) {
+		t.Errorf(`want count "%d", got "%d"`, len(wantIds), count)
+	}
+
+	var gotIds []int64
+	for _, src := range sources {
+		gotIds = append(gotIds, src.ID)
+	}
+
+	if !reflect.DeepEqual(wantIds, gotIds) {
+		t.Errorf(`want sources "%v", got "%v"`, wantIds, gotIds)
+	}
+}
+
+// TestSourceListNullFilter tests that the "null" filter splits the sources between the paused and the active ones,
+// and that the count reflects it.
+func TestSourceListNullFilter(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("null_filter")
+
+	var tenantSources int64
+	for _, src := range fixtures.TestSourceData {
+		if src.TenantID == *sourceDao.TenantID {
+			tenantSources++
+		}
+	}
+
+	pausedId := fixtures.TestSourceData[0].ID
+	err := DB.Model(&m.Source{}).Where("id = ?", pausedId).Update("paused_at", time.Now()).Error
+	if err != nil {
+		t.Fatalf(`could not pause the source: %s`, err)
+	}
+
+	active, activeCount, err := sourceDao.List(100, 0, []util.Filter{{Name: "paused_at", Operation: "null", Value: []string{"true"}}})
+	if err != nil {
+		t.Fatalf(`want nil error, got "%s"`, err)
+	}
+
+	if activeCount != tenantSources-1 || int64(len(active)) != activeCount {
+		t.Errorf(`want "%d" active sources, got "%d" with a count of "%d"`, tenantSources-1, len(active), activeCount)
+	}
+
+	paused, pausedCount, err := sourceDao.List(100, 0, []util.Filter{{Name: "paused_at", Operation: "null", Value: []string{"false"}}})
+	if err != nil {
+		t.Fatalf(`want nil error, got "%s"`, err)
+	}
+
+	if pausedCount != 1 || len(paused) != 1 || paused[0].ID != pausedId {
+		t.Errorf(`want the source "%d" as the only paused one, got "%v" with a count of "%d"`, pausedId, paused, pausedCount)
+	}
+
+	DropSchema("null_filter")
+}
+
+// TestSourceListJoinFilterDeduplicates is a regression test which checks that the filters which join the sources'
+// applications return every source just once, even when many of its applications match the filter.
+func TestSourceListJoinFilterDeduplicates(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("join_filter")
+
+	// Every application in the fixtures belongs to the tenant, and some sources have more than one application.
+	wantIds := make(map[int64]bool)
+	for _, app := range fixtures.TestApplicationData {
+		if app.TenantID == *sourceDao.TenantID {
+			wantIds[app.SourceID] = true
+		}
+	}
+
+	filters := []util.Filter{
+		{Subresource: "application", Name: "tenant_id", Value: []string{"1"}},
+	}
+
+	sources, gotCount, err := sourceDao.List(100, 0, filters)
+	if err != nil {
+		t.Errorf(`unexpected error when listing the sources: %s`, err)
+	}
+
+	if int64(len(wantIds)) != gotCount {
+		t.Errorf(`incorrect count of sources, want "%d", got "%d"`, len(wantIds), gotCount)
+	}
+
+	seen := make(map[int64]bool)
+	for _, src := range sources {
+		if seen[src.ID] {
+			t.Errorf(`source "%d" returned more than once`, src.ID)
+		}
+		seen[src.ID] = true
+
+		if !wantIds[src.ID] {
+			t.Errorf(`unexpected source "%d" returned`, src.ID)
+		}
+	}
+
+	if len(seen) != len(wantIds) {
+		t.Errorf(`want "%d" sources, got "%d"`, len(wantIds), len(seen))
+	}
+
+	DropSchema("join_filter")
+}
+
+// TestGetDependencyGraph tests that the graph contains the source's applications, endpoints and connections, along with
+// the sources which share those connections.
+func TestGetDependencyGraph(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema(RHC_CONNECTION_SCHEMA)
+
+	source := fixtures.TestSourceData[0]
+
+	graph, err := sourceDao.GetDependencyGraph(source.ID, fixtures.TestTenantData[0].Id)
+	if err != nil {
+		t.Fatalf(`want nil error, got "%s"`, err)
+	}
+
+	var wantApplications, wantEndpoints int
+	for _, app := range fixtures.TestApplicationData {
+		if app.SourceID == source.ID && app.TenantID == source.TenantID {
+			wantApplications++
+		}
+	}
+	for _, endpoint := range fixtures.TestEndpointData {
+		if endpoint.SourceID == source.ID && endpoint.TenantID == source.TenantID {
+			wantEndpoints++
+		}
+	}
+
+	if len(graph.Applications) != wantApplications {
+		t.Errorf(`want "%d" applications, got "%d"`, wantApplications, len(graph.Applications))
+	}
+
+	for _, app := range graph.Applications {
+		if app.ApplicationTypeName == "" {
+			t.Errorf(`want the application type name of the application "%d", got an empty one`, app.ID)
+		}
+	}
+
+	if len(graph.Endpoints) != wantEndpoints {
+		t.Errorf(`want "%d" endpoints, got "%d"`, wantEndpoints, len(graph.Endpoints))
+	}
+
+	// Build the expected siblings from the links of the connections.
+	wantSiblings := make(map[int64][]int64)
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.SourceId == source.ID {
+			wantSiblings[link.RhcConnectionId] = []int64{}
+		}
+	}
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if siblings, ok := wantSiblings[link.RhcConnectionId]; ok && link.SourceId != source.ID {
+			wantSiblings[link.RhcConnectionId] = append(siblings, link.SourceId)
+		}
+	}
+
+	if len(graph.RhcConnections) != len(wantSiblings) {
+		t.Fatalf(`want "%d" connections, got "%d"`, len(wantSiblings), len(graph.RhcConnections))
+	}
+
+	for _, rhcConnection := range graph.RhcConnections {
+		var got []int64
+		for _, sibling := range rhcConnection.SiblingSources {
+			got = append(got, sibling.ID)
+		}
+
+		want := wantSiblings[rhcConnection.ID]
+		if len(want) != len(got) || (len(want) > 0 && !reflect.DeepEqual(want, got)) {
+			t.Errorf(`want sibling sources "%v" for the connection "%d", got "%v"`, want, rhcConnection.ID, got)
+		}
+	}
+
+	DropSchema(RHC_CONNECTION_SCHEMA)
+}
+
+// TestGetDependencyGraphNotFound tests that a not found error is returned for another tenant's source.
+func TestGetDependencyGraphNotFound(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema(RHC_CONNECTION_SCHEMA)
+
+	_, err := sourceDao.GetDependencyGraph(fixtures.TestSourceData[0].ID, 12345)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want not found error, got "%v"`, err)
+	}
+
+	DropSchema(RHC_CONNECTION_SCHEMA)
+}
+
+// BenchmarkGetDependencyGraph benchmarks the graph of a source with ten dependent objects: four applications, three
+// endpoints and three connections, each one shared with another source.
+func BenchmarkGetDependencyGraph(b *testing.B) {
+	if !flags.Integration {
+		b.Skip("Skipping integration benchmark")
+	}
+
+	SwitchSchema("dependency_graph")
+	tenantId := fixtures.TestTenantData[0].Id
+
+	source := m.Source{Name: "dependency graph", SourceTypeID: fixtures.TestSourceTypeData[0].Id, TenantID: tenantId, Uid: util.StringRef("dependency-graph")}
+	sibling := m.Source{Name: "dependency graph sibling", SourceTypeID: fixtures.TestSourceTypeData[0].Id, TenantID: tenantId, Uid: util.StringRef("dependency-graph-sibling")}
+	for _, src := range []*m.Source{&source, &sibling} {
+		if err := DB.Create(src).Error; err != nil {
+			b.Fatalf(`could not create the source: %s`, err)
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		app := m.Application{SourceID: source.ID, ApplicationTypeID: fixtures.TestApplicationTypeData[0].Id, TenantID: tenantId}
+		if err := DB.Create(&app).Error; err != nil {
+			b.Fatalf(`could not create the application: %s`, err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		endpoint := m.Endpoint{SourceID: source.ID, TenantID: tenantId}
+		if err := DB.Create(&endpoint).Error; err != nil {
+			b.Fatalf(`could not create the endpoint: %s`, err)
+		}
+
+		rhcConnection := m.RhcConnection{RhcId: fmt.Sprintf("dependency-graph-%d", i)}
+		if err := DB.Create(&rhcConnection).Error; err != nil {
+			b.Fatalf(`could not create the connection: %s`, err)
+		}
+
+		for _, src := range []m.Source{source, sibling} {
+			link := m.SourceRhcConnection{SourceId: src.ID, RhcConnectionId: rhcConnection.ID, TenantId: tenantId}
+			if err := DB.Create(&link).Error; err != nil {
+				b.Fatalf(`could not link the connection: %s`, err)
+			}
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := sourceDao.GetDependencyGraph(source.ID, tenantId)
+		if err != nil {
+			b.Fatalf(`want nil error, got "%s"`, err)
+		}
+	}
+	b.StopTimer()
+
+	DropSchema("dependency_graph")
+}
+
+// TestGetByIdForUpdateSerializesUpdates tests that the concurrent read-modify-write flows which lock the source don't
+// lose any updates, since they get serialized by the lock.
+func TestGetByIdForUpdateSerializesUpdates(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_for_update")
+
+	source := fixtures.TestSourceData[0]
+	const workers = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			errs <- DB.Transaction(func(tx *gorm.DB) error {
+				src, err := sourceDao.GetByIdForUpdate(tx, &source.ID)
+				if err != nil {
+					return err
+				}
+
+				// Give the other workers the chance to read the same name, which would make them lose this update
+				// if the row wasn't locked.
+				time.Sleep(10 * time.Millisecond)
+
+				return tx.
+					Model(&m.Source{}).
+					Where("id = ?", src.ID).
+					Update("name", src.Name+"x").
+					Error
+			})
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf(`want no error, got "%s"`, err)
+		}
+	}
+
+	src, err := sourceDao.GetById(&source.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	want := source.Name + strings.Repeat("x", workers)
+	if src.Name != want {
+		t.Errorf(`want name "%s" after every update, got "%s"`, want, src.Name)
+	}
+
+	DropSchema("source_for_update")
+}
+
+// TestGetByIdForUpdateNotFound tests that a "not found" error is returned for the sources of other tenants.
+func TestGetByIdForUpdateNotFound(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_for_update")
+
+	otherTenant := fixtures.TestTenantData[0].Id + 12345
+	otherTenantDao := sourceDaoImpl{TenantID: &otherTenant}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		_, err := otherTenantDao.GetByIdForUpdate(tx, &fixtures.TestSourceData[0].ID)
+		return err
+	})
+
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("source_for_update")
+}
+
+// TestSourceClearFields tests that the given nullable columns are set to NULL, and that the rest of the columns are
+// left untouched.
+func TestSourceClearFields(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_clear_fields")
+
+	source := fixtures.TestSourceData[0]
+
+	err := DB.
+		Model(&m.Source{}).
+		Where("id = ?", source.ID).
+		Updates(map[string]interface{}{"version": "1.0", "source_ref": "ref"}).
+		Error
+	if err != nil {
+		t.Fatalf(`could not set up the source: %s`, err)
+	}
+
+	err = GetSourceDao(context.Background(), &source.TenantID).ClearFields(context.Background(), source.ID, source.TenantID, []string{"version"})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	var got m.Source
+	err = DB.Where("id = ?", source.ID).First(&got).Error
+	if err != nil {
+		t.Fatalf(`could not fetch the source: %s`, err)
+	}
+
+	if got.Version != nil {
+		t.Errorf(`want a null version, got "%s"`, *got.Version)
+	}
+
+	if got.SourceRef == nil || *got.SourceRef != "ref" {
+		t.Errorf(`want the source ref "ref" to be untouched, got "%v"`, got.SourceRef)
+	}
+
+	DropSchema("source_clear_fields")
+}
+
+// TestSourceClearFieldsInvalid tests that non-nullable fields and other tenants' sources are rejected.
+func TestSourceClearFieldsInvalid(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_clear_fields")
+
+	source := fixtures.TestSourceData[0]
+	sourceDao := GetSourceDao(context.Background(), &source.TenantID)
+
+	err := sourceDao.ClearFields(context.Background(), source.ID, source.TenantID, []string{"name"})
+	if !errors.Is(err, util.ErrBadRequestEmpty) {
+		t.Errorf(`want a bad request error, got "%v"`, err)
+	}
+
+	otherTenant := source.TenantID + 12345
+	err = sourceDao.ClearFields(context.Background(), source.ID, otherTenant, []string{"version"})
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("source_clear_fields")
+}
+
+// TestValidateCostCenter tests that the cost centers are validated against the configured pattern.
+func TestValidateCostCenter(t *testing.T) {
+	testCases := []struct {
+		costCenter *string
+		valid      bool
+	}{
+		{costCenter: nil, valid: true},
+		{costCenter: util.StringRef("dept-123"), valid: true},
+		{costCenter: util.StringRef("finance.emea_2"), valid: true},
+		{costCenter: util.StringRef(""), valid: false},
+		{costCenter: util.StringRef("-dept"), valid: false},
+		{costCenter: util.StringRef("dept 123"), valid: false},
+	}
+
+	for _, tc := range testCases {
+		err := validateCostCenter(tc.costCenter)
+
+		if tc.valid && err != nil {
+			t.Errorf(`want no error for "%v", got "%s"`, tc.costCenter, err)
+		}
+
+		if !tc.valid && !errors.Is(err, util.ErrUnprocessableEntityEmpty) {
+			t.Errorf(`want an unprocessable entity error for "%s", got "%v"`, *tc.costCenter, err)
+		}
+	}
+}
+
+// TestListByCostCenter tests that only the tenant's sources charged to the given cost center are listed, and that
+// they can be filtered by their cost center too.
+func TestListByCostCenter(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_cost_center")
+
+	source := fixtures.TestSourceData[0]
+	sourceDao := GetSourceDao(context.Background(), &source.TenantID)
+
+	source.CostCenter = util.StringRef("dept-123")
+	source.BudgetCode = util.StringRef("budget-2022")
+	err := sourceDao.Update(&source)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	sources, count, err := sourceDao.ListByCostCenter(context.Background(), "dept-123", source.TenantID, 10, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 1 || len(sources) != 1 || sources[0].ID != source.ID {
+		t.Fatalf(`want source "%d" only, got "%d" sources`, source.ID, count)
+	}
+
+	if sources[0].BudgetCode == nil || *sources[0].BudgetCode != "budget-2022" {
+		t.Errorf(`want budget code "budget-2022", got "%v"`, sources[0].BudgetCode)
+	}
+
+	otherTenant := source.TenantID + 12345
+	_, count, err = sourceDao.ListByCostCenter(context.Background(), "dept-123", otherTenant, 10, 0)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 0 {
+		t.Errorf(`want no sources for another tenant, got "%d"`, count)
+	}
+
+	filters := []util.Filter{{Name: "cost_center", Value: []string{"dept-123"}}}
+	_, count, err = sourceDao.List(10, 0, filters)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if count != 1 {
+		t.Errorf(`want one source filtered by its cost center, got "%d"`, count)
+	}
+
+	DropSchema("source_cost_center")
+}
+
+// TestSourceCreateInvalidCostCenter tests that the sources with cost centers which don't match the configured pattern
+// are not created.
+func TestSourceCreateInvalidCostCenter(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_cost_center")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	source := m.Source{
+		Name:         "cost center source",
+		SourceTypeID: fixtures.TestSourceTypeData[0].Id,
+		CostCenter:   util.StringRef("invalid cost center!"),
+	}
+
+	err := GetSourceDao(context.Background(), &tenantId).Create(&source)
+	if !errors.Is(err, util.ErrUnprocessableEntityEmpty) {
+		t.Errorf(`want an unprocessable entity error, got "%v"`, err)
+	}
+
+	DropSchema("source_cost_center")
+}
+
+// sourceListCountRows is the number of sources the list benchmarks run against.
+const sourceListCountRows = 100000
+
+// sourceListCountFilters are the filters the list benchmarks use, so that both the count and the actual query have
+// to scan the sources.
+var sourceListCountFilters = []util.Filter{
+	{Name: "name", Operation: "contains_i", Value: []string{"list count"}},
+	{Operation: "sort_by", Value: []string{"name desc"}},
+}
+
+// setUpSourcesForListCount inserts the sources the list benchmarks run against.
+func setUpSourcesForListCount(b *testing.B) {
+	sources := make([]m.Source, 0, sourceListCountRows)
+	for i := 0; i < sourceListCountRows; i++ {
+		sources = append(sources, m.Source{
+			Name:         fmt.Sprintf("list count %d", i),
+			SourceTypeID: fixtures.TestSourceTypeData[0].Id,
+			TenantID:     fixtures.TestTenantData[0].Id,
+			Uid:          util.StringRef(fmt.Sprintf("list-count-%d", i)),
+		})
+	}
+
+	if err := DB.CreateInBatches(&sources, 1000).Error; err != nil {
+		b.Fatalf(`could not create the sources: %s`, err)
+	}
+}
+
+// benchmarkSourceListLatency runs the given list function on every iteration, and reports the 99th percentile of
+// its latency in milliseconds.
+func benchmarkSourceListLatency(b *testing.B, list func() error) {
+	durations := make([]time.Duration, 0, b.N)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		if err := list(); err != nil {
+			b.Fatalf(`want nil error, got "%s"`, err)
+		}
+
+		durations = append(durations, time.Since(start))
+	}
+	b.StopTimer()
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	p99 := durations[int(math.Ceil(float64(len(durations))*0.99))-1]
+
+	b.ReportMetric(float64(p99.Microseconds())/1000, "p99-ms")
+}
+
+// BenchmarkSourceListSequentialCount benchmarks listing the sources when the count runs before the actual query, as
+// "List" used to do. Compare its "p99-ms" with the one of "BenchmarkSourceListConcurrentCount".
+func BenchmarkSourceListSequentialCount(b *testing.B) {
+	if !flags.Integration {
+		b.Skip("Skipping integration benchmark")
+	}
+
+	SwitchSchema("source_list_count")
+	setUpSourcesForListCount(b)
+
+	benchmarkSourceListLatency(b, func() error {
+		query, err := applyFilters(DB.Model(&m.Source{}).Where("sources.tenant_id = ?", fixtures.TestTenantData[0].Id), sourceListCountFilters)
+		if err != nil {
+			return err
+		}
+
+		if _, err := countDistinct(query, "sources.id"); err != nil {
+			return err
+		}
+
+		sources := make([]m.Source, 0, 100)
+		return query.Limit(100).Offset(0).Find(&sources).Error
+	})
+
+	DropSchema("source_list_count")
+}
+
+// BenchmarkSourceListConcurrentCount benchmarks listing the sources when the count runs concurrently with the actual
+// query.
+func BenchmarkSourceListConcurrentCount(b *testing.B) {
+	if !flags.Integration {
+		b.Skip("Skipping integration benchmark")
+	}
+
+	SwitchSchema("source_list_count")
+	setUpSourcesForListCount(b)
+
+	benchmarkSourceListLatency(b, func() error {
+		_, count, err := GetSourceDao(context.Background(), &fixtures.TestTenantData[0].Id).List(100, 0, sourceListCountFilters)
+		if err == nil && count == util.UnknownCount {
+			err = fmt.Errorf(`the sources could not be counted`)
+		}
+
+		return err
+	})
+
+	DropSchema("source_list_count")
+}
+
+// createSourcesNameIndex creates the index the "AddSourcesNameUniqueIndex" migration creates, since the test schemas
+// are auto migrated from the models.
+func createSourcesNameIndex(t *testing.T) {
+	err := DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS "index_sources_on_lower_name_and_tenant_id" ON "sources" USING btree (LOWER("name"), "tenant_id")`).Error
+	if err != nil {
+		t.Fatalf(`could not create the sources' name index: %s`, err)
+	}
+}
+
+// assertSourceNameConflict asserts that the given error is a conflict error for the given name.
+func assertSourceNameConflict(t *testing.T, err error, name string) {
+	var errConflict util.ErrConflict
+	if !errors.As(err, &errConflict) {
+		t.Fatalf(`want a conflict error, got "%v"`, err)
+	}
+
+	want := "a source with name '" + name + "' already exists"
+	if errConflict.Message != want {
+		t.Errorf(`want message "%s", got "%s"`, want, errConflict.Message)
+	}
+}
+
+// TestSourceWriteError tests that the unique violations of the sources' name index are translated to friendly
+// conflict errors.
+func TestSourceWriteError(t *testing.T) {
+	source := &m.Source{Name: "Amazon"}
+
+	err := sourceWriteError(&pgconn.PgError{Code: uniqueViolationCode, ConstraintName: sourcesNameIndex}, source)
+	assertSourceNameConflict(t, err, source.Name)
+
+	otherErr := errors.New("some other error")
+	if err := sourceWriteError(otherErr, source); err != otherErr {
+		t.Errorf(`want the original error "%s", got "%v"`, otherErr, err)
+	}
+}
+
+// TestSourceCreateNameConflict tests that creating a source with a name which is already taken in the tenant —
+// ignoring the case— returns a conflict error, and that other tenants may use the same name.
+func TestSourceCreateNameConflict(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_name_unique")
+	createSourcesNameIndex(t)
+
+	existing := fixtures.TestSourceData[0]
+	duplicate := m.Source{Name: "SOURCE1", SourceTypeID: existing.SourceTypeID}
+
+	err := GetSourceDao(context.Background(), &existing.TenantID).Create(&duplicate)
+	assertSourceNameConflict(t, err, duplicate.Name)
+
+	otherTenant := fixtures.TestTenantData[1].Id
+	otherTenantSource := m.Source{Name: existing.Name, SourceTypeID: existing.SourceTypeID}
+
+	err = GetSourceDao(context.Background(), &otherTenant).Create(&otherTenantSource)
+	if err != nil {
+		t.Errorf(`want no error for another tenant's source with the same name, got "%s"`, err)
+	}
+
+	DropSchema("source_name_unique")
+}
+
+// TestSourceUpdateNameConflict tests that renaming a source to a name which is already taken in the tenant returns a
+// conflict error.
+func TestSourceUpdateNameConflict(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_name_unique")
+	createSourcesNameIndex(t)
+
+	renamed := fixtures.TestSourceData[1]
+	renamed.Name = fixtures.TestSourceData[0].Name
+
+	err := GetSourceDao(context.Background(), &renamed.TenantID).Update(&renamed)
+	assertSourceNameConflict(t, err, renamed.Name)
+
+	DropSchema("source_name_unique")
+}
+
+// TestSourceCreateNameOfDeletedSource tests that the name of a deleted source can be used for a new source.
+func TestSourceCreateNameOfDeletedSource(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_name_unique")
+	createSourcesNameIndex(t)
+
+	tenantId := fixtures.TestTenantData[0].Id
+	sourceDao := GetSourceDao(context.Background(), &tenantId)
+
+	source := m.Source{Name: "short lived source", SourceTypeID: fixtures.TestSourceTypeData[0].Id}
+	err := sourceDao.Create(&source)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	_, err = sourceDao.Delete(&source.ID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	recreated := m.Source{Name: source.Name, SourceTypeID: source.SourceTypeID}
+	err = sourceDao.Create(&recreated)
+	if err != nil {
+		t.Errorf(`want no error when reusing a deleted source's name, got "%s"`, err)
+	}
+
+	DropSchema("source_name_unique")
+}
+
+// TestNameExistsInCurrentTenantIgnoresCase tests that the names are validated ignoring their case, just like the
+// unique index does, and only within the tenant.
+func TestNameExistsInCurrentTenantIgnoresCase(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_name_unique")
+
+	existing := fixtures.TestSourceData[0]
+
+	if !GetSourceDao(context.Background(), &existing.TenantID).NameExistsInCurrentTenant(strings.ToUpper(existing.Name)) {
+		t.Errorf(`want the name "%s" to exist regardless of its case`, strings.ToUpper(existing.Name))
+	}
+
+	otherTenant := existing.TenantID + 12345
+	if GetSourceDao(context.Background(), &otherTenant).NameExistsInCurrentTenant(existing.Name) {
+		t.Errorf(`want the name "%s" not to exist in another tenant`, existing.Name)
+	}
+
+	DropSchema("source_name_unique")
+}
+
+// TestGetWithRelated tests that the source is returned along with all its related resources.
+func TestGetWithRelated(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_with_related")
+
+	source := fixtures.TestSourceData[0]
+
+	sourceWithRelated, err := sourceDao.GetWithRelated(source.ID, source.TenantID)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if sourceWithRelated.ID != source.ID {
+		t.Errorf(`want source "%d", got "%d"`, source.ID, sourceWithRelated.ID)
+	}
+
+	var wantEndpoints, wantApplications, wantRhcConnections int
+	for _, endpoint := range fixtures.TestEndpointData {
+		if endpoint.SourceID == source.ID {
+			wantEndpoints++
+		}
+	}
+
+	for _, app := range fixtures.TestApplicationData {
+		if app.SourceID == source.ID {
+			wantApplications++
+		}
+	}
+
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.SourceId == source.ID {
+			wantRhcConnections++
+		}
+	}
+
+	if len(sourceWithRelated.Endpoints) != wantEndpoints {
+		t.Errorf(`want "%d" endpoints, got "%d"`, wantEndpoints, len(sourceWithRelated.Endpoints))
+	}
+
+	if len(sourceWithRelated.Applications) != wantApplications {
+		t.Errorf(`want "%d" applications, got "%d"`, wantApplications, len(sourceWithRelated.Applications))
+	}
+
+	for _, app := range sourceWithRelated.Applications {
+		if app.ApplicationType.Id != app.ApplicationTypeID {
+			t.Errorf(`want application type "%d" to be loaded, got "%d"`, app.ApplicationTypeID, app.ApplicationType.Id)
+		}
+	}
+
+	if len(sourceWithRelated.RhcConnections) != wantRhcConnections {
+		t.Errorf(`want "%d" connections, got "%d"`, wantRhcConnections, len(sourceWithRelated.RhcConnections))
+	}
+
+	for _, rhcConnection := range sourceWithRelated.RhcConnections {
+		if len(rhcConnection.Sources) == 0 {
+			t.Errorf(`want the sources of connection "%d" to be loaded, got none`, rhcConnection.ID)
+		}
+	}
+
+	DropSchema("source_with_related")
+}
+
+// TestGetWithRelatedNotFound tests that a "not found" error is returned for other tenants' sources.
+func TestGetWithRelatedNotFound(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_with_related")
+
+	source := fixtures.TestSourceData[0]
+
+	_, err := sourceDao.GetWithRelated(source.ID, source.TenantID+12345)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("source_with_related")
+}
+
+// setUpSourceWithRelated creates a source with ten endpoints, five applications and a connection, and returns it.
+func setUpSourceWithRelated(b *testing.B) m.Source {
+	tenantId := fixtures.TestTenantData[0].Id
+
+	source := m.Source{Name: "source with related", SourceTypeID: fixtures.TestSourceTypeData[0].Id, TenantID: tenantId, Uid: util.StringRef("source-with-related")}
+	if err := DB.Create(&source).Error; err != nil {
+		b.Fatalf(`could not create the source: %s`, err)
+	}
+
+	for i := 0; i < 10; i++ {
+		endpoint := m.Endpoint{SourceID: source.ID, TenantID: tenantId, Host: util.StringRef(fmt.Sprintf("host-%d", i))}
+		if err := DB.Create(&endpoint).Error; err != nil {
+			b.Fatalf(`could not create the endpoint: %s`, err)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		app := m.Application{SourceID: source.ID, ApplicationTypeID: fixtures.TestApplicationTypeData[i%len(fixtures.TestApplicationTypeData)].Id, TenantID: tenantId}
+		if err := DB.Create(&app).Error; err != nil {
+			b.Fatalf(`could not create the application: %s`, err)
+		}
+	}
+
+	rhcConnection := m.RhcConnection{RhcId: "source-with-related"}
+	if err := DB.Create(&rhcConnection).Error; err != nil {
+		b.Fatalf(`could not create the connection: %s`, err)
+	}
+
+	link := m.SourceRhcConnection{SourceId: source.ID, RhcConnectionId: rhcConnection.ID, TenantId: tenantId}
+	if err := DB.Create(&link).Error; err != nil {
+		b.Fatalf(`could not link the connection: %s`, err)
+	}
+
+	return source
+}
+
+// BenchmarkSourceRelatedFanOut benchmarks fetching a source with ten endpoints and five applications with the four
+// separate calls the detail page used to make.
+func BenchmarkSourceRelatedFanOut(b *testing.B) {
+	if !flags.Integration {
+		b.Skip("Skipping integration benchmark")
+	}
+
+	SwitchSchema("source_with_related")
+	source := setUpSourceWithRelated(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := sourceDao.GetById(&source.ID); err != nil {
+			b.Fatalf(`want nil error, got "%s"`, err)
+		}
+
+		if _, _, err := GetEndpointDao(context.Background(), &source.TenantID).SubCollectionList(m.Source{ID: source.ID}, 100, 0, []util.Filter{}); err != nil {
+			b.Fatalf(`want nil error, got "%s"`, err)
+		}
+
+		if _, _, err := GetApplicationDao(context.Background(), &source.TenantID).SubCollectionList(m.Source{ID: source.ID}, 100, 0, []util.Filter{}); err != nil {
+			b.Fatalf(`want nil error, got "%s"`, err)
+		}
+
+		if _, _, err := GetRhcConnectionDao(context.Background(), &source.TenantID).ListForSource(&source.ID, 100, 0, []util.Filter{}); err != nil {
+			b.Fatalf(`want nil error, got "%s"`, err)
+		}
+	}
+	b.StopTimer()
+
+	DropSchema("source_with_related")
+}
+
+// BenchmarkGetWithRelated benchmarks fetching the same source as "BenchmarkSourceRelatedFanOut" with a single call.
+func BenchmarkGetWithRelated(b *testing.B) {
+	if !flags.Integration {
+		b.Skip("Skipping integration benchmark")
+	}
+
+	SwitchSchema("source_with_related")
+	source := setUpSourceWithRelated(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := sourceDao.GetWithRelated(source.ID, source.TenantID); err != nil {
+			b.Fatalf(`want nil error, got "%s"`, err)
+		}
+	}
+	b.StopTimer()
+
+	DropSchema("source_with_related")
+}
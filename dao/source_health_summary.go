@@ -0,0 +1,158 @@
+package dao
+
+import (
+	"strconv"
+	"time"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// sourceHealthSummaryCacheTtl is the amount of time a health summary is kept in the cache for a tenant.
+const sourceHealthSummaryCacheTtl = 60 * time.Second
+
+// sourceHealthSummaryCacheSize is the number of tenants whose health summary is kept in the cache.
+const sourceHealthSummaryCacheSize = 1024
+
+// sourceHealthOldestCheckedLimit is the number of least recently checked sources the health summary includes.
+const sourceHealthOldestCheckedLimit = 5
+
+// sourceHealthSummaryCache holds the computed health summaries, keyed by tenant ID.
+var sourceHealthSummaryCache = util.NewLruCache(sourceHealthSummaryCacheSize, sourceHealthSummaryCacheTtl)
+
+// sourcesUnavailableCount exports the number of unavailable sources of the tenants whose health summary is cached. It
+// is computed when scraped, so that it doesn't need a series per tenant.
+var sourcesUnavailableCount = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+	Name: "sources_unavailable_count",
+	Help: "Number of unavailable sources of the tenants whose health summary was computed recently",
+}, sumCachedUnavailableSources)
+
+// sumCachedUnavailableSources sums the unavailable sources of the cached health summaries.
+func sumCachedUnavailableSources() float64 {
+	var count int64
+	for _, summary := range sourceHealthSummaryCache.Values() {
+		count += summary.(*m.SourceHealthSummary).StatusCounts[m.Unavailable]
+	}
+
+	return float64(count)
+}
+
+// Kinds of the rows returned by the health summary query.
+const (
+	sourceHealthRowStatus = "status"
+	sourceHealthRowOldest = "oldest"
+)
+
+// sourceHealthRow is a row returned by the health summary query. The "status" rows hold the number of sources on an
+// availability status, and the "oldest" rows hold the least recently checked sources.
+type sourceHealthRow struct {
+	Kind               string
+	AvailabilityStatus string
+	Count              int64
+	LatestCheck        *time.Time
+	ID                 *int64
+	Name               *string
+	LastCheckedAt      *time.Time
+}
+
+// sourceHealthSummaryQuery computes both the status counts and the least recently checked sources in a single round
+// trip, ranking the tenant's sources by their last check.
+const sourceHealthSummaryQuery = `
+	WITH "ranked_sources" AS (
+		SELECT
+			"id",
+			"name",
+			COALESCE("availability_status", '') AS "availability_status",
+			"last_checked_at",
+			ROW_NUMBER() OVER (ORDER BY "last_checked_at" ASC NULLS FIRST, "id" ASC) AS "check_rank"
+		FROM "sources"
+		WHERE "tenant_id" = @tenant_id
+	)
+	SELECT
+		'status' AS "kind",
+		"availability_status",
+		COUNT(*) AS "count",
+		MAX("last_checked_at") AS "latest_check",
+		NULL::BIGINT AS "id",
+		NULL::VARCHAR AS "name",
+		NULL::TIMESTAMP AS "last_checked_at",
+		NULL::BIGINT AS "check_rank"
+	FROM "ranked_sources"
+	GROUP BY "availability_status"
+	UNION ALL
+	SELECT
+		'oldest' AS "kind",
+		"availability_status",
+		0 AS "count",
+		NULL::TIMESTAMP AS "latest_check",
+		"id",
+		"name",
+		"last_checked_at",
+		"check_rank"
+	FROM "ranked_sources"
+	WHERE "check_rank" <= @oldest_limit
+	ORDER BY "kind", "check_rank"
+`
+
+func (s *sourceDaoImpl) HealthSummary(tenantId int64) (*m.SourceHealthSummary, error) {
+	cacheKey := strconv.FormatInt(tenantId, 10)
+	if cached, ok := sourceHealthSummaryCache.Get(cacheKey); ok {
+		return cached.(*m.SourceHealthSummary), nil
+	}
+
+	var rows []sourceHealthRow
+	err := s.db().
+		Raw(sourceHealthSummaryQuery, map[string]interface{}{"tenant_id": tenantId, "oldest_limit": sourceHealthOldestCheckedLimit}).
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarizeSourceHealth(rows)
+
+	sourceHealthSummaryCache.Set(cacheKey, summary)
+
+	return summary, nil
+}
+
+// summarizeSourceHealth builds the health summary from the rows of the health summary query. The "oldest" rows are
+// expected to be sorted by their last check.
+func summarizeSourceHealth(rows []sourceHealthRow) *m.SourceHealthSummary {
+	summary := &m.SourceHealthSummary{
+		StatusCounts:  make(map[string]int64),
+		OldestChecked: make([]m.SourceHealthCheck, 0, sourceHealthOldestCheckedLimit),
+	}
+
+	for _, row := range rows {
+		switch row.Kind {
+		case sourceHealthRowStatus:
+			summary.StatusCounts[row.AvailabilityStatus] += row.Count
+			summary.Total += row.Count
+
+			if row.LatestCheck != nil && (summary.LatestCheck == nil || row.LatestCheck.After(*summary.LatestCheck)) {
+				summary.LatestCheck = row.LatestCheck
+			}
+		case sourceHealthRowOldest:
+			check := m.SourceHealthCheck{
+				AvailabilityStatus: row.AvailabilityStatus,
+				LastCheckedAt:      row.LastCheckedAt,
+			}
+
+			if row.ID != nil {
+				check.ID = *row.ID
+			}
+
+			if row.Name != nil {
+				check.Name = *row.Name
+			}
+
+			summary.OldestChecked = append(summary.OldestChecked, check)
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,122 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// TestSummarizeSourceHealth tests that the status rows are summed up, that the latest check is the most recent one
+// and that the oldest checked sources keep the order of the query.
+func TestSummarizeSourceHealth(t *testing.T) {
+	earlier := time.Date(2022, 5, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	firstId, secondId := int64(7), int64(3)
+	firstName, secondName := "never checked", "checked"
+
+	rows := []sourceHealthRow{
+		{Kind: sourceHealthRowOldest, AvailabilityStatus: "", ID: &firstId, Name: &firstName},
+		{Kind: sourceHealthRowOldest, AvailabilityStatus: m.Unavailable, ID: &secondId, Name: &secondName, LastCheckedAt: &earlier},
+		{Kind: sourceHealthRowStatus, AvailabilityStatus: m.Available, Count: 4, LatestCheck: &later},
+		{Kind: sourceHealthRowStatus, AvailabilityStatus: m.Unavailable, Count: 2, LatestCheck: &earlier},
+		{Kind: sourceHealthRowStatus, AvailabilityStatus: "", Count: 1},
+	}
+
+	got := summarizeSourceHealth(rows)
+
+	if got.Total != 7 {
+		t.Errorf(`want a total of "7" sources, got "%d"`, got.Total)
+	}
+
+	wantCounts := map[string]int64{m.Available: 4, m.Unavailable: 2, "": 1}
+	for status, want := range wantCounts {
+		if got.StatusCounts[status] != want {
+			t.Errorf(`want "%d" sources with status "%s", got "%d"`, want, status, got.StatusCounts[status])
+		}
+	}
+
+	if got.LatestCheck == nil || !got.LatestCheck.Equal(later) {
+		t.Errorf(`want latest check "%s", got "%v"`, later, got.LatestCheck)
+	}
+
+	if len(got.OldestChecked) != 2 || got.OldestChecked[0].ID != firstId || got.OldestChecked[1].ID != secondId {
+		t.Fatalf(`want the oldest checked sources "%d" and "%d", got "%+v"`, firstId, secondId, got.OldestChecked)
+	}
+
+	if got.OldestChecked[0].LastCheckedAt != nil || got.OldestChecked[0].Name != firstName {
+		t.Errorf(`want the never checked source first, got "%+v"`, got.OldestChecked[0])
+	}
+}
+
+// TestSummarizeSourceHealthNoSources tests that an empty summary is returned when the tenant doesn't have any
+// sources.
+func TestSummarizeSourceHealthNoSources(t *testing.T) {
+	got := summarizeSourceHealth(nil)
+
+	if got.Total != 0 || len(got.StatusCounts) != 0 || got.LatestCheck != nil || len(got.OldestChecked) != 0 {
+		t.Errorf(`want an empty summary, got "%+v"`, *got)
+	}
+}
+
+// TestHealthSummary tests that the summary is computed from the tenant's sources only.
+func TestHealthSummary(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_health_summary")
+
+	tenantId := fixtures.TestTenantData[0].Id
+
+	var wantTotal, wantUnavailable int64
+	for _, source := range fixtures.TestSourceData {
+		if source.TenantID != tenantId {
+			continue
+		}
+
+		wantTotal++
+		if source.AvailabilityStatus == m.Unavailable {
+			wantUnavailable++
+		}
+	}
+
+	summary, err := GetSourceDao(context.Background(), &tenantId).HealthSummary(tenantId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if summary.Total != wantTotal {
+		t.Errorf(`want a total of "%d" sources, got "%d"`, wantTotal, summary.Total)
+	}
+
+	if summary.StatusCounts[m.Unavailable] != wantUnavailable {
+		t.Errorf(`want "%d" unavailable sources, got "%d"`, wantUnavailable, summary.StatusCounts[m.Unavailable])
+	}
+
+	wantOldest := wantTotal
+	if wantOldest > sourceHealthOldestCheckedLimit {
+		wantOldest = sourceHealthOldestCheckedLimit
+	}
+
+	if int64(len(summary.OldestChecked)) != wantOldest {
+		t.Errorf(`want "%d" oldest checked sources, got "%d"`, wantOldest, len(summary.OldestChecked))
+	}
+
+	DropSchema("source_health_summary")
+}
+
+// TestSumCachedUnavailableSources tests that the exported gauge sums the unavailable sources of every cached summary.
+func TestSumCachedUnavailableSources(t *testing.T) {
+	sourceHealthSummaryCache.Flush()
+	defer sourceHealthSummaryCache.Flush()
+
+	sourceHealthSummaryCache.Set("1", &m.SourceHealthSummary{StatusCounts: map[string]int64{m.Unavailable: 2, m.Available: 5}})
+	sourceHealthSummaryCache.Set("2", &m.SourceHealthSummary{StatusCounts: map[string]int64{m.Unavailable: 3}})
+	sourceHealthSummaryCache.Set("3", &m.SourceHealthSummary{StatusCounts: map[string]int64{}})
+
+	if got := sumCachedUnavailableSources(); got != 5 {
+		t.Errorf(`want "5" unavailable sources, got "%v"`, got)
+	}
+}
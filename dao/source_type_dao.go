@@ -1,17 +1,18 @@
 package dao
 
 import (
+	"context"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
 )
 
 // GetSourceTypeDao is a function definition that can be replaced in runtime in case some other DAO provider is
 // needed.
-var GetSourceTypeDao func() SourceTypeDao
+var GetSourceTypeDao func(context.Context) SourceTypeDao
 
 // getDefaultRhcConnectionDao gets the default DAO implementation which will have the given tenant ID.
-func getDefaultSourceTypeDao() SourceTypeDao {
-	return &sourceTypeDaoImpl{}
+func getDefaultSourceTypeDao(ctx context.Context) SourceTypeDao {
+	return &sourceTypeDaoImpl{requestContext: requestContext{ctx: ctx}}
 }
 
 // init sets the default DAO implementation so that other packages can request it easily.
@@ -20,15 +21,26 @@ func init() {
 }
 
 type sourceTypeDaoImpl struct {
+	requestContext
 }
 
 func (st *sourceTypeDaoImpl) List(limit, offset int, filters []util.Filter) ([]m.SourceType, int64, error) {
 	// allocating a slice of source types, initial length of
 	// 0, size of limit (since we will not be returning more than that)
 	sourceTypes := make([]m.SourceType, 0, limit)
-	query := DB.Debug().Model(&m.SourceType{})
+	query := st.db().Model(&m.SourceType{})
 
-	query, err := applyFilters(query, filters)
+	// the source types can be narrowed down to the ones compatible with an application type.
+	appTypeId, filters, err := extractIdFilter(filters, "application_type_id")
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	if appTypeId != nil {
+		query = query.Where(sourceTypeSupportedByApplicationType, *appTypeId)
+	}
+
+	query, err = applyFilters(query, filters)
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
@@ -48,26 +60,57 @@ func (st *sourceTypeDaoImpl) List(limit, offset int, filters []util.Filter) ([]m
 
 func (st *sourceTypeDaoImpl) GetById(id *int64) (*m.SourceType, error) {
 	sourceType := &m.SourceType{Id: *id}
-	result := DB.Debug().First(sourceType)
+	result := st.db().First(sourceType)
 	if result.Error != nil {
 		return nil, util.NewErrNotFound("source type")
 	}
 	return sourceType, nil
 }
 
+func (st *sourceTypeDaoImpl) ListCompatibleApplicationTypes(sourceTypeId int64) ([]m.ApplicationType, error) {
+	applicationTypes := make([]m.ApplicationType, 0)
+	result := st.db().
+		Model(&m.ApplicationType{}).
+		Where(applicationTypeSupportingSourceType, sourceTypeId).
+		Order("application_types.id").
+		Find(&applicationTypes)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return applicationTypes, nil
+}
+
 func (st *sourceTypeDaoImpl) GetByName(name string) (*m.SourceType, error) {
 	sourceType := &m.SourceType{}
-	result := DB.Debug().Where("name LIKE ?", "%"+name+"%").First(sourceType)
+	result := st.db().Where("name LIKE ?", "%"+name+"%").First(sourceType)
 
 	return sourceType, result.Error
 }
 
+func (st *sourceTypeDaoImpl) ExistingNames(names []string) ([]string, error) {
+	existing := make([]string, 0)
+	if len(names) == 0 {
+		return existing, nil
+	}
+
+	err := st.db().
+		Model(&m.SourceType{}).
+		Where("LOWER(name) IN ?", names).
+		Pluck("LOWER(name)", &existing).
+		Error
+
+	return existing, err
+}
+
 func (a *sourceTypeDaoImpl) Create(_ *m.SourceType) error {
 	panic("not needed (yet) due to seeding.")
 }
 
-func (st *sourceTypeDaoImpl) Update(_ *m.SourceType) error {
-	panic("not needed (yet) due to seeding.")
+func (st *sourceTypeDaoImpl) Update(sourceType *m.SourceType) error {
+	result := st.db().Updates(sourceType)
+	return result.Error
 }
 
 func (st *sourceTypeDaoImpl) Delete(_ *int64) error {
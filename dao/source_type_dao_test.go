@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
@@ -14,7 +15,7 @@ func TestSourceTypeListOffsetAndLimit(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("offset_limit")
 
-	sourceTypeDao := GetSourceTypeDao()
+	sourceTypeDao := GetSourceTypeDao(context.Background())
 	wantCount := int64(len(fixtures.TestSourceTypeData))
 
 	for _, d := range fixtures.TestDataOffsetLimit {
@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetSourceTypeFlagDao is a function definition that can be replaced in runtime in case some other DAO provider is
+// needed.
+var GetSourceTypeFlagDao func(context.Context) SourceTypeFlagDao
+
+// getDefaultSourceTypeFlagDao gets the default DAO implementation.
+func getDefaultSourceTypeFlagDao(ctx context.Context) SourceTypeFlagDao {
+	return &sourceTypeFlagDaoImpl{requestContext: requestContext{ctx: ctx}}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetSourceTypeFlagDao = getDefaultSourceTypeFlagDao
+}
+
+// sourceTypeFlagNameRegex is the format the names of the capabilities must follow.
+var sourceTypeFlagNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// validateSourceTypeFlags returns an error when any of the given capabilities' names doesn't follow the expected
+// format.
+func validateSourceTypeFlags(flags map[string]bool) error {
+	if len(flags) == 0 {
+		return fmt.Errorf("at least one capability is required")
+	}
+
+	for name := range flags {
+		if !sourceTypeFlagNameRegex.MatchString(name) {
+			return fmt.Errorf(`invalid capability "%s": it must start with a lowercase letter, and contain up to 64 lowercase letters, digits or "_"`, name)
+		}
+	}
+
+	return nil
+}
+
+type sourceTypeFlagDaoImpl struct {
+	requestContext
+}
+
+func (s *sourceTypeFlagDaoImpl) GetAll(sourceTypeId int64) ([]m.SourceTypeFlag, error) {
+	err := s.ensureSourceTypeExists(s.db(), sourceTypeId)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make([]m.SourceTypeFlag, 0)
+	err = s.db().
+		Where("source_type_id = ?", sourceTypeId).
+		Order("name").
+		Find(&flags).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+func (s *sourceTypeFlagDaoImpl) BulkSet(sourceTypeId int64, flags map[string]bool) error {
+	err := validateSourceTypeFlags(flags)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	sourceTypeFlags := make([]m.SourceTypeFlag, 0, len(flags))
+	for name, enabled := range flags {
+		sourceTypeFlags = append(sourceTypeFlags, m.SourceTypeFlag{SourceTypeId: sourceTypeId, Name: name, Enabled: enabled})
+	}
+
+	return transaction(s.db(), func(tx *gorm.DB) error {
+		err := s.ensureSourceTypeExists(tx, sourceTypeId)
+		if err != nil {
+			return err
+		}
+
+		// Setting the same flags again leaves them as they were, so the requests can safely be retried.
+		return tx.
+			Omit(clause.Associations).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "source_type_id"}, {Name: "name"}},
+				DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+			}).
+			Create(&sourceTypeFlags).
+			Error
+	})
+}
+
+// ensureSourceTypeExists returns a "not found" error when the given source type doesn't exist.
+func (s *sourceTypeFlagDaoImpl) ensureSourceTypeExists(db *gorm.DB, sourceTypeId int64) error {
+	var sourceTypeExists bool
+	err := db.
+		Model(&m.SourceType{}).
+		Select("1").
+		Where("id = ?", sourceTypeId).
+		Scan(&sourceTypeExists).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	if !sourceTypeExists {
+		return util.NewErrNotFound("source type")
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestValidateSourceTypeFlags tests that the capabilities' names must follow the expected format.
+func TestValidateSourceTypeFlags(t *testing.T) {
+	err := validateSourceTypeFlags(map[string]bool{"cost_management": true, "topology2": false})
+	if err != nil {
+		t.Errorf(`want no error, got "%s"`, err)
+	}
+
+	invalidFlags := []map[string]bool{
+		{},
+		{"Cost": true},
+		{"2fa": true},
+		{"cost-management": true},
+	}
+
+	for _, flags := range invalidFlags {
+		if err := validateSourceTypeFlags(flags); err == nil {
+			t.Errorf(`want an error for "%v", got none`, flags)
+		}
+	}
+}
+
+// TestSourceTypeFlagBulkSet tests that setting the flags is idempotent, and that it leaves the other flags alone.
+func TestSourceTypeFlagBulkSet(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_type_flags")
+
+	sourceTypeId := fixtures.TestSourceTypeData[0].Id
+	sourceTypeFlagDao := GetSourceTypeFlagDao(context.Background())
+
+	err := sourceTypeFlagDao.BulkSet(sourceTypeId, map[string]bool{"cost_management": true, "topology": true})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	// Setting the same flags again must not fail.
+	for i := 0; i < 2; i++ {
+		err = sourceTypeFlagDao.BulkSet(sourceTypeId, map[string]bool{"topology": false})
+		if err != nil {
+			t.Fatalf(`want no error, got "%s"`, err)
+		}
+	}
+
+	flags, err := sourceTypeFlagDao.GetAll(sourceTypeId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	capabilities := m.ToSourceTypeCapabilities(flags)
+	if len(capabilities) != 2 || !capabilities["cost_management"] || capabilities["topology"] {
+		t.Errorf(`want "cost_management" enabled and "topology" disabled, got "%v"`, capabilities)
+	}
+
+	DropSchema("source_type_flags")
+}
+
+// TestSourceTypeFlagNotFound tests that a "not found" error is returned for nonexistent source types.
+func TestSourceTypeFlagNotFound(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("source_type_flags")
+
+	_, err := GetSourceTypeFlagDao(context.Background()).GetAll(12345)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	err = GetSourceTypeFlagDao(context.Background()).BulkSet(12345, map[string]bool{"topology": true})
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("source_type_flags")
+}
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/redhatinsights/platform-go-middlewares/identity"
@@ -8,11 +9,11 @@ import (
 
 // GetTenantDao is a function definition that can be replaced in runtime in case some other DAO provider is
 // needed.
-var GetTenantDao func() TenantDao
+var GetTenantDao func(context.Context) TenantDao
 
 // getDefaultRhcConnectionDao gets the default DAO implementation which will have the given tenant ID.
-func getDefaultTenantDao() TenantDao {
-	return &tenantDaoImpl{}
+func getDefaultTenantDao(ctx context.Context) TenantDao {
+	return &tenantDaoImpl{requestContext: requestContext{ctx: ctx}}
 }
 
 // init sets the default DAO implementation so that other packages can request it easily.
@@ -20,13 +21,14 @@ func init() {
 	GetTenantDao = getDefaultTenantDao
 }
 
-type tenantDaoImpl struct{}
+type tenantDaoImpl struct {
+	requestContext
+}
 
 func (t *tenantDaoImpl) GetOrCreateTenantID(identity *identity.Identity) (int64, error) {
 	// Try to find the tenant.
 	var tenant m.Tenant
-	err := DB.
-		Debug().
+	err := t.db().
 		Model(&m.Tenant{}).
 		Where("org_id = ? AND org_id != ''", identity.OrgID).
 		Or("external_tenant = ? AND external_tenant != ''", identity.AccountNumber).
@@ -38,8 +40,7 @@ func (t *tenantDaoImpl) GetOrCreateTenantID(identity *identity.Identity) (int64,
 		tenant.ExternalTenant = identity.AccountNumber
 		tenant.OrgID = identity.OrgID
 
-		err := DB.
-			Debug().
+		err := t.db().
 			Create(&tenant).
 			Error
 
@@ -54,8 +55,7 @@ func (t *tenantDaoImpl) GetOrCreateTenantID(identity *identity.Identity) (int64,
 func (t *tenantDaoImpl) TenantByIdentity(id *identity.Identity) (*m.Tenant, error) {
 	var tenant m.Tenant
 
-	err := DB.
-		Debug().
+	err := t.db().
 		Model(&m.Tenant{}).
 		Where("org_id = ? AND org_id != ''", id.OrgID).
 		Or("external_tenant = ? AND external_tenant != ''", id.AccountNumber).
@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
@@ -22,7 +23,7 @@ func TestGetOrCreateTenantIDEbsNumberCreate(t *testing.T) {
 		AccountNumber: accountNumber,
 	}
 
-	tenantDao := GetTenantDao()
+	tenantDao := GetTenantDao(context.Background())
 
 	id, err := tenantDao.GetOrCreateTenantID(&identityStruct)
 	if err != nil {
@@ -31,7 +32,6 @@ func TestGetOrCreateTenantIDEbsNumberCreate(t *testing.T) {
 
 	var tenant model.Tenant
 	err = DB.
-		Debug().
 		Model(&model.Tenant{}).
 		Where(`id = ?`, id).
 		First(&tenant).
@@ -61,7 +61,7 @@ func TestGetOrCreateTenantIDEbsNumberFind(t *testing.T) {
 		AccountNumber: fixtures.TestTenantData[0].ExternalTenant,
 	}
 
-	tenantDao := GetTenantDao()
+	tenantDao := GetTenantDao(context.Background())
 
 	id, err := tenantDao.GetOrCreateTenantID(&identityStruct)
 	if err != nil {
@@ -70,7 +70,6 @@ func TestGetOrCreateTenantIDEbsNumberFind(t *testing.T) {
 
 	var tenant model.Tenant
 	err = DB.
-		Debug().
 		Model(&model.Tenant{}).
 		Where(`id = ?`, id).
 		First(&tenant).
@@ -100,7 +99,7 @@ func TestGetOrCreateTenantIDOrgIdCreate(t *testing.T) {
 		OrgID: orgId,
 	}
 
-	tenantDao := GetTenantDao()
+	tenantDao := GetTenantDao(context.Background())
 
 	id, err := tenantDao.GetOrCreateTenantID(&identityStruct)
 	if err != nil {
@@ -109,7 +108,6 @@ func TestGetOrCreateTenantIDOrgIdCreate(t *testing.T) {
 
 	var tenant model.Tenant
 	err = DB.
-		Debug().
 		Model(&model.Tenant{}).
 		Where(`id = ?`, id).
 		First(&tenant).
@@ -138,7 +136,7 @@ func TestGetOrCreateTenantIDOrgIdFind(t *testing.T) {
 		OrgID: fixtures.TestTenantData[0].OrgID,
 	}
 
-	tenantDao := GetTenantDao()
+	tenantDao := GetTenantDao(context.Background())
 
 	id, err := tenantDao.GetOrCreateTenantID(&identityStruct)
 	if err != nil {
@@ -147,7 +145,6 @@ func TestGetOrCreateTenantIDOrgIdFind(t *testing.T) {
 
 	var tenant model.Tenant
 	err = DB.
-		Debug().
 		Model(&model.Tenant{}).
 		Where(`id = ?`, id).
 		First(&tenant).
@@ -172,7 +169,7 @@ func TestTenantByIdentity(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("tenant_tests")
 
-	tenantDao := GetTenantDao()
+	tenantDao := GetTenantDao(context.Background())
 
 	// Call the function under test by fetching by EBS account number.
 	tenant, err := tenantDao.TenantByIdentity(&identity.Identity{
@@ -216,7 +213,7 @@ func TestTenantByIdentityNotFound(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	SwitchSchema("tenant_tests")
 
-	tenantDao := GetTenantDao()
+	tenantDao := GetTenantDao(context.Background())
 
 	// Call the function under test by providing it an invalid account number.
 	_, err := tenantDao.TenantByIdentity(&identity.Identity{
@@ -0,0 +1,189 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/redhatinsights/platform-go-middlewares/identity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// onboardedTenantsTtl is the amount of time an onboarded tenant is kept in the cache.
+const onboardedTenantsTtl = 5 * time.Minute
+
+// GetTenantOnboardingDao is a function definition that can be replaced in runtime in case some other DAO provider
+// is needed.
+var GetTenantOnboardingDao func(context.Context) TenantOnboardingDao
+
+// getDefaultTenantOnboardingDao gets the default DAO implementation.
+func getDefaultTenantOnboardingDao(ctx context.Context) TenantOnboardingDao {
+	return &tenantOnboardingDaoImpl{requestContext: requestContext{ctx: ctx}}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetTenantOnboardingDao = getDefaultTenantOnboardingDao
+}
+
+// TenantCreatedPublisher publishes the "tenant.created" events.
+type TenantCreatedPublisher interface {
+	PublishTenantCreated(tenant *m.Tenant) error
+}
+
+// TenantPublisher is the publisher used when a tenant gets onboarded. It can be replaced in runtime to either plug
+// in a real publisher or a mocked one for the tests. When nil, no events are published.
+var TenantPublisher TenantCreatedPublisher
+
+// onboardedTenantsCache holds the tenants which have already been onboarded, keyed by their OrgId, so that the
+// database doesn't get hit on every request.
+type onboardedTenantsCache struct {
+	mutex   sync.Mutex
+	entries map[string]onboardedTenantsEntry
+}
+
+type onboardedTenantsEntry struct {
+	tenant    m.Tenant
+	expiresAt time.Time
+}
+
+var onboardedTenants = onboardedTenantsCache{entries: make(map[string]onboardedTenantsEntry)}
+
+// get returns a copy of the cached tenant, if it's present and it hasn't expired.
+func (otc *onboardedTenantsCache) get(orgId string) (*m.Tenant, bool) {
+	otc.mutex.Lock()
+	defer otc.mutex.Unlock()
+
+	entry, ok := otc.entries[orgId]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(otc.entries, orgId)
+		return nil, false
+	}
+
+	tenant := entry.tenant
+	return &tenant, true
+}
+
+func (otc *onboardedTenantsCache) set(tenant *m.Tenant) {
+	otc.mutex.Lock()
+	defer otc.mutex.Unlock()
+
+	otc.entries[tenant.OrgID] = onboardedTenantsEntry{tenant: *tenant, expiresAt: time.Now().Add(onboardedTenantsTtl)}
+}
+
+type tenantOnboardingDaoImpl struct {
+	requestContext
+}
+
+func (t *tenantOnboardingDaoImpl) EnsureOnboarded(id *identity.Identity) (*m.Tenant, error) {
+	orgId := id.OrgID
+	if orgId == "" {
+		return nil, errors.New("an OrgId is required to onboard a tenant")
+	}
+
+	if tenant, ok := onboardedTenants.get(orgId); ok {
+		return tenant, nil
+	}
+
+	var tenant m.Tenant
+	err := t.findTenant(&tenant, id)
+
+	created := false
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		created, err = t.insertTenant(&tenant, id)
+	case err == nil && tenant.OrgID == "":
+		err = t.backfillOrgId(&tenant, orgId)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	quotaDao := GetTenantQuotaDao(t.ctx, &tenant.Id)
+	_, err = quotaDao.GetOrCreate(&m.TenantQuota{
+		MaxSources:      config.Get().DefaultTenantMaxSources,
+		MaxApplications: config.Get().DefaultTenantMaxApplications,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if created && TenantPublisher != nil {
+		err = TenantPublisher.PublishTenantCreated(&tenant)
+		if err != nil {
+			logging.Log.Warnf(`[org_id: %s] Unable to publish the "tenant.created" event: %s`, orgId, err)
+		}
+	}
+
+	onboardedTenants.set(&tenant)
+
+	return &tenant, nil
+}
+
+// findTenant fetches the tenant of the identity the same way "GetOrCreateTenantID" does: by its OrgId, or by its EBS
+// account number for the legacy tenants which haven't got an OrgId yet. The tenant matching the OrgId is preferred
+// when both exist.
+func (t *tenantOnboardingDaoImpl) findTenant(tenant *m.Tenant, id *identity.Identity) error {
+	err := t.db().
+		Model(&m.Tenant{}).
+		Where("org_id = ?", id.OrgID).
+		First(tenant).
+		Error
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) || id.AccountNumber == "" {
+		return err
+	}
+
+	return t.db().
+		Model(&m.Tenant{}).
+		Where("external_tenant = ?", id.AccountNumber).
+		First(tenant).
+		Error
+}
+
+// backfillOrgId stores the OrgId of a legacy tenant which was only known by its EBS account number, so that it keeps
+// its sources once its requests start carrying an OrgId.
+func (t *tenantOnboardingDaoImpl) backfillOrgId(tenant *m.Tenant, orgId string) error {
+	err := t.db().
+		Model(&m.Tenant{}).
+		Where("id = ?", tenant.Id).
+		Where("org_id IS NULL OR org_id = ''").
+		Update("org_id", orgId).
+		Error
+
+	if err != nil {
+		return err
+	}
+
+	tenant.OrgID = orgId
+	return nil
+}
+
+// insertTenant creates the tenant of the given identity, and returns true if the tenant was actually inserted.
+// Another request might be onboarding the same tenant, in which case the insert does nothing and the tenant that
+// request created gets fetched instead.
+func (t *tenantOnboardingDaoImpl) insertTenant(tenant *m.Tenant, id *identity.Identity) (bool, error) {
+	*tenant = m.Tenant{OrgID: id.OrgID, ExternalTenant: id.AccountNumber}
+	result := t.db().
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(tenant)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+
+	err := t.findTenant(tenant, id)
+
+	return false, err
+}
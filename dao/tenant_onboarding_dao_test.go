@@ -0,0 +1,149 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/redhatinsights/platform-go-middlewares/identity"
+)
+
+// fakeTenantPublisher stores the tenants it was asked to publish instead of sending them anywhere.
+type fakeTenantPublisher struct {
+	tenants []m.Tenant
+}
+
+func (f *fakeTenantPublisher) PublishTenantCreated(tenant *m.Tenant) error {
+	f.tenants = append(f.tenants, *tenant)
+	return nil
+}
+
+// TestOnboardedTenantsCache tests that the cached tenants are returned until they expire.
+func TestOnboardedTenantsCache(t *testing.T) {
+	cache := onboardedTenantsCache{entries: make(map[string]onboardedTenantsEntry)}
+
+	if _, ok := cache.get("org"); ok {
+		t.Errorf(`want no tenant from an empty cache, got one`)
+	}
+
+	cache.set(&m.Tenant{Id: 5, OrgID: "org"})
+
+	tenant, ok := cache.get("org")
+	if !ok || tenant.Id != 5 {
+		t.Errorf(`want the tenant with id "5" from the cache, got "%v"`, tenant)
+	}
+
+	cache.entries["org"] = onboardedTenantsEntry{tenant: *tenant, expiresAt: time.Now().Add(-time.Second)}
+
+	if _, ok := cache.get("org"); ok {
+		t.Errorf(`want no tenant after the entry expired, got one`)
+	}
+}
+
+// TestEnsureOnboarded tests that a new tenant is created along with its default quota, and that the "tenant.created"
+// event is only published the first time.
+func TestEnsureOnboarded(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("tenant_onboarding_tests")
+
+	originalPublisher := TenantPublisher
+	publisher := &fakeTenantPublisher{}
+	TenantPublisher = publisher
+	defer func() { TenantPublisher = originalPublisher }()
+
+	orgId := "onboarding-org-id"
+	tenant, err := GetTenantOnboardingDao(context.Background()).EnsureOnboarded(&identity.Identity{OrgID: orgId})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if tenant.OrgID != orgId {
+		t.Errorf(`want tenant with OrgId "%s", got "%s"`, orgId, tenant.OrgID)
+	}
+
+	var quota m.TenantQuota
+	err = DB.
+		Model(&m.TenantQuota{}).
+		Where("tenant_id = ?", tenant.Id).
+		First(&quota).
+		Error
+
+	if err != nil {
+		t.Fatalf(`want no error fetching the quota, got "%s"`, err)
+	}
+
+	if quota.MaxSources != config.Get().DefaultTenantMaxSources {
+		t.Errorf(`want max sources "%d", got "%d"`, config.Get().DefaultTenantMaxSources, quota.MaxSources)
+	}
+
+	// Onboarding the tenant again, even without the cache, must return the same tenant without raising the event.
+	onboardedTenants = onboardedTenantsCache{entries: make(map[string]onboardedTenantsEntry)}
+
+	again, err := GetTenantOnboardingDao(context.Background()).EnsureOnboarded(&identity.Identity{OrgID: orgId})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if again.Id != tenant.Id {
+		t.Errorf(`want tenant id "%d", got "%d"`, tenant.Id, again.Id)
+	}
+
+	if len(publisher.tenants) != 1 {
+		t.Errorf(`want one "tenant.created" event, got "%d"`, len(publisher.tenants))
+	}
+
+	DropSchema("tenant_onboarding_tests")
+}
+
+// TestEnsureOnboardedLegacyTenant tests that a tenant which was only known by its EBS account number keeps its ID,
+// and gets the OrgId stored, once its requests start carrying an OrgId.
+func TestEnsureOnboardedLegacyTenant(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("tenant_onboarding_tests")
+
+	originalPublisher := TenantPublisher
+	publisher := &fakeTenantPublisher{}
+	TenantPublisher = publisher
+	defer func() { TenantPublisher = originalPublisher }()
+
+	legacy := m.Tenant{ExternalTenant: "legacy-account-number"}
+	err := DB.Create(&legacy).Error
+	if err != nil {
+		t.Fatalf(`could not create the legacy tenant: %s`, err)
+	}
+
+	id := &identity.Identity{AccountNumber: legacy.ExternalTenant, OrgID: "legacy-org-id"}
+	tenant, err := GetTenantOnboardingDao(context.Background()).EnsureOnboarded(id)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if tenant.Id != legacy.Id {
+		t.Errorf(`want the legacy tenant "%d", got "%d"`, legacy.Id, tenant.Id)
+	}
+
+	var stored m.Tenant
+	err = DB.Where("id = ?", legacy.Id).First(&stored).Error
+	if err != nil {
+		t.Fatalf(`could not fetch the legacy tenant: %s`, err)
+	}
+
+	if stored.OrgID != id.OrgID || stored.ExternalTenant != id.AccountNumber {
+		t.Errorf(`want OrgId "%s" and account number "%s", got "%s" and "%s"`, id.OrgID, id.AccountNumber, stored.OrgID, stored.ExternalTenant)
+	}
+
+	var count int64
+	DB.Model(&m.Tenant{}).Where("org_id = ? OR external_tenant = ?", id.OrgID, id.AccountNumber).Count(&count)
+	if count != 1 {
+		t.Errorf(`want a single tenant for the identity, got "%d"`, count)
+	}
+
+	if len(publisher.tenants) != 0 {
+		t.Errorf(`want no "tenant.created" events for an existing tenant, got "%d"`, len(publisher.tenants))
+	}
+
+	DropSchema("tenant_onboarding_tests")
+}
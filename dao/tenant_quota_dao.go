@@ -0,0 +1,57 @@
+package dao
+
+import (
+	"context"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"gorm.io/gorm/clause"
+)
+
+// GetTenantQuotaDao is a function definition that can be replaced in runtime in case some other DAO provider is
+// needed.
+var GetTenantQuotaDao func(context.Context, *int64) TenantQuotaDao
+
+// getDefaultTenantQuotaDao gets the default DAO implementation which will have the given tenant ID.
+func getDefaultTenantQuotaDao(ctx context.Context, tenantId *int64) TenantQuotaDao {
+	return &tenantQuotaDaoImpl{
+		requestContext: requestContext{ctx: ctx},
+		TenantID:       tenantId,
+	}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetTenantQuotaDao = getDefaultTenantQuotaDao
+}
+
+type tenantQuotaDaoImpl struct {
+	TenantID *int64
+	requestContext
+}
+
+func (t *tenantQuotaDaoImpl) GetOrCreate(defaults *m.TenantQuota) (*m.TenantQuota, error) {
+	quota := *defaults
+	quota.TenantId = *t.TenantID
+
+	// Concurrent requests might try to create the quota at the same time, so the conflicts are ignored and the stored
+	// quota is fetched afterwards.
+	err := t.db().
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&quota).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.db().
+		Model(&m.TenantQuota{}).
+		Where("tenant_id = ?", t.TenantID).
+		First(&quota).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &quota, nil
+}
@@ -0,0 +1,118 @@
+package dao
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// tenantStatsCacheTtl is the amount of time the statistics of a tenant are kept in the cache.
+const tenantStatsCacheTtl = 2 * time.Minute
+
+// tenantStatsCache holds the computed statistics, keyed by tenant ID.
+var tenantStatsCache sync.Map
+
+// cachedTenantStats is the cache entry for a tenant's statistics.
+type cachedTenantStats struct {
+	stats     *m.TenantStats
+	expiresAt time.Time
+}
+
+// GetTenantStatsDao is a function definition that can be replaced in runtime in case some other DAO provider is
+// needed.
+var GetTenantStatsDao func(context.Context) TenantStatsDao
+
+// getDefaultTenantStatsDao gets the default DAO implementation.
+func getDefaultTenantStatsDao(ctx context.Context) TenantStatsDao {
+	return &tenantStatsDaoImpl{requestContext: requestContext{ctx: ctx}}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetTenantStatsDao = getDefaultTenantStatsDao
+}
+
+type tenantStatsDaoImpl struct {
+	requestContext
+}
+
+// tenantStatsRow is the row returned by the statistics query.
+type tenantStatsRow struct {
+	TenantExists    bool
+	Sources         int64
+	Applications    int64
+	Endpoints       int64
+	Authentications int64
+	RhcConnections  int64
+}
+
+// tenantStatsQuery counts every resource type of the tenant in a single round trip. The connections don't have a
+// tenant of their own, so they are counted through the links to the tenant's sources.
+const tenantStatsQuery = `
+	WITH
+		"tenant_count" AS (SELECT COUNT(*) AS "count" FROM "tenants" WHERE "id" = @tenant_id),
+		"sources_count" AS (SELECT COUNT(*) AS "count" FROM "sources" WHERE "tenant_id" = @tenant_id),
+		"applications_count" AS (SELECT COUNT(*) AS "count" FROM "applications" WHERE "tenant_id" = @tenant_id),
+		"endpoints_count" AS (SELECT COUNT(*) AS "count" FROM "endpoints" WHERE "tenant_id" = @tenant_id),
+		"authentications_count" AS (SELECT COUNT(*) AS "count" FROM "authentications" WHERE "tenant_id" = @tenant_id),
+		"rhc_connections_count" AS (
+			SELECT COUNT(DISTINCT "rhc_connection_id") AS "count" FROM "source_rhc_connections" WHERE "tenant_id" = @tenant_id
+		)
+	SELECT
+		"tenant_count"."count" > 0 AS "tenant_exists",
+		"sources_count"."count" AS "sources",
+		"applications_count"."count" AS "applications",
+		"endpoints_count"."count" AS "endpoints",
+		"authentications_count"."count" AS "authentications",
+		"rhc_connections_count"."count" AS "rhc_connections"
+	FROM
+		"tenant_count",
+		"sources_count",
+		"applications_count",
+		"endpoints_count",
+		"authentications_count",
+		"rhc_connections_count"
+`
+
+func (t *tenantStatsDaoImpl) GetStats(tenantId int64) (*m.TenantStats, error) {
+	if cached, ok := tenantStatsCache.Load(tenantId); ok {
+		entry := cached.(cachedTenantStats)
+		if time.Now().Before(entry.expiresAt) {
+			return entry.stats, nil
+		}
+	}
+
+	var row tenantStatsRow
+	err := t.db().
+		Raw(tenantStatsQuery, map[string]interface{}{"tenant_id": tenantId}).
+		Scan(&row).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !row.TenantExists {
+		return nil, util.NewErrNotFound("tenant")
+	}
+
+	stats := &m.TenantStats{
+		TenantId:        tenantId,
+		Sources:         row.Sources,
+		Applications:    row.Applications,
+		Endpoints:       row.Endpoints,
+		Authentications: row.Authentications,
+		RhcConnections:  row.RhcConnections,
+		GeneratedAt:     time.Now(),
+	}
+
+	tenantStatsCache.Store(tenantId, cachedTenantStats{
+		stats:     stats,
+		expiresAt: time.Now().Add(tenantStatsCacheTtl),
+	})
+
+	return stats, nil
+}
@@ -0,0 +1,72 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestGetStats tests that the resources of the tenant are counted, and that the statistics are cached.
+func TestGetStats(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("tenant_stats")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	tenantStatsCache.Delete(tenantId)
+
+	stats, err := GetTenantStatsDao(context.Background()).GetStats(tenantId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	var wantSources int64
+	for _, source := range fixtures.TestSourceData {
+		if source.TenantID == tenantId {
+			wantSources++
+		}
+	}
+
+	if stats.Sources != wantSources {
+		t.Errorf(`want "%d" sources, got "%d"`, wantSources, stats.Sources)
+	}
+
+	var wantApplications int64
+	for _, application := range fixtures.TestApplicationData {
+		if application.TenantID == tenantId {
+			wantApplications++
+		}
+	}
+
+	if stats.Applications != wantApplications {
+		t.Errorf(`want "%d" applications, got "%d"`, wantApplications, stats.Applications)
+	}
+
+	cached, err := GetTenantStatsDao(context.Background()).GetStats(tenantId)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if !cached.GeneratedAt.Equal(stats.GeneratedAt) {
+		t.Errorf(`want the cached statistics generated at "%s", got "%s"`, stats.GeneratedAt, cached.GeneratedAt)
+	}
+
+	tenantStatsCache.Delete(tenantId)
+	DropSchema("tenant_stats")
+}
+
+// TestGetStatsNotFound tests that a "not found" error is returned for the tenants which don't exist.
+func TestGetStatsNotFound(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("tenant_stats")
+
+	_, err := GetTenantStatsDao(context.Background()).GetStats(12345)
+	if !errors.Is(err, util.ErrNotFoundEmpty) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	DropSchema("tenant_stats")
+}
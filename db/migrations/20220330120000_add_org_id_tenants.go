@@ -26,7 +26,6 @@ func AddOrgIdToTenants() *gormigrate.Migration {
 				// Add the comment to the "external_tenant" column manually, since doing it in the struct with a "gorm"
 				// tag makes "gormigrate" try to create the already existing column.
 				err := tx.
-					Debug().
 					Exec(`COMMENT ON COLUMN "tenants"."external_tenant" IS 'EBS account number'`).
 					Error
 
@@ -35,7 +34,6 @@ func AddOrgIdToTenants() *gormigrate.Migration {
 				}
 
 				err = tx.
-					Debug().
 					AutoMigrate(&Tenant{})
 
 				if err != nil {
@@ -51,7 +49,6 @@ func AddOrgIdToTenants() *gormigrate.Migration {
 			// Remove the "org_id" column and remove the comment from the "external_tenant" column.
 			err := db.Transaction(func(tx *gorm.DB) error {
 				err := tx.
-					Debug().
 					Migrator().
 					DropColumn(&Tenant{}, "org_id")
 
@@ -62,7 +59,6 @@ func AddOrgIdToTenants() *gormigrate.Migration {
 				// Remove the comment from the "external_tenant" column manually, since doing it in the struct with a
 				// "gorm" tag makes "gormigrate" try to create the already existing column.
 				err = tx.
-					Debug().
 					Exec(`COMMENT ON COLUMN "tenants"."external_tenant" IS NULL`).
 					Error
 
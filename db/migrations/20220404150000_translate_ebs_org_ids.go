@@ -32,7 +32,6 @@ func TranslateEbsAccountNumbersToOrgIds() *gormigrate.Migration {
 			// Get all the EBS account numbers from the database.
 			var ebsAccountNumbers []string
 			err := db.
-				Debug().
 				Model(&Tenant{}).
 				Where("external_tenant IS NOT NULL").
 				Pluck("external_tenant", &ebsAccountNumbers).
@@ -60,7 +59,6 @@ func TranslateEbsAccountNumbersToOrgIds() *gormigrate.Migration {
 				}
 
 				dbResult := db.
-					Debug().
 					Model(&Tenant{}).
 					Where("external_tenant = ?", result.EAN).
 					Updates(map[string]interface{}{
@@ -26,7 +26,7 @@ func SourceTypesAddCategoryColumn() *gormigrate.Migration {
 			logging.Log.Info(`Migration "source types: add category column" started`)
 			defer logging.Log.Info(`Migration "source types: add category column" ended`)
 
-			err := db.Debug().Transaction(func(tx *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
 				// Create the new column.
 				err := tx.AutoMigrate(&SourceType{})
 				if err != nil {
@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddSourceAvailabilityNotifyTrigger adds a trigger which sends a notification through the "source_availability"
+// channel every time a source's availability status changes, so that the listeners can react to the change without
+// having to poll the database.
+func AddSourceAvailabilityNotifyTrigger() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "20220510120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add source availability notify trigger" started`)
+			defer logging.Log.Info(`Migration "add source availability notify trigger" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Exec(`
+					CREATE OR REPLACE FUNCTION "notify_source_availability"() RETURNS TRIGGER AS $$
+					BEGIN
+						PERFORM pg_notify(
+							'source_availability',
+							json_build_object(
+								'source_id', NEW."id",
+								'status', NEW."availability_status",
+								'tenant_id', NEW."tenant_id"
+							)::TEXT
+						);
+
+						RETURN NEW;
+					END;
+					$$ LANGUAGE plpgsql;
+				`).Error
+
+				if err != nil {
+					return err
+				}
+
+				return tx.Exec(`
+					CREATE TRIGGER "sources_availability_status_notify"
+						AFTER UPDATE OF "availability_status" ON "sources"
+						FOR EACH ROW
+						WHEN (OLD."availability_status" IS DISTINCT FROM NEW."availability_status")
+						EXECUTE PROCEDURE "notify_source_availability"();
+				`).Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Exec(`DROP TRIGGER IF EXISTS "sources_availability_status_notify" ON "sources"`).Error
+				if err != nil {
+					return err
+				}
+
+				return tx.Exec(`DROP FUNCTION IF EXISTS "notify_source_availability"()`).Error
+			})
+
+			return err
+		},
+	}
+}
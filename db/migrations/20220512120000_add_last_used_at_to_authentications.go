@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func AddLastUsedAtToAuthentications() *gormigrate.Migration {
+	type Authentication struct {
+		LastUsedAt *time.Time
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220512120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add last used at to authentications" started`)
+			defer logging.Log.Info(`Migration "add last used at to authentications" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().AddColumn(&Authentication{}, "LastUsedAt")
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&Authentication{}, "LastUsedAt")
+			})
+
+			return err
+		},
+	}
+}
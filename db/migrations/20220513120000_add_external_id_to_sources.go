@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// sourcesExternalIdIndex is the name of the index which makes the external IDs unique per tenant.
+const sourcesExternalIdIndex = "index_sources_on_tenant_id_and_external_id"
+
+func AddExternalIdToSources() *gormigrate.Migration {
+	type Source struct {
+		TenantID   int64   `gorm:"uniqueIndex:index_sources_on_tenant_id_and_external_id,priority:1"`
+		ExternalId *string `gorm:"size:255;uniqueIndex:index_sources_on_tenant_id_and_external_id,priority:2"`
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220513120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add external id to sources" started`)
+			defer logging.Log.Info(`Migration "add external id to sources" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Migrator().AddColumn(&Source{}, "ExternalId")
+				if err != nil {
+					return err
+				}
+
+				return tx.Migrator().CreateIndex(&Source{}, sourcesExternalIdIndex)
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Migrator().DropIndex(&Source{}, sourcesExternalIdIndex)
+				if err != nil {
+					return err
+				}
+
+				return tx.Migrator().DropColumn(&Source{}, "ExternalId")
+			})
+
+			return err
+		},
+	}
+}
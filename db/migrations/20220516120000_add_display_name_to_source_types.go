@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func AddDisplayNameToSourceTypes() *gormigrate.Migration {
+	type SourceType struct {
+		DisplayName string
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220516120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add display name to source types" started`)
+			defer logging.Log.Info(`Migration "add display name to source types" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().AddColumn(&SourceType{}, "DisplayName")
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&SourceType{}, "DisplayName")
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddTenantQuotas creates the "tenant_quotas" table, and adds a unique index on the tenants' "org_id" column so that
+// the tenants can be safely onboarded by concurrent requests.
+func AddTenantQuotas() *gormigrate.Migration {
+	type TenantQuota struct {
+		TenantId        int64 `gorm:"primaryKey"`
+		MaxSources      int   `gorm:"not null"`
+		MaxApplications int   `gorm:"not null"`
+		CreatedAt       time.Time
+		UpdatedAt       time.Time
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220517120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add tenant quotas" started`)
+			defer logging.Log.Info(`Migration "add tenant quotas" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Migrator().
+					CreateTable(&TenantQuota{})
+
+				if err != nil {
+					return err
+				}
+
+				err = tx.
+					Exec(`ALTER TABLE "tenant_quotas" ADD CONSTRAINT "fk_tenant_quotas_tenant" FOREIGN KEY ("tenant_id") REFERENCES "tenants"("id") ON DELETE CASCADE`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				// The anemic tenants have an empty "org_id", so they are left out of the index.
+				return tx.
+					Exec(`CREATE UNIQUE INDEX "tenants_org_id_unique" ON "tenants" ("org_id") WHERE "org_id" != ''`).
+					Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Exec(`DROP INDEX IF EXISTS "tenants_org_id_unique"`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Migrator().
+					DropTable(&TenantQuota{})
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddAvailabilitySchedules creates the "availability_schedules" table, which holds when the sources' availability gets
+// periodically checked.
+func AddAvailabilitySchedules() *gormigrate.Migration {
+	type AvailabilitySchedule struct {
+		SourceId  int64     `gorm:"primaryKey"`
+		TenantId  int64     `gorm:"not null; index"`
+		CronExpr  string    `gorm:"not null"`
+		NextRunAt time.Time `gorm:"not null; index"`
+		LastRunAt *time.Time
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220518120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add availability schedules" started`)
+			defer logging.Log.Info(`Migration "add availability schedules" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Migrator().
+					CreateTable(&AvailabilitySchedule{})
+
+				if err != nil {
+					return err
+				}
+
+				err = tx.
+					Exec(`ALTER TABLE "availability_schedules" ADD CONSTRAINT "fk_availability_schedules_source" FOREIGN KEY ("source_id") REFERENCES "sources"("id") ON DELETE CASCADE`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Exec(`ALTER TABLE "availability_schedules" ADD CONSTRAINT "fk_availability_schedules_tenant" FOREIGN KEY ("tenant_id") REFERENCES "tenants"("id") ON DELETE CASCADE`).
+					Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.
+					Migrator().
+					DropTable(&AvailabilitySchedule{})
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddSourceAvailabilityChanges creates the "source_availability_changes" table, along with a trigger which records
+// every change of the sources' availability status in it, so that the sources' uptime can be computed.
+func AddSourceAvailabilityChanges() *gormigrate.Migration {
+	type SourceAvailabilityChange struct {
+		ID                 int64     `gorm:"primaryKey"`
+		SourceId           int64     `gorm:"not null; index:source_availability_changes_source_id_changed_at"`
+		TenantId           int64     `gorm:"not null"`
+		AvailabilityStatus string    `gorm:"not null"`
+		ChangedAt          time.Time `gorm:"not null; index:source_availability_changes_source_id_changed_at"`
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220519120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add source availability changes" started`)
+			defer logging.Log.Info(`Migration "add source availability changes" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Migrator().
+					CreateTable(&SourceAvailabilityChange{})
+
+				if err != nil {
+					return err
+				}
+
+				err = tx.
+					Exec(`ALTER TABLE "source_availability_changes" ADD CONSTRAINT "fk_source_availability_changes_source" FOREIGN KEY ("source_id") REFERENCES "sources"("id") ON DELETE CASCADE`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				err = tx.
+					Exec(`ALTER TABLE "source_availability_changes" ADD CONSTRAINT "fk_source_availability_changes_tenant" FOREIGN KEY ("tenant_id") REFERENCES "tenants"("id") ON DELETE CASCADE`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				err = tx.Exec(`
+					CREATE OR REPLACE FUNCTION "record_source_availability_change"() RETURNS TRIGGER AS $$
+					BEGIN
+						IF TG_OP = 'UPDATE' AND OLD."availability_status" IS NOT DISTINCT FROM NEW."availability_status" THEN
+							RETURN NEW;
+						END IF;
+
+						INSERT INTO "source_availability_changes" ("source_id", "tenant_id", "availability_status", "changed_at")
+						VALUES (NEW."id", NEW."tenant_id", COALESCE(NEW."availability_status", ''), NOW());
+
+						RETURN NEW;
+					END;
+					$$ LANGUAGE plpgsql;
+				`).Error
+
+				if err != nil {
+					return err
+				}
+
+				return tx.Exec(`
+					CREATE TRIGGER "sources_availability_status_record"
+						AFTER INSERT OR UPDATE OF "availability_status" ON "sources"
+						FOR EACH ROW
+						EXECUTE PROCEDURE "record_source_availability_change"();
+				`).Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Exec(`DROP TRIGGER IF EXISTS "sources_availability_status_record" ON "sources"`).Error
+				if err != nil {
+					return err
+				}
+
+				err = tx.Exec(`DROP FUNCTION IF EXISTS "record_source_availability_change"()`).Error
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Migrator().
+					DropTable(&SourceAvailabilityChange{})
+			})
+
+			return err
+		},
+	}
+}
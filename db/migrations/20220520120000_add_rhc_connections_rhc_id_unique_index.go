@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddRhcConnectionsRhcIdUniqueIndex makes sure that the "rhc_id" column of the connections is unique. The initial
+// schema already creates the index, but the databases which weren't created from it might lack it, which would allow
+// concurrent requests to register the same "rhc_id" more than once.
+func AddRhcConnectionsRhcIdUniqueIndex() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "20220520120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add rhc connections rhc id unique index" started`)
+			defer logging.Log.Info(`Migration "add rhc connections rhc id unique index" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS "index_rhc_connections_on_rhc_id" ON "rhc_connections" USING btree ("rhc_id")`).Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			// The index is kept, since it is part of the initial schema as well.
+			return nil
+		},
+	}
+}
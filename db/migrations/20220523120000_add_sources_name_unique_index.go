@@ -0,0 +1,86 @@
+package migrations
+
+import (
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// sourcesNameIndex is the name of the index which makes the sources' names unique within each tenant.
+const sourcesNameIndex = "index_sources_on_lower_name_and_tenant_id"
+
+// renamedSource is a source which got renamed because its name clashed with another source's name of the same tenant.
+type renamedSource struct {
+	Id       int64
+	TenantId int64
+	Name     string
+}
+
+// AddSourcesNameUniqueIndex makes the sources' names unique —ignoring the case— within each tenant. The sources are
+// hard deleted, so the deleted sources never block the creation of new sources with the same name.
+//
+// The names used to be validated case-sensitively, so a tenant might already have sources whose names only differ in
+// their case. Those sources get renamed first, keeping the oldest source's name untouched and appending the ID to the
+// rest, and every renamed source gets logged. The index is then built concurrently, outside any transaction, so that
+// the sources table doesn't get locked for writes while the index is built.
+func AddSourcesNameUniqueIndex() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "20220523120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add sources name unique index" started`)
+			defer logging.Log.Info(`Migration "add sources name unique index" ended`)
+
+			// Rename the sources with duplicated names.
+			var renamedSources []renamedSource
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Raw(`
+					WITH "duplicates" AS (
+						SELECT "id", ROW_NUMBER() OVER (PARTITION BY "tenant_id", LOWER("name") ORDER BY "id") AS "position"
+						FROM "sources"
+					)
+					UPDATE "sources"
+					SET "name" = "sources"."name" || ' (' || "sources"."id" || ')'
+					FROM "duplicates"
+					WHERE "duplicates"."id" = "sources"."id" AND "duplicates"."position" > 1
+					RETURNING "sources"."id", "sources"."tenant_id", "sources"."name"
+				`).Scan(&renamedSources).Error
+			})
+
+			if err != nil {
+				return err
+			}
+
+			for _, source := range renamedSources {
+				logging.Log.Warnf(`[tenant_id: %d][source_id: %d] Source renamed to "%s" because its name was duplicated`, source.TenantId, source.Id, source.Name)
+			}
+
+			// A previously failed concurrent build leaves an invalid index behind, which "IF NOT EXISTS" would keep.
+			var invalidIndex bool
+			err = db.Raw(`
+				SELECT EXISTS (
+					SELECT 1
+					FROM "pg_index"
+					INNER JOIN "pg_class" ON "pg_class"."oid" = "pg_index"."indexrelid"
+					WHERE "pg_class"."relname" = ? AND NOT "pg_index"."indisvalid"
+				)
+			`, sourcesNameIndex).Scan(&invalidIndex).Error
+
+			if err != nil {
+				return err
+			}
+
+			if invalidIndex {
+				err = db.Exec(`DROP INDEX CONCURRENTLY IF EXISTS "` + sourcesNameIndex + `"`).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			// Perform the migration. "CREATE INDEX CONCURRENTLY" can't run inside a transaction.
+			return db.Exec(`CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS "` + sourcesNameIndex + `" ON "sources" USING btree (LOWER("name"), "tenant_id")`).Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec(`DROP INDEX CONCURRENTLY IF EXISTS "` + sourcesNameIndex + `"`).Error
+		},
+	}
+}
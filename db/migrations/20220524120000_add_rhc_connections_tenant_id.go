@@ -0,0 +1,117 @@
+package migrations
+
+import (
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddRhcConnectionsTenantId scopes the connections to the tenants, so that two tenants can register the same
+// "rhc_id" without sharing a connection. The existing connections are assigned to the tenant which linked them
+// first, and the connections linked by more than one tenant are copied over for the rest of the tenants.
+func AddRhcConnectionsTenantId() *gormigrate.Migration {
+	// sharedLink represents a tenant which is linked to a connection that is assigned to another tenant.
+	type sharedLink struct {
+		RhcConnectionId int64
+		TenantId        int64
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220524120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add rhc connections tenant id" started`)
+			defer logging.Log.Info(`Migration "add rhc connections tenant id" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Exec(`ALTER TABLE "rhc_connections" ADD COLUMN IF NOT EXISTS "tenant_id" BIGINT REFERENCES "tenants"("id") ON DELETE CASCADE`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				// The connections which aren't linked to any sources keep a NULL tenant.
+				err = tx.
+					Exec(`UPDATE "rhc_connections" SET "tenant_id" = (SELECT MIN("tenant_id") FROM "source_rhc_connections" WHERE "rhc_connection_id" = "rhc_connections"."id") WHERE "tenant_id" IS NULL`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				var sharedLinks []sharedLink
+				err = tx.
+					Raw(`SELECT DISTINCT "src"."rhc_connection_id", "src"."tenant_id" FROM "source_rhc_connections" AS "src" INNER JOIN "rhc_connections" AS "rc" ON "rc"."id" = "src"."rhc_connection_id" WHERE "src"."tenant_id" != "rc"."tenant_id"`).
+					Scan(&sharedLinks).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				for _, link := range sharedLinks {
+					var copyId int64
+					err = tx.
+						Raw(`INSERT INTO "rhc_connections" ("rhc_id", "extra", "availability_status", "availability_status_error", "last_checked_at", "last_available_at", "created_at", "updated_at", "tenant_id") SELECT "rhc_id", "extra", "availability_status", "availability_status_error", "last_checked_at", "last_available_at", "created_at", "updated_at", ? FROM "rhc_connections" WHERE "id" = ? RETURNING "id"`, link.TenantId, link.RhcConnectionId).
+						Scan(&copyId).
+						Error
+
+					if err != nil {
+						return err
+					}
+
+					err = tx.
+						Exec(`UPDATE "source_rhc_connections" SET "rhc_connection_id" = ? WHERE "rhc_connection_id" = ? AND "tenant_id" = ?`, copyId, link.RhcConnectionId, link.TenantId).
+						Error
+
+					if err != nil {
+						return err
+					}
+				}
+
+				err = tx.
+					Exec(`DROP INDEX IF EXISTS "index_rhc_connections_on_rhc_id"`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Exec(`CREATE UNIQUE INDEX IF NOT EXISTS "index_rhc_connections_on_tenant_id_and_rhc_id" ON "rhc_connections" USING btree ("tenant_id", "rhc_id")`).
+					Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Exec(`DROP INDEX IF EXISTS "index_rhc_connections_on_tenant_id_and_rhc_id"`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				err = tx.
+					Exec(`ALTER TABLE "rhc_connections" DROP COLUMN IF EXISTS "tenant_id"`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				// The global index cannot be restored if different tenants have registered the same "rhc_id" in the
+				// meantime, in which case the rollback fails.
+				return tx.
+					Exec(`CREATE UNIQUE INDEX IF NOT EXISTS "index_rhc_connections_on_rhc_id" ON "rhc_connections" USING btree ("rhc_id")`).
+					Error
+			})
+
+			return err
+		},
+	}
+}
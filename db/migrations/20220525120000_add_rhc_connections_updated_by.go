@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddRhcConnectionsUpdatedBy adds the "updated_by" column to the connections, which records the last actor that
+// created or modified them, along with an index to look up what a given actor has recently changed.
+func AddRhcConnectionsUpdatedBy() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "20220525120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add rhc connections updated by" started`)
+			defer logging.Log.Info(`Migration "add rhc connections updated by" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Exec(`ALTER TABLE "rhc_connections" ADD COLUMN IF NOT EXISTS "updated_by" CHARACTER VARYING`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Exec(`CREATE INDEX IF NOT EXISTS "index_rhc_connections_on_tenant_id_and_updated_by" ON "rhc_connections" USING btree ("tenant_id", "updated_by", "updated_at")`).
+					Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Exec(`DROP INDEX IF EXISTS "index_rhc_connections_on_tenant_id_and_updated_by"`).
+					Error
+
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Exec(`ALTER TABLE "rhc_connections" DROP COLUMN IF EXISTS "updated_by"`).
+					Error
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddRhcConnectionStatusEvents creates the "rhc_connection_status_events" table, which keeps the history of the
+// changes of the connections' availability statuses.
+func AddRhcConnectionStatusEvents() *gormigrate.Migration {
+	type RhcConnectionStatusEvent struct {
+		ID              int64     `gorm:"primaryKey"`
+		RhcConnectionId int64     `gorm:"not null; index:rhc_connection_status_events_rhc_connection_id_changed_at"`
+		PreviousStatus  string    `gorm:"not null"`
+		NewStatus       string    `gorm:"not null"`
+		ChangedAt       time.Time `gorm:"not null; index:rhc_connection_status_events_rhc_connection_id_changed_at"`
+		ChangedBy       string
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220526120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add rhc connection status events" started`)
+			defer logging.Log.Info(`Migration "add rhc connection status events" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Migrator().
+					CreateTable(&RhcConnectionStatusEvent{})
+
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Exec(`ALTER TABLE "rhc_connection_status_events" ADD CONSTRAINT "fk_rhc_connection_status_events_rhc_connection" FOREIGN KEY ("rhc_connection_id") REFERENCES "rhc_connections"("id") ON DELETE CASCADE`).
+					Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.
+					Migrator().
+					DropTable(&RhcConnectionStatusEvent{})
+			})
+
+			return err
+		},
+	}
+}
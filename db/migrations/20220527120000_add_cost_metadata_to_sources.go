@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// sourcesCostCenterIndex is the name of the index used to look up the tenant's sources by their cost center.
+const sourcesCostCenterIndex = "index_sources_on_tenant_id_and_cost_center"
+
+// AddCostMetadataToSources adds the "cost_center" and "budget_code" columns to the sources, so that they can be
+// charged back to the teams which own them.
+func AddCostMetadataToSources() *gormigrate.Migration {
+	type Source struct {
+		TenantID   int64   `gorm:"index:index_sources_on_tenant_id_and_cost_center,priority:1"`
+		CostCenter *string `gorm:"size:255;index:index_sources_on_tenant_id_and_cost_center,priority:2"`
+		BudgetCode *string `gorm:"size:255"`
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220527120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add cost metadata to sources" started`)
+			defer logging.Log.Info(`Migration "add cost metadata to sources" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Migrator().AddColumn(&Source{}, "CostCenter")
+				if err != nil {
+					return err
+				}
+
+				err = tx.Migrator().AddColumn(&Source{}, "BudgetCode")
+				if err != nil {
+					return err
+				}
+
+				return tx.Migrator().CreateIndex(&Source{}, sourcesCostCenterIndex)
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Migrator().DropIndex(&Source{}, sourcesCostCenterIndex)
+				if err != nil {
+					return err
+				}
+
+				err = tx.Migrator().DropColumn(&Source{}, "BudgetCode")
+				if err != nil {
+					return err
+				}
+
+				return tx.Migrator().DropColumn(&Source{}, "CostCenter")
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// authenticationsExpiresAtIndex is the name of the index used to look up the tenants' authentications which are about
+// to expire.
+const authenticationsExpiresAtIndex = "index_authentications_on_tenant_id_and_expires_at"
+
+// AddExpiresAtToAuthentications adds the "expires_at" column to the authentications, so that the users can be warned
+// before their credentials expire.
+func AddExpiresAtToAuthentications() *gormigrate.Migration {
+	type Authentication struct {
+		TenantId  int64      `gorm:"index:index_authentications_on_tenant_id_and_expires_at,priority:1"`
+		ExpiresAt *time.Time `gorm:"index:index_authentications_on_tenant_id_and_expires_at,priority:2"`
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220528120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add expires at to authentications" started`)
+			defer logging.Log.Info(`Migration "add expires at to authentications" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Migrator().AddColumn(&Authentication{}, "ExpiresAt")
+				if err != nil {
+					return err
+				}
+
+				return tx.Migrator().CreateIndex(&Authentication{}, authenticationsExpiresAtIndex)
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Migrator().DropIndex(&Authentication{}, authenticationsExpiresAtIndex)
+				if err != nil {
+					return err
+				}
+
+				return tx.Migrator().DropColumn(&Authentication{}, "ExpiresAt")
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddKafkaOffsets creates the "kafka_offsets" table, which keeps track of the messages the consumer groups have
+// processed from each topic's partition.
+func AddKafkaOffsets() *gormigrate.Migration {
+	type KafkaOffset struct {
+		ID            int64     `gorm:"primaryKey"`
+		ConsumerGroup string    `gorm:"not null; uniqueIndex:index_kafka_offsets_on_consumer_group_and_topic_and_partition,priority:1"`
+		Topic         string    `gorm:"not null; uniqueIndex:index_kafka_offsets_on_consumer_group_and_topic_and_partition,priority:2"`
+		Partition     int       `gorm:"not null; uniqueIndex:index_kafka_offsets_on_consumer_group_and_topic_and_partition,priority:3"`
+		Offset        int64     `gorm:"not null"`
+		UpdatedAt     time.Time `gorm:"not null"`
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220529120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add kafka offsets" started`)
+			defer logging.Log.Info(`Migration "add kafka offsets" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.
+					Migrator().
+					CreateTable(&KafkaOffset{})
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.
+					Migrator().
+					DropTable(&KafkaOffset{})
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddPausedBeforeArchiveToApplications adds the nullable "paused_before_archive" column to the applications, which
+// marks the ones that got paused along with their archived source, so that resuming the source leaves alone the ones
+// which had been paused on their own.
+func AddPausedBeforeArchiveToApplications() *gormigrate.Migration {
+	type Application struct {
+		PausedBeforeArchive *bool
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220530120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add paused before archive to applications" started`)
+			defer logging.Log.Info(`Migration "add paused before archive to applications" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().AddColumn(&Application{}, "PausedBeforeArchive")
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&Application{}, "PausedBeforeArchive")
+			})
+
+			return err
+		},
+	}
+}
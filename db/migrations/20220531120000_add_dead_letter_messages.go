@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// AddDeadLetterMessages creates the "dead_letter_messages" table, which keeps the messages that could not be delivered
+// to Kafka so that they can be inspected and replayed.
+func AddDeadLetterMessages() *gormigrate.Migration {
+	type DeadLetterMessage struct {
+		ID         int64  `gorm:"primaryKey"`
+		Topic      string `gorm:"not null"`
+		EventType  string
+		Payload    datatypes.JSON
+		Headers    datatypes.JSON
+		Error      string
+		Status     string    `gorm:"not null; index:index_dead_letter_messages_on_tenant_id_and_status,priority:2"`
+		TenantId   int64     `gorm:"not null; index:index_dead_letter_messages_on_tenant_id_and_status,priority:1"`
+		CreatedAt  time.Time `gorm:"not null"`
+		UpdatedAt  time.Time `gorm:"not null"`
+		RequeuedAt *time.Time
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220531120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add dead letter messages" started`)
+			defer logging.Log.Info(`Migration "add dead letter messages" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Migrator().
+					CreateTable(&DeadLetterMessage{})
+
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Exec(`ALTER TABLE "dead_letter_messages" ADD CONSTRAINT "fk_dead_letter_messages_tenant" FOREIGN KEY ("tenant_id") REFERENCES "tenants"("id") ON DELETE CASCADE`).
+					Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.
+					Migrator().
+					DropTable(&DeadLetterMessage{})
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// AddTagsToRhcConnections adds the "tags" column to the rhc connections, along with an index which speeds up finding
+// the connections by their tags.
+func AddTagsToRhcConnections() *gormigrate.Migration {
+	type RhcConnection struct {
+		Tags datatypes.JSON
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220601120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add tags to rhc connections" started`)
+			defer logging.Log.Info(`Migration "add tags to rhc connections" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Migrator().AddColumn(&RhcConnection{}, "Tags")
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Exec(`CREATE INDEX "index_rhc_connections_on_tags" ON "rhc_connections" USING GIN ("tags")`).
+					Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&RhcConnection{}, "Tags")
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddSourceTypeFlags creates the "source_type_flags" table, which holds the capabilities each source type supports.
+func AddSourceTypeFlags() *gormigrate.Migration {
+	type SourceTypeFlag struct {
+		ID           int64     `gorm:"primaryKey"`
+		SourceTypeId int64     `gorm:"not null; uniqueIndex:index_source_type_flags_on_source_type_id_and_name,priority:1"`
+		Name         string    `gorm:"not null; uniqueIndex:index_source_type_flags_on_source_type_id_and_name,priority:2"`
+		Enabled      bool      `gorm:"not null; default:false"`
+		CreatedAt    time.Time `gorm:"not null"`
+		UpdatedAt    time.Time `gorm:"not null"`
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220602120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add source type flags" started`)
+			defer logging.Log.Info(`Migration "add source type flags" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Migrator().
+					CreateTable(&SourceTypeFlag{})
+
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Exec(`ALTER TABLE "source_type_flags" ADD CONSTRAINT "fk_source_type_flags_source_type" FOREIGN KEY ("source_type_id") REFERENCES "source_types"("id") ON DELETE CASCADE`).
+					Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.
+					Migrator().
+					DropTable(&SourceTypeFlag{})
+			})
+
+			return err
+		},
+	}
+}
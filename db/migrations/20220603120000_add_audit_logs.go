@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddAuditLogs creates the "audit_logs" table, which keeps the changes made to the tenants' resources so that they can
+// be exported.
+func AddAuditLogs() *gormigrate.Migration {
+	type AuditLog struct {
+		ID           int64  `gorm:"primaryKey"`
+		EventType    string `gorm:"not null"`
+		ResourceType string
+		ResourceId   string
+		Actor        string
+		TenantId     int64     `gorm:"not null; index:index_audit_logs_on_tenant_id_and_created_at,priority:1"`
+		CreatedAt    time.Time `gorm:"not null; index:index_audit_logs_on_tenant_id_and_created_at,priority:2"`
+	}
+
+	return &gormigrate.Migration{
+		ID: "20220603120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add audit logs" started`)
+			defer logging.Log.Info(`Migration "add audit logs" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.
+					Migrator().
+					CreateTable(&AuditLog{})
+
+				if err != nil {
+					return err
+				}
+
+				return tx.
+					Exec(`ALTER TABLE "audit_logs" ADD CONSTRAINT "fk_audit_logs_tenant" FOREIGN KEY ("tenant_id") REFERENCES "tenants"("id") ON DELETE CASCADE`).
+					Error
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.
+					Migrator().
+					DropTable(&AuditLog{})
+			})
+
+			return err
+		},
+	}
+}
@@ -17,6 +17,26 @@ var migrationsCollection = []*gormigrate.Migration{
 	TranslateEbsAccountNumbersToOrgIds(),
 	SourceTypesAddCategoryColumn(),
 	AddRetryCounterToApplications(),
+	AddSourceAvailabilityNotifyTrigger(),
+	AddLastUsedAtToAuthentications(),
+	AddExternalIdToSources(),
+	AddDisplayNameToSourceTypes(),
+	AddTenantQuotas(),
+	AddAvailabilitySchedules(),
+	AddSourceAvailabilityChanges(),
+	AddRhcConnectionsRhcIdUniqueIndex(),
+	AddSourcesNameUniqueIndex(),
+	AddRhcConnectionsTenantId(),
+	AddRhcConnectionsUpdatedBy(),
+	AddRhcConnectionStatusEvents(),
+	AddCostMetadataToSources(),
+	AddExpiresAtToAuthentications(),
+	AddKafkaOffsets(),
+	AddPausedBeforeArchiveToApplications(),
+	AddDeadLetterMessages(),
+	AddTagsToRhcConnections(),
+	AddSourceTypeFlags(),
+	AddAuditLogs(),
 }
 
 var ctx = context.Background()
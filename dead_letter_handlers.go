@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// function that defines how we get the dao - default implementation below.
+var getDeadLetterDao func(c echo.Context) (dao.DeadLetterDao, error)
+
+func getDeadLetterDaoWithTenant(c echo.Context) (dao.DeadLetterDao, error) {
+	tenantId, err := getTenantFromEchoContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetterDao := dao.GetDeadLetterDao(c.Request().Context(), &tenantId)
+
+	return deadLetterDao, nil
+}
+
+// InternalDeadLetterList lists the tenant's messages which could not be delivered to Kafka, optionally filtered by
+// their "topic" and their "status". Internal use only.
+func InternalDeadLetterList(c echo.Context) error {
+	limit, offset, err := getLimitAndOffset(c)
+	if err != nil {
+		return err
+	}
+
+	deadLetterDao, err := getDeadLetterDao(c)
+	if err != nil {
+		return err
+	}
+
+	deadLetters, count, err := deadLetterDao.List(c.QueryParam("topic"), c.QueryParam("status"), limit, offset)
+	if err != nil {
+		return err
+	}
+
+	out := make([]interface{}, len(deadLetters))
+	for i := range deadLetters {
+		out[i] = deadLetters[i].ToResponse()
+	}
+
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+}
+
+// InternalDeadLetterReplay publishes the tenant's dead letter message to its topic again. Internal use only.
+func InternalDeadLetterReplay(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	deadLetterDao, err := getDeadLetterDao(c)
+	if err != nil {
+		return err
+	}
+
+	deadLetter, err := deadLetterDao.Requeue(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, deadLetter.ToResponse())
+}
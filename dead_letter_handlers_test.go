@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestInternalDeadLetterList tests that the tenant's dead letter messages are returned filtered by their status.
+func TestInternalDeadLetterList(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/internal/v2.0/dead_letters?status=pending",
+		nil,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"tenantID": int64(1),
+		},
+	)
+
+	err := InternalDeadLetterList(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out util.Collection
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	deadLetterDao, _ := getDeadLetterDao(c)
+	want, _, _ := deadLetterDao.List("", m.DeadLetterPending, 100, 0)
+	if len(out.Data) != len(want) {
+		t.Errorf(`want "%d" dead letters, got "%d"`, len(want), len(out.Data))
+	}
+
+	for _, deadLetter := range out.Data {
+		dl, ok := deadLetter.(map[string]interface{})
+		if !ok {
+			t.Error("model did not deserialize as a dead letter")
+		}
+
+		if dl["status"] != m.DeadLetterPending {
+			t.Errorf(`want status "%s", got "%v"`, m.DeadLetterPending, dl["status"])
+		}
+	}
+}
+
+// TestInternalDeadLetterListInvalidStatus tests that a bad request is returned for unknown statuses.
+func TestInternalDeadLetterListInvalidStatus(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/internal/v2.0/dead_letters?status=lost",
+		nil,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"tenantID": int64(1),
+		},
+	)
+
+	badRequestDeadLetterList := ErrorHandlingContext(InternalDeadLetterList)
+	err := badRequestDeadLetterList(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+// TestInternalDeadLetterReplayNotFound tests that a not found is returned for a nonexistent dead letter message.
+func TestInternalDeadLetterReplayNotFound(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/internal/v2.0/dead_letters/12345/replay",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("12345")
+
+	notFoundDeadLetterReplay := ErrorHandlingContext(InternalDeadLetterReplay)
+	err := notFoundDeadLetterReplay(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+// TestInternalDeadLetterReplayBadRequest tests that a bad request is returned for an invalid ID.
+func TestInternalDeadLetterReplayBadRequest(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/internal/v2.0/dead_letters/xxx/replay",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("xxx")
+
+	badRequestDeadLetterReplay := ErrorHandlingContext(InternalDeadLetterReplay)
+	err := badRequestDeadLetterReplay(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
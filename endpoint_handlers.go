@@ -23,7 +23,9 @@ func getEndpointDaoWithTenant(c echo.Context) (dao.EndpointDao, error) {
 		return nil, err
 	}
 
-	return dao.GetEndpointDao(&tenantId), nil
+	endpointDao := dao.GetEndpointDao(c.Request().Context(), &tenantId)
+
+	return endpointDao, nil
 }
 
 func SourceListEndpoint(c echo.Context) error {
@@ -166,6 +168,93 @@ func EndpointCreate(c echo.Context) error {
 	return c.JSON(http.StatusCreated, endpoint.ToResponse())
 }
 
+// SourceEndpointsBulkCreate creates all the endpoints of the body for the source of the path, atomically. When any of
+// the endpoints is invalid none of them get created, and every validation error is returned.
+func SourceEndpointsBulkCreate(c echo.Context) error {
+	endpointDao, err := getEndpointDao(c)
+	if err != nil {
+		return err
+	}
+
+	tenantId, err := getTenantFromEchoContext(c)
+	if err != nil {
+		return err
+	}
+
+	sourceId, err := strconv.ParseInt(c.Param("source_id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	var input []m.EndpointCreateRequest
+	err = c.Bind(&input)
+	if err != nil {
+		return err
+	}
+
+	if len(input) == 0 {
+		return util.NewErrBadRequest("at least one endpoint is required")
+	}
+
+	// The source comes from the path, so a different one in the body is most likely a mistake.
+	for i := range input {
+		if input[i].SourceIDRaw != nil {
+			bodySourceId, err := util.InterfaceToInt64(input[i].SourceIDRaw)
+			if err != nil || bodySourceId != sourceId {
+				return util.NewErrBadRequest(fmt.Sprintf("endpoint %d: the source ID does not match the one of the path", i))
+			}
+		}
+	}
+
+	bulkErrors := service.ValidateEndpointBulkCreateRequest(endpointDao, sourceId, input)
+	if len(bulkErrors) > 0 {
+		return bulkCreateErrorResponse(c, bulkErrors)
+	}
+
+	endpoints := make([]m.Endpoint, len(input))
+	for i := range input {
+		endpoints[i] = m.Endpoint{
+			Default:              &input[i].Default,
+			ReceptorNode:         input[i].ReceptorNode,
+			Role:                 &input[i].Role,
+			Scheme:               input[i].Scheme,
+			Host:                 &input[i].Host,
+			Port:                 input[i].Port,
+			Path:                 &input[i].Path,
+			VerifySsl:            input[i].VerifySsl,
+			CertificateAuthority: input[i].CertificateAuthority,
+			AvailabilityStatus:   input[i].AvailabilityStatus,
+			SourceID:             input[i].SourceID,
+		}
+	}
+
+	created, bulkErrors, err := endpointDao.BulkCreate(c.Request().Context(), endpoints, tenantId)
+	if err != nil {
+		return err
+	}
+
+	if len(bulkErrors) > 0 {
+		return bulkCreateErrorResponse(c, bulkErrors)
+	}
+
+	out := make([]interface{}, len(created))
+	for i := range created {
+		out[i] = created[i].ToResponse()
+	}
+
+	return c.JSON(http.StatusCreated, out)
+}
+
+// bulkCreateErrorResponse responds with a bad request which lists every error of the bulk creation.
+func bulkCreateErrorResponse(c echo.Context, bulkErrors []dao.BulkError) error {
+	errorDocument := util.ErrorDocument{Errors: make([]util.Error, len(bulkErrors))}
+	for i, bulkError := range bulkErrors {
+		errorDocument.Errors[i] = util.Error{Detail: fmt.Sprintf("endpoint %d: %s", bulkError.Index, bulkError.Message), Status: "400"}
+	}
+
+	return c.JSON(http.StatusBadRequest, errorDocument)
+}
+
 func EndpointEdit(c echo.Context) error {
 	endpointDao, err := getEndpointDao(c)
 	if err != nil {
@@ -2,12 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -576,7 +578,7 @@ func TestEndpointDelete(t *testing.T) {
 
 	// Create a source
 	tenantID := fixtures.TestTenantData[0].Id
-	sourceDao := dao.GetSourceDao(&tenantID)
+	sourceDao := dao.GetSourceDao(context.Background(), &tenantID)
 
 	src := m.Source{
 		Name:         "Source for TestApplicationDelete()",
@@ -589,7 +591,7 @@ func TestEndpointDelete(t *testing.T) {
 	}
 
 	// Create an endpoint
-	endpointDao := dao.GetEndpointDao(&tenantID)
+	endpointDao := dao.GetEndpointDao(context.Background(), &tenantID)
 
 	role := "new role"
 	endpoint := m.Endpoint{
@@ -604,7 +606,7 @@ func TestEndpointDelete(t *testing.T) {
 	}
 
 	// Create an authentication for endpoint
-	authenticationDao := dao.GetAuthenticationDao(&tenantID)
+	authenticationDao := dao.GetAuthenticationDao(context.Background(), &tenantID)
 
 	authName3 := "authentication for endpoint"
 	auth := m.Authentication{
@@ -979,3 +981,160 @@ func TestEndpointListAuthenticationsNotFound(t *testing.T) {
 
 	templates.NotFoundTest(t, rec)
 }
+
+// TestSourceEndpointsBulkCreate tests that all the endpoints of the body get created for the source of the path.
+func TestSourceEndpointsBulkCreate(t *testing.T) {
+	port := 443
+	sourceId := fixtures.TestSourceData[0].ID
+
+	requestBody := []m.EndpointCreateRequest{
+		{Role: "first", Host: "first.example.com", Port: &port},
+		{Role: "second", Host: "second.example.com", Path: "/api", SourceIDRaw: sourceId},
+	}
+
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Error("Could not marshal JSON")
+	}
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/sources/1/endpoints/bulk_create",
+		bytes.NewReader(body),
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+	c.SetParamNames("source_id")
+	c.SetParamValues(strconv.FormatInt(sourceId, 10))
+
+	err = SourceEndpointsBulkCreate(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("want 201, got %d", rec.Code)
+	}
+
+	var out []m.EndpointResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("could not unmarshal the response: %s", err)
+	}
+
+	if len(out) != len(requestBody) {
+		t.Fatalf("want %d endpoints, got %d", len(requestBody), len(out))
+	}
+
+	for i, endpoint := range out {
+		if endpoint.SourceID != strconv.FormatInt(sourceId, 10) {
+			t.Errorf(`want source "%d", got "%s"`, sourceId, endpoint.SourceID)
+		}
+
+		if endpoint.Host == nil || *endpoint.Host != requestBody[i].Host {
+			t.Errorf(`want host "%s", got "%v"`, requestBody[i].Host, endpoint.Host)
+		}
+
+		if endpoint.Scheme == nil || *endpoint.Scheme != "https" {
+			t.Errorf(`want the scheme to default to "https", got "%v"`, endpoint.Scheme)
+		}
+	}
+}
+
+// TestSourceEndpointsBulkCreateValidationErrors tests that every invalid endpoint gets reported, including the ones
+// which repeat a role or a default endpoint of the batch, and that nothing gets created in that case.
+func TestSourceEndpointsBulkCreateValidationErrors(t *testing.T) {
+	invalidPort := 70000
+	verifySsl := true
+
+	requestBody := []m.EndpointCreateRequest{
+		{Role: "valid", Host: "example.com", Default: true},
+		{Role: "invalid port", Host: "example.com", Port: &invalidPort},
+		{Role: "invalid host", Host: "example.com/path"},
+		{Role: "no certificate authority", Host: "example.com", VerifySsl: &verifySsl},
+		{Role: "valid", Host: "other.example.com"},
+		{Role: "second default", Host: "example.com", Default: true},
+	}
+
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Error("Could not marshal JSON")
+	}
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/sources/2/endpoints/bulk_create",
+		bytes.NewReader(body),
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+	c.SetParamNames("source_id")
+	// The second source has no default endpoint, so that the first endpoint can be the default one.
+	c.SetParamValues(strconv.FormatInt(fixtures.TestSourceData[1].ID, 10))
+
+	err = SourceEndpointsBulkCreate(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("want 400, got %d", rec.Code)
+	}
+
+	var errorDocument util.ErrorDocument
+	err = json.Unmarshal(rec.Body.Bytes(), &errorDocument)
+	if err != nil {
+		t.Fatalf("could not unmarshal the response: %s", err)
+	}
+
+	wantPrefixes := []string{"endpoint 1:", "endpoint 2:", "endpoint 3:", "endpoint 4:", "endpoint 5:"}
+	if len(errorDocument.Errors) != len(wantPrefixes) {
+		t.Fatalf(`want %d errors, got "%v"`, len(wantPrefixes), errorDocument.Errors)
+	}
+
+	for i, wantPrefix := range wantPrefixes {
+		if !strings.HasPrefix(errorDocument.Errors[i].Detail, wantPrefix) {
+			t.Errorf(`want an error starting with "%s", got "%s"`, wantPrefix, errorDocument.Errors[i].Detail)
+		}
+	}
+}
+
+// TestSourceEndpointsBulkCreateBadRequest tests that a bad request is returned for an empty body or for a source ID
+// in the body which doesn't match the one of the path.
+func TestSourceEndpointsBulkCreateBadRequest(t *testing.T) {
+	testCases := []struct {
+		name string
+		body []m.EndpointCreateRequest
+	}{
+		{name: "empty body", body: []m.EndpointCreateRequest{}},
+		{name: "mismatched source", body: []m.EndpointCreateRequest{{Host: "example.com", SourceIDRaw: 12345}}},
+	}
+
+	for _, tc := range testCases {
+		body, err := json.Marshal(tc.body)
+		if err != nil {
+			t.Error("Could not marshal JSON")
+		}
+
+		c, _ := request.CreateTestContext(
+			http.MethodPost,
+			"/api/sources/v3.1/sources/1/endpoints/bulk_create",
+			bytes.NewReader(body),
+			map[string]interface{}{
+				"tenantID": int64(1),
+			},
+		)
+		c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+		c.SetParamNames("source_id")
+		c.SetParamValues(strconv.FormatInt(fixtures.TestSourceData[0].ID, 10))
+
+		err = SourceEndpointsBulkCreate(c)
+		if !errors.Is(err, util.ErrBadRequestEmpty) {
+			t.Errorf(`[%s] want a bad request error, got "%v"`, tc.name, err)
+		}
+	}
+}
@@ -69,7 +69,7 @@ func (r *applicationTypeResolver) SupportedAuthenticationTypes(ctx context.Conte
 }
 
 func (r *applicationTypeResolver) Sources(ctx context.Context, obj *model.ApplicationType) ([]*model.Source, error) {
-	srces, _, err := dao.GetSourceDao(tenantIdFromCtx(ctx)).SubCollectionList(model.ApplicationType{Id: obj.Id}, 500, 0, []util.Filter{})
+	srces, _, err := dao.GetSourceDao(ctx, tenantIdFromCtx(ctx)).SubCollectionList(model.ApplicationType{Id: obj.Id}, 500, 0, []util.Filter{})
 	out := make([]*model.Source, len(srces))
 	for i := range srces {
 		out[i] = &srces[i]
@@ -131,7 +131,7 @@ func (r *queryResolver) Sources(ctx context.Context, limit *int, offset *int, so
 	f := parseArgs(sortBy, filter)
 
 	// list the sources with filters en tote!
-	srces, count, err := dao.GetSourceDao(tenantIdFromCtx(ctx)).List(*limit, *offset, f)
+	srces, count, err := dao.GetSourceDao(ctx, tenantIdFromCtx(ctx)).List(*limit, *offset, f)
 	sendCount(ctx, count)
 
 	// storing the IDs of relevant sources on the request context for later subresources
@@ -162,7 +162,7 @@ func (r *queryResolver) ApplicationTypes(ctx context.Context, limit *int, offset
 
 	// parse any filters passed along the request
 	f := parseArgs(sortBy, filter)
-	appTypes, count, err := dao.GetApplicationTypeDao(tenantIdFromCtx(ctx)).List(*limit, *offset, f)
+	appTypes, count, err := dao.GetApplicationTypeDao(ctx, tenantIdFromCtx(ctx)).List(*limit, *offset, f)
 	sendCount(ctx, count)
 
 	out := make([]*model.ApplicationType, len(appTypes))
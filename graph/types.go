@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"context"
 	"sync"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
@@ -48,7 +49,7 @@ func (rd *RequestData) EnsureApplicationsAreLoaded() error {
 	// again due to the fact that multiple threads might have locked this the
 	// first time
 	if rd.applicationMap == nil {
-		apps, _, err := dao.GetApplicationDao(&rd.TenantID).List(defaultLimit, 0, []util.Filter{{Name: "source_id", Value: *rd.sourceIdList}})
+		apps, _, err := dao.GetApplicationDao(context.Background(), &rd.TenantID).List(defaultLimit, 0, []util.Filter{{Name: "source_id", Value: *rd.sourceIdList}})
 		if err != nil {
 			return err
 		}
@@ -79,7 +80,7 @@ func (rd *RequestData) EnsureEndpointsAreLoaded() error {
 	defer rd.SourceMutex.Unlock()
 
 	if rd.endpointMap == nil {
-		endpts, _, err := dao.GetEndpointDao(&rd.TenantID).List(defaultLimit, 0, []util.Filter{{Name: "source_id", Value: *rd.sourceIdList}})
+		endpts, _, err := dao.GetEndpointDao(context.Background(), &rd.TenantID).List(defaultLimit, 0, []util.Filter{{Name: "source_id", Value: *rd.sourceIdList}})
 		if err != nil {
 			return err
 		}
@@ -109,7 +110,7 @@ func (rd *RequestData) EnsureAuthenticationsAreLoaded() error {
 	defer rd.SourceMutex.Unlock()
 
 	if rd.authenticationMap == nil {
-		auths, _, err := dao.GetAuthenticationDao(&rd.TenantID).List(defaultLimit, 0, []util.Filter{{Name: "source_id", Value: *rd.sourceIdList}})
+		auths, _, err := dao.GetAuthenticationDao(context.Background(), &rd.TenantID).List(defaultLimit, 0, []util.Filter{{Name: "source_id", Value: *rd.sourceIdList}})
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/dao"
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/labstack/echo/v4"
+)
+
+// cyndiReadinessTimeout is the time the Cyndi pipeline has to report its lag before it is considered unresponsive.
+const cyndiReadinessTimeout = 5 * time.Second
+
+// cyndiLagThreshold is the number of unconfirmed WAL bytes above which the Cyndi pipeline is reported as degraded.
+var cyndiLagThreshold = config.Get().CyndiLagThreshold
+
+// function that defines how we get the dao - default implementation below.
+var getCyndiStatusDao func(c echo.Context) (dao.CyndiStatusDao, error)
+
+func getCyndiStatusDaoWithoutTenant(c echo.Context) (dao.CyndiStatusDao, error) {
+	return dao.GetCyndiStatusDao(c.Request().Context()), nil
+}
+
+// HealthReady reports whether the service is ready to serve requests, along with the status of its dependencies. A
+// lagging Cyndi pipeline only degrades the service, whereas an unresponsive one makes it unavailable.
+func HealthReady(c echo.Context) error {
+	// The DAO gets bound to the request's context, so the probe's timeout has to be set on it beforehand.
+	ctx, cancel := context.WithTimeout(c.Request().Context(), cyndiReadinessTimeout)
+	defer cancel()
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	cyndiStatusDao, err := getCyndiStatusDao(c)
+	if err != nil {
+		return err
+	}
+
+	out := m.ReadinessResponse{Status: m.ReadinessOk, Dependencies: make([]m.ReadinessDependency, 0)}
+
+	lag, err := cyndiStatusDao.GetReplicationLag()
+	switch {
+	case errors.Is(err, dao.ErrCyndiNotConfigured):
+		// Nothing to report when the pipeline isn't deployed.
+	case err != nil:
+		l.Log.Warnf(`the Cyndi pipeline is unresponsive: %s`, err)
+
+		out.Status = m.ReadinessUnavailable
+		out.Dependencies = append(out.Dependencies, m.ReadinessDependency{Name: "cyndi", Status: m.ReadinessUnavailable, Error: err.Error()})
+	default:
+		cyndi := m.ReadinessDependency{Name: "cyndi", Status: m.ReadinessOk, LagBytes: &lag}
+		if lag > cyndiLagThreshold {
+			out.Status = m.ReadinessDegraded
+			cyndi.Status = m.ReadinessDegraded
+		}
+
+		out.Dependencies = append(out.Dependencies, cyndi)
+	}
+
+	if out.Status == m.ReadinessUnavailable {
+		return c.JSON(http.StatusServiceUnavailable, out)
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
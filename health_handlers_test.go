@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/labstack/echo/v4"
+)
+
+// TestHealthReady tests that the readiness probe reports the Cyndi pipeline's lag, and that only an unresponsive
+// pipeline makes the service unavailable.
+func TestHealthReady(t *testing.T) {
+	backupGetCyndiStatusDao := getCyndiStatusDao
+
+	testCases := []struct {
+		name         string
+		cyndiDao     dao.CyndiStatusDao
+		wantCode     int
+		wantStatus   string
+		wantCyndi    string
+		wantLagBytes int64
+	}{
+		{
+			name:         "lag below the threshold",
+			cyndiDao:     &dao.MockCyndiStatusDao{Lag: cyndiLagThreshold},
+			wantCode:     http.StatusOK,
+			wantStatus:   m.ReadinessOk,
+			wantCyndi:    m.ReadinessOk,
+			wantLagBytes: cyndiLagThreshold,
+		},
+		{
+			name:         "lag above the threshold",
+			cyndiDao:     &dao.MockCyndiStatusDao{Lag: cyndiLagThreshold + 1},
+			wantCode:     http.StatusOK,
+			wantStatus:   m.ReadinessDegraded,
+			wantCyndi:    m.ReadinessDegraded,
+			wantLagBytes: cyndiLagThreshold + 1,
+		},
+		{
+			name:       "unresponsive pipeline",
+			cyndiDao:   &dao.MockCyndiStatusDao{Err: errors.New("connection refused")},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: m.ReadinessUnavailable,
+			wantCyndi:  m.ReadinessUnavailable,
+		},
+		{
+			name:       "replication slot without a consumer",
+			cyndiDao:   &dao.MockCyndiStatusDao{Err: dao.ErrCyndiInactive},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: m.ReadinessUnavailable,
+			wantCyndi:  m.ReadinessUnavailable,
+		},
+		{
+			name:       "pipeline not deployed",
+			cyndiDao:   &dao.MockCyndiStatusDao{Err: dao.ErrCyndiNotConfigured},
+			wantCode:   http.StatusOK,
+			wantStatus: m.ReadinessOk,
+		},
+	}
+
+	for _, tc := range testCases {
+		cyndiDao := tc.cyndiDao
+		getCyndiStatusDao = func(c echo.Context) (dao.CyndiStatusDao, error) { return cyndiDao, nil }
+
+		c, rec := request.CreateTestContext(
+			http.MethodGet,
+			"/health/ready",
+			nil,
+			map[string]interface{}{},
+		)
+
+		err := HealthReady(c)
+		if err != nil {
+			t.Errorf(`[%s] want no error, got "%s"`, tc.name, err)
+		}
+
+		if rec.Code != tc.wantCode {
+			t.Errorf(`[%s] want status "%d", got "%d"`, tc.name, tc.wantCode, rec.Code)
+		}
+
+		var out m.ReadinessResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &out)
+		if err != nil {
+			t.Errorf(`[%s] failed unmarshaling output: %s`, tc.name, err)
+		}
+
+		if out.Status != tc.wantStatus {
+			t.Errorf(`[%s] want readiness status "%s", got "%s"`, tc.name, tc.wantStatus, out.Status)
+		}
+
+		if tc.wantCyndi == "" {
+			if len(out.Dependencies) != 0 {
+				t.Errorf(`[%s] want no dependencies, got "%v"`, tc.name, out.Dependencies)
+			}
+
+			continue
+		}
+
+		if len(out.Dependencies) != 1 || out.Dependencies[0].Name != "cyndi" {
+			t.Errorf(`[%s] want the "cyndi" dependency, got "%v"`, tc.name, out.Dependencies)
+			continue
+		}
+
+		cyndi := out.Dependencies[0]
+		if cyndi.Status != tc.wantCyndi {
+			t.Errorf(`[%s] want the "cyndi" status "%s", got "%s"`, tc.name, tc.wantCyndi, cyndi.Status)
+		}
+
+		if tc.wantCyndi != m.ReadinessUnavailable && (cyndi.LagBytes == nil || *cyndi.LagBytes != tc.wantLagBytes) {
+			t.Errorf(`[%s] want "%d" lag bytes, got "%v"`, tc.name, tc.wantLagBytes, cyndi.LagBytes)
+		}
+	}
+
+	getCyndiStatusDao = backupGetCyndiStatusDao
+}
@@ -1,14 +1,17 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
 	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 	"github.com/redhatinsights/platform-go-middlewares/identity"
@@ -102,6 +105,50 @@ func getTenantFromEchoContext(c echo.Context) (int64, error) {
 	}
 }
 
+// tenantIdentity returns the organization ID and the account number which identify the request's tenant in the
+// raised events.
+func tenantIdentity(c echo.Context) (string, string, error) {
+	headers, err := service.ForwadableHeaders(c)
+	if err != nil {
+		return "", "", err
+	}
+
+	var orgId, account string
+	for _, header := range headers {
+		switch header.Key {
+		case h.ORGID:
+			orgId = string(header.Value)
+		case h.ACCOUNT_NUMBER:
+			account = string(header.Value)
+		}
+	}
+
+	if orgId == "" && account == "" {
+		return "", "", util.NewErrBadRequest("the tenant of the request cannot be identified")
+	}
+
+	return orgId, account, nil
+}
+
+// getActorFromEchoContext returns who is performing the request: the user's username, or the system's common name for
+// the certificate based identities. An empty string is returned when the identity doesn't identify anyone.
+func getActorFromEchoContext(c echo.Context) string {
+	id, ok := c.Get(h.PARSED_IDENTITY).(*identity.XRHID)
+	if !ok || id == nil {
+		return ""
+	}
+
+	if id.Identity.User.Username != "" {
+		return id.Identity.User.Username
+	}
+
+	if cn, ok := id.Identity.System["cn"].(string); ok && cn != "" {
+		return "system:" + cn
+	}
+
+	return ""
+}
+
 func getAccountNumberFromEchoContext(c echo.Context) (string, error) {
 	id, ok := c.Get(h.PARSED_IDENTITY).(*identity.XRHID)
 	if !ok {
@@ -110,3 +157,72 @@ func getAccountNumberFromEchoContext(c echo.Context) (string, error) {
 
 	return id.Identity.AccountNumber, nil
 }
+
+// setListValidators sets the "ETag" and "Last-Modified" headers of a list response, and returns true when the
+// request's conditional headers show that the client already has the current version of the list. The "ETag" is made
+// of the number of listed resources and their latest modification, so that the deletions —which don't modify any of
+// the remaining resources— change it too. As mandated by the RFC, "If-Modified-Since" is only evaluated when the
+// request carries no "If-None-Match", and since the HTTP dates only have a precision of seconds, the last modification
+// is truncated to the second for it.
+func setListValidators(c echo.Context, count int64, lastModified *time.Time) bool {
+	var version int64
+	if lastModified != nil {
+		version = lastModified.UnixNano()
+	}
+
+	etag := fmt.Sprintf(`W/"%d-%d"`, count, version)
+	c.Response().Header().Set("ETag", etag)
+
+	if ifNoneMatch := c.Request().Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+
+	if lastModified == nil {
+		return false
+	}
+
+	truncated := lastModified.UTC().Truncate(time.Second)
+	c.Response().Header().Set(echo.HeaderLastModified, truncated.Format(http.TimeFormat))
+
+	ifModifiedSince := c.Request().Header.Get(echo.HeaderIfModifiedSince)
+	if ifModifiedSince == "" {
+		return false
+	}
+
+	// Invalid dates are ignored, as mandated by the RFC.
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	return !truncated.After(since)
+}
+
+// etagMatches returns true when any of the entity tags of the "If-None-Match" header matches the given one. The tags
+// are compared weakly, which is what the RFC mandates for "If-None-Match".
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeServerSentEvent sends the given data as a JSON encoded server sent event of the given type.
+func writeServerSentEvent(c echo.Context, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", event, payload)
+	if err != nil {
+		return err
+	}
+
+	c.Response().Flush()
+	return nil
+}
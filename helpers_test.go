@@ -10,6 +10,7 @@ import (
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/redhatinsights/platform-go-middlewares/identity"
 )
 
 // TestGetTenantFromEchoContext tests that the tenant id is correctly pulled from the context when the tenant has been
@@ -467,3 +468,32 @@ func TestSetEventStreamResourceForApplication(t *testing.T) {
 		}
 	}
 }
+
+// TestGetActorFromEchoContext tests that the actor is the user's username, or the system's common name when there is
+// no user.
+func TestGetActorFromEchoContext(t *testing.T) {
+	testCases := []struct {
+		identity *identity.XRHID
+		want     string
+	}{
+		{identity: &identity.XRHID{Identity: identity.Identity{User: identity.User{Username: "jdoe"}}}, want: "jdoe"},
+		{identity: &identity.XRHID{Identity: identity.Identity{System: map[string]interface{}{"cn": "abc-123"}}}, want: "system:abc-123"},
+		{identity: &identity.XRHID{Identity: identity.Identity{OrgID: "12345"}}, want: ""},
+		{identity: nil, want: ""},
+	}
+
+	for _, tc := range testCases {
+		c, _ := request.CreateTestContext(
+			http.MethodGet,
+			"/api/sources/v3.1/whatever",
+			nil,
+			map[string]interface{}{
+				"identity": tc.identity,
+			},
+		)
+
+		if got := getActorFromEchoContext(c); got != tc.want {
+			t.Errorf(`want actor "%s", got "%s"`, tc.want, got)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package events
 
 import (
+	"context"
+
 	c "github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/kafka"
@@ -44,7 +46,13 @@ func (esp *EventStreamSender) RaiseEvent(eventType string, payload []byte, heade
 	m.AddHeaders(headers)
 	m.AddValue(payload)
 
-	err := kf.Produce(m)
+	// The change gets streamed to the clients even if the event cannot be delivered, since it has already been made.
+	err := dao.NotifyResourceChange(context.Background(), eventType, payload, headers)
+	if err != nil {
+		logging.Log.Warnf("unable to announce the change of the event %v to the streaming clients: %s", eventType, err)
+	}
+
+	err = kf.Produce(m)
 	if err != nil {
 		return err
 	}
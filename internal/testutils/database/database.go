@@ -108,6 +108,8 @@ func MigrateSchema() {
 		AvailabilityStatusError string         `gorm:"column:availability_status_error"`
 		LastCheckedAt           time.Time      `gorm:"column:last_checked_at"`
 		LastAvailableAt         time.Time      `gorm:"column:last_available_at"`
+		LastUsedAt              *time.Time     `gorm:"column:last_used_at"`
+		ExpiresAt               *time.Time     `gorm:"column:expires_at"`
 		SourceId                int64          `gorm:"column:source_id"`
 		TenantId                int64          `gorm:"column:tenant_id"`
 		ResourceType            string         `gorm:"column:resource_type"`
@@ -133,6 +135,15 @@ func MigrateSchema() {
 
 		&m.Endpoint{},
 		&m.MetaData{},
+
+		&m.TenantQuota{},
+		&m.AvailabilitySchedule{},
+		&m.SourceAvailabilityChange{},
+		&m.RhcConnectionStatusEvent{},
+		&m.KafkaOffset{},
+		&m.DeadLetterMessage{},
+		&m.SourceTypeFlag{},
+		&m.AuditLog{},
 	)
 
 	if err != nil {
@@ -1,14 +1,19 @@
 package fixtures
 
-import m "github.com/RedHatInsights/sources-api-go/model"
+import (
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"gorm.io/datatypes"
+)
 
 var TestApplicationTypeData = []m.ApplicationType{
 	{
-		Id:          1,
-		DisplayName: "test app type",
+		Id:                   1,
+		DisplayName:          "test app type",
+		SupportedSourceTypes: datatypes.JSON(`["amazon"]`),
 	},
 	{
-		Id:          2,
-		DisplayName: "second test app type",
+		Id:                   2,
+		DisplayName:          "second test app type",
+		SupportedSourceTypes: datatypes.JSON(`["amazon", "google"]`),
 	},
 }
@@ -7,15 +7,18 @@ var TestRhcConnectionData = []m.RhcConnection{
 		ID:                 1,
 		RhcId:              "a",
 		AvailabilityStatus: "available",
+		TenantId:           1,
 	},
 	{
 		ID:                 2,
 		RhcId:              "b",
 		AvailabilityStatus: "available",
+		TenantId:           1,
 	},
 	{
 		ID:                 3,
 		RhcId:              "c",
 		AvailabilityStatus: "unavailable",
+		TenantId:           1,
 	},
 }
@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/dao"
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/RedHatInsights/sources-api-go/service"
+)
+
+// authExpiryWarningDays is the number of days before their expiry the authentications start being reported.
+const authExpiryWarningDays = 30
+
+// AuthExpiryNotificationWorker raises an "Authentication.expiring_soon" event for every authentication which expires
+// within the next "WithinDays" days, so that the users can rotate the credentials before they cause an outage.
+type AuthExpiryNotificationWorker struct {
+	WithinDays int `json:"within_days"`
+}
+
+func (ae AuthExpiryNotificationWorker) Delay() time.Duration {
+	// run this job immediately, no delay.
+	return 0
+}
+
+func (ae AuthExpiryNotificationWorker) Arguments() map[string]interface{} {
+	return map[string]interface{}{
+		"within_days": ae.WithinDays,
+	}
+}
+
+func (ae AuthExpiryNotificationWorker) Name() string {
+	return "AuthExpiryNotificationWorker"
+}
+
+func (ae AuthExpiryNotificationWorker) Run() error {
+	if config.IsVaultOn() {
+		l.Log.Debugf("Skipping [%v] since the authentications are stored in vault", ae.Name())
+		return nil
+	}
+
+	within := time.Duration(ae.WithinDays) * 24 * time.Hour
+	authDao := dao.GetAuthenticationDao(context.Background(), nil)
+
+	tenantIds, err := authDao.ListTenantsWithExpiringSoon(within)
+	if err != nil {
+		return fmt.Errorf("failed to list the tenants with expiring authentications: %w", err)
+	}
+
+	for _, tenantId := range tenantIds {
+		auths, err := authDao.ListExpiringSoon(tenantId, within)
+		if err != nil {
+			l.Log.Warnf("Failed to list the expiring authentications of tenant [%v]: %v", tenantId, err)
+			continue
+		}
+
+		for i := range auths {
+			err := service.RaiseEvent("Authentication.expiring_soon", &auths[i], tenantHeaders(&auths[i].Tenant))
+			if err != nil {
+				l.Log.Warnf("Failed to raise the expiring soon event for authentication [%v]: %v", auths[i].DbID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ae AuthExpiryNotificationWorker) ToJSON() []byte {
+	bytes, err := json.Marshal(&ae)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
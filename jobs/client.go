@@ -41,6 +41,30 @@ func (jr *JobRequest) Parse() error {
 		}
 
 		jr.Job = &adj
+	case "StaleAuthenticationJob":
+		saj := StaleAuthenticationJob{}
+		err := json.Unmarshal(jr.JobRaw, &saj)
+		if err != nil {
+			return err
+		}
+
+		jr.Job = &saj
+	case "SchedulerWorker":
+		sw := SchedulerWorker{}
+		err := json.Unmarshal(jr.JobRaw, &sw)
+		if err != nil {
+			return err
+		}
+
+		jr.Job = &sw
+	case "AuthExpiryNotificationWorker":
+		aenw := AuthExpiryNotificationWorker{}
+		err := json.Unmarshal(jr.JobRaw, &aenw)
+		if err != nil {
+			return err
+		}
+
+		jr.Job = &aenw
 	default:
 		l.Log.Warnf("Unsupported job: %v", jr.JobName)
 		return fmt.Errorf("unsupported job %v", jr.JobName)
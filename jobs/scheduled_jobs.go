@@ -3,6 +3,7 @@ package jobs
 import (
 	"time"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	l "github.com/RedHatInsights/sources-api-go/logger"
 )
 
@@ -34,7 +35,11 @@ func (sj *ScheduledJob) runForever() {
 // are adding a new job that we want run on a schedule, add it here.
 //
 // example: var schedule = []ScheduledJob{{Interval: 5 * time.Second, Job: &AsyncDestroyJob{}}}
-var schedule = []ScheduledJob{}
+var schedule = []ScheduledJob{
+	{Interval: 24 * time.Hour, Job: &StaleAuthenticationJob{StaleDays: config.Get().StaleAuthDays}},
+	{Interval: time.Minute, Job: &SchedulerWorker{}},
+	{Interval: 24 * time.Hour, Job: &AuthExpiryNotificationWorker{WithinDays: authExpiryWarningDays}},
+}
 
 // runScheduledJobs runs all of the jobs on a schedule forever.
 func runScheduledJobs() {
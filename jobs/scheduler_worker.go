@@ -0,0 +1,77 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+)
+
+// schedulerWorkerBatchSize is the number of due schedules claimed from the database at once.
+const schedulerWorkerBatchSize = 100
+
+// SchedulerWorker raises a "Source.check_availability" event for every source whose availability schedule is due.
+// The schedules are claimed, and moved to their next check, before the events are raised, so that a worker running on
+// every replica raises each check only once.
+type SchedulerWorker struct{}
+
+func (sw SchedulerWorker) Delay() time.Duration {
+	// run this job immediately, no delay.
+	return 0
+}
+
+func (sw SchedulerWorker) Arguments() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+func (sw SchedulerWorker) Name() string {
+	return "SchedulerWorker"
+}
+
+func (sw SchedulerWorker) Run() error {
+	scheduleDao := dao.GetAvailabilityScheduleDao(context.Background(), nil)
+
+	for {
+		// The claimed schedules are moved forward even if their events can't be raised, so that a broken source
+		// doesn't get retried every minute.
+		schedules, err := scheduleDao.ClaimDue(time.Now(), schedulerWorkerBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to claim the due availability schedules: %w", err)
+		}
+
+		for i := range schedules {
+			sw.checkAvailability(&schedules[i])
+		}
+
+		if len(schedules) < schedulerWorkerBatchSize {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (sw SchedulerWorker) ToJSON() []byte {
+	bytes, err := json.Marshal(&sw)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+// checkAvailability raises the availability check event for the schedule's source. Any errors are just logged, since
+// a single failing source shouldn't prevent the rest of the sources from being checked.
+func (sw SchedulerWorker) checkAvailability(schedule *m.AvailabilitySchedule) {
+	source := &schedule.Source
+	source.Tenant = schedule.Tenant
+
+	err := service.RaiseEvent("Source.check_availability", source, tenantHeaders(&schedule.Tenant))
+	if err != nil {
+		l.Log.Warnf("Failed to raise the availability check event for source [%v]: %v", source.ID, err)
+	}
+}
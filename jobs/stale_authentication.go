@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// staleAuthenticationBatchSize is the number of stale authentications fetched from the database at once.
+const staleAuthenticationBatchSize = 100
+
+// StaleAuthenticationJob raises an "Authentication.stale" event for every authentication which hasn't been used in
+// the last "StaleDays" days, so that the credentials can be rotated.
+type StaleAuthenticationJob struct {
+	StaleDays int `json:"stale_days"`
+}
+
+func (sa StaleAuthenticationJob) Delay() time.Duration {
+	// run this job immediately, no delay.
+	return 0
+}
+
+func (sa StaleAuthenticationJob) Arguments() map[string]interface{} {
+	return map[string]interface{}{
+		"stale_days": sa.StaleDays,
+	}
+}
+
+func (sa StaleAuthenticationJob) Name() string {
+	return "StaleAuthenticationJob"
+}
+
+func (sa StaleAuthenticationJob) Run() error {
+	if config.IsVaultOn() {
+		l.Log.Debugf("Skipping [%v] since the authentications are stored in vault", sa.Name())
+		return nil
+	}
+
+	usedBefore := time.Now().AddDate(0, 0, -sa.StaleDays)
+	authDao := dao.GetAuthenticationDao(context.Background(), nil)
+
+	offset := 0
+	for {
+		auths, count, err := authDao.ListStale(usedBefore, staleAuthenticationBatchSize, offset)
+		if err != nil {
+			return fmt.Errorf("failed to list the stale authentications: %w", err)
+		}
+
+		for i := range auths {
+			err := service.RaiseEvent("Authentication.stale", &auths[i], tenantHeaders(&auths[i].Tenant))
+			if err != nil {
+				l.Log.Warnf("Failed to raise the stale event for authentication [%v]: %v", auths[i].DbID, err)
+			}
+		}
+
+		offset += len(auths)
+		if len(auths) == 0 || int64(offset) >= count {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (sa StaleAuthenticationJob) ToJSON() []byte {
+	bytes, err := json.Marshal(&sa)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+// tenantHeaders generates the identity headers for the given tenant, since the background jobs have no request to
+// forward them from.
+func tenantHeaders(tenant *m.Tenant) []kafka.Header {
+	headers := []kafka.Header{
+		{Key: h.XRHID, Value: []byte(util.GeneratedXRhIdentity(tenant.ExternalTenant, tenant.OrgID))},
+	}
+
+	if tenant.ExternalTenant != "" {
+		headers = append(headers, kafka.Header{Key: h.ACCOUNT_NUMBER, Value: []byte(tenant.ExternalTenant)})
+	}
+	if tenant.OrgID != "" {
+		headers = append(headers, kafka.Header{Key: h.ORGID, Value: []byte(tenant.OrgID)})
+	}
+
+	return headers
+}
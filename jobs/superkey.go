@@ -1,6 +1,7 @@
 package jobs
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -57,7 +58,7 @@ func (sk SuperkeyDestroyJob) Run() error {
 func (sk SuperkeyDestroyJob) sendForSource(id int64) error {
 	l.Log.Infof("Sending SuperKey Delete request for source %v", sk.Id)
 
-	a := dao.GetApplicationDao(&sk.Tenant)
+	a := dao.GetApplicationDao(context.Background(), &sk.Tenant)
 
 	apps, _, err := a.SubCollectionList(m.Source{ID: id}, 100, 0, make([]util.Filter, 0))
 	if err != nil {
@@ -3,6 +3,7 @@ package kafka
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/segmentio/kafka-go"
 )
@@ -70,3 +71,42 @@ func (manager *Manager) Consumer() *kafka.Reader {
 	})
 	return manager.consumer
 }
+
+// OffsetsForTime returns, for every partition of the given topic, the offset of the first message which was produced
+// at or after the given time.
+func (manager *Manager) OffsetsForTime(topic string, t time.Time) (map[int]int64, error) {
+	if len(manager.Config.KafkaBrokers) == 0 {
+		return nil, fmt.Errorf("no kafka brokers are configured")
+	}
+
+	broker := manager.Config.KafkaBrokers[0]
+
+	conn, err := kafka.Dial("tcp", broker)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := make(map[int]int64, len(partitions))
+	for _, partition := range partitions {
+		leader, err := kafka.DialLeader(context.Background(), "tcp", broker, topic, partition.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		offset, err := leader.ReadOffset(t)
+		leader.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		offsets[partition.ID] = offset
+	}
+
+	return offsets, nil
+}
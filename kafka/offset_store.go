@@ -0,0 +1,183 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/segmentio/kafka-go"
+)
+
+// OffsetStore keeps the offsets a consumer group has processed outside of Kafka, so that they can be moved in order to
+// replay the messages.
+type OffsetStore interface {
+	// GetOffset returns the next offset to process from the partition, and false when none has been stored for it.
+	GetOffset(partition int) (int64, bool, error)
+	// SetOffset records the next offset to process from the partition.
+	SetOffset(partition int, offset int64) error
+}
+
+// ConsumeWithOffsetStore consumes the topic as a member of the consumer group, but it positions the readers of the
+// assigned partitions at the offsets kept in the store instead of at the ones committed to Kafka. The offsets of the
+// read messages are stored and committed in batches, once every "flushInterval", and the readers are moved whenever
+// the stored offsets get changed by someone else, which is what happens when they get reset. It blocks until the context is done.
+func (manager *Manager) ConsumeWithOffsetStore(ctx context.Context, store OffsetStore, flushInterval time.Duration, consumerHandler func(Message)) error {
+	topic := manager.ConsumerConfig.Topic
+
+	group, err := kafka.NewConsumerGroup(kafka.ConsumerGroupConfig{
+		ID:      manager.ConsumerConfig.GroupID,
+		Brokers: manager.Config.KafkaBrokers,
+		Topics:  []string{topic},
+	})
+	if err != nil {
+		return err
+	}
+	defer group.Close()
+
+	for {
+		generation, err := group.Next(ctx)
+		if errors.Is(err, context.Canceled) || errors.Is(err, kafka.ErrGroupClosed) {
+			return nil
+		}
+
+		// The group keeps trying to join on its own, so the errors are only reported.
+		if err != nil {
+			l.Log.Warnf("Unable to join the consumer group %q: %s", manager.ConsumerConfig.GroupID, err)
+			continue
+		}
+
+		for _, assignment := range generation.Assignments[topic] {
+			assignment := assignment
+			generation.Start(func(ctx context.Context) {
+				manager.consumePartition(ctx, generation, assignment, store, flushInterval, consumerHandler)
+			})
+		}
+	}
+}
+
+// consumePartition reads the assigned partition until the generation ends, storing and committing the offsets of the
+// read messages once every "flushInterval".
+func (manager *Manager) consumePartition(ctx context.Context, generation *kafka.Generation, assignment kafka.PartitionAssignment, store OffsetStore, flushInterval time.Duration, consumerHandler func(Message)) {
+	topic := manager.ConsumerConfig.Topic
+
+	offsets := &partitionOffsets{partition: assignment.ID, stored: -1, next: -1}
+	start := assignment.Offset
+
+	stored, found, err := store.GetOffset(assignment.ID)
+	if err != nil {
+		l.Log.Warnf("Unable to get the stored offset of the partition %d of the topic %q, starting from the committed one: %s", assignment.ID, topic, err)
+	} else if found {
+		offsets.stored = stored
+		start = stored
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   manager.Config.KafkaBrokers,
+		Topic:     topic,
+		Partition: assignment.ID,
+	})
+	defer reader.Close()
+
+	// The reader doesn't belong to the group, so setting its offset never fails.
+	_ = reader.SetOffset(start)
+
+	commit := func(offset int64) {
+		err := generation.CommitOffsets(map[string]map[int]int64{topic: {assignment.ID: offset}})
+		if err != nil {
+			l.Log.Warnf("Unable to commit the offset %d of the partition %d of the topic %q: %s", offset, assignment.ID, topic, err)
+		}
+	}
+
+	flush := func() {
+		previous := offsets.stored
+
+		seekTo, seek, err := offsets.flush(store)
+		if err != nil {
+			l.Log.Warnf("Unable to store the offset of the partition %d of the topic %q: %s", assignment.ID, topic, err)
+			return
+		}
+
+		if seek {
+			l.Log.Infof("The offset of the partition %d of the topic %q has been reset to %d", assignment.ID, topic, seekTo)
+			_ = reader.SetOffset(seekTo)
+			commit(seekTo)
+			return
+		}
+
+		if offsets.stored != previous {
+			commit(offsets.stored)
+		}
+	}
+
+	nextFlush := time.Now().Add(flushInterval)
+	for {
+		// The read is bounded so that the offsets get flushed even when no messages arrive, since that is also how a
+		// reset gets noticed.
+		readCtx, cancel := context.WithDeadline(ctx, nextFlush)
+		message, err := reader.ReadMessage(readCtx)
+		cancel()
+
+		if err == nil {
+			offsets.read(message)
+			go consumerHandler(Message(message))
+		}
+
+		// The generation has ended, so the partition might get assigned to a different member from now on.
+		if ctx.Err() != nil {
+			flush()
+			return
+		}
+
+		if !time.Now().Before(nextFlush) {
+			flush()
+			nextFlush = time.Now().Add(flushInterval)
+		}
+	}
+}
+
+// partitionOffsets keeps track of the offsets of a partition between the flushes to the offset store.
+type partitionOffsets struct {
+	partition int
+	// stored is the last offset which was stored for the partition, or -1 when none has been stored yet.
+	stored int64
+	// next is the offset that follows the last read message, or -1 when nothing has been read yet.
+	next int64
+}
+
+// read records that the given message has been read from the partition.
+func (p *partitionOffsets) read(message kafka.Message) {
+	if message.Offset+1 > p.next {
+		p.next = message.Offset + 1
+	}
+}
+
+// flush stores the offset that follows the last read message. When the stored offset has been changed by someone else
+// since the last flush, the read messages are discarded and the offset the reader has to be moved to is returned
+// instead.
+func (p *partitionOffsets) flush(store OffsetStore) (int64, bool, error) {
+	current, found, err := store.GetOffset(p.partition)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if found && current != p.stored {
+		p.stored = current
+		p.next = -1
+
+		return current, true, nil
+	}
+
+	if p.next <= p.stored {
+		return 0, false, nil
+	}
+
+	err = store.SetOffset(p.partition, p.next)
+	if err != nil {
+		return 0, false, err
+	}
+
+	p.stored = p.next
+
+	return 0, false, nil
+}
@@ -0,0 +1,126 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// memoryOffsetStore is an offset store which keeps the offsets in memory.
+type memoryOffsetStore struct {
+	offsets map[int]int64
+	sets    int
+}
+
+func (m *memoryOffsetStore) GetOffset(partition int) (int64, bool, error) {
+	offset, ok := m.offsets[partition]
+
+	return offset, ok, nil
+}
+
+func (m *memoryOffsetStore) SetOffset(partition int, offset int64) error {
+	m.offsets[partition] = offset
+	m.sets++
+
+	return nil
+}
+
+// TestPartitionOffsetsFlush tests that only the offset which follows the last read message gets stored, and only once
+// per flush.
+func TestPartitionOffsetsFlush(t *testing.T) {
+	store := &memoryOffsetStore{offsets: map[int]int64{}}
+	offsets := &partitionOffsets{partition: 1, stored: -1, next: -1}
+
+	// Nothing has been read yet, so nothing gets stored.
+	_, seek, err := offsets.flush(store)
+	if err != nil {
+		t.Errorf(`want no errors, got "%s"`, err)
+	}
+
+	if seek || store.sets != 0 {
+		t.Errorf(`want no seeks and no stored offsets, got seek "%t" and "%d" stored offsets`, seek, store.sets)
+	}
+
+	for _, offset := range []int64{10, 12, 11} {
+		offsets.read(kafka.Message{Partition: 1, Offset: offset})
+	}
+
+	_, seek, err = offsets.flush(store)
+	if err != nil {
+		t.Errorf(`want no errors, got "%s"`, err)
+	}
+
+	if seek {
+		t.Errorf(`want no seeks, got one`)
+	}
+
+	if store.sets != 1 || store.offsets[1] != 13 {
+		t.Errorf(`want the offset "13" stored once, got "%d" stored "%d" times`, store.offsets[1], store.sets)
+	}
+
+	// Flushing again without reading anything doesn't store anything.
+	_, _, err = offsets.flush(store)
+	if err != nil {
+		t.Errorf(`want no errors, got "%s"`, err)
+	}
+
+	if store.sets != 1 {
+		t.Errorf(`want the offset stored once, got "%d" times`, store.sets)
+	}
+}
+
+// TestPartitionOffsetsFlushReset tests that when the stored offset gets changed by someone else, the read messages
+// are discarded and the reader is told to move to the stored offset.
+func TestPartitionOffsetsFlushReset(t *testing.T) {
+	store := &memoryOffsetStore{offsets: map[int]int64{0: 50}}
+	offsets := &partitionOffsets{partition: 0, stored: 50, next: -1}
+
+	offsets.read(kafka.Message{Offset: 60})
+
+	// Simulate a reset.
+	store.offsets[0] = 5
+
+	seekTo, seek, err := offsets.flush(store)
+	if err != nil {
+		t.Errorf(`want no errors, got "%s"`, err)
+	}
+
+	if !seek || seekTo != 5 {
+		t.Errorf(`want a seek to the offset "5", got seek "%t" to "%d"`, seek, seekTo)
+	}
+
+	if store.offsets[0] != 5 || store.sets != 0 {
+		t.Errorf(`want the reset offset to be kept, got "%d" after "%d" stores`, store.offsets[0], store.sets)
+	}
+
+	// The messages read from the reset offset onwards are stored as usual.
+	offsets.read(kafka.Message{Offset: 5})
+
+	_, seek, err = offsets.flush(store)
+	if err != nil {
+		t.Errorf(`want no errors, got "%s"`, err)
+	}
+
+	if seek || store.offsets[0] != 6 {
+		t.Errorf(`want the offset "6" stored without seeking, got "%d" with seek "%t"`, store.offsets[0], seek)
+	}
+}
+
+// TestPartitionOffsetsFlushStoredBeforeStart tests that a stored offset which shows up after the partition started
+// being read is treated as a reset.
+func TestPartitionOffsetsFlushStoredBeforeStart(t *testing.T) {
+	store := &memoryOffsetStore{offsets: map[int]int64{}}
+	offsets := &partitionOffsets{partition: 2, stored: -1, next: -1}
+
+	offsets.read(kafka.Message{Partition: 2, Offset: 100})
+	store.offsets[2] = 30
+
+	seekTo, seek, err := offsets.flush(store)
+	if err != nil {
+		t.Errorf(`want no errors, got "%s"`, err)
+	}
+
+	if !seek || seekTo != 30 {
+		t.Errorf(`want a seek to the offset "30", got seek "%t" to "%d"`, seek, seekTo)
+	}
+}
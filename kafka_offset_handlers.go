@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// function that defines how we get the dao - default implementation below.
+var getKafkaOffsetDao func(c echo.Context) (dao.KafkaOffsetDao, error)
+
+func getKafkaOffsetDaoWithoutTenant(c echo.Context) (dao.KafkaOffsetDao, error) {
+	kafkaOffsetDao := dao.GetKafkaOffsetDao(c.Request().Context())
+
+	return kafkaOffsetDao, nil
+}
+
+// KafkaOffsetList returns the offsets the consumer groups have processed from each topic's partition.
+func KafkaOffsetList(c echo.Context) error {
+	kafkaOffsetDao, err := getKafkaOffsetDao(c)
+	if err != nil {
+		return err
+	}
+
+	offsets, err := kafkaOffsetDao.List()
+	if err != nil {
+		return err
+	}
+
+	out := make([]m.KafkaOffsetResponse, len(offsets))
+	for i := range offsets {
+		out[i] = *offsets[i].ToResponse()
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
+
+// KafkaOffsetReset moves the consumer group's offsets of the given topic to the first messages which were produced at
+// or after the given timestamp, so that they get replayed.
+func KafkaOffsetReset(c echo.Context) error {
+	input := &m.KafkaOffsetResetRequest{}
+	if err := c.Bind(input); err != nil {
+		return err
+	}
+
+	if input.ConsumerGroup == "" || input.Topic == "" {
+		return util.NewErrBadRequest(`the "consumer_group" and the "topic" are required`)
+	}
+
+	if input.Timestamp.IsZero() {
+		return util.NewErrBadRequest(`the "timestamp" is required`)
+	}
+
+	kafkaOffsetDao, err := getKafkaOffsetDao(c)
+	if err != nil {
+		return err
+	}
+
+	err = kafkaOffsetDao.ResetToTime(input.ConsumerGroup, input.Topic, input.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// TestKafkaOffsetList tests that the recorded offsets are returned.
+func TestKafkaOffsetList(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/admin/kafka/offsets",
+		nil,
+		map[string]interface{}{},
+	)
+
+	err := KafkaOffsetList(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out []m.KafkaOffsetResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	kafkaOffsetDao, _ := getKafkaOffsetDao(c)
+	want, _ := kafkaOffsetDao.List()
+	if len(out) != len(want) {
+		t.Errorf(`want "%d" offsets, got "%d"`, len(want), len(out))
+	}
+}
+
+// TestKafkaOffsetResetBadRequest tests that the reset requests without a consumer group, a topic or a timestamp are
+// rejected.
+func TestKafkaOffsetResetBadRequest(t *testing.T) {
+	requests := []m.KafkaOffsetResetRequest{
+		{Topic: "platform.sources.status", Timestamp: time.Now()},
+		{ConsumerGroup: "sources-api-status-worker", Timestamp: time.Now()},
+		{ConsumerGroup: "sources-api-status-worker", Topic: "platform.sources.status"},
+	}
+
+	for _, req := range requests {
+		body, _ := json.Marshal(req)
+
+		c, rec := request.CreateTestContext(
+			http.MethodPost,
+			"/api/sources/v3.1/admin/kafka/offsets/reset",
+			bytes.NewReader(body),
+			map[string]interface{}{},
+		)
+		c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+		badRequestKafkaOffsetReset := ErrorHandlingContext(KafkaOffsetReset)
+		err := badRequestKafkaOffsetReset(c)
+		if err != nil {
+			t.Error(err)
+		}
+
+		templates.BadRequestTest(t, rec)
+	}
+}
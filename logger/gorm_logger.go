@@ -3,6 +3,8 @@ package logger
 import (
 	"context"
 	"errors"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -10,11 +12,60 @@ import (
 	"gorm.io/gorm/utils"
 )
 
+// tenantIdContextKey is the key under which the tenant ID of the queries is stored in their context.
+type tenantIdContextKey struct{}
+
+// ContextWithTenantId returns a copy of the given context which carries the given tenant ID, so that the queries run
+// with the context get logged along with the tenant they were run for.
+func ContextWithTenantId(ctx context.Context, tenantId int64) context.Context {
+	return context.WithValue(ctx, tenantIdContextKey{}, tenantId)
+}
+
+// tenantIdFromContext returns the tenant ID stored in the given context, if any.
+func tenantIdFromContext(ctx context.Context) (int64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+
+	tenantId, ok := ctx.Value(tenantIdContextKey{}).(int64)
+	return tenantId, ok
+}
+
+// sqlTableRegex captures the first table a statement reads from or writes to.
+var sqlTableRegex = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE|JOIN)\s+"?(\w+)"?(?:\."?(\w+)"?)?`)
+
+// queryType returns the type of the statement, such as "SELECT" or "INSERT".
+func queryType(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(fields[0])
+}
+
+// queryTable returns the first table the statement reads from or writes to, without its schema.
+func queryTable(sql string) string {
+	matches := sqlTableRegex.FindStringSubmatch(sql)
+	if matches == nil {
+		return ""
+	}
+
+	if matches[2] != "" {
+		return matches[2]
+	}
+
+	return matches[1]
+}
+
 type CustomGORMLogger struct {
 	Logger                  *logrus.Logger
 	SlowThreshold           time.Duration
 	SkipErrorRecordNotFound bool
 	LogLevelForSqlLogs      string
+	MaskSensitiveFields     bool
+	// LogAllQueries logs every query instead of only the slow and the failed ones.
+	LogAllQueries bool
 }
 
 func (l *CustomGORMLogger) LogMode(gormLogger.LogLevel) gormLogger.Interface {
@@ -33,35 +84,59 @@ func (l *CustomGORMLogger) Error(_ context.Context, logMessage string, data ...i
 	l.Logger.Error(logMessage, data)
 }
 
-func (l *CustomGORMLogger) Trace(_ context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+func (l *CustomGORMLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
 	var ErrorRecordNotFound = errors.New("record not found")
 
 	elapsed := time.Since(begin)
+	slow := elapsed > l.SlowThreshold && l.SlowThreshold != 0
+
+	if err == nil && !slow && !l.LogAllQueries {
+		return
+	}
+
+	if err != nil && errors.Is(err, ErrorRecordNotFound) && l.SkipErrorRecordNotFound {
+		return
+	}
+
 	sql, rows := fc()
-	duration := float64(elapsed.Nanoseconds()) / 1e6
-	fileWithLineNum := utils.FileWithLineNum()
+	if l.MaskSensitiveFields {
+		sql = MaskRhcIdsInSql(sql)
+	}
 
-	loggerEntry := l.Logger.WithFields(logrus.Fields{
-		"rows":     rows,
-		"duration": duration,
-		"filename": fileWithLineNum,
-		"log_type": SQLType,
-	})
+	loggerEntry := l.Logger.WithFields(l.traceFields(ctx, sql, rows, elapsed, err))
 
 	switch {
 	case err != nil:
-		if errors.Is(err, ErrorRecordNotFound) && l.SkipErrorRecordNotFound {
-			return
-		}
-
 		loggerEntry.Warn(sql)
-	case elapsed > l.SlowThreshold && l.SlowThreshold != 0:
+	case slow:
 		loggerEntry.Warn("SLOW SQL: " + sql)
 	default:
 		l.logByLevelWithFields(loggerEntry, sql)
 	}
 }
 
+// traceFields returns the structured fields the queries get logged with.
+func (l *CustomGORMLogger) traceFields(ctx context.Context, sql string, rows int64, elapsed time.Duration, err error) logrus.Fields {
+	fields := logrus.Fields{
+		"query_type":    queryType(sql),
+		"table":         queryTable(sql),
+		"duration_ms":   float64(elapsed.Nanoseconds()) / 1e6,
+		"rows_affected": rows,
+		"filename":      utils.FileWithLineNum(),
+		"log_type":      SQLType,
+	}
+
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+
+	if tenantId, ok := tenantIdFromContext(ctx); ok {
+		fields["tenant_id"] = tenantId
+	}
+
+	return fields
+}
+
 func (l *CustomGORMLogger) logByLevelWithFields(loggerWithFields *logrus.Entry, sql string) {
 	switch l.LogLevelForSqlLogs {
 	case "DEBUG":
@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// TestQueryTypeAndTable tests that the type and the table of the statements are extracted from the SQL.
+func TestQueryTypeAndTable(t *testing.T) {
+	testCases := []struct {
+		sql       string
+		wantType  string
+		wantTable string
+	}{
+		{sql: `SELECT * FROM "sources" WHERE id = 1`, wantType: "SELECT", wantTable: "sources"},
+		{sql: `INSERT INTO "rhc_connections" ("rhc_id") VALUES ('a')`, wantType: "INSERT", wantTable: "rhc_connections"},
+		{sql: `UPDATE "applications" SET "paused_at"=NULL`, wantType: "UPDATE", wantTable: "applications"},
+		{sql: `delete from dao.endpoints where id = 1`, wantType: "DELETE", wantTable: "endpoints"},
+		{sql: `SELECT 1`, wantType: "SELECT", wantTable: ""},
+	}
+
+	for _, tc := range testCases {
+		if got := queryType(tc.sql); got != tc.wantType {
+			t.Errorf(`want query type "%s" for "%s", got "%s"`, tc.wantType, tc.sql, got)
+		}
+
+		if got := queryTable(tc.sql); got != tc.wantTable {
+			t.Errorf(`want table "%s" for "%s", got "%s"`, tc.wantTable, tc.sql, got)
+		}
+	}
+}
+
+// TestTraceStructuredFields tests that the queries are logged with their structured fields, including the tenant
+// from the context.
+func TestTraceStructuredFields(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	gormLogger := &CustomGORMLogger{Logger: logger, LogLevelForSqlLogs: "DEBUG", LogAllQueries: true}
+
+	ctx := ContextWithTenantId(context.Background(), 5)
+	gormLogger.Trace(ctx, time.Now(), func() (string, int64) { return `SELECT * FROM "sources"`, 3 }, nil)
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatalf(`want a log entry, got none`)
+	}
+
+	if entry.Level != logrus.DebugLevel {
+		t.Errorf(`want level "%s", got "%s"`, logrus.DebugLevel, entry.Level)
+	}
+
+	wantFields := map[string]interface{}{
+		"query_type":    "SELECT",
+		"table":         "sources",
+		"rows_affected": int64(3),
+		"tenant_id":     int64(5),
+	}
+
+	for field, want := range wantFields {
+		if entry.Data[field] != want {
+			t.Errorf(`want field "%s" to be "%v", got "%v"`, field, want, entry.Data[field])
+		}
+	}
+
+	if _, ok := entry.Data["duration_ms"]; !ok {
+		t.Errorf(`want the "duration_ms" field, got none`)
+	}
+
+	if _, ok := entry.Data["error"]; ok {
+		t.Errorf(`want no "error" field for a successful query, got one`)
+	}
+}
+
+// TestTraceOnlySlowAndFailedQueries tests that when not every query is logged, only the slow and the failed ones
+// are, at the warning level.
+func TestTraceOnlySlowAndFailedQueries(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.DebugLevel)
+
+	gormLogger := &CustomGORMLogger{Logger: logger, LogLevelForSqlLogs: "DEBUG", SlowThreshold: time.Second}
+	sql := func() (string, int64) { return `SELECT * FROM "sources"`, 1 }
+
+	// A fast query is not logged.
+	gormLogger.Trace(context.Background(), time.Now(), sql, nil)
+	if len(hook.AllEntries()) != 0 {
+		t.Errorf(`want no log entries for a fast query, got "%d"`, len(hook.AllEntries()))
+	}
+
+	// A slow query is logged as a warning.
+	gormLogger.Trace(context.Background(), time.Now().Add(-2*time.Second), sql, nil)
+	entry := hook.LastEntry()
+	if entry == nil || entry.Level != logrus.WarnLevel {
+		t.Fatalf(`want a warning for a slow query, got "%v"`, entry)
+	}
+
+	// A failed query is logged as a warning along with its error.
+	gormLogger.Trace(context.Background(), time.Now(), sql, errors.New("boom"))
+	entry = hook.LastEntry()
+	if entry == nil || entry.Level != logrus.WarnLevel {
+		t.Fatalf(`want a warning for a failed query, got "%v"`, entry)
+	}
+
+	if entry.Data["error"] != "boom" {
+		t.Errorf(`want error "boom", got "%v"`, entry.Data["error"])
+	}
+
+	if _, ok := entry.Data["tenant_id"]; ok {
+		t.Errorf(`want no "tenant_id" field without a tenant in the context, got one`)
+	}
+}
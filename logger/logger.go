@@ -25,6 +25,7 @@ const (
 	RequestType = "request"
 	EchoType    = "echo"
 	SQLType     = "sql"
+	AuditType   = "audit"
 )
 
 var whiteListForFunctionsInBacktrace = []string{"RedHatInsights", "redhatinsights", "main"}
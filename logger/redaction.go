@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+
+	appconf "github.com/RedHatInsights/sources-api-go/config"
+)
+
+// visibleSuffixLength is the number of trailing characters which are left unmasked, so that the masked values can
+// still be correlated.
+const visibleSuffixLength = 4
+
+var (
+	// rhcIdComparisonRegex matches the comparisons and assignments of the "rhc_id" column against a literal.
+	rhcIdComparisonRegex = regexp.MustCompile(`(?i)((?:"?\w+"?\.)?"?rhc_id"?\s*(?:=|!=|<>|ILIKE|LIKE)\s*)'((?:[^']|'')*)'`)
+	// rhcIdInRegex matches the "IN" lists of the "rhc_id" column.
+	rhcIdInRegex = regexp.MustCompile(`(?i)((?:"?\w+"?\.)?"?rhc_id"?\s+IN\s*\()([^)]*)\)`)
+	// insertRegex matches the column list and the values of an "INSERT" statement.
+	insertRegex = regexp.MustCompile(`(?is)^(INSERT INTO\s+\S+\s*\()([^)]*)(\)\s*VALUES\s*)(.*)$`)
+	// quotedLiteralRegex matches a single quoted SQL literal.
+	quotedLiteralRegex = regexp.MustCompile(`'((?:[^']|'')*)'`)
+)
+
+// MaskValue masks all but the last four characters of the given value. The values which are not longer than that are
+// masked completely, since otherwise they would be leaked as they are.
+func MaskValue(value string) string {
+	runes := []rune(value)
+	if len(runes) <= visibleSuffixLength {
+		return strings.Repeat("*", len(runes))
+	}
+
+	return strings.Repeat("*", len(runes)-visibleSuffixLength) + string(runes[len(runes)-visibleSuffixLength:])
+}
+
+// RedactRhcId masks the given "rhc_id" when the sensitive fields are configured to be masked.
+func RedactRhcId(rhcId string) string {
+	if !appconf.Get().MaskSensitiveFields {
+		return rhcId
+	}
+
+	return MaskValue(rhcId)
+}
+
+// MaskRhcIdsInSql masks the "rhc_id" literals which appear in the given SQL statement, be it in comparisons,
+// assignments, "IN" lists or "INSERT" statements.
+func MaskRhcIdsInSql(sql string) string {
+	if !strings.Contains(strings.ToLower(sql), "rhc_id") {
+		return sql
+	}
+
+	sql = rhcIdComparisonRegex.ReplaceAllStringFunc(sql, func(match string) string {
+		parts := rhcIdComparisonRegex.FindStringSubmatch(match)
+		return parts[1] + maskLiteral(parts[2])
+	})
+
+	sql = rhcIdInRegex.ReplaceAllStringFunc(sql, func(match string) string {
+		parts := rhcIdInRegex.FindStringSubmatch(match)
+		return parts[1] + maskLiterals(parts[2]) + ")"
+	})
+
+	return maskInsertedRhcIds(sql)
+}
+
+// maskLiteral masks the contents of an SQL literal, and returns it quoted again.
+func maskLiteral(literal string) string {
+	value := strings.ReplaceAll(literal, "''", "'")
+
+	return "'" + strings.ReplaceAll(MaskValue(value), "'", "''") + "'"
+}
+
+// maskLiterals masks every quoted literal of the given text.
+func maskLiterals(text string) string {
+	return quotedLiteralRegex.ReplaceAllStringFunc(text, func(match string) string {
+		return maskLiteral(match[1 : len(match)-1])
+	})
+}
+
+// maskInsertedRhcIds masks the values of the "rhc_id" column in an "INSERT" statement.
+func maskInsertedRhcIds(sql string) string {
+	parts := insertRegex.FindStringSubmatch(sql)
+	if parts == nil {
+		return sql
+	}
+
+	column := -1
+	for i, name := range strings.Split(parts[2], ",") {
+		if strings.Trim(strings.TrimSpace(name), `"`) == "rhc_id" {
+			column = i
+			break
+		}
+	}
+
+	if column == -1 {
+		return sql
+	}
+
+	return parts[1] + parts[2] + parts[3] + maskTupleValues(parts[4], column)
+}
+
+// maskTupleValues masks the quoted literal found in the given position of every tuple from the "VALUES" clause.
+func maskTupleValues(values string, column int) string {
+	var result, current strings.Builder
+
+	depth := 0
+	position := 0
+	inQuotes := false
+
+	flush := func() {
+		value := current.String()
+		if depth == 1 && position == column {
+			value = maskLiterals(value)
+		}
+
+		result.WriteString(value)
+		current.Reset()
+	}
+
+	for _, char := range values {
+		switch {
+		case char == '\'':
+			inQuotes = !inQuotes
+			current.WriteRune(char)
+		case inQuotes:
+			current.WriteRune(char)
+		case char == '(':
+			flush()
+			depth++
+			if depth == 1 {
+				position = 0
+			}
+			result.WriteRune(char)
+		case char == ')':
+			flush()
+			depth--
+			result.WriteRune(char)
+		case char == ',' && depth == 1:
+			flush()
+			position++
+			result.WriteRune(char)
+		default:
+			current.WriteRune(char)
+		}
+	}
+
+	flush()
+
+	return result.String()
+}
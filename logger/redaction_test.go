@@ -0,0 +1,62 @@
+package logger
+
+import "testing"
+
+// TestMaskValue tests that all but the last four characters are masked, and that the short values are fully masked.
+func TestMaskValue(t *testing.T) {
+	testCases := map[string]string{
+		"":                                     "",
+		"abc":                                  "***",
+		"abcd":                                 "****",
+		"abcde":                                "*bcde",
+		"6a6b9f4c-a7ee-4b17-a7f2-0b2d5e8d5f9a": "********************************5f9a",
+	}
+
+	for value, want := range testCases {
+		got := MaskValue(value)
+		if got != want {
+			t.Errorf(`want "%s" for "%s", got "%s"`, want, value, got)
+		}
+	}
+}
+
+// TestMaskRhcIdsInSql tests that the "rhc_id" literals get masked in the different kinds of statements, and that the
+// rest of the statement is left untouched.
+func TestMaskRhcIdsInSql(t *testing.T) {
+	testCases := []struct {
+		sql  string
+		want string
+	}{
+		{
+			sql:  `SELECT * FROM "rhc_connections" WHERE "rhc_connections"."rhc_id" = 'abcdefgh' AND "id" = 5`,
+			want: `SELECT * FROM "rhc_connections" WHERE "rhc_connections"."rhc_id" = '****efgh' AND "id" = 5`,
+		},
+		{
+			sql:  `SELECT * FROM rhc_connections WHERE rhc_id='o''brien-id'`,
+			want: `SELECT * FROM rhc_connections WHERE rhc_id='******n-id'`,
+		},
+		{
+			sql:  `SELECT * FROM "rhc_connections" WHERE "rhc_id" IN ('12345678','abcdefgh')`,
+			want: `SELECT * FROM "rhc_connections" WHERE "rhc_id" IN ('****5678','****efgh')`,
+		},
+		{
+			sql:  `UPDATE "rhc_connections" SET "rhc_id"='abcdefgh',"updated_at"='2022-05-17 12:00:00' WHERE "id" = 1`,
+			want: `UPDATE "rhc_connections" SET "rhc_id"='****efgh',"updated_at"='2022-05-17 12:00:00' WHERE "id" = 1`,
+		},
+		{
+			sql:  `INSERT INTO "rhc_connections" ("extra","rhc_id","availability_status") VALUES ('{}','abcdefgh','available'),('{"a":"(b,c)"}','12345678','')`,
+			want: `INSERT INTO "rhc_connections" ("extra","rhc_id","availability_status") VALUES ('{}','****efgh','available'),('{"a":"(b,c)"}','****5678','')`,
+		},
+		{
+			sql:  `SELECT * FROM "sources" WHERE "name" = 'abcdefgh'`,
+			want: `SELECT * FROM "sources" WHERE "name" = 'abcdefgh'`,
+		},
+	}
+
+	for _, tc := range testCases {
+		got := MaskRhcIdsInSql(tc.sql)
+		if got != tc.want {
+			t.Errorf("want\n%s\ngot\n%s", tc.want, got)
+		}
+	}
+}
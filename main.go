@@ -13,6 +13,7 @@ import (
 	"github.com/RedHatInsights/sources-api-go/jobs"
 	logging "github.com/RedHatInsights/sources-api-go/logger"
 	"github.com/RedHatInsights/sources-api-go/redis"
+	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/RedHatInsights/sources-api-go/statuslistener"
 	echoMetrics "github.com/labstack/echo-contrib/prometheus"
 	"github.com/labstack/echo/v4"
@@ -30,6 +31,12 @@ func main() {
 	redis.Init()
 	dao.Init()
 
+	// the availability status changes of the sources get announced in the event stream once they're committed.
+	err := dao.RegisterSourceAvailabilityHook(dao.DB, service.SourceAvailabilityEventPublisher{})
+	if err != nil {
+		logging.Log.Fatalf("Failed to register the source availability hook: %s", err)
+	}
+
 	shutdown := make(chan struct{})
 	interrupts := make(chan os.Signal, 1)
 	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
@@ -54,6 +61,13 @@ func main() {
 	shutdown <- struct{}{}
 	<-shutdown
 
+	// wait for the in-flight database operations to finish before closing the connection.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := dao.Shutdown(ctx); err != nil {
+		logging.Log.Warnf("Error shutting down the database connection: %s", err)
+	}
+	cancel()
+
 	os.Exit(0)
 }
 
@@ -89,9 +103,25 @@ func runServer(shutdown chan struct{}) {
 	getApplicationAuthenticationDao = getApplicationAuthenticationDaoWithTenant
 	getApplicationTypeDao = getApplicationTypeDaoWithTenant
 	getSourceTypeDao = getSourceTypeDaoWithoutTenant
+	getSourceTypeFlagDao = getSourceTypeFlagDaoWithoutTenant
 	getEndpointDao = getEndpointDaoWithTenant
 	getMetaDataDao = getMetaDataDaoWithoutTenant
 	getRhcConnectionDao = getDefaultRhcConnectionDao
+	getTenantStatsDao = getTenantStatsDaoWithoutTenant
+	getKafkaOffsetDao = getKafkaOffsetDaoWithoutTenant
+	getDeadLetterDao = getDeadLetterDaoWithTenant
+	getCyndiStatusDao = getCyndiStatusDaoWithoutTenant
+	getAuditLogDao = getAuditLogDaoWithoutTenant
+	getAvailabilityScheduleDao = getAvailabilityScheduleDaoWithTenant
+
+	// the newly created rhcConnections get their availability checked by cloud-connector.
+	dao.RhcAvailabilityPublisher = service.RhcConnectionAvailabilityRequester{}
+
+	// the onboarded tenants get announced in the event stream.
+	dao.TenantPublisher = service.TenantEventPublisher{}
+
+	// the bulk created endpoints get announced in the event stream.
+	dao.EndpointPublisher = service.EndpointEventPublisher{}
 
 	// hiding the ascii art to make the logs more json-like
 	e.HideBanner = true
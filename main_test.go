@@ -14,6 +14,7 @@ import (
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/parser"
 	l "github.com/RedHatInsights/sources-api-go/logger"
 	"github.com/RedHatInsights/sources-api-go/middleware"
+	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
@@ -29,6 +30,13 @@ var (
 	mockRhcConnectionDao             dao.RhcConnectionDao
 	mockApplicationAuthenticationDao dao.ApplicationAuthenticationDao
 	mockAuthenticationDao            dao.AuthenticationDao
+	mockTenantStatsDao               dao.TenantStatsDao
+	mockKafkaOffsetDao               dao.KafkaOffsetDao
+	mockDeadLetterDao                dao.DeadLetterDao
+	mockSourceTypeFlagDao            dao.SourceTypeFlagDao
+	mockCyndiStatusDao               dao.CyndiStatusDao
+	mockAuditLogDao                  dao.AuditLogDao
+	mockAvailabilityScheduleDao      dao.AvailabilityScheduleDao
 )
 
 func TestMain(t *testing.M) {
@@ -50,6 +58,13 @@ func TestMain(t *testing.M) {
 		getRhcConnectionDao = getDefaultRhcConnectionDao
 		getApplicationAuthenticationDao = getApplicationAuthenticationDaoWithTenant
 		getAuthenticationDao = getAuthenticationDaoWithTenant
+		getTenantStatsDao = getTenantStatsDaoWithoutTenant
+		getKafkaOffsetDao = getKafkaOffsetDaoWithoutTenant
+		getDeadLetterDao = getDeadLetterDaoWithTenant
+		getSourceTypeFlagDao = getSourceTypeFlagDaoWithoutTenant
+		getCyndiStatusDao = getCyndiStatusDaoWithoutTenant
+		getAuditLogDao = getAuditLogDaoWithoutTenant
+		getAvailabilityScheduleDao = getAvailabilityScheduleDaoWithTenant
 
 		dao.Vault = &mocks.MockVault{}
 
@@ -70,6 +85,22 @@ func TestMain(t *testing.M) {
 		mockRhcConnectionDao = &dao.MockRhcConnectionDao{RhcConnections: fixtures.TestRhcConnectionData, RelatedRhcConnections: fixtures.TestRhcConnectionData}
 		mockApplicationAuthenticationDao = &dao.MockApplicationAuthenticationDao{ApplicationAuthentications: fixtures.TestApplicationAuthenticationData}
 		mockAuthenticationDao = &dao.MockAuthenticationDao{Authentications: fixtures.TestAuthenticationData}
+		mockTenantStatsDao = &dao.MockTenantStatsDao{Stats: []m.TenantStats{
+			{TenantId: fixtures.TestTenantData[0].Id, Sources: int64(len(fixtures.TestSourceData))},
+			{TenantId: fixtures.TestTenantData[1].Id},
+		}}
+		mockKafkaOffsetDao = &dao.MockKafkaOffsetDao{Offsets: []m.KafkaOffset{{ID: 1, ConsumerGroup: "sources-api-status-worker", Topic: "platform.sources.status", Partition: 0, Offset: 10}}}
+		mockDeadLetterDao = &dao.MockDeadLetterDao{DeadLetters: []m.DeadLetterMessage{
+			{ID: 1, Topic: "platform.sources.event-stream", EventType: "Source.create", Status: m.DeadLetterPending, TenantId: fixtures.TestTenantData[0].Id},
+			{ID: 2, Topic: "platform.sources.event-stream", EventType: "Source.update", Status: m.DeadLetterReplayed, TenantId: fixtures.TestTenantData[0].Id},
+		}}
+		mockSourceTypeFlagDao = &dao.MockSourceTypeFlagDao{Flags: []m.SourceTypeFlag{
+			{ID: 1, SourceTypeId: fixtures.TestSourceTypeData[0].Id, Name: "cost_management", Enabled: true},
+			{ID: 2, SourceTypeId: fixtures.TestSourceTypeData[0].Id, Name: "topology", Enabled: false},
+		}}
+		mockCyndiStatusDao = &dao.MockCyndiStatusDao{Lag: 10}
+		mockAuditLogDao = &dao.MockAuditLogDao{}
+		mockAvailabilityScheduleDao = &dao.MockAvailabilityScheduleDao{}
 
 		getSourceDao = func(c echo.Context) (dao.SourceDao, error) { return mockSourceDao, nil }
 		getApplicationDao = func(c echo.Context) (dao.ApplicationDao, error) { return mockApplicationDao, nil }
@@ -82,6 +113,15 @@ func TestMain(t *testing.M) {
 			return mockApplicationAuthenticationDao, nil
 		}
 		getAuthenticationDao = func(c echo.Context) (dao.AuthenticationDao, error) { return mockAuthenticationDao, nil }
+		getTenantStatsDao = func(c echo.Context) (dao.TenantStatsDao, error) { return mockTenantStatsDao, nil }
+		getKafkaOffsetDao = func(c echo.Context) (dao.KafkaOffsetDao, error) { return mockKafkaOffsetDao, nil }
+		getDeadLetterDao = func(c echo.Context) (dao.DeadLetterDao, error) { return mockDeadLetterDao, nil }
+		getSourceTypeFlagDao = func(c echo.Context) (dao.SourceTypeFlagDao, error) { return mockSourceTypeFlagDao, nil }
+		getCyndiStatusDao = func(c echo.Context) (dao.CyndiStatusDao, error) { return mockCyndiStatusDao, nil }
+		getAuditLogDao = func(c echo.Context) (dao.AuditLogDao, error) { return mockAuditLogDao, nil }
+		getAvailabilityScheduleDao = func(c echo.Context) (dao.AvailabilityScheduleDao, error) {
+			return mockAvailabilityScheduleDao, nil
+		}
 
 	}
 
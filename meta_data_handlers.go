@@ -14,7 +14,9 @@ import (
 var getMetaDataDao func(c echo.Context) (dao.MetaDataDao, error)
 
 func getMetaDataDaoWithoutTenant(c echo.Context) (dao.MetaDataDao, error) {
-	return dao.GetMetaDataDao(), nil
+	metaDataDao := dao.GetMetaDataDao(c.Request().Context())
+
+	return metaDataDao, nil
 }
 
 func MetaDataList(c echo.Context) error {
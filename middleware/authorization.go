@@ -3,8 +3,10 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/RedHatInsights/rbac-client-go"
@@ -19,6 +21,18 @@ var (
 	psks            = config.Get().Psks
 	bypassRbac      = config.Get().BypassRbac
 	rbacClient Rbac = &RbacClient{client: rbac.NewClient(os.Getenv("RBAC_URL"), "sources")}
+
+	// includeRequiredPermission makes the RBAC denials tell which permission was required.
+	includeRequiredPermission = config.Get().RbacDenialIncludesPermission
+
+	// rbacRetryAfter is how long the clients are told to wait before retrying when RBAC is unavailable.
+	rbacRetryAfter = config.Get().RbacRetryAfter
+
+	// trustedSystemCNs are the "cn"s and "cluster_id"s of the system identities which are authorized. Any system
+	// identity is trusted when empty.
+	trustedSystemCNs = config.Get().TrustedSystemCNs
+	// permissiveSystemIdentities authorizes the system identities with any "cn" or "cluster_id", even empty ones.
+	permissiveSystemIdentities = config.Get().PermissiveSystemIdentities
 )
 
 /*
@@ -32,6 +46,111 @@ var (
 	   returns whether or not it contains the correct `sources:*:*` permission.
 */
 func PermissionCheck(next echo.HandlerFunc) echo.HandlerFunc {
+	allowed := func(xrhid string) (bool, error) {
+		return rbacClient.Allowed(xrhid)
+	}
+
+	return checkPermission(next, "sources:*:*", allowed)
+}
+
+/*
+	Works like "PermissionCheck", but instead of requiring the `sources:*:*`
+	permission it checks the permission for the given subresource, and the
+	verb that corresponds to the request's method. For example, a "GET" request
+	for the "endpoints" subresource requires the `sources:endpoints:read`
+	permission, and a "POST" request the `sources:endpoints:write` one.
+
+	The `sources:*:*` permission keeps granting access to all the subresources.
+*/
+func PermissionCheckForSubresource(resourceType string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			verb := rbacVerbForMethod(c.Request().Method)
+			allowed := func(xrhid string) (bool, error) {
+				return rbacAllowedForResource(xrhid, resourceType, verb)
+			}
+
+			return checkPermission(next, fmt.Sprintf("sources:%s:%s", resourceType, verb), allowed)(c)
+		}
+	}
+}
+
+/*
+	Only lets through the requests which carry one of the approved PSKs. It is
+	meant for the operations reserved to the operators, which therefore cannot
+	be authorized with an identity header.
+*/
+func PermissionCheckPskOnly(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if bypassRbac {
+			c.Logger().Debugf("Skipping authorization check -- disabled in ENV")
+			return next(c)
+		}
+
+		psk, ok := c.Get(h.PSK).(string)
+		if !ok || !pskMatches(psk) {
+			recordDenial(c, denialReasonPsk, "")
+			return c.JSON(http.StatusUnauthorized, util.ErrorDoc("Unauthorized Action: a valid [x-rh-sources-psk] is required", "401"))
+		}
+
+		return next(c)
+	}
+}
+
+/*
+	Only lets through the requests which either carry one of the approved PSKs
+	or an identity of an organization administrator. It is meant for the
+	operations which expose data about the whole tenant.
+*/
+func PermissionCheckPskOrOrgAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if bypassRbac {
+			c.Logger().Debugf("Skipping authorization check -- disabled in ENV")
+			return next(c)
+		}
+
+		if hasValidPsk(c) {
+			c.Set(h.PSK_AUTHORIZED, true)
+			return next(c)
+		}
+
+		if isOrgAdmin(c) {
+			return next(c)
+		}
+
+		recordDenial(c, denialReasonPskOrOrgAdmin, "")
+		return c.JSON(http.StatusUnauthorized, util.ErrorDoc("Unauthorized Action: a valid [x-rh-sources-psk] or an organization administrator identity is required", "401"))
+	}
+}
+
+// IsPskOrOrgAdmin returns true when the request either carries one of the approved PSKs or an identity of an
+// organization administrator.
+func IsPskOrOrgAdmin(c echo.Context) bool {
+	return hasValidPsk(c) || isOrgAdmin(c)
+}
+
+// AuthorizedByPsk returns true when the request was let through because of one of the approved PSKs, as opposed to
+// just carrying a PSK header.
+func AuthorizedByPsk(c echo.Context) bool {
+	authorized, ok := c.Get(h.PSK_AUTHORIZED).(bool)
+	return ok && authorized
+}
+
+// hasValidPsk returns true when the request carries one of the approved PSKs.
+func hasValidPsk(c echo.Context) bool {
+	psk, ok := c.Get(h.PSK).(string)
+	return ok && pskMatches(psk)
+}
+
+// isOrgAdmin returns true when the request carries the identity of an organization administrator.
+func isOrgAdmin(c echo.Context) bool {
+	id, ok := c.Get(h.PARSED_IDENTITY).(*identity.XRHID)
+	return ok && id.Identity.User.OrgAdmin
+}
+
+// checkPermission authorizes the request by either the PSK or the identity header, in which case the given function is
+// used to check the permissions against RBAC. The required permission is only used to explain the denials.
+func checkPermission(next echo.HandlerFunc, requiredPermission string, rbacAllowed func(xrhid string) (bool, error)) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		switch {
 		case bypassRbac:
@@ -43,6 +162,7 @@ func PermissionCheck(next echo.HandlerFunc) echo.HandlerFunc {
 			}
 
 			if !pskMatches(psk) {
+				recordDenial(c, denialReasonPsk, requiredPermission)
 				return c.JSON(http.StatusUnauthorized, util.ErrorDoc("Unauthorized Action: Incorrect PSK", "401"))
 			}
 
@@ -61,23 +181,20 @@ func PermissionCheck(next echo.HandlerFunc) echo.HandlerFunc {
 			if identity.Identity.System != nil {
 				// system-auth only allows GET and POST requests.
 				if c.Request().Method != http.MethodGet && c.Request().Method != http.MethodPost {
+					recordDenial(c, denialReasonSystem, requiredPermission)
 					c.Response().Header().Set("Allow", "GET, POST")
 					return c.JSON(http.StatusMethodNotAllowed, util.ErrorDoc("Method not allowed", "405"))
 				}
 
-				// basically we're checking whether cn or cluster_id is set in
-				// the system section of the header, if it is then this request
-				// can go through (but only if it's a POST)
-				//
-				// we're returning early because this is easier than a goto.
-				switch {
-				case identity.Identity.System["cluster_id"] != nil:
-					return next(c)
-				case identity.Identity.System["cn"] != nil:
-					return next(c)
-				default:
-					return c.JSON(http.StatusUnauthorized, util.ErrorDoc("Unauthorized Action: system authorization only supports cn/cluster_id authorization", "401"))
+				// the system identity must carry a "cn" or a "cluster_id" we
+				// trust. We're returning early because this is easier than a
+				// goto.
+				if message, ok := systemIdentityAllowed(identity.Identity.System); !ok {
+					recordDenial(c, denialReasonSystem, requiredPermission)
+					return c.JSON(http.StatusUnauthorized, util.ErrorDoc(message, "401"))
 				}
+
+				return next(c)
 			}
 
 			// otherwise, ship the xrhid off to rbac and check access rights.
@@ -86,29 +203,60 @@ func PermissionCheck(next echo.HandlerFunc) echo.HandlerFunc {
 				return fmt.Errorf("error casting x-rh-identity to string: %v", c.Get("x-rh-identity"))
 			}
 
-			allowed, err := rbacClient.Allowed(rhid)
+			allowed, err := rbacAllowed(rhid)
 			if err != nil {
-				return fmt.Errorf("error hitting rbac: %v", err)
+				c.Logger().Warnf("error hitting rbac: %s", err)
+				return rbacUnavailable(c)
 			}
 
 			if !allowed {
-				return c.JSON(http.StatusUnauthorized, util.ErrorDoc("Unauthorized Action: Missing RBAC permissions", "401"))
+				recordDenial(c, denialReasonRbac, requiredPermission)
+				return c.JSON(http.StatusUnauthorized, util.ErrorDoc(rbacDenialDetail(requiredPermission), "401"))
 			}
 
 		default:
-			return c.JSON(http.StatusUnauthorized, util.ErrorDoc("Authentication required by either [x-rh-identity] or [x-rh-sources-psk]", "401"))
+			recordDenial(c, denialReasonMissingCredentials, requiredPermission)
+			return c.JSON(http.StatusUnauthorized, util.ErrorDoc(authenticationRequiredMessage(), "401"))
 		}
 
 		return next(c)
 	}
 }
 
+// rbacDenialDetail returns the detail of the RBAC denials, which includes the required permission unless it has been
+// configured to be hidden. The caller's access list is never included.
+func rbacDenialDetail(requiredPermission string) string {
+	if !includeRequiredPermission || requiredPermission == "" {
+		return "Unauthorized Action: Missing RBAC permissions"
+	}
+
+	return fmt.Sprintf("Unauthorized Action: Missing RBAC permissions, [%s] is required", requiredPermission)
+}
+
+// rbacUnavailable responds with a "503 Service Unavailable" which tells the clients when to retry, since RBAC being
+// unavailable is a transient error.
+func rbacUnavailable(c echo.Context) error {
+	setRetryAfter(c, rbacRetryAfter)
+	return c.JSON(http.StatusServiceUnavailable, util.ErrorDoc("Authorization service unavailable, please retry later", "503"))
+}
+
+// setRetryAfter sets the "Retry-After" header to the given amount of time, rounded up to the next whole second.
+func setRetryAfter(c echo.Context, retryAfter time.Duration) {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	c.Response().Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+}
+
 func pskMatches(psk string) bool {
 	return util.SliceContainsString(psks, psk)
 }
 
 type Rbac interface {
 	Allowed(string) (bool, error)
+	AllowedForResource(xrhid, resourceType, verb string) (bool, error)
 }
 
 type RbacClient struct {
@@ -128,3 +276,70 @@ func (r *RbacClient) Allowed(xrhid string) (bool, error) {
 
 	return acl.IsAllowed("sources", "*", "*"), nil
 }
+
+// fetches an access list from RBAC and returns whether or not the xrhid has the
+// `sources:<resourceType>:<verb>` permission. Wildcard permissions such as
+// `sources:*:*` are honored.
+func (r *RbacClient) AllowedForResource(xrhid, resourceType, verb string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	acl, err := r.client.GetAccess(ctx, xrhid, "")
+	if err != nil {
+		return false, err
+	}
+
+	return acl.IsAllowed("sources", resourceType, verb), nil
+}
+
+// rbacAllowedForResource checks whether the xrhid has the given verb permission on the given sources' resource type.
+func rbacAllowedForResource(xrhid, resourceType, verb string) (bool, error) {
+	return rbacClient.AllowedForResource(xrhid, resourceType, verb)
+}
+
+// rbacVerbForMethod returns the RBAC verb required for the given HTTP method: "read" for the safe methods and "write"
+// for everything else.
+func rbacVerbForMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return "read"
+	default:
+		return "write"
+	}
+}
+
+// systemIdentityAllowed returns whether the system section of an identity is authorized, along with the reason when
+// it isn't. The system identities need a non-empty "cn" or "cluster_id", which must be one of the trusted ones if any
+// are configured. In the permissive mode any "cn" or "cluster_id" is enough.
+func systemIdentityAllowed(system map[string]interface{}) (string, bool) {
+	if permissiveSystemIdentities {
+		if system["cluster_id"] != nil || system["cn"] != nil {
+			return "", true
+		}
+
+		return "Unauthorized Action: system authorization only supports cn/cluster_id authorization", false
+	}
+
+	names := make([]string, 0, 2)
+	for _, key := range []string{"cn", "cluster_id"} {
+		if name, ok := system[key].(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return "Unauthorized Action: system authorization only supports cn/cluster_id authorization", false
+	}
+
+	if len(trustedSystemCNs) == 0 {
+		return "", true
+	}
+
+	for _, name := range names {
+		if util.SliceContainsString(trustedSystemCNs, name) {
+			return "", true
+		}
+	}
+
+	return "Unauthorized Action: the system identity is not trusted", false
+}
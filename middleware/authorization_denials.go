@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/labstack/echo/v4"
+	"github.com/redhatinsights/platform-go-middlewares/identity"
+	"github.com/sirupsen/logrus"
+)
+
+// The reasons the requests get denied for.
+const (
+	denialReasonMissingCredentials = "missing_credentials"
+	denialReasonPsk                = "psk"
+	denialReasonPskOrOrgAdmin      = "psk_or_org_admin"
+	denialReasonRbac               = "rbac"
+	denialReasonSystem             = "system"
+)
+
+// denialQueueSize is the number of denials which can be waiting to be logged before new ones start being dropped.
+const denialQueueSize = 1024
+
+var (
+	logAuthorizationDenials = config.Get().LogAuthorizationDenials
+	hashDeniedIdentities    = config.Get().HashDeniedIdentities
+
+	// denialQueue holds the denials until they get logged, so that the logging stays off the requests' path.
+	denialQueue = make(chan authorizationDenial, denialQueueSize)
+)
+
+// authorizationDenial is the audit record of a request which was denied by the authorization middlewares.
+type authorizationDenial struct {
+	Reason             string
+	RequiredPermission string
+	AccountNumber      string
+	OrgId              string
+	Username           string
+	Method             string
+	Path               string
+	DeniedAt           time.Time
+}
+
+func init() {
+	if logAuthorizationDenials {
+		go logAuthorizationDenialsFrom(denialQueue)
+	}
+}
+
+// recordDenial queues the request's denial to be logged, if the denials' logging is enabled. When the queue is full
+// the denial is dropped, since the requests must not wait for the logs.
+func recordDenial(c echo.Context, reason, requiredPermission string) {
+	if !logAuthorizationDenials {
+		return
+	}
+
+	select {
+	case denialQueue <- newAuthorizationDenial(c, reason, requiredPermission):
+	default:
+		l.Log.Warnf(`Authorization denials queue full, dropping the denial of "%s %s"`, c.Request().Method, c.Request().URL.Path)
+	}
+}
+
+// newAuthorizationDenial builds the denial's record from the request's identity, hashing the identifiers of the
+// caller if configured so.
+func newAuthorizationDenial(c echo.Context, reason, requiredPermission string) authorizationDenial {
+	denial := authorizationDenial{
+		Reason:             reason,
+		RequiredPermission: requiredPermission,
+		Method:             c.Request().Method,
+		Path:               c.Request().URL.Path,
+		DeniedAt:           time.Now(),
+	}
+
+	if id, ok := c.Get(h.PARSED_IDENTITY).(*identity.XRHID); ok && id != nil {
+		denial.AccountNumber = id.Identity.AccountNumber
+		denial.OrgId = id.Identity.OrgID
+		denial.Username = id.Identity.User.Username
+	}
+
+	// The PSK requests carry the tenant in their own headers.
+	if accountNumber, ok := c.Get(h.ACCOUNT_NUMBER).(string); ok && denial.AccountNumber == "" {
+		denial.AccountNumber = accountNumber
+	}
+
+	if orgId, ok := c.Get(h.ORGID).(string); ok && denial.OrgId == "" {
+		denial.OrgId = orgId
+	}
+
+	if hashDeniedIdentities {
+		denial.AccountNumber = hashIdentifier(denial.AccountNumber)
+		denial.OrgId = hashIdentifier(denial.OrgId)
+		denial.Username = hashIdentifier(denial.Username)
+	}
+
+	return denial
+}
+
+// hashIdentifier returns the hex encoded SHA-256 hash of the given identifier, or an empty string if the identifier
+// is empty.
+func hashIdentifier(identifier string) string {
+	if identifier == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(identifier))
+	return hex.EncodeToString(sum[:])
+}
+
+// fields returns the structured fields the denial gets logged with.
+func (ad authorizationDenial) fields() logrus.Fields {
+	return logrus.Fields{
+		"log_type":            l.AuditType,
+		"reason":              ad.Reason,
+		"required_permission": ad.RequiredPermission,
+		"account_number":      ad.AccountNumber,
+		"org_id":              ad.OrgId,
+		"username":            ad.Username,
+		"method":              ad.Method,
+		"path":                ad.Path,
+		"denied_at":           ad.DeniedAt.Format(time.RFC3339Nano),
+	}
+}
+
+// logAuthorizationDenialsFrom logs the denials received from the given queue until it gets closed.
+func logAuthorizationDenialsFrom(queue <-chan authorizationDenial) {
+	for denial := range queue {
+		l.Log.WithFields(denial.fields()).Warn("Authorization denied")
+	}
+}
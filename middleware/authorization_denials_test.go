@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/redhatinsights/platform-go-middlewares/identity"
+)
+
+// TestRbacDenialRecorded tests that the RBAC denials get queued to be logged along with who was denied, and what
+// permission was missing.
+func TestRbacDenialRecorded(t *testing.T) {
+	logAuthorizationDenials = true
+	hashDeniedIdentities = false
+	denialQueue = make(chan authorizationDenial, 1)
+	rbacClient = dummyRbac{access: false}
+	defer func() {
+		logAuthorizationDenials = false
+	}()
+
+	c, rec := request.CreateTestContext(
+		http.MethodDelete,
+		"/api/sources/v3.1/sources/1",
+		nil,
+		map[string]interface{}{
+			h.XRHID:           "xrhid",
+			h.PARSED_IDENTITY: &identity.XRHID{Identity: identity.Identity{AccountNumber: "12345", OrgID: "67890", User: identity.User{Username: "jdoe"}}},
+		},
+	)
+
+	err := permCheckOrElse204(c)
+	if err != nil {
+		t.Errorf(`want no error, got "%s"`, err)
+	}
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf(`want status code "%d", got "%d"`, http.StatusUnauthorized, rec.Code)
+	}
+
+	select {
+	case denial := <-denialQueue:
+		want := authorizationDenial{
+			Reason:             denialReasonRbac,
+			RequiredPermission: "sources:*:*",
+			AccountNumber:      "12345",
+			OrgId:              "67890",
+			Username:           "jdoe",
+			Method:             http.MethodDelete,
+			Path:               "/api/sources/v3.1/sources/1",
+		}
+
+		denial.DeniedAt = want.DeniedAt
+		if denial != want {
+			t.Errorf(`want denial "%+v", got "%+v"`, want, denial)
+		}
+	default:
+		t.Errorf(`want a queued denial, got none`)
+	}
+}
+
+// TestPskDenialHashedIdentity tests that the identities of the denied PSK requests get hashed when configured so.
+func TestPskDenialHashedIdentity(t *testing.T) {
+	logAuthorizationDenials = true
+	hashDeniedIdentities = true
+	denialQueue = make(chan authorizationDenial, 1)
+	psks = []string{"abcdef"}
+	defer func() {
+		logAuthorizationDenials = false
+		hashDeniedIdentities = false
+	}()
+
+	c, _ := request.CreateTestContext(
+		http.MethodPost,
+		"/",
+		nil,
+		map[string]interface{}{h.PSK: "1234", h.ACCOUNT_NUMBER: "12345"},
+	)
+
+	err := pskOnlyCheckOrElse204(c)
+	if err != nil {
+		t.Errorf(`want no error, got "%s"`, err)
+	}
+
+	select {
+	case denial := <-denialQueue:
+		if denial.Reason != denialReasonPsk {
+			t.Errorf(`want reason "%s", got "%s"`, denialReasonPsk, denial.Reason)
+		}
+
+		if denial.AccountNumber != hashIdentifier("12345") || denial.AccountNumber == "12345" {
+			t.Errorf(`want a hashed account number, got "%s"`, denial.AccountNumber)
+		}
+
+		if denial.OrgId != "" {
+			t.Errorf(`want an empty org ID to stay empty, got "%s"`, denial.OrgId)
+		}
+	default:
+		t.Errorf(`want a queued denial, got none`)
+	}
+}
+
+// TestDenialNotRecordedWhenDisabled tests that nothing is queued when the denials' logging is disabled.
+func TestDenialNotRecordedWhenDisabled(t *testing.T) {
+	logAuthorizationDenials = false
+	denialQueue = make(chan authorizationDenial, 1)
+	psks = []string{"abcdef"}
+
+	c, _ := request.CreateTestContext(http.MethodPost, "/", nil, map[string]interface{}{h.PSK: "1234"})
+
+	err := pskOnlyCheckOrElse204(c)
+	if err != nil {
+		t.Errorf(`want no error, got "%s"`, err)
+	}
+
+	if len(denialQueue) != 0 {
+		t.Errorf(`want no queued denials, got "%d"`, len(denialQueue))
+	}
+}
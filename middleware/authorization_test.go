@@ -1,12 +1,16 @@
 package middleware
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/RedHatInsights/rbac-client-go"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 	"github.com/redhatinsights/platform-go-middlewares/identity"
 )
@@ -155,6 +159,76 @@ func TestSystemCN(t *testing.T) {
 	}
 }
 
+// TestSystemIdentityAllowed tests that the system identities need a non-empty "cn" or "cluster_id", which must be a
+// trusted one when any are configured, and that the permissive mode only requires either of them to be present.
+func TestSystemIdentityAllowed(t *testing.T) {
+	backupTrusted, backupPermissive := trustedSystemCNs, permissiveSystemIdentities
+	defer func() { trustedSystemCNs, permissiveSystemIdentities = backupTrusted, backupPermissive }()
+
+	testCases := []struct {
+		name       string
+		system     map[string]interface{}
+		trusted    []string
+		permissive bool
+		want       bool
+	}{
+		{name: "cn", system: map[string]interface{}{"cn": "test_cert"}, want: true},
+		{name: "cluster_id", system: map[string]interface{}{"cluster_id": "test_cluster"}, want: true},
+		{name: "empty cn", system: map[string]interface{}{"cn": ""}, want: false},
+		{name: "non string cn", system: map[string]interface{}{"cn": 12345}, want: false},
+		{name: "neither cn nor cluster_id", system: map[string]interface{}{"other": "value"}, want: false},
+		{name: "trusted cn", system: map[string]interface{}{"cn": "test_cert"}, trusted: []string{"test_cert"}, want: true},
+		{name: "trusted cluster_id", system: map[string]interface{}{"cn": "other", "cluster_id": "test_cluster"}, trusted: []string{"test_cluster"}, want: true},
+		{name: "untrusted cn", system: map[string]interface{}{"cn": "other"}, trusted: []string{"test_cert"}, want: false},
+		{name: "permissive empty cn", system: map[string]interface{}{"cn": ""}, permissive: true, want: true},
+		{name: "permissive untrusted cn", system: map[string]interface{}{"cn": "other"}, trusted: []string{"test_cert"}, permissive: true, want: true},
+		{name: "permissive without cn", system: map[string]interface{}{"other": "value"}, permissive: true, want: false},
+	}
+
+	for _, tc := range testCases {
+		trustedSystemCNs, permissiveSystemIdentities = tc.trusted, tc.permissive
+
+		message, got := systemIdentityAllowed(tc.system)
+		if got != tc.want {
+			t.Errorf(`[%s] want "%t", got "%t"`, tc.name, tc.want, got)
+		}
+
+		if !got && message == "" {
+			t.Errorf(`[%s] want a reason for the denial, got none`, tc.name)
+		}
+	}
+}
+
+// TestSystemUntrustedCN tests that the system identities which aren't trusted are rejected.
+func TestSystemUntrustedCN(t *testing.T) {
+	backupTrusted := trustedSystemCNs
+	trustedSystemCNs = []string{"trusted_cert"}
+	defer func() { trustedSystemCNs = backupTrusted }()
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/",
+		nil,
+		map[string]interface{}{
+			"x-rh-identity": "dummy",
+			"identity": &identity.XRHID{
+				Identity: identity.Identity{
+					System: map[string]interface{}{"cn": "test_cert"},
+				},
+			},
+		},
+	)
+
+	err := permCheckOrElse204(c)
+	if err != nil {
+		t.Errorf("caught an error when there should not have been one")
+	}
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("%v was returned instead of %v", rec.Code, http.StatusUnauthorized)
+	}
+}
+
 func TestSystemPatch(t *testing.T) {
 	c, rec := request.CreateTestContext(
 		http.MethodPatch,
@@ -219,6 +293,10 @@ func (d dummyRbac) Allowed(_ string) (bool, error) {
 	return d.access, nil
 }
 
+func (d dummyRbac) AllowedForResource(_, _, _ string) (bool, error) {
+	return d.Allowed("")
+}
+
 func TestRbacWithAccess(t *testing.T) {
 	rbacClient = dummyRbac{access: true}
 
@@ -265,10 +343,71 @@ func TestRbacWithoutAccess(t *testing.T) {
 	}
 }
 
+// TestRbacDenialIncludesPermission tests that the RBAC denials include the required permission, unless it has been
+// configured to be hidden.
+func TestRbacDenialIncludesPermission(t *testing.T) {
+	rbacClient = dummyRbac{access: false}
+	original := includeRequiredPermission
+	defer func() { includeRequiredPermission = original }()
+
+	endpointsCheckOrElse204 := PermissionCheckForSubresource("endpoints")(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	testCases := []struct {
+		include bool
+		check   echo.HandlerFunc
+		want    string
+	}{
+		{include: true, check: permCheckOrElse204, want: "Unauthorized Action: Missing RBAC permissions, [sources:*:*] is required"},
+		{include: true, check: endpointsCheckOrElse204, want: "Unauthorized Action: Missing RBAC permissions, [sources:endpoints:write] is required"},
+		{include: false, check: permCheckOrElse204, want: "Unauthorized Action: Missing RBAC permissions"},
+	}
+
+	for _, tc := range testCases {
+		includeRequiredPermission = tc.include
+
+		c, rec := request.CreateTestContext(
+			http.MethodPost,
+			"/",
+			nil,
+			map[string]interface{}{
+				"x-rh-identity": "a wild xrhid",
+				"identity":      &identity.XRHID{Identity: identity.Identity{}},
+			},
+		)
+
+		err := tc.check(c)
+		if err != nil {
+			t.Errorf("caught an error when there should not have been one")
+		}
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf(`want "%d", got "%d"`, http.StatusUnauthorized, rec.Code)
+		}
+
+		var errorDocument util.ErrorDocument
+		err = json.Unmarshal(rec.Body.Bytes(), &errorDocument)
+		if err != nil {
+			t.Fatalf(`could not unmarshal the error document: %s`, err)
+		}
+
+		if errorDocument.Errors[0].Detail != tc.want {
+			t.Errorf(`want detail "%s", got "%s"`, tc.want, errorDocument.Errors[0].Detail)
+		}
+	}
+}
+
+// TestRbacNoConnection tests that a "503 Service Unavailable" response which tells when to retry is returned when RBAC
+// cannot be reached.
 func TestRbacNoConnection(t *testing.T) {
 	rbacClient = dummyRbac{access: false, blowup: true}
 
-	c, _ := request.CreateTestContext(
+	originalRetryAfter := rbacRetryAfter
+	rbacRetryAfter = 1500 * time.Millisecond
+	defer func() { rbacRetryAfter = originalRetryAfter }()
+
+	c, rec := request.CreateTestContext(
 		"POST",
 		"/",
 		nil,
@@ -279,8 +418,212 @@ func TestRbacNoConnection(t *testing.T) {
 	)
 
 	err := permCheckOrElse204(c)
+	if err != nil {
+		t.Errorf(`want no error, got "%s"`, err)
+	}
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusServiceUnavailable, rec.Code)
+	}
+
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf(`want a "Retry-After" of "2" seconds, got "%s"`, got)
+	}
+}
+
+// dummyAclRbac checks the permissions against the given access list.
+type dummyAclRbac struct {
+	acl rbac.AccessList
+}
+
+func (d dummyAclRbac) Allowed(_ string) (bool, error) {
+	return d.acl.IsAllowed("sources", "*", "*"), nil
+}
+
+func (d dummyAclRbac) AllowedForResource(_, resourceType, verb string) (bool, error) {
+	return d.acl.IsAllowed("sources", resourceType, verb), nil
+}
+
+// TestSubresourcePermissionCheck tests that the subresource permission check allows or denies the requests depending
+// on the subresource's permissions, the method of the request, and that the "sources:*:*" permission still grants
+// access to everything.
+func TestSubresourcePermissionCheck(t *testing.T) {
+	endpointsCheckOrElse204 := PermissionCheckForSubresource("endpoints")(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	testCases := []struct {
+		permission string
+		method     string
+		want       int
+	}{
+		{permission: "sources:endpoints:read", method: http.MethodGet, want: http.StatusNoContent},
+		{permission: "sources:endpoints:read", method: http.MethodPost, want: http.StatusUnauthorized},
+		{permission: "sources:endpoints:write", method: http.MethodPost, want: http.StatusNoContent},
+		{permission: "sources:authentications:read", method: http.MethodGet, want: http.StatusUnauthorized},
+		{permission: "sources:*:*", method: http.MethodGet, want: http.StatusNoContent},
+		{permission: "sources:*:*", method: http.MethodPost, want: http.StatusNoContent},
+	}
+
+	for _, tc := range testCases {
+		rbacClient = dummyAclRbac{acl: rbac.AccessList{{Permission: tc.permission}}}
+
+		c, rec := request.CreateTestContext(
+			tc.method,
+			"/",
+			nil,
+			map[string]interface{}{
+				"x-rh-identity": "a wild xrhid",
+				"identity":      &identity.XRHID{Identity: identity.Identity{}},
+			},
+		)
+
+		err := endpointsCheckOrElse204(c)
+		if err != nil {
+			t.Errorf("caught an error when there should not have been one")
+		}
+
+		if rec.Code != tc.want {
+			t.Errorf(`[permission: %s][method: %s] want "%d", got "%d"`, tc.permission, tc.method, tc.want, rec.Code)
+		}
+	}
+}
+
+var pskOnlyCheckOrElse204 = PermissionCheckPskOnly(func(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+})
+
+func TestPskOnlyGoodPSK(t *testing.T) {
+	psks = []string{"1234"}
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		"/",
+		nil,
+		map[string]interface{}{h.PSK: "1234"},
+	)
+
+	err := pskOnlyCheckOrElse204(c)
+	if err != nil {
+		t.Errorf("caught an error when there should not have been one")
+	}
+
+	if rec.Code != 204 {
+		t.Errorf("%v was returned instead of %v", rec.Code, 204)
+	}
+}
+
+func TestPskOnlyRejectsIdentity(t *testing.T) {
+	psks = []string{"1234"}
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		"/",
+		nil,
+		map[string]interface{}{h.XRHID: "dummy"},
+	)
+
+	err := pskOnlyCheckOrElse204(c)
+	if err != nil {
+		t.Errorf("caught an error when there should not have been one")
+	}
+
+	if rec.Code != 401 {
+		t.Errorf("%v was returned instead of %v", rec.Code, 401)
+	}
+}
+
+func TestPskOnlyBadPSK(t *testing.T) {
+	psks = []string{"abcdef"}
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		"/",
+		nil,
+		map[string]interface{}{h.PSK: "1234"},
+	)
+
+	err := pskOnlyCheckOrElse204(c)
+	if err != nil {
+		t.Errorf("caught an error when there should not have been one")
+	}
+
+	if rec.Code != 401 {
+		t.Errorf("%v was returned instead of %v", rec.Code, 401)
+	}
+}
+
+var pskOrOrgAdminCheckOrElse204 = PermissionCheckPskOrOrgAdmin(func(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+})
+
+// TestPskOrOrgAdminAuthorizedByPsk tests that only the requests let through because of a valid PSK are flagged as
+// authorized by it, even when an organization administrator sends a bogus PSK along.
+func TestPskOrOrgAdminAuthorizedByPsk(t *testing.T) {
+	psks = []string{"1234"}
+	orgAdmin := &identity.XRHID{Identity: identity.Identity{User: identity.User{OrgAdmin: true}}}
+
+	testCases := []struct {
+		name    string
+		context map[string]interface{}
+		want    bool
+	}{
+		{name: "valid psk", context: map[string]interface{}{h.PSK: "1234"}, want: true},
+		{name: "org admin", context: map[string]interface{}{h.PARSED_IDENTITY: orgAdmin}, want: false},
+		{name: "org admin with a bogus psk", context: map[string]interface{}{h.PSK: "abcd", h.PARSED_IDENTITY: orgAdmin}, want: false},
+	}
+
+	for _, tc := range testCases {
+		c, _ := request.CreateTestContext(http.MethodGet, "/", nil, tc.context)
+
+		authorizedByPsk := false
+		err := PermissionCheckPskOrOrgAdmin(func(c echo.Context) error {
+			authorizedByPsk = AuthorizedByPsk(c)
+			return c.NoContent(http.StatusNoContent)
+		})(c)
+
+		if err != nil {
+			t.Errorf("caught an error when there should not have been one")
+		}
+
+		if authorizedByPsk != tc.want {
+			t.Errorf(`[%s] want authorized by psk "%t", got "%t"`, tc.name, tc.want, authorizedByPsk)
+		}
+	}
+}
+
+// TestPskOrOrgAdmin tests that only the requests with a valid PSK or with an organization administrator's identity
+// are let through.
+func TestPskOrOrgAdmin(t *testing.T) {
+	psks = []string{"1234"}
+
+	testCases := []struct {
+		name    string
+		context map[string]interface{}
+		want    int
+	}{
+		{name: "valid psk", context: map[string]interface{}{h.PSK: "1234"}, want: http.StatusNoContent},
+		{name: "invalid psk", context: map[string]interface{}{h.PSK: "abcd"}, want: http.StatusUnauthorized},
+		{
+			name:    "org admin",
+			context: map[string]interface{}{h.PARSED_IDENTITY: &identity.XRHID{Identity: identity.Identity{User: identity.User{OrgAdmin: true}}}},
+			want:    http.StatusNoContent,
+		},
+		{
+			name:    "regular user",
+			context: map[string]interface{}{h.PARSED_IDENTITY: &identity.XRHID{Identity: identity.Identity{User: identity.User{OrgAdmin: false}}}},
+			want:    http.StatusUnauthorized,
+		},
+		{name: "no authorization", context: map[string]interface{}{}, want: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		c, rec := request.CreateTestContext(http.MethodGet, "/", nil, tc.context)
+
+		err := pskOrOrgAdminCheckOrElse204(c)
+		if err != nil {
+			t.Errorf("caught an error when there should not have been one")
+		}
 
-	if err == nil {
-		t.Errorf("no error was returned when we were expecting one!")
+		if rec.Code != tc.want {
+			t.Errorf(`[%s] want "%d", got "%d"`, tc.name, tc.want, rec.Code)
+		}
 	}
 }
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+var maxRequestBodyBytes = config.Get().MaxRequestBodyBytes
+
+/*
+	Rejects the "write" requests —POST/PATCH/PUT— whose body is bigger than
+	the configured limit with a 413, before the handlers buffer the whole
+	body in memory. Use "BodyLimitOf" for the routes which need a different
+	limit.
+*/
+func BodyLimit(next echo.HandlerFunc) echo.HandlerFunc {
+	return BodyLimitOf(maxRequestBodyBytes)(next)
+}
+
+// BodyLimitOf returns a middleware which rejects the write requests whose body is bigger than the given number of
+// bytes. The declared content length is checked upfront, and the body is capped with "http.MaxBytesReader" for the
+// requests which don't declare it or lie about it.
+func BodyLimitOf(limit int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			switch req.Method {
+			case http.MethodPost, http.MethodPatch, http.MethodPut:
+			default:
+				return next(c)
+			}
+
+			if req.ContentLength > limit {
+				return bodyTooLarge(c, limit)
+			}
+
+			body := &limitedBody{ReadCloser: http.MaxBytesReader(c.Response(), req.Body, limit), limit: limit}
+			req.Body = body
+
+			err := next(c)
+
+			// The handlers report the failed reads as they see fit, so the actual reason gets reported instead.
+			if body.exceeded && !c.Response().Committed {
+				return bodyTooLarge(c, limit)
+			}
+
+			return err
+		}
+	}
+}
+
+// bodyTooLarge responds with the error document of the requests whose body exceeds the limit.
+func bodyTooLarge(c echo.Context, limit int64) error {
+	return c.JSON(http.StatusRequestEntityTooLarge, util.ErrorDoc(fmt.Sprintf("Request body too large, the maximum size is %d bytes", limit), "413"))
+}
+
+// limitedBody is a request body capped by "http.MaxBytesReader" which remembers whether the limit was exceeded.
+type limitedBody struct {
+	io.ReadCloser
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	n, err := l.ReadCloser.Read(p)
+	l.read += int64(n)
+
+	// "http.MaxBytesReader" fails once the whole limit has been read and there is still more to read.
+	if err != nil && err != io.EOF && l.read >= l.limit {
+		l.exceeded = true
+	}
+
+	return n, err
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// readBodyOrElse204 is a handler which reads the whole body, like the binders do, and fails when it can't.
+var readBodyOrElse204 = func(c echo.Context) error {
+	_, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// TestBodyLimitWithinLimit tests that the requests whose body doesn't exceed the limit are let through.
+func TestBodyLimitWithinLimit(t *testing.T) {
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodPut} {
+		c, rec := request.CreateTestContext(method, "/", bytes.NewBufferString(`{"name": "test"}`), map[string]interface{}{})
+
+		err := BodyLimitOf(16)(readBodyOrElse204)(c)
+		if err != nil {
+			t.Errorf(`unexpected error: %s`, err)
+		}
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf(`want status "%d" for method "%s", got "%d"`, http.StatusNoContent, method, rec.Code)
+		}
+	}
+}
+
+// TestBodyLimitDeclaredLength tests that the requests which declare a content length bigger than the limit are
+// rejected without running the handler.
+func TestBodyLimitDeclaredLength(t *testing.T) {
+	c, rec := request.CreateTestContext(http.MethodPost, "/", bytes.NewBufferString(`{"name": "too long"}`), map[string]interface{}{})
+
+	err := BodyLimitOf(16)(func(c echo.Context) error {
+		t.Errorf(`want the handler not to be run, but it was`)
+		return nil
+	})(c)
+
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusRequestEntityTooLarge, rec.Code)
+	}
+}
+
+// TestBodyLimitUndeclaredLength tests that the requests which don't declare their content length get rejected once
+// the handler reads past the limit.
+func TestBodyLimitUndeclaredLength(t *testing.T) {
+	c, rec := request.CreateTestContext(http.MethodPost, "/", strings.NewReader(`{"name": "too long"}`), map[string]interface{}{})
+	c.Request().ContentLength = -1
+
+	err := BodyLimitOf(16)(readBodyOrElse204)(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusRequestEntityTooLarge, rec.Code)
+	}
+
+	if !strings.Contains(rec.Body.String(), "413") {
+		t.Errorf(`want an error document with status "413", got "%s"`, rec.Body.String())
+	}
+}
+
+// TestBodyLimitReadRequests tests that the requests which don't write anything aren't limited.
+func TestBodyLimitReadRequests(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		c, rec := request.CreateTestContext(method, "/", bytes.NewBufferString(`{"name": "too long"}`), map[string]interface{}{})
+
+		err := BodyLimitOf(16)(readBodyOrElse204)(c)
+		if err != nil {
+			t.Errorf(`unexpected error: %s`, err)
+		}
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf(`want status "%d" for method "%s", got "%d"`, http.StatusNoContent, method, rec.Code)
+		}
+	}
+}
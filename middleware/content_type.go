@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+var acceptedContentTypes = config.Get().AcceptedContentTypes
+
+/*
+	Rejects the "write" requests —POST/PATCH/PUT— which carry a body with a
+	content type other than the accepted ones, returning a 415 instead of
+	letting the request fail later with a confusing unmarshalling error.
+
+	Any media type parameters, such as the charset, are ignored. Requests
+	without a body are let through, since there is nothing to parse.
+*/
+func ContentTypeCheck(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+
+		switch req.Method {
+		case http.MethodPost, http.MethodPatch, http.MethodPut:
+		default:
+			return next(c)
+		}
+
+		if req.ContentLength == 0 {
+			return next(c)
+		}
+
+		contentType := req.Header.Get(echo.HeaderContentType)
+		if !contentTypeAccepted(contentType) {
+			return c.JSON(http.StatusUnsupportedMediaType, util.ErrorDoc(fmt.Sprintf("Unsupported content type %q, expected one of: %s", contentType, strings.Join(acceptedContentTypes, ", ")), "415"))
+		}
+
+		return next(c)
+	}
+}
+
+// contentTypeAccepted returns true if the media type of the given content type is one of the accepted ones.
+func contentTypeAccepted(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, accepted := range acceptedContentTypes {
+		if strings.EqualFold(strings.TrimSpace(accepted), mediaType) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/labstack/echo/v4"
+)
+
+var contentTypeCheckOrElse204 = ContentTypeCheck(func(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+})
+
+// TestContentTypeCheckAccepted tests that the requests with an accepted content type are let through.
+func TestContentTypeCheckAccepted(t *testing.T) {
+	contentTypes := []string{"application/json", "application/json; charset=utf-8", "application/vnd.api+json"}
+
+	for _, contentType := range contentTypes {
+		c, rec := request.CreateTestContext(
+			http.MethodPost,
+			"/",
+			bytes.NewBufferString(`{"name": "test"}`),
+			map[string]interface{}{},
+		)
+		c.Request().Header.Set("Content-Type", contentType)
+
+		err := contentTypeCheckOrElse204(c)
+		if err != nil {
+			t.Errorf(`unexpected error: %s`, err)
+		}
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf(`want status "%d" for content type "%s", got "%d"`, http.StatusNoContent, contentType, rec.Code)
+		}
+	}
+}
+
+// TestContentTypeCheckUnsupported tests that the write requests with an unsupported or a missing content type are
+// rejected.
+func TestContentTypeCheckUnsupported(t *testing.T) {
+	contentTypes := []string{"", "text/plain", "application/x-www-form-urlencoded"}
+	methods := []string{http.MethodPost, http.MethodPatch, http.MethodPut}
+
+	for _, method := range methods {
+		for _, contentType := range contentTypes {
+			c, rec := request.CreateTestContext(
+				method,
+				"/",
+				bytes.NewBufferString(`{"name": "test"}`),
+				map[string]interface{}{},
+			)
+			c.Request().Header.Set("Content-Type", contentType)
+
+			err := contentTypeCheckOrElse204(c)
+			if err != nil {
+				t.Errorf(`unexpected error: %s`, err)
+			}
+
+			if rec.Code != http.StatusUnsupportedMediaType {
+				t.Errorf(`want status "%d" for method "%s" and content type "%s", got "%d"`, http.StatusUnsupportedMediaType, method, contentType, rec.Code)
+			}
+		}
+	}
+}
+
+// TestContentTypeCheckSkipped tests that the requests which don't need to be checked are let through.
+func TestContentTypeCheckSkipped(t *testing.T) {
+	// A read request with a body.
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/",
+		bytes.NewBufferString(`hello`),
+		map[string]interface{}{},
+	)
+	c.Request().Header.Set("Content-Type", "text/plain")
+
+	err := contentTypeCheckOrElse204(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf(`want status "%d" for a read request, got "%d"`, http.StatusNoContent, rec.Code)
+	}
+
+	// A write request without a body.
+	c, rec = request.CreateTestContext(
+		http.MethodPost,
+		"/",
+		nil,
+		map[string]interface{}{},
+	)
+
+	err = contentTypeCheckOrElse204(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf(`want status "%d" for a request without a body, got "%d"`, http.StatusNoContent, rec.Code)
+	}
+}
+
+// TestContentTypeCheckConfigurable tests that the accepted content types can be changed.
+func TestContentTypeCheckConfigurable(t *testing.T) {
+	original := acceptedContentTypes
+	acceptedContentTypes = []string{"application/vnd.api+json"}
+	defer func() { acceptedContentTypes = original }()
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/",
+		bytes.NewBufferString(`{"name": "test"}`),
+		map[string]interface{}{},
+	)
+	c.Request().Header.Set("Content-Type", "application/json")
+
+	err := contentTypeCheckOrElse204(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusUnsupportedMediaType, rec.Code)
+	}
+}
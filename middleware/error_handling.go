@@ -22,6 +22,19 @@ func HandleErrors(next echo.HandlerFunc) echo.HandlerFunc {
 			case util.ErrBadRequest:
 				statusCode = http.StatusBadRequest
 				message = util.ErrorDocWithoutLogging(err.Error(), "400")
+			case util.ErrConflict:
+				statusCode = http.StatusConflict
+				message = util.ErrorDocWithoutLogging(err.Error(), "409")
+			case util.ErrGone:
+				statusCode = http.StatusGone
+				message = util.ErrorDocWithoutLogging(err.Error(), "410")
+			case util.ErrUnprocessableEntity:
+				statusCode = http.StatusUnprocessableEntity
+				message = util.ErrorDocWithoutLogging(err.Error(), "422")
+			case util.ErrServiceUnavailable:
+				setRetryAfter(c, err.(util.ErrServiceUnavailable).RetryAfter)
+				statusCode = http.StatusServiceUnavailable
+				message = util.ErrorDocWithoutLogging(err.Error(), "503")
 			default:
 				statusCode = http.StatusInternalServerError
 				message = util.ErrorDoc(fmt.Sprintf("Internal Server Error: %v", err.Error()), "500")
@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 )
 
@@ -57,3 +59,114 @@ func TestNoError(t *testing.T) {
 	}
 
 }
+
+func TestConflictError(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/",
+		nil,
+		map[string]interface{}{},
+	)
+
+	conflict := HandleErrors(func(echo.Context) error { return util.NewErrConflict("already exists") })
+	err := conflict(c)
+
+	if err != nil {
+		t.Error("caught an error when there should not have been one")
+	}
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf("%v was returned instead of %v", rec.Code, http.StatusConflict)
+	}
+
+	body, _ := ioutil.ReadAll(rec.Body)
+
+	if !strings.Contains(string(body), "already exists") {
+		t.Errorf("malformed body: %s", body)
+	}
+}
+
+func TestGoneError(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/",
+		nil,
+		map[string]interface{}{},
+	)
+
+	gone := HandleErrors(func(echo.Context) error { return util.NewErrGone("rhcConnection") })
+	err := gone(c)
+
+	if err != nil {
+		t.Error("caught an error when there should not have been one")
+	}
+
+	if rec.Code != http.StatusGone {
+		t.Errorf("%v was returned instead of %v", rec.Code, http.StatusGone)
+	}
+
+	body, _ := ioutil.ReadAll(rec.Body)
+
+	if !strings.Contains(string(body), "rhcConnection no longer exists") {
+		t.Errorf("malformed body: %s", body)
+	}
+}
+
+func TestUnprocessableEntityError(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/",
+		nil,
+		map[string]interface{}{},
+	)
+
+	unprocessable := HandleErrors(func(echo.Context) error { return util.NewErrUnprocessableEntity("invalid cost center") })
+	err := unprocessable(c)
+
+	if err != nil {
+		t.Error("caught an error when there should not have been one")
+	}
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("%v was returned instead of %v", rec.Code, http.StatusUnprocessableEntity)
+	}
+
+	body, _ := ioutil.ReadAll(rec.Body)
+
+	if !strings.Contains(string(body), "invalid cost center") {
+		t.Errorf("malformed body: %s", body)
+	}
+}
+
+func TestServiceUnavailableError(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/",
+		nil,
+		map[string]interface{}{},
+	)
+
+	unavailable := HandleErrors(func(echo.Context) error {
+		return util.NewErrServiceUnavailable("under maintenance", 1500*time.Millisecond)
+	})
+	err := unavailable(c)
+
+	if err != nil {
+		t.Error("caught an error when there should not have been one")
+	}
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("%v was returned instead of %v", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	// The amount of time is rounded up to the next whole second.
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf(`want a "Retry-After" of "2", got "%s"`, got)
+	}
+
+	body, _ := ioutil.ReadAll(rec.Body)
+
+	if !strings.Contains(string(body), "under maintenance") {
+		t.Errorf("malformed body: %s", body)
+	}
+}
@@ -2,9 +2,11 @@ package middleware
 
 import (
 	l "github.com/RedHatInsights/sources-api-go/logger"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
 	"github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/labstack/echo/v4"
+	"github.com/redhatinsights/platform-go-middlewares/identity"
 )
 
 // RaiseEvent calls the "RaiseEvent" function once the previous handler has succeeded. It grabs the resource and the
@@ -57,14 +59,46 @@ func RaiseEvent(next echo.HandlerFunc) echo.HandlerFunc {
 			return err
 		}
 
+		// the undelivered events get stored as dead letters for the tenant, so that they can be replayed.
+		tenantId, hasTenant := c.Get(h.TENANTID).(int64)
+		actor := auditActor(c)
+
 		// async!
 		go func() {
+			if hasTenant {
+				err := service.RecordAuditLog(tenantId, eventType, resource, actor)
+				if err != nil {
+					l.Log.Errorf("Error storing the audit log of the event %v: %v", eventType, err)
+				}
+			}
+
 			err := service.RaiseEvent(eventType, resource, headers)
 			if err != nil {
 				l.Log.Warnf("Error raising event %v: %v", eventType, err)
+
+				if hasTenant {
+					err = service.RecordDeadLetter(tenantId, eventType, resource, headers, err)
+					if err != nil {
+						l.Log.Errorf("Error storing the undelivered event %v as a dead letter: %v", eventType, err)
+					}
+				}
 			}
 		}()
 
 		return nil
 	}
 }
+
+// auditActor returns who made the request, for the audit trail: the identity's user, or "psk" for the services using a
+// PSK.
+func auditActor(c echo.Context) string {
+	if id, ok := c.Get(h.PARSED_IDENTITY).(*identity.XRHID); ok && id.Identity.User.Username != "" {
+		return id.Identity.User.Username
+	}
+
+	if _, ok := c.Get(h.PSK).(string); ok {
+		return "psk"
+	}
+
+	return ""
+}
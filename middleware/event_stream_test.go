@@ -1,16 +1,20 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"testing"
 	"time"
 
+	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/internal/events"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
+	"github.com/redhatinsights/platform-go-middlewares/identity"
 )
 
 var raiseMiddleware = RaiseEvent
@@ -181,3 +185,49 @@ func TestSkipOnContext(t *testing.T) {
 		t.Errorf("Wrong number of Hits to raise event, got %v expected %v", s.Hit, 0)
 	}
 }
+
+// TestRaiseEventRecordsAuditLog tests that the raised events are recorded in the tenant's audit trail along with the
+// user who made the change.
+func TestRaiseEventRecordsAuditLog(t *testing.T) {
+	s := mocks.MockSender{}
+	service.Producer = func() events.Sender { return events.EventStreamProducer{Sender: &s} }
+	c, _ := request.CreateTestContext(http.MethodGet, "/", nil, map[string]interface{}{
+		"tenantID": int64(1),
+		"identity": &identity.XRHID{Identity: identity.Identity{User: identity.User{Username: "jdoe"}}},
+	})
+
+	testAuditLogDao := &dao.MockAuditLogDao{}
+	dao.GetAuditLogDao = func(context.Context) dao.AuditLogDao { return testAuditLogDao }
+	defer func() { dao.GetAuditLogDao = func(context.Context) dao.AuditLogDao { return auditLogDao } }()
+
+	f := raiseMiddleware(func(c echo.Context) error {
+		c.Set("event_type", "Thing.create")
+		c.Set("resource", &fakeEvent{raised: true})
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	err := f(c)
+	if err != nil {
+		t.Errorf("Got an error when none would have been expected: %v", err)
+	}
+
+	// sleep in order for goroutine to run
+	time.Sleep(50 * time.Millisecond)
+
+	// The events raised by the previous tests might get recorded late, so only the ones made by the test's user are
+	// looked at.
+	var recorded []model.AuditLog
+	for _, auditLog := range testAuditLogDao.AuditLogs {
+		if auditLog.Actor == "jdoe" {
+			recorded = append(recorded, auditLog)
+		}
+	}
+
+	if len(recorded) != 1 {
+		t.Fatalf(`want one audit log, got "%d"`, len(recorded))
+	}
+
+	if recorded[0].TenantId != 1 || recorded[0].EventType != "Thing.create" || recorded[0].ResourceType != "Thing" {
+		t.Errorf(`want an audit log of a "Thing" created for tenant "1", got "%+v"`, recorded[0])
+	}
+}
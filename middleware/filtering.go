@@ -1,13 +1,23 @@
 package middleware
 
 import (
+	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 )
 
-var BadQueryParams = []string{"limit", "offset", "sort_by"}
+// BadQueryParams are the query parameters which are not treated as filters, since they tweak the listing itself.
+var BadQueryParams = []string{"limit", "offset", "sort_by", "include_source_type"}
+
+var (
+	filterValueMaxLength = config.Get().FilterValueMaxLength
+	maxFiltersPerRequest = config.Get().MaxFiltersPerRequest
+	maxInFilterValues    = config.Get().MaxInFilterValues
+)
 
 func SortAndFilter(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -16,6 +26,12 @@ func SortAndFilter(next echo.HandlerFunc) echo.HandlerFunc {
 			filters = append(filters, *sort)
 		}
 
+		// The oversized filters are rejected before they reach any query or log line.
+		err := validateFilterSizes(filters)
+		if err != nil {
+			return err
+		}
+
 		c.Set("filters", filters)
 		return next(c)
 	}
@@ -69,6 +85,52 @@ func parseFilter(c echo.Context) []util.Filter {
 	return f
 }
 
+// validateFilterSizes returns a "bad request" error when there are too many filters, or when any of their values is
+// too long. The values of the "in" filters are comma separated lists, so the length cap applies to each of their
+// items, and the number of items is capped instead.
+func validateFilterSizes(filters []util.Filter) error {
+	if len(filters) > maxFiltersPerRequest {
+		return util.NewErrBadRequest(fmt.Sprintf("too many filters, up to %d are accepted", maxFiltersPerRequest))
+	}
+
+	for _, filter := range filters {
+		name := filter.Name
+		if filter.Operation == "sort_by" {
+			name = "sort_by"
+		}
+
+		values := filter.Value
+		if filter.Operation == "in" {
+			values = splitInFilterValues(filter.Value)
+			if len(values) > maxInFilterValues {
+				return util.NewErrBadRequest(fmt.Sprintf("the \"in\" filter %q accepts up to %d values", name, maxInFilterValues))
+			}
+		}
+
+		for _, value := range values {
+			if len(value) > filterValueMaxLength {
+				return util.NewErrBadRequest(fmt.Sprintf("the value of the filter %q is too long, up to %d characters are accepted", name, filterValueMaxLength))
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitInFilterValues returns the non-empty items of the comma separated values of an "in" filter.
+func splitInFilterValues(rawValues []string) []string {
+	values := make([]string, 0, len(rawValues))
+	for _, rawValue := range rawValues {
+		for _, value := range strings.Split(rawValue, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
+
 func parseSorting(c echo.Context) *util.Filter {
 	for k, v := range c.QueryParams() {
 		if k == "sort_by" {
@@ -78,3 +140,116 @@ func parseSorting(c echo.Context) *util.Filter {
 
 	return nil
 }
+
+// ColumnType is the type of the column a filter is applied to, which determines the operations the filter supports.
+type ColumnType int
+
+const (
+	StringColumn ColumnType = iota
+	IntegerColumn
+	TimestampColumn
+	BooleanColumn
+	// JSONColumn is a column which can only be filtered by equality, such as the connections' tags.
+	JSONColumn
+)
+
+// commonFilterOperations are the operations every column type supports. The empty operation stands for "eq".
+var commonFilterOperations = []string{"", "eq", "not_eq", "in", "null", "nil", "not_nil"}
+
+// filterOperationsByColumnType are the operations each column type supports on top of the common ones.
+var filterOperationsByColumnType = map[ColumnType][]string{
+	StringColumn:    {"contains", "starts_with", "ends_with", "eq_i", "not_eq_i", "contains_i", "starts_with_i", "ends_with_i"},
+	IntegerColumn:   {"gt", "gte", "lt", "lte"},
+	TimestampColumn: {"gt", "gte", "lt", "lte"},
+}
+
+// filterKeyRegex matches the well formed filter query parameters: "filter[field]", "filter[field][operation]",
+// "filter[subresource][field]" and "filter[subresource][field][operation]". Any of them may end with an empty "[]",
+// as in "filter[rhc_id][]=a&filter[rhc_id][]=b", to repeat the filter with several values.
+var filterKeyRegex = regexp.MustCompile(`^filter(\[\w+\]){1,3}(\[\])?$`)
+
+// ParseFilters returns a middleware which parses the filters and the sorting of the request just like
+// "SortAndFilter", but which only accepts the given fields, along with the operations their column types support.
+// The subresources' fields are given as "subresource.field". Any other field, operation or malformed filter is
+// rejected with a "bad request" error before it reaches any query.
+func ParseFilters(allowedFields map[string]ColumnType) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			for key := range c.QueryParams() {
+				if strings.HasPrefix(key, "filter") && !filterKeyRegex.MatchString(key) {
+					return util.NewErrBadRequest(fmt.Sprintf("malformed filter %q, expected \"filter[field]\" or \"filter[field][operation]\"", key))
+				}
+			}
+
+			filters := parseFilter(c)
+			if sort := parseSorting(c); sort != nil {
+				filters = append(filters, *sort)
+			}
+
+			err := validateFilterSizes(filters)
+			if err != nil {
+				return err
+			}
+
+			err = validateFilterFields(filters, allowedFields)
+			if err != nil {
+				return err
+			}
+
+			c.Set("filters", filters)
+			return next(c)
+		}
+	}
+}
+
+// validateFilterFields returns a "bad request" error when any of the filters or the sorting refers to a field which
+// is not allowed, or when a filter uses an operation which its field's column type doesn't support.
+func validateFilterFields(filters []util.Filter, allowedFields map[string]ColumnType) error {
+	for _, filter := range filters {
+		if filter.Operation == "sort_by" {
+			err := validateSortFields(filter.Value, allowedFields)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		field := filter.Name
+		if filter.Subresource != "" {
+			field = filter.Subresource + "." + filter.Name
+		}
+
+		columnType, ok := allowedFields[field]
+		if !ok {
+			return util.NewErrBadRequest(fmt.Sprintf("unknown filter field %q", field))
+		}
+
+		if !util.SliceContainsString(commonFilterOperations, filter.Operation) && !util.SliceContainsString(filterOperationsByColumnType[columnType], filter.Operation) {
+			return util.NewErrBadRequest(fmt.Sprintf("unsupported operation %q for the filter field %q", filter.Operation, field))
+		}
+	}
+
+	return nil
+}
+
+// validateSortFields returns a "bad request" error when the sorting refers to a field which is not allowed, or when it
+// uses a direction other than "asc" or "desc". The values get joined just like they are when the sorting is applied.
+func validateSortFields(values []string, allowedFields map[string]ColumnType) error {
+	sorting := strings.Join(values, " ")
+
+	parts := strings.Fields(sorting)
+	if len(parts) == 0 || len(parts) > 2 {
+		return util.NewErrBadRequest(fmt.Sprintf("malformed sorting %q, expected \"field\" or \"field direction\"", sorting))
+	}
+
+	if _, ok := allowedFields[parts[0]]; !ok {
+		return util.NewErrBadRequest(fmt.Sprintf("unknown sort field %q", parts[0]))
+	}
+
+	if len(parts) == 2 && !strings.EqualFold(parts[1], "asc") && !strings.EqualFold(parts[1], "desc") {
+		return util.NewErrBadRequest(fmt.Sprintf("unknown sort direction %q, expected \"asc\" or \"desc\"", parts[1]))
+	}
+
+	return nil
+}
@@ -1,11 +1,15 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 )
 
@@ -62,6 +66,29 @@ func TestParseFilterWithoutOperation(t *testing.T) {
 	}
 }
 
+// TestParseFilterArrayValues tests that the values of the filters given as arrays, such as
+// "filter[rhc_id][]=a&filter[rhc_id][]=b", are gathered in the same filter.
+func TestParseFilterArrayValues(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/rhc_connections?filter[rhc_id][]=a&filter[rhc_id][]=b", nil)
+	c := e.NewContext(req, nil)
+
+	filters := parseFilter(c)
+
+	if len(filters) != 1 {
+		t.Fatalf("wrong number of filters")
+	}
+
+	f := filters[0]
+
+	if f.Name != "rhc_id" || f.Operation != "" {
+		t.Errorf(`want the "rhc_id" filter without an operation, got "%v"`, f)
+	}
+
+	if len(f.Value) != 2 || f.Value[0] != "a" || f.Value[1] != "b" {
+		t.Errorf(`want the values "a" and "b", got "%v"`, f.Value)
+	}
+}
+
 func TestParseSorting(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?sort_by=name", nil)
 	c := e.NewContext(req, nil)
@@ -201,3 +228,137 @@ func TestParseFilteringWithoutFilterArgWithLimit(t *testing.T) {
 		t.Errorf("too many filters were parsed - should have been one")
 	}
 }
+
+// TestSortAndFilterSizeLimits tests that the requests with too many filters or with too long filter values are
+// rejected before reaching the handler.
+func TestSortAndFilterSizeLimits(t *testing.T) {
+	tooManyFilters := make([]string, 0, maxFiltersPerRequest+1)
+	for i := 0; i <= maxFiltersPerRequest; i++ {
+		tooManyFilters = append(tooManyFilters, fmt.Sprintf("filter[field%d]=value", i))
+	}
+
+	// The "in" filters can carry up to "maxInFilterValues" UUIDs, which together are way longer than a single value.
+	uuids := make([]string, 0, maxInFilterValues+1)
+	for i := 0; i <= maxInFilterValues; i++ {
+		uuids = append(uuids, fmt.Sprintf("%08d-aaaa-bbbb-cccc-dddddddddddd", i))
+	}
+
+	testCases := []struct {
+		name      string
+		query     string
+		wantError bool
+	}{
+		{name: "valid filters", query: "filter[name]=test&sort_by=name", wantError: false},
+		{name: "in filter with the maximum number of values", query: "filter[rhc_id][in]=" + strings.Join(uuids[:maxInFilterValues], ","), wantError: false},
+		{name: "in filter with too many values", query: "filter[rhc_id][in]=" + strings.Join(uuids, ","), wantError: true},
+		{name: "in filter value too long", query: "filter[name][in]=a," + strings.Repeat("a", filterValueMaxLength+1), wantError: true},
+		{name: "value at the limit", query: "filter[name]=" + strings.Repeat("a", filterValueMaxLength), wantError: false},
+		{name: "value too long", query: "filter[name]=" + strings.Repeat("a", filterValueMaxLength+1), wantError: true},
+		{name: "raw filter value too long", query: "name=" + strings.Repeat("a", filterValueMaxLength+1), wantError: true},
+		{name: "sort value too long", query: "sort_by=" + strings.Repeat("a", filterValueMaxLength+1), wantError: true},
+		{name: "too many filters", query: strings.Join(tooManyFilters, "&"), wantError: true},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?"+tc.query, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		handlerCalled := false
+		err := SortAndFilter(func(c echo.Context) error {
+			handlerCalled = true
+			return nil
+		})(c)
+
+		if tc.wantError {
+			if !errors.Is(err, util.ErrBadRequestEmpty) {
+				t.Errorf(`[%s] want a bad request error, got "%v"`, tc.name, err)
+			}
+
+			if handlerCalled {
+				t.Errorf(`[%s] want the handler not to be called, but it was`, tc.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf(`[%s] want no error, got "%s"`, tc.name, err)
+		}
+
+		if !handlerCalled {
+			t.Errorf(`[%s] want the handler to be called, but it wasn't`, tc.name)
+		}
+	}
+}
+
+// TestParseFilters tests that only the allowed fields, with the operations their column types support, get through
+// the middleware, and that the malformed filters are rejected.
+func TestParseFilters(t *testing.T) {
+	allowedFields := map[string]ColumnType{
+		"id":               IntegerColumn,
+		"name":             StringColumn,
+		"created_at":       TimestampColumn,
+		"source_type.name": StringColumn,
+	}
+
+	testCases := []struct {
+		name        string
+		query       string
+		wantFilters int
+		wantError   bool
+	}{
+		{name: "no filters", query: "", wantFilters: 0},
+		{name: "allowed field", query: "filter[name]=test", wantFilters: 1},
+		{name: "allowed operation", query: "filter[name][contains_i]=test&filter[id][gt]=5", wantFilters: 2},
+		{name: "allowed subresource field", query: "filter[source_type][name][eq]=amazon", wantFilters: 1},
+		{name: "allowed sorting", query: "filter[name]=test&sort_by=created_at%20desc", wantFilters: 2},
+		{name: "raw filter", query: "id=5", wantFilters: 1},
+		{name: "repeated array filter", query: "filter[name][]=a&filter[name][]=b", wantFilters: 1},
+		{name: "repeated array filter with an operation", query: "filter[name][eq][]=a&filter[name][eq][]=b", wantFilters: 1},
+		{name: "unknown field", query: "filter[password]=test", wantError: true},
+		{name: "unknown raw field", query: "password=test", wantError: true},
+		{name: "unknown subresource field", query: "filter[source_type][vendor]=Red%20Hat", wantError: true},
+		{name: "unknown operation", query: "filter[name][like]=test", wantError: true},
+		{name: "unsupported operation for the column type", query: "filter[id][contains]=5", wantError: true},
+		{name: "malformed filter", query: "filter=test", wantError: true},
+		{name: "malformed brackets", query: "filter[name=test", wantError: true},
+		{name: "too many segments", query: "filter[a][b][c][d]=test", wantError: true},
+		{name: "empty segment in the middle", query: "filter[name][][eq]=test", wantError: true},
+		{name: "unknown sort field", query: "sort_by=password", wantError: true},
+		{name: "unknown sort direction", query: "sort_by=name%3B%20DROP", wantError: true},
+		{name: "malformed sorting", query: "sort_by=name%20asc%20id", wantError: true},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/rhc_connections?"+tc.query, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		handlerCalled := false
+		err := ParseFilters(allowedFields)(func(c echo.Context) error {
+			handlerCalled = true
+			return nil
+		})(c)
+
+		if tc.wantError {
+			if !errors.Is(err, util.ErrBadRequestEmpty) {
+				t.Errorf(`[%s] want a bad request error, got "%v"`, tc.name, err)
+			}
+
+			if handlerCalled {
+				t.Errorf(`[%s] want the handler not to be called, but it was`, tc.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf(`[%s] want no error, got "%s"`, tc.name, err)
+			continue
+		}
+
+		filters, ok := c.Get("filters").([]util.Filter)
+		if !ok || len(filters) != tc.wantFilters {
+			t.Errorf(`[%s] want "%d" filters, got "%v"`, tc.name, tc.wantFilters, c.Get("filters"))
+		}
+	}
+}
@@ -3,22 +3,38 @@ package middleware
 import (
 	"fmt"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 )
 
+var (
+	// identityHeaderName and pskHeaderName are the names of the request headers the identity and the PSK are read
+	// from. Regardless of their names, they are always stored in the context under the "h.XRHID" and "h.PSK" keys.
+	identityHeaderName = config.Get().IdentityHeaderName
+	pskHeaderName      = config.Get().PskHeaderName
+)
+
+// authenticationRequiredMessage returns the error message for the requests which came without any of the
+// authentication headers.
+func authenticationRequiredMessage() string {
+	return fmt.Sprintf("Authentication required by either [%s] or [%s]", identityHeaderName, pskHeaderName)
+}
+
 /*
    Parse the required headers for processing this request. Currently this
    involves _three_ major headers:
 
    1. `x-rh-identity`: contains the account number and various other information
-      about the request. This is set by 3scale.
+      about the request. This is set by 3scale. The header's name can be
+      changed with the "IDENTITY_HEADER_NAME" environment variable.
 
    2. `x-rh-sources-psk`: a pre-shared-key (psk) which is used internally to
       authenticate from within the CRC cluster. This is checked against a list
       of known keys which are set in vault, if it matches any of them the
-      request is authorized.
+      request is authorized. The header's name can be changed with the
+      "PSK_HEADER_NAME" environment variable.
 
     3. `x-rh-sources-account-number`: used with a PSK to access a certain
        account. Only accessible from within the CRC cluster.
@@ -26,8 +42,8 @@ import (
 func ParseHeaders(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		// the PSK related headers - just storing them as raw strings.
-		if c.Request().Header.Get(h.PSK) != "" {
-			c.Set(h.PSK, c.Request().Header.Get(h.PSK))
+		if c.Request().Header.Get(pskHeaderName) != "" {
+			c.Set(h.PSK, c.Request().Header.Get(pskHeaderName))
 		}
 
 		if c.Request().Header.Get(h.ACCOUNT_NUMBER) != "" {
@@ -39,11 +55,11 @@ func ParseHeaders(next echo.HandlerFunc) echo.HandlerFunc {
 		}
 
 		// parsing the base64-encoded identity header if present
-		if c.Request().Header.Get(h.XRHID) != "" {
+		if c.Request().Header.Get(identityHeaderName) != "" {
 			// store it raw first.
-			c.Set(h.XRHID, c.Request().Header.Get(h.XRHID))
+			c.Set(h.XRHID, c.Request().Header.Get(identityHeaderName))
 
-			xRhIdentity, err := util.ParseXRHIDHeader(c.Request().Header.Get(h.XRHID))
+			xRhIdentity, err := util.ParseXRHIDHeader(c.Request().Header.Get(identityHeaderName))
 			if err != nil {
 				return fmt.Errorf("could not extract identity from header: %s", err)
 			}
@@ -1,5 +1,7 @@
 package headers
 
+// The keys the headers' values are stored under in the request's context. Except for the identity and the PSK, whose
+// request header names are configurable, they are also the names of the request headers.
 const (
 	PSK             = "x-rh-sources-psk"
 	ACCOUNT_NUMBER  = "x-rh-sources-account-number"
@@ -7,4 +9,6 @@ const (
 	XRHID           = "x-rh-identity"
 	PARSED_IDENTITY = "identity"
 	TENANTID        = "tenantID"
+	// PSK_AUTHORIZED is only stored in the context, when the request was authorized by one of the approved PSKs.
+	PSK_AUTHORIZED = "pskAuthorized"
 )
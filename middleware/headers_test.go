@@ -163,3 +163,59 @@ func TestOnlyPskHeaders(t *testing.T) {
 		t.Errorf("%v was set as psk-account instead of %v", c.Get(h.ACCOUNT_NUMBER).(string), "9876")
 	}
 }
+
+// TestParseCustomHeaderNames tests that the identity and the PSK are read from the configured headers, and that they
+// are stored in the context under the usual keys.
+func TestParseCustomHeaderNames(t *testing.T) {
+	originalIdentityHeaderName := identityHeaderName
+	originalPskHeaderName := pskHeaderName
+	identityHeaderName = "x-gateway-identity"
+	pskHeaderName = "x-gateway-psk"
+	defer func() {
+		identityHeaderName = originalIdentityHeaderName
+		pskHeaderName = originalPskHeaderName
+	}()
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/",
+		nil,
+		map[string]interface{}{},
+	)
+
+	c.Request().Header.Set("x-gateway-identity", xrhid)
+	c.Request().Header.Set("x-gateway-psk", "1234")
+	// The default headers are ignored when custom ones are configured.
+	c.Request().Header.Set(h.PSK, "5678")
+
+	err := parseOrElse204(c)
+	if err != nil {
+		t.Errorf("caught an error when there should not have been one: %v", err)
+	}
+
+	if rec.Code != 204 {
+		t.Errorf("%v was returned instead of %v", rec.Code, 204)
+	}
+
+	if c.Get(h.XRHID).(string) != xrhid {
+		t.Errorf(`want the raw identity "%s", got "%v"`, xrhid, c.Get(h.XRHID))
+	}
+
+	if c.Get(h.PSK).(string) != "1234" {
+		t.Errorf(`want psk "%s", got "%v"`, "1234", c.Get(h.PSK))
+	}
+
+	id, ok := c.Get(h.PARSED_IDENTITY).(*identity.XRHID)
+	if !ok {
+		t.Fatalf(`unexpected type of identity received. Want "*identity.XRHID", got "%s"`, reflect.TypeOf(c.Get(h.PARSED_IDENTITY)))
+	}
+
+	if id.Identity.AccountNumber != "12345" {
+		t.Errorf(`want account number "%s", got "%s"`, "12345", id.Identity.AccountNumber)
+	}
+
+	want := "Authentication required by either [x-gateway-identity] or [x-gateway-psk]"
+	if got := authenticationRequiredMessage(); got != want {
+		t.Errorf(`want message "%s", got "%s"`, want, got)
+	}
+}
@@ -1,11 +1,13 @@
 package middleware
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"os"
 	"testing"
 
+	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/parser"
 	"github.com/RedHatInsights/sources-api-go/logger"
 	"github.com/labstack/echo/v4"
@@ -13,6 +15,7 @@ import (
 )
 
 var (
+	auditLogDao   = &dao.MockAuditLogDao{}
 	xrhid         string
 	emptyIdentity = identity.XRHID{Identity: identity.Identity{AccountNumber: "12345"}}
 )
@@ -26,6 +29,10 @@ func TestMain(t *testing.M) {
 
 	logger.InitLogger(conf)
 	e = echo.New()
+
+	// the raised events get recorded in the audit trail.
+	dao.GetAuditLogDao = func(context.Context) dao.AuditLogDao { return auditLogDao }
+
 	code := t.Run()
 	os.Exit(code)
 }
@@ -3,10 +3,14 @@ package middleware
 import (
 	"strconv"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 )
 
+// defaultPageSize is the limit the lists get when the request doesn't specify one.
+var defaultPageSize = config.Get().DefaultPageSize
+
 func Pagination(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		err := parsePaginationIntoContext(c)
@@ -27,7 +31,7 @@ func parsePaginationIntoContext(c echo.Context) error {
 
 		c.Set("limit", val)
 	} else {
-		c.Set("limit", 100)
+		c.Set("limit", defaultPageSize)
 	}
 
 	if c.QueryParam("offset") != "" {
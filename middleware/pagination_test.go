@@ -53,8 +53,8 @@ func TestParsePaginationDefaults(t *testing.T) {
 		t.Error("limit did not get parsed correctly")
 	}
 
-	if limit != 100 {
-		t.Error("limit not parsed to integer correctly")
+	if limit != defaultPageSize {
+		t.Errorf(`want the default page size "%d", got "%d"`, defaultPageSize, limit)
 	}
 
 	offset, ok := c.Get("offset").(int)
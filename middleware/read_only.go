@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	// readOnly rejects the write requests when enabled.
+	readOnly = config.Get().ReadOnly
+	// readOnlyRetryAfter is how long the clients are told to wait before retrying their writes.
+	readOnlyRetryAfter = config.Get().ReadOnlyRetryAfter
+)
+
+// ReadOnlyCheck rejects the write requests —POST/PATCH/PUT/DELETE— with a "503 Service Unavailable" when the read-only
+// mode is enabled, before any other work is done for them. The reads proceed normally.
+func ReadOnlyCheck(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !readOnly {
+			return next(c)
+		}
+
+		switch c.Request().Method {
+		case http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+			return util.NewErrServiceUnavailable(dao.ReadOnlyMessage, readOnlyRetryAfter)
+		default:
+			return next(c)
+		}
+	}
+}
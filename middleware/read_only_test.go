@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// noContent is a handler which just responds with a "204 No Content".
+var noContent = func(c echo.Context) error {
+	return c.NoContent(http.StatusNoContent)
+}
+
+// TestReadOnlyCheckRejectsWrites tests that the writes are rejected with a "service unavailable" error which carries
+// the configured retry delay when the read-only mode is enabled.
+func TestReadOnlyCheckRejectsWrites(t *testing.T) {
+	backupReadOnly, backupRetryAfter := readOnly, readOnlyRetryAfter
+	readOnly, readOnlyRetryAfter = true, time.Minute
+	defer func() { readOnly, readOnlyRetryAfter = backupReadOnly, backupRetryAfter }()
+
+	for _, method := range []string{http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete} {
+		c, _ := request.CreateTestContext(method, "/", nil, map[string]interface{}{})
+
+		err := ReadOnlyCheck(func(c echo.Context) error {
+			t.Errorf(`want the handler not to be run for method "%s", but it was`, method)
+			return nil
+		})(c)
+
+		if !errors.Is(err, util.ErrServiceUnavailableEmpty) {
+			t.Errorf(`want a service unavailable error for method "%s", got "%v"`, method, err)
+			continue
+		}
+
+		if retryAfter := err.(util.ErrServiceUnavailable).RetryAfter; retryAfter != time.Minute {
+			t.Errorf(`want a retry after "%s", got "%s"`, time.Minute, retryAfter)
+		}
+	}
+}
+
+// TestReadOnlyCheckAllowsReads tests that the reads proceed normally in read-only mode, and that the writes proceed
+// normally when the mode is disabled.
+func TestReadOnlyCheckAllowsReads(t *testing.T) {
+	backupReadOnly := readOnly
+	defer func() { readOnly = backupReadOnly }()
+
+	testCases := []struct {
+		readOnly bool
+		method   string
+	}{
+		{readOnly: true, method: http.MethodGet},
+		{readOnly: true, method: http.MethodHead},
+		{readOnly: false, method: http.MethodPost},
+		{readOnly: false, method: http.MethodDelete},
+	}
+
+	for _, tc := range testCases {
+		readOnly = tc.readOnly
+		c, rec := request.CreateTestContext(tc.method, "/", nil, map[string]interface{}{})
+
+		err := ReadOnlyCheck(noContent)(c)
+		if err != nil {
+			t.Errorf(`unexpected error: %s`, err)
+		}
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf(`want status "%d" for method "%s" with read-only "%t", got "%d"`, http.StatusNoContent, tc.method, tc.readOnly, rec.Code)
+		}
+	}
+}
@@ -33,7 +33,7 @@ func SuperKeyDestroySource(next echo.HandlerFunc) echo.HandlerFunc {
 			return util.NewErrBadRequest(err)
 		}
 
-		s := dao.GetSourceDao(&tenantId)
+		s := dao.GetSourceDao(c.Request().Context(), &tenantId)
 
 		if s.IsSuperkey(id) {
 			xrhid, ok := c.Get(h.XRHID).(string)
@@ -73,7 +73,7 @@ func SuperKeyDestroyApplication(next echo.HandlerFunc) echo.HandlerFunc {
 			return util.NewErrBadRequest(err)
 		}
 
-		a := dao.GetApplicationDao(&tenantId)
+		a := dao.GetApplicationDao(c.Request().Context(), &tenantId)
 
 		if a.IsSuperkey(id) {
 			xrhid, ok := c.Get(h.XRHID).(string)
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
+	l "github.com/RedHatInsights/sources-api-go/logger"
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
@@ -46,10 +48,9 @@ func Tenancy(next echo.HandlerFunc) echo.HandlerFunc {
 				},
 			}
 
-			tenantDao := dao.GetTenantDao()
-			tenantId, err := tenantDao.GetOrCreateTenantID(&id.Identity)
+			tenantId, err := resolveTenantId(c, &id.Identity)
 			if err != nil {
-				return fmt.Errorf("failed to get or create tenant for request: %s", err)
+				return err
 			}
 
 			c.Set(h.PARSED_IDENTITY, id)
@@ -71,10 +72,9 @@ func Tenancy(next echo.HandlerFunc) echo.HandlerFunc {
 				},
 			}
 
-			tenantDao := dao.GetTenantDao()
-			tenantId, err := tenantDao.GetOrCreateTenantID(&id.Identity)
+			tenantId, err := resolveTenantId(c, &id.Identity)
 			if err != nil {
-				return fmt.Errorf("failed to get or create tenant for request: %s", err)
+				return err
 			}
 
 			c.Set(h.PARSED_IDENTITY, id)
@@ -100,18 +100,74 @@ func Tenancy(next echo.HandlerFunc) echo.HandlerFunc {
 
 			c.Logger().Debugf("[org_id: %s][account_number: %s] Looking up Tenant ID", identity.Identity.OrgID, identity.Identity.AccountNumber)
 
-			tenantDao := dao.GetTenantDao()
-			tenantId, err := tenantDao.GetOrCreateTenantID(&identity.Identity)
+			tenantId, err := resolveTenantId(c, &identity.Identity)
 			if err != nil {
-				return fmt.Errorf("failed to get or create tenant for request: %s", err)
+				return err
 			}
 
 			c.Set(h.TENANTID, tenantId)
 
 		default:
-			return c.JSON(http.StatusUnauthorized, util.ErrorDoc("Authentication required by either [x-rh-identity] or [x-rh-sources-psk]", "401"))
+			return c.JSON(http.StatusUnauthorized, util.ErrorDoc(authenticationRequiredMessage(), "401"))
+		}
+
+		// Tag the request's queries with the tenant, so that the SQL logs can be attributed to it.
+		if tenantId, ok := c.Get(h.TENANTID).(int64); ok {
+			c.SetRequest(c.Request().WithContext(l.ContextWithTenantId(c.Request().Context(), tenantId)))
 		}
 
 		return next(c)
 	}
 }
+
+// resolveTenantId returns the ID of the tenant of the given identity, either from the cache or from the database.
+func resolveTenantId(c echo.Context, id *identity.Identity) (int64, error) {
+	key := "account_number:" + id.AccountNumber
+	if id.OrgID != "" {
+		key = "org_id:" + id.OrgID
+	}
+
+	if tenantId, ok := tenants.Get(key); ok {
+		return tenantId.(int64), nil
+	}
+
+	start := time.Now()
+	tenantId, err := lookUpTenantId(c, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if elapsed := time.Since(start); elapsed > slowTenantLookup {
+		l.Log.Warnf(`[org_id: %s][account_number: %s] Slow tenant lookup: %s`, id.OrgID, id.AccountNumber, elapsed)
+	}
+
+	tenants.Set(key, tenantId)
+
+	return tenantId, nil
+}
+
+// lookUpTenantId returns the ID of the tenant of the given identity from the database. The tenants with an OrgId get
+// onboarded on their first request, which makes sure that their default quota is in place before any other DAO is
+// used. The tenants which only have an EBS account number are just fetched or created. Since the reads depend on
+// these writes, they are exempted from the read-only mode.
+func lookUpTenantId(c echo.Context, id *identity.Identity) (int64, error) {
+	ctx := dao.ExemptFromReadOnly(c.Request().Context())
+
+	if id.OrgID != "" {
+		onboardingDao := dao.GetTenantOnboardingDao(ctx)
+		tenant, err := onboardingDao.EnsureOnboarded(id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to onboard tenant for request: %s", err)
+		}
+
+		return tenant.Id, nil
+	}
+
+	tenantDao := dao.GetTenantDao(ctx)
+	tenantId, err := tenantDao.GetOrCreateTenantID(id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get or create tenant for request: %s", err)
+	}
+
+	return tenantId, nil
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// slowTenantLookup is the duration after which the tenant lookups are considered slow, and get logged.
+const slowTenantLookup = 10 * time.Millisecond
+
+// tenants caches the tenant IDs the identities resolve to, so that the high frequency callers, such as the health
+// pollers, don't hit the database on every request.
+var tenants = util.NewLruCache(config.Get().TenantCacheSize, config.Get().TenantCacheTtl)
+
+// FlushTenantCache empties the cache of the resolved tenants, so that the tests which modify the tenants don't get
+// stale IDs.
+func FlushTenantCache() {
+	tenants.Flush()
+}
@@ -14,6 +14,9 @@ type Application struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	PausedAt  *time.Time `json:"paused_at"`
+	// PausedBeforeArchive is false for the applications which got paused along with their archived source, so that
+	// only those get resumed with it. It is null for the rest of the applications.
+	PausedBeforeArchive *bool `json:"-"`
 
 	AvailabilityStatus      string     `json:"availability_status,omitempty"`
 	LastCheckedAt           *time.Time `json:"last_checked_at,omitempty"`
@@ -0,0 +1,12 @@
+package model
+
+// AppAvailabilitySummary holds the number of applications a tenant has on each availability status, along with the
+// percentage of them that are available.
+type AppAvailabilitySummary struct {
+	Total              int     `json:"total"`
+	Available          int     `json:"available"`
+	Unavailable        int     `json:"unavailable"`
+	Unknown            int     `json:"unknown"`
+	PartiallyAvailable int     `json:"partially_available"`
+	PercentAvailable   float64 `json:"percent_available"`
+}
@@ -39,3 +39,9 @@ type ApplicationEditRequest struct {
 	LastCheckedAt   *string `json:"last_checked_at"`
 	LastAvailableAt *string `json:"last_available_at"`
 }
+
+// ApplicationCredentialValidationResponse is the result of validating an application's credentials.
+type ApplicationCredentialValidationResponse struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
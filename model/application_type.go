@@ -44,6 +44,15 @@ func (a *ApplicationType) ToResponse() *ApplicationTypeResponse {
 	}
 }
 
+// ToCompatibleTypeResponse returns the application type's representation in a compatibility matrix.
+func (a *ApplicationType) ToCompatibleTypeResponse() CompatibleTypeResponse {
+	return CompatibleTypeResponse{
+		Id:          strconv.FormatInt(a.Id, 10),
+		Name:        a.Name,
+		DisplayName: a.DisplayName,
+	}
+}
+
 // AvailabilityCheckURL returns the application's availability check URL, e.g. where to send the
 // request for the client to re-check the application's availability status.
 func (at *ApplicationType) AvailabilityCheckURL() *url.URL {
@@ -16,3 +16,10 @@ type ApplicationTypeResponse struct {
 	SupportedSourceTypes         datatypes.JSON `json:"supported_source_types"`
 	SupportedAuthenticationTypes datatypes.JSON `json:"supported_authentication_types"`
 }
+
+// ApplicationTypeWithIncludesResponse is the single application type response, which includes the source types the
+// application type supports.
+type ApplicationTypeWithIncludesResponse struct {
+	*ApplicationTypeResponse
+	Includes []CompatibleTypeResponse `json:"includes"`
+}
@@ -0,0 +1,21 @@
+package model
+
+// ApplicationWithSourceType is an application along with the name of its source's type, so that the clients don't
+// need to look the source up to know it.
+type ApplicationWithSourceType struct {
+	Application
+	SourceTypeName string
+}
+
+// ApplicationWithSourceTypeResponse is the response for an application along with its source's type name.
+type ApplicationWithSourceTypeResponse struct {
+	*ApplicationResponse
+	SourceTypeName string `json:"source_type_name"`
+}
+
+func (app *ApplicationWithSourceType) ToResponse() *ApplicationWithSourceTypeResponse {
+	return &ApplicationWithSourceTypeResponse{
+		ApplicationResponse: app.Application.ToResponse(),
+		SourceTypeName:      app.SourceTypeName,
+	}
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// AuditLog records a change made to one of the tenant's resources.
+type AuditLog struct {
+	ID           int64 `gorm:"primaryKey"`
+	EventType    string
+	ResourceType string
+	ResourceId   string
+	// Actor is the user who made the change, or "psk" when it was made by a service using a PSK.
+	Actor     string
+	TenantId  int64
+	Tenant    Tenant
+	CreatedAt time.Time
+}
+
+// AuditLogCsvHeader is the header of the exported audit logs.
+var AuditLogCsvHeader = []string{"id", "created_at", "event_type", "resource_type", "resource_id", "actor"}
+
+// ToCsvRecord returns the audit log as a CSV record which follows the "AuditLogCsvHeader" columns.
+func (a *AuditLog) ToCsvRecord() []string {
+	return []string{
+		strconv.FormatInt(a.ID, 10),
+		a.CreatedAt.UTC().Format(time.RFC3339Nano),
+		a.EventType,
+		a.ResourceType,
+		a.ResourceId,
+		a.Actor,
+	}
+}
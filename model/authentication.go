@@ -29,6 +29,8 @@ type Authentication struct {
 	AvailabilityStatus      *string    `json:"availability_status,omitempty"`
 	LastCheckedAt           *time.Time `json:"last_checked_at,omitempty"`
 	LastAvailableAt         *time.Time `json:"last_available_at,omitempty"`
+	LastUsedAt              *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt               *time.Time `json:"expires_at,omitempty"`
 	AvailabilityStatusError *string    `json:"availability_status_error,omitempty"`
 
 	SourceID int64 `json:"source_id"`
@@ -66,6 +68,7 @@ func (auth *Authentication) ToResponse() *AuthenticationResponse {
 		Extra:                   extra,
 		AvailabilityStatus:      util.ValueOrBlank(auth.AvailabilityStatus),
 		AvailabilityStatusError: util.ValueOrBlank(auth.AvailabilityStatusError),
+		ExpiresAt:               util.DateTimePointerToRFC3339(auth.ExpiresAt),
 		ResourceType:            auth.ResourceType,
 		ResourceID:              resourceID,
 	}
@@ -117,6 +120,8 @@ func (auth *Authentication) ToVaultMap() (map[string]interface{}, error) {
 		"availability_status_error": auth.AvailabilityStatusError,
 		"last_checked_at":           auth.LastCheckedAt,
 		"last_available_at":         auth.LastAvailableAt,
+		"last_used_at":              auth.LastUsedAt,
+		"expires_at":                auth.ExpiresAt,
 		"resource_type":             auth.ResourceType,
 		"resource_id":               strconv.FormatInt(auth.ResourceID, 10),
 		"source_id":                 strconv.FormatInt(auth.SourceID, 10),
@@ -17,6 +17,7 @@ type AuthenticationResponse struct {
 	Extra                   map[string]interface{} `json:"extra,omitempty"`
 	AvailabilityStatus      string                 `json:"availability_status,omitempty"`
 	AvailabilityStatusError string                 `json:"availability_status_error,omitempty"`
+	ExpiresAt               string                 `json:"expires_at,omitempty"`
 
 	ResourceType string `json:"resource_type"`
 	ResourceID   string `json:"resource_id"`
@@ -46,6 +47,7 @@ type AuthenticationCreateRequest struct {
 	Password                *string                `json:"password,omitempty"`
 	Extra                   map[string]interface{} `json:"extra,omitempty"`
 	AvailabilityStatusError *string                `json:"availability_status_error,omitempty"`
+	ExpiresAt               *time.Time             `json:"expires_at,omitempty"`
 
 	ResourceType  string      `json:"resource_type"`
 	ResourceIDRaw interface{} `json:"resource_id"`
@@ -60,6 +62,7 @@ type AuthenticationEditRequest struct {
 	Extra                   *map[string]interface{} `json:"extra,omitempty"`
 	AvailabilityStatus      *string                 `json:"availability_status,omitempty"`
 	AvailabilityStatusError *string                 `json:"availability_status_error,omitempty"`
+	ExpiresAt               *time.Time              `json:"expires_at,omitempty"`
 }
 
 func (auth *Authentication) UpdateFromRequest(update *AuthenticationEditRequest) error {
@@ -99,6 +102,9 @@ func (auth *Authentication) UpdateFromRequest(update *AuthenticationEditRequest)
 	if update.AvailabilityStatusError != nil {
 		auth.AvailabilityStatusError = update.AvailabilityStatusError
 	}
+	if update.ExpiresAt != nil {
+		auth.ExpiresAt = update.ExpiresAt
+	}
 
 	return nil
 }
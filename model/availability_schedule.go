@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// AvailabilitySchedule holds when the availability of a source gets periodically checked.
+type AvailabilitySchedule struct {
+	SourceId  int64 `gorm:"primaryKey"`
+	Source    Source
+	TenantId  int64
+	Tenant    Tenant
+	CronExpr  string
+	NextRunAt time.Time
+	LastRunAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// AvailabilityScheduleUpsertRequest holds the cron expression the availability of a source gets checked with.
+type AvailabilityScheduleUpsertRequest struct {
+	CronExpr string `json:"cron_expr"`
+}
@@ -1,11 +1,19 @@
 package model
 
+import (
+	"fmt"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
 // Availability statuses.
 const (
 	Available          string = "available"
 	InProgress         string = "in_progress"
 	PartiallyAvailable string = "partially_available"
 	Unavailable        string = "unavailable"
+	// Unknown is only used to summarize the statuses which aren't known yet, and it is never stored.
+	Unknown string = "unknown"
 )
 
 // AvailabilityStatuses contains the possible valid values of the status of a source
@@ -16,3 +24,54 @@ var AvailabilityStatuses = []string{
 	PartiallyAvailable,
 	Unavailable,
 }
+
+// ValidSourceTransitions is the graph of the availability status transitions, as an adjacency list keyed by the status
+// the transitions start from. The empty status, which the resources have until their availability is first checked,
+// is keyed as "unknown". The statuses may currently change freely between each other.
+var ValidSourceTransitions = completeTransitions(Unknown, Available, InProgress, PartiallyAvailable, Unavailable)
+
+// completeTransitions returns the transitions which allow every given status to change to any other one.
+func completeTransitions(statuses ...string) map[string][]string {
+	transitions := make(map[string][]string, len(statuses))
+	for _, from := range statuses {
+		transitions[from] = make([]string, 0, len(statuses)-1)
+		for _, to := range statuses {
+			if to != from {
+				transitions[from] = append(transitions[from], to)
+			}
+		}
+	}
+
+	return transitions
+}
+
+// TransitionState returns how the given status is keyed in "ValidSourceTransitions".
+func TransitionState(status string) string {
+	if status == "" {
+		return Unknown
+	}
+
+	return status
+}
+
+// ValidateTransition returns an error when the availability status cannot change from the previous status to the next
+// one, according to "ValidSourceTransitions". Keeping the same status is always valid.
+func ValidateTransition(previous, next string) error {
+	if !util.SliceContainsString(AvailabilityStatuses, next) {
+		return fmt.Errorf("invalid availability status transition from %q to %q", previous, next)
+	}
+
+	from, to := TransitionState(previous), TransitionState(next)
+	if from == to {
+		return nil
+	}
+
+	// The statuses which were stored before they got validated are not part of the graph, and they may change to any
+	// valid status.
+	transitions, ok := ValidSourceTransitions[from]
+	if ok && !util.SliceContainsString(transitions, to) {
+		return fmt.Errorf("invalid availability status transition from %q to %q", previous, next)
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package model
+
+import "testing"
+
+// TestValidateTransition tests that the transitions to the valid statuses are allowed, and that the rest are rejected.
+func TestValidateTransition(t *testing.T) {
+	for _, next := range AvailabilityStatuses {
+		if err := ValidateTransition(Available, next); err != nil {
+			t.Errorf(`want the transition to "%s" to be valid, got "%s"`, next, err)
+		}
+	}
+
+	if err := ValidateTransition(Available, "bogus"); err == nil {
+		t.Errorf(`want the transition to "bogus" to be invalid, got no error`)
+	}
+}
+
+// TestValidSourceTransitions tests that every status can change to every other one, including the unknown one, and
+// that the transitions are validated against the graph.
+func TestValidSourceTransitions(t *testing.T) {
+	for _, from := range AvailabilityStatuses {
+		transitions, ok := ValidSourceTransitions[TransitionState(from)]
+		if !ok {
+			t.Errorf(`want the transitions from "%s", got none`, from)
+			continue
+		}
+
+		if len(transitions) != len(AvailabilityStatuses)-1 {
+			t.Errorf(`want "%d" transitions from "%s", got "%v"`, len(AvailabilityStatuses)-1, from, transitions)
+		}
+
+		for _, next := range AvailabilityStatuses {
+			if err := ValidateTransition(from, next); err != nil {
+				t.Errorf(`want the transition from "%s" to "%s" to be valid, got "%s"`, from, next, err)
+			}
+		}
+	}
+}
@@ -91,3 +91,11 @@ func (b BulkCreateOutput) ToResponse() *BulkCreateResponse {
 
 	return &resp
 }
+
+// SourceImportRowResponse is the outcome of importing one of the rows of a CSV file. The rows are numbered from one,
+// without counting the header.
+type SourceImportRowResponse struct {
+	Row    int    `json:"row"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
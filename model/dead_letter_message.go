@@ -0,0 +1,105 @@
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/datatypes"
+)
+
+// The statuses a dead letter message goes through.
+const (
+	DeadLetterPending  = "pending"
+	DeadLetterReplayed = "replayed"
+)
+
+// DeadLetterStatuses are the valid statuses of the dead letter messages.
+var DeadLetterStatuses = []string{DeadLetterPending, DeadLetterReplayed}
+
+// DeadLetterMessage records a message which could not be delivered to Kafka, so that it can be inspected and replayed
+// later.
+type DeadLetterMessage struct {
+	ID        int64 `gorm:"primaryKey"`
+	Topic     string
+	EventType string
+	Payload   datatypes.JSON
+	// Headers holds the message's Kafka headers as a list of "DeadLetterHeader"s.
+	Headers    datatypes.JSON
+	Error      string
+	Status     string
+	TenantId   int64
+	Tenant     Tenant
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	RequeuedAt *time.Time
+}
+
+// DeadLetterHeader is the representation of the Kafka headers which gets stored along with the dead letter messages.
+type DeadLetterHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// DeadLetterMessageResponse is the representation of the dead letter messages which is returned to the clients.
+type DeadLetterMessageResponse struct {
+	Id         string         `json:"id"`
+	Topic      string         `json:"topic"`
+	EventType  string         `json:"event_type"`
+	Payload    datatypes.JSON `json:"payload"`
+	Error      string         `json:"error"`
+	Status     string         `json:"status"`
+	CreatedAt  string         `json:"created_at"`
+	RequeuedAt string         `json:"requeued_at,omitempty"`
+}
+
+// SetKafkaHeaders stores the given Kafka headers in the message.
+func (d *DeadLetterMessage) SetKafkaHeaders(headers []kafka.Header) error {
+	deadLetterHeaders := make([]DeadLetterHeader, 0, len(headers))
+	for _, header := range headers {
+		deadLetterHeaders = append(deadLetterHeaders, DeadLetterHeader{Key: header.Key, Value: string(header.Value)})
+	}
+
+	out, err := json.Marshal(deadLetterHeaders)
+	if err != nil {
+		return err
+	}
+
+	d.Headers = out
+	return nil
+}
+
+// KafkaHeaders returns the stored headers of the message, ready to be sent to Kafka again.
+func (d *DeadLetterMessage) KafkaHeaders() ([]kafka.Header, error) {
+	if len(d.Headers) == 0 {
+		return []kafka.Header{}, nil
+	}
+
+	var deadLetterHeaders []DeadLetterHeader
+	err := json.Unmarshal(d.Headers, &deadLetterHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make([]kafka.Header, 0, len(deadLetterHeaders))
+	for _, header := range deadLetterHeaders {
+		headers = append(headers, kafka.Header{Key: header.Key, Value: []byte(header.Value)})
+	}
+
+	return headers, nil
+}
+
+func (d *DeadLetterMessage) ToResponse() *DeadLetterMessageResponse {
+	return &DeadLetterMessageResponse{
+		Id:         strconv.FormatInt(d.ID, 10),
+		Topic:      d.Topic,
+		EventType:  d.EventType,
+		Payload:    d.Payload,
+		Error:      d.Error,
+		Status:     d.Status,
+		CreatedAt:  util.DateTimeToRFC3339(d.CreatedAt),
+		RequeuedAt: util.DateTimePointerToRFC3339(d.RequeuedAt),
+	}
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/kafka"
+)
+
+// TestDeadLetterMessageKafkaHeaders tests that the Kafka headers are stored and read back unchanged.
+func TestDeadLetterMessageKafkaHeaders(t *testing.T) {
+	headers := []kafka.Header{
+		{Key: "event_type", Value: []byte("Source.create")},
+		{Key: "x-rh-sources-org-id", Value: []byte("12345")},
+	}
+
+	deadLetter := DeadLetterMessage{}
+	err := deadLetter.SetKafkaHeaders(headers)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	got, err := deadLetter.KafkaHeaders()
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if len(got) != len(headers) {
+		t.Fatalf(`want "%d" headers, got "%d"`, len(headers), len(got))
+	}
+
+	for i := range headers {
+		if got[i].Key != headers[i].Key || !bytes.Equal(got[i].Value, headers[i].Value) {
+			t.Errorf(`want header "%s: %s", got "%s: %s"`, headers[i].Key, headers[i].Value, got[i].Key, got[i].Value)
+		}
+	}
+}
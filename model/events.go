@@ -45,3 +45,26 @@ func UpdateMessage(eventObject EventModelDao, resource util.Resource, attributes
 
 	return data, nil
 }
+
+// BulkDestroyEvent is the payload of the aggregated "<Resource>.bulk_destroy" events, which get raised instead of one
+// "<Resource>.destroy" event per removed resource when the bulk events are aggregated. The IDs are the ones of the
+// removed resources, formatted as strings like in the rest of the events:
+//
+//	{"ids": ["1", "2", "3"]}
+type BulkDestroyEvent struct {
+	Ids []string `json:"ids"`
+}
+
+// NewBulkDestroyEvent returns the aggregated event for the given removed resource IDs.
+func NewBulkDestroyEvent(ids []int64) *BulkDestroyEvent {
+	event := &BulkDestroyEvent{Ids: make([]string, 0, len(ids))}
+	for _, id := range ids {
+		event.Ids = append(event.Ids, strconv.FormatInt(id, 10))
+	}
+
+	return event
+}
+
+func (b *BulkDestroyEvent) ToEvent() interface{} {
+	return b
+}
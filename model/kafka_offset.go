@@ -0,0 +1,48 @@
+package model
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// KafkaOffset records the next offset a consumer group has to process from a topic's partition, so that the
+// consumers can be told where they left off, and so that the messages can be replayed from a given point in time.
+type KafkaOffset struct {
+	ID            int64  `gorm:"primaryKey"`
+	ConsumerGroup string `gorm:"uniqueIndex:index_kafka_offsets_on_consumer_group_and_topic_and_partition,priority:1"`
+	Topic         string `gorm:"uniqueIndex:index_kafka_offsets_on_consumer_group_and_topic_and_partition,priority:2"`
+	Partition     int    `gorm:"uniqueIndex:index_kafka_offsets_on_consumer_group_and_topic_and_partition,priority:3"`
+	Offset        int64
+	UpdatedAt     time.Time
+}
+
+// KafkaOffsetResponse is the representation of the offsets which is returned to the clients.
+type KafkaOffsetResponse struct {
+	Id            string `json:"id"`
+	ConsumerGroup string `json:"consumer_group"`
+	Topic         string `json:"topic"`
+	Partition     int    `json:"partition"`
+	Offset        int64  `json:"offset"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// KafkaOffsetResetRequest represents a request to move a consumer group's offsets of a topic back, or forward, to the
+// first messages produced at or after the given time.
+type KafkaOffsetResetRequest struct {
+	ConsumerGroup string    `json:"consumer_group"`
+	Topic         string    `json:"topic"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+func (k *KafkaOffset) ToResponse() *KafkaOffsetResponse {
+	return &KafkaOffsetResponse{
+		Id:            strconv.FormatInt(k.ID, 10),
+		ConsumerGroup: k.ConsumerGroup,
+		Topic:         k.Topic,
+		Partition:     k.Partition,
+		Offset:        k.Offset,
+		UpdatedAt:     util.DateTimeToRFC3339(k.UpdatedAt),
+	}
+}
@@ -0,0 +1,23 @@
+package model
+
+// Statuses the readiness probe reports for the service and for each of its dependencies.
+const (
+	ReadinessOk          = "ok"
+	ReadinessDegraded    = "degraded"
+	ReadinessUnavailable = "unavailable"
+)
+
+// ReadinessResponse is the body the readiness probe responds with.
+type ReadinessResponse struct {
+	Status       string                `json:"status"`
+	Dependencies []ReadinessDependency `json:"dependencies"`
+}
+
+// ReadinessDependency is the status of one of the service's dependencies.
+type ReadinessDependency struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// LagBytes is the amount of WAL the dependency still has to process, for the ones that replicate data.
+	LagBytes *int64 `json:"lag_bytes,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
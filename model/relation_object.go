@@ -69,7 +69,7 @@ func (relationObject *RelationObject) SelectStatementFor(query *gorm.DB, model i
 }
 
 func (relationObject *RelationObject) HasManyThrough(query *gorm.DB, model interface{}, throughTable string) *gorm.DB {
-	query = query.Debug().Select(relationObject.SelectStatementFor(query, model))
+	query = query.Select(relationObject.SelectStatementFor(query, model))
 	query.Statement.Distinct = true
 
 	subCollectionModel := strcase.ToSnake(reflect.TypeOf(model).Elem().Name())
@@ -0,0 +1,23 @@
+package model
+
+// ResourceLinkage identifies a related resource, following the JSON:API specification.
+type ResourceLinkage struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// ToManyRelationship holds the linkage of a "to many" relationship. The data is always present, even when there
+// aren't any related resources, in which case it is an empty array.
+type ToManyRelationship struct {
+	Data []ResourceLinkage `json:"data"`
+}
+
+// newToManyRelationship builds the linkage of the given type for the given IDs.
+func newToManyRelationship(resourceType string, ids []string) ToManyRelationship {
+	data := make([]ResourceLinkage, 0, len(ids))
+	for _, id := range ids {
+		data = append(data, ResourceLinkage{Type: resourceType, ID: id})
+	}
+
+	return ToManyRelationship{Data: data}
+}
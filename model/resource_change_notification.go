@@ -0,0 +1,14 @@
+package model
+
+import "encoding/json"
+
+// ResourceChangeNotification is the payload of the notifications sent every time an event is raised for a resource,
+// so that the change can be streamed by every replica. The tenant is identified the same way the event's headers do.
+type ResourceChangeNotification struct {
+	OrgId     string          `json:"org_id,omitempty"`
+	Account   string          `json:"account,omitempty"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	// Truncated is set when the event's payload didn't fit in the notification, and therefore was left out.
+	Truncated bool `json:"truncated,omitempty"`
+}
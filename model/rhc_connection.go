@@ -10,8 +10,14 @@ import (
 
 type RhcConnection struct {
 	ID    int64          `gorm:"primaryKey" json:"id"`
-	RhcId string         `json:"rhc_id"`
+	RhcId string         `gorm:"uniqueIndex:index_rhc_connections_on_tenant_id_and_rhc_id,priority:2" json:"rhc_id"`
 	Extra datatypes.JSON `json:"extra,omitempty"`
+	// Tags holds the "key": "value" tags the operators organize the connections with.
+	Tags datatypes.JSON `json:"tags,omitempty"`
+
+	// TenantId is the tenant which registered the connection. Different tenants may register the same "rhc_id",
+	// in which case each of them gets its own connection.
+	TenantId int64 `gorm:"uniqueIndex:index_rhc_connections_on_tenant_id_and_rhc_id,priority:1" json:"-"`
 
 	AvailabilityStatus      string     `json:"availability_status,omitempty"`
 	LastCheckedAt           *time.Time `json:"last_checked_at,omitempty"`
@@ -19,6 +25,8 @@ type RhcConnection struct {
 	AvailabilityStatusError string     `json:"availability_status_error,omitempty"`
 	CreatedAt               time.Time  `json:"created_at"`
 	UpdatedAt               time.Time  `json:"updated_at"`
+	// UpdatedBy is the actor which last created or modified the connection.
+	UpdatedBy string `json:"updated_by,omitempty"`
 
 	Sources []Source `gorm:"many2many:source_rhc_connections"`
 }
@@ -48,14 +56,19 @@ func (r *RhcConnection) ToEvent() interface{} {
 
 func (r *RhcConnection) ToResponse() *RhcConnectionResponse {
 	id := strconv.FormatInt(r.ID, 10)
+	sourceIds := r.SourceIDs()
 
 	return &RhcConnectionResponse{
 		Id:                      &id,
 		RhcId:                   &r.RhcId,
 		Extra:                   r.Extra,
+		Tags:                    r.Tags,
 		AvailabilityStatus:      r.AvailabilityStatus,
 		AvailabilityStatusError: r.AvailabilityStatusError,
-		SourceIds:               r.SourceIDs(),
+		SourceIds:               sourceIds,
+		Relationships: RhcConnectionRelationships{
+			Sources: newToManyRelationship("source", sourceIds),
+		},
 	}
 }
 
@@ -0,0 +1,23 @@
+package model
+
+// RhcConnectionAdminEntry is a connection linked to a source as the administrators see it, which might be a soft
+// deleted connection or link.
+type RhcConnectionAdminEntry struct {
+	RhcConnection
+	// Deleted tells whether either the connection or its link to the source were soft deleted.
+	Deleted bool
+}
+
+// RhcConnectionAdminResponse is the representation of the "RhcConnectionAdminEntry" which is returned to the
+// administrators.
+type RhcConnectionAdminResponse struct {
+	RhcConnectionResponse
+	Deleted bool `json:"deleted"`
+}
+
+func (r *RhcConnectionAdminEntry) ToResponse() *RhcConnectionAdminResponse {
+	return &RhcConnectionAdminResponse{
+		RhcConnectionResponse: *r.RhcConnection.ToResponse(),
+		Deleted:               r.Deleted,
+	}
+}
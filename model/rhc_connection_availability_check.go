@@ -0,0 +1,9 @@
+package model
+
+// RhcConnectionAvailabilityCheck is the request to probe the availability of a newly created rhcConnection.
+type RhcConnectionAvailabilityCheck struct {
+	RhcConnectionId int64  `json:"rhc_connection_id"`
+	RhcId           string `json:"rhc_id"`
+	SourceId        int64  `json:"source_id"`
+	TenantId        int64  `json:"tenant_id"`
+}
@@ -0,0 +1,7 @@
+package model
+
+// RhcConnectionCountSnapshot is the number of connections a tenant had when the snapshot was taken.
+type RhcConnectionCountSnapshot struct {
+	TenantID int64 `json:"tenant_id"`
+	Count    int64 `json:"count"`
+}
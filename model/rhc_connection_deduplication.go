@@ -0,0 +1,15 @@
+package model
+
+// RhcConnectionDeduplication describes how some connections get merged into a canonical one, either because they share
+// a rhc_id or because they were explicitly merged.
+type RhcConnectionDeduplication struct {
+	// Canonical is the connection which survives the deduplication.
+	Canonical RhcConnection `json:"canonical"`
+	// DuplicateIds are the IDs of the connections which get deleted.
+	DuplicateIds []int64 `json:"duplicate_ids"`
+	// RepointedSourceIds are the sources whose link to a duplicate gets moved to the canonical connection.
+	RepointedSourceIds []int64 `json:"repointed_source_ids"`
+	// DroppedSourceIds are the sources whose link to a duplicate gets dropped, since they are already linked to the
+	// canonical connection.
+	DroppedSourceIds []int64 `json:"dropped_source_ids"`
+}
@@ -23,9 +23,17 @@ type RhcConnectionResponse struct {
 	Id                      *string        `json:"id"`
 	RhcId                   *string        `json:"rhc_id"`
 	Extra                   datatypes.JSON `json:"extra,omitempty"`
+	Tags                    datatypes.JSON `json:"tags,omitempty"`
 	AvailabilityStatus      string         `json:"availability_status,omitempty"`
 	LastCheckedAt           time.Time      `json:"last_checked_at,omitempty"`
 	LastAvailableAt         time.Time      `json:"last_available_at,omitempty"`
 	AvailabilityStatusError string         `json:"availability_status_error,omitempty"`
 	SourceIds               []string       `json:"source_ids,omitempty"`
+	// Relationships links the connection to its sources, following the JSON:API specification.
+	Relationships RhcConnectionRelationships `json:"relationships"`
+}
+
+// RhcConnectionRelationships holds the resources a connection is related to.
+type RhcConnectionRelationships struct {
+	Sources ToManyRelationship `json:"sources"`
 }
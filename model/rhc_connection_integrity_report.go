@@ -0,0 +1,41 @@
+package model
+
+// IntegrityReport describes the referential integrity problems of a tenant's connections and of their links to the
+// sources.
+type IntegrityReport struct {
+	// OrphanedRhcConnections are the connections which are not linked to any source.
+	OrphanedRhcConnections IntegrityIssue `json:"orphaned_rhc_connections"`
+	// MissingSourceLinks are the links which point at sources that don't exist.
+	MissingSourceLinks IntegrityLinkIssue `json:"missing_source_links"`
+	// MissingRhcConnectionLinks are the links which point at connections that don't exist.
+	MissingRhcConnectionLinks IntegrityLinkIssue `json:"missing_rhc_connection_links"`
+	// TenantMismatchedLinks are the links whose tenant differs from the one of their source or of their connection.
+	TenantMismatchedLinks IntegrityLinkIssue `json:"tenant_mismatched_links"`
+}
+
+// IntegrityIssue holds how many rows have an integrity problem, along with a sample of their IDs.
+type IntegrityIssue struct {
+	Count     int64   `json:"count"`
+	SampleIds []int64 `json:"sample_ids"`
+}
+
+// IntegrityLinkIssue holds how many links between the sources and the connections have an integrity problem, along
+// with a sample of them.
+type IntegrityLinkIssue struct {
+	Count       int64             `json:"count"`
+	SampleLinks []IntegrityLinkId `json:"sample_links"`
+}
+
+// IntegrityLinkId identifies a link between a source and a connection.
+type IntegrityLinkId struct {
+	SourceId        int64 `json:"source_id"`
+	RhcConnectionId int64 `json:"rhc_connection_id"`
+}
+
+// Healthy returns true when the report found no integrity problems.
+func (r *IntegrityReport) Healthy() bool {
+	return r.OrphanedRhcConnections.Count == 0 &&
+		r.MissingSourceLinks.Count == 0 &&
+		r.MissingRhcConnectionLinks.Count == 0 &&
+		r.TenantMismatchedLinks.Count == 0
+}
@@ -0,0 +1,9 @@
+package model
+
+// RhcConnectionSourceSummary is the number of connections a source is linked to, and how many of them are available.
+type RhcConnectionSourceSummary struct {
+	SourceId        int64  `json:"source_id"`
+	SourceName      string `json:"source_name"`
+	ConnectionCount int    `json:"connection_count"`
+	AvailableCount  int    `json:"available_count"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// RhcConnectionStatusEvent records a change of an rhc connection's availability status, and who made it.
+type RhcConnectionStatusEvent struct {
+	ID              int64 `gorm:"primaryKey"`
+	RhcConnectionId int64
+	PreviousStatus  string
+	NewStatus       string
+	ChangedAt       time.Time
+	ChangedBy       string
+}
+
+// RhcConnectionStatusEventResponse is the representation of the status events which is returned to the clients.
+type RhcConnectionStatusEventResponse struct {
+	Id              string `json:"id"`
+	RhcConnectionId string `json:"rhc_connection_id"`
+	PreviousStatus  string `json:"previous_status"`
+	NewStatus       string `json:"new_status"`
+	ChangedAt       string `json:"changed_at"`
+	ChangedBy       string `json:"changed_by,omitempty"`
+}
+
+func (e *RhcConnectionStatusEvent) ToResponse() *RhcConnectionStatusEventResponse {
+	return &RhcConnectionStatusEventResponse{
+		Id:              strconv.FormatInt(e.ID, 10),
+		RhcConnectionId: strconv.FormatInt(e.RhcConnectionId, 10),
+		PreviousStatus:  e.PreviousStatus,
+		NewStatus:       e.NewStatus,
+		ChangedAt:       util.DateTimeToRFC3339(e.ChangedAt),
+		ChangedBy:       e.ChangedBy,
+	}
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestRhcConnectionResponseRelationships tests that the connection's sources are linked as JSON:API resource linkage
+// objects.
+func TestRhcConnectionResponseRelationships(t *testing.T) {
+	rhcConnection := RhcConnection{ID: 1, Sources: []Source{{ID: 5}, {ID: 12}}}
+
+	got := rhcConnection.ToResponse().Relationships.Sources.Data
+	want := []ResourceLinkage{{Type: "source", ID: "5"}, {Type: "source", ID: "12"}}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf(`want "%+v", got "%+v"`, want, got)
+	}
+}
+
+// TestRhcConnectionResponseRelationshipsEmpty tests that a connection without sources gets an empty data array
+// instead of a null one.
+func TestRhcConnectionResponseRelationshipsEmpty(t *testing.T) {
+	rhcConnection := RhcConnection{ID: 1}
+
+	body, err := json.Marshal(rhcConnection.ToResponse().Relationships)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	want := `{"sources":{"data":[]}}`
+	if string(body) != want {
+		t.Errorf(`want "%s", got "%s"`, want, body)
+	}
+}
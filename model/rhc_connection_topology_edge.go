@@ -0,0 +1,23 @@
+package model
+
+import "strconv"
+
+// RhcConnectionTopologyEdge is a link between a connection and a source, in the compact form the topology graph is
+// built from.
+type RhcConnectionTopologyEdge struct {
+	RhcConnectionId int64
+	SourceId        int64
+}
+
+// RhcConnectionTopologyEdgeResponse is the representation of the topology edges which is returned to the clients.
+type RhcConnectionTopologyEdgeResponse struct {
+	RhcConnectionId string `json:"rhc_connection_id"`
+	SourceId        string `json:"source_id"`
+}
+
+func (e *RhcConnectionTopologyEdge) ToResponse() *RhcConnectionTopologyEdgeResponse {
+	return &RhcConnectionTopologyEdgeResponse{
+		RhcConnectionId: strconv.FormatInt(e.RhcConnectionId, 10),
+		SourceId:        strconv.FormatInt(e.SourceId, 10),
+	}
+}
@@ -0,0 +1,36 @@
+package model
+
+// availabilityStatusSeverity ranks the availability statuses from the best to the worst one. The sources without a
+// status rank worse than the available ones, since their status is unknown.
+var availabilityStatusSeverity = map[string]int{
+	Available:          0,
+	"":                 1,
+	InProgress:         2,
+	PartiallyAvailable: 3,
+	Unavailable:        4,
+}
+
+// RhcConnectionWithAvailability is a connection along with the aggregated availability status of the sources it is
+// linked to.
+type RhcConnectionWithAvailability struct {
+	RhcConnection
+	// SourcesAvailabilityStatus is the worst availability status among the linked sources.
+	SourcesAvailabilityStatus string
+	// SourcesAvailabilityCounts holds how many of the linked sources are in each availability status.
+	SourcesAvailabilityCounts map[string]int64
+}
+
+// AddSourcesAvailability adds the given number of linked sources in the given availability status, and keeps the
+// worst status up to date.
+func (r *RhcConnectionWithAvailability) AddSourcesAvailability(status string, count int64) {
+	if r.SourcesAvailabilityCounts == nil {
+		r.SourcesAvailabilityCounts = make(map[string]int64)
+	}
+
+	// The first status is always the worst one seen so far.
+	if len(r.SourcesAvailabilityCounts) == 0 || availabilityStatusSeverity[status] > availabilityStatusSeverity[r.SourcesAvailabilityStatus] {
+		r.SourcesAvailabilityStatus = status
+	}
+
+	r.SourcesAvailabilityCounts[status] += count
+}
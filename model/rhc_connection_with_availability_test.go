@@ -0,0 +1,38 @@
+package model
+
+import "testing"
+
+// TestAddSourcesAvailability tests that the statuses of the linked sources are counted, and that the worst one is
+// kept.
+func TestAddSourcesAvailability(t *testing.T) {
+	testCases := []struct {
+		statuses  []string
+		wantWorst string
+	}{
+		{statuses: []string{Available}, wantWorst: Available},
+		{statuses: []string{Available, ""}, wantWorst: ""},
+		{statuses: []string{Available, InProgress, Available}, wantWorst: InProgress},
+		{statuses: []string{PartiallyAvailable, Available}, wantWorst: PartiallyAvailable},
+		{statuses: []string{Available, Unavailable, PartiallyAvailable}, wantWorst: Unavailable},
+	}
+
+	for _, tc := range testCases {
+		var rhcConnection RhcConnectionWithAvailability
+		for _, status := range tc.statuses {
+			rhcConnection.AddSourcesAvailability(status, 1)
+		}
+
+		if rhcConnection.SourcesAvailabilityStatus != tc.wantWorst {
+			t.Errorf(`[%v] want worst status "%s", got "%s"`, tc.statuses, tc.wantWorst, rhcConnection.SourcesAvailabilityStatus)
+		}
+
+		var total int64
+		for _, count := range rhcConnection.SourcesAvailabilityCounts {
+			total += count
+		}
+
+		if total != int64(len(tc.statuses)) {
+			t.Errorf(`[%v] want "%d" counted sources, got "%d"`, tc.statuses, len(tc.statuses), total)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package model
+
+// RhcConnectionWithSources is a connection along with the full sources it is linked to, instead of just their IDs.
+type RhcConnectionWithSources struct {
+	RhcConnection
+	Sources []Source
+}
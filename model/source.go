@@ -20,6 +20,13 @@ type Source struct {
 	LastCheckedAt      *time.Time `json:"last_checked_at,omitempty"`
 	LastAvailableAt    *time.Time `json:"last_available_at,omitempty"`
 
+	// ApplicationStatusRollup is the worst availability status of the source's applications. It isn't stored, and
+	// it only gets computed when the source is fetched by its ID.
+	ApplicationStatusRollup string `gorm:"-" json:"-"`
+	// AvailabilityStatusError is the reason of the source's availability status. It isn't stored, and it only gets
+	// carried to the availability status change events.
+	AvailabilityStatusError string `gorm:"-" json:"-"`
+
 	//fields for gorm
 	ID        int64      `gorm:"primarykey" json:"id"`
 	CreatedAt time.Time  `json:"created_at"`
@@ -33,6 +40,11 @@ type Source struct {
 	Imported            *string `json:"imported,omitempty"`
 	SourceRef           *string `json:"source_ref,omitempty"`
 	AppCreationWorkflow string  `gorm:"default:manual_configuration" json:"app_creation_workflow"`
+	ExternalId          *string `gorm:"size:255" json:"external_id,omitempty"`
+
+	// cost metadata used to charge the sources back to the teams which own them.
+	CostCenter *string `gorm:"size:255" json:"cost_center,omitempty"`
+	BudgetCode *string `gorm:"size:255" json:"budget_code,omitempty"`
 
 	SourceType   SourceType
 	SourceTypeID int64 `json:"source_type_id"`
@@ -87,7 +99,12 @@ func (src *Source) ToResponse() *SourceResponse {
 		Imported:            src.Imported,
 		SourceRef:           src.SourceRef,
 		AppCreationWorkflow: &src.AppCreationWorkflow,
+		ExternalId:          src.ExternalId,
+		CostCenter:          src.CostCenter,
+		BudgetCode:          src.BudgetCode,
 		SourceTypeId:        stid,
+
+		ApplicationStatusRollup: util.StringValueOrNil(src.ApplicationStatusRollup),
 	}
 }
 
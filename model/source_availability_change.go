@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// SourceAvailabilityChange records a change of a source's availability status, and when it happened.
+type SourceAvailabilityChange struct {
+	ID                 int64 `gorm:"primaryKey"`
+	SourceId           int64
+	TenantId           int64
+	AvailabilityStatus string
+	ChangedAt          time.Time
+}
+
+// SourceSLAReport holds how long a source was available during a period of time. Only the time during which the
+// source's status was known is accounted for.
+type SourceSLAReport struct {
+	SourceId         int64     `json:"source_id,string"`
+	From             time.Time `json:"from"`
+	To               time.Time `json:"to"`
+	UpTimeSec        int64     `json:"up_time_sec"`
+	DownTimeSec      int64     `json:"down_time_sec"`
+	TotalSec         int64     `json:"total_sec"`
+	PercentAvailable float64   `json:"percent_available"`
+}
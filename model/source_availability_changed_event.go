@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// SourceAvailabilityChangedEvent is the body of the event raised when the availability status of a source changes.
+type SourceAvailabilityChangedEvent struct {
+	SourceId          int64      `json:"source_id"`
+	TenantId          int64      `json:"tenant_id"`
+	PreviousStatus    string     `json:"previous_status"`
+	NewStatus         string     `json:"new_status"`
+	CheckedAt         *time.Time `json:"checked_at"`
+	AvailabilityError string     `json:"availability_error"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// SourceAvailabilityNotification is the payload of the notifications sent by the database every time a source's
+// availability status changes.
+type SourceAvailabilityNotification struct {
+	SourceId int64  `json:"source_id"`
+	Status   string `json:"status"`
+	TenantId int64  `json:"tenant_id"`
+}
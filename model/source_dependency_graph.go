@@ -0,0 +1,37 @@
+package model
+
+// SourceDependencyGraph describes everything that depends on a source, so that the impact of deleting it can be
+// assessed beforehand.
+type SourceDependencyGraph struct {
+	ID             int64                           `json:"id,string"`
+	Name           string                          `json:"name"`
+	Applications   []SourceDependencyApplication   `json:"applications"`
+	Endpoints      []SourceDependencyEndpoint      `json:"endpoints"`
+	RhcConnections []SourceDependencyRhcConnection `json:"rhc_connections"`
+}
+
+// SourceDependencyApplication is an application which depends on the source.
+type SourceDependencyApplication struct {
+	ID                  int64  `json:"id,string"`
+	ApplicationTypeID   int64  `json:"application_type_id,string"`
+	ApplicationTypeName string `json:"application_type_name"`
+}
+
+// SourceDependencyEndpoint is an endpoint which depends on the source.
+type SourceDependencyEndpoint struct {
+	ID   int64  `json:"id,string"`
+	Host string `json:"host"`
+}
+
+// SourceDependencyRhcConnection is a connection linked to the source, along with the other sources which share it.
+type SourceDependencyRhcConnection struct {
+	ID             int64                    `json:"id,string"`
+	RhcId          string                   `json:"rhc_id"`
+	SiblingSources []SourceDependencySource `json:"sibling_sources"`
+}
+
+// SourceDependencySource is a source which shares a connection with the source.
+type SourceDependencySource struct {
+	ID   int64  `json:"id,string"`
+	Name string `json:"name"`
+}
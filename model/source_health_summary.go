@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// SourceHealthSummary is a snapshot of the health of all the tenant's sources.
+type SourceHealthSummary struct {
+	// Total is the number of sources the tenant has.
+	Total int64 `json:"total"`
+	// StatusCounts holds the number of sources on each availability status. The sources without a status are counted
+	// under an empty key.
+	StatusCounts map[string]int64 `json:"status_counts"`
+	// LatestCheck is the last time any of the sources' availability was checked.
+	LatestCheck *time.Time `json:"latest_check"`
+	// OldestChecked are the sources whose availability was checked the longest ago, starting with the never checked
+	// ones.
+	OldestChecked []SourceHealthCheck `json:"oldest_checked"`
+}
+
+// SourceHealthCheck is the last availability check of a source.
+type SourceHealthCheck struct {
+	ID                 int64      `json:"id,string"`
+	Name               string     `json:"name"`
+	AvailabilityStatus string     `json:"availability_status"`
+	LastCheckedAt      *time.Time `json:"last_checked_at"`
+}
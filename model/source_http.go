@@ -19,6 +19,9 @@ type SourceCreateRequest struct {
 	SourceRef           *string `json:"source_ref,omitempty"`
 	AppCreationWorkflow string  `json:"app_creation_workflow"`
 	AvailabilityStatus  string  `json:"availability_status"`
+	ExternalId          *string `json:"external_id,omitempty"`
+	CostCenter          *string `json:"cost_center,omitempty"`
+	BudgetCode          *string `json:"budget_code,omitempty"`
 
 	SourceTypeID    *int64      `json:"-"`
 	SourceTypeIDRaw interface{} `json:"source_type_id"`
@@ -32,6 +35,9 @@ type SourceEditRequest struct {
 	Imported           *string `json:"imported,omitempty"`
 	SourceRef          *string `json:"source_ref,omitempty"`
 	AvailabilityStatus *string `json:"availability_status"`
+	ExternalId         *string `json:"external_id,omitempty"`
+	CostCenter         *string `json:"cost_center,omitempty"`
+	BudgetCode         *string `json:"budget_code,omitempty"`
 
 	// TODO: remove these once satellite goes away.
 	LastCheckedAt   *string `json:"last_checked_at"`
@@ -62,8 +68,13 @@ type SourceResponse struct {
 	Imported            *string `json:"imported,omitempty"`
 	SourceRef           *string `json:"source_ref,omitempty"`
 	AppCreationWorkflow *string `json:"app_creation_workflow"`
+	ExternalId          *string `json:"external_id,omitempty"`
+	CostCenter          *string `json:"cost_center,omitempty"`
+	BudgetCode          *string `json:"budget_code,omitempty"`
 
 	SourceTypeId string `json:"source_type_id"`
+
+	ApplicationStatusRollup *string `json:"application_status_rollup,omitempty"`
 }
 
 // SourceInternalResponse represents the structure we will return
@@ -90,6 +101,15 @@ func (src *Source) UpdateFromRequest(update *SourceEditRequest) {
 	if update.AvailabilityStatus != nil {
 		src.AvailabilityStatus = *update.AvailabilityStatus
 	}
+	if update.ExternalId != nil {
+		src.ExternalId = update.ExternalId
+	}
+	if update.CostCenter != nil {
+		src.CostCenter = update.CostCenter
+	}
+	if update.BudgetCode != nil {
+		src.BudgetCode = update.BudgetCode
+	}
 
 	if update.LastAvailableAt != nil {
 		t, _ := time.Parse(util.RecordDateTimeFormat, *update.LastAvailableAt)
@@ -102,6 +122,26 @@ func (src *Source) UpdateFromRequest(update *SourceEditRequest) {
 	}
 }
 
+// ClearFields sets the given nullable fields of the source to nil.
+func (src *Source) ClearFields(fields []string) {
+	for _, field := range fields {
+		switch field {
+		case "version":
+			src.Version = nil
+		case "imported":
+			src.Imported = nil
+		case "source_ref":
+			src.SourceRef = nil
+		case "external_id":
+			src.ExternalId = nil
+		case "cost_center":
+			src.CostCenter = nil
+		case "budget_code":
+			src.BudgetCode = nil
+		}
+	}
+}
+
 func (src *Source) UpdateFromRequestPaused(update *SourcePausedEditRequest) error {
 	availabilityStatus := update.AvailabilityStatus
 	lastAvailableAt := update.LastAvailableAt
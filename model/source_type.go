@@ -25,6 +25,7 @@ type SourceType struct {
 	ProductName  string            `json:"product_name"`
 	Vendor       string            `json:"vendor"`
 	IconUrl      string            `json:"icon_url"`
+	DisplayName  string            `json:"display_name"`
 	Schema       datatypes.JSON    `json:"schema"`
 	SchemaParsed *sourceTypeScheme `gorm:"-"`
 
@@ -45,6 +46,26 @@ func (st *SourceType) ToResponse() *SourceTypeResponse {
 		Vendor:      st.Vendor,
 		Schema:      st.Schema,
 		IconUrl:     st.IconUrl,
+		DisplayName: st.DisplayName,
+	}
+}
+
+// ToCompatibleTypeResponse returns the source type's representation in a compatibility matrix.
+func (st *SourceType) ToCompatibleTypeResponse() CompatibleTypeResponse {
+	return CompatibleTypeResponse{
+		Id:          strconv.FormatInt(st.Id, 10),
+		Name:        st.Name,
+		DisplayName: st.DisplayName,
+	}
+}
+
+func (st *SourceType) UpdateFromRequest(update *SourceTypeEditRequest) {
+	if update.IconUrl != nil {
+		st.IconUrl = *update.IconUrl
+	}
+
+	if update.DisplayName != nil {
+		st.DisplayName = *update.DisplayName
 	}
 }
 
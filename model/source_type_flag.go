@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// SourceTypeFlag records whether a source type supports a capability, such as "cost_management", so that the clients
+// can enable or disable the flows which depend on it.
+type SourceTypeFlag struct {
+	ID           int64 `gorm:"primaryKey"`
+	SourceTypeId int64 `gorm:"uniqueIndex:index_source_type_flags_on_source_type_id_and_name,priority:1"`
+	SourceType   SourceType
+	Name         string `gorm:"uniqueIndex:index_source_type_flags_on_source_type_id_and_name,priority:2"`
+	Enabled      bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// SourceTypeCapabilities is the representation of the source type flags which is returned to the clients, and which
+// they send to set them: a flat object of capability names and whether they are supported, as in
+// {"cost_management": true, "topology": false}.
+type SourceTypeCapabilities map[string]bool
+
+// ToSourceTypeCapabilities returns the given flags as the source type's capabilities.
+func ToSourceTypeCapabilities(flags []SourceTypeFlag) SourceTypeCapabilities {
+	capabilities := make(SourceTypeCapabilities, len(flags))
+	for _, flag := range flags {
+		capabilities[flag.Name] = flag.Enabled
+	}
+
+	return capabilities
+}
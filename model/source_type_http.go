@@ -16,4 +16,25 @@ type SourceTypeResponse struct {
 	Vendor      string         `json:"vendor"`
 	Schema      datatypes.JSON `json:"schema"`
 	IconUrl     string         `json:"icon_url"`
+	DisplayName string         `json:"display_name"`
+}
+
+// SourceTypeWithIncludesResponse is the single source type response, which includes the application types that
+// support the source type.
+type SourceTypeWithIncludesResponse struct {
+	*SourceTypeResponse
+	Includes []CompatibleTypeResponse `json:"includes"`
+}
+
+// CompatibleTypeResponse represents a source type or an application type in a compatibility matrix.
+type CompatibleTypeResponse struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// SourceTypeEditRequest is a struct representing a request to update the presentation fields of a source type.
+type SourceTypeEditRequest struct {
+	IconUrl     *string `json:"icon_url"`
+	DisplayName *string `json:"display_name"`
 }
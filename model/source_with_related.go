@@ -0,0 +1,56 @@
+package model
+
+// SourceWithRelated is a source along with its endpoints, its applications and its rhc connections, so that they can
+// all be fetched in a single request.
+type SourceWithRelated struct {
+	Source
+	Endpoints      []Endpoint
+	Applications   []ApplicationWithType
+	RhcConnections []RhcConnection
+}
+
+// ApplicationWithType is an application which has its application type loaded.
+type ApplicationWithType struct {
+	Application
+}
+
+// SourceWithRelatedResponse is the response for a source along with its related resources.
+type SourceWithRelatedResponse struct {
+	*SourceResponse
+	Endpoints      []*EndpointResponse           `json:"endpoints"`
+	Applications   []ApplicationWithTypeResponse `json:"applications"`
+	RhcConnections []*RhcConnectionResponse      `json:"rhc_connections"`
+}
+
+// ApplicationWithTypeResponse is the response for an application along with its application type.
+type ApplicationWithTypeResponse struct {
+	*ApplicationResponse
+	ApplicationType *ApplicationTypeResponse `json:"application_type"`
+}
+
+func (s *SourceWithRelated) ToResponse() *SourceWithRelatedResponse {
+	endpoints := make([]*EndpointResponse, len(s.Endpoints))
+	for i := range s.Endpoints {
+		endpoints[i] = s.Endpoints[i].ToResponse()
+	}
+
+	applications := make([]ApplicationWithTypeResponse, len(s.Applications))
+	for i := range s.Applications {
+		applications[i] = ApplicationWithTypeResponse{
+			ApplicationResponse: s.Applications[i].ToResponse(),
+			ApplicationType:     s.Applications[i].ApplicationType.ToResponse(),
+		}
+	}
+
+	rhcConnections := make([]*RhcConnectionResponse, len(s.RhcConnections))
+	for i := range s.RhcConnections {
+		rhcConnections[i] = s.RhcConnections[i].ToResponse()
+	}
+
+	return &SourceWithRelatedResponse{
+		SourceResponse: s.Source.ToResponse(),
+		Endpoints:      endpoints,
+		Applications:   applications,
+		RhcConnections: rhcConnections,
+	}
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// TenantQuota holds the limits that apply to a tenant.
+type TenantQuota struct {
+	TenantId        int64 `gorm:"primaryKey"`
+	MaxSources      int
+	MaxApplications int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
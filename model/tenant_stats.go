@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// TenantStats holds the number of resources of each type a tenant has.
+type TenantStats struct {
+	TenantId        int64     `json:"tenant_id,string"`
+	Sources         int64     `json:"sources"`
+	Applications    int64     `json:"applications"`
+	Endpoints       int64     `json:"endpoints"`
+	Authentications int64     `json:"authentications"`
+	RhcConnections  int64     `json:"rhc_connections"`
+	GeneratedAt     time.Time `json:"generated_at"`
+}
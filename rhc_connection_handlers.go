@@ -1,11 +1,14 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/middleware"
 	"github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/RedHatInsights/sources-api-go/util"
@@ -21,7 +24,9 @@ func getDefaultRhcConnectionDao(c echo.Context) (dao.RhcConnectionDao, error) {
 		return nil, err
 	}
 
-	return dao.GetRhcConnectionDao(&tenantId), nil
+	rhcConnectionDao := dao.GetRhcConnectionDao(c.Request().Context(), &tenantId)
+
+	return rhcConnectionDao, nil
 }
 
 func RhcConnectionList(c echo.Context) error {
@@ -40,11 +45,16 @@ func RhcConnectionList(c echo.Context) error {
 		return err
 	}
 
-	rhcConnections, count, err := rhcConnectionDao.List(limit, offset, filters)
+	rhcConnections, count, lastModified, err := rhcConnectionDao.ListWithLastModified(limit, offset, filters)
 	if err != nil {
 		return err
 	}
 
+	// The clients which poll the connections get a "304 Not Modified" when the listed connections didn't change.
+	if setListValidators(c, count, lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	out := make([]interface{}, len(rhcConnections))
 	for i := 0; i < len(rhcConnections); i++ {
 		out[i] = rhcConnections[i].ToResponse()
@@ -53,6 +63,107 @@ func RhcConnectionList(c echo.Context) error {
 	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
 }
 
+// RhcConnectionSummaryBySource returns, for each of the tenant's sources, how many connections it is linked to and
+// how many of them are available.
+func RhcConnectionSummaryBySource(c echo.Context) error {
+	tenantId, err := getTenantFromEchoContext(c)
+	if err != nil {
+		return err
+	}
+
+	rhcConnectionDao, err := getRhcConnectionDao(c)
+	if err != nil {
+		return err
+	}
+
+	summary, err := rhcConnectionDao.SummaryBySource(tenantId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, summary)
+}
+
+// RhcConnectionTopology lists the links between the tenant's connections and sources, so that the clients can build
+// the topology graph from them.
+func RhcConnectionTopology(c echo.Context) error {
+	rhcConnectionDao, err := getRhcConnectionDao(c)
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := getLimitAndOffset(c)
+	if err != nil {
+		return err
+	}
+
+	edges, count, err := rhcConnectionDao.TopologyEdges(limit, offset)
+	if err != nil {
+		return err
+	}
+
+	out := make([]interface{}, len(edges))
+	for i := range edges {
+		out[i] = edges[i].ToResponse()
+	}
+
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+}
+
+// rhcConnectionStreamBufferSize is the number of change events buffered for each streaming client. Once the buffer is
+// full, the events get dropped and the client is told to resync.
+const rhcConnectionStreamBufferSize = 64
+
+// rhcConnectionStreamHeartbeat is how often a comment is sent to the streaming clients, so that the proxies in between
+// don't close the idle streams.
+var rhcConnectionStreamHeartbeat = 15 * time.Second
+
+// RhcConnectionStream streams the changes of the tenant's connections as server sent events, until the client
+// disconnects.
+func RhcConnectionStream(c echo.Context) error {
+	orgId, account, err := tenantIdentity(c)
+	if err != nil {
+		return err
+	}
+
+	subscription := service.Changes.Subscribe(orgId, account, "RhcConnection", rhcConnectionStreamBufferSize)
+	defer service.Changes.Unsubscribe(subscription)
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	c.Response().Header().Set("Connection", "keep-alive")
+	c.Response().WriteHeader(http.StatusOK)
+	c.Response().Flush()
+
+	heartbeat := time.NewTicker(rhcConnectionStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(c.Response(), ": heartbeat\n\n")
+		case <-subscription.Resync:
+			_, err = fmt.Fprint(c.Response(), "event: resync\ndata: {}\n\n")
+		case event := <-subscription.Events:
+			var data []byte
+			data, err = json.Marshal(event)
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", event.EventType, data)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		c.Response().Flush()
+	}
+}
+
 func RhcConnectionGetById(c echo.Context) error {
 	paramId := c.Param("id")
 
@@ -86,9 +197,10 @@ func RhcConnectionCreate(c echo.Context) error {
 	}
 
 	rhcConnection := &model.RhcConnection{
-		RhcId:   input.RhcId,
-		Extra:   input.Extra,
-		Sources: []model.Source{{ID: input.SourceId}},
+		RhcId:     input.RhcId,
+		Extra:     input.Extra,
+		UpdatedBy: getActorFromEchoContext(c),
+		Sources:   []model.Source{{ID: input.SourceId}},
 	}
 
 	rhcConnectionDao, err := getRhcConnectionDao(c)
@@ -130,11 +242,17 @@ func RhcConnectionEdit(c echo.Context) error {
 	}
 
 	dbRhcConnection.UpdateFromRequest(input)
-	err = rhcConnectionDao.Update(dbRhcConnection)
+	dbRhcConnection.UpdatedBy = getActorFromEchoContext(c)
+	rowsAffected, err := rhcConnectionDao.Update(dbRhcConnection)
 	if err != nil {
 		return err
 	}
 
+	// The connection might have been deleted in between.
+	if rowsAffected == 0 {
+		return util.NewErrNotFound("rhcConnection")
+	}
+
 	setEventStreamResource(c, dbRhcConnection)
 
 	return c.JSON(http.StatusOK, dbRhcConnection.ToResponse())
@@ -153,7 +271,10 @@ func RhcConnectionDelete(c echo.Context) error {
 		return err
 	}
 
-	rhcConnection, err := rhcConnectionDao.Delete(&rhcConnectionId)
+	// The admins may delete the connections which are still in use without explicitly forcing it.
+	force := c.QueryParam("force") == "true" || middleware.IsPskOrOrgAdmin(c)
+
+	rhcConnection, err := rhcConnectionDao.Delete(&rhcConnectionId, force)
 	if err != nil {
 		return err
 	}
@@ -163,6 +284,43 @@ func RhcConnectionDelete(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// SourceRhcConnectionUnlink detaches the given connection from the given source. The connection is only deleted if
+// no other sources are linked to it.
+func SourceRhcConnectionUnlink(c echo.Context) error {
+	sourceId, err := strconv.ParseInt(c.Param("source_id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	rhcConnectionId, err := strconv.ParseInt(c.Param("rhc_connection_id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	tenantId, err := getTenantFromEchoContext(c)
+	if err != nil {
+		return err
+	}
+
+	rhcConnectionDao, err := getRhcConnectionDao(c)
+	if err != nil {
+		return err
+	}
+
+	rhcConnection, deleted, err := rhcConnectionDao.UnlinkFromSource(rhcConnectionId, sourceId, tenantId)
+	if err != nil {
+		return err
+	}
+
+	// The connection is destroyed along with its last link. Otherwise it is just updated, since it lost a source.
+	setEventStreamResource(c, rhcConnection)
+	if !deleted {
+		c.Set("event_override", "RhcConnection.update")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
 // RhcConnectionSourcesList returns all the sources related to a given connection.
 func RhcConnectionSourcesList(c echo.Context) error {
 	paramId := c.Param("id")
@@ -211,3 +369,64 @@ func RhcConnectionSourcesList(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
 }
+
+// RhcConnectionStatusHistory lists the latest changes of the connection's availability status.
+func RhcConnectionStatusHistory(c echo.Context) error {
+	rhcConnectionId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	limit, _, err := getLimitAndOffset(c)
+	if err != nil {
+		return err
+	}
+
+	tenantId, err := getTenantFromEchoContext(c)
+	if err != nil {
+		return err
+	}
+
+	rhcConnectionDao, err := getRhcConnectionDao(c)
+	if err != nil {
+		return err
+	}
+
+	events, err := rhcConnectionDao.ListStatusHistory(rhcConnectionId, tenantId, limit)
+	if err != nil {
+		return err
+	}
+
+	out := make([]interface{}, len(events))
+	for i := range events {
+		out[i] = events[i].ToResponse()
+	}
+
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), len(events), limit, 0))
+}
+
+// AdminSourceRhcConnectionList returns the connections linked to the given source regardless of their tenant. When
+// "include_deleted" is "true", the soft deleted connections and links are returned too, marked as deleted.
+func AdminSourceRhcConnectionList(c echo.Context) error {
+	sourceId, err := strconv.ParseInt(c.Param("source_id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	rhcConnectionDao, err := getRhcConnectionDao(c)
+	if err != nil {
+		return err
+	}
+
+	entries, err := rhcConnectionDao.AdminListForSource(&sourceId, c.QueryParam("include_deleted") == "true")
+	if err != nil {
+		return err
+	}
+
+	out := make([]model.RhcConnectionAdminResponse, len(entries))
+	for i := range entries {
+		out[i] = *entries[i].ToResponse()
+	}
+
+	return c.JSON(http.StatusOK, out)
+}
@@ -2,18 +2,28 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/parser"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
 	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm/clause"
 )
 
 func TestRhcConnectionList(t *testing.T) {
@@ -127,6 +137,17 @@ func TestRhcConnectionGetById(t *testing.T) {
 	if *outRhcConnectionResponse.RhcId != fixtures.TestRhcConnectionData[0].RhcId {
 		t.Error("ghosts infected the return")
 	}
+
+	linkage := outRhcConnectionResponse.Relationships.Sources.Data
+	if linkage == nil || len(linkage) != len(outRhcConnectionResponse.SourceIds) {
+		t.Errorf(`want a linkage object per source "%v", got "%v"`, outRhcConnectionResponse.SourceIds, linkage)
+	}
+
+	for i, resource := range linkage {
+		if resource.Type != "source" || resource.ID != outRhcConnectionResponse.SourceIds[i] {
+			t.Errorf(`want the linkage of source "%s", got "%+v"`, outRhcConnectionResponse.SourceIds[i], resource)
+		}
+	}
 }
 
 func TestRhcConnectionGetByIdMissingIdParam(t *testing.T) {
@@ -502,6 +523,152 @@ func TestRhcConnectionDeleteNotFound(t *testing.T) {
 	templates.NotFoundTest(t, rec)
 }
 
+func TestSourceRhcConnectionUnlink(t *testing.T) {
+	// The connection is also linked to another source, so it doesn't get deleted along with the link.
+	link := fixtures.TestSourceRhcConnectionData[2]
+	sourceId := strconv.FormatInt(link.SourceId, 10)
+	rhcConnectionId := strconv.FormatInt(link.RhcConnectionId, 10)
+
+	c, rec := request.CreateTestContext(
+		http.MethodDelete,
+		"/api/sources/v3.1/sources/"+sourceId+"/rhc_connections/"+rhcConnectionId,
+		nil,
+		map[string]interface{}{
+			"tenantID": link.TenantId,
+		},
+	)
+
+	c.SetParamNames("source_id", "rhc_connection_id")
+	c.SetParamValues(sourceId, rhcConnectionId)
+
+	err := SourceRhcConnectionUnlink(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Want status code %d. Got %d. Body: %s", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+
+	// The connection only lost a source, so an update event is raised for it.
+	if eventType := c.Get("event_override"); eventType != "RhcConnection.update" {
+		t.Errorf(`want the "RhcConnection.update" event, got "%v"`, eventType)
+	}
+
+	if rhcConnection, ok := c.Get("resource").(*model.RhcConnection); !ok || rhcConnection.ID != link.RhcConnectionId {
+		t.Errorf(`want the event raised for connection "%d", got "%v"`, link.RhcConnectionId, c.Get("resource"))
+	}
+
+	// Restore the link so that the rest of the tests find the fixtures untouched.
+	if parser.RunningIntegrationTests {
+		err := dao.DB.Create(&link).Error
+		if err != nil {
+			t.Errorf(`could not restore the link: %s`, err)
+		}
+	}
+}
+
+// TestSourceRhcConnectionUnlinkDeletesOrphan tests that a destroy event is raised when the unlinked connection gets
+// deleted because it isn't linked to any other source.
+func TestSourceRhcConnectionUnlinkDeletesOrphan(t *testing.T) {
+	// The connection is only linked to this source.
+	link := fixtures.TestSourceRhcConnectionData[3]
+	sourceId := strconv.FormatInt(link.SourceId, 10)
+	rhcConnectionId := strconv.FormatInt(link.RhcConnectionId, 10)
+
+	c, rec := request.CreateTestContext(
+		http.MethodDelete,
+		"/api/sources/v3.1/sources/"+sourceId+"/rhc_connections/"+rhcConnectionId,
+		nil,
+		map[string]interface{}{
+			"tenantID": link.TenantId,
+		},
+	)
+
+	c.SetParamNames("source_id", "rhc_connection_id")
+	c.SetParamValues(sourceId, rhcConnectionId)
+
+	err := SourceRhcConnectionUnlink(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Want status code %d. Got %d. Body: %s", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+
+	if eventType := c.Get("event_type"); eventType != "RhcConnection.destroy" || c.Get("event_override") != nil {
+		t.Errorf(`want the "RhcConnection.destroy" event, got "%v" overridden by "%v"`, eventType, c.Get("event_override"))
+	}
+
+	// Restore the connection and its link so that the rest of the tests find the fixtures untouched.
+	if parser.RunningIntegrationTests {
+		for _, rhcConnection := range fixtures.TestRhcConnectionData {
+			if rhcConnection.ID != link.RhcConnectionId {
+				continue
+			}
+
+			err := dao.DB.Omit(clause.Associations).Create(&rhcConnection).Error
+			if err != nil {
+				t.Errorf(`could not restore the connection: %s`, err)
+			}
+		}
+
+		err := dao.DB.Create(&link).Error
+		if err != nil {
+			t.Errorf(`could not restore the link: %s`, err)
+		}
+	}
+}
+
+func TestSourceRhcConnectionUnlinkInvalidParam(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodDelete,
+		"/api/sources/v3.1/sources/1/rhc_connections/xxx",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id", "rhc_connection_id")
+	c.SetParamValues("1", "xxx")
+
+	badRequestSourceRhcConnectionUnlink := ErrorHandlingContext(SourceRhcConnectionUnlink)
+	err := badRequestSourceRhcConnectionUnlink(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+func TestSourceRhcConnectionUnlinkNotFound(t *testing.T) {
+	// The connection exists, but it isn't linked to the source.
+	sourceId := strconv.FormatInt(fixtures.TestSourceData[0].ID, 10)
+	rhcConnectionId := strconv.FormatInt(fixtures.TestRhcConnectionData[2].ID, 10)
+
+	c, rec := request.CreateTestContext(
+		http.MethodDelete,
+		"/api/sources/v3.1/sources/"+sourceId+"/rhc_connections/"+rhcConnectionId,
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id", "rhc_connection_id")
+	c.SetParamValues(sourceId, rhcConnectionId)
+
+	notFoundSourceRhcConnectionUnlink := ErrorHandlingContext(SourceRhcConnectionUnlink)
+	err := notFoundSourceRhcConnectionUnlink(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
 func TestRhcConnectionGetRelatedSources(t *testing.T) {
 	rhcConnectionId := "2"
 
@@ -647,3 +814,527 @@ func TestRhcConnectionGetRelatedSourcesNotFound(t *testing.T) {
 
 	templates.NotFoundTest(t, rec)
 }
+
+// TestRhcConnectionStatusHistory tests that the status history of a connection is returned as a collection.
+func TestRhcConnectionStatusHistory(t *testing.T) {
+	id := strconv.FormatInt(fixtures.TestRhcConnectionData[0].ID, 10)
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/rhc_connections/"+id+"/status_history",
+		nil,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"filters":  []util.Filter{},
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+
+	err := RhcConnectionStatusHistory(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var out util.Collection
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("Failed unmarshalling output: %s", err)
+	}
+
+	if out.Data == nil {
+		t.Errorf(`want a "data" array, got none`)
+	}
+}
+
+// TestRhcConnectionStatusHistoryNotFound tests that a "not found" error is returned for the connections which don't
+// exist.
+func TestRhcConnectionStatusHistoryNotFound(t *testing.T) {
+	rhcConnectionId := "789678567"
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/rhc_connections/"+rhcConnectionId+"/status_history",
+		nil,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"filters":  []util.Filter{},
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues(rhcConnectionId)
+
+	notFoundRhcConnectionStatusHistory := ErrorHandlingContext(RhcConnectionStatusHistory)
+	err := notFoundRhcConnectionStatusHistory(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+func TestRhcConnectionStream(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/rhc_connections/stream",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+			h.ORGID:    fixtures.TestTenantData[0].OrgID,
+		},
+	)
+
+	// Simulate a client that has already disconnected so that the handler returns.
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	cancel()
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	err := RhcConnectionStream(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Wrong code, got %v, expected %v", rec.Code, http.StatusOK)
+	}
+
+	if contentType := rec.Header().Get(echo.HeaderContentType); contentType != "text/event-stream" {
+		t.Errorf(`want "text/event-stream" content type, got "%s"`, contentType)
+	}
+}
+
+// TestRhcConnectionStreamEvents tests that the tenant's connection changes are streamed along with the heartbeats, and
+// that the changes of other tenants are not.
+func TestRhcConnectionStreamEvents(t *testing.T) {
+	backupChanges := service.Changes
+	backupHeartbeat := rhcConnectionStreamHeartbeat
+	defer func() {
+		service.Changes = backupChanges
+		rhcConnectionStreamHeartbeat = backupHeartbeat
+	}()
+
+	service.Changes = service.NewChangeBroker(func(ctx context.Context, _ func(payload string)) error {
+		<-ctx.Done()
+		return nil
+	})
+	rhcConnectionStreamHeartbeat = 10 * time.Millisecond
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/rhc_connections/stream",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+			h.ORGID:    "12345",
+		},
+	)
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	done := make(chan error)
+	go func() { done <- RhcConnectionStream(c) }()
+
+	// Give the handler some time to subscribe and to send a few heartbeats.
+	time.Sleep(50 * time.Millisecond)
+	service.Changes.Publish(service.ChangeEvent{OrgId: "12345", ResourceType: "RhcConnection", EventType: "RhcConnection.update", Payload: []byte(`{"id":"1"}`)})
+	service.Changes.Publish(service.ChangeEvent{OrgId: "67890", ResourceType: "RhcConnection", EventType: "RhcConnection.destroy", Payload: []byte(`{"id":"2"}`)})
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	err := <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, ": heartbeat\n\n") {
+		t.Errorf(`want heartbeats sent, got "%s"`, body)
+	}
+
+	if !strings.Contains(body, "event: RhcConnection.update\ndata: {\"event_type\":\"RhcConnection.update\",\"payload\":{\"id\":\"1\"}}\n\n") {
+		t.Errorf(`want the connection's update streamed, got "%s"`, body)
+	}
+
+	if strings.Contains(body, "RhcConnection.destroy") {
+		t.Errorf(`want the other tenant's changes left out, got "%s"`, body)
+	}
+}
+
+// TestAdminSourceRhcConnectionList tests that the administrators get the connections linked to the source, marked as
+// not deleted.
+func TestAdminSourceRhcConnectionList(t *testing.T) {
+	sourceId := fixtures.TestSourceData[0].ID
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/admin/sources/1/rhc_connections?include_deleted=true",
+		nil,
+		map[string]interface{}{},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues(strconv.FormatInt(sourceId, 10))
+
+	err := AdminSourceRhcConnectionList(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Want status code %d. Got %d. Body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var out []model.RhcConnectionAdminResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the response: %s`, err)
+	}
+
+	var want []string
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.SourceId == sourceId {
+			want = append(want, strconv.FormatInt(link.RhcConnectionId, 10))
+		}
+	}
+
+	if len(out) != len(want) {
+		t.Fatalf(`want "%d" connections, got "%d"`, len(want), len(out))
+	}
+
+	for i, entry := range out {
+		if *entry.Id != want[i] {
+			t.Errorf(`want connection "%s", got "%s"`, want[i], *entry.Id)
+		}
+
+		if entry.Deleted {
+			t.Errorf(`want connection "%s" not to be marked as deleted`, *entry.Id)
+		}
+	}
+}
+
+func TestAdminSourceRhcConnectionListInvalidParam(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/admin/sources/xxx/rhc_connections",
+		nil,
+		map[string]interface{}{},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("xxx")
+
+	badRequestAdminSourceRhcConnectionList := ErrorHandlingContext(AdminSourceRhcConnectionList)
+	err := badRequestAdminSourceRhcConnectionList(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+// TestRhcConnectionListLastModified tests that the list returns the latest modification of the connections, and that
+// a "304 Not Modified" is returned when the client already has it.
+func TestRhcConnectionListLastModified(t *testing.T) {
+	lastModified := time.Date(2022, 6, 1, 10, 30, 15, 500, time.UTC)
+	rhcConnectionDao := &dao.MockRhcConnectionDao{RhcConnections: []model.RhcConnection{
+		{ID: 1, RhcId: "first", UpdatedAt: lastModified.Add(-time.Hour)},
+		{ID: 2, RhcId: "second", UpdatedAt: lastModified},
+	}}
+
+	backupDao := getRhcConnectionDao
+	getRhcConnectionDao = func(c echo.Context) (dao.RhcConnectionDao, error) { return rhcConnectionDao, nil }
+	defer func() { getRhcConnectionDao = backupDao }()
+
+	testCases := []struct {
+		ifModifiedSince string
+		wantStatus      int
+	}{
+		{ifModifiedSince: "", wantStatus: http.StatusOK},
+		{ifModifiedSince: "Wed, 01 Jun 2022 10:30:14 GMT", wantStatus: http.StatusOK},
+		{ifModifiedSince: "Wed, 01 Jun 2022 10:30:15 GMT", wantStatus: http.StatusNotModified},
+		{ifModifiedSince: "Thu, 02 Jun 2022 00:00:00 GMT", wantStatus: http.StatusNotModified},
+		{ifModifiedSince: "not a date", wantStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		c, rec := request.CreateTestContext(
+			http.MethodGet,
+			"/api/sources/v3.1/rhc_connections",
+			nil,
+			map[string]interface{}{
+				"limit":    100,
+				"offset":   0,
+				"filters":  []util.Filter{},
+				"tenantID": int64(1),
+			},
+		)
+
+		if tc.ifModifiedSince != "" {
+			c.Request().Header.Set(echo.HeaderIfModifiedSince, tc.ifModifiedSince)
+		}
+
+		err := RhcConnectionList(c)
+		if err != nil {
+			t.Fatalf(`[%s] unexpected error: %s`, tc.ifModifiedSince, err)
+		}
+
+		if rec.Code != tc.wantStatus {
+			t.Errorf(`[%s] want status "%d", got "%d"`, tc.ifModifiedSince, tc.wantStatus, rec.Code)
+		}
+
+		if got := rec.Header().Get(echo.HeaderLastModified); got != "Wed, 01 Jun 2022 10:30:15 GMT" {
+			t.Errorf(`[%s] want the last modification "Wed, 01 Jun 2022 10:30:15 GMT", got "%s"`, tc.ifModifiedSince, got)
+		}
+
+		if tc.wantStatus == http.StatusNotModified && rec.Body.Len() != 0 {
+			t.Errorf(`[%s] want an empty body, got "%s"`, tc.ifModifiedSince, rec.Body.String())
+		}
+	}
+}
+
+// TestRhcConnectionListWithoutLastModified tests that neither the "Last-Modified" header is set nor a "304 Not
+// Modified" is returned when there are no connections.
+func TestRhcConnectionListWithoutLastModified(t *testing.T) {
+	backupDao := getRhcConnectionDao
+	getRhcConnectionDao = func(c echo.Context) (dao.RhcConnectionDao, error) { return &dao.MockRhcConnectionDao{}, nil }
+	defer func() { getRhcConnectionDao = backupDao }()
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/rhc_connections",
+		nil,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"filters":  []util.Filter{},
+			"tenantID": int64(1),
+		},
+	)
+	c.Request().Header.Set(echo.HeaderIfModifiedSince, "Thu, 02 Jun 2022 00:00:00 GMT")
+
+	err := RhcConnectionList(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	if got := rec.Header().Get(echo.HeaderLastModified); got != "" {
+		t.Errorf(`want no "Last-Modified" header, got "%s"`, got)
+	}
+}
+
+// TestRhcConnectionSummaryBySource tests that the connections are summarized by the tenant's sources they're linked
+// to.
+func TestRhcConnectionSummaryBySource(t *testing.T) {
+	tenantId := int64(1)
+
+	rhcConnectionDao := &dao.MockRhcConnectionDao{RhcConnections: []model.RhcConnection{
+		{ID: 1, AvailabilityStatus: model.Available, Sources: []model.Source{{ID: 2, Name: "second", TenantID: tenantId}, {ID: 1, Name: "first", TenantID: tenantId}}},
+		{ID: 2, AvailabilityStatus: model.Unavailable, Sources: []model.Source{{ID: 1, Name: "first", TenantID: tenantId}}},
+		{ID: 3, AvailabilityStatus: model.Available, Sources: []model.Source{{ID: 3, Name: "other tenant", TenantID: tenantId + 1}}},
+	}}
+
+	backupDao := getRhcConnectionDao
+	getRhcConnectionDao = func(c echo.Context) (dao.RhcConnectionDao, error) { return rhcConnectionDao, nil }
+	defer func() { getRhcConnectionDao = backupDao }()
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/rhc_connections/summary",
+		nil,
+		map[string]interface{}{
+			"tenantID": tenantId,
+		},
+	)
+
+	err := RhcConnectionSummaryBySource(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var summary []model.RhcConnectionSourceSummary
+	err = json.Unmarshal(rec.Body.Bytes(), &summary)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	want := []model.RhcConnectionSourceSummary{
+		{SourceId: 1, SourceName: "first", ConnectionCount: 2, AvailableCount: 1},
+		{SourceId: 2, SourceName: "second", ConnectionCount: 1, AvailableCount: 1},
+	}
+
+	if len(summary) != len(want) {
+		t.Fatalf(`want "%d" summaries, got "%d"`, len(want), len(summary))
+	}
+
+	for i := range want {
+		if summary[i] != want[i] {
+			t.Errorf(`want summary "%+v", got "%+v"`, want[i], summary[i])
+		}
+	}
+}
+
+// TestRhcConnectionTopology tests that the links between the connections and the sources are returned as edges.
+func TestRhcConnectionTopology(t *testing.T) {
+	rhcConnectionDao := &dao.MockRhcConnectionDao{RhcConnections: []model.RhcConnection{
+		{ID: 1, Sources: []model.Source{{ID: 1}, {ID: 2}}},
+		{ID: 2, Sources: []model.Source{{ID: 2}}},
+	}}
+
+	backupDao := getRhcConnectionDao
+	getRhcConnectionDao = func(c echo.Context) (dao.RhcConnectionDao, error) { return rhcConnectionDao, nil }
+	defer func() { getRhcConnectionDao = backupDao }()
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/rhc_connections/topology",
+		nil,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"tenantID": int64(1),
+		},
+	)
+
+	err := RhcConnectionTopology(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var out struct {
+		Meta util.Metadata                             `json:"meta"`
+		Data []model.RhcConnectionTopologyEdgeResponse `json:"data"`
+	}
+
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	want := []model.RhcConnectionTopologyEdgeResponse{
+		{RhcConnectionId: "1", SourceId: "1"},
+		{RhcConnectionId: "1", SourceId: "2"},
+		{RhcConnectionId: "2", SourceId: "2"},
+	}
+
+	if out.Meta.Count != len(want) || len(out.Data) != len(want) {
+		t.Fatalf(`want "%d" edges, got "%d" with a count of "%d"`, len(want), len(out.Data), out.Meta.Count)
+	}
+
+	for i := range want {
+		if out.Data[i] != want[i] {
+			t.Errorf(`want edge "%+v", got "%+v"`, want[i], out.Data[i])
+		}
+	}
+}
+
+// TestRhcConnectionListRhcIdFiltersThroughRouter tests that the "/rhc_connections" route accepts both the repeated
+// "filter[rhc_id][]" form and the "filter[rhc_id][in]" form of the rhc_id filters.
+func TestRhcConnectionListRhcIdFiltersThroughRouter(t *testing.T) {
+	rhcIds := []string{fixtures.TestRhcConnectionData[0].RhcId, fixtures.TestRhcConnectionData[1].RhcId}
+
+	targets := []string{
+		fmt.Sprintf("/api/sources/v3.1/rhc_connections?filter[rhc_id][]=%s&filter[rhc_id][]=%s", rhcIds[0], rhcIds[1]),
+		fmt.Sprintf("/api/sources/v3.1/rhc_connections?filter[rhc_id][in]=%s,%s", rhcIds[0], rhcIds[1]),
+	}
+
+	for _, target := range targets {
+		rec := serveThroughRouter(t, http.MethodGet, target)
+		if rec.Code != http.StatusOK {
+			t.Errorf(`want status "%d" for "%s", got "%d": %s`, http.StatusOK, target, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// TestRhcConnectionStatusHistoryThroughRouter tests that the status history route resolves the tenant of the request,
+// so that the tenant's connections are found.
+func TestRhcConnectionStatusHistoryThroughRouter(t *testing.T) {
+	id := strconv.FormatInt(fixtures.TestRhcConnectionData[0].ID, 10)
+
+	rec := serveThroughRouter(t, http.MethodGet, "/api/sources/v3.1/rhc_connections/"+id+"/status_history")
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d": %s`, http.StatusOK, rec.Code, rec.Body.String())
+	}
+}
+
+// TestRhcConnectionListETag tests that the list's "ETag" changes when a connection gets deleted, even though the
+// latest modification stays the same, and that "If-None-Match" takes precedence over "If-Modified-Since".
+func TestRhcConnectionListETag(t *testing.T) {
+	lastModified := time.Date(2022, 6, 1, 10, 30, 15, 0, time.UTC)
+	rhcConnectionDao := &dao.MockRhcConnectionDao{RhcConnections: []model.RhcConnection{
+		{ID: 1, RhcId: "first", UpdatedAt: lastModified.Add(-time.Hour)},
+		{ID: 2, RhcId: "second", UpdatedAt: lastModified},
+	}}
+
+	backupDao := getRhcConnectionDao
+	getRhcConnectionDao = func(c echo.Context) (dao.RhcConnectionDao, error) { return rhcConnectionDao, nil }
+	defer func() { getRhcConnectionDao = backupDao }()
+
+	list := func(headers map[string]string) *httptest.ResponseRecorder {
+		c, rec := request.CreateTestContext(
+			http.MethodGet,
+			"/api/sources/v3.1/rhc_connections",
+			nil,
+			map[string]interface{}{
+				"limit":    100,
+				"offset":   0,
+				"filters":  []util.Filter{},
+				"tenantID": int64(1),
+			},
+		)
+
+		for name, value := range headers {
+			c.Request().Header.Set(name, value)
+		}
+
+		err := RhcConnectionList(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return rec
+	}
+
+	etag := list(nil).Header().Get("ETag")
+	if etag == "" {
+		t.Fatalf(`want an "ETag" header, got none`)
+	}
+
+	if rec := list(map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Errorf(`want status "%d" for the current "ETag", got "%d"`, http.StatusNotModified, rec.Code)
+	}
+
+	// Deleting the oldest connection doesn't change the latest modification, but it changes the list.
+	rhcConnectionDao.RhcConnections = rhcConnectionDao.RhcConnections[1:]
+
+	rec := list(map[string]string{"If-None-Match": etag, echo.HeaderIfModifiedSince: "Wed, 01 Jun 2022 10:30:15 GMT"})
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d" after a deletion, got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	if rec.Header().Get("ETag") == etag {
+		t.Errorf(`want the "ETag" to change after a deletion, got the same "%s"`, etag)
+	}
+}
@@ -14,13 +14,165 @@ var listMiddleware = []echo.MiddlewareFunc{
 }
 
 var tenancyWithListMiddleware = append([]echo.MiddlewareFunc{middleware.Tenancy}, listMiddleware...)
-var permissionMiddleware = []echo.MiddlewareFunc{middleware.Tenancy, middleware.PermissionCheck, middleware.RaiseEvent}
+var permissionMiddleware = []echo.MiddlewareFunc{middleware.ReadOnlyCheck, middleware.Tenancy, middleware.PermissionCheck, middleware.BodyLimit, middleware.ContentTypeCheck, middleware.RaiseEvent}
+
+// bulkCreateMiddleware is the "permissionMiddleware" with a bigger body limit, since the bulk create requests carry many
+// resources at once.
+var bulkCreateMiddleware = []echo.MiddlewareFunc{middleware.ReadOnlyCheck, middleware.Tenancy, middleware.PermissionCheck, middleware.BodyLimitOf(conf.MaxBulkCreateBodyBytes), middleware.ContentTypeCheck, middleware.RaiseEvent}
 var permissionWithListMiddleware = append(listMiddleware, middleware.PermissionCheck)
 
+// subresourcePermissionMiddleware is the "permissionMiddleware" with the permission check of the given subresource
+// instead of the general one, so that the write requests are also allowed by the roles that only grant the
+// subresource's "write" permission.
+func subresourcePermissionMiddleware(resourceType string) []echo.MiddlewareFunc {
+	return []echo.MiddlewareFunc{middleware.ReadOnlyCheck, middleware.Tenancy, middleware.PermissionCheckForSubresource(resourceType), middleware.BodyLimit, middleware.ContentTypeCheck, middleware.RaiseEvent}
+}
+
+// rhcConnectionFilterFields are the fields the connections can be filtered and sorted by.
+var rhcConnectionFilterFields = map[string]middleware.ColumnType{
+	"id":                        middleware.IntegerColumn,
+	"rhc_id":                    middleware.StringColumn,
+	"tags":                      middleware.JSONColumn,
+	"availability_status":       middleware.StringColumn,
+	"availability_status_error": middleware.StringColumn,
+	"last_checked_at":           middleware.TimestampColumn,
+	"last_available_at":         middleware.TimestampColumn,
+	"created_at":                middleware.TimestampColumn,
+	"updated_at":                middleware.TimestampColumn,
+	"updated_by":                middleware.StringColumn,
+}
+
+// sourceTypeFilterFields are the fields the source types can be filtered and sorted by.
+var sourceTypeFilterFields = map[string]middleware.ColumnType{
+	"id":           middleware.IntegerColumn,
+	"name":         middleware.StringColumn,
+	"product_name": middleware.StringColumn,
+	"vendor":       middleware.StringColumn,
+	"category":     middleware.StringColumn,
+	"display_name": middleware.StringColumn,
+	"created_at":   middleware.TimestampColumn,
+	"updated_at":   middleware.TimestampColumn,
+}
+
+// applicationTypeFilterFields are the fields the application types can be filtered and sorted by.
+var applicationTypeFilterFields = map[string]middleware.ColumnType{
+	"id":           middleware.IntegerColumn,
+	"name":         middleware.StringColumn,
+	"display_name": middleware.StringColumn,
+	"created_at":   middleware.TimestampColumn,
+	"updated_at":   middleware.TimestampColumn,
+}
+
+// sourceTypeListFilterFields are the fields the source types list accepts, which include the application type the
+// listed source types must be compatible with.
+var sourceTypeListFilterFields = withSubresourceFilterFields(map[string]middleware.ColumnType{
+	"application_type_id": middleware.IntegerColumn,
+}, "", sourceTypeFilterFields)
+
+// applicationTypeListFilterFields are the fields the application types list accepts, which include the source type the
+// listed application types must be compatible with.
+var applicationTypeListFilterFields = withSubresourceFilterFields(map[string]middleware.ColumnType{
+	"source_type_id": middleware.IntegerColumn,
+}, "", applicationTypeFilterFields)
+
+// sourceFilterFields are the fields the sources can be filtered and sorted by, including the ones of their source
+// types.
+var sourceFilterFields = withSubresourceFilterFields(map[string]middleware.ColumnType{
+	"id":                    middleware.IntegerColumn,
+	"name":                  middleware.StringColumn,
+	"uid":                   middleware.StringColumn,
+	"version":               middleware.StringColumn,
+	"imported":              middleware.StringColumn,
+	"source_ref":            middleware.StringColumn,
+	"app_creation_workflow": middleware.StringColumn,
+	"external_id":           middleware.StringColumn,
+	"cost_center":           middleware.StringColumn,
+	"budget_code":           middleware.StringColumn,
+	"source_type_id":        middleware.IntegerColumn,
+	"source_type_name":      middleware.StringColumn,
+	"availability_status":   middleware.StringColumn,
+	"last_checked_at":       middleware.TimestampColumn,
+	"last_available_at":     middleware.TimestampColumn,
+	"paused_at":             middleware.TimestampColumn,
+	"created_at":            middleware.TimestampColumn,
+	"updated_at":            middleware.TimestampColumn,
+}, "source_type", sourceTypeFilterFields)
+
+// applicationFilterFields are the fields the applications can be filtered and sorted by, including the ones of their
+// application types.
+var applicationFilterFields = withSubresourceFilterFields(map[string]middleware.ColumnType{
+	"id":                        middleware.IntegerColumn,
+	"source_id":                 middleware.IntegerColumn,
+	"application_type_id":       middleware.IntegerColumn,
+	"authtype":                  middleware.StringColumn,
+	"availability_status":       middleware.StringColumn,
+	"availability_status_error": middleware.StringColumn,
+	"last_checked_at":           middleware.TimestampColumn,
+	"last_available_at":         middleware.TimestampColumn,
+	"paused_at":                 middleware.TimestampColumn,
+	"created_at":                middleware.TimestampColumn,
+	"updated_at":                middleware.TimestampColumn,
+}, "application_type", applicationTypeFilterFields)
+
+// endpointFilterFields are the fields the endpoints can be filtered and sorted by.
+var endpointFilterFields = map[string]middleware.ColumnType{
+	"id":                        middleware.IntegerColumn,
+	"source_id":                 middleware.IntegerColumn,
+	"role":                      middleware.StringColumn,
+	"port":                      middleware.IntegerColumn,
+	"default":                   middleware.BooleanColumn,
+	"scheme":                    middleware.StringColumn,
+	"host":                      middleware.StringColumn,
+	"path":                      middleware.StringColumn,
+	"verify_ssl":                middleware.BooleanColumn,
+	"receptor_node":             middleware.StringColumn,
+	"availability_status":       middleware.StringColumn,
+	"availability_status_error": middleware.StringColumn,
+	"last_checked_at":           middleware.TimestampColumn,
+	"last_available_at":         middleware.TimestampColumn,
+	"paused_at":                 middleware.TimestampColumn,
+	"created_at":                middleware.TimestampColumn,
+	"updated_at":                middleware.TimestampColumn,
+}
+
+// authenticationFilterFields are the fields the authentications can be filtered and sorted by.
+var authenticationFilterFields = map[string]middleware.ColumnType{
+	"id":                        middleware.StringColumn,
+	"name":                      middleware.StringColumn,
+	"authtype":                  middleware.StringColumn,
+	"username":                  middleware.StringColumn,
+	"source_id":                 middleware.IntegerColumn,
+	"resource_type":             middleware.StringColumn,
+	"resource_id":               middleware.IntegerColumn,
+	"availability_status":       middleware.StringColumn,
+	"availability_status_error": middleware.StringColumn,
+	"last_checked_at":           middleware.TimestampColumn,
+	"last_available_at":         middleware.TimestampColumn,
+	"last_used_at":              middleware.TimestampColumn,
+	"expires_at":                middleware.TimestampColumn,
+	"expires_before":            middleware.TimestampColumn,
+	"created_at":                middleware.TimestampColumn,
+}
+
+// withSubresourceFilterFields returns the given fields along with the subresource's ones, which are given as
+// "subresource.field". An empty subresource adds its fields as they are.
+func withSubresourceFilterFields(fields map[string]middleware.ColumnType, subresource string, subresourceFields map[string]middleware.ColumnType) map[string]middleware.ColumnType {
+	for field, columnType := range subresourceFields {
+		if subresource != "" {
+			field = subresource + "." + field
+		}
+
+		fields[field] = columnType
+	}
+
+	return fields
+}
+
 func setupRoutes(e *echo.Echo) {
 	e.GET("/health", func(c echo.Context) error {
 		return c.String(http.StatusOK, "OK")
 	})
+	e.GET("/health/ready", HealthReady)
 
 	apiVersions := []string{"v1.0", "v2.0", "v3.0", "v3.1"}
 	for _, version := range apiVersions {
@@ -30,51 +182,62 @@ func setupRoutes(e *echo.Echo) {
 		r.GET("/openapi.json", PublicOpenApi(version))
 
 		// Bulk Create
-		r.POST("/bulk_create", BulkCreate, permissionMiddleware...)
+		r.POST("/bulk_create", BulkCreate, bulkCreateMiddleware...)
 
 		// Sources
-		r.GET("/sources", SourceList, tenancyWithListMiddleware...)
+		r.GET("/sources", SourceList, middleware.Tenancy, middleware.ParseFilters(sourceFilterFields), middleware.Pagination)
+		r.GET("/sources/by_external_id/:external_id", SourceGetByExternalId, middleware.Tenancy)
+		r.GET("/sources/health_summary", SourceHealthSummary, middleware.Tenancy)
 		r.GET("/sources/:id", SourceGet, middleware.Tenancy)
 		r.POST("/sources", SourceCreate, permissionMiddleware...)
+		r.POST("/sources/import/csv", SourceImportCsv, middleware.ReadOnlyCheck, middleware.Tenancy, middleware.PermissionCheck)
 		r.PATCH("/sources/:id", SourceEdit, append(permissionMiddleware, middleware.Notifier)...)
 		r.DELETE("/sources/:id", SourceDelete, append(permissionMiddleware, middleware.SuperKeyDestroySource)...)
 		r.POST("/sources/:source_id/check_availability", SourceCheckAvailability, middleware.Tenancy)
+		r.GET("/sources/:source_id/availability_stream", SourceAvailabilityStream, middleware.Tenancy)
 		r.GET("/sources/:source_id/application_types", SourceListApplicationTypes, tenancyWithListMiddleware...)
 		r.GET("/sources/:source_id/applications", SourceListApplications, tenancyWithListMiddleware...)
 		r.GET("/sources/:source_id/endpoints", SourceListEndpoint, tenancyWithListMiddleware...)
+		r.POST("/sources/:source_id/endpoints/bulk_create", SourceEndpointsBulkCreate, subresourcePermissionMiddleware("endpoints")...)
 		r.GET("/sources/:source_id/authentications", SourceListAuthentications, tenancyWithListMiddleware...)
 		r.GET("/sources/:source_id/rhc_connections", SourcesRhcConnectionList, tenancyWithListMiddleware...)
-		r.POST("/sources/:source_id/pause", SourcePause, middleware.Tenancy)
-		r.POST("/sources/:source_id/unpause", SourceUnpause, middleware.Tenancy)
+		r.DELETE("/sources/:source_id/rhc_connections/:rhc_connection_id", SourceRhcConnectionUnlink, permissionMiddleware...)
+		r.GET("/sources/:source_id/dependencies", SourceDependencies, middleware.Tenancy)
+		r.GET("/sources/:source_id/sla", SourceSLAReport, middleware.Tenancy)
+		r.PUT("/sources/:source_id/availability_schedule", SourceAvailabilityScheduleUpsert, permissionMiddleware...)
+		r.POST("/sources/:source_id/pause", SourcePause, middleware.ReadOnlyCheck, middleware.Tenancy)
+		r.POST("/sources/:source_id/unpause", SourceUnpause, middleware.ReadOnlyCheck, middleware.Tenancy)
 
 		// Applications
-		r.GET("/applications", ApplicationList, tenancyWithListMiddleware...)
+		r.GET("/applications", ApplicationList, middleware.Tenancy, middleware.ParseFilters(applicationFilterFields), middleware.Pagination)
+		r.GET("/applications/availability_summary", ApplicationAvailabilitySummary, middleware.Tenancy)
 		r.GET("/applications/:id", ApplicationGet, middleware.Tenancy)
 		r.POST("/applications", ApplicationCreate, permissionMiddleware...)
 		r.PATCH("/applications/:id", ApplicationEdit, append(permissionMiddleware, middleware.Notifier)...)
 		r.DELETE("/applications/:id", ApplicationDelete, append(permissionMiddleware, middleware.SuperKeyDestroyApplication)...)
 		r.GET("/applications/:application_id/authentications", ApplicationListAuthentications, tenancyWithListMiddleware...)
-		r.POST("/applications/:id/pause", ApplicationPause, middleware.Tenancy)
-		r.POST("/applications/:id/unpause", ApplicationUnpause, middleware.Tenancy)
+		r.POST("/applications/:id/pause", ApplicationPause, middleware.ReadOnlyCheck, middleware.Tenancy)
+		r.POST("/applications/:id/unpause", ApplicationUnpause, middleware.ReadOnlyCheck, middleware.Tenancy)
+		r.POST("/applications/:id/validate", ApplicationValidateCredentials, middleware.Tenancy)
 
 		// Authentications
-		r.GET("/authentications", AuthenticationList, tenancyWithListMiddleware...)
+		r.GET("/authentications", AuthenticationList, middleware.Tenancy, middleware.ParseFilters(authenticationFilterFields), middleware.Pagination)
 		r.GET("/authentications/:uid", AuthenticationGet, middleware.Tenancy)
-		r.POST("/authentications", AuthenticationCreate, permissionMiddleware...)
-		r.PATCH("/authentications/:uid", AuthenticationEdit, append(permissionMiddleware, middleware.Notifier)...)
-		r.DELETE("/authentications/:uid", AuthenticationDelete, permissionMiddleware...)
+		r.POST("/authentications", AuthenticationCreate, subresourcePermissionMiddleware("authentications")...)
+		r.PATCH("/authentications/:uid", AuthenticationEdit, append(subresourcePermissionMiddleware("authentications"), middleware.Notifier)...)
+		r.DELETE("/authentications/:uid", AuthenticationDelete, subresourcePermissionMiddleware("authentications")...)
 
 		// ApplicationTypes
-		r.GET("/application_types", ApplicationTypeList, listMiddleware...)
+		r.GET("/application_types", ApplicationTypeList, middleware.ParseFilters(applicationTypeListFilterFields), middleware.Pagination)
 		r.GET("/application_types/:id", ApplicationTypeGet)
 		r.GET("/application_types/:application_type_id/sources", ApplicationTypeListSource, tenancyWithListMiddleware...)
 
 		// Endpoints
-		r.GET("/endpoints", EndpointList, tenancyWithListMiddleware...)
+		r.GET("/endpoints", EndpointList, middleware.Tenancy, middleware.ParseFilters(endpointFilterFields), middleware.Pagination)
 		r.GET("/endpoints/:id", EndpointGet, middleware.Tenancy)
-		r.POST("/endpoints", EndpointCreate, permissionMiddleware...)
-		r.PATCH("/endpoints/:id", EndpointEdit, append(permissionMiddleware, middleware.Notifier)...)
-		r.DELETE("/endpoints/:id", EndpointDelete, permissionMiddleware...)
+		r.POST("/endpoints", EndpointCreate, subresourcePermissionMiddleware("endpoints")...)
+		r.PATCH("/endpoints/:id", EndpointEdit, append(subresourcePermissionMiddleware("endpoints"), middleware.Notifier)...)
+		r.DELETE("/endpoints/:id", EndpointDelete, subresourcePermissionMiddleware("endpoints")...)
 		r.GET("/endpoints/:endpoint_id/authentications", EndpointListAuthentications, tenancyWithListMiddleware...)
 
 		// ApplicationAuthentications
@@ -90,17 +253,38 @@ func setupRoutes(e *echo.Echo) {
 		r.GET("/application_types/:application_type_id/app_meta_data", ApplicationTypeListMetaData, listMiddleware...)
 
 		// SourceTypes
-		r.GET("/source_types", SourceTypeList, listMiddleware...)
+		r.GET("/source_types", SourceTypeList, middleware.ParseFilters(sourceTypeListFilterFields), middleware.Pagination)
 		r.GET("/source_types/:id", SourceTypeGet)
+		r.PATCH("/source_types/:id", SourceTypeEdit, middleware.ReadOnlyCheck, middleware.PermissionCheckPskOnly, middleware.BodyLimit, middleware.ContentTypeCheck)
 		r.GET("/source_types/:source_type_id/sources", SourceTypeListSource, tenancyWithListMiddleware...)
+		r.GET("/source_types/:id/capabilities", SourceTypeCapabilities)
+		r.POST("/source_types/:id/capabilities", SourceTypeCapabilitiesSet, middleware.ReadOnlyCheck, middleware.PermissionCheckPskOnly, middleware.BodyLimit, middleware.ContentTypeCheck)
+
+		// Metadata
+		r.GET("/meta/source_availability_transitions", SourceAvailabilityTransitions)
 
 		// Red Hat Connector Connections
-		r.GET("/rhc_connections", RhcConnectionList, tenancyWithListMiddleware...)
+		r.GET("/rhc_connections", RhcConnectionList, middleware.Tenancy, middleware.ParseFilters(rhcConnectionFilterFields), middleware.Pagination)
+		r.GET("/rhc_connections/stream", RhcConnectionStream, middleware.Tenancy)
+		r.GET("/rhc_connections/summary", RhcConnectionSummaryBySource, middleware.Tenancy)
+		r.GET("/rhc_connections/topology", RhcConnectionTopology, middleware.Tenancy, middleware.Pagination)
 		r.GET("/rhc_connections/:id", RhcConnectionGetById, permissionMiddleware...)
 		r.POST("/rhc_connections", RhcConnectionCreate, permissionMiddleware...)
 		r.PATCH("/rhc_connections/:id", RhcConnectionEdit, append(permissionMiddleware, middleware.Notifier)...)
 		r.DELETE("/rhc_connections/:id", RhcConnectionDelete, permissionMiddleware...)
 		r.GET("/rhc_connections/:id/sources", RhcConnectionSourcesList, permissionWithListMiddleware...)
+		r.GET("/rhc_connections/:id/status_history", RhcConnectionStatusHistory, append(tenancyWithListMiddleware, middleware.PermissionCheck)...)
+
+		// Tenants
+		r.GET("/tenants/:id/stats", TenantStats, middleware.Tenancy, middleware.PermissionCheckPskOrOrgAdmin)
+
+		// Audit logs
+		r.GET("/audit_logs/export", AuditLogExport, middleware.Tenancy, middleware.PermissionCheckPskOrOrgAdmin)
+
+		// Admin
+		r.GET("/admin/kafka/offsets", KafkaOffsetList, middleware.PermissionCheckPskOnly)
+		r.POST("/admin/kafka/offsets/reset", KafkaOffsetReset, middleware.ReadOnlyCheck, middleware.PermissionCheckPskOnly, middleware.BodyLimit, middleware.ContentTypeCheck)
+		r.GET("/admin/sources/:source_id/rhc_connections", AdminSourceRhcConnectionList, middleware.PermissionCheckPskOnly)
 
 		// GraphQL
 		// TODO: remove this once we get the crazy filtering going on the gqlgen graphql
@@ -128,6 +312,10 @@ func setupRoutes(e *echo.Echo) {
 	// Sources
 	internalv2.GET("/sources", InternalSourceList, permissionWithListMiddleware...)
 
+	// Dead letters
+	internalv2.GET("/dead_letters", InternalDeadLetterList, middleware.Tenancy, middleware.PermissionCheckPskOnly, middleware.Pagination)
+	internalv2.POST("/dead_letters/:id/replay", InternalDeadLetterReplay, middleware.ReadOnlyCheck, middleware.Tenancy, middleware.PermissionCheckPskOnly)
+
 	/**            **\
 	 * Internal API *
 	\**            **/
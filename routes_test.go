@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/labstack/echo/v4"
+	"github.com/redhatinsights/platform-go-middlewares/identity"
+)
+
+// serveThroughRouter sends the request through the actual routes and their middlewares, on behalf of the first
+// fixture tenant, and returns the recorded response. The request carries a system identity so that it passes the
+// permission checks without reaching RBAC, and the tenants get onboarded from the fixtures.
+func serveThroughRouter(t *testing.T, method, target string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	return serveThroughRouterAs(t, method, target, map[string]interface{}{"cn": "router-tests"})
+}
+
+// serveThroughRouterAs works like "serveThroughRouter", but the request carries the given system section in its
+// identity. A nil system section makes the request come from a regular user.
+func serveThroughRouterAs(t *testing.T, method, target string, system map[string]interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	backupOnboardingDao := dao.GetTenantOnboardingDao
+	dao.GetTenantOnboardingDao = func(context.Context) dao.TenantOnboardingDao {
+		return &dao.MockTenantOnboardingDao{Tenants: fixtures.TestTenantData}
+	}
+	defer func() { dao.GetTenantOnboardingDao = backupOnboardingDao }()
+
+	e := echo.New()
+	setupRoutes(e)
+
+	xRhIdentity, err := json.Marshal(identity.XRHID{Identity: identity.Identity{
+		AccountNumber: fixtures.TestTenantData[0].ExternalTenant,
+		OrgID:         fixtures.TestTenantData[0].OrgID,
+		System:        system,
+	}})
+	if err != nil {
+		t.Fatalf(`could not marshal the identity: %s`, err)
+	}
+
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set(h.XRHID, base64.StdEncoding.EncodeToString(xRhIdentity))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+// TestSubresourceRoutesPermissions tests that the reads of the endpoints and authentications subresources don't need
+// RBAC, and that their writes are checked against it. There is no RBAC service in the tests, so the checked requests
+// end up with a "service unavailable" response.
+func TestSubresourceRoutesPermissions(t *testing.T) {
+	testCases := []struct {
+		Method     string
+		Target     string
+		StatusCode int
+	}{
+		{Method: http.MethodGet, Target: "/api/sources/v3.1/sources/1/endpoints", StatusCode: http.StatusOK},
+		{Method: http.MethodGet, Target: "/api/sources/v3.1/sources/1/authentications", StatusCode: http.StatusOK},
+		{Method: http.MethodGet, Target: "/api/sources/v3.1/applications/1/authentications", StatusCode: http.StatusOK},
+		{Method: http.MethodPost, Target: "/api/sources/v3.1/sources/1/endpoints/bulk_create", StatusCode: http.StatusServiceUnavailable},
+		{Method: http.MethodPost, Target: "/api/sources/v3.1/endpoints", StatusCode: http.StatusServiceUnavailable},
+		{Method: http.MethodPatch, Target: "/api/sources/v3.1/endpoints/1", StatusCode: http.StatusServiceUnavailable},
+		{Method: http.MethodDelete, Target: "/api/sources/v3.1/endpoints/1", StatusCode: http.StatusServiceUnavailable},
+		{Method: http.MethodPost, Target: "/api/sources/v3.1/authentications", StatusCode: http.StatusServiceUnavailable},
+		{Method: http.MethodPatch, Target: "/api/sources/v3.1/authentications/1", StatusCode: http.StatusServiceUnavailable},
+		{Method: http.MethodDelete, Target: "/api/sources/v3.1/authentications/1", StatusCode: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		rec := serveThroughRouterAs(t, tc.Method, tc.Target, nil)
+
+		if rec.Code != tc.StatusCode {
+			t.Errorf(`%s %s: want status code "%d", got "%d": %s`, tc.Method, tc.Target, tc.StatusCode, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// TestListRoutesFilterFields tests that the list routes only accept the filters of their own fields, and that the
+// query parameters which aren't filters still get through.
+func TestListRoutesFilterFields(t *testing.T) {
+	testCases := []struct {
+		Target     string
+		StatusCode int
+	}{
+		{Target: "/api/sources/v3.1/sources?filter[name]=amazon", StatusCode: http.StatusOK},
+		{Target: "/api/sources/v3.1/sources?filter[source_type][name]=amazon", StatusCode: http.StatusOK},
+		{Target: "/api/sources/v3.1/sources?filter[password]=secret", StatusCode: http.StatusBadRequest},
+		{Target: "/api/sources/v3.1/sources?sort_by=password", StatusCode: http.StatusBadRequest},
+		{Target: "/api/sources/v3.1/applications?filter[application_type][name]=cost", StatusCode: http.StatusOK},
+		{Target: "/api/sources/v3.1/applications?include_source_type=true", StatusCode: http.StatusOK},
+		{Target: "/api/sources/v3.1/applications?filter[name]=cost", StatusCode: http.StatusBadRequest},
+		{Target: "/api/sources/v3.1/endpoints?filter[host][contains]=example", StatusCode: http.StatusOK},
+		{Target: "/api/sources/v3.1/endpoints?filter[port][contains]=80", StatusCode: http.StatusBadRequest},
+		{Target: "/api/sources/v3.1/authentications?filter[authtype]=arn", StatusCode: http.StatusOK},
+		{Target: "/api/sources/v3.1/authentications?filter[password]=secret", StatusCode: http.StatusBadRequest},
+		{Target: "/api/sources/v3.1/source_types?filter[name]=amazon", StatusCode: http.StatusOK},
+		{Target: "/api/sources/v3.1/source_types?filter[schema]=x", StatusCode: http.StatusBadRequest},
+		{Target: "/api/sources/v3.1/application_types?filter[name]=cost", StatusCode: http.StatusOK},
+		{Target: "/api/sources/v3.1/application_types?filter[supported_source_types]=x", StatusCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		rec := serveThroughRouter(t, http.MethodGet, tc.Target)
+
+		if rec.Code != tc.StatusCode {
+			t.Errorf(`%s: want status code "%d", got "%d": %s`, tc.Target, tc.StatusCode, rec.Code, rec.Body.String())
+		}
+	}
+}
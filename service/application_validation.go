@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
@@ -10,7 +11,7 @@ import (
 
 // by default we'll be using an empty instance of the apptype dao - replacing it
 // in tests.
-var AppTypeDao = dao.GetApplicationTypeDao(nil)
+var AppTypeDao = dao.GetApplicationTypeDao(context.Background(), nil)
 
 /*
 	Go through and validate the application create request.
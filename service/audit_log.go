@@ -0,0 +1,50 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/model"
+)
+
+// RecordAuditLog stores the change the given event represents in the tenant's audit trail.
+func RecordAuditLog(tenantId int64, eventType string, resource model.Event, actor string) error {
+	resourceId, err := eventResourceId(resource)
+	if err != nil {
+		return err
+	}
+
+	auditLog := &model.AuditLog{
+		EventType:    eventType,
+		ResourceType: strings.SplitN(eventType, ".", 2)[0],
+		ResourceId:   resourceId,
+		Actor:        actor,
+		TenantId:     tenantId,
+	}
+
+	return dao.GetAuditLogDao(context.Background()).Create(auditLog)
+}
+
+// eventResourceId returns the "id" field of the event's payload, or an empty string for the events which don't carry
+// one, such as the bulk ones.
+func eventResourceId(resource model.Event) (string, error) {
+	payload, err := json.Marshal(resource.ToEvent())
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %+v as event: %v", resource, err)
+	}
+
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	// Not every event is an object, in which case there is no ID to extract.
+	if decoder.Decode(&fields) != nil || fields["id"] == nil {
+		return "", nil
+	}
+
+	return fmt.Sprint(fields["id"]), nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/model"
+)
+
+// fakeAuditEvent is an event whose payload is the given value.
+type fakeAuditEvent struct {
+	payload interface{}
+}
+
+func (f fakeAuditEvent) ToEvent() interface{} {
+	return f.payload
+}
+
+// TestEventResourceId tests that the ID is extracted from the events' payloads, regardless of whether it is a number
+// or a string.
+func TestEventResourceId(t *testing.T) {
+	testCases := []struct {
+		event model.Event
+		want  string
+	}{
+		{event: fakeAuditEvent{payload: map[string]interface{}{"id": 12345678901234}}, want: "12345678901234"},
+		{event: fakeAuditEvent{payload: map[string]interface{}{"id": "abc"}}, want: "abc"},
+		{event: fakeAuditEvent{payload: map[string]interface{}{"name": "no id"}}, want: ""},
+		{event: fakeAuditEvent{payload: []int64{1, 2}}, want: ""},
+	}
+
+	for _, tc := range testCases {
+		got, err := eventResourceId(tc.event)
+		if err != nil {
+			t.Errorf(`want no error, got "%s"`, err)
+		}
+
+		if got != tc.want {
+			t.Errorf(`want resource ID "%s", got "%s"`, tc.want, got)
+		}
+	}
+}
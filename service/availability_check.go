@@ -194,7 +194,7 @@ func (acr availabilityCheckRequester) RhcConnectionAvailabilityCheck(source *m.S
 
 func pingRHC(source *m.Source, rhcConnection *m.RhcConnection, headers []kafka.Header) {
 	if cloudConnectorUrl == "" {
-		l.Log.Warnf("CLOUD_CONNECTOR_AVAILABILITY_CHECK_URL not set - skipping check for RHC Connection Availability Status [%v]", rhcConnection.RhcId)
+		l.Log.Warnf("CLOUD_CONNECTOR_AVAILABILITY_CHECK_URL not set - skipping check for RHC Connection Availability Status [%v]", l.RedactRhcId(rhcConnection.RhcId))
 		return
 	}
 
@@ -226,14 +226,14 @@ func pingRHC(source *m.Source, rhcConnection *m.RhcConnection, headers []kafka.H
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		l.Log.Warnf("Failed to request connection_status for RHC ID [%v]: %v", rhcConnection.RhcId, err)
+		l.Log.Warnf("Failed to request connection_status for RHC ID [%v]: %v", l.RedactRhcId(rhcConnection.RhcId), err)
 		return
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode/100 != 2 {
-		l.Log.Warnf("Invalid return code received for RHC ID [%v]: %v", rhcConnection.RhcId, resp.StatusCode)
+		l.Log.Warnf("Invalid return code received for RHC ID [%v]: %v", l.RedactRhcId(rhcConnection.RhcId), resp.StatusCode)
 		b, _ := io.ReadAll(resp.Body)
 		l.Log.Warnf("Body Returned from RHC ID [%v]: %s", rhcConnection.ID, b)
 
@@ -278,6 +278,7 @@ func updateRhcStatus(source *m.Source, status string, errstr string, rhcConnecti
 	now := time.Now()
 
 	source.AvailabilityStatus = status
+	source.AvailabilityStatusError = errstr
 	source.LastCheckedAt = &now
 	rhcConnection.AvailabilityStatus = status
 	rhcConnection.LastCheckedAt = &now
@@ -289,18 +290,23 @@ func updateRhcStatus(source *m.Source, status string, errstr string, rhcConnecti
 		rhcConnection.AvailabilityStatusError = errstr
 	}
 
-	err := dao.GetSourceDao(&source.TenantID).Update(source)
+	err := dao.GetSourceDao(context.Background(), &source.TenantID).Update(source)
 	if err != nil {
 		l.Log.Warnf("failed to update source availability status: %v", err)
 		return
 	}
 
-	err = dao.GetRhcConnectionDao(&source.TenantID).Update(rhcConnection)
+	rowsAffected, err := dao.GetRhcConnectionDao(context.Background(), &source.TenantID).Update(rhcConnection)
 	if err != nil {
 		l.Log.Warnf("failed to update RHC Connection availability status: %v", err)
 		return
 	}
 
+	if rowsAffected == 0 {
+		l.Log.Warnf("RHC Connection %d not found when updating its availability status", rhcConnection.ID)
+		return
+	}
+
 	err = RaiseEvent("RhcConnection.update", rhcConnection, headers)
 	if err != nil {
 		l.Log.Warnf("error raising RhcConnection.update event: %v", err)
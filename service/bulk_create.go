@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -36,7 +37,7 @@ func BulkAssembly(req m.BulkCreateRequest, tenant *m.Tenant) (*m.BulkCreateOutpu
 	var output m.BulkCreateOutput
 
 	// initiate a transaction that we'll rollback if anything bad happens.
-	err := dao.DB.Transaction(func(tx *gorm.DB) error {
+	err := dao.Transaction(func(tx *gorm.DB) error {
 		var err error
 
 		// parse the sources, then save them in the transaction.
@@ -74,7 +75,7 @@ func BulkAssembly(req m.BulkCreateRequest, tenant *m.Tenant) (*m.BulkCreateOutpu
 		}
 
 		for i := 0; i < len(output.Authentications); i++ {
-			err = dao.GetAuthenticationDao(&tenant.Id).BulkCreate(&output.Authentications[i])
+			err = dao.GetAuthenticationDao(context.Background(), &tenant.Id).BulkCreate(&output.Authentications[i])
 			if err != nil {
 				return err
 			}
@@ -102,6 +103,63 @@ func BulkAssembly(req m.BulkCreateRequest, tenant *m.Tenant) (*m.BulkCreateOutpu
 	return &output, err
 }
 
+// Statuses of the sources processed by BulkCreate.
+const (
+	BulkCreateStatusCreated = "created"
+	BulkCreateStatusSkipped = "skipped"
+	BulkCreateStatusError   = "error"
+)
+
+// BulkCreateResult is the outcome of one of the sources given to BulkCreate. The source is only set when it was
+// created, and the error explains why it was skipped or why it failed.
+type BulkCreateResult struct {
+	Source *m.Source
+	Status string
+	Err    error
+}
+
+/*
+	Creates the given sources one by one, unlike "BulkAssembly", so that a bad
+	source doesn't prevent the rest of them from being created.
+
+	The sources whose name already exists in the tenant are skipped, which
+	makes repeating an import harmless. When "failFast" is set the processing
+	stops at the first error, and the remaining sources are reported as
+	skipped.
+*/
+func BulkCreate(reqSources []m.BulkCreateSource, tenant *m.Tenant, failFast bool) []BulkCreateResult {
+	results := make([]BulkCreateResult, len(reqSources))
+	sourceDao := dao.GetSourceDao(context.Background(), &tenant.Id)
+
+	failed := false
+	for i := range reqSources {
+		if failed {
+			results[i] = BulkCreateResult{Status: BulkCreateStatusSkipped, Err: errors.New("a previous source failed to be created")}
+			continue
+		}
+
+		if reqSources[i].Name != nil && sourceDao.NameExistsInCurrentTenant(*reqSources[i].Name) {
+			results[i] = BulkCreateResult{Status: BulkCreateStatusSkipped, Err: fmt.Errorf("a source named %q already exists", *reqSources[i].Name)}
+			continue
+		}
+
+		sources, err := parseSources(reqSources[i:i+1], tenant)
+		if err == nil {
+			err = sourceDao.Create(&sources[0])
+		}
+
+		if err != nil {
+			results[i] = BulkCreateResult{Status: BulkCreateStatusError, Err: err}
+			failed = failFast
+			continue
+		}
+
+		results[i] = BulkCreateResult{Source: &sources[0], Status: BulkCreateStatusCreated}
+	}
+
+	return results
+}
+
 func parseSources(reqSources []m.BulkCreateSource, tenant *m.Tenant) ([]m.Source, error) {
 	sources := make([]m.Source, len(reqSources))
 
@@ -118,14 +176,14 @@ func parseSources(reqSources []m.BulkCreateSource, tenant *m.Tenant) ([]m.Source
 				return nil, err
 			}
 
-			sourceType, err = dao.GetSourceTypeDao().GetById(&id)
+			sourceType, err = dao.GetSourceTypeDao(context.Background()).GetById(&id)
 			if err != nil {
 				return nil, err
 			}
 
 		case source.SourceTypeName != "":
 			// look up the source type dynamically....or set it by ID later
-			sourceType, err = dao.GetSourceTypeDao().GetByName(source.SourceTypeName)
+			sourceType, err = dao.GetSourceTypeDao(context.Background()).GetByName(source.SourceTypeName)
 			if err != nil {
 				return nil, fmt.Errorf("invalid source_type_name for lookup: %v", source.SourceTypeName)
 			}
@@ -139,7 +197,7 @@ func parseSources(reqSources []m.BulkCreateSource, tenant *m.Tenant) ([]m.Source
 		s.SourceType = *sourceType
 
 		// validate the source request
-		err = ValidateSourceCreationRequest(dao.GetSourceDao(&tenant.Id), &source.SourceCreateRequest)
+		err = ValidateSourceCreationRequest(dao.GetSourceDao(context.Background(), &tenant.Id), &source.SourceCreateRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -152,6 +210,9 @@ func parseSources(reqSources []m.BulkCreateSource, tenant *m.Tenant) ([]m.Source
 		s.SourceRef = source.SourceRef
 		s.AppCreationWorkflow = source.AppCreationWorkflow
 		s.AvailabilityStatus = source.AvailabilityStatus
+		s.ExternalId = source.ExternalId
+		s.CostCenter = source.CostCenter
+		s.BudgetCode = source.BudgetCode
 		s.SourceTypeID = *source.SourceTypeID
 		s.Tenant = *tenant
 		s.TenantID = tenant.Id
@@ -182,7 +243,7 @@ func parseApplications(reqApplications []m.BulkCreateApplication, current *m.Bul
 				return nil, err
 			}
 
-			apptype, err := dao.GetApplicationTypeDao(&tenant.Id).GetById(&id)
+			apptype, err := dao.GetApplicationTypeDao(context.Background(), &tenant.Id).GetById(&id)
 			if err != nil {
 				return nil, err
 			}
@@ -192,7 +253,7 @@ func parseApplications(reqApplications []m.BulkCreateApplication, current *m.Bul
 
 		case app.ApplicationTypeName != "":
 			// dynamically look up the application type by name if passed
-			apptype, err := dao.GetApplicationTypeDao(&tenant.Id).GetByName(app.ApplicationTypeName)
+			apptype, err := dao.GetApplicationTypeDao(context.Background(), &tenant.Id).GetByName(app.ApplicationTypeName)
 			if err != nil {
 				return nil, fmt.Errorf("failed to lookup application_type_name %v", app.ApplicationTypeName)
 			}
@@ -211,7 +272,7 @@ func parseApplications(reqApplications []m.BulkCreateApplication, current *m.Bul
 			}
 
 			// check compatibility with the source type
-			err := dao.GetApplicationTypeDao(&tenant.Id).ApplicationTypeCompatibleWithSourceType(a.ApplicationType.Id, src.SourceType.Id)
+			err := dao.GetApplicationTypeDao(context.Background(), &tenant.Id).ApplicationTypeCompatibleWithSourceType(a.ApplicationType.Id, src.SourceType.Id)
 			if err != nil {
 				return nil, err
 			}
@@ -241,7 +302,7 @@ func parseEndpoints(reqEndpoints []m.BulkCreateEndpoint, current *m.BulkCreateOu
 	for _, endpt := range reqEndpoints {
 		e := m.Endpoint{}
 
-		err := ValidateEndpointCreateRequest(dao.GetEndpointDao(&tenant.Id), &endpt.EndpointCreateRequest)
+		err := ValidateEndpointCreateRequest(dao.GetEndpointDao(context.Background(), &tenant.Id), &endpt.EndpointCreateRequest)
 		if err != nil {
 			return nil, err
 		}
@@ -296,7 +357,7 @@ func linkUpAuthentications(req m.BulkCreateRequest, current *m.BulkCreateOutput,
 			var err error
 			switch strings.ToLower(auth.ResourceType) {
 			case "source":
-				_, err = dao.GetSourceDao(&tenant.Id).GetById(&id)
+				_, err = dao.GetSourceDao(context.Background(), &tenant.Id).GetById(&id)
 				if err == nil {
 					l.Log.Debugf("Found existing Source with id %v, adding to list and continuing", id)
 					a.ResourceID = id
@@ -304,7 +365,7 @@ func linkUpAuthentications(req m.BulkCreateRequest, current *m.BulkCreateOutput,
 					continue
 				}
 			case "application":
-				_, err = dao.GetApplicationDao(&tenant.Id).GetById(&id)
+				_, err = dao.GetApplicationDao(context.Background(), &tenant.Id).GetById(&id)
 				if err == nil {
 					l.Log.Debugf("Found existing Application with id %v, adding to list and continuing", id)
 					a.ResourceID = id
@@ -312,7 +373,7 @@ func linkUpAuthentications(req m.BulkCreateRequest, current *m.BulkCreateOutput,
 					continue
 				}
 			case "endpoint":
-				_, err = dao.GetEndpointDao(&tenant.Id).GetById(&id)
+				_, err = dao.GetEndpointDao(context.Background(), &tenant.Id).GetById(&id)
 				if err == nil {
 					l.Log.Debugf("Found existing Endpoint with id %v, adding to list and continuing", id)
 					a.ResourceID = id
@@ -367,7 +428,7 @@ func linkUpAuthentications(req m.BulkCreateRequest, current *m.BulkCreateOutput,
 // authentications are attached to an application that has the same "resource
 // name" which is passed in the payload.
 func linkupApplication(name string, apps []m.Application, tenantID *int64) (int64, error) {
-	at, err := dao.GetApplicationTypeDao(tenantID).GetByName(name)
+	at, err := dao.GetApplicationTypeDao(context.Background(), tenantID).GetByName(name)
 	if err != nil {
 		return 0, err
 	}
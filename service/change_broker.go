@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// changeBrokerRetryDelay is the time the broker waits before listening for the changes again after a failure.
+const changeBrokerRetryDelay = 5 * time.Second
+
+// ChangeEvent is a notification about a resource that was created, updated or deleted. The tenant is identified by its
+// organization ID and its account number, the same way the raised events identify it.
+type ChangeEvent struct {
+	OrgId        string          `json:"-"`
+	Account      string          `json:"-"`
+	ResourceType string          `json:"-"`
+	EventType    string          `json:"event_type"`
+	Payload      json.RawMessage `json:"payload"`
+}
+
+// ChangeSubscription receives the change events of a tenant's resources of a given type.
+type ChangeSubscription struct {
+	orgId        string
+	account      string
+	resourceType string
+	// Events receives the matching change events.
+	Events chan ChangeEvent
+	// Resync receives a signal whenever some events were missed, either because the subscriber wasn't keeping up or
+	// because they couldn't be received, which means that the subscriber should fetch the resources again to get back
+	// in sync.
+	Resync chan struct{}
+}
+
+// matches returns whether the event belongs to the subscription's tenant and resource type. The organization IDs
+// take precedence, and the account numbers are only compared when either side doesn't have one.
+func (cs *ChangeSubscription) matches(orgId, account, resourceType string) bool {
+	if cs.resourceType != resourceType {
+		return false
+	}
+
+	if cs.orgId != "" && orgId != "" {
+		return cs.orgId == orgId
+	}
+
+	return cs.account != "" && cs.account == account
+}
+
+// resync signals the subscription to resync, unless it has already been signaled.
+func (cs *ChangeSubscription) resync() {
+	select {
+	case cs.Resync <- struct{}{}:
+	default:
+	}
+}
+
+// ChangeBroker fans out the change events to the subscribers of the events' tenants. The events are received from
+// the resource changes the database announces, so that the subscribers get the changes made by any replica or
+// process. The broker only listens for them while it has subscribers.
+type ChangeBroker struct {
+	// listen calls the given function with every announced change until the context is done or the listener fails.
+	listen func(ctx context.Context, onChange func(payload string)) error
+
+	mutex         sync.RWMutex
+	subscriptions map[*ChangeSubscription]struct{}
+	// stop cancels the listener, and it is nil when the broker isn't listening.
+	stop context.CancelFunc
+}
+
+// NewChangeBroker returns a broker without any subscribers, which receives the changes through the given listener.
+func NewChangeBroker(listen func(ctx context.Context, onChange func(payload string)) error) *ChangeBroker {
+	return &ChangeBroker{
+		listen:        listen,
+		subscriptions: make(map[*ChangeSubscription]struct{}),
+	}
+}
+
+// Changes is the broker which streams the resource changes to the clients.
+var Changes = NewChangeBroker(dao.ListenResourceChanges)
+
+// Subscribe returns a subscription to the tenant's change events of the given resource type, which buffers up to
+// "bufferSize" events.
+func (cb *ChangeBroker) Subscribe(orgId, account, resourceType string, bufferSize int) *ChangeSubscription {
+	subscription := &ChangeSubscription{
+		orgId:        orgId,
+		account:      account,
+		resourceType: resourceType,
+		Events:       make(chan ChangeEvent, bufferSize),
+		Resync:       make(chan struct{}, 1),
+	}
+
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.subscriptions[subscription] = struct{}{}
+
+	if cb.stop == nil {
+		var ctx context.Context
+		ctx, cb.stop = context.WithCancel(context.Background())
+		go cb.receive(ctx)
+	}
+
+	return subscription
+}
+
+// Unsubscribe stops sending events to the given subscription, and stops listening for the changes when nobody else
+// is subscribed.
+func (cb *ChangeBroker) Unsubscribe(subscription *ChangeSubscription) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	delete(cb.subscriptions, subscription)
+
+	if len(cb.subscriptions) == 0 && cb.stop != nil {
+		cb.stop()
+		cb.stop = nil
+	}
+}
+
+// receive publishes the announced changes until the context is done. Whenever the listener fails the subscribers are
+// told to resync, since they might have missed some changes, and the broker listens again after a while.
+func (cb *ChangeBroker) receive(ctx context.Context) {
+	for {
+		err := cb.listen(ctx, cb.publishNotification)
+		if ctx.Err() != nil {
+			return
+		}
+
+		l.Log.Warnf("stopped receiving the resource changes, retrying in %s: %v", changeBrokerRetryDelay, err)
+		cb.resyncAll()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(changeBrokerRetryDelay):
+		}
+	}
+}
+
+// publishNotification publishes the change the notification's payload holds. When the notification doesn't carry the
+// resource because it didn't fit, the matching subscribers are told to resync instead.
+func (cb *ChangeBroker) publishNotification(payload string) {
+	var notification m.ResourceChangeNotification
+	err := json.Unmarshal([]byte(payload), &notification)
+	if err != nil {
+		l.Log.Warnf(`could not unmarshal the resource change notification "%s": %s`, payload, err)
+		return
+	}
+
+	event := ChangeEvent{
+		OrgId:        notification.OrgId,
+		Account:      notification.Account,
+		ResourceType: strings.SplitN(notification.EventType, ".", 2)[0],
+		EventType:    notification.EventType,
+		Payload:      notification.Payload,
+	}
+
+	if notification.Truncated {
+		cb.mutex.RLock()
+		defer cb.mutex.RUnlock()
+
+		for subscription := range cb.subscriptions {
+			if subscription.matches(event.OrgId, event.Account, event.ResourceType) {
+				subscription.resync()
+			}
+		}
+
+		return
+	}
+
+	cb.Publish(event)
+}
+
+// resyncAll tells every subscriber to resync.
+func (cb *ChangeBroker) resyncAll() {
+	cb.mutex.RLock()
+	defer cb.mutex.RUnlock()
+
+	for subscription := range cb.subscriptions {
+		subscription.resync()
+	}
+}
+
+// Publish sends the event to the matching subscriptions without blocking. When a subscription's buffer is full the
+// event is dropped for it, and it gets signaled to resync instead.
+func (cb *ChangeBroker) Publish(event ChangeEvent) {
+	cb.mutex.RLock()
+	defer cb.mutex.RUnlock()
+
+	for subscription := range cb.subscriptions {
+		if !subscription.matches(event.OrgId, event.Account, event.ResourceType) {
+			continue
+		}
+
+		select {
+		case subscription.Events <- event:
+		default:
+			subscription.resync()
+		}
+	}
+}
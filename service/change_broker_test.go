@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeChangeListener delivers the payloads sent through its channel, and fails with the error sent through "failures".
+type fakeChangeListener struct {
+	payloads chan string
+	failures chan error
+}
+
+func newFakeChangeListener() *fakeChangeListener {
+	return &fakeChangeListener{payloads: make(chan string), failures: make(chan error)}
+}
+
+func (f *fakeChangeListener) listen(ctx context.Context, onChange func(payload string)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-f.failures:
+			return err
+		case payload := <-f.payloads:
+			onChange(payload)
+		}
+	}
+}
+
+// blockingListen listens for nothing until the context is done.
+func blockingListen(ctx context.Context, _ func(payload string)) error {
+	<-ctx.Done()
+	return nil
+}
+
+// TestChangeBrokerPublishFiltersByTenantAndResource tests that the subscriptions only receive the events of their
+// tenant and resource type.
+func TestChangeBrokerPublishFiltersByTenantAndResource(t *testing.T) {
+	broker := NewChangeBroker(blockingListen)
+	subscription := broker.Subscribe("org1", "acct1", "RhcConnection", 10)
+	defer broker.Unsubscribe(subscription)
+
+	broker.Publish(ChangeEvent{OrgId: "org1", ResourceType: "RhcConnection", EventType: "RhcConnection.create"})
+	broker.Publish(ChangeEvent{OrgId: "org2", Account: "acct1", ResourceType: "RhcConnection", EventType: "RhcConnection.create"})
+	broker.Publish(ChangeEvent{OrgId: "org1", ResourceType: "Source", EventType: "Source.create"})
+	broker.Publish(ChangeEvent{Account: "acct1", ResourceType: "RhcConnection", EventType: "RhcConnection.update"})
+
+	if len(subscription.Events) != 2 {
+		t.Fatalf(`want two events received, got "%d"`, len(subscription.Events))
+	}
+
+	for _, want := range []string{"RhcConnection.create", "RhcConnection.update"} {
+		event := <-subscription.Events
+		if event.EventType != want {
+			t.Errorf(`want the "%s" event, got "%+v"`, want, event)
+		}
+	}
+}
+
+// TestChangeBrokerPublishResync tests that the events are dropped and a resync is signaled when the subscription's
+// buffer is full.
+func TestChangeBrokerPublishResync(t *testing.T) {
+	broker := NewChangeBroker(blockingListen)
+	subscription := broker.Subscribe("org1", "", "RhcConnection", 2)
+	defer broker.Unsubscribe(subscription)
+
+	for i := 0; i < 5; i++ {
+		broker.Publish(ChangeEvent{OrgId: "org1", ResourceType: "RhcConnection", EventType: "RhcConnection.update"})
+	}
+
+	if len(subscription.Events) != 2 {
+		t.Errorf(`want the buffer to hold "2" events, got "%d"`, len(subscription.Events))
+	}
+
+	if len(subscription.Resync) != 1 {
+		t.Errorf(`want a resync signaled, got "%d"`, len(subscription.Resync))
+	}
+}
+
+// TestChangeBrokerUnsubscribe tests that the unsubscribed subscriptions don't receive any more events, and that the
+// broker stops listening once nobody is subscribed.
+func TestChangeBrokerUnsubscribe(t *testing.T) {
+	broker := NewChangeBroker(blockingListen)
+	subscription := broker.Subscribe("org1", "", "RhcConnection", 10)
+	broker.Unsubscribe(subscription)
+
+	broker.Publish(ChangeEvent{OrgId: "org1", ResourceType: "RhcConnection", EventType: "RhcConnection.delete"})
+
+	if len(subscription.Events) != 0 {
+		t.Errorf(`want no events received, got "%d"`, len(subscription.Events))
+	}
+
+	if broker.stop != nil {
+		t.Errorf(`want the broker to stop listening, got it listening`)
+	}
+}
+
+// TestChangeBrokerReceivesNotifications tests that the announced changes are published to the matching subscribers,
+// and that the ones which didn't fit in the notification make them resync.
+func TestChangeBrokerReceivesNotifications(t *testing.T) {
+	listener := newFakeChangeListener()
+	broker := NewChangeBroker(listener.listen)
+	subscription := broker.Subscribe("org1", "", "RhcConnection", 10)
+	defer broker.Unsubscribe(subscription)
+
+	listener.payloads <- `{"org_id": "org2", "event_type": "RhcConnection.create", "payload": {}}`
+	listener.payloads <- `{"org_id": "org1", "event_type": "RhcConnection.create", "payload": {"id": "1"}}`
+
+	select {
+	case event := <-subscription.Events:
+		if event.EventType != "RhcConnection.create" || string(event.Payload) != `{"id": "1"}` {
+			t.Errorf(`want the connection's creation, got "%+v"`, event)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf(`want an event received, got nothing`)
+	}
+
+	listener.payloads <- `{"org_id": "org1", "event_type": "RhcConnection.update", "truncated": true}`
+
+	select {
+	case <-subscription.Resync:
+	case <-time.After(time.Second):
+		t.Errorf(`want a resync signaled for a truncated notification, got nothing`)
+	}
+
+	if len(subscription.Events) != 0 {
+		t.Errorf(`want no other events received, got "%d"`, len(subscription.Events))
+	}
+}
+
+// TestChangeBrokerListenerFailure tests that the subscribers are told to resync when the listener fails, since they
+// might have missed some changes.
+func TestChangeBrokerListenerFailure(t *testing.T) {
+	listener := newFakeChangeListener()
+	broker := NewChangeBroker(listener.listen)
+	subscription := broker.Subscribe("org1", "", "RhcConnection", 10)
+	defer broker.Unsubscribe(subscription)
+
+	listener.failures <- errors.New("connection lost")
+
+	select {
+	case <-subscription.Resync:
+	case <-time.After(time.Second):
+		t.Errorf(`want a resync signaled, got nothing`)
+	}
+}
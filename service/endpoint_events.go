@@ -0,0 +1,41 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// EndpointCreatedEventType is the event type of the event raised when an endpoint gets bulk created.
+const EndpointCreatedEventType = "endpoint.created"
+
+// EndpointEventPublisher implements the "dao.EndpointCreatedPublisher" interface by raising the events in the event
+// stream.
+type EndpointEventPublisher struct{}
+
+// PublishEndpointCreated raises the "endpoint.created" event for the given endpoint, which must have its tenant
+// loaded.
+func (e EndpointEventPublisher) PublishEndpointCreated(endpoint *m.Endpoint) error {
+	msg, err := json.Marshal(endpoint.ToEvent())
+	if err != nil {
+		return fmt.Errorf("failed to marshal %+v as event: %v", endpoint, err)
+	}
+
+	// The events are raised once the transaction commits, so the identity headers get generated from the tenant.
+	headers := []kafka.Header{
+		{Key: "event_type", Value: []byte(EndpointCreatedEventType)},
+		{Key: h.XRHID, Value: []byte(util.GeneratedXRhIdentity(endpoint.Tenant.ExternalTenant, endpoint.Tenant.OrgID))},
+		{Key: h.ORGID, Value: []byte(endpoint.Tenant.OrgID)},
+	}
+
+	err = Producer().RaiseEvent(EndpointCreatedEventType, msg, headers)
+	if err != nil {
+		return fmt.Errorf("failed to raise event to kafka: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/events"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// TestPublishEndpointCreated tests that the "endpoint.created" event carries the endpoint and the event type header.
+func TestPublishEndpointCreated(t *testing.T) {
+	originalProducer := Producer
+	defer func() { Producer = originalProducer }()
+
+	s := mocks.MockSender{}
+	Producer = func() events.Sender { return events.EventStreamProducer{Sender: &s} }
+
+	host := "example.com"
+	endpoint := m.Endpoint{ID: 5, SourceID: 3, Host: &host, Tenant: m.Tenant{Id: 1, ExternalTenant: "12345", OrgID: "abcde"}}
+
+	err := EndpointEventPublisher{}.PublishEndpointCreated(&endpoint)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if s.Hit != 1 {
+		t.Errorf(`want one event raised, got "%d"`, s.Hit)
+	}
+
+	var body m.EndpointEvent
+	err = json.Unmarshal([]byte(s.Body), &body)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the event body: %s`, err)
+	}
+
+	if body.ID != endpoint.ID || body.SourceID != endpoint.SourceID || body.Tenant == nil || *body.Tenant != "12345" {
+		t.Errorf(`want the event of endpoint "%d" of tenant "12345", got "%+v"`, endpoint.ID, body)
+	}
+
+	found := false
+	for _, header := range s.Headers {
+		if header.Key == "event_type" && string(header.Value) == EndpointCreatedEventType {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf(`want the "event_type" header to be "%s", got "%v"`, EndpointCreatedEventType, s.Headers)
+	}
+}
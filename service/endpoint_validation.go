@@ -103,3 +103,53 @@ func ValidateEndpointCreateRequest(dao dao.EndpointDao, ecr *model.EndpointCreat
 
 	return nil
 }
+
+// ValidateEndpointBulkCreateRequest validates every endpoint of a bulk creation for the given source with
+// "ValidateEndpointCreateRequest", and on top of that makes sure that the batch doesn't repeat a role or set more than
+// one endpoint as the default one. When the source has no endpoints and none of the batch's endpoints is the default
+// one, the first endpoint becomes the default. The errors are returned in the order of the endpoints.
+func ValidateEndpointBulkCreateRequest(endpointDao dao.EndpointDao, sourceId int64, ecrs []model.EndpointCreateRequest) []dao.BulkError {
+	bulkErrors := make([]dao.BulkError, 0)
+
+	sourceHasEndpoints := endpointDao.SourceHasEndpoints(sourceId)
+	defaultIndex := -1
+	roleIndexes := make(map[string]int, len(ecrs))
+
+	for i := range ecrs {
+		ecrs[i].SourceIDRaw = sourceId
+
+		// The validator sets every endpoint of a source without endpoints as the default one, which would make the
+		// whole batch default.
+		requestedDefault := ecrs[i].Default
+
+		err := ValidateEndpointCreateRequest(endpointDao, &ecrs[i])
+		if err != nil {
+			bulkErrors = append(bulkErrors, dao.BulkError{Index: i, Message: err.Error()})
+			continue
+		}
+
+		ecrs[i].Default = requestedDefault
+
+		if ecrs[i].Default {
+			if defaultIndex != -1 {
+				bulkErrors = append(bulkErrors, dao.BulkError{Index: i, Message: fmt.Sprintf("endpoint %d is already the default one", defaultIndex)})
+				continue
+			}
+
+			defaultIndex = i
+		}
+
+		if j, ok := roleIndexes[ecrs[i].Role]; ok {
+			bulkErrors = append(bulkErrors, dao.BulkError{Index: i, Message: fmt.Sprintf("the role is already used by endpoint %d", j)})
+			continue
+		}
+
+		roleIndexes[ecrs[i].Role] = i
+	}
+
+	if len(bulkErrors) == 0 && !sourceHasEndpoints && defaultIndex == -1 && len(ecrs) > 0 {
+		ecrs[0].Default = true
+	}
+
+	return bulkErrors
+}
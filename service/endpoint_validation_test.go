@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
 	"github.com/RedHatInsights/sources-api-go/model"
@@ -416,3 +417,78 @@ func TestInvalidAvailabilityStatuses(t *testing.T) {
 		}
 	}
 }
+
+// sourceWithoutEndpointsDao is an endpoint DAO for a source which has no endpoints yet.
+type sourceWithoutEndpointsDao struct {
+	dao.MockEndpointDao
+}
+
+func (s *sourceWithoutEndpointsDao) SourceHasEndpoints(_ int64) bool {
+	return false
+}
+
+// TestValidateEndpointBulkCreateRequest tests that every endpoint is run through the regular validator, and that the
+// roles and the default endpoint can't be repeated within the batch.
+func TestValidateEndpointBulkCreateRequest(t *testing.T) {
+	sourceId := fixtures.TestSourceData[0].ID
+	invalidPort := 70000
+
+	ecrs := []model.EndpointCreateRequest{
+		{Role: "first", Host: "example.com", Default: true},
+		{Role: "second", Host: "hello world"},
+		{Role: "third", Host: "example.com", Port: &invalidPort},
+		{Role: "first", Host: "example.com"},
+		{Role: "fifth", Host: "example.com", Default: true},
+		{Role: "sixth", Host: "example.com"},
+	}
+
+	bulkErrors := ValidateEndpointBulkCreateRequest(&dao.MockEndpointDao{}, sourceId, ecrs)
+
+	wantIndexes := []int{1, 2, 3, 4}
+	if len(bulkErrors) != len(wantIndexes) {
+		t.Fatalf(`want errors for the endpoints "%v", got "%v"`, wantIndexes, bulkErrors)
+	}
+
+	for i, bulkError := range bulkErrors {
+		if bulkError.Index != wantIndexes[i] {
+			t.Errorf(`want an error for endpoint "%d", got "%v"`, wantIndexes[i], bulkError)
+		}
+	}
+
+	if ecrs[5].SourceID != sourceId || ecrs[5].Port == nil || *ecrs[5].Port != defaultPort {
+		t.Errorf(`want the source and the default port to be set by the validator, got "%+v"`, ecrs[5])
+	}
+}
+
+// TestValidateEndpointBulkCreateRequestFirstDefault tests that only the first endpoint becomes the default one when
+// the source has no endpoints, instead of every endpoint of the batch.
+func TestValidateEndpointBulkCreateRequestFirstDefault(t *testing.T) {
+	ecrs := []model.EndpointCreateRequest{
+		{Role: "first", Host: "example.com"},
+		{Role: "second", Host: "example.com"},
+	}
+
+	bulkErrors := ValidateEndpointBulkCreateRequest(&sourceWithoutEndpointsDao{}, fixtures.TestSourceData[0].ID, ecrs)
+	if len(bulkErrors) != 0 {
+		t.Fatalf(`want no errors, got "%v"`, bulkErrors)
+	}
+
+	if !ecrs[0].Default || ecrs[1].Default {
+		t.Errorf(`want only the first endpoint to be the default one, got "%t" and "%t"`, ecrs[0].Default, ecrs[1].Default)
+	}
+
+	// An explicit default endpoint is kept as the only default one.
+	ecrs = []model.EndpointCreateRequest{
+		{Role: "first", Host: "example.com"},
+		{Role: "second", Host: "example.com", Default: true},
+	}
+
+	bulkErrors = ValidateEndpointBulkCreateRequest(&sourceWithoutEndpointsDao{}, fixtures.TestSourceData[0].ID, ecrs)
+	if len(bulkErrors) != 0 {
+		t.Fatalf(`want no errors, got "%v"`, bulkErrors)
+	}
+
+	if ecrs[0].Default || !ecrs[1].Default {
+		t.Errorf(`want only the second endpoint to be the default one, got "%t" and "%t"`, ecrs[0].Default, ecrs[1].Default)
+	}
+}
@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
+	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/internal/events"
 	"github.com/RedHatInsights/sources-api-go/kafka"
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
@@ -31,6 +33,34 @@ func RaiseEvent(eventType string, resource model.Event, headers []kafka.Header)
 	return nil
 }
 
+// RecordDeadLetter stores the event which could not be delivered to the event stream as a pending dead letter message,
+// so that it can be inspected and replayed later.
+func RecordDeadLetter(tenantId int64, eventType string, resource model.Event, headers []kafka.Header, deliveryErr error) error {
+	payload, err := json.Marshal(resource.ToEvent())
+	if err != nil {
+		return fmt.Errorf("failed to marshal %+v as event: %v", resource, err)
+	}
+
+	// Store the headers the same way the event stream sender would have sent them.
+	deadLetterHeaders := make([]kafka.Header, 0, len(headers)+2)
+	deadLetterHeaders = append(deadLetterHeaders, headers...)
+	deadLetterHeaders = append(deadLetterHeaders, kafka.Header{Key: "event_type", Value: []byte(eventType)}, kafka.Header{Key: "encoding", Value: []byte("json")})
+
+	deadLetter := &model.DeadLetterMessage{
+		Topic:     events.EventStreamTopic,
+		EventType: eventType,
+		Payload:   payload,
+		Error:     deliveryErr.Error(),
+	}
+
+	err = deadLetter.SetKafkaHeaders(deadLetterHeaders)
+	if err != nil {
+		return fmt.Errorf("failed to store the headers of the dead letter message: %v", err)
+	}
+
+	return dao.GetDeadLetterDao(context.Background(), &tenantId).Create(deadLetter)
+}
+
 // ForwadableHeaders fetches the required identity headers from the request that are needed to forward along:
 // 	1. x-rh-identity -- a generated one if it wasn't passed along (e.g. psk)
 //	2. x-rh-sources-account-number -- always passed if present, and used for generation.
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -27,8 +28,8 @@ func TestMain(t *testing.M) {
 	} else if flags.Integration {
 		database.ConnectAndMigrateDB("service")
 
-		endpointDao = dao.GetEndpointDao(&fixtures.TestTenantData[0].Id)
-		sourceDao = dao.GetSourceDao(&fixtures.TestTenantData[0].Id)
+		endpointDao = dao.GetEndpointDao(context.Background(), &fixtures.TestTenantData[0].Id)
+		sourceDao = dao.GetSourceDao(context.Background(), &fixtures.TestTenantData[0].Id)
 		database.CreateFixtures()
 	} else {
 		endpointDao = &dao.MockEndpointDao{}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// RhcConnectionAvailabilityRequester implements the "dao.RhcConnectionAvailabilityPublisher" interface by asking
+// cloud-connector for the status of the connection.
+type RhcConnectionAvailabilityRequester struct{}
+
+// PublishAvailabilityCheck fetches the source and the connection of the request and pings cloud-connector in the
+// background, which in turn updates the connection's status.
+func (r RhcConnectionAvailabilityRequester) PublishAvailabilityCheck(request m.RhcConnectionAvailabilityCheck) error {
+	source, err := dao.GetSourceDao(context.Background(), &request.TenantId).GetByIdWithPreload(&request.SourceId, "Tenant")
+	if err != nil {
+		return fmt.Errorf("unable to fetch the source: %w", err)
+	}
+
+	rhcConnection, err := dao.GetRhcConnectionDao(context.Background(), &request.TenantId).GetById(&request.RhcConnectionId)
+	if err != nil {
+		return fmt.Errorf("unable to fetch the rhcConnection: %w", err)
+	}
+
+	// There is no request to forward the identity headers from, so they get generated from the tenant.
+	headers := []kafka.Header{
+		{Key: h.XRHID, Value: []byte(util.GeneratedXRhIdentity(source.Tenant.ExternalTenant, source.Tenant.OrgID))},
+	}
+
+	if source.Tenant.ExternalTenant != "" {
+		headers = append(headers, kafka.Header{Key: h.ACCOUNT_NUMBER, Value: []byte(source.Tenant.ExternalTenant)})
+	}
+	if source.Tenant.OrgID != "" {
+		headers = append(headers, kafka.Header{Key: h.ORGID, Value: []byte(source.Tenant.OrgID)})
+	}
+
+	go pingRHC(source, rhcConnection, headers)
+
+	return nil
+}
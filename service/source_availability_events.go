@@ -0,0 +1,69 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/google/uuid"
+)
+
+// SourceAvailabilityChangedEventType is the event type of the event raised when the availability status of a source
+// changes.
+const SourceAvailabilityChangedEventType = "source.availability_status.changed"
+
+// cloudEventSource identifies the service as the producer of the CloudEvents.
+const cloudEventSource = "urn:redhat:source:sources-api"
+
+// cloudEvent is the structured mode envelope of the CloudEvents specification, version 1.0.
+type cloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	Id              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Subject         string      `json:"subject"`
+	Time            string      `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+// SourceAvailabilityEventPublisher implements the "dao.SourceAvailabilityChangedPublisher" interface by raising the
+// changes as CloudEvents in the event stream.
+type SourceAvailabilityEventPublisher struct{}
+
+// PublishSourceAvailabilityChanged raises the "source.availability_status.changed" event for the given change.
+func (s SourceAvailabilityEventPublisher) PublishSourceAvailabilityChanged(event *m.SourceAvailabilityChangedEvent) error {
+	msg, err := json.Marshal(newSourceAvailabilityCloudEvent(event, time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to marshal %+v as event: %v", event, err)
+	}
+
+	headers := []kafka.Header{
+		{Key: "event_type", Value: []byte(SourceAvailabilityChangedEventType)},
+	}
+
+	err = Producer().RaiseEvent(SourceAvailabilityChangedEventType, msg, headers)
+	if err != nil {
+		return fmt.Errorf("failed to raise event to kafka: %v", err)
+	}
+
+	return nil
+}
+
+// newSourceAvailabilityCloudEvent wraps the given change in a CloudEvent which happened at the given time.
+func newSourceAvailabilityCloudEvent(event *m.SourceAvailabilityChangedEvent, now time.Time) *cloudEvent {
+	return &cloudEvent{
+		SpecVersion:     "1.0",
+		Id:              uuid.New().String(),
+		Source:          cloudEventSource,
+		Type:            SourceAvailabilityChangedEventType,
+		Subject:         strconv.FormatInt(event.SourceId, 10),
+		Time:            util.DateTimeToRFC3339(now),
+		DataContentType: "application/json",
+		Data:            event,
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/events"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// TestPublishSourceAvailabilityChanged tests that the availability changes are raised as CloudEvents which carry the
+// change in their data.
+func TestPublishSourceAvailabilityChanged(t *testing.T) {
+	originalProducer := Producer
+	defer func() { Producer = originalProducer }()
+
+	s := mocks.MockSender{}
+	Producer = func() events.Sender { return events.EventStreamProducer{Sender: &s} }
+
+	checkedAt := time.Date(2022, 6, 1, 10, 30, 0, 0, time.UTC)
+	change := &m.SourceAvailabilityChangedEvent{
+		SourceId:          5,
+		TenantId:          1,
+		PreviousStatus:    m.Available,
+		NewStatus:         m.Unavailable,
+		CheckedAt:         &checkedAt,
+		AvailabilityError: "host unreachable",
+	}
+
+	err := SourceAvailabilityEventPublisher{}.PublishSourceAvailabilityChanged(change)
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if s.Hit != 1 {
+		t.Errorf(`want one event raised, got "%d"`, s.Hit)
+	}
+
+	var got struct {
+		SpecVersion string                           `json:"specversion"`
+		Id          string                           `json:"id"`
+		Type        string                           `json:"type"`
+		Subject     string                           `json:"subject"`
+		Data        m.SourceAvailabilityChangedEvent `json:"data"`
+	}
+
+	err = json.Unmarshal([]byte(s.Body), &got)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the event: %s`, err)
+	}
+
+	if got.SpecVersion != "1.0" || got.Id == "" || got.Type != SourceAvailabilityChangedEventType || got.Subject != "5" {
+		t.Errorf(`want a CloudEvent of type "%s" for source "5", got "%s"`, SourceAvailabilityChangedEventType, s.Body)
+	}
+
+	if got.Data.PreviousStatus != change.PreviousStatus || got.Data.NewStatus != change.NewStatus || got.Data.AvailabilityError != change.AvailabilityError {
+		t.Errorf(`want the data "%+v", got "%+v"`, *change, got.Data)
+	}
+
+	if got.Data.CheckedAt == nil || !got.Data.CheckedAt.Equal(checkedAt) {
+		t.Errorf(`want checked at "%s", got "%v"`, checkedAt, got.Data.CheckedAt)
+	}
+
+	found := false
+	for _, header := range s.Headers {
+		if header.Key == "event_type" && string(header.Value) == SourceAvailabilityChangedEventType {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf(`want the "event_type" header to be "%s", got "%v"`, SourceAvailabilityChangedEventType, s.Headers)
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// Columns of the CSV files the sources get imported from.
+const (
+	csvColumnName               = "name"
+	csvColumnSourceTypeName     = "source_type_name"
+	csvColumnAvailabilityStatus = "availability_status"
+	csvColumnExternalId         = "external_id"
+	csvColumnCostCenter         = "cost_center"
+)
+
+// requiredCsvColumns are the columns every imported CSV file must have.
+var requiredCsvColumns = []string{csvColumnName, csvColumnSourceTypeName}
+
+// ParseSourcesCsv parses the given CSV file into the bulk create requests for the sources, one per row. The first row
+// must be a header naming the columns, in any order. The "name" and "source_type_name" columns are required, and the
+// "availability_status", "cost_center" and "external_id" ones are optional. Any other columns are ignored.
+//
+// An error is returned for malformed files or missing required columns, so that nothing gets imported from them.
+func ParseSourcesCsv(file io.Reader) ([]m.BulkCreateSource, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("malformed CSV file: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, column := range requiredCsvColumns {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("the CSV file is missing the required %q column", column)
+		}
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("malformed CSV file: %w", err)
+	}
+
+	sources := make([]m.BulkCreateSource, 0, len(records))
+	for _, record := range records {
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok {
+				return ""
+			}
+
+			return strings.TrimSpace(record[i])
+		}
+
+		name := value(csvColumnName)
+
+		source := m.BulkCreateSource{
+			SourceCreateRequest: m.SourceCreateRequest{
+				Name:               &name,
+				AvailabilityStatus: value(csvColumnAvailabilityStatus),
+			},
+			SourceTypeName: value(csvColumnSourceTypeName),
+		}
+
+		if externalId := value(csvColumnExternalId); externalId != "" {
+			source.ExternalId = &externalId
+		}
+
+		if costCenter := value(csvColumnCostCenter); costCenter != "" {
+			source.CostCenter = &costCenter
+		}
+
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseSourcesCsv tests that the rows get mapped to the bulk create requests regardless of the columns' order.
+func TestParseSourcesCsv(t *testing.T) {
+	contents := "source_type_name,Name,external_id,cost_center,unknown\n" +
+		"amazon,first source,first-external-id,dept-123,ignored\n" +
+		"google, second source ,,,\n"
+
+	sources, err := ParseSourcesCsv(strings.NewReader(contents))
+	if err != nil {
+		t.Fatalf("want no error, got %s", err)
+	}
+
+	if len(sources) != 2 {
+		t.Fatalf("want 2 sources, got %d", len(sources))
+	}
+
+	if *sources[0].Name != "first source" || sources[0].SourceTypeName != "amazon" {
+		t.Errorf(`unexpected first source: name "%s", source type name "%s"`, *sources[0].Name, sources[0].SourceTypeName)
+	}
+
+	if sources[0].ExternalId == nil || *sources[0].ExternalId != "first-external-id" {
+		t.Errorf(`want external id "first-external-id", got %v`, sources[0].ExternalId)
+	}
+
+	if sources[0].CostCenter == nil || *sources[0].CostCenter != "dept-123" {
+		t.Errorf(`want cost center "dept-123", got %v`, sources[0].CostCenter)
+	}
+
+	if *sources[1].Name != "second source" || sources[1].SourceTypeName != "google" {
+		t.Errorf(`unexpected second source: name "%s", source type name "%s"`, *sources[1].Name, sources[1].SourceTypeName)
+	}
+
+	if sources[1].ExternalId != nil {
+		t.Errorf(`want no external id, got "%s"`, *sources[1].ExternalId)
+	}
+
+	if sources[1].CostCenter != nil {
+		t.Errorf(`want no cost center, got "%s"`, *sources[1].CostCenter)
+	}
+}
+
+// TestParseSourcesCsvInvalid tests that the malformed files and the ones missing the required columns are rejected.
+func TestParseSourcesCsvInvalid(t *testing.T) {
+	files := []string{
+		"",
+		"name\nfirst source\n",
+		"source_type_name\namazon\n",
+		"name,source_type_name\nfirst source,amazon,extra field\n",
+		"name,source_type_name\n\"first source,amazon\n",
+	}
+
+	for _, contents := range files {
+		_, err := ParseSourcesCsv(strings.NewReader(contents))
+		if err == nil {
+			t.Errorf("want an error for the file %q, got none", contents)
+		}
+	}
+}
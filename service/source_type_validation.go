@@ -0,0 +1,30 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/RedHatInsights/sources-api-go/model"
+)
+
+// ValidateSourceTypeEditRequest validates that the icon URL, if given, is an absolute HTTPS URL, and that the display
+// name, if given, is not blank.
+func ValidateSourceTypeEditRequest(req *model.SourceTypeEditRequest) error {
+	if req.IconUrl != nil {
+		iconUrl, err := url.Parse(*req.IconUrl)
+		if err != nil {
+			return errors.New("the icon url is not a valid url")
+		}
+
+		if !iconUrl.IsAbs() || iconUrl.Scheme != "https" || iconUrl.Host == "" {
+			return errors.New("the icon url must be an absolute https url")
+		}
+	}
+
+	if req.DisplayName != nil && strings.TrimSpace(*req.DisplayName) == "" {
+		return errors.New("the display name cannot be empty")
+	}
+
+	return nil
+}
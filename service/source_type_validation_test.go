@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestValidateSourceTypeEditRequest tests that valid icon URLs and display names are accepted.
+func TestValidateSourceTypeEditRequest(t *testing.T) {
+	req := model.SourceTypeEditRequest{
+		IconUrl:     util.StringRef("https://example.com/icons/amazon.svg"),
+		DisplayName: util.StringRef("Amazon Web Services"),
+	}
+
+	err := ValidateSourceTypeEditRequest(&req)
+	if err != nil {
+		t.Errorf("want no error, got %s", err)
+	}
+}
+
+// TestValidateSourceTypeEditRequestInvalidIconUrl tests that the icon URLs which aren't absolute HTTPS URLs are
+// rejected.
+func TestValidateSourceTypeEditRequestInvalidIconUrl(t *testing.T) {
+	iconUrls := []string{
+		"",
+		"/icons/amazon.svg",
+		"http://example.com/icons/amazon.svg",
+		"https:///icons/amazon.svg",
+		"https://exa mple.com",
+	}
+
+	for _, iconUrl := range iconUrls {
+		req := model.SourceTypeEditRequest{IconUrl: util.StringRef(iconUrl)}
+
+		err := ValidateSourceTypeEditRequest(&req)
+		if err == nil {
+			t.Errorf(`want an error for the icon url "%s", got none`, iconUrl)
+		}
+	}
+}
+
+// TestValidateSourceTypeEditRequestBlankDisplayName tests that blank display names are rejected.
+func TestValidateSourceTypeEditRequestBlankDisplayName(t *testing.T) {
+	req := model.SourceTypeEditRequest{DisplayName: util.StringRef("   ")}
+
+	err := ValidateSourceTypeEditRequest(&req)
+	if err == nil {
+		t.Error("want an error for a blank display name, got none")
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"regexp"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/model"
@@ -14,6 +15,9 @@ import (
 // needs to be validated.
 var validWorkflowStatuses = []string{model.AccountAuth, model.ManualConfig}
 
+// externalIdRegexp matches the external IDs we accept for the sources.
+var externalIdRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,255}$`)
+
 // ValidateSourceCreationRequest validates that the required fields of the SourceCreateRequest request hold proper
 // values. In the specific case of the UUID, if an empty or nil one is provided, a new random UUID is generated and
 // appended to the request.
@@ -40,6 +44,11 @@ func ValidateSourceCreationRequest(dao dao.SourceDao, req *model.SourceCreateReq
 		return fmt.Errorf("invalid status")
 	}
 
+	err := validateExternalId(req.ExternalId)
+	if err != nil {
+		return err
+	}
+
 	// Try to get the SourceTypeID. If an error occurs, the user gets a generic error message, as they are not
 	// interested in the underlying ones
 	value, err := util.InterfaceToInt64(req.SourceTypeIDRaw)
@@ -55,3 +64,22 @@ func ValidateSourceCreationRequest(dao dao.SourceDao, req *model.SourceCreateReq
 
 	return nil
 }
+
+// ValidateSourceEditRequest validates that the fields of the SourceEditRequest hold proper values.
+func ValidateSourceEditRequest(req *model.SourceEditRequest) error {
+	return validateExternalId(req.ExternalId)
+}
+
+// validateExternalId validates that the external ID, if given, is made of up to 255 alphanumeric characters, dashes or
+// underscores.
+func validateExternalId(externalId *string) error {
+	if externalId == nil {
+		return nil
+	}
+
+	if !externalIdRegexp.MatchString(*externalId) {
+		return errors.New("the external id must be made of up to 255 alphanumeric characters, dashes or underscores")
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"math"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
@@ -254,3 +255,36 @@ func TestInvalidSourceTypeIdFormat(t *testing.T) {
 		}
 	}
 }
+
+// TestExternalIdFormat tests that only the external IDs made of up to 255 alphanumeric characters, dashes or
+// underscores pass the validation, both when creating and when editing the sources.
+func TestExternalIdFormat(t *testing.T) {
+	testData := []struct {
+		ExternalId string
+		Valid      bool
+	}{
+		{ExternalId: "abc-123_DEF", Valid: true},
+		{ExternalId: strings.Repeat("a", 255), Valid: true},
+		{ExternalId: "", Valid: false},
+		{ExternalId: strings.Repeat("a", 256), Valid: false},
+		{ExternalId: "with spaces", Valid: false},
+		{ExternalId: "with/slash", Valid: false},
+	}
+
+	for _, tt := range testData {
+		externalId := tt.ExternalId
+
+		createRequest := setUp()
+		createRequest.ExternalId = &externalId
+
+		err := ValidateSourceCreationRequest(sourceDao, &createRequest)
+		if tt.Valid != (err == nil) {
+			t.Errorf(`unexpected validation result on create for external id "%s". Want valid "%t", got error "%v"`, externalId, tt.Valid, err)
+		}
+
+		err = ValidateSourceEditRequest(&model.SourceEditRequest{ExternalId: &externalId})
+		if tt.Valid != (err == nil) {
+			t.Errorf(`unexpected validation result on edit for external id "%s". Want valid "%t", got error "%v"`, externalId, tt.Valid, err)
+		}
+	}
+}
@@ -1,14 +1,20 @@
 package service
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/kafka"
 	logging "github.com/RedHatInsights/sources-api-go/logger"
 	"github.com/RedHatInsights/sources-api-go/model"
 )
 
+// aggregateBulkEvents makes the bulk removals raise a single aggregated event instead of one event per removed
+// resource.
+var aggregateBulkEvents = config.Get().AggregateBulkEvents
+
 // DeleteCascade removes the resource type and all its dependants, raising an event for every deleted resource. Returns
 // an error when the resources and its dependants could not be successfully removed.
 //
@@ -16,6 +22,9 @@ import (
 // connections.
 // - In the other cases, it removes the resource itself.
 //
+// The removed connections are reported in a single "RhcConnection.bulk_destroy" event when the bulk events are
+// aggregated.
+//
 // In both cases the authentications are fetched for every single resource and sub resource, and they get deleted in
 // batch.
 //
@@ -32,12 +41,12 @@ import (
 // Finally, those authentications are safely encrypted so they can stay in their datastores until we manually remove
 // them.
 func DeleteCascade(tenantId *int64, resourceType string, resourceId int64, headers []kafka.Header) error {
-	authenticationsDao := dao.GetAuthenticationDao(tenantId)
+	authenticationsDao := dao.GetAuthenticationDao(context.Background(), tenantId)
 	var authentications []model.Authentication
 
 	switch resourceType {
 	case "Source":
-		sourceDao := dao.GetSourceDao(tenantId)
+		sourceDao := dao.GetSourceDao(context.Background(), tenantId)
 		applicationAuthentications, applications, endpoints, rhcConnections, source, err := sourceDao.DeleteCascade(resourceId)
 		if err != nil {
 			return fmt.Errorf(`could not completely delete the source: %s`, err)
@@ -72,12 +81,7 @@ func DeleteCascade(tenantId *int64, resourceType string, resourceId int64, heade
 		}
 
 		// Raise events for the deleted connections.
-		for _, connection := range rhcConnections {
-			err := RaiseEvent("RhcConnection.destroy", &connection, headers)
-			if err != nil {
-				logging.Log.Errorf(`Event "RhcConnection.destroy" could not be raised for rhcConnection %v: %s`, connection.ToEvent(), err)
-			}
-		}
+		raiseRhcConnectionDestroyEvents(rhcConnections, headers)
 
 		// Raise an event for the source itself.
 		err = RaiseEvent("Source.destroy", source, headers)
@@ -105,7 +109,7 @@ func DeleteCascade(tenantId *int64, resourceType string, resourceId int64, heade
 		}
 
 	case "Application":
-		applicationsDao := dao.GetApplicationDao(tenantId)
+		applicationsDao := dao.GetApplicationDao(context.Background(), tenantId)
 		applicationAuthentications, application, err := applicationsDao.DeleteCascade(resourceId)
 		if err != nil {
 			return fmt.Errorf(`could not completely delete the application: %s`, err)
@@ -134,7 +138,7 @@ func DeleteCascade(tenantId *int64, resourceType string, resourceId int64, heade
 		authentications = append(authentications, auths...)
 	case "Endpoint":
 		// Delete the endpoint.
-		endpointDao := dao.GetEndpointDao(tenantId)
+		endpointDao := dao.GetEndpointDao(context.Background(), tenantId)
 		endpoint, err := endpointDao.Delete(&resourceId)
 		if err != nil {
 			return fmt.Errorf(`could not delete the endpoint: %s`, err)
@@ -171,3 +175,33 @@ func DeleteCascade(tenantId *int64, resourceType string, resourceId int64, heade
 
 	return nil
 }
+
+// raiseRhcConnectionDestroyEvents raises the events for the given removed connections. When the bulk events are
+// aggregated a single "RhcConnection.bulk_destroy" event is raised with all the connections' IDs, which avoids
+// flooding the event stream. Otherwise, a "RhcConnection.destroy" event is raised for every connection.
+func raiseRhcConnectionDestroyEvents(rhcConnections []model.RhcConnection, headers []kafka.Header) {
+	if len(rhcConnections) == 0 {
+		return
+	}
+
+	if aggregateBulkEvents {
+		ids := make([]int64, 0, len(rhcConnections))
+		for _, connection := range rhcConnections {
+			ids = append(ids, connection.ID)
+		}
+
+		err := RaiseEvent("RhcConnection.bulk_destroy", model.NewBulkDestroyEvent(ids), headers)
+		if err != nil {
+			logging.Log.Errorf(`Event "RhcConnection.bulk_destroy" could not be raised for rhcConnections %v: %s`, ids, err)
+		}
+
+		return
+	}
+
+	for _, connection := range rhcConnections {
+		err := RaiseEvent("RhcConnection.destroy", &connection, headers)
+		if err != nil {
+			logging.Log.Errorf(`Event "RhcConnection.destroy" could not be raised for rhcConnection %v: %s`, connection.ToEvent(), err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/events"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
+	"github.com/RedHatInsights/sources-api-go/model"
+)
+
+// TestRaiseRhcConnectionDestroyEvents tests that one event per connection is raised by default, and that a single
+// aggregated event with all the connections' IDs is raised when the bulk events are aggregated.
+func TestRaiseRhcConnectionDestroyEvents(t *testing.T) {
+	backupProducer := Producer
+	backupAggregate := aggregateBulkEvents
+	defer func() {
+		Producer = backupProducer
+		aggregateBulkEvents = backupAggregate
+	}()
+
+	rhcConnections := []model.RhcConnection{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	testCases := []struct {
+		aggregate     bool
+		wantHits      int
+		wantEventType string
+	}{
+		{aggregate: false, wantHits: 3, wantEventType: "RhcConnection.destroy"},
+		{aggregate: true, wantHits: 1, wantEventType: "RhcConnection.bulk_destroy"},
+	}
+
+	for _, tc := range testCases {
+		sender := mocks.MockSender{}
+		Producer = func() events.Sender { return events.EventStreamProducer{Sender: &sender} }
+		aggregateBulkEvents = tc.aggregate
+
+		raiseRhcConnectionDestroyEvents(rhcConnections, nil)
+
+		if sender.Hit != tc.wantHits {
+			t.Errorf(`[aggregate: %t] want "%d" events, got "%d"`, tc.aggregate, tc.wantHits, sender.Hit)
+		}
+
+		var eventType string
+		for _, header := range sender.Headers {
+			if header.Key == "event_type" {
+				eventType = string(header.Value)
+			}
+		}
+
+		if eventType != tc.wantEventType {
+			t.Errorf(`[aggregate: %t] want event type "%s", got "%s"`, tc.aggregate, tc.wantEventType, eventType)
+		}
+
+		if tc.aggregate && sender.Body != `{"ids":["1","2","3"]}` {
+			t.Errorf(`want the payload "{"ids":["1","2","3"]}", got "%s"`, sender.Body)
+		}
+	}
+}
+
+// TestRaiseRhcConnectionDestroyEventsEmpty tests that no events are raised when no connections were removed.
+func TestRaiseRhcConnectionDestroyEventsEmpty(t *testing.T) {
+	backupProducer := Producer
+	backupAggregate := aggregateBulkEvents
+	defer func() {
+		Producer = backupProducer
+		aggregateBulkEvents = backupAggregate
+	}()
+
+	sender := mocks.MockSender{}
+	Producer = func() events.Sender { return events.EventStreamProducer{Sender: &sender} }
+	aggregateBulkEvents = true
+
+	raiseRhcConnectionDestroyEvents(nil, nil)
+
+	if sender.Hit != 0 {
+		t.Errorf(`want no events, got "%d"`, sender.Hit)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -14,7 +15,7 @@ import (
 // loads up the application as well as the associates we need for the superkey
 // request
 func loadApplication(application *m.Application) (*m.Application, error) {
-	appDao := dao.GetApplicationDao(&application.TenantID)
+	appDao := dao.GetApplicationDao(context.Background(), &application.TenantID)
 
 	// re-pulling it from the db to make sure we have the full-version, as well
 	// as preloading any relations necessary.
@@ -30,7 +31,7 @@ func loadApplication(application *m.Application) (*m.Application, error) {
 // application type
 func getApplicationSuperkeyMetaData(application *m.Application) ([]superkey.Step, error) {
 	// fetch the metadata from the db (no tenancy required)
-	mDB := dao.GetMetaDataDao()
+	mDB := dao.GetMetaDataDao(context.Background())
 	metadata, err := mDB.GetSuperKeySteps(application.ApplicationTypeID)
 	if err != nil {
 		return nil, err
@@ -64,7 +65,7 @@ func getExtraValues(application *m.Application, provider string) (map[string]str
 	switch provider {
 	case "amazon":
 		// fetch the account number for replacing in the iam payloads
-		mDB := dao.GetMetaDataDao()
+		mDB := dao.GetMetaDataDao(context.Background())
 		acct, err := mDB.GetSuperKeyAccountNumber(application.ApplicationTypeID)
 		if err != nil {
 			return nil, err
@@ -72,7 +73,7 @@ func getExtraValues(application *m.Application, provider string) (map[string]str
 		extra["account"] = acct
 
 		// fetch the result_type for the application_type
-		atDB := dao.GetApplicationTypeDao(nil)
+		atDB := dao.GetApplicationTypeDao(context.Background(), nil)
 		authType, err := atDB.GetSuperKeyResultType(application.ApplicationTypeID, provider)
 		if err != nil {
 			return nil, err
@@ -89,7 +90,7 @@ func getExtraValues(application *m.Application, provider string) (map[string]str
 // returns the "super key" e.g. the authentication used to communicate with the
 // provider
 func getSuperKeyAuthentication(application *m.Application) (*m.Authentication, error) {
-	authDao := dao.GetAuthenticationDao(&application.TenantID)
+	authDao := dao.GetAuthenticationDao(context.Background(), &application.TenantID)
 
 	// fetch auths for this source
 	auths, _, err := authDao.ListForSource(application.SourceID, 100, 0, nil)
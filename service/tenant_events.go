@@ -0,0 +1,47 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TenantCreatedEventType is the event type of the event raised when a tenant gets onboarded.
+const TenantCreatedEventType = "tenant.created"
+
+// TenantEventPublisher implements the "dao.TenantCreatedPublisher" interface by raising the events in the event
+// stream.
+type TenantEventPublisher struct{}
+
+// tenantCreatedEvent is the payload of the "tenant.created" event.
+type tenantCreatedEvent struct {
+	Id             int64  `json:"id"`
+	ExternalTenant string `json:"external_tenant"`
+	OrgId          string `json:"org_id"`
+}
+
+// PublishTenantCreated raises the "tenant.created" event for the given tenant.
+func (t TenantEventPublisher) PublishTenantCreated(tenant *m.Tenant) error {
+	msg, err := json.Marshal(tenantCreatedEvent{Id: tenant.Id, ExternalTenant: tenant.ExternalTenant, OrgId: tenant.OrgID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %+v as event: %v", tenant, err)
+	}
+
+	// There is no request to forward the identity headers from, so they get generated from the tenant.
+	headers := []kafka.Header{
+		{Key: "event_type", Value: []byte(TenantCreatedEventType)},
+		{Key: h.XRHID, Value: []byte(util.GeneratedXRhIdentity(tenant.ExternalTenant, tenant.OrgID))},
+		{Key: h.ORGID, Value: []byte(tenant.OrgID)},
+	}
+
+	err = Producer().RaiseEvent(TenantCreatedEventType, msg, headers)
+	if err != nil {
+		return fmt.Errorf("failed to raise event to kafka: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/events"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// TestPublishTenantCreated tests that the "tenant.created" event carries the tenant and the event type header.
+func TestPublishTenantCreated(t *testing.T) {
+	originalProducer := Producer
+	defer func() { Producer = originalProducer }()
+
+	s := mocks.MockSender{}
+	Producer = func() events.Sender { return events.EventStreamProducer{Sender: &s} }
+
+	err := TenantEventPublisher{}.PublishTenantCreated(&m.Tenant{Id: 1, OrgID: "abcde"})
+	if err != nil {
+		t.Fatalf(`want no error, got "%s"`, err)
+	}
+
+	if s.Hit != 1 {
+		t.Errorf(`want one event raised, got "%d"`, s.Hit)
+	}
+
+	want := `{"id":1,"external_tenant":"","org_id":"abcde"}`
+	if s.Body != want {
+		t.Errorf(`want body "%s", got "%s"`, want, s.Body)
+	}
+
+	found := false
+	for _, header := range s.Headers {
+		if header.Key == "event_type" && string(header.Value) == TenantCreatedEventType {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Errorf(`want the "event_type" header to be "%s", got "%v"`, TenantCreatedEventType, s.Headers)
+	}
+}
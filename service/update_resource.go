@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"github.com/RedHatInsights/sources-api-go/dao"
 	l "github.com/RedHatInsights/sources-api-go/logger"
 	m "github.com/RedHatInsights/sources-api-go/model"
@@ -15,6 +16,7 @@ func UpdateSourceFromApplicationAvailabilityStatus(application *m.Application, p
 		source := &m.Source{}
 		source.ID = application.SourceID
 		source.AvailabilityStatus = application.AvailabilityStatus
+		source.AvailabilityStatusError = application.AvailabilityStatusError
 		if application.LastCheckedAt != nil && !application.LastCheckedAt.IsZero() {
 			source.LastCheckedAt = application.LastCheckedAt
 		}
@@ -23,7 +25,7 @@ func UpdateSourceFromApplicationAvailabilityStatus(application *m.Application, p
 			source.LastAvailableAt = application.LastAvailableAt
 		}
 
-		sourceDao := dao.GetSourceDao(&application.TenantID)
+		sourceDao := dao.GetSourceDao(context.Background(), &application.TenantID)
 		err := sourceDao.Update(source)
 		if err != nil {
 			l.Log.Errorf("unable to load source: %v", err.Error())
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// SourceAvailabilityTransitions returns the graph of the valid availability status transitions as an adjacency list.
+// When the "from" query parameter is given, only the part of the graph which is reachable from that status is
+// returned.
+func SourceAvailabilityTransitions(c echo.Context) error {
+	from := c.QueryParam("from")
+	if from == "" {
+		return c.JSON(http.StatusOK, m.ValidSourceTransitions)
+	}
+
+	if _, ok := m.ValidSourceTransitions[from]; !ok {
+		return util.NewErrBadRequest(fmt.Sprintf("unknown availability status %q", from))
+	}
+
+	return c.JSON(http.StatusOK, reachableTransitions(m.ValidSourceTransitions, from))
+}
+
+// reachableTransitions returns the transitions of the statuses which can be reached from the given one, itself
+// included.
+func reachableTransitions(transitions map[string][]string, from string) map[string][]string {
+	reachable := make(map[string][]string)
+
+	pending := []string{from}
+	for len(pending) > 0 {
+		status := pending[0]
+		pending = pending[1:]
+
+		if _, visited := reachable[status]; visited {
+			continue
+		}
+
+		reachable[status] = transitions[status]
+		pending = append(pending, transitions[status]...)
+	}
+
+	return reachable
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	m "github.com/RedHatInsights/sources-api-go/model"
+)
+
+// TestSourceAvailabilityTransitions tests that the whole transition graph is returned when no status is given.
+func TestSourceAvailabilityTransitions(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/meta/source_availability_transitions",
+		nil,
+		map[string]interface{}{},
+	)
+
+	err := SourceAvailabilityTransitions(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out map[string][]string
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the response: %s`, err)
+	}
+
+	if !reflect.DeepEqual(out, m.ValidSourceTransitions) {
+		t.Errorf(`want "%v", got "%v"`, m.ValidSourceTransitions, out)
+	}
+}
+
+// TestSourceAvailabilityTransitionsFrom tests that only the statuses reachable from the given one are returned.
+func TestSourceAvailabilityTransitionsFrom(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/meta/source_availability_transitions?from=unknown",
+		nil,
+		map[string]interface{}{},
+	)
+
+	err := SourceAvailabilityTransitions(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out map[string][]string
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the response: %s`, err)
+	}
+
+	if !reflect.DeepEqual(out[m.Unknown], m.ValidSourceTransitions[m.Unknown]) {
+		t.Errorf(`want the transitions from "unknown" to be "%v", got "%v"`, m.ValidSourceTransitions[m.Unknown], out[m.Unknown])
+	}
+}
+
+// TestSourceAvailabilityTransitionsBadRequest tests that an unknown status is rejected.
+func TestSourceAvailabilityTransitionsBadRequest(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/meta/source_availability_transitions?from=bogus",
+		nil,
+		map[string]interface{}{},
+	)
+
+	badRequestSourceAvailabilityTransitions := ErrorHandlingContext(SourceAvailabilityTransitions)
+	err := badRequestSourceAvailabilityTransitions(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+// TestReachableTransitions tests that only the part of the graph reachable from the given status is returned.
+func TestReachableTransitions(t *testing.T) {
+	transitions := map[string][]string{
+		"a": {"b"},
+		"b": {"c"},
+		"c": {"b"},
+		"d": {"a"},
+	}
+
+	want := map[string][]string{
+		"b": {"c"},
+		"c": {"b"},
+	}
+
+	got := reachableTransitions(transitions, "b")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf(`want "%v", got "%v"`, want, got)
+	}
+}
@@ -1,9 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/marketplace"
@@ -23,7 +32,9 @@ func getSourceDaoWithTenant(c echo.Context) (dao.SourceDao, error) {
 		return nil, err
 	}
 
-	return dao.GetSourceDao(&tenantId), nil
+	sourceDao := dao.GetSourceDao(c.Request().Context(), &tenantId)
+
+	return sourceDao, nil
 }
 
 func SourceList(c echo.Context) error {
@@ -66,7 +77,47 @@ func SourceList(c echo.Context) error {
 		out[i] = sources[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	collection := util.CollectionResponse(out, c.Request(), int(count), limit, offset)
+
+	warning, err := sourceTypeNamesWarning(c, filters)
+	if err != nil {
+		return err
+	}
+
+	if warning != "" {
+		collection.Meta.Warnings = append(collection.Meta.Warnings, warning)
+	}
+
+	return c.JSON(http.StatusOK, collection)
+}
+
+// sourceTypeNamesWarning returns a warning when none of the names of the "source_type_name" filter belong to a source
+// type, so that the clients can tell an invalid source type name apart from a source type without sources.
+func sourceTypeNamesWarning(c echo.Context, filters []util.Filter) (string, error) {
+	names, _, err := dao.ExtractSourceTypeNameFilter(filters)
+	if err != nil {
+		return "", util.NewErrBadRequest(err)
+	}
+
+	if len(names) == 0 {
+		return "", nil
+	}
+
+	sourceTypeDao, err := getSourceTypeDao(c)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := sourceTypeDao.ExistingNames(names)
+	if err != nil {
+		return "", err
+	}
+
+	if len(existing) != 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("No source types found for names: [%s]", strings.Join(names, ", ")), nil
 }
 
 func SourceGet(c echo.Context) error {
@@ -82,6 +133,17 @@ func SourceGet(c echo.Context) error {
 
 	c.Logger().Infof("Getting Source Id %v", id)
 
+	// The source's endpoints, applications and rhc connections can be requested along with it, to avoid having to
+	// fetch them separately.
+	if c.QueryParam("include_related") == "true" {
+		sourceWithRelated, err := sourcesDB.GetWithRelated(id, *sourcesDB.Tenant())
+		if err != nil {
+			return err
+		}
+
+		return c.JSON(http.StatusOK, sourceWithRelated.ToResponse())
+	}
+
 	s, err := sourcesDB.GetById(&id)
 
 	if err != nil {
@@ -91,6 +153,26 @@ func SourceGet(c echo.Context) error {
 	return c.JSON(http.StatusOK, s.ToResponse())
 }
 
+// SourceGetByExternalId gets the source which has the given external ID, which third party systems use to correlate
+// the sources with their own records.
+func SourceGetByExternalId(c echo.Context) error {
+	sourcesDB, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	externalId := c.Param("external_id")
+
+	c.Logger().Infof("Getting Source with external id %v", externalId)
+
+	s, err := sourcesDB.GetByExternalId(externalId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, s.ToResponse())
+}
+
 func SourceCreate(c echo.Context) error {
 	sourcesDB, err := getSourceDao(c)
 	if err != nil {
@@ -115,6 +197,9 @@ func SourceCreate(c echo.Context) error {
 		SourceRef:           input.SourceRef,
 		AppCreationWorkflow: input.AppCreationWorkflow,
 		AvailabilityStatus:  input.AvailabilityStatus,
+		ExternalId:          input.ExternalId,
+		CostCenter:          input.CostCenter,
+		BudgetCode:          input.BudgetCode,
 		SourceTypeID:        *input.SourceTypeID,
 	}
 
@@ -146,6 +231,9 @@ func SourceEdit(c echo.Context) error {
 	// Store the previous status before updating the source.
 	previousStatus := s.AvailabilityStatus
 
+	// The fields which were explicitly set to null in the request.
+	var nullFields []string
+
 	// If "PausedAt" contains a date it means that the source was paused back then.
 	if s.PausedAt != nil {
 		input := &m.SourcePausedEditRequest{}
@@ -158,11 +246,21 @@ func SourceEdit(c echo.Context) error {
 			return util.NewErrBadRequest(err)
 		}
 	} else {
+		nullFields, err = explicitlyNullFields(c)
+		if err != nil {
+			return err
+		}
+
 		input := &m.SourceEditRequest{}
 		if err := c.Bind(input); err != nil {
 			return err
 		}
 
+		err := service.ValidateSourceEditRequest(input)
+		if err != nil {
+			return util.NewErrBadRequest(fmt.Sprintf("Validation failed: %s", err.Error()))
+		}
+
 		s.UpdateFromRequest(input)
 	}
 
@@ -171,6 +269,16 @@ func SourceEdit(c echo.Context) error {
 		return err
 	}
 
+	// "Update" skips the nil fields, so the ones the client wants to clear have to be set to NULL separately.
+	if len(nullFields) > 0 {
+		err = sourcesDB.ClearFields(c.Request().Context(), s.ID, s.TenantID, nullFields)
+		if err != nil {
+			return err
+		}
+
+		s.ClearFields(nullFields)
+	}
+
 	setNotificationForAvailabilityStatus(c, previousStatus, s)
 	setEventStreamResource(c, s)
 	return c.JSON(http.StatusOK, s.ToResponse())
@@ -344,9 +452,19 @@ func SourceCheckAvailability(c echo.Context) error {
 		return util.NewErrNotFound("source")
 	}
 
+	// The request's context gets cancelled as soon as the response is sent, so the goroutine's lookup gets its own DAO
+	// which is bound to a detached context instead.
+	request := c.Request()
+	c.SetRequest(request.WithContext(context.Background()))
+	detachedSourceDao, err := getSourceDao(c)
+	c.SetRequest(request)
+	if err != nil {
+		return err
+	}
+
 	// do it async!
 	go func() {
-		src, err := sourceDao.GetByIdWithPreload(&sourceID,
+		src, err := detachedSourceDao.GetByIdWithPreload(&sourceID,
 			"SourceType",
 			"Applications",
 			"Applications.ApplicationType",
@@ -372,6 +490,98 @@ func SourceCheckAvailability(c echo.Context) error {
 	return c.JSON(http.StatusAccepted, map[string]interface{}{})
 }
 
+// sourceAvailabilityStreamHeartbeat is how often a comment is sent to the streaming clients, so that the proxies in
+// between don't close the idle streams.
+var sourceAvailabilityStreamHeartbeat = 15 * time.Second
+
+// SourceAvailabilityStream streams the availability status changes of the given source as server sent events, until
+// the client closes the connection.
+func SourceAvailabilityStream(c echo.Context) error {
+	sourceDao, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	sourceID, err := strconv.ParseInt(c.Param("source_id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	exists, err := sourceDao.Exists(sourceID)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return util.NewErrNotFound("source")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+	c.Response().Header().Set("Cache-Control", "no-cache")
+	c.Response().Header().Set("Connection", "keep-alive")
+	c.Response().WriteHeader(http.StatusOK)
+	c.Response().Flush()
+
+	// The heartbeats are sent from their own goroutine while the listener blocks, so the writes to the response are
+	// serialized. Once the heartbeats cannot be written anymore the client is gone, and the listener is stopped.
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	var writeMutex sync.Mutex
+	var heartbeats sync.WaitGroup
+
+	heartbeats.Add(1)
+	defer heartbeats.Wait()
+	defer cancel()
+
+	go func() {
+		defer heartbeats.Done()
+
+		heartbeat := time.NewTicker(sourceAvailabilityStreamHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-heartbeat.C:
+				writeMutex.Lock()
+				_, err := fmt.Fprint(c.Response(), ": heartbeat\n\n")
+				if err == nil {
+					c.Response().Flush()
+				}
+				writeMutex.Unlock()
+
+				if err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err = sourceDao.ListenAvailabilityChanges(ctx, sourceID, func(notification m.SourceAvailabilityNotification) error {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+
+		return writeServerSentEvent(c, "availability_status", notification)
+	})
+	if err != nil {
+		c.Logger().Warnf("stopped streaming the availability changes of source %d: %s", sourceID, err)
+
+		// The headers have already been sent, so the failure can only be told to the client with an event before the
+		// stream gets closed.
+		detail := "the availability changes cannot be streamed right now, please retry later"
+		if errors.Is(err, dao.ErrListenerFellBehind) {
+			detail = "the client fell behind the availability changes, please reconnect"
+		}
+
+		writeMutex.Lock()
+		_ = writeServerSentEvent(c, "error", util.ErrorDoc(detail, "503"))
+		writeMutex.Unlock()
+	}
+
+	return nil
+}
+
 // SourcesRhcConnectionList returns all the connections related to a source.
 func SourcesRhcConnectionList(c echo.Context) error {
 	paramId := c.Param("source_id")
@@ -421,6 +631,63 @@ func SourcesRhcConnectionList(c echo.Context) error {
 	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
 }
 
+// SourceDependencies returns everything that depends on the given source, so that the impact of deleting it can be
+// assessed beforehand.
+func SourceDependencies(c echo.Context) error {
+	sourceId, err := strconv.ParseInt(c.Param("source_id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	tenantId, err := getTenantFromEchoContext(c)
+	if err != nil {
+		return err
+	}
+
+	sourceDao, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	graph, err := sourceDao.GetDependencyGraph(sourceId, tenantId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, graph)
+}
+
+// SourceSLAReport returns how long the given source was available between the "from" and "to" dates, which must be
+// given in the RFC3339 format.
+func SourceSLAReport(c echo.Context) error {
+	sourceId, err := strconv.ParseInt(c.Param("source_id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	from, err := time.Parse(time.RFC3339, c.QueryParam("from"))
+	if err != nil {
+		return util.NewErrBadRequest(fmt.Sprintf(`invalid "from" date: %s`, err))
+	}
+
+	to, err := time.Parse(time.RFC3339, c.QueryParam("to"))
+	if err != nil {
+		return util.NewErrBadRequest(fmt.Sprintf(`invalid "to" date: %s`, err))
+	}
+
+	sourceDao, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	report, err := sourceDao.SLAReport(sourceId, from, to)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
 // SourcePause pauses a source and all its dependant applications, by setting the former's and the latter's "paused_at"
 // columns to "now()".
 func SourcePause(c echo.Context) error {
@@ -512,3 +779,55 @@ func SourceUnpause(c echo.Context) error {
 
 	return c.JSON(http.StatusNoContent, nil)
 }
+
+// explicitlyNullFields returns the nullable fields which are explicitly set to null in the request body, and leaves
+// the body untouched so that it can still be bound afterwards. The rest of the null fields are ignored, as they
+// always have been.
+func explicitlyNullFields(c echo.Context) ([]string, error) {
+	if c.Request().Body == nil || c.Request().Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+	c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+	// Malformed bodies are reported by the binder.
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, nil
+	}
+
+	nullFields := make([]string, 0)
+	for key, value := range fields {
+		if string(bytes.TrimSpace(value)) == "null" && dao.IsClearableSourceField(key) {
+			nullFields = append(nullFields, key)
+		}
+	}
+	sort.Strings(nullFields)
+
+	return nullFields, nil
+}
+
+// SourceHealthSummary returns how many of the tenant's sources are on each availability status, along with the
+// sources which were checked the longest ago.
+func SourceHealthSummary(c echo.Context) error {
+	tenantId, err := getTenantFromEchoContext(c)
+	if err != nil {
+		return err
+	}
+
+	sourceDao, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	summary, err := sourceDao.HealthSummary(tenantId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, summary)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -38,7 +39,7 @@ func TestSourceListAuthentications(t *testing.T) {
 	// If we're running integration tests without Vault...
 	if parser.RunningIntegrationTests && !config.IsVaultOn() {
 		// Create one authentication for the database tests, to make sure that we at least have one we can fetch.
-		authsDao := dao.GetAuthenticationDao(&tenantId)
+		authsDao := dao.GetAuthenticationDao(context.Background(), &tenantId)
 		err := authsDao.Create(&fixtures.TestAuthenticationData[0])
 		if err != nil {
 			t.Errorf(`could not create the authentication fixture for the test`)
@@ -573,6 +574,93 @@ func TestSourceList(t *testing.T) {
 	AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
 }
 
+// TestSourceListSourceTypeNames tests that the sources can be filtered by the names of their source types, ignoring
+// the case, and that no warnings are returned when the names exist.
+func TestSourceListSourceTypeNames(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources?filter[source_type_name][in]=AMAZON,unknown",
+		nil,
+		map[string]interface{}{
+			"limit":  100,
+			"offset": 0,
+			"filters": []util.Filter{
+				{Name: "source_type_name", Operation: "in", Value: []string{"AMAZON,unknown"}},
+			},
+			"tenantID": int64(1),
+		})
+
+	err := SourceList(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out util.Collection
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	if len(out.Meta.Warnings) != 0 {
+		t.Errorf(`want no warnings, got "%v"`, out.Meta.Warnings)
+	}
+
+	// The mocked DAO doesn't filter the sources.
+	if parser.RunningIntegrationTests {
+		for _, rawSource := range out.Data {
+			source, ok := rawSource.(map[string]interface{})
+			if !ok {
+				t.Fatalf("model did not deserialize as a source")
+			}
+
+			if source["source_type_id"] != strconv.FormatInt(fixtures.TestSourceTypeData[0].Id, 10) {
+				t.Errorf(`want only the "amazon" sources, got "%v"`, source)
+			}
+		}
+	}
+}
+
+// TestSourceListSourceTypeNamesWarning tests that a warning is returned when none of the given source type names
+// exist.
+func TestSourceListSourceTypeNamesWarning(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources?filter[source_type_name][in]=foo,bar",
+		nil,
+		map[string]interface{}{
+			"limit":  100,
+			"offset": 0,
+			"filters": []util.Filter{
+				{Name: "source_type_name", Operation: "in", Value: []string{"foo,bar"}},
+			},
+			"tenantID": int64(1),
+		})
+
+	err := SourceList(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out util.Collection
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	want := []string{"No source types found for names: [foo, bar]"}
+	if !cmp.Equal(want, out.Meta.Warnings) {
+		t.Errorf(`want warnings "%v", got "%v"`, want, out.Meta.Warnings)
+	}
+}
+
 func TestSourceListSatellite(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 
@@ -676,6 +764,69 @@ func TestSourceGet(t *testing.T) {
 	}
 }
 
+// TestSourceGetIncludeRelated tests that the source's related resources are included in the response when requested.
+func TestSourceGetIncludeRelated(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/1?include_related=true",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := SourceGet(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out m.SourceWithRelatedResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the response: %s`, err)
+	}
+
+	if out.SourceResponse == nil || *out.Name != "Source1" {
+		t.Errorf(`want source "Source1", got "%+v"`, out.SourceResponse)
+	}
+
+	var wantEndpoints, wantApplications, wantRhcConnections int
+	for _, endpoint := range fixtures.TestEndpointData {
+		if endpoint.SourceID == 1 {
+			wantEndpoints++
+		}
+	}
+
+	for _, app := range fixtures.TestApplicationData {
+		if app.SourceID == 1 {
+			wantApplications++
+		}
+	}
+
+	for _, link := range fixtures.TestSourceRhcConnectionData {
+		if link.SourceId == 1 {
+			wantRhcConnections++
+		}
+	}
+
+	if len(out.Endpoints) != wantEndpoints || len(out.Applications) != wantApplications || len(out.RhcConnections) != wantRhcConnections {
+		t.Errorf(`want "%d" endpoints, "%d" applications and "%d" connections, got "%d", "%d" and "%d"`, wantEndpoints, wantApplications, wantRhcConnections, len(out.Endpoints), len(out.Applications), len(out.RhcConnections))
+	}
+
+	for _, app := range out.Applications {
+		if app.ApplicationType == nil || app.ApplicationType.Id != app.ApplicationTypeID {
+			t.Errorf(`want the application type "%s" to be included, got "%+v"`, app.ApplicationTypeID, app.ApplicationType)
+		}
+	}
+}
+
 func TestSourceGetNotFound(t *testing.T) {
 	c, rec := request.CreateTestContext(
 		http.MethodGet,
@@ -698,6 +849,28 @@ func TestSourceGetNotFound(t *testing.T) {
 	templates.NotFoundTest(t, rec)
 }
 
+func TestSourceGetByExternalIdNotFound(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/by_external_id/not-existing-external-id",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("external_id")
+	c.SetParamValues("not-existing-external-id")
+
+	notFoundSourceGetByExternalId := ErrorHandlingContext(SourceGetByExternalId)
+	err := notFoundSourceGetByExternalId(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
 func TestSourceGetBadRequest(t *testing.T) {
 	c, rec := request.CreateTestContext(
 		http.MethodGet,
@@ -949,6 +1122,115 @@ func TestSourceEditBadRequest(t *testing.T) {
 	templates.BadRequestTest(t, rec)
 }
 
+// TestSourceEditClearFields tests that the nullable fields explicitly set to null in the request get cleared, and
+// that the rest of the null fields are ignored.
+func TestSourceEditClearFields(t *testing.T) {
+	body := `{"name": null, "version": null, "source_ref": "new-source-ref"}`
+
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		"/api/sources/v3.1/sources/1",
+		strings.NewReader(body),
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+	err := SourceEdit(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Wrong return code, expected %v got %v", http.StatusOK, rec.Code)
+	}
+
+	src := m.SourceResponse{}
+	err = json.Unmarshal(rec.Body.Bytes(), &src)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal source from response: %v", err)
+	}
+
+	if *src.Name != fixtures.TestSourceData[0].Name {
+		t.Errorf("Unexpected source name: expected '%s', got '%s'", fixtures.TestSourceData[0].Name, *src.Name)
+	}
+
+	if src.Version != nil {
+		t.Errorf("Expected the version to be cleared, got '%s'", *src.Version)
+	}
+
+	if src.SourceRef == nil || *src.SourceRef != "new-source-ref" {
+		t.Errorf("Unexpected source ref: expected '%s', got '%v'", "new-source-ref", src.SourceRef)
+	}
+}
+
+// TestSourceEditInvalidExternalId tests that a bad request is returned when the external ID has an invalid format.
+func TestSourceEditInvalidExternalId(t *testing.T) {
+	req := m.SourceEditRequest{
+		ExternalId: util.StringRef("invalid external id!"),
+	}
+
+	body, _ := json.Marshal(req)
+
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		"/api/sources/v3.1/sources/1",
+		bytes.NewReader(body),
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+	badRequestSourceEdit := ErrorHandlingContext(SourceEdit)
+	err := badRequestSourceEdit(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+// TestSourceEditInvalidCostCenter tests that a 422 is returned when the cost center doesn't match the configured
+// pattern.
+func TestSourceEditInvalidCostCenter(t *testing.T) {
+	req := m.SourceEditRequest{
+		CostCenter: util.StringRef("invalid cost center!"),
+	}
+
+	body, _ := json.Marshal(req)
+
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		"/api/sources/v3.1/sources/1",
+		bytes.NewReader(body),
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+	unprocessableSourceEdit := ErrorHandlingContext(SourceEdit)
+	err := unprocessableSourceEdit(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusUnprocessableEntity, rec.Code)
+	}
+}
+
 func TestSourceDelete(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 
@@ -1107,6 +1389,216 @@ func TestAvailabilityStatusCheckBadRequest(t *testing.T) {
 	templates.BadRequestTest(t, rec)
 }
 
+// TestSourceAvailabilityStream tests that the stream endpoint sends the event stream headers and returns once the
+// client goes away.
+func TestSourceAvailabilityStream(t *testing.T) {
+	// The real DAO would try to open a "LISTEN" connection against the database.
+	if parser.RunningIntegrationTests {
+		t.Skip("Skipping test")
+	}
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/1/availability_stream",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("1")
+
+	// Simulate a client that has already disconnected so that the handler returns.
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	cancel()
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	err := SourceAvailabilityStream(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Wrong code, got %v, expected %v", rec.Code, http.StatusOK)
+	}
+
+	if contentType := rec.Header().Get(echo.HeaderContentType); contentType != "text/event-stream" {
+		t.Errorf(`want "text/event-stream" content type, got "%s"`, contentType)
+	}
+}
+
+// failingListenerSourceDao is a source DAO whose availability changes listener always fails.
+type failingListenerSourceDao struct {
+	dao.MockSourceDao
+	err error
+}
+
+func (f *failingListenerSourceDao) ListenAvailabilityChanges(_ context.Context, _ int64, _ func(notification m.SourceAvailabilityNotification) error) error {
+	return f.err
+}
+
+// TestSourceAvailabilityStreamListenerFailure tests that when the listener fails, the client gets an error event
+// before the stream gets closed.
+func TestSourceAvailabilityStreamListenerFailure(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/1/availability_stream",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("1")
+
+	backupGetSourceDao := getSourceDao
+	getSourceDao = func(c echo.Context) (dao.SourceDao, error) {
+		return &failingListenerSourceDao{MockSourceDao: dao.MockSourceDao{Sources: fixtures.TestSourceData}, err: dao.ErrTooManyListeners}, nil
+	}
+	defer func() { getSourceDao = backupGetSourceDao }()
+
+	err := SourceAvailabilityStream(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Wrong code, got %v, expected %v", rec.Code, http.StatusOK)
+	}
+
+	if !strings.HasPrefix(rec.Body.String(), "event: error\ndata: ") {
+		t.Errorf(`want an error event, got "%s"`, rec.Body.String())
+	}
+
+	if !strings.Contains(rec.Body.String(), `"status":"503"`) {
+		t.Errorf(`want a "503" error, got "%s"`, rec.Body.String())
+	}
+}
+
+func TestSourceAvailabilityStreamNotFound(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/183209745/availability_stream",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("183209745")
+
+	notFoundSourceAvailabilityStream := ErrorHandlingContext(SourceAvailabilityStream)
+	err := notFoundSourceAvailabilityStream(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+func TestSourceAvailabilityStreamBadRequest(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/xxx/availability_stream",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("xxx")
+
+	badRequestSourceAvailabilityStream := ErrorHandlingContext(SourceAvailabilityStream)
+	err := badRequestSourceAvailabilityStream(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+// failingExistsSourceDao is a source DAO which cannot check whether the sources exist.
+type failingExistsSourceDao struct {
+	dao.MockSourceDao
+	err error
+}
+
+func (f *failingExistsSourceDao) Exists(_ int64) (bool, error) {
+	return false, f.err
+}
+
+// TestSourceAvailabilityStreamExistsFailure tests that the failures when checking whether the source exists are
+// returned as they are, instead of being reported as a missing source.
+func TestSourceAvailabilityStreamExistsFailure(t *testing.T) {
+	c, _ := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/1/availability_stream",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("1")
+
+	dbErr := errors.New("connection refused")
+
+	backupGetSourceDao := getSourceDao
+	getSourceDao = func(c echo.Context) (dao.SourceDao, error) {
+		return &failingExistsSourceDao{err: dbErr}, nil
+	}
+	defer func() { getSourceDao = backupGetSourceDao }()
+
+	err := SourceAvailabilityStream(c)
+	if !errors.Is(err, dbErr) {
+		t.Errorf(`want "%s", got "%v"`, dbErr, err)
+	}
+}
+
+// TestSourceAvailabilityStreamHeartbeat tests that the heartbeats are sent while there are no availability changes.
+func TestSourceAvailabilityStreamHeartbeat(t *testing.T) {
+	backupHeartbeat := sourceAvailabilityStreamHeartbeat
+	defer func() { sourceAvailabilityStreamHeartbeat = backupHeartbeat }()
+
+	sourceAvailabilityStreamHeartbeat = 10 * time.Millisecond
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/1/availability_stream",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("1")
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	done := make(chan error)
+	go func() { done <- SourceAvailabilityStream(c) }()
+
+	// Give the handler some time to send a few heartbeats.
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	err := <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(rec.Body.String(), ": heartbeat\n\n") {
+		t.Errorf(`want heartbeats sent, got "%s"`, rec.Body.String())
+	}
+}
+
 func TestSourcesGetRelatedRhcConnectionsTest(t *testing.T) {
 	sourceId := "1"
 
@@ -1652,3 +2144,219 @@ func TestSourceEditPausedUnit(t *testing.T) {
 	// Restore the binder to not affect any other tests.
 	c.Echo().Binder = backupBinder
 }
+
+func TestSourceDependencies(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/1/dependencies",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("1")
+
+	err := SourceDependencies(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var graph m.SourceDependencyGraph
+	err = json.Unmarshal(rec.Body.Bytes(), &graph)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	if graph.ID != fixtures.TestSourceData[0].ID || graph.Name != fixtures.TestSourceData[0].Name {
+		t.Errorf(`want source "%d" named "%s", got "%d" named "%s"`, fixtures.TestSourceData[0].ID, fixtures.TestSourceData[0].Name, graph.ID, graph.Name)
+	}
+}
+
+func TestSourceDependenciesNotFound(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/9843762095/dependencies",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("9843762095")
+
+	notFoundSourceDependencies := ErrorHandlingContext(SourceDependencies)
+	err := notFoundSourceDependencies(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+func TestSourceDependenciesBadRequest(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/xxx/dependencies",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("xxx")
+
+	badRequestSourceDependencies := ErrorHandlingContext(SourceDependencies)
+	err := badRequestSourceDependencies(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+func TestSourceSLAReport(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/1/sla?from=2022-04-01T00:00:00Z&to=2022-05-01T00:00:00Z",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("1")
+
+	err := SourceSLAReport(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var report m.SourceSLAReport
+	err = json.Unmarshal(rec.Body.Bytes(), &report)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	if report.SourceId != fixtures.TestSourceData[0].ID {
+		t.Errorf(`want the report of source "%d", got "%d"`, fixtures.TestSourceData[0].ID, report.SourceId)
+	}
+
+	wantFrom := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC)
+	if !report.From.Equal(wantFrom) {
+		t.Errorf(`want the report to start at "%s", got "%s"`, wantFrom, report.From)
+	}
+}
+
+func TestSourceSLAReportNotFound(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/9843762095/sla?from=2022-04-01T00:00:00Z&to=2022-05-01T00:00:00Z",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("source_id")
+	c.SetParamValues("9843762095")
+
+	notFoundSourceSLAReport := ErrorHandlingContext(SourceSLAReport)
+	err := notFoundSourceSLAReport(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+func TestSourceSLAReportBadRequest(t *testing.T) {
+	urls := []string{
+		"/api/sources/v3.1/sources/1/sla?from=yesterday&to=2022-05-01T00:00:00Z",
+		"/api/sources/v3.1/sources/1/sla?from=2022-04-01T00:00:00Z",
+		"/api/sources/v3.1/sources/1/sla?from=2022-05-01T00:00:00Z&to=2022-04-01T00:00:00Z",
+	}
+
+	for _, url := range urls {
+		c, rec := request.CreateTestContext(
+			http.MethodGet,
+			url,
+			nil,
+			map[string]interface{}{
+				"tenantID": int64(1),
+			},
+		)
+
+		c.SetParamNames("source_id")
+		c.SetParamValues("1")
+
+		badRequestSourceSLAReport := ErrorHandlingContext(SourceSLAReport)
+		err := badRequestSourceSLAReport(c)
+		if err != nil {
+			t.Error(err)
+		}
+
+		templates.BadRequestTest(t, rec)
+	}
+}
+
+// TestSourceHealthSummary tests that the health summary of the tenant's sources is returned.
+func TestSourceHealthSummary(t *testing.T) {
+	tenantId := int64(1)
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/health_summary",
+		nil,
+		map[string]interface{}{
+			"tenantID": tenantId,
+		},
+	)
+
+	err := SourceHealthSummary(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var summary m.SourceHealthSummary
+	err = json.Unmarshal(rec.Body.Bytes(), &summary)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	var wantTotal int64
+	for _, source := range fixtures.TestSourceData {
+		if source.TenantID == tenantId {
+			wantTotal++
+		}
+	}
+
+	if summary.Total != wantTotal {
+		t.Errorf(`want a total of "%d" sources, got "%d"`, wantTotal, summary.Total)
+	}
+
+	var got int64
+	for _, count := range summary.StatusCounts {
+		got += count
+	}
+
+	if summary.Total != got {
+		t.Errorf(`the total doesn't match the sum of the statuses. Want "%d", got "%d"`, summary.Total, got)
+	}
+}
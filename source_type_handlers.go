@@ -1,21 +1,78 @@
 package main
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 )
 
+// sourceTypeListCacheTTL is the amount of time the source type list responses are cached for, both by us and by the
+// clients.
+const sourceTypeListCacheTTL = 5 * time.Minute
+
+// sourceTypeListCacheSize is the maximum number of source type list responses which are cached. The keys come from the
+// query strings, so the cache must be bounded for the clients not to be able to grow it at will.
+const sourceTypeListCacheSize = 128
+
+// sourceTypeListCache holds the source type list responses, keyed by the request and its list parameters. The source
+// types barely change, so there is no point in hitting the database for every request.
+var sourceTypeListCache = util.NewLruCache(sourceTypeListCacheSize, sourceTypeListCacheTTL)
+
+// invalidateSourceTypeListCache removes all the cached source type list responses.
+func invalidateSourceTypeListCache() {
+	sourceTypeListCache.Flush()
+}
+
+// sourceTypeCapabilitiesCacheTTL is the amount of time the capabilities of a source type are cached for.
+const sourceTypeCapabilitiesCacheTTL = 5 * time.Minute
+
+// cachedSourceTypeCapabilities are the cached capabilities of a source type along with their expiration time.
+type cachedSourceTypeCapabilities struct {
+	capabilities m.SourceTypeCapabilities
+	expiresAt    time.Time
+}
+
+// sourceTypeCapabilitiesCache holds the source types' capabilities, keyed by the source type ID. The clients check
+// them to decide which flows to enable, so they get requested often but barely change.
+var sourceTypeCapabilitiesCache = struct {
+	sync.Mutex
+	entries map[int64]cachedSourceTypeCapabilities
+}{entries: make(map[int64]cachedSourceTypeCapabilities)}
+
+// invalidateSourceTypeCapabilitiesCache removes all the cached source type capabilities.
+func invalidateSourceTypeCapabilitiesCache() {
+	sourceTypeCapabilitiesCache.Lock()
+	defer sourceTypeCapabilitiesCache.Unlock()
+
+	sourceTypeCapabilitiesCache.entries = make(map[int64]cachedSourceTypeCapabilities)
+}
+
 // function that defines how we get the dao - default implementation below.
 var getSourceTypeDao func(c echo.Context) (dao.SourceTypeDao, error)
 
-func getSourceTypeDaoWithoutTenant(_ echo.Context) (dao.SourceTypeDao, error) {
+func getSourceTypeDaoWithoutTenant(c echo.Context) (dao.SourceTypeDao, error) {
 	// we do not need tenancy for source type.
+	sourceTypeDao := dao.GetSourceTypeDao(c.Request().Context())
+
+	return sourceTypeDao, nil
+}
+
+// function that defines how we get the dao - default implementation below.
+var getSourceTypeFlagDao func(c echo.Context) (dao.SourceTypeFlagDao, error)
+
+func getSourceTypeFlagDaoWithoutTenant(c echo.Context) (dao.SourceTypeFlagDao, error) {
+	// the capabilities belong to the source types, which don't need tenancy.
+	sourceTypeFlagDao := dao.GetSourceTypeFlagDao(c.Request().Context())
 
-	return dao.GetSourceTypeDao(), nil
+	return sourceTypeFlagDao, nil
 }
 
 func SourceTypeList(c echo.Context) error {
@@ -35,6 +92,14 @@ func SourceTypeList(c echo.Context) error {
 		return err
 	}
 
+	c.Response().Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(sourceTypeListCacheTTL.Seconds())))
+
+	cacheKey := fmt.Sprintf("%s|%d|%d|%v", c.Request().RequestURI, limit, offset, filters)
+
+	if cached, ok := sourceTypeListCache.Get(cacheKey); ok {
+		return c.JSON(http.StatusOK, cached)
+	}
+
 	sourceTypes, count, err := sourceTypeDB.List(limit, offset, filters)
 	if err != nil {
 		return err
@@ -47,7 +112,11 @@ func SourceTypeList(c echo.Context) error {
 		out[i] = sourceTypes[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	response := util.CollectionResponse(out, c.Request(), int(count), limit, offset)
+
+	sourceTypeListCache.Set(cacheKey, response)
+
+	return c.JSON(http.StatusOK, response)
 }
 
 func SourceTypeGet(c echo.Context) error {
@@ -67,5 +136,123 @@ func SourceTypeGet(c echo.Context) error {
 		return err
 	}
 
+	appTypes, err := SourceTypeDB.ListCompatibleApplicationTypes(sourceType.Id)
+	if err != nil {
+		return err
+	}
+
+	includes := make([]m.CompatibleTypeResponse, len(appTypes))
+	for i := range appTypes {
+		includes[i] = appTypes[i].ToCompatibleTypeResponse()
+	}
+
+	return c.JSON(http.StatusOK, m.SourceTypeWithIncludesResponse{SourceTypeResponse: sourceType.ToResponse(), Includes: includes})
+}
+
+func SourceTypeEdit(c echo.Context) error {
+	sourceTypeDB, err := getSourceTypeDao(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	updateRequest := &m.SourceTypeEditRequest{}
+	err = c.Bind(updateRequest)
+	if err != nil {
+		return err
+	}
+
+	err = service.ValidateSourceTypeEditRequest(updateRequest)
+	if err != nil {
+		return util.NewErrBadRequest(fmt.Sprintf("Validation failed: %s", err))
+	}
+
+	sourceType, err := sourceTypeDB.GetById(&id)
+	if err != nil {
+		return err
+	}
+
+	sourceType.UpdateFromRequest(updateRequest)
+	err = sourceTypeDB.Update(sourceType)
+	if err != nil {
+		return err
+	}
+
+	// the cached lists would otherwise keep serving the old values until they expire.
+	invalidateSourceTypeListCache()
+
 	return c.JSON(http.StatusOK, sourceType.ToResponse())
 }
+
+// SourceTypeCapabilities returns the capabilities the source type supports, as a flat object of capability names and
+// whether they are supported.
+func SourceTypeCapabilities(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	sourceTypeCapabilitiesCache.Lock()
+	cached, ok := sourceTypeCapabilitiesCache.entries[id]
+	sourceTypeCapabilitiesCache.Unlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return c.JSON(http.StatusOK, cached.capabilities)
+	}
+
+	sourceTypeFlagDao, err := getSourceTypeFlagDao(c)
+	if err != nil {
+		return err
+	}
+
+	flags, err := sourceTypeFlagDao.GetAll(id)
+	if err != nil {
+		return err
+	}
+
+	capabilities := m.ToSourceTypeCapabilities(flags)
+
+	sourceTypeCapabilitiesCache.Lock()
+	sourceTypeCapabilitiesCache.entries[id] = cachedSourceTypeCapabilities{capabilities: capabilities, expiresAt: time.Now().Add(sourceTypeCapabilitiesCacheTTL)}
+	sourceTypeCapabilitiesCache.Unlock()
+
+	return c.JSON(http.StatusOK, capabilities)
+}
+
+// SourceTypeCapabilitiesSet sets multiple capabilities of the source type at once, and returns all its capabilities.
+func SourceTypeCapabilitiesSet(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	input := m.SourceTypeCapabilities{}
+	err = c.Bind(&input)
+	if err != nil {
+		return err
+	}
+
+	sourceTypeFlagDao, err := getSourceTypeFlagDao(c)
+	if err != nil {
+		return err
+	}
+
+	err = sourceTypeFlagDao.BulkSet(id, input)
+	if err != nil {
+		return err
+	}
+
+	// the cached capabilities would otherwise keep serving the old values until they expire.
+	invalidateSourceTypeCapabilitiesCache()
+
+	flags, err := sourceTypeFlagDao.GetAll(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, m.ToSourceTypeCapabilities(flags))
+}
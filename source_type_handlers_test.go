@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"net/http"
+	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
@@ -167,3 +170,380 @@ func TestSourceTypeGetBadRequest(t *testing.T) {
 
 	templates.BadRequestTest(t, rec)
 }
+
+// TestSourceTypeListCacheControl tests that the source type list responses are marked as cacheable.
+func TestSourceTypeListCacheControl(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/source_types",
+		nil,
+		map[string]interface{}{
+			"limit":   100,
+			"offset":  0,
+			"filters": []util.Filter{},
+		},
+	)
+
+	err := SourceTypeList(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	want := "public, max-age=300"
+	if got := rec.Header().Get("Cache-Control"); got != want {
+		t.Errorf(`want Cache-Control "%s", got "%s"`, want, got)
+	}
+}
+
+func TestSourceTypeEdit(t *testing.T) {
+	req := m.SourceTypeEditRequest{
+		IconUrl:     util.StringRef("https://example.com/icons/amazon.svg"),
+		DisplayName: util.StringRef("Amazon Web Services"),
+	}
+
+	body, _ := json.Marshal(req)
+
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		"/api/sources/v3.1/source_types/1",
+		bytes.NewReader(body),
+		map[string]interface{}{},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+	// Cache a list response so that we can check that it gets invalidated.
+	sourceTypeListCache.Set("test", nil)
+
+	err := SourceTypeEdit(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var out m.SourceTypeResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	if out.IconUrl != *req.IconUrl {
+		t.Errorf(`want icon url "%s", got "%s"`, *req.IconUrl, out.IconUrl)
+	}
+
+	if out.DisplayName != *req.DisplayName {
+		t.Errorf(`want display name "%s", got "%s"`, *req.DisplayName, out.DisplayName)
+	}
+
+	if sourceTypeListCache.Len() != 0 {
+		t.Errorf("want the source type list cache to be invalidated, got %d entries", sourceTypeListCache.Len())
+	}
+}
+
+func TestSourceTypeEditInvalidIconUrl(t *testing.T) {
+	req := m.SourceTypeEditRequest{
+		IconUrl: util.StringRef("http://example.com/icons/amazon.svg"),
+	}
+
+	body, _ := json.Marshal(req)
+
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		"/api/sources/v3.1/source_types/1",
+		bytes.NewReader(body),
+		map[string]interface{}{},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+	badRequestSourceTypeEdit := ErrorHandlingContext(SourceTypeEdit)
+	err := badRequestSourceTypeEdit(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+func TestSourceTypeEditNotFound(t *testing.T) {
+	req := m.SourceTypeEditRequest{
+		DisplayName: util.StringRef("Amazon Web Services"),
+	}
+
+	body, _ := json.Marshal(req)
+
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		"/api/sources/v3.1/source_types/3098539345",
+		bytes.NewReader(body),
+		map[string]interface{}{},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("3098539345")
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+	notFoundSourceTypeEdit := ErrorHandlingContext(SourceTypeEdit)
+	err := notFoundSourceTypeEdit(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+// TestSourceTypeGetIncludes tests that the single source type response includes the application types which support
+// it.
+func TestSourceTypeGetIncludes(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/source_types/2",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		})
+
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+
+	err := SourceTypeGet(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out m.SourceTypeWithIncludesResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the response: %s`, err)
+	}
+
+	if out.SourceTypeResponse == nil || out.Name != "google" {
+		t.Errorf(`want the "google" source type, got "%+v"`, out.SourceTypeResponse)
+	}
+
+	if len(out.Includes) != 1 || out.Includes[0].Id != "2" {
+		t.Errorf(`want application type "2" in the includes, got "%+v"`, out.Includes)
+	}
+}
+
+// TestSourceTypeListCompatibleWithApplicationType tests that the source types can be filtered by an application type,
+// and that an empty list is returned when none of them are compatible.
+func TestSourceTypeListCompatibleWithApplicationType(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	testCases := []struct {
+		appTypeId string
+		wantCount int
+	}{
+		{appTypeId: "1", wantCount: 1},
+		{appTypeId: "12345", wantCount: 0},
+	}
+
+	for _, tc := range testCases {
+		c, rec := request.CreateTestContext(
+			http.MethodGet,
+			"/api/sources/v3.1/source_types?application_type_id="+tc.appTypeId,
+			nil,
+			map[string]interface{}{
+				"limit":  100,
+				"offset": 0,
+				"filters": []util.Filter{
+					{Name: "application_type_id", Value: []string{tc.appTypeId}},
+				},
+			},
+		)
+
+		err := SourceTypeList(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+		}
+
+		var out util.Collection
+		err = json.Unmarshal(rec.Body.Bytes(), &out)
+		if err != nil {
+			t.Fatalf(`could not unmarshal the response: %s`, err)
+		}
+
+		if len(out.Data) != tc.wantCount {
+			t.Errorf(`[application type "%s"] want "%d" source types, got "%d"`, tc.appTypeId, tc.wantCount, len(out.Data))
+		}
+	}
+}
+
+// TestSourceTypeCapabilities tests that the source type's capabilities are returned as a flat object.
+func TestSourceTypeCapabilities(t *testing.T) {
+	invalidateSourceTypeCapabilitiesCache()
+
+	sourceTypeId := fixtures.TestSourceTypeData[0].Id
+	id := strconv.FormatInt(sourceTypeId, 10)
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/source_types/"+id+"/capabilities",
+		nil,
+		map[string]interface{}{},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+
+	err := SourceTypeCapabilities(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out m.SourceTypeCapabilities
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf(`could not unmarshal the response: %s`, err)
+	}
+
+	sourceTypeFlagDao, _ := getSourceTypeFlagDao(c)
+	flags, _ := sourceTypeFlagDao.GetAll(sourceTypeId)
+	want := m.ToSourceTypeCapabilities(flags)
+
+	if !reflect.DeepEqual(want, out) {
+		t.Errorf(`want capabilities "%v", got "%v"`, want, out)
+	}
+}
+
+// TestSourceTypeCapabilitiesNotFound tests that a not found is returned for a nonexistent source type.
+func TestSourceTypeCapabilitiesNotFound(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/source_types/12345/capabilities",
+		nil,
+		map[string]interface{}{},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("12345")
+
+	notFoundSourceTypeCapabilities := ErrorHandlingContext(SourceTypeCapabilities)
+	err := notFoundSourceTypeCapabilities(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+// TestSourceTypeCapabilitiesSet tests that the capabilities get set, and that the cached capabilities get
+// invalidated.
+func TestSourceTypeCapabilitiesSet(t *testing.T) {
+	invalidateSourceTypeCapabilitiesCache()
+
+	sourceTypeId := fixtures.TestSourceTypeData[0].Id
+	id := strconv.FormatInt(sourceTypeId, 10)
+
+	getCapabilities := func() m.SourceTypeCapabilities {
+		c, rec := request.CreateTestContext(http.MethodGet, "/api/sources/v3.1/source_types/"+id+"/capabilities", nil, map[string]interface{}{})
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+
+		err := SourceTypeCapabilities(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out m.SourceTypeCapabilities
+		err = json.Unmarshal(rec.Body.Bytes(), &out)
+		if err != nil {
+			t.Fatalf(`could not unmarshal the response: %s`, err)
+		}
+
+		return out
+	}
+
+	// Cache the capabilities before setting them.
+	getCapabilities()
+
+	body, _ := json.Marshal(m.SourceTypeCapabilities{"topology": true, "cost_management": false})
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/source_types/"+id+"/capabilities",
+		bytes.NewReader(body),
+		map[string]interface{}{},
+	)
+
+	c.Request().Header.Add("Content-Type", "application/json")
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+
+	// Make sure we are using the "NoUnknownFieldsBinder".
+	backupBinder := c.Echo().Binder
+	c.Echo().Binder = &NoUnknownFieldsBinder{}
+
+	err := SourceTypeCapabilitiesSet(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	out := getCapabilities()
+	if !out["topology"] || out["cost_management"] {
+		t.Errorf(`want "topology" enabled and "cost_management" disabled, got "%v"`, out)
+	}
+
+	// Restore the binder to not affect any other tests.
+	c.Echo().Binder = backupBinder
+}
+
+// TestSourceTypeCapabilitiesSetBadRequest tests that invalid capability names are rejected.
+func TestSourceTypeCapabilitiesSetBadRequest(t *testing.T) {
+	bodies := []string{`{}`, `{"Cost Management": true}`, `{"cost_management": "yes"}`}
+
+	for _, body := range bodies {
+		c, rec := request.CreateTestContext(
+			http.MethodPost,
+			"/api/sources/v3.1/source_types/1/capabilities",
+			bytes.NewReader([]byte(body)),
+			map[string]interface{}{},
+		)
+
+		c.Request().Header.Add("Content-Type", "application/json")
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+
+		// Make sure we are using the "NoUnknownFieldsBinder".
+		backupBinder := c.Echo().Binder
+		c.Echo().Binder = &NoUnknownFieldsBinder{}
+
+		badRequestSourceTypeCapabilitiesSet := ErrorHandlingContext(SourceTypeCapabilitiesSet)
+		err := badRequestSourceTypeCapabilitiesSet(c)
+		if err != nil {
+			t.Error(err)
+		}
+
+		templates.BadRequestTest(t, rec)
+
+		// Restore the binder to not affect any other tests.
+		c.Echo().Binder = backupBinder
+	}
+}
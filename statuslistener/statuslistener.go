@@ -1,6 +1,8 @@
 package statuslistener
 
 import (
+	"context"
+	"errors"
 	"sort"
 	"strings"
 	"time"
@@ -20,6 +22,8 @@ const (
 	sourcesStatusTopic      = "platform.sources.status"
 	groupID                 = "sources-api-status-worker"
 	eventAvailabilityStatus = "availability_status"
+	// offsetFlushInterval is how often the offsets of the processed messages get stored and committed.
+	offsetFlushInterval = 5 * time.Second
 )
 
 var config = c.Get()
@@ -29,6 +33,16 @@ type AvailabilityStatusListener struct {
 }
 
 func Run(shutdown chan struct{}) {
+	// The status updates would be rejected in read-only mode, so the messages are left in the topic instead. They get
+	// consumed from the stored offsets once the listener runs with the read-only mode disabled.
+	if config.ReadOnly {
+		l.Log.Warnf("The read-only mode is enabled, the Availability Status Listener on topic [%v] is paused", config.KafkaTopic(sourcesStatusTopic))
+
+		<-shutdown
+		shutdown <- struct{}{}
+		return
+	}
+
 	l.Log.Infof("Starting Availability Status Listener on topic [%v]", config.KafkaTopic(sourcesStatusTopic))
 
 	avs := AvailabilityStatusListener{EventStreamProducer: NewEventStreamProducer()}
@@ -53,10 +67,16 @@ func (avs *AvailabilityStatusListener) subscribeToAvailabilityStatus(shutdown ch
 	}
 
 	kf := &kafka.Manager{Config: kafkaConfig}
+	store := &kafkaOffsetStore{topic: kafkaConfig.ConsumerConfig.Topic}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
 
 	// run async for graceful shutdown handling
 	go func() {
-		if err := kf.Consume(avs.ConsumeStatusMessage); err != nil {
+		defer close(done)
+
+		if err := kf.ConsumeWithOffsetStore(ctx, store, offsetFlushInterval, avs.ConsumeStatusMessage); err != nil {
 			l.Log.Errorf("Consumer kafka message error: %s", err.Error())
 		}
 	}()
@@ -64,9 +84,10 @@ func (avs *AvailabilityStatusListener) subscribeToAvailabilityStatus(shutdown ch
 	<-shutdown
 	l.Log.Infof("Closing Kafka Consumer...")
 
-	if err := kf.Consumer().Close(); err != nil {
-		l.Log.Warn(err)
-	}
+	// Wait for the consumer so that the offsets of the last read messages get stored.
+	cancel()
+	<-done
+
 	shutdown <- struct{}{}
 }
 
@@ -96,6 +117,29 @@ func (avs *AvailabilityStatusListener) ConsumeStatusMessage(message kafka.Messag
 	avs.processEvent(statusMessage, headers)
 }
 
+// kafkaOffsetStore keeps the offsets the listener has processed from the topic in the database, so that they can be
+// reset in order to replay the messages.
+type kafkaOffsetStore struct {
+	topic string
+}
+
+func (k *kafkaOffsetStore) GetOffset(partition int) (int64, bool, error) {
+	offset, err := dao.GetKafkaOffsetDao(context.Background()).GetOffset(groupID, k.topic, partition)
+	if errors.Is(err, util.ErrNotFoundEmpty) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	return offset, true, nil
+}
+
+func (k *kafkaOffsetStore) SetOffset(partition int, offset int64) error {
+	return dao.GetKafkaOffsetDao(context.Background()).SetOffset(groupID, k.topic, partition, offset)
+}
+
 func (avs *AvailabilityStatusListener) processEvent(statusMessage types.StatusMessage, headers []kafka.Header) {
 	resource := &util.Resource{}
 	resource, err := util.ParseStatusMessageToResource(resource, statusMessage)
@@ -122,7 +166,7 @@ func (avs *AvailabilityStatusListener) processEvent(statusMessage types.StatusMe
 		return
 	}
 
-	tenantDao := dao.GetTenantDao()
+	tenantDao := dao.GetTenantDao(context.Background())
 	tenant, err := tenantDao.TenantByIdentity(id)
 	if err != nil {
 		l.Log.Error(err)
@@ -145,7 +189,7 @@ func (avs *AvailabilityStatusListener) processEvent(statusMessage types.StatusMe
 
 	if previousStatus != statusMessage.Status {
 		if statusMessage.ResourceType == "Application" {
-			appDao := dao.GetApplicationDao(&tenant.Id)
+			appDao := dao.GetApplicationDao(context.Background(), &tenant.Id)
 			app, err := appDao.GetById(&resource.ResourceID)
 			if err != nil {
 				l.Log.Errorf("unable to fetch application: %s", err)
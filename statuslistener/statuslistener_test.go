@@ -1,6 +1,7 @@
 package statuslistener
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -171,7 +172,7 @@ func FetchDataFor(resourceType string, resourceID string, forBulkMessage bool) (
 			ApplicationAuthentications: []m.ApplicationAuthentication{},
 		}
 
-		authDao := dao.GetAuthenticationDao(&application.TenantID)
+		authDao := dao.GetAuthenticationDao(context.Background(), &application.TenantID)
 		authenticationsByResource, err := authDao.AuthenticationsByResource(authentication)
 		if err != nil {
 			panic("error to fetch authentications: " + err.Error())
@@ -208,7 +209,7 @@ func FetchDataFor(resourceType string, resourceID string, forBulkMessage bool) (
 			ResourceType:               "Endpoint",
 			ApplicationAuthentications: []m.ApplicationAuthentication{},
 		}
-		authDao := dao.GetAuthenticationDao(&endpoint.TenantID)
+		authDao := dao.GetAuthenticationDao(context.Background(), &endpoint.TenantID)
 		authenticationsByResource, err := authDao.AuthenticationsByResource(authentication)
 		if err != nil {
 			return err, nil
@@ -482,3 +483,43 @@ func TestConsumeStatusMessage(t *testing.T) {
 	}
 	config.SecretStore = originalSecretStore
 }
+
+// TestKafkaOffsetStore tests that the offset store reads and writes the listener's offsets, and that a missing offset
+// isn't reported as an error.
+func TestKafkaOffsetStore(t *testing.T) {
+	originalDao := dao.GetKafkaOffsetDao
+	defer func() { dao.GetKafkaOffsetDao = originalDao }()
+
+	mockDao := &dao.MockKafkaOffsetDao{Offsets: []m.KafkaOffset{{ConsumerGroup: groupID, Topic: "topic", Partition: 0, Offset: 10}}}
+	dao.GetKafkaOffsetDao = func(context.Context) dao.KafkaOffsetDao { return mockDao }
+
+	store := &kafkaOffsetStore{topic: "topic"}
+
+	offset, found, err := store.GetOffset(0)
+	if err != nil {
+		t.Errorf(`want no errors, got "%s"`, err)
+	}
+
+	if !found || offset != 10 {
+		t.Errorf(`want the offset "10" to be found, got "%d" and found "%t"`, offset, found)
+	}
+
+	_, found, err = store.GetOffset(1)
+	if err != nil {
+		t.Errorf(`want no errors, got "%s"`, err)
+	}
+
+	if found {
+		t.Errorf(`want no offset for the partition "1", got one`)
+	}
+
+	err = store.SetOffset(1, 25)
+	if err != nil {
+		t.Errorf(`want no errors, got "%s"`, err)
+	}
+
+	offset, found, _ = store.GetOffset(1)
+	if !found || offset != 25 {
+		t.Errorf(`want the offset "25" to be stored, got "%d" and found "%t"`, offset, found)
+	}
+}
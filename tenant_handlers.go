@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/middleware"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// function that defines how we get the dao - default implementation below.
+var getTenantStatsDao func(c echo.Context) (dao.TenantStatsDao, error)
+
+func getTenantStatsDaoWithoutTenant(c echo.Context) (dao.TenantStatsDao, error) {
+	tenantStatsDao := dao.GetTenantStatsDao(c.Request().Context())
+
+	return tenantStatsDao, nil
+}
+
+// TenantStats returns the number of resources of each type the given tenant has. The requests authorized by an
+// identity can only fetch the statistics of their own tenant, while the ones authorized by a valid PSK can fetch any.
+func TenantStats(c echo.Context) error {
+	tenantId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	if !middleware.AuthorizedByPsk(c) {
+		requestTenantId, err := getTenantFromEchoContext(c)
+		if err != nil {
+			return err
+		}
+
+		if requestTenantId != tenantId {
+			return util.NewErrNotFound("tenant")
+		}
+	}
+
+	tenantStatsDao, err := getTenantStatsDao(c)
+	if err != nil {
+		return err
+	}
+
+	stats, err := tenantStatsDao.GetStats(tenantId)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, stats)
+}
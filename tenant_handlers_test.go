@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	"github.com/RedHatInsights/sources-api-go/middleware"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/redhatinsights/platform-go-middlewares/identity"
+)
+
+func TestTenantStats(t *testing.T) {
+	tenantId := fixtures.TestTenantData[0].Id
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/tenants/1/stats",
+		nil,
+		map[string]interface{}{
+			"tenantID": tenantId,
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := TenantStats(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var stats m.TenantStats
+	err = json.Unmarshal(rec.Body.Bytes(), &stats)
+	if err != nil {
+		t.Error("Failed unmarshaling output")
+	}
+
+	if stats.TenantId != tenantId {
+		t.Errorf(`want the stats of tenant "%d", got "%d"`, tenantId, stats.TenantId)
+	}
+
+	if stats.Sources == 0 {
+		t.Errorf(`want the tenant's sources to be counted, got "%d"`, stats.Sources)
+	}
+}
+
+// TestTenantStatsOtherTenant tests that the requests authorized by an identity cannot fetch the statistics of other
+// tenants.
+func TestTenantStatsOtherTenant(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/tenants/2/stats",
+		nil,
+		map[string]interface{}{
+			"tenantID": fixtures.TestTenantData[0].Id,
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("2")
+
+	notFoundTenantStats := ErrorHandlingContext(TenantStats)
+	err := notFoundTenantStats(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+// TestTenantStatsPskNotFound tests that the requests authorized by a PSK can ask for any tenant, and that a "not
+// found" error is returned for the tenants which don't exist.
+func TestTenantStatsPskNotFound(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/tenants/9843762095/stats",
+		nil,
+		map[string]interface{}{
+			"x-rh-sources-psk": "1234",
+			h.PSK_AUTHORIZED:   true,
+			"tenantID":         fixtures.TestTenantData[0].Id,
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("9843762095")
+
+	notFoundTenantStats := ErrorHandlingContext(TenantStats)
+	err := notFoundTenantStats(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+// TestTenantStatsOrgAdminBogusPsk tests that an organization administrator who also sends a PSK which isn't valid
+// still can't fetch the statistics of other tenants.
+func TestTenantStatsOrgAdminBogusPsk(t *testing.T) {
+	otherTenantId := fixtures.TestTenantData[1].Id
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		fmt.Sprintf("/api/sources/v3.1/tenants/%d/stats", otherTenantId),
+		nil,
+		map[string]interface{}{
+			h.PSK:             "bogus-psk",
+			h.PARSED_IDENTITY: &identity.XRHID{Identity: identity.Identity{User: identity.User{OrgAdmin: true}}},
+			"tenantID":        fixtures.TestTenantData[0].Id,
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.FormatInt(otherTenantId, 10))
+
+	tenantStats := ErrorHandlingContext(middleware.PermissionCheckPskOrOrgAdmin(TenantStats))
+	err := tenantStats(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+func TestTenantStatsBadRequest(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/tenants/xxx/stats",
+		nil,
+		map[string]interface{}{
+			"tenantID": fixtures.TestTenantData[0].Id,
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("xxx")
+
+	badRequestTenantStats := ErrorHandlingContext(TenantStats)
+	err := badRequestTenantStats(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
@@ -14,9 +14,53 @@ type Collection struct {
 }
 
 type Metadata struct {
-	Count  int `json:"count"`
-	Limit  int `json:"limit"`
-	Offset int `json:"offset"`
+	Count  int      `json:"count"`
+	Limit  int      `json:"limit"`
+	Offset int      `json:"offset"`
+	Page   PageMeta `json:"page"`
+	// Warnings tell the clients about the issues with their requests which didn't prevent them from succeeding.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// PageMeta holds the page the collection corresponds to, so that the clients don't need to compute it from the
+// offset, the limit and the count themselves. The pages are numbered starting from one.
+type PageMeta struct {
+	CurrentPage int `json:"current_page"`
+	TotalPages  int `json:"total_pages"`
+	PerPage     int `json:"per_page"`
+	TotalCount  int `json:"total_count"`
+}
+
+// UnknownCount is the count of the collections whose total could not be obtained.
+const UnknownCount = -1
+
+// NewPageMeta computes the page metadata for the given count, limit and offset. The current page is the one the
+// offset falls in, and the last page may be partial. A non-positive limit is treated as a single page holding the
+// whole collection, and an unknown count leaves the total pages out.
+func NewPageMeta(count, limit, offset int) PageMeta {
+	pageMeta := PageMeta{
+		CurrentPage: 1,
+		PerPage:     limit,
+		TotalCount:  count,
+	}
+
+	if limit <= 0 {
+		if count > 0 {
+			pageMeta.TotalPages = 1
+		}
+
+		return pageMeta
+	}
+
+	if offset > 0 {
+		pageMeta.CurrentPage = offset/limit + 1
+	}
+
+	if count >= 0 {
+		pageMeta.TotalPages = (count + limit - 1) / limit
+	}
+
+	return pageMeta
 }
 
 type Links struct {
@@ -51,6 +95,7 @@ func CollectionResponse(collection []interface{}, req *http.Request, count, limi
 			Count:  count,
 			Limit:  limit,
 			Offset: offset,
+			Page:   NewPageMeta(count, limit, offset),
 		},
 		Links: links,
 	}
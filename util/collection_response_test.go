@@ -0,0 +1,41 @@
+package util
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+// TestNewPageMeta tests that the page metadata is computed correctly for empty collections, partial last pages and
+// offsets which don't fall on a page boundary.
+func TestNewPageMeta(t *testing.T) {
+	testCases := []struct {
+		count, limit, offset int
+		want                 PageMeta
+	}{
+		{count: 0, limit: 10, offset: 0, want: PageMeta{CurrentPage: 1, TotalPages: 0, PerPage: 10, TotalCount: 0}},
+		{count: 10, limit: 10, offset: 0, want: PageMeta{CurrentPage: 1, TotalPages: 1, PerPage: 10, TotalCount: 10}},
+		{count: 11, limit: 10, offset: 10, want: PageMeta{CurrentPage: 2, TotalPages: 2, PerPage: 10, TotalCount: 11}},
+		{count: 25, limit: 10, offset: 15, want: PageMeta{CurrentPage: 2, TotalPages: 3, PerPage: 10, TotalCount: 25}},
+		{count: 25, limit: 0, offset: 0, want: PageMeta{CurrentPage: 1, TotalPages: 1, PerPage: 0, TotalCount: 25}},
+		{count: UnknownCount, limit: 1, offset: 3, want: PageMeta{CurrentPage: 4, TotalPages: 0, PerPage: 1, TotalCount: UnknownCount}},
+	}
+
+	for _, tc := range testCases {
+		got := NewPageMeta(tc.count, tc.limit, tc.offset)
+		if got != tc.want {
+			t.Errorf(`[count: %d][limit: %d][offset: %d] want "%+v", got "%+v"`, tc.count, tc.limit, tc.offset, tc.want, got)
+		}
+	}
+}
+
+// TestCollectionResponsePageMeta tests that the collection responses carry the page metadata.
+func TestCollectionResponsePageMeta(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/sources/v3.1/sources?limit=2&offset=2", nil)
+
+	collection := CollectionResponse([]interface{}{"a", "b"}, req, 5, 2, 2)
+
+	want := PageMeta{CurrentPage: 2, TotalPages: 3, PerPage: 2, TotalCount: 5}
+	if collection.Meta.Page != want {
+		t.Errorf(`want "%+v", got "%+v"`, want, collection.Meta.Page)
+	}
+}
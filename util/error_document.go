@@ -3,12 +3,17 @@ package util
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	l "github.com/RedHatInsights/sources-api-go/logger"
 )
 
 var ErrNotFoundEmpty = NewErrNotFound("")
 var ErrBadRequestEmpty = NewErrBadRequest("")
+var ErrConflictEmpty = NewErrConflict("")
+var ErrGoneEmpty = NewErrGone("")
+var ErrUnprocessableEntityEmpty = NewErrUnprocessableEntity("")
+var ErrServiceUnavailableEmpty = NewErrServiceUnavailable("", 0)
 
 type Error struct {
 	Detail string `json:"detail"`
@@ -83,3 +88,88 @@ func NewErrBadRequest(t interface{}) error {
 
 	return ErrBadRequest{Message: errorMessage}
 }
+
+type ErrConflict struct {
+	Message string
+}
+
+func (e ErrConflict) Error() string {
+	return fmt.Sprintf("conflict: %s", e.Message)
+}
+
+func (e ErrConflict) Is(err error) bool {
+	return reflect.TypeOf(err) == reflect.TypeOf(e)
+}
+
+func NewErrConflict(message string) error {
+	if l.Log != nil {
+		l.Log.Error(message)
+	}
+
+	return ErrConflict{Message: message}
+}
+
+// ErrGone signals that the requested resource existed, but that it was deleted.
+type ErrGone struct {
+	Type string
+}
+
+func (e ErrGone) Error() string {
+	return fmt.Sprintf("%s no longer exists", e.Type)
+}
+
+func (e ErrGone) Is(err error) bool {
+	return reflect.TypeOf(err) == reflect.TypeOf(e)
+}
+
+func NewErrGone(t string) error {
+	if l.Log != nil {
+		l.Log.Error(t)
+	}
+
+	return ErrGone{Type: t}
+}
+
+// ErrUnprocessableEntity signals that the request is well formed, but that some of its values are not acceptable.
+type ErrUnprocessableEntity struct {
+	Message string
+}
+
+func (e ErrUnprocessableEntity) Error() string {
+	return fmt.Sprintf("unprocessable entity: %s", e.Message)
+}
+
+func (e ErrUnprocessableEntity) Is(err error) bool {
+	return reflect.TypeOf(err) == reflect.TypeOf(e)
+}
+
+func NewErrUnprocessableEntity(message string) error {
+	if l.Log != nil {
+		l.Log.Error(message)
+	}
+
+	return ErrUnprocessableEntity{Message: message}
+}
+
+// ErrServiceUnavailable signals that the request cannot be served for now, and that it may be retried after the given
+// amount of time.
+type ErrServiceUnavailable struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e ErrServiceUnavailable) Error() string {
+	return fmt.Sprintf("service unavailable: %s", e.Message)
+}
+
+func (e ErrServiceUnavailable) Is(err error) bool {
+	return reflect.TypeOf(err) == reflect.TypeOf(e)
+}
+
+func NewErrServiceUnavailable(message string, retryAfter time.Duration) error {
+	if l.Log != nil {
+		l.Log.Error(message)
+	}
+
+	return ErrServiceUnavailable{Message: message, RetryAfter: retryAfter}
+}
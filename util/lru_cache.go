@@ -0,0 +1,115 @@
+package util
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LruCache is a least recently used cache whose entries expire after a fixed amount of time. It holds up to a fixed
+// number of entries, so that it cannot grow unbounded no matter how many different keys get cached.
+type LruCache struct {
+	mutex   sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	// order holds the keys from the most recently used to the least recently used.
+	order *list.List
+}
+
+type lruCacheEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+// NewLruCache returns an empty cache which holds up to "size" entries, each of them for the given duration.
+func NewLruCache(size int, ttl time.Duration) *LruCache {
+	return &LruCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the cached value for the given key, if it's present and it hasn't expired.
+func (lc *LruCache) Get(key string) (interface{}, bool) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	element, ok := lc.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		lc.order.Remove(element)
+		delete(lc.entries, key)
+
+		return nil, false
+	}
+
+	lc.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+// Set caches the value under the given key, and evicts the least recently used entry if the cache is full.
+func (lc *LruCache) Set(key string, value interface{}) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	expiresAt := time.Now().Add(lc.ttl)
+	if element, ok := lc.entries[key]; ok {
+		entry := element.Value.(*lruCacheEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		lc.order.MoveToFront(element)
+
+		return
+	}
+
+	lc.entries[key] = lc.order.PushFront(&lruCacheEntry{key: key, value: value, expiresAt: expiresAt})
+
+	if lc.order.Len() > lc.size {
+		oldest := lc.order.Back()
+		lc.order.Remove(oldest)
+		delete(lc.entries, oldest.Value.(*lruCacheEntry).key)
+	}
+}
+
+// Values returns the values which haven't expired, without changing how recently they were used.
+func (lc *LruCache) Values() []interface{} {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	now := time.Now()
+	values := make([]interface{}, 0, lc.order.Len())
+	for element := lc.order.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*lruCacheEntry)
+		if now.Before(entry.expiresAt) {
+			values = append(values, entry.value)
+		}
+	}
+
+	return values
+}
+
+// Len returns the number of cached entries, including the expired ones which haven't been removed yet.
+func (lc *LruCache) Len() int {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	return lc.order.Len()
+}
+
+// Flush empties the cache.
+func (lc *LruCache) Flush() {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.entries = make(map[string]*list.Element)
+	lc.order.Init()
+}
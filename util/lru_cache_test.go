@@ -0,0 +1,71 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLruCacheEvictsLeastRecentlyUsed tests that when the cache is full, the least recently used entry is the one
+// which gets evicted.
+func TestLruCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLruCache(2, time.Minute)
+
+	cache.Set("org_id:a", 1)
+	cache.Set("org_id:b", 2)
+
+	// Use "a" so that "b" becomes the least recently used entry.
+	if _, ok := cache.Get("org_id:a"); !ok {
+		t.Fatalf(`want "org_id:a" to be cached, got a miss`)
+	}
+
+	cache.Set("org_id:c", 3)
+
+	if _, ok := cache.Get("org_id:b"); ok {
+		t.Errorf(`want "org_id:b" to be evicted, got a hit`)
+	}
+
+	for key, want := range map[string]int{"org_id:a": 1, "org_id:c": 3} {
+		got, ok := cache.Get(key)
+		if !ok || got != want {
+			t.Errorf(`want "%d" for "%s", got "%v" (cached: %t)`, want, key, got, ok)
+		}
+	}
+}
+
+// TestLruCacheExpiry tests that the expired entries are not returned, and that the cache can be flushed.
+func TestLruCacheExpiry(t *testing.T) {
+	cache := NewLruCache(2, time.Minute)
+
+	cache.Set("org_id:a", 1)
+	cache.entries["org_id:a"].Value.(*lruCacheEntry).expiresAt = time.Now().Add(-time.Second)
+
+	if _, ok := cache.Get("org_id:a"); ok {
+		t.Errorf(`want an expired entry to be a miss, got a hit`)
+	}
+
+	if cache.order.Len() != 0 {
+		t.Errorf(`want the expired entry to be removed, got "%d" entries`, cache.order.Len())
+	}
+
+	cache.Set("org_id:b", 2)
+	cache.Flush()
+
+	if _, ok := cache.Get("org_id:b"); ok {
+		t.Errorf(`want a miss after flushing the cache, got a hit`)
+	}
+}
+
+// TestLruCacheValues tests that only the values which haven't expired are returned.
+func TestLruCacheValues(t *testing.T) {
+	cache := NewLruCache(3, time.Minute)
+
+	cache.Set("org_id:a", 1)
+	cache.Set("org_id:b", 2)
+	cache.Set("org_id:c", 3)
+	cache.entries["org_id:b"].Value.(*lruCacheEntry).expiresAt = time.Now().Add(-time.Second)
+
+	values := cache.Values()
+	if len(values) != 2 || values[0] != 3 || values[1] != 1 {
+		t.Errorf(`want the values "[3 1]", got "%v"`, values)
+	}
+}